@@ -57,6 +57,7 @@ Event symbols:
   ✓  completed       - Issue closed or step completed
   ✗  failed          - Step or issue failed
   ⊘  deleted         - Issue removed
+  ⚑  gate            - Gate resolved, escalated or timed out (daemon)
 
 Examples:
   bd activity                     # Show last 100 events
@@ -75,7 +76,7 @@ func init() {
 	activityCmd.Flags().BoolVarP(&activityFollow, "follow", "f", false, "Stream events in real-time")
 	activityCmd.Flags().StringVar(&activityMol, "mol", "", "Filter by molecule/issue ID prefix")
 	activityCmd.Flags().StringVar(&activitySince, "since", "", "Show events since duration (e.g., 5m, 1h, 30s)")
-	activityCmd.Flags().StringVar(&activityType, "type", "", "Filter by event type (create, update, delete, comment)")
+	activityCmd.Flags().StringVar(&activityType, "type", "", "Filter by event type (create, update, delete, comment, status, gate)")
 	activityCmd.Flags().IntVar(&activityLimit, "limit", 100, "Maximum number of events to show")
 	activityCmd.Flags().DurationVar(&activityInterval, "interval", 500*time.Millisecond, "Polling interval for --follow mode")
 	activityCmd.Flags().BoolVar(&activityTown, "town", false, "Aggregated feed from all rigs (uses routes.jsonl)")
@@ -347,6 +348,17 @@ func getEventDisplay(e rpc.MutationEvent) (symbol, message string) {
 			return "↺", fmt.Sprintf("%s reopened%s", e.IssueID, context)
 		}
 		return "→", fmt.Sprintf("%s → %s%s", e.IssueID, e.NewStatus, context)
+	case rpc.MutationGate:
+		switch e.NewStatus {
+		case "resolved":
+			return "⚑", fmt.Sprintf("%s gate resolved%s", e.IssueID, context)
+		case "timed_out":
+			return "⚑", fmt.Sprintf("%s gate timed out%s", e.IssueID, context)
+		default:
+			return "⚑", fmt.Sprintf("%s gate %s%s", e.IssueID, e.NewStatus, context)
+		}
+	case rpc.MutationSLA:
+		return "⏰", fmt.Sprintf("%s SLA %s breached%s", e.IssueID, e.NewStatus, context)
 	default:
 		return "•", fmt.Sprintf("%s %s%s", e.IssueID, e.Type, context)
 	}
@@ -399,12 +411,18 @@ func printEvent(e rpc.MutationEvent) {
 		coloredSymbol = ui.RenderPass(symbol)
 	case rpc.MutationUpdate:
 		coloredSymbol = ui.RenderWarn(symbol)
-	case rpc.MutationDelete, rpc.MutationBurned:
+	case rpc.MutationDelete, rpc.MutationBurned, rpc.MutationSLA:
 		coloredSymbol = ui.RenderFail(symbol)
 	case rpc.MutationComment:
 		coloredSymbol = ui.RenderAccent(symbol)
 	case rpc.MutationSquashed:
 		coloredSymbol = ui.RenderAccent(symbol)
+	case rpc.MutationGate:
+		if e.NewStatus == "resolved" {
+			coloredSymbol = ui.RenderPass(symbol)
+		} else {
+			coloredSymbol = ui.RenderWarn(symbol)
+		}
 	case rpc.MutationStatus:
 		// Color based on new status
 		if e.NewStatus == "closed" {
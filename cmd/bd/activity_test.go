@@ -237,6 +237,24 @@ func TestGetEventDisplay(t *testing.T) {
 			expectedSymbol: "\u21BA", // ↺
 			checkMessage:   func(m string) bool { return m == "bd-reopen reopened" },
 		},
+		{
+			name:           "gate resolved",
+			event:          rpc.MutationEvent{Type: rpc.MutationGate, IssueID: "bd-gate", NewStatus: "resolved"},
+			expectedSymbol: "\u2691", // ⚑
+			checkMessage:   func(m string) bool { return m == "bd-gate gate resolved" },
+		},
+		{
+			name:           "gate timed out",
+			event:          rpc.MutationEvent{Type: rpc.MutationGate, IssueID: "bd-gate", NewStatus: "timed_out"},
+			expectedSymbol: "\u2691", // ⚑
+			checkMessage:   func(m string) bool { return m == "bd-gate gate timed out" },
+		},
+		{
+			name:           "sla breached",
+			event:          rpc.MutationEvent{Type: rpc.MutationSLA, IssueID: "bd-p0", NewStatus: "respond"},
+			expectedSymbol: "\u23F0", // ⏰
+			checkMessage:   func(m string) bool { return m == "bd-p0 SLA respond breached" },
+		},
 		{
 			name:           "unknown event type",
 			event:          rpc.MutationEvent{Type: "custom", IssueID: "bd-custom"},
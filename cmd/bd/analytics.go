@@ -0,0 +1,423 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/analytics"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var analyticsCmd = &cobra.Command{
+	Use:     "analytics",
+	GroupID: "views",
+	Short:   "Flow metrics (cycle time, lead time, throughput) from issue history",
+	Long: `Show flow metrics computed from the event history.
+
+Metrics are rebuilt from status change events rather than from the current
+state of each issue, so reopened issues and time spent in each status are
+counted as they happened:
+
+  cycle time   first in_progress to closed (issues never started are left out)
+  lead time    created to closed
+  throughput   issues closed per week
+  reopen rate  share of closes in the window that were later reopened
+
+An issue closed, reopened and closed again counts as two completions.
+
+Subcommands:
+  bd analytics              Summary of all metrics per segment
+  bd analytics throughput   Issues closed per week
+  bd analytics cfd          Cumulative flow: issues per status at the end of each day
+
+Use --by to break metrics down by type, label, assignee or epic. Labels,
+assignee and epic are taken from the current state of each issue; an issue
+with several labels counts toward each of them.
+
+Examples:
+  bd analytics --since -30d
+  bd analytics --by type --format csv > flow.csv
+  bd analytics throughput --by assignee --since 2025-01-01
+  bd analytics cfd --by epic --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		data := loadAnalyticsData(cmd)
+		defer data.close()
+		summaries := analytics.Summarize(data.histories, data.opts)
+		if jsonOutput {
+			outputJSON(struct {
+				analyticsWindow
+				Segments []*analytics.Summary `json:"segments"`
+			}{data.window(), summaries})
+			return
+		}
+		if data.format == "csv" {
+			writeAnalyticsCSV(os.Stdout, analyticsSummaryCSV(summaries))
+			return
+		}
+		printAnalyticsSummary(os.Stdout, data, summaries)
+	},
+}
+
+var analyticsThroughputCmd = &cobra.Command{
+	Use:   "throughput",
+	Short: "Issues closed per week",
+	Run: func(cmd *cobra.Command, args []string) {
+		data := loadAnalyticsData(cmd)
+		defer data.close()
+		weeks := analytics.Throughput(data.histories, data.opts)
+		if jsonOutput {
+			outputJSON(struct {
+				analyticsWindow
+				Weeks []*analytics.WeekCount `json:"weeks"`
+			}{data.window(), weeks})
+			return
+		}
+		rows := [][]string{{"week", "segment", "completed"}}
+		for _, w := range weeks {
+			rows = append(rows, []string{w.Week.Format("2006-01-02"), w.Segment, strconv.Itoa(w.Completed)})
+		}
+		if data.format == "csv" {
+			writeAnalyticsCSV(os.Stdout, rows)
+			return
+		}
+		printAnalyticsHeader(os.Stdout, "Throughput", data)
+		printAnalyticsTable(os.Stdout, analyticsPivot(rows))
+	},
+}
+
+var analyticsFlowCmd = &cobra.Command{
+	Use:   "cfd",
+	Short: "Cumulative flow: issues per status at the end of each day",
+	Run: func(cmd *cobra.Command, args []string) {
+		data := loadAnalyticsData(cmd)
+		defer data.close()
+		points := analytics.CumulativeFlow(data.histories, data.opts)
+		if jsonOutput {
+			outputJSON(struct {
+				analyticsWindow
+				Days []*analytics.FlowPoint `json:"days"`
+			}{data.window(), points})
+			return
+		}
+		statuses := analytics.FlowStatuses(points)
+		header := []string{"date", "segment"}
+		for _, s := range statuses {
+			header = append(header, string(s))
+		}
+		rows := [][]string{header}
+		for _, p := range points {
+			row := []string{p.Date.Format("2006-01-02"), p.Segment}
+			for _, s := range statuses {
+				row = append(row, strconv.Itoa(p.Counts[s]))
+			}
+			rows = append(rows, row)
+		}
+		if data.format == "csv" {
+			writeAnalyticsCSV(os.Stdout, rows)
+			return
+		}
+		printAnalyticsHeader(os.Stdout, "Cumulative flow", data)
+		if data.opts.By == analytics.ByNone {
+			rows = analyticsDropColumn(rows, 1)
+		}
+		printAnalyticsTable(os.Stdout, rows)
+	},
+}
+
+// analyticsWindow is the reporting window echoed in JSON output.
+type analyticsWindow struct {
+	Since time.Time         `json:"since"`
+	Until time.Time         `json:"until"`
+	By    analytics.Segment `json:"by,omitempty"`
+}
+
+// analyticsData is the reconstructed history behind every analytics view.
+type analyticsData struct {
+	histories map[string]*analytics.History
+	opts      analytics.Options
+	format    string
+	close     func() // Closes a store opened alongside the daemon
+}
+
+func (d *analyticsData) window() analyticsWindow {
+	return analyticsWindow{Since: d.opts.Since, Until: d.opts.Until, By: d.opts.By}
+}
+
+// loadAnalyticsData parses the shared flags and loads issue histories,
+// exiting on error. Callers defer data.close().
+func loadAnalyticsData(cmd *cobra.Command) *analyticsData {
+	ctx := rootCtx
+	sinceStr, _ := cmd.Flags().GetString("since")
+	untilStr, _ := cmd.Flags().GetString("until")
+	byStr, _ := cmd.Flags().GetString("by")
+	format, _ := cmd.Flags().GetString("format")
+
+	opts := analytics.Options{Until: time.Now()}
+	var err error
+	if opts.By, err = analytics.ParseSegment(byStr); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	if format != "table" && format != "csv" {
+		FatalErrorRespectJSON("unknown format %q (want table or csv)", format)
+	}
+	if untilStr != "" {
+		if opts.Until, err = parseTimeFlag(untilStr); err != nil {
+			FatalErrorRespectJSON("parsing --until: %v", err)
+		}
+	}
+	opts.Since = opts.Until.AddDate(0, 0, -90)
+	if sinceStr != "" {
+		if opts.Since, err = parseTimeFlag(sinceStr); err != nil {
+			FatalErrorRespectJSON("parsing --since: %v", err)
+		}
+	}
+	if !opts.Since.Before(opts.Until) {
+		FatalErrorRespectJSON("--since must be before --until")
+	}
+
+	// Analytics are read-only, so read directly even when the daemon is running
+	data := &analyticsData{format: format, close: func() {}}
+	if daemonClient != nil && store == nil {
+		store, err = sqlite.New(ctx, dbPath)
+		if err != nil {
+			FatalErrorRespectJSON("failed to open database: %v", err)
+		}
+		opened := store
+		data.close = func() { _ = opened.Close() }
+	}
+	if store == nil {
+		FatalErrorRespectJSON("no database connection")
+	}
+
+	histories, err := loadIssueHistories(ctx, store, opts.Until)
+	if err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	if err := loadAnalyticsSegments(ctx, store, histories, &opts); err != nil {
+		FatalErrorRespectJSON("%v", err)
+	}
+	data.histories, data.opts = histories, opts
+	return data
+}
+
+// loadIssueHistories rebuilds the status history of every issue up to until.
+// History before the reporting window is needed to know where each issue
+// stood when the window opened, so events are loaded from the beginning.
+func loadIssueHistories(ctx context.Context, s storage.Storage, until time.Time) (map[string]*analytics.History, error) {
+	all, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issues: %w", err)
+	}
+	issues := make([]*types.Issue, 0, len(all))
+	for _, issue := range all {
+		if issue.Status != types.StatusTombstone {
+			issues = append(issues, issue)
+		}
+	}
+	events, err := s.SearchEvents(ctx, types.EventFilter{Types: analytics.HistoryEventTypes, Until: &until})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	return analytics.BuildHistories(issues, events), nil
+}
+
+// loadAnalyticsSegments fills in the labels or epics opts.By needs.
+func loadAnalyticsSegments(ctx context.Context, s storage.Storage, histories map[string]*analytics.History, opts *analytics.Options) error {
+	ids := make([]string, 0, len(histories))
+	for id := range histories {
+		ids = append(ids, id)
+	}
+
+	switch opts.By {
+	case analytics.ByLabel:
+		labels, err := s.GetLabelsForIssues(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to load labels: %w", err)
+		}
+		opts.Labels = labels
+
+	case analytics.ByEpic:
+		deps, err := s.GetAllDependencyRecords(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load dependencies: %w", err)
+		}
+		parents := make(map[string]string)
+		for child, childDeps := range deps {
+			for _, dep := range childDeps {
+				if dep.Type == types.DepParentChild {
+					parents[child] = dep.DependsOnID
+				}
+			}
+		}
+		opts.Epics = make(map[string]string)
+		for _, id := range ids {
+			// Walk up to the nearest epic; the depth limit guards against cycles
+			cur := id
+			for depth := 0; depth < 50; depth++ {
+				parent, ok := parents[cur]
+				if !ok {
+					break
+				}
+				if h := histories[parent]; h != nil && h.Issue.IssueType == types.TypeEpic {
+					opts.Epics[id] = parent
+					break
+				}
+				cur = parent
+			}
+		}
+	}
+	return nil
+}
+
+func printAnalyticsHeader(w io.Writer, title string, data *analyticsData) {
+	fmt.Fprintf(w, "%s, %s to %s", title, data.opts.Since.Format("2006-01-02"), data.opts.Until.Format("2006-01-02"))
+	if data.opts.By != analytics.ByNone {
+		fmt.Fprintf(w, " by %s", data.opts.By)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w)
+}
+
+func printAnalyticsSummary(w io.Writer, data *analyticsData, summaries []*analytics.Summary) {
+	printAnalyticsHeader(w, "Flow metrics", data)
+	if len(summaries) == 0 {
+		fmt.Fprintln(w, "No issues closed in this window")
+		return
+	}
+	rows := [][]string{{"segment", "closed", "per week", "cycle avg", "cycle median", "cycle p85", "lead avg", "lead median", "lead p85", "reopened"}}
+	for _, s := range summaries {
+		rows = append(rows, []string{
+			s.Segment,
+			strconv.Itoa(s.Completed),
+			fmt.Sprintf("%.1f", s.Throughput),
+			formatAnalyticsHours(s.CycleTime, s.CycleTime.Mean),
+			formatAnalyticsHours(s.CycleTime, s.CycleTime.Median),
+			formatAnalyticsHours(s.CycleTime, s.CycleTime.P85),
+			formatAnalyticsHours(s.LeadTime, s.LeadTime.Mean),
+			formatAnalyticsHours(s.LeadTime, s.LeadTime.Median),
+			formatAnalyticsHours(s.LeadTime, s.LeadTime.P85),
+			fmt.Sprintf("%d (%.0f%%)", s.Reopened, s.ReopenRate*100),
+		})
+	}
+	if data.opts.By == analytics.ByNone {
+		rows = analyticsDropColumn(rows, 0)
+	}
+	printAnalyticsTable(w, rows)
+}
+
+// formatAnalyticsHours renders one statistic of d, or "-" without samples.
+func formatAnalyticsHours(d analytics.Durations, hours float64) string {
+	switch {
+	case d.Count == 0:
+		return "-"
+	case hours < 1:
+		return fmt.Sprintf("%.0fm", hours*60)
+	case hours < 48:
+		return fmt.Sprintf("%.1fh", hours)
+	default:
+		return fmt.Sprintf("%.1fd", hours/24)
+	}
+}
+
+func analyticsSummaryCSV(summaries []*analytics.Summary) [][]string {
+	rows := [][]string{{
+		"segment", "completed", "throughput_per_week",
+		"cycle_count", "cycle_mean_hours", "cycle_median_hours", "cycle_p85_hours",
+		"lead_count", "lead_mean_hours", "lead_median_hours", "lead_p85_hours",
+		"reopened", "reopen_rate",
+	}}
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	for _, s := range summaries {
+		rows = append(rows, []string{
+			s.Segment, strconv.Itoa(s.Completed), f(s.Throughput),
+			strconv.Itoa(s.CycleTime.Count), f(s.CycleTime.Mean), f(s.CycleTime.Median), f(s.CycleTime.P85),
+			strconv.Itoa(s.LeadTime.Count), f(s.LeadTime.Mean), f(s.LeadTime.Median), f(s.LeadTime.P85),
+			strconv.Itoa(s.Reopened), f(s.ReopenRate),
+		})
+	}
+	return rows
+}
+
+// analyticsPivot turns (key, segment, value) rows into one row per key with
+// a column per segment, keeping the order keys and segments first appear in.
+func analyticsPivot(rows [][]string) [][]string {
+	var keys, segments []string
+	seenKey, seenSeg := make(map[string]bool), make(map[string]bool)
+	values := make(map[[2]string]string)
+	for _, row := range rows[1:] {
+		if !seenKey[row[0]] {
+			seenKey[row[0]] = true
+			keys = append(keys, row[0])
+		}
+		if !seenSeg[row[1]] {
+			seenSeg[row[1]] = true
+			segments = append(segments, row[1])
+		}
+		values[[2]string{row[0], row[1]}] = row[2]
+	}
+
+	header := []string{rows[0][0]}
+	if len(segments) == 1 && segments[0] == analytics.NoSegment {
+		header = append(header, rows[0][2])
+	} else {
+		header = append(header, segments...)
+	}
+	out := [][]string{header}
+	for _, key := range keys {
+		row := []string{key}
+		for _, seg := range segments {
+			row = append(row, values[[2]string{key, seg}])
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+func analyticsDropColumn(rows [][]string, col int) [][]string {
+	out := make([][]string, len(rows))
+	for i, row := range rows {
+		out[i] = append(append([]string{}, row[:col]...), row[col+1:]...)
+	}
+	return out
+}
+
+// printAnalyticsTable prints rows as aligned columns with an upper-case header.
+func printAnalyticsTable(w io.Writer, rows [][]string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, row := range rows {
+		if i == 0 {
+			row = append([]string{}, row...)
+			for j := range row {
+				row[j] = strings.ToUpper(row[j])
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	_ = tw.Flush()
+}
+
+func writeAnalyticsCSV(w io.Writer, rows [][]string) {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		FatalErrorRespectJSON("writing CSV: %v", err)
+	}
+}
+
+func init() {
+	analyticsCmd.PersistentFlags().String("since", "", "Start of the window (e.g. -30d, 2025-01-01; default 90 days before --until)")
+	analyticsCmd.PersistentFlags().String("until", "", "End of the window (default now)")
+	analyticsCmd.PersistentFlags().String("by", "", "Break down by: type, label, assignee or epic")
+	analyticsCmd.PersistentFlags().String("format", "table", "Output format: table or csv")
+	analyticsCmd.AddCommand(analyticsThroughputCmd, analyticsFlowCmd)
+	rootCmd.AddCommand(analyticsCmd)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/analytics"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestLoadIssueHistories(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	for _, issue := range []*types.Issue{
+		{ID: "test-epic", Title: "Epic", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeEpic},
+		{ID: "test-feat", Title: "Feature", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeFeature},
+		{ID: "test-1", Title: "Nested", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "test-2", Title: "Loose", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	} {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: "test-feat", DependsOnID: "test-epic", Type: types.DepParentChild},
+		{IssueID: "test-1", DependsOnID: "test-feat", Type: types.DepParentChild},
+	} {
+		if err := s.AddDependency(ctx, dep, "tester"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	if err := s.AddLabel(ctx, "test-1", "backend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := s.UpdateIssue(ctx, "test-1", map[string]interface{}{"status": string(types.StatusInProgress)}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := s.CloseIssue(ctx, "test-1", "done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	now := time.Now().Add(time.Minute)
+	histories, err := loadIssueHistories(ctx, s, now)
+	if err != nil {
+		t.Fatalf("loadIssueHistories failed: %v", err)
+	}
+	var statuses []types.Status
+	for _, tr := range histories["test-1"].Transitions {
+		statuses = append(statuses, tr.Status)
+	}
+	want := []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusClosed}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("test-1 transitions = %v, want %v", statuses, want)
+	}
+
+	opts := analytics.Options{Since: now.Add(-time.Hour), Until: now, By: analytics.ByEpic}
+	if err := loadAnalyticsSegments(ctx, s, histories, &opts); err != nil {
+		t.Fatalf("loadAnalyticsSegments failed: %v", err)
+	}
+	if opts.Epics["test-1"] != "test-epic" || opts.Epics["test-feat"] != "test-epic" || opts.Epics["test-2"] != "" {
+		t.Errorf("epics = %v, want test-1 and test-feat under test-epic", opts.Epics)
+	}
+
+	opts.By = analytics.ByLabel
+	if err := loadAnalyticsSegments(ctx, s, histories, &opts); err != nil {
+		t.Fatalf("loadAnalyticsSegments failed: %v", err)
+	}
+	summaries := analytics.Summarize(histories, opts)
+	if len(summaries) != 1 || summaries[0].Segment != "backend" || summaries[0].CycleTime.Count != 1 {
+		t.Errorf("summaries by label = %+v, want one started backend completion", summaries)
+	}
+}
+
+func TestAnalyticsPivot(t *testing.T) {
+	rows := [][]string{
+		{"week", "segment", "completed"},
+		{"2025-03-03", "bug", "2"},
+		{"2025-03-03", "task", "1"},
+		{"2025-03-10", "bug", "0"},
+		{"2025-03-10", "task", "4"},
+	}
+	want := [][]string{
+		{"week", "bug", "task"},
+		{"2025-03-03", "2", "1"},
+		{"2025-03-10", "0", "4"},
+	}
+	if got := analyticsPivot(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("analyticsPivot = %v, want %v", got, want)
+	}
+
+	unsegmented := [][]string{{"week", "segment", "completed"}, {"2025-03-03", analytics.NoSegment, "3"}}
+	if got := analyticsPivot(unsegmented); !reflect.DeepEqual(got[0], []string{"week", "completed"}) {
+		t.Errorf("unsegmented header = %v, want [week completed]", got[0])
+	}
+}
+
+func TestFormatAnalyticsHours(t *testing.T) {
+	d := analytics.Durations{Count: 1}
+	for hours, want := range map[float64]string{0.5: "30m", 5: "5.0h", 72: "3.0d"} {
+		if got := formatAnalyticsHours(d, hours); got != want {
+			t.Errorf("formatAnalyticsHours(%v) = %q, want %q", hours, got, want)
+		}
+	}
+	if got := formatAnalyticsHours(analytics.Durations{}, 0); got != "-" {
+		t.Errorf("no samples = %q, want -", got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -789,6 +790,11 @@ func updateFlushExportMetadata(ctx context.Context, s storage.Storage, jsonlPath
 		fmt.Fprintf(os.Stderr, "Warning: failed to update jsonl_file_hash after export: %v\n", err)
 	}
 
+	// Record the JSONL record format so readers know which schema applies
+	if err := s.SetMetadata(ctx, "jsonl_format_version", strconv.Itoa(types.JSONLFormatVersion)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update jsonl_format_version after export: %v\n", err)
+	}
+
 	// Update last_import_time so staleness check doesn't see JSONL as "newer" (fixes #399)
 	// Use RFC3339Nano to preserve nanosecond precision.
 	exportTime := time.Now().Format(time.RFC3339Nano)
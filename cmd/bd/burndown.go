@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/analytics"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var burndownCmd = &cobra.Command{
+	Use:     "burndown <epic-or-molecule-id>",
+	GroupID: "views",
+	Short:   "Burndown and burnup chart for an epic or molecule",
+	Long: `Chart the remaining and completed children of an epic or molecule per day.
+
+The series is rebuilt from the event history: children count from the day
+they were linked with a parent-child dependency (so scope added mid-sprint
+shows up when it was added) until they are unlinked, and each child counts
+as completed on the days it was closed. Estimates (estimated_minutes) are
+tracked the same way, including changes to them.
+
+The finish date is projected from the net drop in remaining children over
+the last --window days; scope added in that time slows the projection down.
+
+In the chart, bar height is the scope on that day: the solid part is the
+remaining work (burndown) and the shaded part is what was completed
+(burnup). Use --estimate to chart estimated minutes instead of children.
+
+Examples:
+  bd burndown bd-epic1
+  bd burndown bd-mol-xyz --since -14d --window 7
+  bd burndown bd-epic1 --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+		sinceStr, _ := cmd.Flags().GetString("since")
+		untilStr, _ := cmd.Flags().GetString("until")
+		window, _ := cmd.Flags().GetInt("window")
+		byEstimate, _ := cmd.Flags().GetBool("estimate")
+
+		opts := analytics.BurndownOptions{WindowDays: window}
+		var err error
+		if sinceStr != "" {
+			if opts.Since, err = parseTimeFlag(sinceStr); err != nil {
+				FatalErrorRespectJSON("parsing --since: %v", err)
+			}
+		}
+		if untilStr != "" {
+			if opts.Until, err = parseTimeFlag(untilStr); err != nil {
+				FatalErrorRespectJSON("parsing --until: %v", err)
+			}
+		}
+
+		// Read-only, so read directly even when the daemon is running
+		if daemonClient != nil && store == nil {
+			store, err = sqlite.New(ctx, dbPath)
+			if err != nil {
+				FatalErrorRespectJSON("failed to open database: %v", err)
+			}
+			defer func() { _ = store.Close() }()
+		}
+		if store == nil {
+			FatalErrorRespectJSON("no database connection")
+		}
+
+		id, err := utils.ResolvePartialID(ctx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("issue '%s' not found", args[0])
+		}
+		report, err := loadBurndown(ctx, store, id, opts)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(report)
+			return
+		}
+		printBurndown(os.Stdout, report, byEstimate)
+	},
+}
+
+// burndownReport is the burndown of one epic or molecule.
+type burndownReport struct {
+	IssueID    string                     `json:"issue_id"`
+	Title      string                     `json:"title"`
+	Series     []*analytics.BurndownPoint `json:"series"`
+	Projection analytics.Projection       `json:"projection"`
+}
+
+// loadBurndown rebuilds the daily burndown series for parentID.
+func loadBurndown(ctx context.Context, s storage.Storage, parentID string, opts analytics.BurndownOptions) (*burndownReport, error) {
+	parent, err := s.GetIssue(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("issue %s not found", parentID)
+	}
+
+	allDeps, err := s.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependencies: %w", err)
+	}
+	var current []*types.Dependency
+	for _, deps := range allDeps {
+		for _, dep := range deps {
+			if dep.DependsOnID == parentID && dep.Type == types.DepParentChild {
+				current = append(current, dep)
+			}
+		}
+	}
+	scopeEvents, err := s.SearchEvents(ctx, types.EventFilter{Types: analytics.ScopeEventTypes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	members := analytics.Memberships(parentID, scopeEvents, current)
+
+	histories := make(map[string]*analytics.History)
+	if len(members) > 0 {
+		seen := make(map[string]bool)
+		var childIDs []string
+		for _, m := range members {
+			if !seen[m.IssueID] {
+				seen[m.IssueID] = true
+				childIDs = append(childIDs, m.IssueID)
+			}
+		}
+		children, err := s.SearchIssues(ctx, "", types.IssueFilter{IDs: childIDs})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load children: %w", err)
+		}
+		eventTypes := append([]types.EventType{types.EventUpdated}, analytics.HistoryEventTypes...)
+		events, err := s.SearchEvents(ctx, types.EventFilter{IssueIDs: childIDs, Types: eventTypes})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load events: %w", err)
+		}
+		histories = analytics.BuildHistories(children, events)
+	}
+
+	series, projection := analytics.Burndown(parent, histories, members, opts)
+	return &burndownReport{IssueID: parent.ID, Title: parent.Title, Series: series, Projection: projection}, nil
+}
+
+// burndownChartHeight is the number of rows in the ASCII chart.
+const burndownChartHeight = 12
+
+// burndownChartWidth caps the chart's columns; longer series are sampled.
+const burndownChartWidth = 72
+
+func printBurndown(w io.Writer, r *burndownReport, byEstimate bool) {
+	fmt.Fprintf(w, "%s %s: %s\n\n", ui.RenderAccent("Burndown"), r.IssueID, r.Title)
+	if !burndownHadScope(r.Series) {
+		fmt.Fprintln(w, "No children to chart (link them with a parent-child dependency)")
+		return
+	}
+
+	values := func(p *analytics.BurndownPoint) (remaining, total int) {
+		if byEstimate {
+			return p.RemainingMinutes, p.TotalMinutes
+		}
+		return p.Remaining, p.Total
+	}
+	renderBurndownChart(w, burndownSample(r.Series, burndownChartWidth), values)
+
+	first, last := r.Series[0], r.Series[len(r.Series)-1]
+	fmt.Fprintf(w, "\nScope:     %d children (%+d since %s), %d done, %d remaining\n",
+		last.Total, last.Total-first.Total, first.Date.Format("2006-01-02"), last.Completed, last.Remaining)
+	if last.TotalMinutes > 0 {
+		fmt.Fprintf(w, "Estimate:  %s remaining of %s\n",
+			formatBurndownMinutes(last.RemainingMinutes), formatBurndownMinutes(last.TotalMinutes))
+	}
+
+	p := r.Projection
+	switch {
+	case p.Done:
+		fmt.Fprintln(w, "Projected: done")
+	case len(r.Series) < 2:
+		fmt.Fprintln(w, "Projected: needs at least two days of history")
+	case p.Finish != nil:
+		fmt.Fprintf(w, "Velocity:  %.1f/day over the last %d days\n", p.PerDay, p.WindowDays)
+		fmt.Fprintf(w, "Projected: %s", p.Finish.Format("2006-01-02"))
+		if p.FinishEstimate != nil {
+			fmt.Fprintf(w, " (%s by estimate)", p.FinishEstimate.Format("2006-01-02"))
+		}
+		fmt.Fprintln(w)
+	default:
+		fmt.Fprintf(w, "Projected: no finish date, remaining work did not drop over the last %d days\n", p.WindowDays)
+	}
+}
+
+func burndownHadScope(series []*analytics.BurndownPoint) bool {
+	for _, p := range series {
+		if p.Total > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// burndownSample keeps at most width points, always including the last.
+func burndownSample(series []*analytics.BurndownPoint, width int) []*analytics.BurndownPoint {
+	if len(series) <= width {
+		return series
+	}
+	sampled := make([]*analytics.BurndownPoint, 0, width)
+	step := float64(len(series)-1) / float64(width-1)
+	for i := 0; i < width; i++ {
+		sampled = append(sampled, series[int(math.Round(float64(i)*step))])
+	}
+	return sampled
+}
+
+// renderBurndownChart draws one column per point: the remaining part of the
+// scope solid and the completed part shaded.
+func renderBurndownChart(w io.Writer, points []*analytics.BurndownPoint, values func(*analytics.BurndownPoint) (remaining, total int)) {
+	maxTotal := 1
+	for _, p := range points {
+		_, total := values(p)
+		maxTotal = max(maxTotal, total)
+	}
+	cells := func(v int) int {
+		return int(math.Round(float64(v) * burndownChartHeight / float64(maxTotal)))
+	}
+
+	labelWidth := len(fmt.Sprint(maxTotal))
+	for row := burndownChartHeight; row >= 1; row-- {
+		label := ""
+		switch row {
+		case burndownChartHeight:
+			label = fmt.Sprint(maxTotal)
+		case burndownChartHeight / 2:
+			label = fmt.Sprint(maxTotal / 2)
+		}
+		var line strings.Builder
+		for _, p := range points {
+			remaining, total := values(p)
+			switch {
+			case row <= cells(remaining):
+				line.WriteString("█")
+			case row <= cells(total):
+				line.WriteString("░")
+			default:
+				line.WriteString(" ")
+			}
+		}
+		fmt.Fprintf(w, "%*s │%s\n", labelWidth, label, strings.TrimRight(line.String(), " "))
+	}
+	fmt.Fprintf(w, "%*s └%s\n", labelWidth, "0", strings.Repeat("─", len(points)))
+
+	start := points[0].Date.Format("2006-01-02")
+	end := points[len(points)-1].Date.Format("2006-01-02")
+	gap := max(len(points)-len(start)-len(end), 1)
+	if len(points) == 1 {
+		end, gap = "", 0
+	}
+	fmt.Fprintf(w, "%*s  %s%s%s\n", labelWidth, "", start, strings.Repeat(" ", gap), end)
+	fmt.Fprintf(w, "%*s  █ remaining  ░ completed\n", labelWidth, "")
+}
+
+func formatBurndownMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%.1fh", float64(minutes)/60)
+}
+
+func init() {
+	burndownCmd.Flags().String("since", "", "First day to chart (e.g. -14d, 2025-01-01; default when the epic was created)")
+	burndownCmd.Flags().String("until", "", "Last day to chart (default now)")
+	burndownCmd.Flags().Int("window", 14, "Days of recent history the projection is based on")
+	burndownCmd.Flags().Bool("estimate", false, "Chart estimated minutes instead of children")
+	rootCmd.AddCommand(burndownCmd)
+}
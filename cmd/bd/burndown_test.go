@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/analytics"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestLoadBurndown(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	est := func(m int) *int { return &m }
+	for _, issue := range []*types.Issue{
+		{ID: "test-epic", Title: "Epic", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeEpic},
+		{ID: "test-1", Title: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, EstimatedMinutes: est(60)},
+		{ID: "test-2", Title: "Open", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, EstimatedMinutes: est(30)},
+		{ID: "test-3", Title: "Unlinked", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	} {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	for _, id := range []string{"test-1", "test-2", "test-3"} {
+		dep := &types.Dependency{IssueID: id, DependsOnID: "test-epic", Type: types.DepParentChild}
+		if err := s.AddDependency(ctx, dep, "tester"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	if err := s.RemoveDependency(ctx, "test-3", "test-epic", "tester"); err != nil {
+		t.Fatalf("RemoveDependency failed: %v", err)
+	}
+	if err := s.CloseIssue(ctx, "test-1", "done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	report, err := loadBurndown(ctx, s, "test-epic", analytics.BurndownOptions{Until: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("loadBurndown failed: %v", err)
+	}
+	if len(report.Series) == 0 {
+		t.Fatal("empty series")
+	}
+	last := report.Series[len(report.Series)-1]
+	if last.Total != 2 || last.Completed != 1 || last.Remaining != 1 {
+		t.Errorf("last point = %+v, want 2 children with 1 done (test-3 unlinked)", last)
+	}
+	if last.TotalMinutes != 90 || last.RemainingMinutes != 30 {
+		t.Errorf("estimates = %dm total, %dm remaining, want 90m and 30m", last.TotalMinutes, last.RemainingMinutes)
+	}
+
+	if _, err := loadBurndown(ctx, s, "test-missing", analytics.BurndownOptions{}); err == nil {
+		t.Error("loadBurndown should fail for a missing issue")
+	}
+}
+
+func TestRenderBurndownChart(t *testing.T) {
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	var series []*analytics.BurndownPoint
+	for i, remaining := range []int{4, 4, 3, 1} {
+		series = append(series, &analytics.BurndownPoint{Date: start.AddDate(0, 0, i), Total: 4, Completed: 4 - remaining, Remaining: remaining})
+	}
+
+	var buf bytes.Buffer
+	renderBurndownChart(&buf, series, func(p *analytics.BurndownPoint) (int, int) { return p.Remaining, p.Total })
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != burndownChartHeight+3 {
+		t.Fatalf("chart has %d lines, want %d:\n%s", len(lines), burndownChartHeight+3, buf.String())
+	}
+	if top := lines[0]; top != "4 │██░░" {
+		t.Errorf("top row = %q, want full scope with the last two days partly done", top)
+	}
+	if bottom := lines[burndownChartHeight-1]; bottom != "  │████" {
+		t.Errorf("bottom row = %q, want remaining work on every day", bottom)
+	}
+	if !strings.Contains(buf.String(), "2025-03-03") {
+		t.Errorf("chart is missing the start date:\n%s", buf.String())
+	}
+}
+
+func TestBurndownSample(t *testing.T) {
+	var series []*analytics.BurndownPoint
+	for i := 0; i < 200; i++ {
+		series = append(series, &analytics.BurndownPoint{Total: i})
+	}
+	sampled := burndownSample(series, burndownChartWidth)
+	if len(sampled) != burndownChartWidth {
+		t.Fatalf("sampled %d points, want %d", len(sampled), burndownChartWidth)
+	}
+	if sampled[0] != series[0] || sampled[len(sampled)-1] != series[199] {
+		t.Error("sampling should keep the first and last points")
+	}
+	if got := burndownSample(series[:10], burndownChartWidth); len(got) != 10 {
+		t.Errorf("short series sampled to %d points, want all 10", len(got))
+	}
+}
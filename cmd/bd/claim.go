@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/util"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+var claimCmd = &cobra.Command{
+	Use:     "claim [id]",
+	GroupID: "issues",
+	Short:   "Atomically claim an issue (or the next ready issue)",
+	Long: `Atomically claim an issue: assign it to you and set it to in_progress.
+
+Unlike 'bd update --claim', the check and the update happen in a single
+transaction guarded by a lease, so two agents racing for the same issue
+cannot both win. The loser gets an error (or, with --from-ready, moves on
+to the next ready issue).
+
+Leases are renewed by activity on the issue (updates, comments). If the
+holder goes quiet for longer than --ttl, the lease expires and the issue
+returns to open so another agent can pick it up.
+
+Examples:
+  bd claim bd-42                    # Claim a specific issue
+  bd claim --from-ready             # Claim the next ready issue
+  bd claim --from-ready -t bug -p 1 # Claim the next ready P1 bug
+  bd claim bd-42 --ttl 2h           # Claim with a longer lease`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckReadonly("claim")
+
+		fromReady, _ := cmd.Flags().GetBool("from-ready")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		issueType, _ := cmd.Flags().GetString("type")
+		issueType = util.NormalizeIssueType(issueType)
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		labelsAny, _ := cmd.Flags().GetStringSlice("label-any")
+		parentID, _ := cmd.Flags().GetString("parent")
+		molTypeStr, _ := cmd.Flags().GetString("mol-type")
+		sortPolicy, _ := cmd.Flags().GetString("sort")
+
+		if fromReady == (len(args) == 1) {
+			FatalErrorRespectJSON("specify either an issue ID or --from-ready")
+		}
+		if ttl <= 0 {
+			FatalErrorRespectJSON("--ttl must be positive")
+		}
+		if !types.SortPolicy(sortPolicy).IsValid() {
+			FatalErrorRespectJSON("invalid sort policy '%s'. Valid values: hybrid, priority, oldest", sortPolicy)
+		}
+		var molType *types.MolType
+		if molTypeStr != "" {
+			mt := types.MolType(molTypeStr)
+			if !mt.IsValid() {
+				FatalErrorRespectJSON("invalid mol-type %q (must be swarm, patrol, or work)", molTypeStr)
+			}
+			molType = &mt
+		}
+		labels = util.NormalizeLabels(labels)
+		labelsAny = util.NormalizeLabels(labelsAny)
+
+		var priority *int
+		if cmd.Flags().Changed("priority") {
+			p, _ := cmd.Flags().GetInt("priority")
+			priority = &p
+		}
+
+		ctx := rootCtx
+		var issue *types.Issue
+
+		// If daemon is running, use RPC
+		if daemonClient != nil {
+			claimArgs := &rpc.ClaimArgs{
+				FromReady:  fromReady,
+				TTL:        ttl.String(),
+				Priority:   priority,
+				Type:       issueType,
+				SortPolicy: sortPolicy,
+				Labels:     labels,
+				LabelsAny:  labelsAny,
+				ParentID:   parentID,
+				MolType:    molTypeStr,
+			}
+			if !fromReady {
+				resp, err := daemonClient.ResolveID(&rpc.ResolveIDArgs{ID: args[0]})
+				if err != nil {
+					FatalErrorRespectJSON("resolving ID %s: %v", args[0], err)
+				}
+				if err := json.Unmarshal(resp.Data, &claimArgs.ID); err != nil {
+					FatalErrorRespectJSON("unmarshaling resolved ID: %v", err)
+				}
+			}
+			resp, err := daemonClient.Claim(claimArgs)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if err := json.Unmarshal(resp.Data, &issue); err != nil {
+				FatalErrorRespectJSON("parsing response: %v", err)
+			}
+		} else {
+			if store == nil {
+				FatalErrorRespectJSON("database not initialized")
+			}
+			var err error
+			if fromReady {
+				filter := types.WorkFilter{
+					Type:       issueType,
+					Priority:   priority,
+					SortPolicy: types.SortPolicy(sortPolicy),
+					Labels:     labels,
+					LabelsAny:  labelsAny,
+					MolType:    molType,
+				}
+				if parentID != "" {
+					filter.ParentID = &parentID
+				}
+				issue, err = storage.ClaimReady(ctx, store, filter, actor, ttl)
+			} else {
+				var fullID string
+				fullID, err = utils.ResolvePartialID(ctx, store, args[0])
+				if err != nil {
+					FatalErrorRespectJSON("resolving %s: %v", args[0], err)
+				}
+				issue, err = storage.ClaimIssue(ctx, store, fullID, actor, ttl)
+			}
+			if errors.Is(err, storage.ErrNoClaimableWork) {
+				FatalErrorRespectJSON("no ready work to claim")
+			}
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			markDirtyAndScheduleFlush()
+		}
+
+		if jsonOutput {
+			outputJSON(issue)
+			return
+		}
+		fmt.Printf("%s Claimed %s: %s (lease expires in %s)\n",
+			ui.RenderPass("✓"), ui.RenderID(issue.ID), issue.Title, formatLeaseTTL(ttl))
+	},
+}
+
+// formatLeaseTTL renders a lease duration without trailing zero units
+// (30m rather than 30m0s).
+func formatLeaseTTL(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func init() {
+	claimCmd.Flags().Bool("from-ready", false, "Claim the next ready issue instead of a specific ID")
+	claimCmd.Flags().Duration("ttl", types.DefaultLeaseTTL, "Lease duration; the claim lapses if there is no activity for this long")
+	claimCmd.Flags().IntP("priority", "p", 0, "With --from-ready: filter by priority")
+	claimCmd.Flags().StringP("type", "t", "", "With --from-ready: filter by issue type")
+	claimCmd.Flags().StringSliceP("label", "l", []string{}, "With --from-ready: filter by labels (AND)")
+	claimCmd.Flags().StringSlice("label-any", []string{}, "With --from-ready: filter by labels (OR)")
+	claimCmd.Flags().String("parent", "", "With --from-ready: filter to descendants of this bead/epic")
+	claimCmd.Flags().String("mol-type", "", "With --from-ready: filter by molecule type: swarm, patrol, or work")
+	claimCmd.Flags().StringP("sort", "s", "hybrid", "With --from-ready: sort policy: hybrid (default), priority, oldest")
+	claimCmd.ValidArgsFunction = issueIDCompletion
+	rootCmd.AddCommand(claimCmd)
+}
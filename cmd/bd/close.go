@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/hooks"
 	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/types"
@@ -49,6 +50,10 @@ create, update, show, or close operation).`,
 		continueFlag, _ := cmd.Flags().GetBool("continue")
 		noAuto, _ := cmd.Flags().GetBool("no-auto")
 		suggestNext, _ := cmd.Flags().GetBool("suggest-next")
+		requireCommit, _ := cmd.Flags().GetBool("require-commit")
+		if !cmd.Flags().Changed("require-commit") {
+			requireCommit = config.GetBool("close.require-commit")
+		}
 
 		// Get session ID from flag or environment variable
 		session, _ := cmd.Flags().GetString("session")
@@ -122,11 +127,12 @@ create, update, show, or close operation).`,
 				}
 
 				closeArgs := &rpc.CloseArgs{
-					ID:          id,
-					Reason:      reason,
-					Session:     session,
-					SuggestNext: suggestNext,
-					Force:       force,
+					ID:            id,
+					Reason:        reason,
+					Session:       session,
+					SuggestNext:   suggestNext,
+					Force:         force,
+					RequireCommit: requireCommit,
 				}
 				resp, err := daemonClient.CloseIssue(closeArgs)
 				if err != nil {
@@ -209,6 +215,13 @@ create, update, show, or close operation).`,
 						fmt.Fprintf(os.Stderr, "cannot close %s: blocked by open issues %v (use --force to override)\n", id, blockers)
 						continue
 					}
+					if requireCommit {
+						if err := requireLinkedCommit(ctx, result.Store, result.ResolvedID); err != nil {
+							result.Close()
+							fmt.Fprintf(os.Stderr, "%s\n", err)
+							continue
+						}
+					}
 				}
 
 				if err := result.Store.CloseIssue(ctx, result.ResolvedID, reason, actor, session); err != nil {
@@ -271,6 +284,12 @@ create, update, show, or close operation).`,
 					fmt.Fprintf(os.Stderr, "cannot close %s: blocked by open issues %v (use --force to override)\n", id, blockers)
 					continue
 				}
+				if requireCommit {
+					if err := requireLinkedCommit(ctx, store, id); err != nil {
+						fmt.Fprintf(os.Stderr, "%s\n", err)
+						continue
+					}
+				}
 			}
 
 			if err := store.CloseIssue(ctx, id, reason, actor, session); err != nil {
@@ -329,6 +348,13 @@ create, update, show, or close operation).`,
 					fmt.Fprintf(os.Stderr, "cannot close %s: blocked by open issues %v (use --force to override)\n", id, blockers)
 					continue
 				}
+				if requireCommit {
+					if err := requireLinkedCommit(ctx, result.Store, result.ResolvedID); err != nil {
+						result.Close()
+						fmt.Fprintf(os.Stderr, "%s\n", err)
+						continue
+					}
+				}
 			}
 
 			if err := result.Store.CloseIssue(ctx, result.ResolvedID, reason, actor, session); err != nil {
@@ -413,6 +439,7 @@ func init() {
 	closeCmd.Flags().Bool("continue", false, "Auto-advance to next step in molecule")
 	closeCmd.Flags().Bool("no-auto", false, "With --continue, show next step but don't claim it")
 	closeCmd.Flags().Bool("suggest-next", false, "Show newly unblocked issues after closing")
+	closeCmd.Flags().Bool("require-commit", false, "Refuse to close issues no commit mentions (default from close.require-commit)")
 	closeCmd.Flags().String("session", "", "Claude Code session ID (or set CLAUDE_SESSION_ID env var)")
 	closeCmd.ValidArgsFunction = issueIDCompletion
 	rootCmd.AddCommand(closeCmd)
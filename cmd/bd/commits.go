@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/commitlink"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// commitCloseRunner closes an issue named by a "Closes" commit. It goes
+// through bd close so the change is exported like any other close.
+var commitCloseRunner = func(issueID, reason string) error {
+	cmd := exec.Command("bd", "close", issueID, "--reason", reason) // #nosec G204 -- issueID comes from the database
+	return cmd.Run()
+}
+
+// commitIsAncestor reports whether a commit has reached a branch.
+var commitIsAncestor = func(ctx context.Context, sha, branch string) bool {
+	return commitlink.IsAncestor(ctx, "", sha, branch)
+}
+
+var commitsCmd = &cobra.Command{
+	Use:     "commits <issue-id>",
+	GroupID: "views",
+	Short:   "List git commits that mention an issue",
+	Long: `List the git commits that mention an issue.
+
+Commit links are recorded by the post-commit and post-merge hooks (see
+'bd hooks install'), or by 'bd commits index' for existing history. A
+commit links to every issue ID in its message; IDs after "Closes",
+"Fixes" or "Resolves" are recorded as closes, IDs after "Refs", "See" or
+"Part of" as refs, and any other mention as a plain mention.
+
+When a commit with "Closes <id>" reaches the main branch, the issue is
+closed automatically (disable with commits.auto-close: false).
+
+Examples:
+  bd commits bd-42              # Commits that mention bd-42
+  bd commits index              # Index all commits reachable from HEAD
+  bd commits index main~20..main`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+
+		var id string
+		var links []*types.CommitLink
+		if daemonClient != nil {
+			resp, err := daemonClient.ResolveID(&rpc.ResolveIDArgs{ID: args[0]})
+			if err != nil {
+				FatalErrorRespectJSON("resolving ID %s: %v", args[0], err)
+			}
+			if err := json.Unmarshal(resp.Data, &id); err != nil {
+				FatalErrorRespectJSON("unmarshaling resolved ID: %v", err)
+			}
+			resp, err = daemonClient.Show(&rpc.ShowArgs{ID: id})
+			if err != nil {
+				FatalErrorRespectJSON("fetching %s: %v", id, err)
+			}
+			var details types.IssueDetails
+			if err := json.Unmarshal(resp.Data, &details); err != nil {
+				FatalErrorRespectJSON("parsing response: %v", err)
+			}
+			links = details.Commits
+		} else {
+			var err error
+			id, err = utils.ResolvePartialID(ctx, store, args[0])
+			if err != nil {
+				FatalErrorRespectJSON("resolving ID %s: %v", args[0], err)
+			}
+			links, err = store.GetCommitLinks(ctx, id)
+			if err != nil {
+				FatalErrorRespectJSON("getting commits for %s: %v", id, err)
+			}
+		}
+
+		if jsonOutput {
+			if links == nil {
+				links = []*types.CommitLink{}
+			}
+			outputJSON(links)
+			return
+		}
+		if len(links) == 0 {
+			fmt.Printf("No commits mention %s\n", id)
+			return
+		}
+		fmt.Printf("%s\n", ui.RenderBold(fmt.Sprintf("Commits mentioning %s:", id)))
+		printCommitLinks(links)
+	},
+}
+
+var commitsIndexCmd = &cobra.Command{
+	Use:   "index [revision-range]",
+	Short: "Record commit links from git history",
+	Long: `Scan git history for commits that mention issues and record them.
+
+The range is passed to git log and defaults to HEAD (all history). Only
+IDs of issues that exist in the database are recorded; re-indexing a
+commit replaces its earlier links. The git hooks run this automatically
+for new commits.
+
+Examples:
+  bd commits index                   # All commits reachable from HEAD
+  bd commits index ORIG_HEAD..HEAD   # Commits brought in by the last merge
+  bd commits index --limit 1         # Just the latest commit`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		// Commit links are a local index and are not exported, so they are
+		// always written directly
+		if err := ensureDirectMode("commit index requires direct database access"); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		rev := "HEAD"
+		if len(args) == 1 {
+			rev = args[0]
+		}
+		gitArgs := []string{rev}
+		if limit > 0 {
+			gitArgs = append([]string{fmt.Sprintf("--max-count=%d", limit)}, gitArgs...)
+		}
+		commits, err := commitlink.Log(ctx, "", gitArgs...)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		links, err := indexCommits(ctx, store, commits)
+		if err != nil {
+			FatalErrorRespectJSON("indexing commits: %v", err)
+		}
+		closed := autoCloseFromCommits(ctx, store, links)
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"commits": len(commits),
+				"links":   len(links),
+				"closed":  closed,
+			})
+			return
+		}
+		// Stay silent for commits that mention no issues (the common hook case)
+		if quietFlag || len(links) == 0 {
+			return
+		}
+		fmt.Printf("%s Indexed %d commit link(s) from %d commit(s)\n", ui.RenderPass("✓"), len(links), len(commits))
+		for _, id := range closed {
+			fmt.Printf("%s Closed %s\n", ui.RenderPass("✓"), id)
+		}
+	},
+}
+
+// indexCommits records links from commits to the issues they mention.
+// Mentions of IDs that don't exist in the database are dropped.
+func indexCommits(ctx context.Context, s storage.Storage, commits []*commitlink.Commit) ([]*types.CommitLink, error) {
+	prefix, err := s.GetConfig(ctx, "issue_prefix")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue prefix: %w", err)
+	}
+	prefix = strings.TrimSuffix(prefix, "-")
+
+	exists := make(map[string]bool)
+	var links []*types.CommitLink
+	for _, commit := range commits {
+		for _, link := range commit.Links(prefix) {
+			known, seen := exists[link.IssueID]
+			if !seen {
+				issue, err := s.GetIssue(ctx, link.IssueID)
+				if err != nil {
+					return nil, err
+				}
+				known = issue != nil
+				exists[link.IssueID] = known
+			}
+			if known {
+				links = append(links, link)
+			}
+		}
+	}
+	if err := s.AddCommitLinks(ctx, links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// autoCloseFromCommits closes open issues whose "Closes" commit has reached
+// the main branch, and returns their IDs. Failures are reported as warnings
+// so that a hook never fails a commit.
+func autoCloseFromCommits(ctx context.Context, s storage.Storage, links []*types.CommitLink) []string {
+	if !config.GetBool("commits.auto-close") {
+		return nil
+	}
+	branch := config.GetString("commits.main-branch")
+	if branch == "" {
+		branch = getDefaultBranch(ctx)
+	}
+
+	var closed []string
+	done := make(map[string]bool)
+	for _, link := range links {
+		if link.Keyword != types.CommitCloses || done[link.IssueID] {
+			continue
+		}
+		issue, err := s.GetIssue(ctx, link.IssueID)
+		if err != nil || issue == nil || issue.Status == types.StatusClosed {
+			continue
+		}
+		if !commitIsAncestor(ctx, link.CommitSHA, branch) {
+			continue
+		}
+		done[link.IssueID] = true
+		reason := fmt.Sprintf("Closed by commit %s", shortSHA(link.CommitSHA))
+		if err := commitCloseRunner(link.IssueID, reason); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not close %s: %v\n", link.IssueID, err)
+			continue
+		}
+		closed = append(closed, link.IssueID)
+	}
+	return closed
+}
+
+// requireLinkedCommit returns an error unless some commit mentions the issue.
+func requireLinkedCommit(ctx context.Context, s storage.Storage, id string) error {
+	links, err := s.GetCommitLinks(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error checking linked commits for %s: %w", id, err)
+	}
+	if len(links) == 0 {
+		return fmt.Errorf("cannot close %s: no commit mentions it (use --force to override)", id)
+	}
+	return nil
+}
+
+// printCommitLinks prints one line per linked commit, oldest first.
+func printCommitLinks(links []*types.CommitLink) {
+	for _, link := range links {
+		keyword := ""
+		if link.Keyword != types.CommitMentions {
+			keyword = ui.RenderMuted(" [" + string(link.Keyword) + "]")
+		}
+		fmt.Printf("  %s %s %s%s\n", ui.RenderMuted(shortSHA(link.CommitSHA)), link.Subject, ui.RenderMuted("· "+link.Author+" · "+link.CommittedAt.Local().Format("2006-01-02")), keyword)
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func init() {
+	commitsIndexCmd.Flags().Int("limit", 0, "Only index the most recent N commits (0 = all)")
+	commitsCmd.AddCommand(commitsIndexCmd)
+	rootCmd.AddCommand(commitsCmd)
+}
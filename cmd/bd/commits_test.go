@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/commitlink"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestIndexCommits(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	for _, id := range []string{"test-1", "test-2"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	commits := []*commitlink.Commit{
+		{SHA: "aaaa111", Author: "alice", CommittedAt: time.Now(), Message: "Fix login\n\nCloses test-1, refs test-9", Files: []string{"login.go"}},
+		{SHA: "bbbb222", Author: "bob", CommittedAt: time.Now(), Message: "Unrelated"},
+	}
+	links, err := indexCommits(ctx, s, commits)
+	if err != nil {
+		t.Fatalf("indexCommits failed: %v", err)
+	}
+	if len(links) != 1 || links[0].IssueID != "test-1" {
+		t.Fatalf("links = %+v, want only the link to the existing issue", links)
+	}
+
+	stored, err := s.GetCommitLinks(ctx, "test-1")
+	if err != nil {
+		t.Fatalf("GetCommitLinks failed: %v", err)
+	}
+	if len(stored) != 1 || stored[0].Keyword != types.CommitCloses || !reflect.DeepEqual(stored[0].Files, []string{"login.go"}) {
+		t.Errorf("stored links = %+v", stored)
+	}
+
+	if err := requireLinkedCommit(ctx, s, "test-1"); err != nil {
+		t.Errorf("requireLinkedCommit(test-1) = %v, want nil", err)
+	}
+	if err := requireLinkedCommit(ctx, s, "test-2"); err == nil {
+		t.Error("requireLinkedCommit(test-2) should fail without a linked commit")
+	}
+}
+
+func TestAutoCloseFromCommits(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+	for _, id := range []string{"test-1", "test-2", "test-3"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	if err := config.Initialize(); err != nil {
+		t.Fatalf("config.Initialize: %v", err)
+	}
+	config.Set("commits.main-branch", "main")
+	t.Cleanup(func() { config.Set("commits.main-branch", "") })
+
+	origAncestor, origClose := commitIsAncestor, commitCloseRunner
+	t.Cleanup(func() { commitIsAncestor, commitCloseRunner = origAncestor, origClose })
+	commitIsAncestor = func(_ context.Context, sha, branch string) bool {
+		return branch == "main" && sha == "on-main"
+	}
+	var closedWith []string
+	commitCloseRunner = func(issueID, reason string) error {
+		closedWith = append(closedWith, issueID+": "+reason)
+		return nil
+	}
+
+	links := []*types.CommitLink{
+		{IssueID: "test-1", CommitSHA: "on-main", Keyword: types.CommitCloses},
+		{IssueID: "test-2", CommitSHA: "on-branch", Keyword: types.CommitCloses},
+		{IssueID: "test-3", CommitSHA: "on-main", Keyword: types.CommitRefs},
+	}
+	closed := autoCloseFromCommits(ctx, s, links)
+	if !reflect.DeepEqual(closed, []string{"test-1"}) {
+		t.Errorf("closed = %v, want only the issue closed by a commit on main", closed)
+	}
+	if !reflect.DeepEqual(closedWith, []string{"test-1: Closed by commit on-main"}) {
+		t.Errorf("close runner calls = %v", closedWith)
+	}
+
+	config.Set("commits.auto-close", false)
+	t.Cleanup(func() { config.Set("commits.auto-close", true) })
+	if closed := autoCloseFromCommits(ctx, s, links); len(closed) != 0 {
+		t.Errorf("closed %v with commits.auto-close disabled", closed)
+	}
+}
@@ -242,7 +242,8 @@ Run 'bd daemon --help' to see all subcommands.`,
 		federation, _ := cmd.Flags().GetBool("federation")
 		federationPort, _ := cmd.Flags().GetInt("federation-port")
 		remotesapiPort, _ := cmd.Flags().GetInt("remotesapi-port")
-		startDaemon(interval, autoCommit, autoPush, autoPull, localMode, foreground, logFile, pidFile, logLevel, logJSON, federation, federationPort, remotesapiPort)
+		metricsAddr, _ := cmd.Flags().GetString("metrics-addr")
+		startDaemon(interval, autoCommit, autoPush, autoPull, localMode, foreground, logFile, pidFile, logLevel, logJSON, federation, federationPort, remotesapiPort, metricsAddr)
 	},
 }
 
@@ -271,6 +272,7 @@ func init() {
 	daemonCmd.Flags().Bool("federation", false, "Enable federation mode (runs dolt sql-server with remotesapi)")
 	daemonCmd.Flags().Int("federation-port", 3306, "MySQL port for federation mode dolt sql-server")
 	daemonCmd.Flags().Int("remotesapi-port", 8080, "remotesapi port for peer-to-peer sync in federation mode")
+	daemonCmd.Flags().String("metrics-addr", "", "Serve OpenMetrics at /metrics on this address (e.g. 127.0.0.1:9464; env: BEADS_DAEMON_METRICS_ADDR)")
 	daemonCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output JSON format")
 	rootCmd.AddCommand(daemonCmd)
 }
@@ -287,7 +289,7 @@ func computeDaemonParentPID() int {
 	}
 	return os.Getppid()
 }
-func runDaemonLoop(interval time.Duration, autoCommit, autoPush, autoPull, localMode bool, logPath, pidFile, logLevel string, logJSON, federation bool, federationPort, remotesapiPort int) {
+func runDaemonLoop(interval time.Duration, autoCommit, autoPush, autoPull, localMode bool, logPath, pidFile, logLevel string, logJSON, federation bool, federationPort, remotesapiPort int, metricsAddr string) {
 	level := parseLogLevel(logLevel)
 	logF, log := setupDaemonLogger(logPath, logJSON, level)
 	defer func() { _ = logF.Close() }()
@@ -593,6 +595,19 @@ func runDaemonLoop(interval time.Duration, autoCommit, autoPush, autoPull, local
 	// Set daemon configuration for status reporting
 	server.SetConfig(autoCommit, autoPush, autoPull, localMode, interval.String(), daemonMode)
 
+	// Optional OpenMetrics endpoint for scraping (stopped by server.Stop)
+	if metricsAddr == "" {
+		metricsAddr = os.Getenv("BEADS_DAEMON_METRICS_ADDR")
+	}
+	if metricsAddr != "" {
+		addr, err := server.StartMetricsServer(metricsAddr)
+		if err != nil {
+			log.Warn("metrics endpoint disabled", "error", err)
+		} else {
+			log.Info("serving metrics", "url", "http://"+addr.String()+"/metrics")
+		}
+	}
+
 	// Register daemon in global registry
 	registry, err := daemon.NewRegistry()
 	if err != nil {
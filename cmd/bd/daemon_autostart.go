@@ -570,7 +570,25 @@ func getSocketPath() string {
 	// Get workspace path (parent of .beads directory)
 	beadsDir := filepath.Dir(dbPath)
 	workspacePath := filepath.Dir(beadsDir)
-	return rpc.ShortSocketPath(workspacePath)
+	socketPath := rpc.ShortSocketPath(workspacePath)
+
+	// A workspace without a daemon of its own is served by the multiplex
+	// daemon when one is running (bd daemon mux start)
+	if _, err := os.Stat(socketPath); os.IsNotExist(err) {
+		if muxSocket := rpc.MuxSocketPath(); muxSocket != "" {
+			if _, err := os.Stat(muxSocket); err == nil {
+				return muxSocket
+			}
+		}
+	}
+	return socketPath
+}
+
+// isMuxSocket reports whether socketPath is the multiplex daemon's socket.
+// The multiplex daemon is started explicitly, so it is never auto-started
+// or restarted on behalf of one workspace.
+func isMuxSocket(socketPath string) bool {
+	return socketPath != "" && socketPath == rpc.MuxSocketPath()
 }
 
 // emitVerboseWarning prints a one-line warning when falling back to direct mode
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"time"
 
@@ -23,6 +24,8 @@ const DefaultRemoteSyncInterval = 30 * time.Second
 // - Git operations (via hooks, optional)
 // - Parent process monitoring (exit if parent dies)
 // - Periodic remote sync (to pull updates from other clones)
+// - Gate evaluation (see daemonGates)
+// - SLA evaluation (see daemonSLA)
 //
 // The remoteSyncInterval parameter controls how often the daemon pulls from
 // remote to check for updates from other clones. Use DefaultRemoteSyncInterval
@@ -73,6 +76,13 @@ func runEventDrivenLoop(
 		defer func() { _ = watcher.Close() }()
 	}
 
+	// Evaluate open gates in the background; closes wake waiting gates
+	gates := newDaemonGates(server, store, filepath.Dir(filepath.Dir(jsonlPath)), config.Current(), log)
+	go gates.run(ctx)
+
+	// Act on SLA breaches (no-op without sla.policies)
+	go newDaemonSLA(server, store, filepath.Dir(filepath.Dir(jsonlPath)), config.Current(), log).run(ctx)
+
 	// Handle mutation events from RPC server
 	mutationChan := server.MutationChan()
 	go func() {
@@ -86,6 +96,7 @@ func runEventDrivenLoop(
 				}
 				log.log("Mutation detected: %s %s", event.Type, event.IssueID)
 				exportDebouncer.Trigger()
+				gates.observe(event)
 
 			case <-ctx.Done():
 				return
@@ -137,6 +148,13 @@ func runEventDrivenLoop(
 		case <-healthTicker.C:
 			// Periodic health validation (not sync)
 			checkDaemonHealth(ctx, store, log)
+			// Return issues with lapsed claim leases to the ready queue
+			if reopened, err := store.ExpireLeases(ctx, "daemon"); err != nil {
+				log.log("Lease expiry failed: %v", err)
+			} else if len(reopened) > 0 {
+				log.log("Expired %d claim lease(s), reopened: %v", len(reopened), reopened)
+				exportDebouncer.Trigger()
+			}
 
 		case <-func() <-chan time.Time {
 			if remoteSyncTicker != nil {
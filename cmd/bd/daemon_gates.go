@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/gateeval"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// gateTickInterval is how often the daemon looks for gates whose next check
+// is due. Each gate's own schedule comes from gateeval.BackoffFor.
+const gateTickInterval = 5 * time.Second
+
+// daemonGates evaluates open gates from the daemon so satisfied gates close
+// without anyone running bd gate check. Gates are polled with per-type
+// backoff; bead and formula-condition gates are also re-checked as soon as
+// an issue closes. Resolved gates are closed and their waiters woken,
+// failed or timed-out gates are escalated once, and every transition is
+// emitted as a gate mutation so it shows up in bd activity.
+type daemonGates struct {
+	store    storage.Storage
+	registry *gateeval.Registry
+	sched    *gateeval.Scheduler
+	emit     func(rpc.MutationEvent)
+	escalate func(ctx context.Context, gate *types.Issue, reason string) error
+	notify   func(ctx context.Context, gate *types.Issue, reason string) error
+	log      daemonLogger
+	wake     chan struct{}
+}
+
+// newDaemonGates returns gate evaluation for the workspace at workspacePath,
+// or nil unless daemon.gate-check is enabled. Settings come from settings,
+// the workspace's configuration.
+//
+// Gates arrive through sync from other clones, and nobody watches the daemon
+// act on them, so it only runs cmd gates listed in gate.cmd.allow and only
+// fetches http gate URLs matching gate.http.allow.
+func newDaemonGates(server *rpc.Server, store storage.Storage, workspacePath string, settings *config.Settings, log daemonLogger) *daemonGates {
+	if !settings.GetBool("daemon.gate-check") {
+		log.Info("daemon gate evaluation disabled (enable with daemon.gate-check: true)")
+		return nil
+	}
+	deps := gateeval.Deps{
+		WorkDir:      workspacePath,
+		CommandAllow: settings.GetStringSlice("gate.cmd.allow"),
+		RestrictHTTP: true,
+		HTTPAllow:    settings.GetStringSlice("gate.http.allow"),
+	}
+	escalateTargets := settings.GetStringSlice("gate.escalate")
+	notifyTargets := settings.GetStringSlice("gate.notify")
+	return &daemonGates{
+		store: store,
+		registry: newGateRegistryFor(deps, func() (storage.Storage, error) {
+			return store, nil
+		}),
+		sched: gateeval.NewScheduler(),
+		emit:  server.EmitMutation,
+		escalate: func(ctx context.Context, gate *types.Issue, reason string) error {
+			return sendGateEscalationTo(ctx, escalateTargets, gate, reason)
+		},
+		notify: func(ctx context.Context, gate *types.Issue, reason string) error {
+			return notifyGateWaitersVia(ctx, notifyTargets, gate, reason)
+		},
+		log:  log,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// observe reacts to a mutation: closing an issue wakes the gates waiting on
+// it. Safe to call from the mutation listener; the check itself runs on the
+// gate loop.
+func (g *daemonGates) observe(event rpc.MutationEvent) {
+	if g == nil || event.Type != rpc.MutationStatus || event.NewStatus != string(types.StatusClosed) {
+		return
+	}
+	if g.sched.WakeClosed(event.IssueID) > 0 {
+		select {
+		case g.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run checks due gates every gateTickInterval, and immediately when
+// observe wakes a gate, until ctx is done.
+func (g *daemonGates) run(ctx context.Context) {
+	if g == nil {
+		return
+	}
+	ticker := time.NewTicker(gateTickInterval)
+	defer ticker.Stop()
+
+	g.check(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.check(ctx)
+		case <-g.wake:
+			g.check(ctx)
+		}
+	}
+}
+
+// check evaluates every open gate that is due and acts on the outcome.
+func (g *daemonGates) check(ctx context.Context) {
+	gateType := types.TypeGate
+	gates, err := g.store.SearchIssues(ctx, "", types.IssueFilter{
+		IssueType:     &gateType,
+		ExcludeStatus: []types.Status{types.StatusClosed},
+	})
+	if err != nil {
+		g.log.Warn("gate check: listing gates failed", "error", err)
+		return
+	}
+
+	for _, gate := range g.sched.Due(gates) {
+		if ctx.Err() != nil {
+			return
+		}
+		res, ok, evalErr := g.registry.Evaluate(ctx, gate)
+		if !ok {
+			// Human gates and unknown types are resolved by hand
+			g.sched.Record(gate, gateeval.Result{}, nil)
+			continue
+		}
+		if evalErr != nil {
+			g.log.Debug("gate check failed", "gate", gate.ID, "type", gate.AwaitType, "error", evalErr)
+		}
+		if res.AwaitID != "" && res.AwaitID != gate.AwaitID {
+			if err := g.store.UpdateIssue(ctx, gate.ID, map[string]interface{}{"await_id": res.AwaitID}, "daemon"); err != nil {
+				g.log.Warn("gate check: updating await_id failed", "gate", gate.ID, "error", err)
+			}
+		}
+
+		switch g.sched.Record(gate, res, evalErr) {
+		case gateeval.TransitionResolved:
+			g.resolve(ctx, gate, res.Reason)
+		case gateeval.TransitionEscalated:
+			g.escalateGate(ctx, gate, gateeval.TransitionEscalated, res.Reason)
+		case gateeval.TransitionTimedOut:
+			reason := fmt.Sprintf("timed out after %s", gate.Timeout)
+			if res.Reason != "" {
+				reason += " (" + res.Reason + ")"
+			}
+			g.escalateGate(ctx, gate, gateeval.TransitionTimedOut, reason)
+		}
+	}
+}
+
+func (g *daemonGates) resolve(ctx context.Context, gate *types.Issue, reason string) {
+	if err := g.store.CloseIssue(ctx, gate.ID, reason, "daemon", ""); err != nil {
+		g.log.Warn("gate check: closing gate failed", "gate", gate.ID, "error", err)
+		g.sched.Wake(gate.ID)
+		return
+	}
+	g.log.Info("gate resolved", "gate", gate.ID, "type", gate.AwaitType, "reason", reason)
+	g.emitTransition(gate, gateeval.TransitionResolved)
+
+	if err := g.notify(ctx, gate, reason); err != nil {
+		g.log.Warn("gate check: waking waiters failed", "gate", gate.ID, "error", err)
+	}
+	// The closed gate may itself be awaited by another gate
+	g.sched.WakeClosed(gate.ID)
+}
+
+func (g *daemonGates) escalateGate(ctx context.Context, gate *types.Issue, transition gateeval.Transition, reason string) {
+	g.log.Warn("gate escalated", "gate", gate.ID, "type", gate.AwaitType, "reason", reason)
+	// Record the escalation in the gate's audit trail
+	if err := g.store.AddComment(ctx, gate.ID, "daemon", "Gate escalated: "+reason); err != nil {
+		g.log.Warn("gate check: recording escalation failed", "gate", gate.ID, "error", err)
+	}
+	g.emitTransition(gate, transition)
+
+	if err := g.escalate(ctx, gate, reason); err != nil {
+		g.log.Warn("gate check: escalation failed", "gate", gate.ID, "error", err)
+	}
+}
+
+func (g *daemonGates) emitTransition(gate *types.Issue, transition gateeval.Transition) {
+	g.emit(rpc.MutationEvent{
+		Type:      rpc.MutationGate,
+		IssueID:   gate.ID,
+		Title:     gate.Title,
+		Actor:     "daemon",
+		OldStatus: string(gate.Status),
+		NewStatus: string(transition),
+	})
+}
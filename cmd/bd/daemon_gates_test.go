@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/gateeval"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestDaemonGates(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := newTestStore(t, filepath.Join(dir, ".beads", "beads.db"))
+
+	target := &types.Issue{Title: "Build", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	fileGate := &types.Issue{Title: "Wait for artifact", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeGate,
+		AwaitType: "file", AwaitID: "artifact.tar", Waiters: []string{"rig/polecats/a"}}
+	slowGate := &types.Issue{Title: "Wait for deploy", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeGate,
+		AwaitType: "file", AwaitID: "deployed", Timeout: time.Minute}
+	for _, issue := range []*types.Issue{target, fileGate, slowGate} {
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	beadGate := &types.Issue{Title: "Wait for build", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeGate,
+		AwaitType: "bead", AwaitID: "local:" + target.ID}
+	if err := s.CreateIssue(ctx, beadGate, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	sched := gateeval.NewScheduler()
+	sched.Now = func() time.Time { return now }
+
+	var events []rpc.MutationEvent
+	var woken, escalated []string
+	g := &daemonGates{
+		store: s,
+		registry: gateeval.NewDefaultRegistry(gateeval.Deps{
+			WorkDir: dir,
+			CheckBead: func(ctx context.Context, awaitID string) (bool, string) {
+				_, id, _ := strings.Cut(awaitID, ":")
+				issue, err := s.GetIssue(ctx, id)
+				if err != nil || issue == nil {
+					return false, "not found"
+				}
+				return issue.Status == types.StatusClosed, string(issue.Status)
+			},
+		}),
+		sched: sched,
+		emit:  func(e rpc.MutationEvent) { events = append(events, e) },
+		escalate: func(_ context.Context, gate *types.Issue, reason string) error {
+			escalated = append(escalated, gate.ID+": "+reason)
+			return nil
+		},
+		notify: func(_ context.Context, gate *types.Issue, _ string) error {
+			woken = append(woken, gate.Waiters...)
+			return nil
+		},
+		log:  daemonLogger{logger: slog.New(slog.NewTextHandler(io.Discard, nil))},
+		wake: make(chan struct{}, 1),
+	}
+
+	// Nothing is satisfied yet
+	g.check(ctx)
+	if len(events) != 0 {
+		t.Fatalf("unexpected events before any gate is satisfied: %+v", events)
+	}
+
+	// The artifact appears: the file gate resolves at its next scheduled check
+	if err := os.WriteFile(filepath.Join(dir, "artifact.tar"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	g.check(ctx)
+	if len(events) != 0 {
+		t.Fatalf("file gate checked again before its backoff elapsed: %+v", events)
+	}
+	now = now.Add(gateeval.BackoffFor("file").Initial)
+	g.check(ctx)
+	assertGateEvent(t, events, fileGate.ID, "resolved")
+	if got, _ := s.GetIssue(ctx, fileGate.ID); got.Status != types.StatusClosed {
+		t.Errorf("file gate status = %s, want closed", got.Status)
+	}
+	if len(woken) != 1 || woken[0] != "rig/polecats/a" {
+		t.Errorf("woken = %v, want the file gate's waiter", woken)
+	}
+
+	// Closing the target wakes the bead gate without waiting for its backoff
+	if err := s.CloseIssue(ctx, target.ID, "done", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+	g.observe(rpc.MutationEvent{Type: rpc.MutationStatus, IssueID: target.ID, NewStatus: "closed"})
+	select {
+	case <-g.wake:
+	default:
+		t.Error("closing the target should wake the gate loop")
+	}
+	g.check(ctx)
+	assertGateEvent(t, events, beadGate.ID, "resolved")
+
+	// The slow gate passes its timeout and is escalated once
+	now = now.Add(2 * time.Minute)
+	g.check(ctx)
+	now = now.Add(10 * time.Minute)
+	g.check(ctx)
+	assertGateEvent(t, events, slowGate.ID, "timed_out")
+	if len(escalated) != 1 || !strings.HasPrefix(escalated[0], slowGate.ID+": timed out after 1m0s") {
+		t.Errorf("escalated = %q, want one timeout escalation for %s", escalated, slowGate.ID)
+	}
+	gateEvents, err := s.GetEvents(ctx, slowGate.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := false
+	for _, e := range gateEvents {
+		if e.EventType == types.EventCommented && e.Comment != nil && strings.HasPrefix(*e.Comment, "Gate escalated: timed out") {
+			recorded = true
+		}
+	}
+	if !recorded {
+		t.Error("escalation should be recorded on the gate")
+	}
+}
+
+func assertGateEvent(t *testing.T, events []rpc.MutationEvent, gateID, transition string) {
+	t.Helper()
+	for _, e := range events {
+		if e.Type == rpc.MutationGate && e.IssueID == gateID && e.NewStatus == transition {
+			return
+		}
+	}
+	t.Errorf("no %s gate event for %s in %+v", transition, gateID, events)
+}
+
+// The multiplex daemon's global config belongs to no workspace, so gate and
+// SLA settings must come from the workspace's own config.yaml.
+func TestDaemonGatesUseWorkspaceSettings(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	s := newTestStore(t, filepath.Join(beadsDir, "beads.db"))
+	yaml := `daemon:
+  gate-check: true
+gate:
+  escalate: []
+sla:
+  policies:
+    - type: bug
+      resolve: 24h
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "config.yaml"), []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := config.LoadWorkspace(beadsDir)
+	if err != nil {
+		t.Fatalf("LoadWorkspace failed: %v", err)
+	}
+
+	server := rpc.NewServer(filepath.Join(dir, "bd.sock"), s, dir, filepath.Join(beadsDir, "beads.db"))
+	log := daemonLogger{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	if newDaemonGates(server, s, dir, settings, log) == nil {
+		t.Error("daemon.gate-check in the workspace config.yaml was ignored")
+	}
+	d := newDaemonSLA(server, s, dir, settings, log)
+	if d == nil || len(d.policies) != 1 {
+		t.Errorf("sla.policies in the workspace config.yaml were ignored: %+v", d)
+	}
+
+	// Without the workspace settings, neither is enabled
+	empty, err := config.LoadWorkspace(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newDaemonGates(server, s, dir, empty, log) != nil {
+		t.Error("expected gate evaluation disabled by default")
+	}
+	if newDaemonSLA(server, s, dir, empty, log) != nil {
+		t.Error("expected no SLA evaluation without policies")
+	}
+}
@@ -264,6 +264,25 @@ func stopDaemon(pidFile string) {
 		return
 	}
 
+	// A workspace held by the multiplex daemon is released, not killed: the
+	// same process serves other workspaces
+	if info, err := readDaemonLockInfo(filepath.Dir(pidFile)); err == nil && info.Multiplex {
+		fmt.Printf("Releasing workspace from multiplex daemon (PID %d)...\n", pid)
+		if err := releaseMuxWorkspace(info.Database); err != nil {
+			fmt.Fprintf(os.Stderr, "Error releasing workspace: %v\n", err)
+			os.Exit(1)
+		}
+		for i := 0; i < daemonShutdownAttempts; i++ {
+			time.Sleep(daemonShutdownPollInterval)
+			if isRunning, _ := isDaemonRunning(pidFile); !isRunning {
+				fmt.Println("Workspace released")
+				return
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Error: multiplex daemon did not release the workspace after %v\n", daemonShutdownTimeout)
+		os.Exit(1)
+	}
+
 	fmt.Printf("Stopping daemon (PID %d)...\n", pid)
 
 	process, err := os.FindProcess(pid)
@@ -369,7 +388,7 @@ func stopAllDaemons() {
 }
 
 // startDaemon starts the daemon (in foreground if requested, otherwise background)
-func startDaemon(interval time.Duration, autoCommit, autoPush, autoPull, localMode, foreground bool, logFile, pidFile, logLevel string, logJSON, federation bool, federationPort, remotesapiPort int) {
+func startDaemon(interval time.Duration, autoCommit, autoPush, autoPull, localMode, foreground bool, logFile, pidFile, logLevel string, logJSON, federation bool, federationPort, remotesapiPort int, metricsAddr string) {
 	logPath, err := getLogFilePath(logFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -386,7 +405,7 @@ func startDaemon(interval time.Duration, autoCommit, autoPush, autoPull, localMo
 
 	// Run in foreground if --foreground flag set or if we're the forked child process
 	if foreground || os.Getenv("BD_DAEMON_FOREGROUND") == "1" {
-		runDaemonLoop(interval, autoCommit, autoPush, autoPull, localMode, logPath, pidFile, logLevel, logJSON, federation, federationPort, remotesapiPort)
+		runDaemonLoop(interval, autoCommit, autoPush, autoPull, localMode, logPath, pidFile, logLevel, logJSON, federation, federationPort, remotesapiPort, metricsAddr)
 		return
 	}
 
@@ -429,6 +448,9 @@ func startDaemon(interval time.Duration, autoCommit, autoPush, autoPull, localMo
 			args = append(args, "--remotesapi-port", strconv.Itoa(remotesapiPort))
 		}
 	}
+	if metricsAddr != "" {
+		args = append(args, "--metrics-addr", metricsAddr)
+	}
 
 	cmd := exec.Command(exe, args...) // #nosec G204 - bd daemon command from trusted binary
 	cmd.Env = append(os.Environ(), "BD_DAEMON_FOREGROUND=1")
@@ -20,6 +20,9 @@ type DaemonLockInfo struct {
 	Database   string    `json:"database"`
 	Version    string    `json:"version"`
 	StartedAt  time.Time `json:"started_at"`
+	// Multiplex is set when the workspace is held open by the multiplex
+	// daemon (bd daemon mux) rather than a daemon of its own
+	Multiplex bool `json:"multiplex,omitempty"`
 }
 
 // DaemonLock represents a held lock on the daemon.lock file
@@ -43,6 +46,28 @@ func (l *DaemonLock) Close() error {
 // Returns ErrDaemonLocked if another daemon is already running
 // dbPath is the full path to the database file (e.g., /path/to/.beads/beads.db)
 func acquireDaemonLock(beadsDir string, dbPath string) (*DaemonLock, error) {
+	lock, err := acquireDaemonLockInfo(beadsDir, DaemonLockInfo{
+		PID:       os.Getpid(),
+		ParentPID: os.Getppid(),
+		Database:  dbPath,
+		Version:   Version,
+		StartedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Also write PID file for Windows compatibility (can't read locked files on Windows)
+	pidFile := filepath.Join(beadsDir, "daemon.pid")
+	_ = os.WriteFile(pidFile, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0600) // Best-effort PID write
+
+	return lock, nil
+}
+
+// acquireDaemonLockInfo acquires daemon.lock in beadsDir and records lockInfo
+// in it. Unlike acquireDaemonLock it writes no PID file, so the multiplex
+// daemon can hold a workspace's lock without looking like its own daemon.
+func acquireDaemonLockInfo(beadsDir string, lockInfo DaemonLockInfo) (*DaemonLock, error) {
 	lockPath := filepath.Join(beadsDir, "daemon.lock")
 
 	// Open or create the lock file
@@ -62,14 +87,6 @@ func acquireDaemonLock(beadsDir string, dbPath string) (*DaemonLock, error) {
 	}
 
 	// Write JSON metadata to the lock file
-	lockInfo := DaemonLockInfo{
-		PID:       os.Getpid(),
-		ParentPID: os.Getppid(),
-		Database:  dbPath,
-		Version:   Version,
-		StartedAt: time.Now().UTC(),
-	}
-	
 	_ = f.Truncate(0)              // Clear file for fresh write (we hold lock)
 	_, _ = f.Seek(0, 0)
 	encoder := json.NewEncoder(f)
@@ -77,10 +94,6 @@ func acquireDaemonLock(beadsDir string, dbPath string) (*DaemonLock, error) {
 	_ = encoder.Encode(lockInfo)   // Write can't fail if Truncate succeeded
 	_ = f.Sync()                   // Best-effort sync to disk
 
-	// Also write PID file for Windows compatibility (can't read locked files on Windows)
-	pidFile := filepath.Join(beadsDir, "daemon.pid")
-	_ = os.WriteFile(pidFile, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0600) // Best-effort PID write
-
 	return &DaemonLock{file: f, path: lockPath}, nil
 }
 
@@ -0,0 +1,691 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/configfile"
+	"github.com/steveyegge/beads/internal/daemon"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/factory"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/syncbranch"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+var daemonMuxCmd = &cobra.Command{
+	Use:   "mux",
+	Short: "Manage the multiplex daemon (one daemon for many workspaces)",
+	Long: `Manage the multiplex daemon: a single background process that serves every
+workspace on this machine over one shared socket (~/.beads/mux/bd.sock).
+
+Instead of one daemon per workspace, the multiplex daemon opens a workspace's
+database on its first request and closes it again after it has been idle for
+--idle-timeout. Workspaces that have a daemon of their own keep using it;
+all others are routed to the multiplex daemon while it runs.
+
+Each open workspace gets the same background sync as a per-workspace daemon:
+mutations are exported to JSONL, JSONL changes (e.g. after git pull) are
+imported, and with --auto-commit/--auto-push/--auto-pull changes are
+committed, pushed and pulled via 'bd sync'.
+
+'bd daemon stop <workspace>' releases one workspace;
+'bd daemon mux stop' stops the multiplex daemon.
+
+Examples:
+  bd daemon mux start                      # Start in the background
+  bd daemon mux start --idle-timeout 10m   # Close workspaces idle for 10 minutes
+  bd daemon mux start --foreground         # Run in foreground (for systemd/launchd)
+  bd daemon mux status                     # Show open workspaces
+  bd daemon mux stop                       # Stop the multiplex daemon`,
+}
+
+var daemonMuxStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the multiplex daemon",
+	Run: func(cmd *cobra.Command, args []string) {
+		foreground, _ := cmd.Flags().GetBool("foreground")
+		logFile, _ := cmd.Flags().GetString("log")
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		settings := muxSettings{}
+		settings.idleTimeout, _ = cmd.Flags().GetDuration("idle-timeout")
+		settings.interval, _ = cmd.Flags().GetDuration("interval")
+		settings.autoCommit, _ = cmd.Flags().GetBool("auto-commit")
+		settings.autoPush, _ = cmd.Flags().GetBool("auto-push")
+		settings.autoPull, _ = cmd.Flags().GetBool("auto-pull")
+		settings.pullInterval, _ = cmd.Flags().GetDuration("pull-interval")
+
+		if !cmd.Flags().Changed("idle-timeout") {
+			if env := os.Getenv("BEADS_MUX_IDLE_TIMEOUT"); env != "" {
+				d, err := time.ParseDuration(env)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: invalid BEADS_MUX_IDLE_TIMEOUT %q: %v\n", env, err)
+					os.Exit(1)
+				}
+				settings.idleTimeout = d
+			}
+		}
+		if settings.idleTimeout <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: idle timeout must be positive (got %v)\n", settings.idleTimeout)
+			os.Exit(1)
+		}
+		if settings.interval <= 0 || settings.pullInterval <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: intervals must be positive (got --interval %v, --pull-interval %v)\n", settings.interval, settings.pullInterval)
+			os.Exit(1)
+		}
+		// Pushing implies committing, as for the per-workspace daemon
+		if settings.autoPush {
+			settings.autoCommit = true
+		}
+
+		socketPath := rpc.MuxSocketPath()
+		if socketPath == "" {
+			fmt.Fprintf(os.Stderr, "Error: cannot determine multiplex daemon socket (set BEADS_MUX_SOCKET)\n")
+			os.Exit(1)
+		}
+		muxDir := filepath.Dir(socketPath)
+
+		if logFile == "" {
+			logFile = filepath.Join(muxDir, "daemon.log")
+		}
+
+		if foreground || os.Getenv("BD_DAEMON_FOREGROUND") == "1" {
+			runMuxDaemon(socketPath, settings, logFile, logLevel)
+			return
+		}
+
+		if running, pid := tryDaemonLock(muxDir); running {
+			fmt.Fprintf(os.Stderr, "Error: multiplex daemon already running (PID %d)\n", pid)
+			fmt.Fprintf(os.Stderr, "Use 'bd daemon mux stop' to stop it first\n")
+			os.Exit(1)
+		}
+
+		startMuxDaemonProcess(muxDir, settings, logFile, logLevel)
+	},
+}
+
+var daemonMuxStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the multiplex daemon",
+	Long: `Stop the multiplex daemon, closing every workspace it serves.
+
+To release a single workspace and leave the multiplex daemon running, use
+'bd daemon stop <workspace>'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		socketPath := rpc.MuxSocketPath()
+		muxDir := filepath.Dir(socketPath)
+
+		running, pid := tryDaemonLock(muxDir)
+		if !running {
+			fmt.Println("Multiplex daemon is not running")
+			return
+		}
+
+		fmt.Printf("Stopping multiplex daemon (PID %d)...\n", pid)
+
+		// Prefer a graceful RPC shutdown so open workspaces are flushed and
+		// released; fall back to a stop signal.
+		stopped := false
+		if client, err := rpc.TryConnectWithTimeout(socketPath, time.Second); err == nil && client != nil {
+			stopped = client.Shutdown() == nil
+			_ = client.Close()
+		}
+		if !stopped {
+			process, err := os.FindProcess(pid)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error finding process: %v\n", err)
+				os.Exit(1)
+			}
+			if err := sendStopSignal(process); err != nil {
+				fmt.Fprintf(os.Stderr, "Error signaling multiplex daemon: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		for i := 0; i < daemonShutdownAttempts; i++ {
+			time.Sleep(daemonShutdownPollInterval)
+			if running, _ := tryDaemonLock(muxDir); !running {
+				fmt.Println("Multiplex daemon stopped")
+				return
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Error: multiplex daemon did not stop after %v\n", daemonShutdownTimeout)
+		os.Exit(1)
+	},
+}
+
+var daemonMuxStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show multiplex daemon status and open workspaces",
+	Run: func(cmd *cobra.Command, args []string) {
+		socketPath := rpc.MuxSocketPath()
+
+		client, err := rpc.TryConnectWithTimeout(socketPath, time.Second)
+		if err != nil || client == nil {
+			if jsonOutput {
+				outputJSON(map[string]interface{}{"running": false})
+			} else {
+				fmt.Println("Multiplex daemon is not running")
+			}
+			return
+		}
+		defer func() { _ = client.Close() }()
+
+		status, err := client.Status()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"running":        true,
+				"pid":            status.PID,
+				"version":        status.Version,
+				"socket_path":    status.SocketPath,
+				"uptime_seconds": status.UptimeSeconds,
+				"workspaces":     status.Workspaces,
+			})
+			return
+		}
+
+		fmt.Printf("Multiplex daemon running (PID %d, version %s)\n", status.PID, status.Version)
+		fmt.Printf("  Socket: %s\n", status.SocketPath)
+		fmt.Printf("  Uptime: %s\n", formatUptime(status.UptimeSeconds))
+		if len(status.Workspaces) == 0 {
+			fmt.Println("  No open workspaces")
+			return
+		}
+		fmt.Printf("  Open workspaces (%d):\n", len(status.Workspaces))
+		for _, dbPath := range status.Workspaces {
+			fmt.Printf("    %s\n", filepath.Dir(filepath.Dir(dbPath)))
+		}
+	},
+}
+
+func init() {
+	daemonMuxStartCmd.Flags().Bool("foreground", false, "Run in foreground (don't daemonize)")
+	daemonMuxStartCmd.Flags().Duration("idle-timeout", rpc.DefaultMuxIdleTimeout, "Close workspaces idle for this long (env: BEADS_MUX_IDLE_TIMEOUT)")
+	daemonMuxStartCmd.Flags().Duration("interval", 5*time.Second, "How often to check each workspace's JSONL for changes")
+	daemonMuxStartCmd.Flags().Bool("auto-commit", false, "Commit exported changes in each workspace")
+	daemonMuxStartCmd.Flags().Bool("auto-push", false, "Push committed changes (implies --auto-commit)")
+	daemonMuxStartCmd.Flags().Bool("auto-pull", false, "Periodically pull and import remote changes")
+	daemonMuxStartCmd.Flags().Duration("pull-interval", time.Minute, "How often to pull with --auto-pull")
+	daemonMuxStartCmd.Flags().String("log", "", "Log file path (default: ~/.beads/mux/daemon.log)")
+	daemonMuxStartCmd.Flags().String("log-level", "info", "Log level (debug, info, warn, error)")
+	daemonMuxStatusCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output JSON format")
+
+	daemonMuxCmd.AddCommand(daemonMuxStartCmd)
+	daemonMuxCmd.AddCommand(daemonMuxStopCmd)
+	daemonMuxCmd.AddCommand(daemonMuxStatusCmd)
+	daemonCmd.AddCommand(daemonMuxCmd)
+}
+
+// muxSettings are the sync settings the multiplex daemon applies to every
+// workspace it serves.
+type muxSettings struct {
+	idleTimeout  time.Duration
+	interval     time.Duration
+	pullInterval time.Duration
+	autoCommit   bool
+	autoPush     bool
+	autoPull     bool
+}
+
+// startMuxDaemonProcess re-executes bd as a background multiplex daemon and
+// waits for it to write its PID file.
+func startMuxDaemonProcess(muxDir string, settings muxSettings, logFile, logLevel string) {
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: cannot resolve executable path: %v\n", err)
+		os.Exit(1)
+	}
+
+	args := []string{"daemon", "mux", "start",
+		"--idle-timeout", settings.idleTimeout.String(),
+		"--interval", settings.interval.String(),
+		"--log", logFile,
+	}
+	if settings.autoCommit {
+		args = append(args, "--auto-commit")
+	}
+	if settings.autoPush {
+		args = append(args, "--auto-push")
+	}
+	if settings.autoPull {
+		args = append(args, "--auto-pull", "--pull-interval", settings.pullInterval.String())
+	}
+	if logLevel != "" && logLevel != "info" {
+		args = append(args, "--log-level", logLevel)
+	}
+
+	cmd := exec.Command(exe, args...) // #nosec G204 - bd daemon command from trusted binary
+	cmd.Env = append(os.Environ(), "BD_DAEMON_FOREGROUND=1")
+	configureDaemonProcess(cmd)
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening /dev/null: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() { _ = devNull.Close() }()
+	cmd.Stdin = devNull
+	cmd.Stdout = devNull
+	cmd.Stderr = devNull
+
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting multiplex daemon: %v\n", err)
+		os.Exit(1)
+	}
+	expectedPID := cmd.Process.Pid
+	if err := cmd.Process.Release(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to release process: %v\n", err)
+	}
+
+	pidFile := filepath.Join(muxDir, "daemon.pid")
+	for i := 0; i < 20; i++ {
+		time.Sleep(100 * time.Millisecond)
+		// #nosec G304 - controlled path from config
+		if data, err := os.ReadFile(pidFile); err == nil {
+			if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid == expectedPID {
+				fmt.Printf("Multiplex daemon started (PID %d)\n", expectedPID)
+				return
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: multiplex daemon may have failed to start (PID file not confirmed)\n")
+	fmt.Fprintf(os.Stderr, "Check log file: %s\n", logFile)
+}
+
+// runMuxDaemon runs the multiplex daemon in the current process until it is
+// signaled or shut down over RPC.
+func runMuxDaemon(socketPath string, settings muxSettings, logPath, logLevel string) {
+	muxDir := filepath.Dir(socketPath)
+	if err := os.MkdirAll(muxDir, 0700); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: cannot create %s: %v\n", muxDir, err)
+		os.Exit(1)
+	}
+
+	logF, log := setupDaemonLogger(logPath, false, parseLogLevel(logLevel))
+	defer func() { _ = logF.Close() }()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	lock, err := acquireDaemonLock(muxDir, "")
+	if err != nil {
+		if err == ErrDaemonLocked {
+			log.Info("multiplex daemon already running (lock held), exiting")
+		} else {
+			log.Error("acquiring multiplex daemon lock", "error", err)
+		}
+		return
+	}
+	defer func() { _ = lock.Close() }()
+	defer func() { _ = os.Remove(filepath.Join(muxDir, "daemon.pid")) }()
+
+	registry, err := daemon.NewRegistry()
+	if err != nil {
+		log.Warn("failed to create registry", "error", err)
+		registry = nil
+	}
+
+	pools := &muxWorkspaces{socketPath: socketPath, settings: settings, registry: registry, log: log}
+	server := rpc.NewMuxServer(socketPath, pools.open, pools.run, settings.idleTimeout)
+	server.SetConfig(settings.autoCommit, settings.autoPush, settings.autoPull, false, settings.interval.String(), rpc.DaemonModeMultiplex)
+
+	serverErrChan := make(chan error, 1)
+	go func() { serverErrChan <- server.Start(ctx) }()
+	select {
+	case <-server.WaitReady():
+	case err := <-serverErrChan:
+		log.Error("multiplex daemon failed to start", "error", err)
+		return
+	case <-time.After(5 * time.Second):
+		log.Error("multiplex daemon did not become ready")
+		_ = server.Stop()
+		return
+	}
+	log.Info("multiplex daemon started", "socket", socketPath, "idle_timeout", settings.idleTimeout,
+		"auto_commit", settings.autoCommit, "auto_push", settings.autoPush, "auto_pull", settings.autoPull)
+
+	if registry != nil {
+		entry := daemon.RegistryEntry{
+			SocketPath: socketPath,
+			PID:        os.Getpid(),
+			Version:    Version,
+			StartedAt:  time.Now(),
+			Mux:        true,
+		}
+		if err := registry.Register(entry); err != nil {
+			log.Warn("failed to register multiplex daemon", "error", err)
+		}
+		// Unregister removes the daemon and every workspace it still holds
+		defer func() {
+			if err := registry.Unregister("", os.Getpid()); err != nil {
+				log.Warn("failed to unregister multiplex daemon", "error", err)
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		log.Info("received signal, shutting down multiplex daemon")
+		_ = server.Stop()
+		<-serverErrChan
+	case err := <-serverErrChan:
+		if err != nil {
+			log.Error("multiplex daemon server error", "error", err)
+		}
+	}
+	log.Info("multiplex daemon stopped")
+}
+
+// muxWorkspaces opens and runs the workspaces of a multiplex daemon.
+type muxWorkspaces struct {
+	socketPath string
+	settings   muxSettings
+	registry   *daemon.Registry
+	log        daemonLogger
+}
+
+// open takes the workspace's daemon lock (so no per-workspace daemon starts
+// alongside the multiplex daemon), opens its storage and registers it.
+func (w *muxWorkspaces) open(dbPath string) (storage.Storage, func(), error) {
+	beadsDir := filepath.Dir(dbPath)
+	workspacePath := filepath.Dir(beadsDir)
+
+	if cfg, err := configfile.Load(beadsDir); err == nil && cfg != nil {
+		if backend := cfg.GetBackend(); configfile.CapabilitiesForBackend(backend).SingleProcessOnly {
+			return nil, nil, fmt.Errorf("%s", singleProcessBackendHelp(backend))
+		}
+	}
+	if filepath.Base(dbPath) != beads.CanonicalDatabaseName {
+		return nil, nil, fmt.Errorf("non-canonical database name %s (expected %s)", filepath.Base(dbPath), beads.CanonicalDatabaseName)
+	}
+
+	lock, err := acquireDaemonLockInfo(beadsDir, DaemonLockInfo{
+		PID:       os.Getpid(),
+		Database:  dbPath,
+		Version:   Version,
+		StartedAt: time.Now().UTC(),
+		Multiplex: true,
+	})
+	if err != nil {
+		if err == ErrDaemonLocked {
+			return nil, nil, fmt.Errorf("workspace %s has a daemon of its own", workspacePath)
+		}
+		return nil, nil, err
+	}
+
+	store, err := factory.NewFromConfigWithOptions(context.Background(), beadsDir, factory.Options{})
+	if err != nil {
+		_ = lock.Close()
+		return nil, nil, fmt.Errorf("cannot open database: %w", err)
+	}
+	if !utils.PathsEqual(utils.CanonicalizePath(store.Path()), dbPath) {
+		_ = store.Close()
+		_ = lock.Close()
+		return nil, nil, fmt.Errorf("workspace database is %s, not %s", store.Path(), dbPath)
+	}
+	if sqliteStore, ok := store.(*sqlite.SQLiteStorage); ok {
+		sqliteStore.EnableFreshnessChecking()
+	}
+
+	if w.registry != nil {
+		entry := daemon.RegistryEntry{
+			WorkspacePath: workspacePath,
+			SocketPath:    w.socketPath,
+			DatabasePath:  dbPath,
+			PID:           os.Getpid(),
+			Version:       Version,
+			StartedAt:     time.Now(),
+			Mux:           true,
+		}
+		if err := w.registry.RegisterWorkspace(entry); err != nil {
+			w.log.Warn("failed to register workspace", "workspace", workspacePath, "error", err)
+		}
+	}
+	w.log.Info("opened workspace", "workspace", workspacePath)
+
+	release := func() {
+		if w.registry != nil {
+			if err := w.registry.UnregisterWorkspace(workspacePath, os.Getpid()); err != nil {
+				w.log.Warn("failed to unregister workspace", "workspace", workspacePath, "error", err)
+			}
+		}
+		_ = lock.Close()
+		w.log.Info("closed workspace", "workspace", workspacePath)
+	}
+	return store, release, nil
+}
+
+// run is the per-workspace sync loop: debounced export after mutations,
+// import when the JSONL changes, periodic pulls with --auto-pull, and gate
+// evaluation.
+func (w *muxWorkspaces) run(ctx context.Context, server *rpc.Server, store storage.Storage, dbPath string) {
+	s := w.settings
+	server.SetConfig(s.autoCommit, s.autoPush, s.autoPull, false, s.interval.String(), rpc.DaemonModeMultiplex)
+
+	beadsDir := filepath.Dir(dbPath)
+	workspacePath := filepath.Dir(beadsDir)
+	syncBranch := muxSyncBranch(ctx, store, beadsDir)
+
+	exportDebouncer := NewDebouncer(500*time.Millisecond, func() {
+		w.export(ctx, store, dbPath, syncBranch != "")
+	})
+	defer exportDebouncer.Cancel()
+
+	importTicker := time.NewTicker(s.interval)
+	defer importTicker.Stop()
+
+	var pullC <-chan time.Time
+	if s.autoPull {
+		pullTicker := time.NewTicker(s.pullInterval)
+		defer pullTicker.Stop()
+		pullC = pullTicker.C
+	}
+
+	// Gate and SLA settings come from the workspace's own config.yaml; the
+	// daemon's global config belongs to no workspace
+	var gates *daemonGates
+	if cfg, err := config.LoadWorkspace(beadsDir); err != nil {
+		w.log.Warn("gate and SLA evaluation disabled", "workspace", workspacePath, "error", err)
+	} else {
+		gates = newDaemonGates(server, store, workspacePath, cfg, w.log)
+		go gates.run(ctx)
+		go newDaemonSLA(server, store, workspacePath, cfg, w.log).run(ctx)
+	}
+
+	mutations := server.MutationChan()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-mutations:
+			w.log.Debug("mutation detected", "workspace", workspacePath, "type", event.Type, "issue", event.IssueID)
+			exportDebouncer.Trigger()
+			gates.observe(event)
+		case <-importTicker.C:
+			// With a sync branch the JSONL lives in a worktree that only
+			// 'bd sync' knows how to reconcile
+			if syncBranch == "" {
+				w.importIfChanged(ctx, store, dbPath)
+			}
+		case <-pullC:
+			args := []string{}
+			if !s.autoPush {
+				args = append(args, "--no-push")
+			}
+			w.sync(ctx, workspacePath, args...)
+		}
+	}
+}
+
+// export writes a workspace's pending changes to JSONL. Committing, and any
+// sync-branch workspace, is delegated to 'bd sync', which owns the git side.
+func (w *muxWorkspaces) export(ctx context.Context, store storage.Storage, dbPath string, hasSyncBranch bool) {
+	beadsDir := filepath.Dir(dbPath)
+	workspacePath := filepath.Dir(beadsDir)
+
+	if w.settings.autoCommit {
+		if w.settings.autoPush {
+			w.sync(ctx, workspacePath)
+		} else {
+			w.sync(ctx, workspacePath, "--no-push")
+		}
+		return
+	}
+	if hasSyncBranch {
+		w.sync(ctx, workspacePath, "--flush-only")
+		return
+	}
+
+	if skip, holder, _ := types.ShouldSkipDatabase(beadsDir); skip {
+		w.log.Info("skipping export (database locked)", "workspace", workspacePath, "holder", holder)
+		return
+	}
+
+	jsonlPath := utils.FindJSONLInDir(beadsDir)
+	exportCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := validatePreExport(exportCtx, store, jsonlPath); err != nil {
+		w.log.Warn("pre-export validation failed", "workspace", workspacePath, "error", err)
+		return
+	}
+	if err := exportToJSONLWithStore(exportCtx, store, jsonlPath); err != nil {
+		w.log.Error("export failed", "workspace", workspacePath, "error", err)
+		return
+	}
+	updateExportMetadata(exportCtx, store, jsonlPath, w.log, "")
+	recordDaemonTimestamp(exportCtx, store, rpc.MetadataLastExportTime, w.log)
+	if _, ok := store.(*sqlite.SQLiteStorage); ok {
+		if err := TouchDatabaseFile(dbPath, jsonlPath); err != nil {
+			w.log.Warn("failed to update database mtime", "workspace", workspacePath, "error", err)
+		}
+	}
+	w.log.Info("exported to JSONL", "workspace", workspacePath)
+}
+
+// importIfChanged imports a workspace's JSONL when it changed on disk since
+// the last export or import (e.g. after git pull).
+func (w *muxWorkspaces) importIfChanged(ctx context.Context, store storage.Storage, dbPath string) {
+	beadsDir := filepath.Dir(dbPath)
+	jsonlPath := utils.FindJSONLInDir(beadsDir)
+	if _, err := os.Stat(jsonlPath); err != nil {
+		return
+	}
+	if !hasJSONLChanged(ctx, store, jsonlPath, "") {
+		return
+	}
+	if skip, _, _ := types.ShouldSkipDatabase(beadsDir); skip {
+		return
+	}
+
+	importCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if err := importToJSONLWithStore(importCtx, store, jsonlPath); err != nil {
+		w.log.Error("import failed", "workspace", filepath.Dir(beadsDir), "error", err)
+		return
+	}
+	updateExportMetadata(importCtx, store, jsonlPath, w.log, "")
+	w.log.Info("imported JSONL changes", "workspace", filepath.Dir(beadsDir))
+}
+
+// sync runs 'bd --no-daemon sync' in a workspace. It bypasses the daemon so
+// the sync cannot route back to the multiplex daemon it is running under.
+func (w *muxWorkspaces) sync(ctx context.Context, workspacePath string, args ...string) {
+	exe, err := os.Executable()
+	if err != nil {
+		w.log.Error("cannot resolve executable path", "error", err)
+		return
+	}
+
+	syncCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	// #nosec G204 - bd sync from trusted binary
+	cmd := exec.CommandContext(syncCtx, exe, append([]string{"--no-daemon", "sync"}, args...)...)
+	cmd.Dir = workspacePath
+	cmd.Env = muxChildEnv()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(syncCtx.Err(), context.Canceled) {
+			return
+		}
+		w.log.Error("sync failed", "workspace", workspacePath, "error", err, "output", strings.TrimSpace(string(out)))
+		return
+	}
+	w.log.Info("synced", "workspace", workspacePath, "args", strings.Join(args, " "))
+}
+
+// muxChildEnv is the environment for commands run in a workspace: the
+// daemon's own environment without anything that pins a database, so the
+// command resolves the workspace from its working directory.
+func muxChildEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		switch name {
+		case "BEADS_DB", "BEADS_DIR", "BEADS_JSONL", "BD_SOCKET", "BD_DAEMON_FOREGROUND":
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
+
+// muxSyncBranch returns the sync branch configured for the workspace in
+// beadsDir. The multiplex daemon's global config belongs to no workspace,
+// so the workspace's config.yaml is read directly.
+func muxSyncBranch(ctx context.Context, store storage.Storage, beadsDir string) string {
+	if branch := os.Getenv(syncbranch.EnvVar); branch != "" {
+		return branch
+	}
+
+	// #nosec G304 - controlled path from workspace
+	if data, err := os.ReadFile(filepath.Join(beadsDir, "config.yaml")); err == nil {
+		var cfg map[string]interface{}
+		if yaml.Unmarshal(data, &cfg) == nil {
+			if branch, ok := cfg[syncbranch.ConfigYAMLKey].(string); ok && branch != "" {
+				return branch
+			}
+		}
+	}
+
+	branch, _ := store.GetConfig(ctx, syncbranch.ConfigKey)
+	return branch
+}
+
+// releaseMuxWorkspace asks the multiplex daemon to close the workspace whose
+// database is dbPath, leaving its other workspaces open.
+func releaseMuxWorkspace(dbPath string) error {
+	client, err := rpc.TryConnectWithTimeout(rpc.MuxSocketPath(), time.Second)
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		return fmt.Errorf("multiplex daemon not reachable")
+	}
+	defer func() { _ = client.Close() }()
+
+	client.SetDatabasePath(dbPath)
+	return client.Shutdown()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/hooks"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/sla"
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+// slaTickInterval is how often the daemon evaluates SLA policies. Targets
+// are measured in hours, so a minute of lag is fine.
+const slaTickInterval = time.Minute
+
+// daemonSLA evaluates SLA policies from the daemon so breaches are acted on
+// without anyone running bd sla check. Each breach is labeled, recorded as
+// an event bead, handed to the on_sla_breach hook and mailed to waiters
+// once, and emitted as an SLA mutation so it shows up in bd activity.
+type daemonSLA struct {
+	store    storage.Storage
+	policies []sla.Policy
+	atRisk   float64
+	actions  *slaActions
+	emit     func(rpc.MutationEvent)
+	log      daemonLogger
+}
+
+// newDaemonSLA returns SLA evaluation for the workspace at workspacePath, or
+// nil when daemon.sla-check is disabled or no policies are configured.
+// Settings come from settings, the workspace's configuration.
+func newDaemonSLA(server *rpc.Server, store storage.Storage, workspacePath string, settings *config.Settings, log daemonLogger) *daemonSLA {
+	if !settings.GetBool("daemon.sla-check") {
+		log.Info("daemon SLA evaluation disabled (daemon.sla-check=false)")
+		return nil
+	}
+	policies, atRisk, err := loadSLAPoliciesFrom(settings)
+	if err != nil {
+		log.Warn("daemon SLA evaluation disabled", "error", err)
+		return nil
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+	return &daemonSLA{
+		store:    store,
+		policies: policies,
+		atRisk:   atRisk,
+		actions:  newSLAActions(store, "daemon", hooks.NewRunnerFromWorkspace(workspacePath)),
+		emit:     server.EmitMutation,
+		log:      log,
+	}
+}
+
+// run checks SLA policies every slaTickInterval until ctx is done.
+func (d *daemonSLA) run(ctx context.Context) {
+	if d == nil {
+		return
+	}
+	ticker := time.NewTicker(slaTickInterval)
+	defer ticker.Stop()
+
+	d.check(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.check(ctx, now)
+		}
+	}
+}
+
+// check acts on every breach that hasn't been handled yet.
+func (d *daemonSLA) check(ctx context.Context, now time.Time) {
+	entries, err := loadSLAEntries(ctx, d.store, d.policies, d.atRisk, now)
+	if err != nil {
+		d.log.Warn("SLA check failed", "error", err)
+		return
+	}
+	for _, e := range entries {
+		for _, c := range e.newBreaches() {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := d.actions.mark(ctx, e, c); err != nil {
+				d.log.Warn("SLA check: marking breach failed", "issue", e.Issue.ID, "target", c.Target, "error", err)
+				continue
+			}
+			eventID, err := d.actions.record(ctx, e, c)
+			if err != nil {
+				d.log.Warn("SLA check: acting on breach failed", "issue", e.Issue.ID, "target", c.Target, "error", err)
+			}
+			d.log.Warn("SLA breached", "issue", e.Issue.ID, "target", c.Target, "policy", e.Status.Policy, "event", eventID)
+			d.emit(rpc.MutationEvent{
+				Type:      rpc.MutationSLA,
+				IssueID:   e.Issue.ID,
+				Title:     e.Issue.Title,
+				Actor:     "daemon",
+				NewStatus: string(c.Target),
+			})
+		}
+	}
+}
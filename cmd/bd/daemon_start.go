@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -34,7 +35,8 @@ Examples:
   bd daemon start --auto-push        # Enable auto-push (implies --auto-commit)
   bd daemon start --foreground       # Run in foreground (for systemd/supervisord)
   bd daemon start --local            # Local-only mode (no git sync)
-  bd daemon start --federation       # Enable federation mode (dolt sql-server)`,
+  bd daemon start --federation       # Enable federation mode (dolt sql-server)
+  bd daemon start --metrics-addr 127.0.0.1:9464  # Serve Prometheus/OpenMetrics at /metrics`,
 	Run: func(cmd *cobra.Command, args []string) {
 		interval, _ := cmd.Flags().GetDuration("interval")
 		autoCommit, _ := cmd.Flags().GetBool("auto-commit")
@@ -48,6 +50,7 @@ Examples:
 		federation, _ := cmd.Flags().GetBool("federation")
 		federationPort, _ := cmd.Flags().GetInt("federation-port")
 		remotesapiPort, _ := cmd.Flags().GetInt("remotesapi-port")
+		metricsAddr, _ := cmd.Flags().GetString("metrics-addr")
 
 		// NOTE: Only load daemon auto-settings from the database in foreground mode.
 		//
@@ -77,6 +80,12 @@ Examples:
 		if os.Getenv("BD_DAEMON_FOREGROUND") != "1" {
 			// Check if daemon is already running
 			if isRunning, pid := isDaemonRunning(pidFile); isRunning {
+				if info, err := readDaemonLockInfo(filepath.Dir(pidFile)); err == nil && info.Multiplex {
+					fmt.Fprintf(os.Stderr, "Error: workspace is served by the multiplex daemon (PID %d)\n", pid)
+					fmt.Fprintf(os.Stderr, "Use 'bd daemon stop %s' to release it first\n", filepath.Dir(filepath.Dir(info.Database)))
+					os.Exit(1)
+				}
+
 				// Check if running daemon has compatible version
 				socketPath := getSocketPathForPID(pidFile)
 				if client, err := rpc.TryConnectWithTimeout(socketPath, 1*time.Second); err == nil && client != nil {
@@ -155,7 +164,7 @@ Examples:
 			fmt.Printf("Logging to: %s\n", logFile)
 		}
 
-		startDaemon(interval, autoCommit, autoPush, autoPull, localMode, foreground, logFile, pidFile, logLevel, logJSON, federation, federationPort, remotesapiPort)
+		startDaemon(interval, autoCommit, autoPush, autoPull, localMode, foreground, logFile, pidFile, logLevel, logJSON, federation, federationPort, remotesapiPort, metricsAddr)
 	},
 }
 
@@ -172,4 +181,5 @@ func init() {
 	daemonStartCmd.Flags().Bool("federation", false, "Enable federation mode (runs dolt sql-server)")
 	daemonStartCmd.Flags().Int("federation-port", 3306, "MySQL port for federation mode dolt sql-server")
 	daemonStartCmd.Flags().Int("remotesapi-port", 8080, "remotesapi port for peer-to-peer sync in federation mode")
+	daemonStartCmd.Flags().String("metrics-addr", "", "Serve OpenMetrics at /metrics on this address (e.g. 127.0.0.1:9464; env: BEADS_DAEMON_METRICS_ADDR)")
 }
@@ -9,13 +9,16 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/beads/internal/beads"
 	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/rpc"
 	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/synctransport"
 	"github.com/steveyegge/beads/internal/types"
 )
 
@@ -308,9 +311,22 @@ func updateExportMetadata(ctx context.Context, store storage.Storage, jsonlPath
 	if err := store.SetMetadata(ctx, timeKey, exportTime); err != nil {
 		log.log("Warning: failed to update %s: %v", timeKey, err)
 	}
+
+	if err := store.SetMetadata(ctx, "jsonl_format_version", strconv.Itoa(types.JSONLFormatVersion)); err != nil {
+		log.log("Warning: failed to update jsonl_format_version: %v", err)
+	}
 	// Note: mtime tracking removed (git doesn't preserve mtime)
 }
 
+// recordDaemonTimestamp stores the current time under a daemon metadata key
+// (rpc.MetadataLastExportTime, rpc.MetadataLastSyncTime) so the metrics
+// endpoint can report how long ago the daemon last exported or synced.
+func recordDaemonTimestamp(ctx context.Context, store storage.Storage, key string, log daemonLogger) {
+	if err := store.SetMetadata(ctx, key, time.Now().Format(time.RFC3339Nano)); err != nil {
+		log.log("Warning: failed to update %s: %v", key, err)
+	}
+}
+
 // validateDatabaseFingerprint checks that the database belongs to this repository
 func validateDatabaseFingerprint(ctx context.Context, store storage.Storage, log *daemonLogger) error {
 
@@ -453,6 +469,7 @@ func performExport(ctx context.Context, store storage.Storage, autoCommit, autoP
 				// Single-repo mode: update metadata for main JSONL
 				updateExportMetadata(exportCtx, store, jsonlPath, log, "")
 			}
+			recordDaemonTimestamp(exportCtx, store, rpc.MetadataLastExportTime, log)
 
 			// Update database mtime to be >= JSONL mtime (fixes #278, #301, #321)
 			// This prevents validatePreExport from incorrectly blocking on next export
@@ -466,6 +483,30 @@ func performExport(ctx context.Context, store storage.Storage, autoCommit, autoP
 			}
 		}
 
+		// With sync.transport: s3, generations in the bucket replace git
+		// commits and pushes
+		if !skipGit {
+			transport, err := daemonSyncTransport()
+			if err != nil {
+				log.log("Sync transport unavailable: %v", err)
+				return
+			}
+			if transport != nil {
+				if autoCommit && autoPush {
+					res, err := transportSyncWithStore(exportCtx, store, transport, jsonlPath, false)
+					if err != nil {
+						log.log("Transport sync failed: %v", err)
+					} else {
+						log.log("Synced with %s transport (generation %d)", config.SyncTransportS3, res.State.Generation)
+						recordDaemonTimestamp(exportCtx, store, rpc.MetadataLastSyncTime, log)
+					}
+				}
+				finalizeExportMetadata()
+				log.log("Export complete")
+				return
+			}
+		}
+
 		// Auto-commit if enabled (skip in git-free mode)
 		if autoCommit && !skipGit {
 			// Try sync branch commit first
@@ -481,6 +522,9 @@ func performExport(ctx context.Context, store storage.Storage, autoCommit, autoP
 			if committed {
 				// GH#885: Finalize after sync branch commit succeeded
 				finalizeExportMetadata()
+				if autoPush {
+					recordDaemonTimestamp(exportCtx, store, rpc.MetadataLastSyncTime, log)
+				}
 			} else {
 				// If sync branch not configured, use regular commit
 				hasChanges, err := gitHasChanges(exportCtx, jsonlPath)
@@ -509,6 +553,7 @@ func performExport(ctx context.Context, store storage.Storage, autoCommit, autoP
 							return
 						}
 						log.log("Pushed to remote")
+						recordDaemonTimestamp(exportCtx, store, rpc.MetadataLastSyncTime, log)
 					}
 				} else {
 					// No git changes but export happened - finalize metadata
@@ -587,6 +632,28 @@ func performAutoImport(ctx context.Context, store storage.Storage, skipGit bool,
 			log.log("Removed stale lock (%s), proceeding", holder)
 		}
 
+		// With sync.transport: s3, pull the remote generation instead of
+		// running git pull
+		var transport synctransport.Transport
+		if !skipGit {
+			transport, err = daemonSyncTransport()
+			if err != nil {
+				log.log("Sync transport unavailable: %v", err)
+				return
+			}
+			if transport != nil {
+				res, err := transportSyncWithStore(importCtx, store, transport, jsonlPath, true)
+				if err != nil {
+					backoff := RecordSyncFailure(beadsDir, err.Error())
+					log.log("Transport pull failed: %v (backoff: %v)", err, backoff)
+					return
+				}
+				if res.Pulled {
+					log.log("Imported remote generation %d", res.State.Generation)
+				}
+			}
+		}
+
 		// Check JSONL content hash to avoid redundant imports
 		// Use content-based check (not mtime) to avoid git resurrection bug
 		// Use getRepoKeyForPath for multi-repo support
@@ -598,7 +665,7 @@ func performAutoImport(ctx context.Context, store storage.Storage, skipGit bool,
 		log.log("JSONL content changed, proceeding with %s...", mode)
 
 		// Pull from git if not in git-free mode
-		if !skipGit {
+		if !skipGit && transport == nil {
 			// SAFETY CHECK: Warn if there are uncommitted local changes
 			// This helps detect race conditions where local work hasn't been pushed yet
 			jsonlPath := findJSONLPath()
@@ -662,6 +729,7 @@ func performAutoImport(ctx context.Context, store storage.Storage, skipGit bool,
 		} else {
 			// Record success to clear backoff state
 			RecordSyncSuccess(beadsDir)
+			recordDaemonTimestamp(importCtx, store, rpc.MetadataLastSyncTime, log)
 			log.log("Auto-import complete")
 		}
 	}
@@ -756,6 +824,7 @@ func performSync(ctx context.Context, store storage.Storage, autoCommit, autoPus
 				// Single-repo mode: update metadata for main JSONL
 				updateExportMetadata(syncCtx, store, jsonlPath, log, "")
 			}
+			recordDaemonTimestamp(syncCtx, store, rpc.MetadataLastExportTime, log)
 
 			// Update database mtime to be >= JSONL mtime
 			// This prevents validatePreExport from incorrectly blocking on next export
@@ -776,6 +845,31 @@ func performSync(ctx context.Context, store storage.Storage, autoCommit, autoPus
 			return
 		}
 
+		// With sync.transport: s3, generations in the bucket replace git
+		// commits, pulls and pushes
+		transport, err := daemonSyncTransport()
+		if err != nil {
+			log.log("Sync transport unavailable: %v", err)
+			return
+		}
+		if transport != nil {
+			res, err := transportSyncWithStore(syncCtx, store, transport, jsonlPath, !(autoCommit && autoPush))
+			if err != nil {
+				log.log("Transport sync failed: %v", err)
+				return
+			}
+			finalizeExportMetadata()
+			if res.Pulled {
+				log.log("Imported remote generation %d", res.State.Generation)
+			}
+			if res.Pushed {
+				log.log("Pushed generation %d", res.State.Generation)
+			}
+			recordDaemonTimestamp(syncCtx, store, rpc.MetadataLastSyncTime, log)
+			log.log("Sync cycle complete")
+			return
+		}
+
 		// ---- Git operations start here ----
 
 		// Capture left snapshot (pre-pull state) for 3-way merge
@@ -938,6 +1032,7 @@ func performSync(ctx context.Context, store storage.Storage, autoCommit, autoPus
 			log.log("Pushed to remote")
 		}
 
+		recordDaemonTimestamp(syncCtx, store, rpc.MetadataLastSyncTime, log)
 		log.log("Sync cycle complete")
 	}
 }
@@ -126,7 +126,12 @@ uptime, last activity, and exclusive lock status.`,
 		_, _ = fmt.Fprintln(w, "WORKSPACE\tPID\tVERSION\tUPTIME\tLAST ACTIVITY\tLOCK")
 		for _, d := range aliveDaemons {
 			workspace := d.WorkspacePath
-			if workspace == "" {
+			switch {
+			case d.Mux && workspace == "":
+				workspace = "(multiplex daemon)"
+			case d.Mux:
+				workspace += " (multiplex)"
+			case workspace == "":
 				workspace = "(unknown)"
 			}
 			uptime := formatDaemonDuration(d.UptimeSeconds)
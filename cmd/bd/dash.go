@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"golang.org/x/term"
+)
+
+var dashCmd = &cobra.Command{
+	Use:     "dash",
+	GroupID: "views",
+	Short:   "Live project dashboard in the terminal",
+	Long: `Show a full-screen, live-updating project dashboard.
+
+The top row shows ready, in-progress, blocked and recently closed issues.
+Below it are agent beads with their reported state, open gates, and the
+progress of molecules with work in flight.
+
+With the daemon running the dashboard refreshes as soon as the daemon
+reports a mutation; in direct mode it polls every --interval.
+
+Keys:
+  ←/→ h/l, tab   Move between panels
+  ↑/↓ k/j        Move within a panel
+  enter          Show the selected issue (esc to go back)
+  c              Claim the selected issue
+  x              Close the selected issue (asks for confirmation)
+  r              Refresh now
+  q, ctrl+c      Quit
+
+Use --once to print a single snapshot (or --json for machine-readable
+output) without starting the interactive view.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		closedWindow, _ := cmd.Flags().GetDuration("closed-within")
+		once, _ := cmd.Flags().GetBool("once")
+		ctx := rootCtx
+
+		// The dashboard reads through a direct store even when the daemon is
+		// running; the daemon is used for change notification and writes
+		if daemonClient != nil && store == nil {
+			var err error
+			store, err = sqlite.New(ctx, dbPath)
+			if err != nil {
+				FatalErrorRespectJSON("failed to open database: %v", err)
+			}
+			defer func() { _ = store.Close() }()
+		}
+		if store == nil {
+			FatalErrorRespectJSON("no database connection")
+		}
+
+		opts := dashOptions{ClosedWithin: closedWindow}
+		if jsonOutput || once || !term.IsTerminal(int(os.Stdout.Fd())) {
+			snap, err := loadDashSnapshot(ctx, store, opts)
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if jsonOutput {
+				outputJSON(snap)
+				return
+			}
+			m := newDashModel(ctx, store, opts, interval)
+			m.snap, m.static = snap, true
+			m.width, m.height = 120, 30
+			if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+				m.width, m.height = w, h
+			}
+			fmt.Println(m.View())
+			return
+		}
+
+		p := tea.NewProgram(newDashModel(ctx, store, opts, interval), tea.WithAltScreen(), tea.WithContext(ctx))
+		if _, err := p.Run(); err != nil && ctx.Err() == nil {
+			FatalErrorRespectJSON("dashboard: %v", err)
+		}
+	},
+}
+
+// dashOptions controls what a dashboard snapshot contains.
+type dashOptions struct {
+	ClosedWithin time.Duration // How far back the closed column reaches
+}
+
+// dashMolecule is the progress of a molecule with work in flight.
+type dashMolecule struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Total      int    `json:"total"`
+	Completed  int    `json:"completed"`
+	InProgress int    `json:"in_progress"`
+}
+
+// dashSnapshot is everything the dashboard shows at one point in time.
+type dashSnapshot struct {
+	Ready      []*types.Issue        `json:"ready"`
+	InProgress []*types.Issue        `json:"in_progress"`
+	Blocked    []*types.BlockedIssue `json:"blocked"`
+	Closed     []*types.Issue        `json:"recently_closed"`
+	Agents     []*types.Issue        `json:"agents"`
+	Gates      []*types.Issue        `json:"gates"`
+	Molecules  []*dashMolecule       `json:"molecules"`
+	LoadedAt   time.Time             `json:"loaded_at"`
+}
+
+// loadDashSnapshot queries the store for every dashboard panel.
+func loadDashSnapshot(ctx context.Context, s storage.Storage, opts dashOptions) (*dashSnapshot, error) {
+	snap := &dashSnapshot{LoadedAt: time.Now()}
+	var err error
+
+	if snap.Ready, err = s.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen}); err != nil {
+		return nil, fmt.Errorf("ready work: %w", err)
+	}
+	inProgress := types.StatusInProgress
+	if snap.InProgress, err = s.SearchIssues(ctx, "", types.IssueFilter{Status: &inProgress}); err != nil {
+		return nil, fmt.Errorf("in-progress issues: %w", err)
+	}
+	if snap.Blocked, err = s.GetBlockedIssues(ctx, types.WorkFilter{}); err != nil {
+		return nil, fmt.Errorf("blocked issues: %w", err)
+	}
+
+	closed := types.StatusClosed
+	closedFilter := types.IssueFilter{Status: &closed}
+	if opts.ClosedWithin > 0 {
+		since := time.Now().Add(-opts.ClosedWithin)
+		closedFilter.ClosedAfter = &since
+	}
+	if snap.Closed, err = s.SearchIssues(ctx, "", closedFilter); err != nil {
+		return nil, fmt.Errorf("closed issues: %w", err)
+	}
+	sort.SliceStable(snap.Closed, func(i, j int) bool {
+		return closedTime(snap.Closed[i]).After(closedTime(snap.Closed[j]))
+	})
+
+	agentType := types.TypeAgent
+	if snap.Agents, err = s.SearchIssues(ctx, "", types.IssueFilter{IssueType: &agentType, ExcludeStatus: []types.Status{types.StatusClosed}}); err != nil {
+		return nil, fmt.Errorf("agents: %w", err)
+	}
+	sort.SliceStable(snap.Agents, func(i, j int) bool { return snap.Agents[i].Title < snap.Agents[j].Title })
+
+	gateType := types.TypeGate
+	if snap.Gates, err = s.SearchIssues(ctx, "", types.IssueFilter{IssueType: &gateType, ExcludeStatus: []types.Status{types.StatusClosed}}); err != nil {
+		return nil, fmt.Errorf("gates: %w", err)
+	}
+
+	// Molecules with work in flight, found from their in-progress steps
+	seen := make(map[string]bool)
+	for _, issue := range snap.InProgress {
+		molID := findParentMolecule(ctx, s, issue.ID)
+		if molID == "" || molID == issue.ID || seen[molID] {
+			continue
+		}
+		seen[molID] = true
+		stats, err := s.GetMoleculeProgress(ctx, molID)
+		if err != nil {
+			continue
+		}
+		snap.Molecules = append(snap.Molecules, &dashMolecule{
+			ID:         stats.MoleculeID,
+			Title:      stats.MoleculeTitle,
+			Total:      stats.Total,
+			Completed:  stats.Completed,
+			InProgress: stats.InProgress,
+		})
+	}
+	sort.Slice(snap.Molecules, func(i, j int) bool { return snap.Molecules[i].ID < snap.Molecules[j].ID })
+
+	return snap, nil
+}
+
+func closedTime(issue *types.Issue) time.Time {
+	if issue.ClosedAt != nil {
+		return *issue.ClosedAt
+	}
+	return issue.UpdatedAt
+}
+
+// dashChanged reports whether anything changed since the given time, and
+// the time to check from next. With the daemon running this asks for its
+// mutation events; in direct mode every poll counts as a change.
+func dashChanged(since time.Time) (bool, time.Time) {
+	if daemonClient == nil {
+		return true, time.Now()
+	}
+	events, err := fetchMutations(since)
+	if err != nil {
+		// Daemon went away: fall back to polling
+		return true, time.Now()
+	}
+	if len(events) == 0 {
+		return false, since
+	}
+	latest := since
+	for _, e := range events {
+		if e.Timestamp.After(latest) {
+			latest = e.Timestamp
+		}
+	}
+	return true, latest
+}
+
+// dashClaim claims an issue for the current actor.
+func dashClaim(ctx context.Context, s storage.Storage, id string) error {
+	if daemonClient != nil {
+		_, err := daemonClient.Claim(&rpc.ClaimArgs{ID: id})
+		return err
+	}
+	if _, err := storage.ClaimIssue(ctx, s, id, actor, types.DefaultLeaseTTL); err != nil {
+		return err
+	}
+	markDirtyAndScheduleFlush()
+	return nil
+}
+
+// dashClose closes an issue, with the same blocker checks as bd close.
+func dashClose(ctx context.Context, s storage.Storage, id string) error {
+	if daemonClient != nil {
+		_, err := daemonClient.CloseIssue(&rpc.CloseArgs{ID: id, Reason: "Closed"})
+		return err
+	}
+	issue, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := validateIssueClosable(id, issue, false); err != nil {
+		return err
+	}
+	blocked, blockers, err := s.IsBlocked(ctx, id)
+	if err != nil {
+		return err
+	}
+	if blocked && len(blockers) > 0 {
+		return fmt.Errorf("cannot close %s: blocked by open issues %v", id, blockers)
+	}
+	if err := s.CloseIssue(ctx, id, "Closed", actor, ""); err != nil {
+		return err
+	}
+	markDirtyAndScheduleFlush()
+	return nil
+}
+
+// dashDetails loads an issue with its labels, dependencies and comments.
+func dashDetails(ctx context.Context, s storage.Storage, id string) (*types.IssueDetails, error) {
+	if daemonClient != nil {
+		resp, err := daemonClient.Show(&rpc.ShowArgs{ID: id})
+		if err != nil {
+			return nil, err
+		}
+		var details types.IssueDetails
+		if err := json.Unmarshal(resp.Data, &details); err != nil {
+			return nil, err
+		}
+		return &details, nil
+	}
+	issue, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s not found", id)
+	}
+	details := &types.IssueDetails{Issue: *issue}
+	details.Labels, _ = s.GetLabels(ctx, id)
+	deps, _ := s.GetDependencies(ctx, id)
+	for _, dep := range deps {
+		details.Dependencies = append(details.Dependencies, &types.IssueWithDependencyMetadata{Issue: *dep})
+	}
+	details.Comments, _ = s.GetIssueComments(ctx, id)
+	details.Commits, _ = s.GetCommitLinks(ctx, id)
+	return details, nil
+}
+
+func init() {
+	dashCmd.Flags().Duration("interval", 5*time.Second, "Polling interval in direct mode (the daemon pushes changes)")
+	dashCmd.Flags().Duration("closed-within", 7*24*time.Hour, "How far back the closed column reaches")
+	dashCmd.Flags().Bool("once", false, "Print a single snapshot and exit")
+	rootCmd.AddCommand(dashCmd)
+}
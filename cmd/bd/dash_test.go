@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// newDashTestStore seeds one issue for each dashboard panel.
+func newDashTestStore(t *testing.T) *sqlite.SQLiteStorage {
+	t.Helper()
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	issues := []*types.Issue{
+		{ID: "test-ready", Title: "Ready work", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{ID: "test-mol", Title: "Release", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeEpic},
+		{ID: "test-mol.1", Title: "Build", Status: types.StatusInProgress, Priority: 2, IssueType: types.TypeTask, Assignee: "alice"},
+		{ID: "test-mol.2", Title: "Ship", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "test-done", Title: "Done work", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "test-agent", Title: "polecat-1", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeAgent},
+		{ID: "test-gate", Title: "Wait for CI", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeGate, AwaitType: "gh:run", AwaitID: "42"},
+	}
+	for _, issue := range issues {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue %s failed: %v", issue.ID, err)
+		}
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: "test-mol.1", DependsOnID: "test-mol", Type: types.DepParentChild},
+		{IssueID: "test-mol.2", DependsOnID: "test-mol", Type: types.DepParentChild},
+		{IssueID: "test-mol.2", DependsOnID: "test-mol.1", Type: types.DepBlocks},
+	} {
+		if err := s.AddDependency(ctx, dep, "tester"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	if err := s.UpdateIssue(ctx, "test-agent", map[string]interface{}{"agent_state": string(types.StateRunning)}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := s.CloseIssue(ctx, "test-done", "Done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	return s
+}
+
+func dashIDs(rows []dashRow) []string {
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestLoadDashSnapshot(t *testing.T) {
+	ctx := context.Background()
+	s := newDashTestStore(t)
+
+	snap, err := loadDashSnapshot(ctx, s, dashOptions{ClosedWithin: time.Hour})
+	if err != nil {
+		t.Fatalf("loadDashSnapshot failed: %v", err)
+	}
+	m := newDashModel(ctx, s, dashOptions{}, time.Second)
+	m.snap = snap
+
+	for panel, want := range map[int]string{
+		dashInProgress: "test-mol.1",
+		dashBlocked:    "test-mol.2",
+		dashClosed:     "test-done",
+		dashAgents:     "test-agent",
+		dashGates:      "test-gate",
+		dashMolecules:  "test-mol",
+	} {
+		if got := dashIDs(m.rows(panel)); len(got) != 1 || got[0] != want {
+			t.Errorf("%s = %v, want [%s]", dashPanelTitles[panel], got, want)
+		}
+	}
+	if got := dashIDs(m.rows(dashReady)); !slices.Contains(got, "test-ready") || slices.Contains(got, "test-mol.2") {
+		t.Errorf("READY = %v, want test-ready and not the blocked step", got)
+	}
+	if mol := snap.Molecules[0]; mol.Total != 2 || mol.InProgress != 1 {
+		t.Errorf("molecule progress = %+v, want 2 steps with 1 in progress", mol)
+	}
+
+	// Issues closed before the window are left out
+	snap, err = loadDashSnapshot(ctx, s, dashOptions{ClosedWithin: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("loadDashSnapshot failed: %v", err)
+	}
+	if len(snap.Closed) != 0 {
+		t.Errorf("closed = %d issues, want none inside a 1ns window", len(snap.Closed))
+	}
+}
+
+func TestDashModelKeys(t *testing.T) {
+	ctx := context.Background()
+	s := newDashTestStore(t)
+	snap, err := loadDashSnapshot(ctx, s, dashOptions{ClosedWithin: time.Hour})
+	if err != nil {
+		t.Fatalf("loadDashSnapshot failed: %v", err)
+	}
+	origActor := actor
+	actor = "tester"
+	t.Cleanup(func() { actor = origActor })
+
+	m := newDashModel(ctx, s, dashOptions{}, time.Second)
+	m.Update(dashSnapshotMsg{snap: snap})
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	press := func(key string) tea.Cmd {
+		var msg tea.KeyMsg
+		switch key {
+		case "left", "right", "enter":
+			msg = tea.KeyMsg{Type: map[string]tea.KeyType{"left": tea.KeyLeft, "right": tea.KeyRight, "enter": tea.KeyEnter}[key]}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		_, cmd := m.Update(msg)
+		return cmd
+	}
+
+	press("left")
+	if m.panel != dashMolecules {
+		t.Fatalf("left from the first panel should wrap to the last, got %d", m.panel)
+	}
+	press("right")
+	press("right")
+	if m.panel != dashInProgress || m.selected() != "test-mol.1" {
+		t.Fatalf("panel %d selected %q, want in-progress test-mol.1", m.panel, m.selected())
+	}
+
+	// Close asks first; anything but y cancels
+	press("x")
+	if m.confirm != "test-mol.1" || !strings.Contains(m.View(), "Close test-mol.1?") {
+		t.Fatalf("x should ask to confirm closing test-mol.1")
+	}
+	if cmd := press("n"); cmd != nil || m.confirm != "" {
+		t.Fatal("n should cancel the close without running anything")
+	}
+
+	press("x")
+	cmd := press("y")
+	if cmd == nil {
+		t.Fatal("y should run the close")
+	}
+	m.Update(cmd())
+	if issue, _ := s.GetIssue(ctx, "test-mol.1"); issue.Status != types.StatusClosed {
+		t.Errorf("test-mol.1 status = %s after confirming close", issue.Status)
+	}
+
+	// Claim from the ready column
+	m.panel, m.cursor[dashReady] = dashReady, 0
+	id := m.selected()
+	m.Update(press("c")())
+	if issue, _ := s.GetIssue(ctx, id); issue.Status != types.StatusInProgress {
+		t.Errorf("%s status = %s after claim, want in_progress", id, issue.Status)
+	}
+
+	// Enter opens the detail view, esc returns
+	m.Update(press("enter")())
+	if m.details == nil || !strings.Contains(m.View(), id) {
+		t.Fatal("enter should show the selected issue")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.details != nil {
+		t.Error("esc should leave the detail view")
+	}
+}
+
+func TestDashView(t *testing.T) {
+	ctx := context.Background()
+	s := newDashTestStore(t)
+	snap, err := loadDashSnapshot(ctx, s, dashOptions{ClosedWithin: time.Hour})
+	if err != nil {
+		t.Fatalf("loadDashSnapshot failed: %v", err)
+	}
+	m := newDashModel(ctx, s, dashOptions{}, time.Second)
+	m.snap, m.width, m.height = snap, 160, 40
+
+	view := m.View()
+	for _, want := range []string{"READY (", "IN PROGRESS (1)", "BLOCKED (1)", "RECENTLY CLOSED (1)", "AGENTS (1)", "running", "gh:run:42", "MOLECULES (1)", "0/2 Release"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if got := dashProgressBar(1, 4, 8); got != "[██░░░░░░]" {
+		t.Errorf("dashProgressBar(1, 4, 8) = %q", got)
+	}
+	if got := dashTruncate("abcdefgh", 5); got != "abcd…" {
+		t.Errorf("dashTruncate = %q, want abcd…", got)
+	}
+}
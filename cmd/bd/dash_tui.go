@@ -0,0 +1,518 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// How often the daemon is asked for new mutation events
+const dashEventPoll = time.Second
+
+// Dashboard panels, in navigation order
+const (
+	dashReady = iota
+	dashInProgress
+	dashBlocked
+	dashClosed
+	dashAgents
+	dashGates
+	dashMolecules
+	dashPanelCount
+)
+
+var dashPanelTitles = [dashPanelCount]string{"READY", "IN PROGRESS", "BLOCKED", "RECENTLY CLOSED", "AGENTS", "GATES", "MOLECULES"}
+
+// dashRow is one selectable line in a panel.
+type dashRow struct {
+	ID   string
+	Text string
+}
+
+type (
+	dashSnapshotMsg struct {
+		snap *dashSnapshot
+		err  error
+	}
+	dashTickMsg    struct{}
+	dashChangedMsg struct {
+		changed bool
+		next    time.Time
+	}
+	dashActionMsg struct {
+		status string
+		err    error
+	}
+	dashDetailsMsg struct {
+		details *types.IssueDetails
+		err     error
+	}
+)
+
+// dashModel is the bubbletea model behind bd dash.
+type dashModel struct {
+	ctx      context.Context
+	store    storage.Storage
+	opts     dashOptions
+	interval time.Duration
+
+	snap      *dashSnapshot
+	err       error
+	since     time.Time // Last mutation seen from the daemon
+	panel     int
+	cursor    [dashPanelCount]int
+	confirm   string // Issue awaiting close confirmation
+	status    string
+	details   *types.IssueDetails
+	width     int
+	height    int
+	detailTop int
+	static    bool // Printed once (--once); no key help
+}
+
+func newDashModel(ctx context.Context, s storage.Storage, opts dashOptions, interval time.Duration) *dashModel {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &dashModel{ctx: ctx, store: s, opts: opts, interval: interval, since: time.Now()}
+}
+
+func (m *dashModel) Init() tea.Cmd {
+	return tea.Batch(m.load(), m.tick())
+}
+
+func (m *dashModel) load() tea.Cmd {
+	return func() tea.Msg {
+		snap, err := loadDashSnapshot(m.ctx, m.store, m.opts)
+		return dashSnapshotMsg{snap: snap, err: err}
+	}
+}
+
+func (m *dashModel) tick() tea.Cmd {
+	wait := m.interval
+	if daemonClient != nil {
+		wait = dashEventPoll
+	}
+	return tea.Tick(wait, func(time.Time) tea.Msg { return dashTickMsg{} })
+}
+
+func (m *dashModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case dashSnapshotMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.snap, m.err = msg.snap, nil
+		m.clampCursors()
+		return m, nil
+
+	case dashTickMsg:
+		since := m.since
+		return m, func() tea.Msg {
+			changed, next := dashChanged(since)
+			return dashChangedMsg{changed: changed, next: next}
+		}
+
+	case dashChangedMsg:
+		m.since = msg.next
+		if msg.changed {
+			return m, tea.Batch(m.load(), m.tick())
+		}
+		return m, m.tick()
+
+	case dashActionMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+		} else {
+			m.status = msg.status
+		}
+		return m, m.load()
+
+	case dashDetailsMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.details, m.detailTop = msg.details, 0
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *dashModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	if m.confirm != "" {
+		id := m.confirm
+		m.confirm = ""
+		if key == "y" || key == "Y" {
+			m.status = "Closing " + id + "..."
+			return m, m.action(fmt.Sprintf("Closed %s", id), func() error { return dashClose(m.ctx, m.store, id) })
+		}
+		m.status = "Close cancelled"
+		return m, nil
+	}
+
+	if m.details != nil {
+		switch key {
+		case "esc", "q", "enter", "backspace":
+			m.details = nil
+		case "down", "j":
+			m.detailTop++
+		case "up", "k":
+			m.detailTop = max(0, m.detailTop-1)
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "q", "esc":
+		return m, tea.Quit
+	case "right", "l", "tab":
+		m.panel = (m.panel + 1) % dashPanelCount
+	case "left", "h", "shift+tab":
+		m.panel = (m.panel + dashPanelCount - 1) % dashPanelCount
+	case "down", "j":
+		if m.cursor[m.panel] < len(m.rows(m.panel))-1 {
+			m.cursor[m.panel]++
+		}
+	case "up", "k":
+		if m.cursor[m.panel] > 0 {
+			m.cursor[m.panel]--
+		}
+	case "r":
+		m.status = "Refreshing..."
+		return m, m.load()
+	case "enter":
+		if id := m.selected(); id != "" {
+			return m, func() tea.Msg {
+				details, err := dashDetails(m.ctx, m.store, id)
+				return dashDetailsMsg{details: details, err: err}
+			}
+		}
+	case "c":
+		if id := m.selected(); id != "" {
+			m.status = "Claiming " + id + "..."
+			return m, m.action(fmt.Sprintf("Claimed %s", id), func() error { return dashClaim(m.ctx, m.store, id) })
+		}
+	case "x":
+		if id := m.selected(); id != "" {
+			m.confirm = id
+		}
+	}
+	return m, nil
+}
+
+func (m *dashModel) action(done string, run func() error) tea.Cmd {
+	return func() tea.Msg {
+		if err := run(); err != nil {
+			return dashActionMsg{err: err}
+		}
+		return dashActionMsg{status: done}
+	}
+}
+
+// selected returns the ID under the cursor in the focused panel.
+func (m *dashModel) selected() string {
+	rows := m.rows(m.panel)
+	if c := m.cursor[m.panel]; c < len(rows) {
+		return rows[c].ID
+	}
+	return ""
+}
+
+func (m *dashModel) clampCursors() {
+	for p := range m.cursor {
+		if n := len(m.rows(p)); m.cursor[p] >= n {
+			m.cursor[p] = max(0, n-1)
+		}
+	}
+}
+
+// rows renders the lines of a panel from the current snapshot.
+func (m *dashModel) rows(panel int) []dashRow {
+	if m.snap == nil {
+		return nil
+	}
+	issueRows := func(issues []*types.Issue) []dashRow {
+		rows := make([]dashRow, 0, len(issues))
+		for _, issue := range issues {
+			rows = append(rows, dashRow{ID: issue.ID, Text: fmt.Sprintf("%s P%d %s", issue.ID, issue.Priority, issue.Title)})
+		}
+		return rows
+	}
+
+	switch panel {
+	case dashReady:
+		return issueRows(m.snap.Ready)
+	case dashInProgress:
+		rows := issueRows(m.snap.InProgress)
+		for i, issue := range m.snap.InProgress {
+			if issue.Assignee != "" {
+				rows[i].Text += " @" + issue.Assignee
+			}
+		}
+		return rows
+	case dashBlocked:
+		rows := make([]dashRow, 0, len(m.snap.Blocked))
+		for _, b := range m.snap.Blocked {
+			rows = append(rows, dashRow{ID: b.ID, Text: fmt.Sprintf("%s P%d %s ← %s", b.ID, b.Priority, b.Title, strings.Join(b.BlockedBy, ","))})
+		}
+		return rows
+	case dashClosed:
+		rows := make([]dashRow, 0, len(m.snap.Closed))
+		for _, issue := range m.snap.Closed {
+			rows = append(rows, dashRow{ID: issue.ID, Text: fmt.Sprintf("%s %s %s", issue.ID, closedTime(issue).Local().Format("01-02 15:04"), issue.Title)})
+		}
+		return rows
+	case dashAgents:
+		rows := make([]dashRow, 0, len(m.snap.Agents))
+		for _, a := range m.snap.Agents {
+			state := string(a.AgentState)
+			if state == "" {
+				state = "unknown"
+			}
+			text := fmt.Sprintf("%-9s %s", state, a.Title)
+			if a.HookBead != "" {
+				text += " → " + a.HookBead
+			}
+			if a.LastActivity != nil {
+				text += " · " + formatDashAge(time.Since(*a.LastActivity))
+			}
+			rows = append(rows, dashRow{ID: a.ID, Text: text})
+		}
+		return rows
+	case dashGates:
+		rows := make([]dashRow, 0, len(m.snap.Gates))
+		for _, g := range m.snap.Gates {
+			await := g.AwaitType
+			if g.AwaitID != "" {
+				await += ":" + g.AwaitID
+			}
+			rows = append(rows, dashRow{ID: g.ID, Text: fmt.Sprintf("%s %s · %s", g.ID, await, formatDashAge(time.Since(g.CreatedAt)))})
+		}
+		return rows
+	case dashMolecules:
+		rows := make([]dashRow, 0, len(m.snap.Molecules))
+		for _, mol := range m.snap.Molecules {
+			rows = append(rows, dashRow{ID: mol.ID, Text: fmt.Sprintf("%s %d/%d %s", dashProgressBar(mol.Completed, mol.Total, 10), mol.Completed, mol.Total, mol.Title)})
+		}
+		return rows
+	}
+	return nil
+}
+
+var (
+	dashPanelStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(ui.ColorMuted).Padding(0, 1)
+	dashFocusStyle   = dashPanelStyle.BorderForeground(ui.ColorAccent)
+	dashSelectStyle  = lipgloss.NewStyle().Reverse(true)
+	dashTitleColors  = [dashPanelCount]lipgloss.TerminalColor{ui.ColorStatusOpen, ui.ColorStatusInProgress, ui.ColorStatusBlocked, ui.ColorStatusClosed, ui.ColorAccent, ui.ColorWarn, ui.ColorAccent}
+	dashHelpKeyStyle = lipgloss.NewStyle().Bold(true)
+)
+
+func (m *dashModel) View() string {
+	width, height := m.width, m.height
+	if width == 0 {
+		return "Loading..."
+	}
+	if m.details != nil {
+		return m.viewDetails(width, height)
+	}
+
+	var b strings.Builder
+	b.WriteString(m.viewHeader(width))
+	b.WriteString("\n")
+
+	// Top row gets about 60% of the height, bottom row the rest
+	avail := max(height-4, 8)
+	topHeight := avail * 3 / 5
+	bottomHeight := avail - topHeight
+
+	top := m.viewRow([]int{dashReady, dashInProgress, dashBlocked, dashClosed}, width, topHeight)
+	bottom := m.viewRow([]int{dashAgents, dashGates, dashMolecules}, width, bottomHeight)
+	b.WriteString(top)
+	b.WriteString("\n")
+	b.WriteString(bottom)
+	if !m.static {
+		b.WriteString("\n")
+		b.WriteString(m.viewFooter(width))
+	}
+	return b.String()
+}
+
+func (m *dashModel) viewHeader(width int) string {
+	mode := fmt.Sprintf("polling every %s", m.interval)
+	if daemonClient != nil {
+		mode = "live (daemon)"
+	}
+	left := ui.RenderBold("beads dashboard")
+	right := mode
+	if m.snap != nil {
+		right += " · updated " + m.snap.LoadedAt.Format("15:04:05")
+	}
+	if m.err != nil {
+		right = ui.RenderFail("error: "+m.err.Error()) + " · " + right
+	}
+	gap := max(width-lipgloss.Width(left)-lipgloss.Width(right), 1)
+	return left + strings.Repeat(" ", gap) + ui.RenderMuted(right)
+}
+
+func (m *dashModel) viewRow(panels []int, width, height int) string {
+	// Each panel has a 2-column border and 2 columns of padding
+	inner := max(width/len(panels)-4, 10)
+	rendered := make([]string, 0, len(panels))
+	for _, p := range panels {
+		rendered = append(rendered, m.viewPanel(p, inner, max(height-2, 3)))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+func (m *dashModel) viewPanel(panel, width, height int) string {
+	rows := m.rows(panel)
+	title := lipgloss.NewStyle().Bold(true).Foreground(dashTitleColors[panel]).Render(fmt.Sprintf("%s (%d)", dashPanelTitles[panel], len(rows)))
+	lines := []string{title}
+
+	visible := height - 1
+	start := 0
+	if c := m.cursor[panel]; c >= visible {
+		start = c - visible + 1
+	}
+	for i := start; i < len(rows) && i < start+visible; i++ {
+		text := dashTruncate(rows[i].Text, width)
+		if panel == m.panel && i == m.cursor[panel] {
+			text = dashSelectStyle.Render(text)
+		}
+		lines = append(lines, text)
+	}
+	if len(rows) == 0 {
+		lines = append(lines, ui.RenderMuted("none"))
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	style := dashPanelStyle
+	if panel == m.panel {
+		style = dashFocusStyle
+	}
+	return style.Width(width + 2).Render(strings.Join(lines, "\n"))
+}
+
+func (m *dashModel) viewFooter(width int) string {
+	if m.confirm != "" {
+		return ui.RenderWarn(fmt.Sprintf("Close %s? (y/n)", m.confirm))
+	}
+	var help []string
+	for _, k := range [][2]string{{"←→", "panel"}, {"↑↓", "select"}, {"enter", "show"}, {"c", "claim"}, {"x", "close"}, {"r", "refresh"}, {"q", "quit"}} {
+		help = append(help, dashHelpKeyStyle.Render(k[0])+" "+k[1])
+	}
+	line := ui.RenderMuted(strings.Join(help, "  "))
+	if m.status != "" {
+		line = dashTruncate(m.status, width/2) + "  " + line
+	}
+	return line
+}
+
+func (m *dashModel) viewDetails(width, height int) string {
+	d := m.details
+	var lines []string
+	lines = append(lines, formatIssueHeader(&d.Issue), formatIssueMetadata(&d.Issue))
+	if d.Assignee != "" {
+		lines = append(lines, "Assignee: "+d.Assignee)
+	}
+	if len(d.Labels) > 0 {
+		lines = append(lines, "Labels: "+strings.Join(d.Labels, ", "))
+	}
+	for _, section := range []struct{ title, body string }{
+		{"DESCRIPTION", d.Description}, {"DESIGN", d.Design}, {"NOTES", d.Notes}, {"ACCEPTANCE CRITERIA", d.AcceptanceCriteria},
+	} {
+		if section.body != "" {
+			lines = append(lines, "", ui.RenderBold(section.title))
+			lines = append(lines, strings.Split(strings.TrimRight(section.body, "\n"), "\n")...)
+		}
+	}
+	if len(d.Dependencies) > 0 {
+		lines = append(lines, "", ui.RenderBold("DEPENDS ON"))
+		for _, dep := range d.Dependencies {
+			lines = append(lines, fmt.Sprintf("  → %s %s [%s]", dep.ID, dep.Title, dep.Status))
+		}
+	}
+	if len(d.Commits) > 0 {
+		lines = append(lines, "", ui.RenderBold("COMMITS"))
+		for _, link := range d.Commits {
+			lines = append(lines, fmt.Sprintf("  %s %s", shortSHA(link.CommitSHA), link.Subject))
+		}
+	}
+	if len(d.Comments) > 0 {
+		lines = append(lines, "", ui.RenderBold("COMMENTS"))
+		for _, c := range d.Comments {
+			lines = append(lines, fmt.Sprintf("  %s %s: %s", c.CreatedAt.Format("2006-01-02"), c.Author, c.Text))
+		}
+	}
+
+	visible := max(height-2, 1)
+	top := min(m.detailTop, max(len(lines)-visible, 0))
+	end := min(top+visible, len(lines))
+	clip := lipgloss.NewStyle().MaxWidth(width)
+	var b strings.Builder
+	for _, line := range lines[top:end] {
+		b.WriteString(clip.Render(line))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(ui.RenderMuted("↑↓ scroll  esc back"))
+	return b.String()
+}
+
+// dashProgressBar renders done/total as a fixed-width bar.
+func dashProgressBar(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(done*width/total, width)
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
+
+// dashTruncate shortens plain text to fit width terminal columns.
+func dashTruncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && lipgloss.Width(string(r))+1 > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "…"
+}
+
+// formatDashAge renders a duration as a compact age like 5m, 3h or 2d.
+func formatDashAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -76,7 +76,12 @@ func merge3WayAndPruneDeletions(ctx context.Context, store storage.Storage, json
 		}
 	}()
 
-	if err := merge.Merge3Way(tmpMerged, basePath, leftPath, jsonlPath, false); err != nil {
+	// Conflict strategies can't stop an automatic merge; they fall back to lww
+	policy, err := merge.LoadPolicy()
+	if err != nil {
+		return false, err
+	}
+	if err := merge.Merge3WayWithPolicy(tmpMerged, basePath, leftPath, jsonlPath, policy.WithoutConflicts(), false); err != nil {
 		// Merge error (including conflicts) is returned as error
 		return false, fmt.Errorf("3-way merge failed: %w", err)
 	}
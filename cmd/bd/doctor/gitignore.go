@@ -27,6 +27,9 @@ bd.sock
 sync-state.json
 last-touched
 
+# Policy actor token key (secret: anyone holding it can act as any actor)
+policy.key
+
 # Local version tracking (prevents upgrade notification spam after git ops)
 .local_version
 
@@ -50,6 +53,7 @@ beads.right.meta.json
 # These files are machine-specific and should not be shared across clones
 .sync.lock
 sync_base.jsonl
+conflict-resolutions.json
 
 # NOTE: Do NOT add negation patterns (e.g., !issues.jsonl) here.
 # They would override fork protection in .git/info/exclude, allowing
@@ -71,6 +75,7 @@ var requiredPatterns = []string{
 	"last-touched",
 	".sync.lock",
 	"sync_base.jsonl",
+	"policy.key",
 }
 
 // CheckGitignore checks if .beads/.gitignore is up to date
@@ -41,12 +41,16 @@ var federationSyncCmd = &cobra.Command{
 Without --peer, syncs with all configured peers.
 With --peer, syncs only with the specified peer.
 
-Handles merge conflicts using the configured strategy:
+Conflicting issues are merged field by field, with the same rules as the
+JSONL merge driver (including merge.fields in config.yaml), so edits to
+different fields of the same issue are all kept. A strategy decides what
+the field merge can't (fields configured as conflict, issues deleted on
+one side) and conflicts in other tables:
   --strategy ours    Keep local changes on conflict
   --strategy theirs  Accept remote changes on conflict
 
-If no strategy is specified and conflicts occur, the sync will pause
-and report which tables have conflicts for manual resolution.
+If no strategy is specified and conflicts remain, the sync will pause
+and report them; 'bd vc conflicts' lists them per issue and field.
 
 Examples:
   bd federation sync                      # Sync with all peers
@@ -190,6 +194,9 @@ func runFederationSync(cmd *cobra.Command, args []string) {
 		if err != nil {
 			if !jsonOutput {
 				fmt.Printf("  %s %v\n", ui.RenderFail("✗"), err)
+				for _, c := range result.Conflicts {
+					fmt.Printf("    - %s\n", c.Field)
+				}
 			}
 			continue
 		}
@@ -207,8 +214,12 @@ func runFederationSync(cmd *cobra.Command, args []string) {
 			}
 			if len(result.Conflicts) > 0 {
 				if result.ConflictsResolved {
-					fmt.Printf("  %s Resolved %d conflicts using %s strategy\n",
-						ui.RenderPass("✓"), len(result.Conflicts), federationStrategy)
+					how := "by field merge"
+					if federationStrategy != "" {
+						how = fmt.Sprintf("using %s strategy", federationStrategy)
+					}
+					fmt.Printf("  %s Resolved conflicts in %d tables %s\n",
+						ui.RenderPass("✓"), len(result.Conflicts), how)
 				} else {
 					fmt.Printf("  %s %d conflicts need resolution\n",
 						ui.RenderWarn("⚠"), len(result.Conflicts))
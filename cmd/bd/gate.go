@@ -1,15 +1,12 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
-	"time"
 
 	_ "github.com/ncruces/go-sqlite3/driver"
 	_ "github.com/ncruces/go-sqlite3/embed"
@@ -33,14 +30,30 @@ Gates are created automatically when a formula step has a gate field.
 They must be closed (manually or via watchers) for the blocked step to proceed.
 
 Gate types:
-  human   - Requires manual bd close (Phase 1)
-  timer   - Expires after timeout (Phase 2)
-  gh:run  - Waits for GitHub workflow (Phase 3)
-  gh:pr   - Waits for PR merge (Phase 3)
-  bead    - Waits for cross-rig bead to close (Phase 4)
+  human             - Requires manual bd close (Phase 1)
+  timer             - Expires after timeout (Phase 2)
+  gh:run            - Waits for GitHub workflow (Phase 3)
+  gh:pr             - Waits for PR merge (Phase 3)
+  bead              - Waits for cross-rig bead to close (Phase 4)
+  gitlab:mr         - Waits for GitLab merge request merge (await_id: [group/project!]<iid>)
+  http              - Polls a URL (await_id: <url> [status == 200 | $.path == value])
+  cmd               - Waits for a command to exit 0 (await_id: command line, no shell;
+                      runs only commands listed in gate.cmd.allow)
+  file              - Waits for a path to exist (await_id: <path> [sha256:<hex>])
+  formula-condition - Evaluates a formula condition against the molecule's steps
 
 For bead gates, await_id format is <rig>:<bead-id> (e.g., "gastown:gt-abc123").
 
+With daemon.gate-check: true, a running daemon evaluates open gates itself:
+each type is polled with backoff, bead gates are checked as soon as their
+target closes, and gates past their timeout are escalated once. Because
+gates can arrive through sync, the daemon only runs cmd gates listed in
+gate.cmd.allow and only polls http gates whose URL matches gate.http.allow.
+
+Escalations go to the targets in gate.escalate (default: gt escalate):
+gt, exec:<command>, webhook:<url> or none. Waiters are woken through the
+targets in gate.notify (default: gt gate wake), which take the same forms.
+
 Examples:
   bd gate list           # Show all open gates
   bd gate list --all     # Show all gates including closed
@@ -203,7 +216,8 @@ var gateAddWaiterCmd = &cobra.Command{
 	Short: "Add a waiter to a gate",
 	Long: `Register an agent as a waiter on a gate bead.
 
-When the gate closes, the waiter will receive a wake notification via 'gt gate wake'.
+When the gate closes, the waiter will receive a wake notification via 'gt gate wake'
+(or the targets configured in gate.notify).
 The waiter is typically the polecat's address (e.g., "gastown/polecats/Toast").
 
 This is used by 'gt done --phase-complete' to register for gate wake notifications.`,
@@ -442,6 +456,9 @@ Use --reason to provide context for why the gate was resolved.`,
 		if reason != "" {
 			fmt.Printf("  Reason: %s\n", reason)
 		}
+		if err := notifyGateWaiters(ctx, issue, reason); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: waking waiters of %s failed: %v\n", gateID, err)
+		}
 	},
 }
 
@@ -459,21 +476,45 @@ Gate types:
   gh:pr    - Check pull request merge status
   timer    - Check timer gates (auto-expire based on timeout)
   bead     - Check cross-rig bead gates
+  gitlab   - Check all GitLab gates (gitlab:mr)
+  http     - Check http gates
+  cmd      - Check command gates
+  file     - Check file gates
+  formula-condition - Check formula condition gates
   all      - Check all gate types
 
-GitHub gates use the 'gh' CLI to query status:
+GitHub gates use the 'gh' CLI to query status, GitLab gates the 'glab' CLI:
   - gh:run checks 'gh run view <id> --json status,conclusion'
   - gh:pr checks 'gh pr view <id> --json state,merged'
+  - gitlab:mr checks 'glab mr view <iid> --output json'
 
 A gate is resolved when:
   - gh:run: status=completed AND conclusion=success
   - gh:pr: state=MERGED
+  - gitlab:mr: state=merged
   - timer: current time > created_at + timeout
   - bead: target bead status=closed
+  - http: the URL returns 2xx, or the status/JSON-path condition holds
+  - cmd: the command exits 0 within 30s (run without a shell, minimal env)
+  - file: the path exists (and matches the sha256 if given)
+  - formula-condition: the condition holds for the molecule's steps
+
+cmd gates RUN the command in their await_id on this machine. Gate issues can
+arrive from other clones through sync, so a cmd gate's command only runs if
+it is listed in gate.cmd.allow in config.yaml; with no allowlist, cmd gates
+are not evaluated at all. Entries match word for word, and a trailing "*"
+allows any further arguments:
+
+  gate:
+    cmd:
+      allow:
+        - make test
+        - ./scripts/ready.sh *
 
 A gate is escalated when:
   - gh:run: status=completed AND conclusion in (failure, canceled)
   - gh:pr: state=CLOSED AND merged=false
+  - gitlab:mr: state=closed
 
 Examples:
   bd gate check              # Check all gates
@@ -481,6 +522,7 @@ Examples:
   bd gate check --type=gh:run # Check only workflow run gates
   bd gate check --type=timer # Check only timer gates
   bd gate check --type=bead  # Check only cross-rig bead gates
+  bd gate check --type=http  # Check only http gates
   bd gate check --dry-run    # Show what would happen without changes
   bd gate check --escalate   # Escalate expired/failed gates`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -553,24 +595,25 @@ Examples:
 		}
 		results := make([]checkResult, 0, len(filteredGates))
 
-		// Check each gate
-		now := time.Now()
+		// Check each gate with the evaluator registered for its type
+		registry := newGateRegistry()
 		for _, gate := range filteredGates {
-			result := checkResult{gate: gate}
-
-			switch {
-			case strings.HasPrefix(gate.AwaitType, "gh:run"):
-				result.resolved, result.escalated, result.reason, result.err = checkGHRun(gate)
-			case strings.HasPrefix(gate.AwaitType, "gh:pr"):
-				result.resolved, result.escalated, result.reason, result.err = checkGHPR(gate)
-			case gate.AwaitType == "timer":
-				result.resolved, result.escalated, result.reason, result.err = checkTimer(gate, now)
-			case gate.AwaitType == "bead":
-				result.resolved, result.reason = checkBeadGate(ctx, gate.AwaitID)
-			default:
+			res, ok, evalErr := registry.Evaluate(ctx, gate)
+			if !ok {
+				if gate.AwaitType == "cmd" {
+					results = append(results, checkResult{gate: gate, reason: "cmd gates are disabled (no gate.cmd.allow configured)"})
+				}
 				// Skip unsupported gate types (human gates need manual resolution)
 				continue
 			}
+			result := checkResult{gate: gate, resolved: res.Resolved, escalated: res.Escalated, reason: res.Reason, err: evalErr}
+
+			// Persist a more specific await_id (e.g. workflow name -> run ID)
+			if res.AwaitID != "" && res.AwaitID != gate.AwaitID && !dryRun {
+				if updateErr := updateGateAwaitID(nil, gate.ID, res.AwaitID); updateErr != nil && result.err == nil {
+					result.err = fmt.Errorf("failed to update gate with discovered run ID: %w", updateErr)
+				}
+			}
 
 			results = append(results, result)
 		}
@@ -603,6 +646,9 @@ Examples:
 					} else {
 						fmt.Printf("%s %s: resolved - %s\n",
 							ui.RenderPass("✓"), r.gate.ID, r.reason)
+						if err := notifyGateWaiters(ctx, r.gate, r.reason); err != nil {
+							fmt.Fprintf(os.Stderr, "Warning: waking waiters of %s failed: %v\n", r.gate.ID, err)
+						}
 					}
 				}
 			} else if r.escalated {
@@ -643,31 +689,19 @@ Examples:
 	},
 }
 
-// shouldCheckGate returns true if the gate matches the type filter
+// shouldCheckGate returns true if the gate matches the type filter.
+// A filter without a colon (e.g. "gh", "gitlab") matches every type in that
+// family ("gh:run", "gh:pr").
 func shouldCheckGate(gate *types.Issue, typeFilter string) bool {
 	if typeFilter == "" || typeFilter == "all" {
 		return true
 	}
-	if typeFilter == "gh" {
-		return strings.HasPrefix(gate.AwaitType, "gh:")
+	if !strings.Contains(typeFilter, ":") && strings.HasPrefix(gate.AwaitType, typeFilter+":") {
+		return true
 	}
 	return gate.AwaitType == typeFilter
 }
 
-// ghRunStatus holds the JSON response from 'gh run view'
-type ghRunStatus struct {
-	Status     string `json:"status"`
-	Conclusion string `json:"conclusion"`
-	Name       string `json:"name"`
-}
-
-// ghPRStatus holds the JSON response from 'gh pr view'
-type ghPRStatus struct {
-	State  string `json:"state"`
-	Merged bool   `json:"merged"`
-	Title  string `json:"title"`
-}
-
 // isNumericID returns true if the string contains only digits (a GitHub run ID)
 func isNumericID(s string) bool {
 	if s == "" {
@@ -681,187 +715,6 @@ func isNumericID(s string) bool {
 	return true
 }
 
-// queryGitHubRunsForWorkflow queries recent runs for a specific workflow using gh CLI.
-// Returns runs sorted newest-first (GitHub API default).
-func queryGitHubRunsForWorkflow(workflow string, limit int) ([]GHWorkflowRun, error) {
-	if _, err := exec.LookPath("gh"); err != nil {
-		return nil, fmt.Errorf("gh CLI not found: install from https://cli.github.com")
-	}
-
-	args := []string{
-		"run", "list",
-		"--workflow", workflow,
-		"--json", "databaseId,name,status,conclusion,createdAt,workflowName",
-		"--limit", fmt.Sprintf("%d", limit),
-	}
-
-	cmd := exec.Command("gh", args...)
-	output, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("gh run list --workflow=%s failed: %s", workflow, string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("gh run list: %w", err)
-	}
-
-	var runs []GHWorkflowRun
-	if err := json.Unmarshal(output, &runs); err != nil {
-		return nil, fmt.Errorf("parse gh output: %w", err)
-	}
-
-	return runs, nil
-}
-
-// discoverRunIDByWorkflowName queries GitHub for the most recent run of a workflow.
-// Returns (runID, error). This is ZFC-compliant: "most recent run" is deterministic.
-func discoverRunIDByWorkflowName(workflowHint string) (string, error) {
-	// Query GitHub directly for this workflow (efficient, avoids limit issues)
-	runs, err := queryGitHubRunsForWorkflow(workflowHint, 5)
-	if err != nil {
-		return "", fmt.Errorf("failed to query workflow runs: %w", err)
-	}
-
-	if len(runs) == 0 {
-		return "", fmt.Errorf("no runs found for workflow '%s'", workflowHint)
-	}
-
-	// Take the most recent run (gh returns newest-first)
-	// This is deterministic: "most recent" is a total ordering by creation time
-	return fmt.Sprintf("%d", runs[0].DatabaseID), nil
-}
-
-// checkGHRun checks a GitHub Actions workflow run gate
-func checkGHRun(gate *types.Issue) (resolved, escalated bool, reason string, err error) {
-	if gate.AwaitID == "" {
-		return false, false, "no run ID specified - set await_id or use workflow name hint", nil
-	}
-
-	runID := gate.AwaitID
-
-	// If await_id is a workflow name hint (non-numeric), auto-discover the run ID
-	if !isNumericID(gate.AwaitID) {
-		discoveredID, discoverErr := discoverRunIDByWorkflowName(gate.AwaitID)
-		if discoverErr != nil {
-			return false, false, fmt.Sprintf("workflow hint '%s': %v", gate.AwaitID, discoverErr), nil
-		}
-
-		// Update the gate with the discovered run ID
-		if updateErr := updateGateAwaitID(nil, gate.ID, discoveredID); updateErr != nil {
-			return false, false, "", fmt.Errorf("failed to update gate with discovered run ID: %w", updateErr)
-		}
-
-		runID = discoveredID
-	}
-
-	// Run: gh run view <id> --json status,conclusion,name
-	cmd := exec.Command("gh", "run", "view", runID, "--json", "status,conclusion,name") // #nosec G204 -- runID is a validated GitHub run ID
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if runErr := cmd.Run(); runErr != nil {
-		// Check if gh CLI is not found
-		if strings.Contains(stderr.String(), "command not found") ||
-			strings.Contains(runErr.Error(), "executable file not found") {
-			return false, false, "", fmt.Errorf("gh CLI not installed")
-		}
-		// Check if run not found
-		if strings.Contains(stderr.String(), "not found") {
-			return false, true, "workflow run not found", nil
-		}
-		return false, false, "", fmt.Errorf("gh run view failed: %s", stderr.String())
-	}
-
-	var status ghRunStatus
-	if parseErr := json.Unmarshal(stdout.Bytes(), &status); parseErr != nil {
-		return false, false, "", fmt.Errorf("failed to parse gh output: %w", parseErr)
-	}
-
-	// Evaluate status
-	switch status.Status {
-	case "completed":
-		switch status.Conclusion {
-		case "success":
-			return true, false, fmt.Sprintf("workflow '%s' succeeded", status.Name), nil
-		case "failure":
-			return false, true, fmt.Sprintf("workflow '%s' failed", status.Name), nil
-		case "cancelled", "canceled":
-			return false, true, fmt.Sprintf("workflow '%s' was canceled", status.Name), nil
-		case "skipped":
-			return true, false, fmt.Sprintf("workflow '%s' was skipped", status.Name), nil
-		default:
-			return false, true, fmt.Sprintf("workflow '%s' concluded with %s", status.Name, status.Conclusion), nil
-		}
-	case "in_progress", "queued", "pending", "waiting":
-		return false, false, fmt.Sprintf("workflow '%s' is %s", status.Name, status.Status), nil
-	default:
-		return false, false, fmt.Sprintf("workflow '%s' status: %s", status.Name, status.Status), nil
-	}
-}
-
-// checkGHPR checks a GitHub pull request gate
-func checkGHPR(gate *types.Issue) (resolved, escalated bool, reason string, err error) {
-	if gate.AwaitID == "" {
-		return false, false, "no PR number specified", nil
-	}
-
-	// Run: gh pr view <id> --json state,merged,title
-	cmd := exec.Command("gh", "pr", "view", gate.AwaitID, "--json", "state,merged,title") // #nosec G204 -- gate.AwaitID is a validated GitHub PR number
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if runErr := cmd.Run(); runErr != nil {
-		// Check if gh CLI is not found
-		if strings.Contains(stderr.String(), "command not found") ||
-			strings.Contains(runErr.Error(), "executable file not found") {
-			return false, false, "", fmt.Errorf("gh CLI not installed")
-		}
-		// Check if PR not found
-		if strings.Contains(stderr.String(), "not found") || strings.Contains(stderr.String(), "Could not resolve") {
-			return false, true, "pull request not found", nil
-		}
-		return false, false, "", fmt.Errorf("gh pr view failed: %s", stderr.String())
-	}
-
-	var status ghPRStatus
-	if parseErr := json.Unmarshal(stdout.Bytes(), &status); parseErr != nil {
-		return false, false, "", fmt.Errorf("failed to parse gh output: %w", parseErr)
-	}
-
-	// Evaluate status
-	switch status.State {
-	case "MERGED":
-		return true, false, fmt.Sprintf("PR '%s' was merged", status.Title), nil
-	case "CLOSED":
-		if status.Merged {
-			return true, false, fmt.Sprintf("PR '%s' was merged", status.Title), nil
-		}
-		return false, true, fmt.Sprintf("PR '%s' was closed without merging", status.Title), nil
-	case "OPEN":
-		return false, false, fmt.Sprintf("PR '%s' is still open", status.Title), nil
-	default:
-		return false, false, fmt.Sprintf("PR '%s' state: %s", status.Title, status.State), nil
-	}
-}
-
-// checkTimer checks a timer gate for expiration
-// Note: timers resolve but never escalate (escalated is always false by design)
-func checkTimer(gate *types.Issue, now time.Time) (resolved, escalated bool, reason string, err error) { //nolint:unparam // escalated intentionally always false
-	if gate.Timeout == 0 {
-		return false, false, "timer gate without timeout configured", fmt.Errorf("no timeout set")
-	}
-
-	expiresAt := gate.CreatedAt.Add(gate.Timeout)
-	if now.After(expiresAt) {
-		expired := now.Sub(expiresAt).Round(time.Second)
-		return true, false, fmt.Sprintf("timer expired %s ago", expired), nil
-	}
-
-	remaining := expiresAt.Sub(now).Round(time.Second)
-	return false, false, fmt.Sprintf("expires in %s", remaining), nil
-}
-
 // checkBeadGate checks if a cross-rig bead gate is satisfied.
 // await_id format: <rig>:<bead-id> (e.g., "gastown:gt-abc123")
 // Returns (satisfied, reason).
@@ -948,24 +801,6 @@ func closeGate(_ interface{}, gateID, reason string) error {
 	return nil
 }
 
-// escalateGate sends an escalation for a failed/expired gate
-func escalateGate(gate *types.Issue, reason string) {
-	topic := fmt.Sprintf("Gate escalation: %s", gate.ID)
-	message := fmt.Sprintf("Gate %s needs attention.\nType: %s\nReason: %s\nCreated: %s",
-		gate.ID,
-		gate.AwaitType,
-		reason,
-		gate.CreatedAt.Format(time.RFC3339))
-
-	// Call gt escalate if available
-	escalateCmd := exec.Command("gt", "escalate", topic, "-s", "HIGH", "-m", message)
-	escalateCmd.Stdout = os.Stdout
-	escalateCmd.Stderr = os.Stderr
-	if err := escalateCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: escalation failed for %s: %v\n", gate.ID, err)
-	}
-}
-
 func init() {
 	// gate list flags
 	gateListCmd.Flags().BoolP("all", "a", false, "Show all gates including closed")
@@ -975,7 +810,7 @@ func init() {
 	gateResolveCmd.Flags().StringP("reason", "r", "", "Reason for resolving the gate")
 
 	// gate check flags
-	gateCheckCmd.Flags().StringP("type", "t", "", "Gate type to check (gh, gh:run, gh:pr, timer, bead, all)")
+	gateCheckCmd.Flags().StringP("type", "t", "", "Gate type to check (gh, gh:run, gh:pr, gitlab, gitlab:mr, timer, bead, http, cmd, file, formula-condition, all)")
 	gateCheckCmd.Flags().Bool("dry-run", false, "Show what would happen without making changes")
 	gateCheckCmd.Flags().BoolP("escalate", "e", false, "Escalate failed/expired gates")
 	gateCheckCmd.Flags().IntP("limit", "l", 100, "Limit results (default 100)")
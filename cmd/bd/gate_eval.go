@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/formula"
+	"github.com/steveyegge/beads/internal/gateeval"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// newGateRegistry returns the gate evaluators used by bd gate check, wired
+// to this workspace: cmd and file gates run relative to the repository root,
+// bead gates look up other rigs and formula-condition gates read the
+// molecule from the local database.
+func newGateRegistry() *gateeval.Registry {
+	deps := gateeval.Deps{
+		WorkDir:      gateWorkDir(),
+		CommandAllow: config.GetStringSlice("gate.cmd.allow"),
+	}
+	return newGateRegistryFor(deps, func() (storage.Storage, error) {
+		if err := ensureStoreActive(); err != nil {
+			return nil, err
+		}
+		return store, nil
+	})
+}
+
+// newGateRegistryFor builds the gate evaluators from deps, wiring bead gates
+// to other rigs and formula-condition gates to the store returned by
+// getStore (the daemon passes its own store). cmd gates are only evaluated
+// for commands in deps.CommandAllow (gate.cmd.allow).
+func newGateRegistryFor(deps gateeval.Deps, getStore func() (storage.Storage, error)) *gateeval.Registry {
+	deps.CheckBead = checkBeadGate
+	deps.StepStates = func(ctx context.Context, gate *types.Issue) (*formula.ConditionContext, error) {
+		s, err := getStore()
+		if err != nil {
+			return nil, err
+		}
+		return moleculeStepStates(ctx, s, gate)
+	}
+	return gateeval.NewDefaultRegistry(deps)
+}
+
+// gateWorkDir is the directory containing .beads, falling back to the
+// current directory.
+func gateWorkDir() string {
+	if beadsDir := beads.FindBeadsDir(); beadsDir != "" {
+		return filepath.Dir(beadsDir)
+	}
+	wd, _ := os.Getwd()
+	return wd
+}
+
+// moleculeStepStates builds the formula condition context for a gate from
+// the molecule that contains it. Steps are keyed by full issue ID and by
+// their ID relative to the molecule root (e.g. "review" for "mol-x.review").
+// Closed steps are "complete", in-progress steps "in_progress" and all
+// others "pending".
+func moleculeStepStates(ctx context.Context, s storage.Storage, gate *types.Issue) (*formula.ConditionContext, error) {
+	molID := findParentMolecule(ctx, s, gate.ID)
+	if molID == "" {
+		return nil, fmt.Errorf("gate %s is not part of a molecule", gate.ID)
+	}
+	root, err := s.GetIssue(ctx, molID)
+	if err != nil || root == nil {
+		return nil, fmt.Errorf("loading molecule %s: %v", molID, err)
+	}
+
+	cc := &formula.ConditionContext{Steps: make(map[string]*formula.StepState)}
+	visited := make(map[string]bool)
+	var build func(issue *types.Issue) (*formula.StepState, error)
+	build = func(issue *types.Issue) (*formula.StepState, error) {
+		visited[issue.ID] = true
+		state := &formula.StepState{ID: stepKey(molID, issue.ID), Status: stepStatus(issue.Status)}
+		dependents, err := s.GetDependentsWithMetadata(ctx, issue.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range dependents {
+			if dep.DependencyType != types.DepParentChild || dep.IssueType == types.TypeGate || visited[dep.ID] {
+				continue
+			}
+			child, err := build(&dep.Issue)
+			if err != nil {
+				return nil, err
+			}
+			state.Children = append(state.Children, child)
+		}
+		cc.Steps[issue.ID] = state
+		cc.Steps[state.ID] = state
+		return state, nil
+	}
+	if _, err := build(root); err != nil {
+		return nil, fmt.Errorf("loading molecule %s: %w", molID, err)
+	}
+
+	// The current step is the one the gate blocks.
+	if dependents, err := s.GetDependentsWithMetadata(ctx, gate.ID); err == nil {
+		for _, dep := range dependents {
+			if dep.DependencyType == types.DepBlocks {
+				cc.CurrentStep = stepKey(molID, dep.ID)
+				break
+			}
+		}
+	}
+	return cc, nil
+}
+
+func stepKey(molID, issueID string) string {
+	if rel, ok := strings.CutPrefix(issueID, molID+"."); ok {
+		return rel
+	}
+	return issueID
+}
+
+func stepStatus(status types.Status) string {
+	switch status {
+	case types.StatusClosed:
+		return "complete"
+	case types.StatusInProgress:
+		return "in_progress"
+	default:
+		return "pending"
+	}
+}
+
+// escalateGate sends an escalation for a failed/expired gate to every
+// target in gate.escalate (default: gt escalate).
+func escalateGate(gate *types.Issue, reason string) {
+	if err := sendGateEscalation(rootCtx, gate, reason); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: escalation failed for %s: %v\n", gate.ID, err)
+	}
+}
+
+func sendGateEscalation(ctx context.Context, gate *types.Issue, reason string) error {
+	return sendGateEscalationTo(ctx, config.GetStringSlice("gate.escalate"), gate, reason)
+}
+
+// sendGateEscalationTo escalates gate to targets (default: gt escalate).
+func sendGateEscalationTo(ctx context.Context, targets []string, gate *types.Issue, reason string) error {
+	if len(targets) == 0 {
+		targets = gateeval.DefaultEscalationTargets
+	}
+	escalators, err := gateeval.ParseEscalators(targets, nil, nil)
+	if err != nil {
+		return err
+	}
+	e := gateeval.NewEscalation(gate, reason)
+	var errs []error
+	for _, esc := range escalators {
+		if err := esc.Escalate(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notifyGateWaiters wakes everyone in gate.Waiters through the targets in
+// gate.notify (default: gt gate wake).
+func notifyGateWaiters(ctx context.Context, gate *types.Issue, reason string) error {
+	return notifyGateWaitersVia(ctx, config.GetStringSlice("gate.notify"), gate, reason)
+}
+
+// notifyGateWaitersVia wakes everyone in gate.Waiters through targets
+// (default: gt gate wake).
+func notifyGateWaitersVia(ctx context.Context, targets []string, gate *types.Issue, reason string) error {
+	if len(gate.Waiters) == 0 {
+		return nil
+	}
+	if len(targets) == 0 {
+		targets = gateeval.DefaultNotifyTargets
+	}
+	wakers, err := gateeval.ParseWakers(targets, nil, nil)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, waiter := range gate.Waiters {
+		w := gateeval.Wake{GateID: gate.ID, Waiter: waiter, Reason: reason}
+		for _, waker := range wakers {
+			if err := waker.Wake(ctx, w); err != nil {
+				errs = append(errs, fmt.Errorf("waking %s: %w", waiter, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/formula"
+	"github.com/steveyegge/beads/internal/gateeval"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestMoleculeStepStates(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	create := func(id string, issueType types.IssueType, status types.Status) {
+		t.Helper()
+		issue := &types.Issue{ID: id, Title: id, Status: status, Priority: 2, IssueType: issueType}
+		if status == types.StatusClosed {
+			issue.ClosedAt = ptrTime(time.Now())
+		}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue(%s): %v", id, err)
+		}
+	}
+	link := func(from, to string, depType types.DependencyType) {
+		t.Helper()
+		if err := s.AddDependency(ctx, &types.Dependency{IssueID: from, DependsOnID: to, Type: depType}, "test"); err != nil {
+			t.Fatalf("AddDependency(%s -> %s): %v", from, to, err)
+		}
+	}
+
+	create("test-mol", types.TypeEpic, types.StatusOpen)
+	create("test-mol.review", types.TypeTask, types.StatusClosed)
+	create("test-mol.build", types.TypeEpic, types.StatusInProgress)
+	create("test-mol.build.lint", types.TypeTask, types.StatusClosed)
+	create("test-mol.deploy", types.TypeTask, types.StatusOpen)
+	create("test-mol.gate-deploy", types.TypeGate, types.StatusOpen)
+	link("test-mol.review", "test-mol", types.DepParentChild)
+	link("test-mol.build", "test-mol", types.DepParentChild)
+	link("test-mol.build.lint", "test-mol.build", types.DepParentChild)
+	link("test-mol.deploy", "test-mol", types.DepParentChild)
+	link("test-mol.gate-deploy", "test-mol", types.DepParentChild)
+	link("test-mol.deploy", "test-mol.gate-deploy", types.DepBlocks)
+
+	gate, err := s.GetIssue(ctx, "test-mol.gate-deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gate.AwaitType = "formula-condition"
+
+	cc, err := moleculeStepStates(ctx, s, gate)
+	if err != nil {
+		t.Fatalf("moleculeStepStates: %v", err)
+	}
+	if cc.CurrentStep != "deploy" {
+		t.Errorf("CurrentStep = %q, want deploy", cc.CurrentStep)
+	}
+	if _, ok := cc.Steps["gate-deploy"]; ok {
+		t.Error("gate issues should not be steps")
+	}
+
+	eval := &gateeval.FormulaConditionEvaluator{StepStates: func(ctx context.Context, gate *types.Issue) (*formula.ConditionContext, error) {
+		return moleculeStepStates(ctx, s, gate)
+	}}
+	for cond, want := range map[string]bool{
+		"review.status == 'complete'":               true,
+		"build.status == 'in_progress'":             true,
+		"children(build).all(status == 'complete')": true,
+		"step.status == 'pending'":                  true,
+		"deploy.status == 'complete'":               false,
+	} {
+		gate.AwaitID = cond
+		res, err := eval.Evaluate(ctx, gate)
+		if err != nil || res.Resolved != want {
+			t.Errorf("%q: result = %+v, err = %v, want resolved=%v", cond, res, err, want)
+		}
+	}
+}
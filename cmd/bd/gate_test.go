@@ -49,6 +49,12 @@ func TestShouldCheckGate(t *testing.T) {
 		{"timer filter does not match gh:run", "gh:run", "timer", false},
 		{"bead filter matches bead", "bead", "bead", true},
 		{"bead filter does not match timer", "timer", "bead", false},
+
+		// Family filters work for any prefix
+		{"gitlab filter matches gitlab:mr", "gitlab:mr", "gitlab", true},
+		{"gitlab filter does not match gh:pr", "gh:pr", "gitlab", false},
+		{"http filter matches http", "http", "http", true},
+		{"http filter does not match cmd", "cmd", "http", false},
 	}
 
 	for _, tt := range tests {
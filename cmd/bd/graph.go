@@ -37,6 +37,7 @@ var (
 	graphCompact bool
 	graphBox     bool
 	graphAll     bool
+	graphFormat  string
 )
 
 var graphCmd = &cobra.Command{
@@ -54,6 +55,18 @@ Display formats:
   --box (default)  ASCII boxes showing layers, more detailed
   --compact        Tree format, one line per issue, more scannable
 
+Export formats (--format), for design docs and PR descriptions:
+  mermaid  Mermaid flowchart (paste into a `+"```"+`mermaid block)
+  dot      Graphviz digraph (render with: dot -Tsvg)
+  svg      Standalone SVG image, laid out by layer
+  json     Nodes with layers, typed edges and clusters
+
+Exports group issues into nested clusters by epic or molecule. Nodes are
+filled by status and outlined by priority (P0 thick red, P1 orange); edges
+distinguish blocks (bold red), parent-child (dashed blue), waits-for
+(dotted purple) and related (dotted gray, undirected). With --all, every
+open issue is drawn in one diagram.
+
 The graph shows execution order:
 - Layer 0 / leftmost = no dependencies (can start immediately)
 - Higher layers depend on lower layers
@@ -73,6 +86,15 @@ Status icons: ○ open  ◐ in_progress  ● blocked  ✓ closed  ❄ deferred`,
 			fmt.Fprintf(os.Stderr, "Error: issue ID required (or use --all for all open issues)\n")
 			os.Exit(1)
 		}
+		switch graphFormat {
+		case "", "box":
+		case "compact":
+			graphCompact = true
+		case "mermaid", "dot", "svg", "json":
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q (want box, compact, mermaid, dot, svg or json)\n", graphFormat)
+			os.Exit(1)
+		}
 
 		// If daemon is running but doesn't support this command, use direct storage
 		if daemonClient != nil && store == nil {
@@ -103,6 +125,12 @@ Status icons: ○ open  ◐ in_progress  ● blocked  ✓ closed  ❄ deferred`,
 				return
 			}
 
+			if isGraphExportFormat(graphFormat) {
+				merged := mergeGraphSubgraphs(subgraphs)
+				renderGraphExport(os.Stdout, graphFormat, computeLayout(merged), merged)
+				return
+			}
+
 			if jsonOutput {
 				outputJSON(subgraphs)
 				return
@@ -155,6 +183,11 @@ Status icons: ○ open  ◐ in_progress  ● blocked  ✓ closed  ❄ deferred`,
 		// Compute layout
 		layout := computeLayout(subgraph)
 
+		if isGraphExportFormat(graphFormat) {
+			renderGraphExport(os.Stdout, graphFormat, layout, subgraph)
+			return
+		}
+
 		if jsonOutput {
 			outputJSON(map[string]interface{}{
 				"root":    subgraph.Root,
@@ -177,6 +210,7 @@ func init() {
 	graphCmd.Flags().BoolVar(&graphAll, "all", false, "Show graph for all open issues")
 	graphCmd.Flags().BoolVar(&graphCompact, "compact", false, "Tree format, one line per issue, more scannable")
 	graphCmd.Flags().BoolVar(&graphBox, "box", true, "ASCII boxes showing layers (default)")
+	graphCmd.Flags().StringVar(&graphFormat, "format", "", "Output format: box, compact, mermaid, dot, svg or json")
 	graphCmd.ValidArgsFunction = issueIDCompletion
 	rootCmd.AddCommand(graphCmd)
 }
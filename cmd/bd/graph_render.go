@@ -0,0 +1,644 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// graphExport is a renderer-neutral view of a dependency graph: the issues
+// with the layers computed by computeLayout, every dependency as a typed
+// edge, and clusters grouping issues under their epic or molecule.
+// It backs bd graph --format mermaid|dot|svg|json.
+type graphExport struct {
+	Nodes    []graphExportNode    `json:"nodes"`
+	Edges    []graphExportEdge    `json:"edges"`
+	Clusters []graphExportCluster `json:"clusters,omitempty"`
+	Layers   int                  `json:"layers"`
+
+	byID     map[string]*graphExportNode
+	clusters map[string]*graphExportCluster
+}
+
+type graphExportNode struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	Status    types.Status    `json:"status"`
+	Priority  int             `json:"priority"`
+	IssueType types.IssueType `json:"issue_type"`
+	Layer     int             `json:"layer"`
+	Position  int             `json:"position"`
+	Cluster   string          `json:"cluster,omitempty"` // ID of the innermost enclosing cluster
+}
+
+// graphExportEdge points in execution order: from the blocker (or parent)
+// to the issue that depends on it.
+type graphExportEdge struct {
+	From string               `json:"from"`
+	To   string               `json:"to"`
+	Type types.DependencyType `json:"type"`
+}
+
+// graphExportCluster is an epic or molecule together with its descendants.
+// The root issue is itself a member.
+type graphExportCluster struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Kind   string `json:"kind"` // epic or molecule
+	Parent string `json:"parent,omitempty"`
+	Depth  int    `json:"depth"`
+}
+
+// buildGraphExport flattens a laid-out subgraph. Nodes are ordered by layer
+// and position, clusters depth-first.
+func buildGraphExport(layout *GraphLayout, subgraph *TemplateSubgraph) *graphExport {
+	g := &graphExport{
+		Layers:   len(layout.Layers),
+		byID:     make(map[string]*graphExportNode),
+		clusters: make(map[string]*graphExportCluster),
+	}
+
+	parent := make(map[string]string)
+	hasChildren := make(map[string]bool)
+	for _, dep := range subgraph.Dependencies {
+		g.Edges = append(g.Edges, graphExportEdge{From: dep.DependsOnID, To: dep.IssueID, Type: dep.Type})
+		if dep.Type == types.DepParentChild {
+			if _, ok := parent[dep.IssueID]; !ok {
+				parent[dep.IssueID] = dep.DependsOnID
+			}
+			hasChildren[dep.DependsOnID] = true
+		}
+	}
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+
+	// clusterOf returns the nearest epic or molecule at or above id
+	clusterOf := func(id string) string {
+		seen := make(map[string]bool)
+		for id != "" && !seen[id] {
+			seen[id] = true
+			if issue := subgraph.IssueMap[id]; issue != nil && hasChildren[id] && graphClusterKind(issue) != "" {
+				return id
+			}
+			id = parent[id]
+		}
+		return ""
+	}
+
+	children := make(map[string][]string)
+	var roots []string
+	for _, issue := range subgraph.Issues {
+		if clusterOf(issue.ID) != issue.ID {
+			continue
+		}
+		c := &graphExportCluster{ID: issue.ID, Title: issue.Title, Kind: graphClusterKind(issue)}
+		if p, ok := parent[issue.ID]; ok {
+			c.Parent = clusterOf(p)
+		}
+		g.clusters[c.ID] = c
+		if c.Parent == "" {
+			roots = append(roots, c.ID)
+		} else {
+			children[c.Parent] = append(children[c.Parent], c.ID)
+		}
+	}
+	var walk func(ids []string, depth int)
+	walk = func(ids []string, depth int) {
+		sort.Strings(ids)
+		for _, id := range ids {
+			c := g.clusters[id]
+			c.Depth = depth
+			g.Clusters = append(g.Clusters, *c)
+			walk(children[id], depth+1)
+		}
+	}
+	walk(roots, 0)
+
+	for _, layer := range layout.Layers {
+		for _, id := range layer {
+			node := layout.Nodes[id]
+			g.Nodes = append(g.Nodes, graphExportNode{
+				ID:        id,
+				Title:     node.Issue.Title,
+				Status:    node.Issue.Status,
+				Priority:  node.Issue.Priority,
+				IssueType: node.Issue.IssueType,
+				Layer:     node.Layer,
+				Position:  node.Position,
+				Cluster:   clusterOf(id),
+			})
+		}
+	}
+	for i := range g.Nodes {
+		g.byID[g.Nodes[i].ID] = &g.Nodes[i]
+	}
+	for i := range g.Clusters {
+		g.clusters[g.Clusters[i].ID] = &g.Clusters[i]
+	}
+	return g
+}
+
+// isGraphExportFormat reports whether a --format value is handled by
+// renderGraphExport rather than the ASCII renderers.
+func isGraphExportFormat(format string) bool {
+	switch format {
+	case "mermaid", "dot", "svg", "json":
+		return true
+	}
+	return false
+}
+
+// renderGraphExport writes a subgraph in one of the export formats.
+func renderGraphExport(w io.Writer, format string, layout *GraphLayout, subgraph *TemplateSubgraph) {
+	g := buildGraphExport(layout, subgraph)
+	switch format {
+	case "mermaid":
+		renderGraphMermaid(w, g)
+	case "dot":
+		renderGraphDOT(w, g)
+	case "svg":
+		renderGraphSVG(w, g)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(g)
+	}
+}
+
+// mergeGraphSubgraphs combines the components from loadAllGraphSubgraphs so
+// bd graph --all can export them as one diagram.
+func mergeGraphSubgraphs(subgraphs []*TemplateSubgraph) *TemplateSubgraph {
+	merged := &TemplateSubgraph{Root: subgraphs[0].Root, IssueMap: make(map[string]*types.Issue)}
+	for _, sg := range subgraphs {
+		merged.Issues = append(merged.Issues, sg.Issues...)
+		merged.Dependencies = append(merged.Dependencies, sg.Dependencies...)
+		for id, issue := range sg.IssueMap {
+			merged.IssueMap[id] = issue
+		}
+	}
+	return merged
+}
+
+// graphClusterKind reports whether an issue groups its children in
+// rendered graphs ("epic" or "molecule") or not ("").
+func graphClusterKind(issue *types.Issue) string {
+	switch {
+	case issue.IssueType == types.TypeMolecule || issue.MolType != "":
+		return "molecule"
+	case issue.IssueType == types.TypeEpic:
+		return "epic"
+	}
+	return ""
+}
+
+// impliedByCluster reports whether an edge is a parent-child link already
+// shown by cluster nesting, so renderers can leave it out.
+func (g *graphExport) impliedByCluster(e graphExportEdge) bool {
+	if e.Type != types.DepParentChild {
+		return false
+	}
+	to := g.byID[e.To]
+	if to == nil {
+		return false
+	}
+	if to.Cluster == e.From {
+		return true
+	}
+	c := g.clusters[e.To]
+	return c != nil && c.Parent == e.From
+}
+
+// visibleEdges returns the edges renderers draw.
+func (g *graphExport) visibleEdges() []graphExportEdge {
+	var edges []graphExportEdge
+	for _, e := range g.Edges {
+		if !g.impliedByCluster(e) {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// membersOf returns the nodes whose innermost cluster is id ("" for
+// unclustered nodes).
+func (g *graphExport) membersOf(id string) []*graphExportNode {
+	var nodes []*graphExportNode
+	for i := range g.Nodes {
+		if g.Nodes[i].Cluster == id {
+			nodes = append(nodes, &g.Nodes[i])
+		}
+	}
+	return nodes
+}
+
+func (g *graphExport) childClusters(id string) []*graphExportCluster {
+	var clusters []*graphExportCluster
+	for i := range g.Clusters {
+		if g.Clusters[i].Parent == id {
+			clusters = append(clusters, &g.Clusters[i])
+		}
+	}
+	return clusters
+}
+
+// graphNodeStyle holds the colors for an issue: fill by status, border by
+// priority.
+type graphNodeStyle struct {
+	Fill, Stroke, Text string
+	StrokeWidth        int
+}
+
+func nodeStyleFor(n *graphExportNode) graphNodeStyle {
+	s := graphNodeStyle{Fill: "#ffffff", Stroke: "#495057", Text: "#212529", StrokeWidth: 1}
+	switch n.Status {
+	case types.StatusInProgress:
+		s.Fill = "#fff3bf"
+	case types.StatusBlocked:
+		s.Fill = "#ffc9c9"
+	case types.StatusDeferred:
+		s.Fill = "#d0ebff"
+	case types.StatusClosed:
+		s.Fill, s.Text = "#e9ecef", "#868e96"
+	}
+	switch n.Priority {
+	case 0:
+		s.Stroke, s.StrokeWidth = "#c92a2a", 3
+	case 1:
+		s.Stroke, s.StrokeWidth = "#e8590c", 2
+	}
+	return s
+}
+
+// graphEdgeStyle distinguishes blocking, hierarchy, fanout and loose links.
+type graphEdgeStyle struct {
+	Color     string
+	Width     int
+	Dash      string // SVG stroke-dasharray, "" for solid
+	Arrow     bool
+	ShowLabel bool
+}
+
+func edgeStyleFor(t types.DependencyType) graphEdgeStyle {
+	switch t {
+	case types.DepBlocks:
+		return graphEdgeStyle{Color: "#c92a2a", Width: 2, Arrow: true}
+	case types.DepConditionalBlocks:
+		return graphEdgeStyle{Color: "#e8590c", Width: 2, Arrow: true, ShowLabel: true}
+	case types.DepParentChild:
+		return graphEdgeStyle{Color: "#1971c2", Width: 1, Dash: "6 4", Arrow: true}
+	case types.DepWaitsFor:
+		return graphEdgeStyle{Color: "#7048e8", Width: 2, Dash: "2 4", Arrow: true, ShowLabel: true}
+	case types.DepRelated, types.DepRelatesTo:
+		return graphEdgeStyle{Color: "#868e96", Width: 1, Dash: "2 3", ShowLabel: true}
+	}
+	return graphEdgeStyle{Color: "#adb5bd", Width: 1, Arrow: true, ShowLabel: true}
+}
+
+// renderGraphMermaid writes a Mermaid flowchart, laid out left to right.
+// Node IDs are replaced by n0, n1, ... because issue IDs may contain
+// characters Mermaid treats as syntax.
+func renderGraphMermaid(w io.Writer, g *graphExport) {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	fmt.Fprintln(w, "flowchart LR")
+	node := func(indent string, n *graphExportNode) {
+		label := mermaidEscape(n.ID) + "<br/>" + mermaidEscape(truncateTitle(n.Title, 40))
+		left, right := mermaidShape(n.IssueType)
+		fmt.Fprintf(w, "%s%s%s\"%s\"%s\n", indent, ids[n.ID], left, label, right)
+	}
+	var cluster func(c *graphExportCluster, indent string)
+	cluster = func(c *graphExportCluster, indent string) {
+		fmt.Fprintf(w, "%ssubgraph c_%s[\"%s: %s\"]\n", indent, ids[c.ID], c.Kind, mermaidEscape(truncateTitle(c.Title, 40)))
+		for _, n := range g.membersOf(c.ID) {
+			node(indent+"  ", n)
+		}
+		for _, child := range g.childClusters(c.ID) {
+			cluster(child, indent+"  ")
+		}
+		fmt.Fprintf(w, "%send\n", indent)
+	}
+	for _, c := range g.childClusters("") {
+		cluster(c, "  ")
+	}
+	for _, n := range g.membersOf("") {
+		node("  ", n)
+	}
+
+	edges := g.visibleEdges()
+	for _, e := range edges {
+		st := edgeStyleFor(e.Type)
+		link := "-->"
+		switch {
+		case !st.Arrow:
+			link = "---"
+		case e.Type == types.DepBlocks:
+			link = "==>"
+		case st.Dash != "":
+			link = "-.->"
+		}
+		if st.ShowLabel {
+			link += "|" + mermaidEscape(string(e.Type)) + "|"
+		}
+		fmt.Fprintf(w, "  %s %s %s\n", ids[e.From], link, ids[e.To])
+	}
+
+	for _, n := range g.Nodes {
+		s := nodeStyleFor(&n)
+		fmt.Fprintf(w, "  style %s fill:%s,stroke:%s,stroke-width:%dpx,color:%s\n", ids[n.ID], s.Fill, s.Stroke, s.StrokeWidth, s.Text)
+	}
+	for i, e := range edges {
+		st := edgeStyleFor(e.Type)
+		dash := ""
+		if st.Dash != "" {
+			dash = ",stroke-dasharray:" + st.Dash
+		}
+		fmt.Fprintf(w, "  linkStyle %d stroke:%s,stroke-width:%dpx%s\n", i, st.Color, st.Width, dash)
+	}
+}
+
+func mermaidShape(t types.IssueType) (string, string) {
+	switch t {
+	case types.TypeEpic:
+		return "[[", "]]"
+	case types.TypeMolecule:
+		return "[(", ")]"
+	case types.TypeFeature:
+		return "(", ")"
+	case types.TypeBug:
+		return "{{", "}}"
+	case types.TypeGate:
+		return "{", "}"
+	}
+	return "[", "]"
+}
+
+var mermaidReplacer = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "|", "#124;")
+
+func mermaidEscape(s string) string {
+	return mermaidReplacer.Replace(s)
+}
+
+// renderGraphDOT writes a Graphviz digraph with one cluster subgraph per
+// epic or molecule.
+func renderGraphDOT(w io.Writer, g *graphExport) {
+	fmt.Fprintln(w, "digraph beads {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, `  node [shape=box, style="rounded,filled", fontname="Helvetica", fontsize=10];`)
+	fmt.Fprintln(w, `  edge [fontname="Helvetica", fontsize=9];`)
+
+	node := func(indent string, n *graphExportNode) {
+		s := nodeStyleFor(n)
+		shape, style := "box", "rounded,filled"
+		switch n.IssueType {
+		case types.TypeEpic, types.TypeMolecule:
+			style = "filled"
+		case types.TypeBug:
+			shape, style = "hexagon", "filled"
+		case types.TypeGate:
+			shape, style = "diamond", "filled"
+		}
+		peripheries := 1
+		if n.IssueType == types.TypeEpic || n.IssueType == types.TypeMolecule {
+			peripheries = 2
+		}
+		label := fmt.Sprintf("%s\n%s\n[%s P%d %s]", n.ID, truncateTitle(n.Title, 40), n.IssueType, n.Priority, n.Status)
+		fmt.Fprintf(w, "%s%q [label=%q, shape=%s, style=%q, peripheries=%d, fillcolor=%q, color=%q, penwidth=%d, fontcolor=%q];\n",
+			indent, n.ID, label, shape, style, peripheries, s.Fill, s.Stroke, s.StrokeWidth, s.Text)
+	}
+	var cluster func(c *graphExportCluster, indent string)
+	cluster = func(c *graphExportCluster, indent string) {
+		fmt.Fprintf(w, "%ssubgraph %q {\n", indent, "cluster_"+c.ID)
+		fmt.Fprintf(w, "%s  label=%q;\n", indent, c.Kind+": "+truncateTitle(c.Title, 40))
+		fmt.Fprintf(w, "%s  style=\"rounded,dashed\"; color=\"#868e96\";\n", indent)
+		for _, n := range g.membersOf(c.ID) {
+			node(indent+"  ", n)
+		}
+		for _, child := range g.childClusters(c.ID) {
+			cluster(child, indent+"  ")
+		}
+		fmt.Fprintf(w, "%s}\n", indent)
+	}
+	for _, c := range g.childClusters("") {
+		cluster(c, "  ")
+	}
+	for _, n := range g.membersOf("") {
+		node("  ", n)
+	}
+
+	for _, e := range g.visibleEdges() {
+		st := edgeStyleFor(e.Type)
+		style := "solid"
+		switch {
+		case st.Dash == "2 3" || st.Dash == "2 4":
+			style = "dotted"
+		case st.Dash != "":
+			style = "dashed"
+		}
+		attrs := fmt.Sprintf("color=%q, penwidth=%d, style=%s", st.Color, st.Width, style)
+		if !st.Arrow {
+			attrs += ", dir=none"
+		}
+		if st.ShowLabel {
+			attrs += fmt.Sprintf(", label=%q", e.Type)
+		}
+		fmt.Fprintf(w, "  %q -> %q [%s];\n", e.From, e.To, attrs)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// SVG layout metrics, in pixels.
+const (
+	svgMargin     = 24
+	svgNodeWidth  = 200
+	svgNodeHeight = 48
+	svgColumnGap  = 80
+	svgRowGap     = 16
+	svgClusterPad = 10
+	svgLabelSpace = 22
+)
+
+// renderGraphSVG writes a standalone SVG image. Columns are the layers from
+// computeLayout, left to right. Each cluster gets its own horizontal band,
+// nested bands inside their parent's, so cluster frames never overlap
+// unrelated issues.
+func renderGraphSVG(w io.Writer, g *graphExport) {
+	type point struct{ x, y int }
+	pos := make(map[string]point, len(g.Nodes))
+	columnX := func(layer int) int { return svgMargin + layer*(svgNodeWidth+svgColumnGap) }
+
+	type frame struct {
+		c                     *graphExportCluster
+		x0, y0, x1, y1, inset int
+	}
+	var frames []frame
+
+	// nesting depth below each cluster, for frame insets
+	height := make(map[string]int)
+	for i := len(g.Clusters) - 1; i >= 0; i-- {
+		c := g.Clusters[i]
+		if c.Parent != "" && height[c.Parent] < height[c.ID]+1 {
+			height[c.Parent] = height[c.ID] + 1
+		}
+	}
+
+	y := svgMargin
+	placeRows := func(nodes []*graphExportNode) {
+		rows := make(map[int]int)
+		maxRows := 0
+		for _, n := range nodes {
+			pos[n.ID] = point{columnX(n.Layer), y + rows[n.Layer]*(svgNodeHeight+svgRowGap)}
+			rows[n.Layer]++
+			if rows[n.Layer] > maxRows {
+				maxRows = rows[n.Layer]
+			}
+		}
+		y += maxRows * (svgNodeHeight + svgRowGap)
+	}
+	var place func(c *graphExportCluster) (int, int)
+	place = func(c *graphExportCluster) (minLayer, maxLayer int) {
+		top := y
+		y += svgLabelSpace
+		members := g.membersOf(c.ID)
+		placeRows(members)
+		minLayer, maxLayer = g.Layers, 0
+		for _, n := range members {
+			minLayer, maxLayer = min(minLayer, n.Layer), max(maxLayer, n.Layer)
+		}
+		for _, child := range g.childClusters(c.ID) {
+			lo, hi := place(child)
+			minLayer, maxLayer = min(minLayer, lo), max(maxLayer, hi)
+		}
+		y += svgClusterPad
+		inset := svgClusterPad * (height[c.ID] + 1)
+		frames = append(frames, frame{c: c, x0: columnX(minLayer) - inset, y0: top,
+			x1: columnX(maxLayer) + svgNodeWidth + inset, y1: y - svgRowGap/2, inset: inset})
+		y += svgRowGap
+		return minLayer, maxLayer
+	}
+	for _, c := range g.childClusters("") {
+		place(c)
+	}
+	placeRows(g.membersOf(""))
+
+	maxInset := 0
+	for _, c := range g.Clusters {
+		maxInset = max(maxInset, svgClusterPad*(height[c.ID]+1))
+	}
+	width := columnX(max(g.Layers-1, 0)) + svgNodeWidth + svgMargin + maxInset
+	totalHeight := y + svgMargin
+	offset := maxInset // shift right so the outermost frames stay in view
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		width+offset, totalHeight, width+offset, totalHeight)
+	fmt.Fprintln(w, "  <defs>")
+	markers := make(map[string]bool)
+	for _, e := range g.visibleEdges() {
+		st := edgeStyleFor(e.Type)
+		if st.Arrow && !markers[st.Color] {
+			markers[st.Color] = true
+			fmt.Fprintf(w, `    <marker id="arrow-%s" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker>`+"\n",
+				strings.TrimPrefix(st.Color, "#"), st.Color)
+		}
+	}
+	fmt.Fprintln(w, "  </defs>")
+	fmt.Fprintf(w, `  <rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+
+	// Outer frames first so nested ones are drawn on top
+	sort.SliceStable(frames, func(i, j int) bool { return frames[i].inset > frames[j].inset })
+	for _, f := range frames {
+		fmt.Fprintf(w, `  <g class="cluster" data-id="%s">`+"\n", html.EscapeString(f.c.ID))
+		fmt.Fprintf(w, `    <rect x="%d" y="%d" width="%d" height="%d" rx="8" fill="#f8f9fa" fill-opacity="0.6" stroke="#868e96" stroke-dasharray="5 3"/>`+"\n",
+			f.x0+offset, f.y0, f.x1-f.x0, f.y1-f.y0)
+		fmt.Fprintf(w, `    <text x="%d" y="%d" font-size="11" fill="#495057">%s: %s</text>`+"\n",
+			f.x0+offset+8, f.y0+15, f.c.Kind, html.EscapeString(truncateTitle(f.c.Title, 50)))
+		fmt.Fprintln(w, "  </g>")
+	}
+
+	for _, e := range g.visibleEdges() {
+		from, okFrom := pos[e.From]
+		to, okTo := pos[e.To]
+		if !okFrom || !okTo {
+			continue
+		}
+		st := edgeStyleFor(e.Type)
+		if !st.Arrow && to.x < from.x {
+			from, to = to, from
+		}
+		// Forward edges leave the right side and enter the left; edges within
+		// a column loop around the right side; backward edges run right to left.
+		x1, y1 := from.x+svgNodeWidth+offset, from.y+svgNodeHeight/2
+		x2, y2 := to.x+offset, to.y+svgNodeHeight/2
+		c1, c2 := x1+max(40, (x2-x1)/2), x2-max(40, (x2-x1)/2)
+		switch {
+		case to.x == from.x:
+			x2 = x1
+			c1, c2 = x1+40, x1+40
+		case to.x < from.x:
+			x1, x2 = from.x+offset, to.x+svgNodeWidth+offset
+			bend := max(40, (x1-x2)/2)
+			c1, c2 = x1-bend, x2+bend
+		}
+		attrs := fmt.Sprintf(`fill="none" stroke="%s" stroke-width="%d"`, st.Color, st.Width)
+		if st.Dash != "" {
+			attrs += fmt.Sprintf(` stroke-dasharray="%s"`, st.Dash)
+		}
+		if st.Arrow {
+			attrs += fmt.Sprintf(` marker-end="url(#arrow-%s)"`, strings.TrimPrefix(st.Color, "#"))
+		}
+		fmt.Fprintf(w, `  <path class="edge %s" d="M%d,%d C%d,%d %d,%d %d,%d" %s/>`+"\n",
+			e.Type, x1, y1, c1, y1, c2, y2, x2, y2, attrs)
+		if st.ShowLabel {
+			fmt.Fprintf(w, `  <text x="%d" y="%d" font-size="9" fill="%s" text-anchor="middle">%s</text>`+"\n",
+				(x1+x2+c1+c2)/4, (y1+y2)/2-4, st.Color, html.EscapeString(string(e.Type)))
+		}
+	}
+
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+		p := pos[n.ID]
+		x := p.x + offset
+		s := nodeStyleFor(n)
+		fmt.Fprintf(w, `  <g class="node" data-id="%s">`+"\n", html.EscapeString(n.ID))
+		fmt.Fprintf(w, "    <title>%s</title>\n", html.EscapeString(fmt.Sprintf("%s: %s [%s P%d %s]", n.ID, n.Title, n.IssueType, n.Priority, n.Status)))
+		shapeAttrs := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="%d"`, s.Fill, s.Stroke, s.StrokeWidth)
+		switch n.IssueType {
+		case types.TypeBug:
+			k := 10
+			fmt.Fprintf(w, `    <polygon points="%d,%d %d,%d %d,%d %d,%d %d,%d %d,%d" %s/>`+"\n",
+				x+k, p.y, x+svgNodeWidth-k, p.y, x+svgNodeWidth, p.y+svgNodeHeight/2,
+				x+svgNodeWidth-k, p.y+svgNodeHeight, x+k, p.y+svgNodeHeight, x, p.y+svgNodeHeight/2, shapeAttrs)
+		case types.TypeGate:
+			fmt.Fprintf(w, `    <rect x="%d" y="%d" width="%d" height="%d" rx="4" stroke-dasharray="4 2" %s/>`+"\n",
+				x, p.y, svgNodeWidth, svgNodeHeight, shapeAttrs)
+		default:
+			rx := 4
+			if n.IssueType == types.TypeFeature || n.IssueType == types.TypeMolecule {
+				rx = 14
+			}
+			fmt.Fprintf(w, `    <rect x="%d" y="%d" width="%d" height="%d" rx="%d" %s/>`+"\n",
+				x, p.y, svgNodeWidth, svgNodeHeight, rx, shapeAttrs)
+			if n.IssueType == types.TypeEpic || n.IssueType == types.TypeMolecule {
+				fmt.Fprintf(w, `    <rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="none" stroke="%s"/>`+"\n",
+					x+3, p.y+3, svgNodeWidth-6, svgNodeHeight-6, max(rx-3, 1), s.Stroke)
+			}
+		}
+		fmt.Fprintf(w, `    <text x="%d" y="%d" font-size="10" font-weight="bold" fill="%s">%s  P%d</text>`+"\n",
+			x+12, p.y+19, s.Text, html.EscapeString(n.ID), n.Priority)
+		fmt.Fprintf(w, `    <text x="%d" y="%d" font-size="11" fill="%s">%s</text>`+"\n",
+			x+12, p.y+36, s.Text, html.EscapeString(truncateTitle(n.Title, 30)))
+		fmt.Fprintln(w, "  </g>")
+	}
+	fmt.Fprintln(w, "</svg>")
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// testExportSubgraph builds an epic with a nested sub-epic, a blocking chain
+// across both, a gate waiting on the epic, and an unrelated issue.
+func testExportSubgraph() *TemplateSubgraph {
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "Launch", IssueType: types.TypeEpic, Status: types.StatusOpen, Priority: 1},
+		{ID: "bd-1.1", Title: "API", IssueType: types.TypeEpic, Status: types.StatusOpen, Priority: 2},
+		{ID: "bd-1.1.1", Title: "Design \"schema\"", IssueType: types.TypeTask, Status: types.StatusInProgress, Priority: 0},
+		{ID: "bd-1.1.2", Title: "Build <api>", IssueType: types.TypeFeature, Status: types.StatusOpen, Priority: 2},
+		{ID: "bd-1.2", Title: "Fix crash", IssueType: types.TypeBug, Status: types.StatusBlocked, Priority: 1},
+		{ID: "bd-2", Title: "Wait for children", IssueType: types.TypeGate, Status: types.StatusOpen, Priority: 2},
+		{ID: "bd-3", Title: "Notes", IssueType: types.TypeTask, Status: types.StatusClosed, Priority: 3},
+	}
+	deps := []*types.Dependency{
+		{IssueID: "bd-1.1", DependsOnID: "bd-1", Type: types.DepParentChild},
+		{IssueID: "bd-1.2", DependsOnID: "bd-1", Type: types.DepParentChild},
+		{IssueID: "bd-1.1.1", DependsOnID: "bd-1.1", Type: types.DepParentChild},
+		{IssueID: "bd-1.1.2", DependsOnID: "bd-1.1", Type: types.DepParentChild},
+		{IssueID: "bd-1.1.2", DependsOnID: "bd-1.1.1", Type: types.DepBlocks},
+		{IssueID: "bd-1.2", DependsOnID: "bd-1.1.2", Type: types.DepBlocks},
+		{IssueID: "bd-2", DependsOnID: "bd-1", Type: types.DepWaitsFor},
+		{IssueID: "bd-3", DependsOnID: "bd-1.2", Type: types.DepRelated},
+	}
+	sg := &TemplateSubgraph{Root: issues[0], Issues: issues, Dependencies: deps, IssueMap: make(map[string]*types.Issue)}
+	for _, issue := range issues {
+		sg.IssueMap[issue.ID] = issue
+	}
+	return sg
+}
+
+func TestBuildGraphExport(t *testing.T) {
+	sg := testExportSubgraph()
+	g := buildGraphExport(computeLayout(sg), sg)
+
+	if len(g.Clusters) != 2 {
+		t.Fatalf("clusters = %+v, want the epic and its sub-epic", g.Clusters)
+	}
+	if c := g.Clusters[0]; c.ID != "bd-1" || c.Kind != "epic" || c.Depth != 0 {
+		t.Errorf("outer cluster = %+v", c)
+	}
+	if c := g.Clusters[1]; c.ID != "bd-1.1" || c.Parent != "bd-1" || c.Depth != 1 {
+		t.Errorf("nested cluster = %+v", c)
+	}
+
+	wantCluster := map[string]string{
+		"bd-1": "bd-1", "bd-1.2": "bd-1",
+		"bd-1.1": "bd-1.1", "bd-1.1.1": "bd-1.1", "bd-1.1.2": "bd-1.1",
+		"bd-2": "", "bd-3": "",
+	}
+	for _, n := range g.Nodes {
+		if n.Cluster != wantCluster[n.ID] {
+			t.Errorf("%s cluster = %q, want %q", n.ID, n.Cluster, wantCluster[n.ID])
+		}
+	}
+	if layer := g.byID["bd-1.2"].Layer; layer != 2 {
+		t.Errorf("bd-1.2 layer = %d, want 2 (same layering as bd graph)", layer)
+	}
+
+	if len(g.Edges) != len(sg.Dependencies) {
+		t.Errorf("edges = %d, want every dependency (%d)", len(g.Edges), len(sg.Dependencies))
+	}
+	for _, e := range g.visibleEdges() {
+		if e.Type == types.DepParentChild {
+			t.Errorf("parent-child edge %s -> %s should be implied by its cluster", e.From, e.To)
+		}
+	}
+}
+
+func TestRenderGraphMermaid(t *testing.T) {
+	sg := testExportSubgraph()
+	var buf bytes.Buffer
+	renderGraphMermaid(&buf, buildGraphExport(computeLayout(sg), sg))
+	out := buf.String()
+
+	for _, want := range []string{
+		"flowchart LR",
+		`subgraph c_n0["epic: Launch"]`,
+		`[["bd-1<br/>Launch"]]`,
+		`#quot;schema#quot;`,
+		`("bd-1.1.2<br/>Build #lt;api#gt;")`,
+		`{{"bd-1.2<br/>Fix crash"}}`,
+		" ==> ",
+		"-.->|waits-for|",
+		"---|related|",
+		"fill:#fff3bf,stroke:#c92a2a,stroke-width:3px",
+		"linkStyle 3 ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mermaid output missing %q:\n%s", want, out)
+		}
+	}
+	// The nested cluster is closed before the outer one
+	if strings.Count(out, "subgraph ") != 2 || strings.Count(out, "\n    end\n") != 1 || strings.Count(out, "\n  end\n") != 1 {
+		t.Errorf("expected one cluster nested in another:\n%s", out)
+	}
+}
+
+func TestRenderGraphDOT(t *testing.T) {
+	sg := testExportSubgraph()
+	var buf bytes.Buffer
+	renderGraphDOT(&buf, buildGraphExport(computeLayout(sg), sg))
+	out := buf.String()
+
+	for _, want := range []string{
+		`subgraph "cluster_bd-1" {`,
+		`    subgraph "cluster_bd-1.1" {`,
+		`"bd-1.1.1" -> "bd-1.1.2" [color="#c92a2a", penwidth=2, style=solid];`,
+		`"bd-1.2" -> "bd-3" [color="#868e96", penwidth=1, style=dotted, dir=none, label="related"];`,
+		`shape=hexagon`,
+		`peripheries=2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dot output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"bd-1" -> "bd-1.1"`) {
+		t.Error("parent-child edge into a nested cluster should be omitted")
+	}
+}
+
+func TestRenderGraphSVG(t *testing.T) {
+	sg := testExportSubgraph()
+	var buf bytes.Buffer
+	renderGraphSVG(&buf, buildGraphExport(computeLayout(sg), sg))
+
+	// Well-formed, one group per node and cluster
+	nodes, clusters := 0, 0
+	dec := xml.NewDecoder(&buf)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v", err)
+		}
+		if el, ok := tok.(xml.StartElement); ok && el.Name.Local == "g" {
+			for _, attr := range el.Attr {
+				if attr.Name.Local == "class" && attr.Value == "node" {
+					nodes++
+				}
+				if attr.Name.Local == "class" && attr.Value == "cluster" {
+					clusters++
+				}
+			}
+		}
+	}
+	if nodes != len(sg.Issues) || clusters != 2 {
+		t.Errorf("got %d nodes and %d clusters, want %d and 2", nodes, clusters, len(sg.Issues))
+	}
+}
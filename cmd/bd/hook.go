@@ -41,8 +41,9 @@ hook scripts installed in .beads/hooks/ (or .git/hooks/).
 
 Supported hooks:
   - pre-commit: Export database to JSONL, stage changes
-  - post-merge: Import JSONL to database after pull/merge
+  - post-merge: Import JSONL to database after pull/merge, link merged commits
   - post-checkout: Import JSONL after branch checkout (with guard)
+  - post-commit: Link the new commit to the issues it mentions
 
 The hook scripts delegate to this command so hook behavior is always
 in sync with the installed bd version.
@@ -64,6 +65,8 @@ Configuration (.beads/config.yaml):
 			exitCode = hookPostMerge(hookArgs)
 		case "post-checkout":
 			exitCode = hookPostCheckout(hookArgs)
+		case "post-commit":
+			exitCode = hookPostCommit(hookArgs)
 		default:
 			fmt.Fprintf(os.Stderr, "Unknown hook: %s\n", hookName)
 			os.Exit(1)
@@ -592,6 +595,7 @@ func hookPostMerge(args []string) int {
 	backend := factory.GetBackendFromConfig(beadsDir)
 	if backend == configfile.BackendDolt {
 		exitCode := hookPostMergeDolt(beadsDir)
+		indexCommitsFromHook("ORIG_HEAD..HEAD")
 		if cfg.ChainStrategy == ChainAfter && exitCode == 0 {
 			return runChainedHookWithConfig("post-merge", args, cfg)
 		}
@@ -607,6 +611,9 @@ func hookPostMerge(args []string) int {
 		fmt.Fprintln(os.Stderr, "Run 'bd doctor --fix' to diagnose and repair")
 	}
 
+	// Link merged commits now that their issues have been imported
+	indexCommitsFromHook("ORIG_HEAD..HEAD")
+
 	// Run quick health check
 	healthCmd := exec.Command("bd", "doctor", "--check-health")
 	_ = healthCmd.Run()
@@ -617,6 +624,44 @@ func hookPostMerge(args []string) int {
 	return 0
 }
 
+// hookPostCommit implements the post-commit hook: record links from the new
+// commit to the issues it mentions, closing any it "Closes" on main.
+func hookPostCommit(args []string) int {
+	beadsDir := beads.FindBeadsDir()
+	if beadsDir == "" {
+		return 0 // Not a beads workspace
+	}
+
+	cfg := loadHookConfig(beadsDir)
+
+	if cfg.ChainStrategy == ChainBefore {
+		if exitCode := runChainedHookWithConfig("post-commit", args, cfg); exitCode != 0 {
+			return exitCode
+		}
+	}
+
+	// Rebased commits are indexed by the post-merge or next post-commit hook
+	if !isRebaseInProgress() {
+		indexCommitsFromHook("--limit", "1", "HEAD")
+	}
+
+	if cfg.ChainStrategy == ChainAfter {
+		return runChainedHookWithConfig("post-commit", args, cfg)
+	}
+	return 0
+}
+
+// indexCommitsFromHook runs 'bd commits index' with the given arguments.
+// Failures are reported but never fail the git operation.
+func indexCommitsFromHook(args ...string) {
+	cmd := exec.Command("bd", append([]string{"commits", "index"}, args...)...) // #nosec G204 -- args are fixed revision ranges
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not index commits: %v\n", err)
+	}
+}
+
 // hookPostMergeDolt implements post-merge for Dolt backend.
 // Import JSONL → Dolt using branch-then-merge pattern:
 // 1. Create jsonl-import branch
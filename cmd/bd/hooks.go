@@ -21,7 +21,7 @@ var hooksFS embed.FS
 
 func getEmbeddedHooks() (map[string]string, error) {
 	hooks := make(map[string]string)
-	hookNames := []string{"pre-commit", "post-merge", "pre-push", "post-checkout", "prepare-commit-msg"}
+	hookNames := []string{"pre-commit", "post-merge", "pre-push", "post-checkout", "prepare-commit-msg", "post-commit"}
 
 	for _, name := range hookNames {
 		content, err := hooksFS.ReadFile("templates/hooks/" + name)
@@ -52,7 +52,7 @@ type HookStatus struct {
 
 // CheckGitHooks checks the status of bd git hooks in .git/hooks/
 func CheckGitHooks() []HookStatus {
-	hooks := []string{"pre-commit", "post-merge", "pre-push", "post-checkout", "prepare-commit-msg"}
+	hooks := []string{"pre-commit", "post-merge", "pre-push", "post-checkout", "prepare-commit-msg", "post-commit"}
 	statuses := make([]HookStatus, 0, len(hooks))
 
 	// Get hooks directory from common git dir (hooks are shared across worktrees)
@@ -186,7 +186,8 @@ The hooks ensure that:
 - post-merge: Imports updated JSONL after pull/merge
 - pre-push: Prevents pushing stale JSONL
 - post-checkout: Imports JSONL after branch checkout
-- prepare-commit-msg: Adds agent identity trailers for forensics`,
+- prepare-commit-msg: Adds agent identity trailers for forensics
+- post-commit: Links new commits to the issues they mention`,
 }
 
 var hooksInstallCmd = &cobra.Command{
@@ -207,7 +208,8 @@ Installed hooks:
   - post-merge: Import JSONL after pull/merge
   - pre-push: Prevent pushing stale JSONL
   - post-checkout: Import JSONL after branch checkout
-  - prepare-commit-msg: Add agent identity trailers (for orchestrator agents)`,
+  - prepare-commit-msg: Add agent identity trailers (for orchestrator agents)
+  - post-commit: Link new commits to the issues they mention`,
 	Run: func(cmd *cobra.Command, args []string) {
 		force, _ := cmd.Flags().GetBool("force")
 		shared, _ := cmd.Flags().GetBool("shared")
@@ -463,7 +465,7 @@ func uninstallHooks() error {
 	if err != nil {
 		return err
 	}
-	hookNames := []string{"pre-commit", "post-merge", "pre-push", "post-checkout", "prepare-commit-msg"}
+	hookNames := []string{"pre-commit", "post-merge", "pre-push", "post-checkout", "prepare-commit-msg", "post-commit"}
 
 	for _, hookName := range hookNames {
 		hookPath := filepath.Join(hooksDir, hookName)
@@ -654,6 +656,9 @@ func runPostMergeHook() int {
 		// Don't fail the merge, just warn
 	}
 
+	// Link merged commits
+	indexCommitsFromHook("ORIG_HEAD..HEAD")
+
 	// Run quick health check
 	healthCmd := exec.Command("bd", "doctor", "--check-health")
 	_ = healthCmd.Run() // Ignore errors
@@ -1189,6 +1194,7 @@ Supported hooks:
   - pre-push: Prevent pushing stale JSONL
   - post-checkout: Import JSONL after branch checkout
   - prepare-commit-msg: Add agent identity trailers for forensics
+  - post-commit: Link the new commit to the issues it mentions
 
 The thin shim pattern ensures hook logic is always in sync with the
 installed bd version - upgrading bd automatically updates hook behavior.`,
@@ -1209,6 +1215,8 @@ installed bd version - upgrading bd automatically updates hook behavior.`,
 			exitCode = runPostCheckoutHook(hookArgs)
 		case "prepare-commit-msg":
 			exitCode = runPrepareCommitMsgHook(hookArgs)
+		case "post-commit":
+			exitCode = hookPostCommit(hookArgs)
 		default:
 			fmt.Fprintf(os.Stderr, "Unknown hook: %s\n", hookName)
 			os.Exit(1)
@@ -16,6 +16,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/beads"
 	"github.com/steveyegge/beads/internal/debug"
+	"github.com/steveyegge/beads/internal/export"
+	"github.com/steveyegge/beads/internal/importer"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/utils"
@@ -36,6 +38,18 @@ Behavior:
   - Collisions (same ID, different content) are detected and reported
   - Use --dedupe-after to find and merge content duplicates after import
   - Use --dry-run to preview changes without applying them
+  - Use --interactive to resolve conflicting issues field by field, with a
+    three-way view (base from git, local, incoming). Choices are recorded in
+    .beads/conflict-resolutions.json and reused on later imports and syncs
+  - Use --strategy-file to apply the same kind of choices non-interactively
+    (JSON or YAML: {"default": "local", "resolutions": [{"issue_id": "bd-1",
+    "field": "title", "choice": "incoming"}]}; issue_id may be "*")
+  - Use --validate-schema to check each record against 'bd schema export'
+    (--validate-schema=warn reports problems without failing)
+
+Files written by an older bd (an older format_version in the export
+manifest, or no manifest at all) are upgraded to the current JSONL
+format as they are read.
 
 NOTE: Import requires direct database access and does not work with daemon mode.
       The command automatically uses --no-daemon when executed.`,
@@ -101,8 +115,21 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 		force, _ := cmd.Flags().GetBool("force")
 		protectLeftSnapshot, _ := cmd.Flags().GetBool("protect-left-snapshot")
 		noGitHistory, _ := cmd.Flags().GetBool("no-git-history")
+		validateSchema, _ := cmd.Flags().GetString("validate-schema")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		strategyFile, _ := cmd.Flags().GetString("strategy-file")
 		_ = noGitHistory // Accepted for compatibility with bd sync subprocess calls
 
+		if interactive && input == "" {
+			fmt.Fprintf(os.Stderr, "Error: --interactive requires an input file (-i), since stdin is needed for prompts\n")
+			os.Exit(1)
+		}
+
+		if validateSchema != "" && validateSchema != "strict" && validateSchema != "warn" {
+			fmt.Fprintf(os.Stderr, "Error: invalid --validate-schema value %q (must be strict or warn)\n", validateSchema)
+			os.Exit(1)
+		}
+
 		// Check if stdin is being used interactively (not piped)
 		if input == "" && term.IsTerminal(int(os.Stdin.Fd())) {
 			fmt.Fprintf(os.Stderr, "Error: No input specified.\n\n")
@@ -132,11 +159,19 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 			in = f
 		}
 
+		formatVersion := detectJSONLFormatVersion(input)
+		if formatVersion > types.JSONLFormatVersion {
+			fmt.Fprintf(os.Stderr, "Error: %s uses JSONL format version %d, but this bd only understands up to %d\n", input, formatVersion, types.JSONLFormatVersion)
+			fmt.Fprintf(os.Stderr, "Upgrade bd to import this file.\n")
+			os.Exit(1)
+		}
+
 		// Phase 1: Read and parse all JSONL
 		ctx := rootCtx
 		scanner := bufio.NewScanner(in)
 
 		var allIssues []*types.Issue
+		var schemaViolations []string
 		lineNum := 0
 
 		for scanner.Scan() {
@@ -202,6 +237,27 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 				}
 			}
 
+			// Bring records from older format versions up to date
+			if formatVersion < types.JSONLFormatVersion {
+				upgraded, err := upgradeJSONLLine(rawLine, formatVersion)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error parsing line %d: %v\n", lineNum, err)
+					os.Exit(1)
+				}
+				line = string(upgraded)
+			}
+
+			if validateSchema != "" {
+				violations, err := types.ValidateJSONLRecord([]byte(line))
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error parsing line %d: %v\n", lineNum, err)
+					os.Exit(1)
+				}
+				for _, v := range violations {
+					schemaViolations = append(schemaViolations, fmt.Sprintf("line %d: %s", lineNum, v.Error()))
+				}
+			}
+
 			// Parse JSON
 			var issue types.Issue
 			if err := json.Unmarshal([]byte(line), &issue); err != nil {
@@ -218,6 +274,21 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 			os.Exit(1)
 		}
 
+		if len(schemaViolations) > 0 {
+			label := "Warning"
+			if validateSchema == "strict" {
+				label = "Error"
+			}
+			fmt.Fprintf(os.Stderr, "%s: %d schema violation(s) in input:\n", label, len(schemaViolations))
+			for _, v := range schemaViolations {
+				fmt.Fprintf(os.Stderr, "  %s\n", v)
+			}
+			if validateSchema == "strict" {
+				fmt.Fprintf(os.Stderr, "\nNo issues were imported. Use --validate-schema=warn to import anyway.\n")
+				os.Exit(1)
+			}
+		}
+
 		// Check if database needs initialization (prefix not set)
 		// Detect prefix from the imported issues
 		initCtx := rootCtx
@@ -253,6 +324,13 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 			fmt.Fprintf(os.Stderr, "✓ Initialized database with prefix '%s' (detected from %s)\n", detectedPrefix, prefixSource)
 		}
 
+		// Apply field-level conflict resolutions (interactive, strategy file,
+		// or choices recorded by an earlier interactive import)
+		if err := resolveImportConflicts(ctx, store, allIssues, input, strategyFile, interactive, dryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving conflicts: %v\n", err)
+			os.Exit(1)
+		}
+
 		// Phase 2: Use shared import logic
 		opts := ImportOptions{
 			DryRun:                     dryRun,
@@ -409,6 +487,15 @@ NOTE: Import requires direct database access and does not work with daemon mode.
 			} else {
 				debug.Logf("Warning: failed to read JSONL for hash update: %v", err)
 			}
+
+			// The imported JSONL becomes the base for the next field-level
+			// conflict check (see loadConflictBase), as after a daemon import.
+			// Only for the workspace's own JSONL, where the snapshot lives.
+			if utils.CanonicalizePath(filepath.Dir(input)) == utils.CanonicalizePath(filepath.Dir(dbPath)) {
+				if err := updateBaseSnapshot(input); err != nil {
+					debug.Logf("Warning: failed to update base snapshot: %v", err)
+				}
+			}
 		}
 
 		// Update database mtime to reflect it's now in sync with JSONL
@@ -791,6 +878,37 @@ func detectPrefixFromIssues(issues []*types.Issue) string {
 	return commonPrefix
 }
 
+// detectJSONLFormatVersion returns the format version the input was written
+// with, taken from the file's export manifest. Files without a manifest
+// (and stdin) predate format versioning and are version 1; upgrading a
+// record that is already current leaves it unchanged.
+func detectJSONLFormatVersion(input string) int {
+	if input != "" {
+		manifest, err := export.ReadManifest(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if manifest != nil && manifest.FormatVersion > 0 {
+			return manifest.FormatVersion
+		}
+	}
+	return 1
+}
+
+// upgradeJSONLLine rewrites one JSONL record from an older format version
+// to the current one.
+func upgradeJSONLLine(line []byte, fromVersion int) ([]byte, error) {
+	var record map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber() // keep large integers (timeouts, IDs) exact
+	if err := dec.Decode(&record); err != nil {
+		return nil, err
+	}
+	if err := importer.UpgradeRecord(record, fromVersion); err != nil {
+		return nil, err
+	}
+	return json.Marshal(record)
+}
+
 func init() {
 	importCmd.Flags().StringP("input", "i", "", "Input file (default: stdin)")
 	importCmd.Flags().BoolP("skip-existing", "s", false, "Skip existing issues instead of updating them")
@@ -803,6 +921,10 @@ func init() {
 	importCmd.Flags().Bool("force", false, "Force metadata update even when database is already in sync with JSONL")
 	importCmd.Flags().Bool("protect-left-snapshot", false, "Protect issues in left snapshot from git-history-backfill")
 	importCmd.Flags().Bool("no-git-history", false, "Skip git history backfill for deletions (passed by bd sync)")
+	importCmd.Flags().Bool("interactive", false, "Resolve conflicting issues field by field (three-way: base, local, incoming)")
+	importCmd.Flags().String("strategy-file", "", "Apply conflict resolutions from a JSON/YAML file (non-interactive, for CI)")
+	importCmd.Flags().String("validate-schema", "", "Validate records against the JSONL schema: strict (reject) or warn")
+	importCmd.Flags().Lookup("validate-schema").NoOptDefVal = "strict"
 	importCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output import statistics in JSON format")
 	rootCmd.AddCommand(importCmd)
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/beads/internal/export"
 	"github.com/steveyegge/beads/internal/types"
 )
 
@@ -105,3 +106,24 @@ func execCmd(t *testing.T, name string, args ...string) string {
 	}
 	return string(out)
 }
+
+func TestDetectJSONLFormatVersion(t *testing.T) {
+	jsonlPath := filepath.Join(t.TempDir(), "issues.jsonl")
+
+	// No manifest: the file predates format versioning
+	if got := detectJSONLFormatVersion(jsonlPath); got != 1 {
+		t.Errorf("without manifest = %d, want 1", got)
+	}
+	if got := detectJSONLFormatVersion(""); got != 1 {
+		t.Errorf("stdin = %d, want 1", got)
+	}
+
+	// A manifest beside the file gives the version
+	manifest := export.NewManifest(export.PolicyStrict)
+	if err := export.WriteManifest(jsonlPath, manifest); err != nil {
+		t.Fatalf("WriteManifest: %v", err)
+	}
+	if got := detectJSONLFormatVersion(jsonlPath); got != types.JSONLFormatVersion {
+		t.Errorf("from manifest = %d, want %d", got, types.JSONLFormatVersion)
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/steveyegge/beads/internal/importer"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"golang.org/x/term"
+)
+
+// conflictResolutionsFile stores field-level choices made interactively, so
+// the same conflict is resolved the same way on the next import or sync.
+// It lives in .beads/ and is local to the clone.
+const conflictResolutionsFile = "conflict-resolutions.json"
+
+// conflictResolver picks per-field resolutions for import conflicts: first
+// from the strategy file, then from previously recorded choices, then (in
+// interactive mode) by asking.
+type conflictResolver struct {
+	strategy    *importer.ResolutionSet
+	recorded    *importer.ResolutionSet
+	interactive bool
+
+	fromStrategy int
+	fromRecorded int
+	prompted     int
+	dirty        bool // recorded has new choices to save
+}
+
+// choose returns the choices for one conflicting issue and whether every
+// conflicting field got one.
+func (r *conflictResolver) choose(c *importer.IssueConflict) (map[string]importer.ConflictSide, bool, error) {
+	choices := make(map[string]importer.ConflictSide)
+	var open []importer.FieldConflict
+	source := ""
+	for _, fc := range c.Fields {
+		if side, ok := r.strategy.Choice(c.ID, fc); ok {
+			choices[fc.Field] = side
+			source = "strategy"
+			continue
+		}
+		if side, ok := r.recorded.Choice(c.ID, fc); ok {
+			choices[fc.Field] = side
+			if source == "" {
+				source = "recorded"
+			}
+			continue
+		}
+		open = append(open, fc)
+	}
+
+	if len(open) > 0 && r.interactive {
+		picked, err := promptIssueConflict(c, open)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, fc := range open {
+			side, ok := picked[fc.Field]
+			if !ok {
+				continue
+			}
+			choices[fc.Field] = side
+			r.recorded.Record(c.ID, fc, side)
+			r.dirty = true
+		}
+		if len(picked) > 0 {
+			r.prompted++
+		}
+		return choices, len(picked) == len(open), nil
+	}
+
+	switch source {
+	case "strategy":
+		r.fromStrategy++
+	case "recorded":
+		r.fromRecorded++
+	}
+	return choices, len(open) == 0, nil
+}
+
+// summary describes where resolutions came from, e.g.
+// "2 from strategy file, 1 recorded".
+func (r *conflictResolver) summary() string {
+	var parts []string
+	if r.fromStrategy > 0 {
+		parts = append(parts, fmt.Sprintf("%d from strategy file", r.fromStrategy))
+	}
+	if r.fromRecorded > 0 {
+		parts = append(parts, fmt.Sprintf("%d recorded", r.fromRecorded))
+	}
+	if r.prompted > 0 {
+		parts = append(parts, fmt.Sprintf("%d interactive", r.prompted))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// newConflictResolver loads the strategy file (if any) and the recorded
+// choices from beadsDir.
+func newConflictResolver(beadsDir, strategyFile string, interactive bool) (*conflictResolver, error) {
+	if interactive && !(term.IsTerminal(int(os.Stdin.Fd())) && ui.IsTerminal()) {
+		return nil, fmt.Errorf("--interactive requires a terminal (use --strategy-file in CI)")
+	}
+	r := &conflictResolver{interactive: interactive}
+	var err error
+	if strategyFile != "" {
+		if _, statErr := os.Stat(strategyFile); statErr != nil {
+			return nil, fmt.Errorf("strategy file: %w", statErr)
+		}
+		if r.strategy, err = importer.LoadResolutionSet(strategyFile); err != nil {
+			return nil, err
+		}
+	}
+	if r.recorded, err = importer.LoadResolutionSet(filepath.Join(beadsDir, conflictResolutionsFile)); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// saveRecorded persists interactive choices made during this run.
+func (r *conflictResolver) saveRecorded(beadsDir string) error {
+	if !r.dirty {
+		return nil
+	}
+	return r.recorded.Save(filepath.Join(beadsDir, conflictResolutionsFile))
+}
+
+// resolveImportConflicts finds field-level conflicts between the incoming
+// issues and the database and rewrites the incoming issues according to the
+// chosen resolutions, so the regular import applies them. It runs when
+// --interactive or --strategy-file is given, or when choices were recorded
+// by an earlier interactive import.
+func resolveImportConflicts(ctx context.Context, s storage.Storage, issues []*types.Issue, jsonlPath, strategyFile string, interactive, dryRun bool) error {
+	beadsDir := filepath.Dir(dbPath)
+	if !interactive && strategyFile == "" {
+		if _, err := os.Stat(filepath.Join(beadsDir, conflictResolutionsFile)); err != nil {
+			return nil
+		}
+	}
+
+	resolver, err := newConflictResolver(beadsDir, strategyFile, interactive)
+	if err != nil {
+		return err
+	}
+
+	base := loadConflictBase(jsonlPath)
+	conflicts, err := importer.FindConflicts(ctx, s, issues, base)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	if base == nil {
+		fmt.Fprintf(os.Stderr, "Warning: no common base found for %s (no merge in progress and no sync snapshot); every field that differs is treated as a conflict\n", jsonlPath)
+	}
+
+	resolved := 0
+	for _, c := range conflicts {
+		choices, _, err := resolver.choose(c)
+		if err != nil {
+			return err
+		}
+		if len(choices) == 0 {
+			continue
+		}
+		c.Resolve(choices)
+		resolved++
+	}
+
+	if !dryRun {
+		if err := resolver.saveRecorded(beadsDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record conflict resolutions: %v\n", err)
+		}
+	}
+	if resolved > 0 {
+		fmt.Fprintf(os.Stderr, "Resolved %d of %d conflicting issue(s) field-by-field (%s)\n", resolved, len(conflicts), resolver.summary())
+	}
+	return nil
+}
+
+// loadConflictBase returns the common-ancestor version of each issue in
+// jsonlPath: the merge base stage during a conflicted merge, the merge base
+// of HEAD and ORIG_HEAD after a pull, or else the base snapshot saved at the
+// last import. HEAD is never used: it usually matches the working JSONL, so
+// it would make every incoming change look uncontested. Returns nil if no
+// base is available, in which case conflicts are two-way.
+func loadConflictBase(jsonlPath string) map[string]*types.Issue {
+	if jsonlPath == "" {
+		return nil
+	}
+	dir := filepath.Dir(jsonlPath)
+	git := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("git", args...) // #nosec G204 - fixed git subcommands with repo-relative paths
+		cmd.Dir = dir
+		return cmd.Output()
+	}
+
+	if rel, err := git("ls-files", "--full-name", "--", filepath.Base(jsonlPath)); err == nil && len(bytes.TrimSpace(rel)) > 0 {
+		relPath := strings.TrimSpace(string(rel))
+		refs := []string{":1"}
+		if mb, err := git("merge-base", "HEAD", "ORIG_HEAD"); err == nil {
+			refs = append(refs, strings.TrimSpace(string(mb)))
+		}
+		for _, ref := range refs {
+			if data, err := git("show", ref+":"+relPath); err == nil {
+				return parseIssueLines(data)
+			}
+		}
+	}
+
+	basePath, _ := NewSnapshotManager(jsonlPath).GetSnapshotPaths()
+	// #nosec G304 - snapshot path derived from the JSONL path
+	if data, err := os.ReadFile(basePath); err == nil {
+		return parseIssueLines(data)
+	}
+	return nil
+}
+
+// parseIssueLines parses JSONL content into a map by issue ID, skipping
+// lines that don't parse.
+func parseIssueLines(data []byte) map[string]*types.Issue {
+	issues := make(map[string]*types.Issue)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var issue types.Issue
+		if err := json.Unmarshal(line, &issue); err != nil {
+			continue
+		}
+		issue.SetDefaults()
+		issues[issue.ID] = &issue
+	}
+	return issues
+}
+
+// promptIssueConflict shows a three-way view of one issue's conflicting
+// fields and asks how to resolve them, either for the whole issue or field
+// by field. An empty result means the user skipped the issue.
+func promptIssueConflict(c *importer.IssueConflict, fields []importer.FieldConflict) (map[string]importer.ConflictSide, error) {
+	fmt.Printf("\n%s %s\n", ui.RenderWarn("Conflict:"), ui.RenderID(c.ID))
+	for _, fc := range fields {
+		fmt.Printf("  %s\n", ui.RenderBold(fc.Field))
+		if fc.HasBase {
+			fmt.Printf("    base:     %s\n", conflictPreview(fc.Base))
+		}
+		fmt.Printf("    local:    %s\n", conflictPreview(fc.Local))
+		fmt.Printf("    incoming: %s\n", conflictPreview(fc.Incoming))
+	}
+
+	mode := "fields"
+	if len(fields) > 1 {
+		err := huh.NewSelect[string]().
+			Title(fmt.Sprintf("Resolve %s", c.ID)).
+			Options(
+				huh.NewOption("Choose per field", "fields"),
+				huh.NewOption("Keep local for all fields", string(importer.SideLocal)),
+				huh.NewOption("Take incoming for all fields", string(importer.SideIncoming)),
+				huh.NewOption("Skip (use normal import rules)", "skip"),
+			).
+			Value(&mode).
+			WithTheme(huh.ThemeDracula()).
+			Run()
+		if err != nil {
+			return nil, fmt.Errorf("prompt canceled: %w", err)
+		}
+	}
+
+	choices := make(map[string]importer.ConflictSide)
+	switch mode {
+	case "skip":
+		return choices, nil
+	case string(importer.SideLocal), string(importer.SideIncoming):
+		for _, fc := range fields {
+			choices[fc.Field] = importer.ConflictSide(mode)
+		}
+		return choices, nil
+	}
+
+	for _, fc := range fields {
+		options := []huh.Option[string]{
+			huh.NewOption("local: "+conflictPreview(fc.Local), string(importer.SideLocal)),
+			huh.NewOption("incoming: "+conflictPreview(fc.Incoming), string(importer.SideIncoming)),
+		}
+		if fc.HasBase {
+			options = append(options, huh.NewOption("base: "+conflictPreview(fc.Base), string(importer.SideBase)))
+		}
+		options = append(options, huh.NewOption("skip", ""))
+
+		var side string
+		err := huh.NewSelect[string]().
+			Title(fmt.Sprintf("%s.%s", c.ID, fc.Field)).
+			Options(options...).
+			Value(&side).
+			WithTheme(huh.ThemeDracula()).
+			Run()
+		if err != nil {
+			return nil, fmt.Errorf("prompt canceled: %w", err)
+		}
+		if side != "" {
+			choices[fc.Field] = importer.ConflictSide(side)
+		}
+	}
+	return choices, nil
+}
+
+// conflictPreview renders a field value on one line for the conflict view.
+func conflictPreview(v string) string {
+	if v == "" {
+		return ui.RenderMuted("(empty)")
+	}
+	v = strings.Join(strings.Fields(v), " ")
+	if runes := []rune(v); len(runes) > 70 {
+		v = string(runes[:67]) + "..."
+	}
+	return v
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/steveyegge/beads/internal/ui"
 	"github.com/steveyegge/beads/internal/util"
 	"github.com/steveyegge/beads/internal/validation"
+	"github.com/steveyegge/beads/internal/views"
 )
 
 // storageExecutor handles operations that need to work with both direct store and daemon mode
@@ -396,55 +397,7 @@ func watchIssues(ctx context.Context, store storage.Storage, filter types.IssueF
 
 // sortIssues sorts a slice of issues by the specified field and direction
 func sortIssues(issues []*types.Issue, sortBy string, reverse bool) {
-	if sortBy == "" {
-		return
-	}
-
-	slices.SortFunc(issues, func(a, b *types.Issue) int {
-		var result int
-
-		switch sortBy {
-		case "priority":
-			// Lower priority numbers come first (P0 > P1 > P2 > P3 > P4)
-			result = cmp.Compare(a.Priority, b.Priority)
-		case "created":
-			// Default: newest first (descending)
-			result = b.CreatedAt.Compare(a.CreatedAt)
-		case "updated":
-			// Default: newest first (descending)
-			result = b.UpdatedAt.Compare(a.UpdatedAt)
-		case "closed":
-			// Default: newest first (descending)
-			// Handle nil ClosedAt values
-			if a.ClosedAt == nil && b.ClosedAt == nil {
-				result = 0
-			} else if a.ClosedAt == nil {
-				result = 1 // nil sorts last
-			} else if b.ClosedAt == nil {
-				result = -1 // non-nil sorts before nil
-			} else {
-				result = b.ClosedAt.Compare(*a.ClosedAt)
-			}
-		case "status":
-			result = cmp.Compare(a.Status, b.Status)
-		case "id":
-			result = cmp.Compare(a.ID, b.ID)
-		case "title":
-			result = cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
-		case "type":
-			result = cmp.Compare(a.IssueType, b.IssueType)
-		case "assignee":
-			result = cmp.Compare(a.Assignee, b.Assignee)
-		default:
-			// Unknown sort field, no sorting
-			result = 0
-		}
-
-		if reverse {
-			return -result
-		}
-		return result
-	})
+	views.Sort(issues, sortBy, reverse)
 }
 
 // formatIssueLong formats a single issue in long format to a buffer
@@ -520,6 +473,14 @@ var listCmd = &cobra.Command{
 	GroupID: "issues",
 	Short:   "List issues",
 	Run: func(cmd *cobra.Command, args []string) {
+		// Saved view: its flags apply unless given on the command line
+		if viewName, _ := cmd.Flags().GetString("view"); viewName != "" {
+			if err := applyView(cmd, viewName, views.CommandList); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
 		status, _ := cmd.Flags().GetString("status")
 		assignee, _ := cmd.Flags().GetString("assignee")
 		issueType, _ := cmd.Flags().GetString("type")
@@ -605,6 +566,16 @@ var listCmd = &cobra.Command{
 		// Ready filter (bd-ihu31)
 		readyFlag, _ := cmd.Flags().GetBool("ready")
 
+		// Table columns (saved with views)
+		columns, _ := cmd.Flags().GetStringSlice("columns")
+		columns = util.NormalizeLabels(columns)
+		for _, c := range columns {
+			if !views.IsColumn(c) {
+				fmt.Fprintf(os.Stderr, "Error: unknown column %q (valid: %s)\n", c, strings.Join(views.Columns, ", "))
+				os.Exit(1)
+			}
+		}
+
 		// Watch mode implies pretty format
 		if watchMode {
 			prettyFormat = true
@@ -849,6 +820,17 @@ var listCmd = &cobra.Command{
 			filter.Overdue = true
 		}
 
+		// Town scope: fan out to every rig routed from routes.jsonl
+		if townScope, _ := cmd.Flags().GetBool("town"); townScope {
+			// Each rig returns every match; the limit applies after merging
+			rigFilter := filter
+			rigFilter.Limit = 0
+			runTownIssues(func(ctx context.Context, store storage.Storage) ([]*types.Issue, error) {
+				return store.SearchIssues(ctx, "", rigFilter)
+			}, sortBy, reverse, effectiveLimit)
+			return
+		}
+
 		// Check database freshness before reading
 		// Skip check when using daemon (daemon auto-imports on staleness)
 		ctx := rootCtx
@@ -1071,6 +1053,8 @@ var listCmd = &cobra.Command{
 				for _, issue := range issues {
 					formatIssueLong(&buf, issue, issue.Labels)
 				}
+			} else if len(columns) > 0 {
+				formatIssueColumns(&buf, issues, columns)
 			} else {
 				// Compact format: one line per issue
 				for _, issue := range issues {
@@ -1219,6 +1203,11 @@ var listCmd = &cobra.Command{
 				labels := labelsMap[issue.ID]
 				formatIssueLong(&buf, issue, labels)
 			}
+		} else if len(columns) > 0 {
+			for _, issue := range issues {
+				issue.Labels = labelsMap[issue.ID]
+			}
+			formatIssueColumns(&buf, issues, columns)
 		} else {
 			// Compact format: one line per issue
 			for _, issue := range issues {
@@ -1317,6 +1306,11 @@ func init() {
 
 	// Ready filter: show only issues ready to be worked on (bd-ihu31)
 	listCmd.Flags().Bool("ready", false, "Show only ready issues (status=open, excludes hooked/in_progress/blocked/deferred)")
+	listCmd.Flags().Bool("town", false, townFlagUsage)
+
+	// Saved views (bd view save) and table output
+	listCmd.Flags().String("view", "", "Run a saved view (see bd view); flags given here override the view's")
+	listCmd.Flags().StringSlice("columns", nil, "Show a table of these columns: "+strings.Join(views.Columns, ", "))
 
 	// Note: --json flag is defined as a persistent flag in main.go, not here
 	rootCmd.AddCommand(listCmd)
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage"
 )
 
 // mailCmd delegates to an external mail provider.
@@ -88,6 +90,13 @@ Examples:
 // findMailDelegate checks for mail delegation configuration
 // Priority: env vars > bd config
 func findMailDelegate() string {
+	// This works even without a database connection since we use direct mode
+	return mailDelegateFor(rootCtx, store)
+}
+
+// mailDelegateFor returns the mail delegate command from the environment or
+// s's config, for callers (like the daemon) that have their own store.
+func mailDelegateFor(ctx context.Context, s storage.Storage) string {
 	// Check environment variables first
 	if delegate := os.Getenv("BEADS_MAIL_DELEGATE"); delegate != "" {
 		return delegate
@@ -97,9 +106,8 @@ func findMailDelegate() string {
 	}
 
 	// Check bd config (requires database)
-	// This works even without a database connection since we use direct mode
-	if store != nil {
-		if delegate, err := store.GetConfig(rootCtx, "mail.delegate"); err == nil && delegate != "" {
+	if s != nil {
+		if delegate, err := s.GetConfig(ctx, "mail.delegate"); err == nil && delegate != "" {
 			return delegate
 		}
 	}
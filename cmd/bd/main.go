@@ -356,11 +356,13 @@ var rootCmd = &cobra.Command{
 			"init",
 			"merge",
 			"onboard",
+			"policy",
 			"powershell",
 			"prime",
 			"quickstart",
 			"repair",
 			"resolve-conflicts",
+			"schema",
 			"setup",
 			"version",
 			"zsh",
@@ -377,6 +379,13 @@ var rootCmd = &cobra.Command{
 			return
 		}
 
+		// --town queries open every rig's database themselves (town.go), so
+		// the current directory doesn't need one
+		if townScope, err := cmd.Flags().GetBool("town"); err == nil && townScope && cmdName != "activity" {
+			actor = getActorWithGit()
+			return
+		}
+
 		// Skip for root command with no subcommand (just shows help)
 		if cmd.Parent() == nil && cmdName == "bd" {
 			return
@@ -568,7 +577,7 @@ var rootCmd = &cobra.Command{
 			Connected:        false,
 			Degraded:         true,
 			SocketPath:       socketPath,
-			AutoStartEnabled: shouldAutoStartDaemon(),
+			AutoStartEnabled: shouldAutoStartDaemon() && !isMuxSocket(socketPath),
 			FallbackReason:   FallbackNone,
 		}
 
@@ -631,7 +640,8 @@ var rootCmd = &cobra.Command{
 						_ = client.Close()
 
 						// Kill old daemon and restart with new version
-						if restartDaemonForVersionMismatch() {
+						// (never the shared multiplex daemon)
+						if !isMuxSocket(socketPath) && restartDaemonForVersionMismatch() {
 							// Retry connection after restart
 							client, err = rpc.TryConnect(socketPath)
 							if err == nil && client != nil {
@@ -804,7 +814,8 @@ var rootCmd = &cobra.Command{
 			doltPath := filepath.Join(beadsDir, "dolt")
 			store, err = factory.NewWithOptions(rootCtx, backend, doltPath, opts)
 		} else {
-			// SQLite backend
+			// SQLite and PostgreSQL backends (Postgres connection failures
+			// are reported below)
 			store, err = factory.NewWithOptions(rootCtx, backend, dbPath, opts)
 			if err != nil && useReadOnly {
 				// If read-only fails (e.g., DB doesn't exist), fall back to read-write
@@ -825,6 +836,9 @@ var rootCmd = &cobra.Command{
 			if handleFreshCloneError(err, beadsDir) {
 				os.Exit(1)
 			}
+			if handlePostgresConnectError(err, beadsDir) {
+				os.Exit(1)
+			}
 			fmt.Fprintf(os.Stderr, "Error: failed to open database: %v\n", err)
 			os.Exit(1)
 		}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/configfile"
+	"github.com/steveyegge/beads/internal/storage/postgres"
 )
 
 // isFreshCloneError checks if the error is due to a fresh clone scenario
@@ -70,6 +73,22 @@ func handleFreshCloneError(err error, beadsDir string) bool {
 	return true
 }
 
+// handlePostgresConnectError explains a failed connection to the postgres
+// backend and returns true if err was one (so caller should exit).
+// There is no local database to fall back to, so bd can't continue.
+func handlePostgresConnectError(err error, beadsDir string) bool {
+	if !errors.Is(err, postgres.ErrUnreachable) {
+		return false
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: cannot connect to the PostgreSQL server\n\n")
+	fmt.Fprintf(os.Stderr, "  %v\n\n", err)
+	fmt.Fprintf(os.Stderr, "This workspace stores its issues in PostgreSQL (see %s).\n", configfile.ConfigPath(beadsDir))
+	fmt.Fprintf(os.Stderr, "Check that the server is running and that the postgres settings there\n")
+	fmt.Fprintf(os.Stderr, "(or %s, which overrides them) are correct.\n", configfile.PostgresURLEnv)
+	return true
+}
+
 // isWispOperation returns true if the command operates on ephemeral wisps.
 // Wisp operations auto-bypass the daemon because wisps are local-only
 // (Ephemeral=true issues are never exported to JSONL).
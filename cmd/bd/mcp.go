@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/mcp"
+	"github.com/steveyegge/beads/internal/rpc"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:     "mcp",
+	GroupID: "setup",
+	Short:   "Model Context Protocol server",
+	Long: `Serve beads to MCP clients (editors and agents) without the Python
+beads-mcp package.
+
+Register it with a client as a stdio server, for example:
+
+  claude mcp add beads -- bd mcp serve`,
+}
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Speak the Model Context Protocol over stdio",
+	Long: `Serve the Model Context Protocol over stdin/stdout.
+
+Tools: ready, list, show, create, update, close, dep, comment. Their input
+schemas are derived from the daemon RPC arguments, so they accept the same
+fields as the corresponding bd commands.
+
+Resources:
+  beads://prime        Workflow context for agents (bd prime --mcp, or PRIME.md)
+  beads://prime/full   Full CLI reference (bd prime --full)
+
+Requests go through the daemon when one is running and straight to the
+database otherwise (changes are flushed to JSONL as usual). Nothing but
+protocol messages is written to stdout.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exec, stop, err := newMCPExecutor()
+		if err != nil {
+			return err
+		}
+		defer stop()
+		return newMCPServer(exec).Serve(rootCtx, os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	mcpCmd.AddCommand(mcpServeCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
+
+// mcpExecutor runs one RPC operation.
+type mcpExecutor func(op string, args interface{}) (*rpc.Response, error)
+
+// newMCPExecutor uses the daemon client when connected. Otherwise it
+// serves requests with an in-process RPC server over the direct-mode store
+// and flushes its mutations to JSONL; stop shuts that down.
+func newMCPExecutor() (mcpExecutor, func(), error) {
+	if daemonClient != nil {
+		return daemonClient.Execute, func() {}, nil
+	}
+	if err := ensureStoreActive(); err != nil {
+		return nil, nil, err
+	}
+
+	workspace := ""
+	if beadsDir := beads.FindBeadsDir(); beadsDir != "" {
+		workspace = filepath.Dir(beadsDir)
+	}
+	local := rpc.NewLocalServer(store, workspace, dbPath)
+	ctx, cancel := context.WithCancel(rootCtx)
+	go func() {
+		for {
+			select {
+			case <-local.MutationChan():
+				markDirtyAndScheduleFlush()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	exec := func(op string, args interface{}) (*rpc.Response, error) {
+		data, err := json.Marshal(args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal args: %w", err)
+		}
+		cwd, _ := os.Getwd()
+		resp := local.Handle(&rpc.Request{
+			Operation:     op,
+			Args:          data,
+			Actor:         actor,
+			ClientVersion: rpc.ClientVersion,
+			Cwd:           cwd,
+			ExpectedDB:    dbPath,
+		})
+		return &resp, nil
+	}
+	return exec, cancel, nil
+}
+
+// mcpToolSpec describes a tool backed by one RPC operation.
+type mcpToolSpec struct {
+	name         string
+	description  string
+	op           string
+	newArgs      func() interface{}
+	descriptions map[string]string
+	defaults     map[string]interface{}
+	omit         []string
+}
+
+func mcpToolSpecs() []mcpToolSpec {
+	return []mcpToolSpec{
+		{
+			name:        "ready",
+			description: "Find open issues with no blockers, ready to be worked on.",
+			op:          rpc.OpReady,
+			newArgs:     func() interface{} { return &rpc.ReadyArgs{} },
+			descriptions: map[string]string{
+				"limit":       "Maximum number of issues to return",
+				"sort_policy": "hybrid (default), priority or oldest",
+			},
+		},
+		{
+			name:        "list",
+			description: "List issues matching filters. Closed issues are included only when status is given.",
+			op:          rpc.OpList,
+			newArgs:     func() interface{} { return &rpc.ListArgs{} },
+			descriptions: map[string]string{
+				"query":  "Text to search for in titles, descriptions and IDs",
+				"status": "open, in_progress, blocked, deferred or closed",
+			},
+		},
+		{
+			name:         "show",
+			description:  "Show an issue with its dependencies, dependents, labels and comments.",
+			op:           rpc.OpShow,
+			newArgs:      func() interface{} { return &rpc.ShowArgs{} },
+			descriptions: map[string]string{"id": "Issue ID"},
+		},
+		{
+			name:        "create",
+			description: "Create an issue.",
+			op:          rpc.OpCreate,
+			newArgs:     func() interface{} { return &rpc.CreateArgs{} },
+			descriptions: map[string]string{
+				"priority":     "0 (critical) to 4 (backlog)",
+				"issue_type":   "bug, feature, task, epic or chore",
+				"dependencies": "Dependencies as \"type:id\" or \"id\" (blocks)",
+			},
+			defaults: map[string]interface{}{"issue_type": "task", "priority": 2},
+			omit:     []string{"created_by", "id_prefix"},
+		},
+		{
+			name:         "update",
+			description:  "Update fields of an issue. Only the given fields change.",
+			op:           rpc.OpUpdate,
+			newArgs:      func() interface{} { return &rpc.UpdateArgs{} },
+			descriptions: map[string]string{"id": "Issue ID", "claim": "Atomically assign to you and mark in_progress"},
+		},
+		{
+			name:         "close",
+			description:  "Close an issue.",
+			op:           rpc.OpClose,
+			newArgs:      func() interface{} { return &rpc.CloseArgs{} },
+			descriptions: map[string]string{"id": "Issue ID", "suggest_next": "Also return issues unblocked by this close"},
+			omit:         []string{"session"},
+		},
+		{
+			name:         "dep",
+			description:  "Add a dependency: from_id depends on to_id.",
+			op:           rpc.OpDepAdd,
+			newArgs:      func() interface{} { return &rpc.DepAddArgs{} },
+			descriptions: map[string]string{"dep_type": "blocks, related, parent-child or discovered-from"},
+			defaults:     map[string]interface{}{"dep_type": "blocks"},
+		},
+		{
+			name:         "comment",
+			description:  "Add a comment to an issue.",
+			op:           rpc.OpCommentAdd,
+			newArgs:      func() interface{} { return &rpc.CommentAddArgs{} },
+			descriptions: map[string]string{"id": "Issue ID"},
+			defaults:     map[string]interface{}{"author": actor},
+		},
+	}
+}
+
+// newMCPServer registers the bd tools and prime resources on an MCP server.
+func newMCPServer(exec mcpExecutor) *mcp.Server {
+	s := mcp.NewServer("beads", Version,
+		"Beads is this project's issue tracker. Use the ready tool to find work, "+
+			"and read beads://prime for the workflow.")
+
+	for _, spec := range mcpToolSpecs() {
+		spec := spec
+		s.AddTool(mcp.Tool{
+			Name:        spec.name,
+			Description: spec.description,
+			InputSchema: mcp.InputSchema(spec.newArgs(), spec.descriptions, spec.omit...),
+			Defaults:    spec.defaults,
+			Handler: func(_ context.Context, raw json.RawMessage) (string, error) {
+				args := spec.newArgs()
+				dec := json.NewDecoder(bytes.NewReader(raw))
+				dec.DisallowUnknownFields()
+				if err := dec.Decode(args); err != nil {
+					return "", fmt.Errorf("invalid arguments: %w", err)
+				}
+				resp, err := exec(spec.op, args)
+				if err != nil {
+					return "", err
+				}
+				if !resp.Success {
+					return "", fmt.Errorf("%s", resp.Error)
+				}
+				return string(resp.Data), nil
+			},
+		})
+	}
+
+	s.AddResource(mcp.Resource{
+		URI:         "beads://prime",
+		Name:        "Beads workflow context",
+		Description: "Session workflow for agents (bd prime --mcp, or the project's PRIME.md)",
+		MIMEType:    "text/markdown",
+		Read:        func(context.Context) (string, error) { return mcpPrimeContext(true) },
+	})
+	s.AddResource(mcp.Resource{
+		URI:         "beads://prime/full",
+		Name:        "Beads CLI reference",
+		Description: "Full command reference (bd prime --full)",
+		MIMEType:    "text/markdown",
+		Read:        func(context.Context) (string, error) { return mcpPrimeContext(false) },
+	})
+	return s
+}
+
+// mcpPrimeContext renders what bd prime would print.
+func mcpPrimeContext(mcpMode bool) (string, error) {
+	if beadsDir := beads.FindBeadsDir(); beadsDir != "" {
+		if content, ok := readPrimeOverride(beadsDir); ok {
+			return content, nil
+		}
+	}
+	var buf bytes.Buffer
+	if err := outputPrimeContext(&buf, mcpMode, config.GetBool("no-git-ops")); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
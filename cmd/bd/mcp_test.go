@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/rpc"
+)
+
+func TestMCPServerTools(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".beads", "beads.db")
+	s := newTestStore(t, path)
+	local := rpc.NewLocalServer(s, dir, path)
+	exec := func(op string, args interface{}) (*rpc.Response, error) {
+		data, err := json.Marshal(args)
+		if err != nil {
+			return nil, err
+		}
+		resp := local.Handle(&rpc.Request{Operation: op, Args: data, Actor: "test", ClientVersion: rpc.ClientVersion, ExpectedDB: path})
+		return &resp, nil
+	}
+
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"create","arguments":{"title":"From MCP","description":"via tool"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"ready","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"show","arguments":{"id":"test-1","bogus":true}}}`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := newMCPServer(exec).Serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	type reply struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	var replies []reply
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var r reply
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Error != nil {
+			t.Fatalf("request %d failed: %s", r.ID, r.Error.Message)
+		}
+		replies = append(replies, r)
+	}
+	if len(replies) != 5 {
+		t.Fatalf("got %d responses, want 5 (notifications get none)", len(replies))
+	}
+
+	var tools struct {
+		Tools []struct {
+			Name        string                 `json:"name"`
+			InputSchema map[string]interface{} `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(replies[1].Result, &tools); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+		if tool.Name == "create" {
+			props := tool.InputSchema["properties"].(map[string]interface{})
+			if _, ok := props["created_by"]; ok {
+				t.Error("create schema should not expose created_by")
+			}
+			if props["issue_type"].(map[string]interface{})["default"] != "task" {
+				t.Errorf("issue_type default = %v, want task", props["issue_type"])
+			}
+		}
+	}
+	if got := strings.Join(names, ","); got != "close,comment,create,dep,list,ready,show,update" {
+		t.Errorf("tools = %s", got)
+	}
+
+	type toolResult struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	var created, ready, shown toolResult
+	for i, dst := range []*toolResult{&created, &ready, &shown} {
+		if err := json.Unmarshal(replies[i+2].Result, dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if created.IsError || !strings.Contains(created.Content[0].Text, `"title":"From MCP"`) {
+		t.Errorf("create result = %+v", created)
+	}
+	if ready.IsError || !strings.Contains(ready.Content[0].Text, "From MCP") {
+		t.Errorf("ready result = %+v, want the created issue", ready)
+	}
+	if !shown.IsError || !strings.Contains(shown.Content[0].Text, "bogus") {
+		t.Errorf("unknown argument should fail the tool call, got %+v", shown)
+	}
+}
@@ -37,6 +37,16 @@ Designed to work as a git merge driver. Configure with:
 
 Or use 'bd init' which automatically configures the merge driver.
 
+Per-field strategies in config.yaml override the built-in rules when both
+sides changed a field:
+
+  merge:
+    fields:
+      assignee: left          # lww | union | concat | max | min | left | right | conflict
+      description: conflict   # leave as a conflict for 'bd resolve-conflicts'
+      status: max             # furthest along status-order wins
+    status-order: [open, in_progress, blocked, closed]
+
 Exit codes:
   0 - Merge successful (no conflicts)
   1 - Merge completed with conflicts (conflict markers in output)
@@ -78,7 +88,13 @@ Vendored into bd with permission.`,
 			cleanupMergeArtifacts(outputPath, debugMerge)
 		}()
 
-		err := merge.Merge3Way(outputPath, basePath, leftPath, rightPath, debugMerge)
+		policy, err := merge.LoadPolicy()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+
+		err = merge.Merge3WayWithPolicy(outputPath, basePath, leftPath, rightPath, policy, debugMerge)
 		if err != nil {
 			// Check if error is due to conflicts
 			if err.Error() == fmt.Sprintf("merge completed with %d conflicts", 1) ||
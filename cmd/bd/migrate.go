@@ -33,6 +33,10 @@ Without subcommand, detects and migrates database schema to current version:
 - Updates schema version metadata
 - Removes stale databases (with confirmation)
 
+With --to postgres, copies the SQLite database into an empty PostgreSQL
+database (--dsn, BEADS_POSTGRES_URL or PG* environment variables) and
+switches metadata.json to the postgres backend. The SQLite file is kept.
+
 Subcommands:
   hash-ids    Migrate sequential IDs to hash-based IDs (legacy)
   issues      Move issues between repositories
@@ -44,6 +48,8 @@ Subcommands:
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		updateRepoID, _ := cmd.Flags().GetBool("update-repo-id")
 		inspect, _ := cmd.Flags().GetBool("inspect")
+		toBackend, _ := cmd.Flags().GetString("to")
+		dsn, _ := cmd.Flags().GetString("dsn")
 
 		// Block writes in readonly mode (migration modifies data, --inspect is read-only)
 		if !dryRun && !inspect {
@@ -62,6 +68,12 @@ Subcommands:
 			return
 		}
 
+		// Handle --to (backend migration)
+		if toBackend != "" {
+			handleMigrateToBackend(toBackend, dsn, dryRun)
+			return
+		}
+
 		// Find .beads directory
 		beadsDir := beads.FindBeadsDir()
 		if beadsDir == "" {
@@ -974,6 +986,8 @@ func init() {
 	migrateCmd.Flags().Bool("dry-run", false, "Show what would be done without making changes")
 	migrateCmd.Flags().Bool("update-repo-id", false, "Update repository ID (use after changing git remote)")
 	migrateCmd.Flags().Bool("inspect", false, "Show migration plan and database state for AI agent analysis")
+	migrateCmd.Flags().String("to", "", "Copy the database to another backend and switch to it (postgres)")
+	migrateCmd.Flags().String("dsn", "", "Connection URL for --to postgres (default: BEADS_POSTGRES_URL or PG* env vars)")
 	migrateCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output migration statistics in JSON format")
 	rootCmd.AddCommand(migrateCmd)
 }
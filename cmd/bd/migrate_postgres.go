@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/configfile"
+	"github.com/steveyegge/beads/internal/storage/postgres"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+// migrateFail reports a migration error in the migrate command's JSON or
+// text style and exits.
+func migrateFail(code, message string) {
+	if jsonOutput {
+		outputJSON(map[string]interface{}{
+			"error":   code,
+			"message": message,
+		})
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", message)
+	}
+	os.Exit(1)
+}
+
+// handleMigrateToBackend dispatches bd migrate --to <backend>.
+func handleMigrateToBackend(target, dsn string, dryRun bool) {
+	switch target {
+	case configfile.BackendPostgres:
+		handleMigrateToPostgres(dsn, dryRun)
+	default:
+		migrateFail("unsupported_target", fmt.Sprintf("unsupported migration target %q (supported: postgres)", target))
+	}
+}
+
+// handleMigrateToPostgres copies the current SQLite database into an empty
+// PostgreSQL database and switches metadata.json to the postgres backend.
+// The SQLite file is left in place as a backup.
+func handleMigrateToPostgres(dsn string, dryRun bool) {
+	beadsDir := beads.FindBeadsDir()
+	if beadsDir == "" {
+		migrateFail("no_beads_directory", "No .beads directory found. Run 'bd init' first.")
+	}
+
+	cfg, err := loadOrCreateConfig(beadsDir)
+	if err != nil {
+		migrateFail("config_load_failed", err.Error())
+	}
+	if backend := cfg.GetBackend(); backend != configfile.BackendSQLite {
+		migrateFail("unsupported_source", fmt.Sprintf("migration to postgres requires the sqlite backend (current: %s)", backend))
+	}
+
+	sourcePath := cfg.DatabasePath(beadsDir)
+	if _, err := os.Stat(sourcePath); err != nil {
+		migrateFail("no_database", fmt.Sprintf("source database not found: %s", sourcePath))
+	}
+
+	if dsn == "" {
+		dsn = cfg.PostgresDSN()
+	}
+
+	// Work out what to persist in metadata.json. Passwords are never
+	// written; keyword/value DSNs can't be split, so they stay in the env.
+	var pgConfig *configfile.PostgresConfig
+	var warnings []string
+	if dsn != "" {
+		parsed, hasPassword, err := configfile.ParsePostgresURL(dsn)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("connection string not saved (%v); set %s for future commands", err, configfile.PostgresURLEnv))
+		} else {
+			pgConfig = parsed
+			if hasPassword {
+				warnings = append(warnings, "password not saved to metadata.json; use PGPASSWORD or ~/.pgpass")
+			}
+		}
+	}
+
+	ctx := rootCtx
+	src, err := sqlite.NewReadOnly(ctx, sourcePath)
+	if err != nil {
+		migrateFail("open_failed", fmt.Sprintf("failed to open source database: %v", err))
+	}
+	defer func() { _ = src.Close() }()
+
+	if dryRun {
+		issues, err := src.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true})
+		if err != nil {
+			migrateFail("read_failed", err.Error())
+		}
+		if jsonOutput {
+			outputJSON(map[string]interface{}{
+				"dry_run":  true,
+				"source":   sourcePath,
+				"target":   configfile.BackendPostgres,
+				"issues":   len(issues),
+				"warnings": warnings,
+			})
+		} else {
+			fmt.Println("Dry run mode - no changes will be made")
+			fmt.Printf("Would copy %d issues from %s to postgres\n", len(issues), sourcePath)
+			fmt.Printf("Would set backend to postgres in %s\n", configfile.ConfigPath(beadsDir))
+			for _, w := range warnings {
+				fmt.Printf("  %s %s\n", ui.RenderWarn("⚠"), w)
+			}
+		}
+		return
+	}
+
+	target, err := postgres.New(ctx, &postgres.Config{
+		DSN:  dsn,
+		Path: (&configfile.Config{Backend: configfile.BackendPostgres}).DatabasePath(beadsDir),
+	})
+	if err != nil {
+		migrateFail("connect_failed", fmt.Sprintf("failed to open postgres database: %v", err))
+	}
+	defer func() { _ = target.Close() }()
+
+	stats, err := target.CopyFrom(ctx, src)
+	if err != nil {
+		migrateFail("copy_failed", err.Error())
+	}
+
+	cfg.Backend = configfile.BackendPostgres
+	cfg.Postgres = pgConfig
+	if err := cfg.Save(beadsDir); err != nil {
+		migrateFail("config_save_failed", fmt.Sprintf("data was copied but metadata.json was not updated: %v", err))
+	}
+
+	if jsonOutput {
+		outputJSON(map[string]interface{}{
+			"status":   "success",
+			"source":   sourcePath,
+			"target":   configfile.BackendPostgres,
+			"stats":    stats,
+			"warnings": warnings,
+		})
+		return
+	}
+
+	fmt.Printf("%s Migrated to postgres\n", ui.RenderPass("✓"))
+	fmt.Printf("  Issues: %d, labels: %d, dependencies: %d, comments: %d, events: %d\n",
+		stats.Issues, stats.Labels, stats.Dependencies, stats.Comments, stats.Events)
+	if stats.Orphans > 0 {
+		fmt.Printf("  Skipped %d orphaned rows\n", stats.Orphans)
+	}
+	for _, w := range warnings {
+		fmt.Printf("  %s %s\n", ui.RenderWarn("⚠"), w)
+	}
+	fmt.Printf("  The SQLite database was kept at %s\n", sourcePath)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/policy"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var policyCmd = &cobra.Command{
+	Use:     "policy",
+	GroupID: "setup",
+	Short:   "Inspect actor authorization policies and issue actor tokens",
+	Long: `Inspect the actor policies in .beads/policy.yaml and issue actor tokens.
+
+The daemon checks every RPC operation against the policy before running it.
+Rules map actor glob patterns to allowed and denied operations (or the
+groups read, write and admin), and can restrict the issue types an actor may
+create or modify and the labels it may add or remove. The first rule whose
+actors match decides; actors matching no rule get 'default' (allow unless
+set to deny). Denials are recorded in .beads/interactions.jsonl.
+'bd claim --from-ready' skips ready issues whose type the actor's rule does
+not allow.
+
+Example policy.yaml:
+
+  default: allow
+  rules:
+    - name: polecats
+      actors: ["*/polecats/*"]
+      allow: [read, create, update, close, comment_add]
+      deny: [delete]
+      issue_types: [task, bug, chore]
+      labels: ["area:*"]
+
+The daemon does not trust the actor the client reports (--actor, BD_ACTOR)
+while a policy is present. Each request must carry an actor token issued
+from .beads/policy.key, and the policy is checked against the actor the
+token names. Processes that can read policy.key mint their own token; give
+sandboxed agents one with 'bd policy token <actor>' in BD_ACTOR_TOKEN and
+keep policy.key out of the sandbox. Deleting policy.key revokes every token.
+Policies are enforced by the daemon only; direct-mode commands (--no-daemon)
+are not checked.`,
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check <actor> <operation>",
+	Short: "Dry-run a policy decision",
+	Long: `Show whether an actor may perform an RPC operation under .beads/policy.yaml.
+
+Operations use daemon operation names (create, update, close, delete,
+label_add, compact, shutdown, ...). Use --type and --label to include the
+issue types and labels the operation would touch.
+
+Examples:
+  bd policy check gastown/polecats/nux delete
+  bd policy check gastown/polecats/nux update --type epic
+  bd policy check gastown/polecats/nux label_add --type task --label area:cli`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		actor, op := args[0], args[1]
+		issueTypes, _ := cmd.Flags().GetStringSlice("type")
+		labels, _ := cmd.Flags().GetStringSlice("label")
+
+		groups, ok := rpc.PolicyOperationGroups(op)
+		if !ok {
+			FatalErrorRespectJSON("unknown or unchecked operation %q", op)
+		}
+
+		beadsDir := beads.FindBeadsDir()
+		if beadsDir == "" {
+			FatalErrorRespectJSON("no .beads directory found")
+		}
+		path := filepath.Join(beadsDir, policy.FileName)
+		p, err := policy.Load(path)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		decision := p.Check(policy.Request{
+			Actor:      actor,
+			Operation:  op,
+			Groups:     groups,
+			IssueTypes: issueTypes,
+			Labels:     labels,
+		})
+
+		if jsonOutput {
+			outputJSON(struct {
+				Actor     string `json:"actor"`
+				Operation string `json:"operation"`
+				Policy    string `json:"policy,omitempty"`
+				policy.Decision
+			}{actor, op, policyPathIfLoaded(path, p), decision})
+			return
+		}
+
+		if p == nil {
+			fmt.Printf("%s No %s found; all operations are allowed\n", ui.RenderMuted("○"), policy.FileName)
+			return
+		}
+		if decision.Allowed {
+			fmt.Printf("%s %s may %s (rule %s)\n", ui.RenderPass("✓"), actor, op, decision.Rule)
+		} else {
+			fmt.Printf("%s %s may not %s (rule %s: %s)\n", ui.RenderFail("✗"), actor, op, decision.Rule, decision.Reason)
+		}
+	},
+}
+
+var policyTokenCmd = &cobra.Command{
+	Use:   "token <actor>",
+	Short: "Issue an actor token for policy enforcement",
+	Long: `Print a token that authenticates requests as <actor> to the daemon.
+
+Pass it to a sandboxed agent as BD_ACTOR_TOKEN. The token is derived from
+.beads/policy.key, which is created on first use; delete that file to
+revoke every issued token.
+
+Example:
+  BD_ACTOR_TOKEN=$(bd policy token gastown/polecats/nux)`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		actor := args[0]
+		beadsDir := beads.FindBeadsDir()
+		if beadsDir == "" {
+			FatalErrorRespectJSON("no .beads directory found")
+		}
+		key, err := policy.LoadOrCreateKey(beadsDir)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		token := policy.IssueToken(key, actor)
+
+		if jsonOutput {
+			outputJSON(struct {
+				Actor string `json:"actor"`
+				Token string `json:"token"`
+			}{actor, token})
+			return
+		}
+		fmt.Println(token)
+	},
+}
+
+func policyPathIfLoaded(path string, p *policy.Policy) string {
+	if p == nil {
+		return ""
+	}
+	return path
+}
+
+func init() {
+	policyCheckCmd.Flags().StringSlice("type", nil, "Issue type(s) the operation touches")
+	policyCheckCmd.Flags().StringSlice("label", nil, "Label(s) the operation adds or removes")
+	policyCmd.AddCommand(policyCheckCmd)
+	policyCmd.AddCommand(policyTokenCmd)
+	rootCmd.AddCommand(policyCmd)
+}
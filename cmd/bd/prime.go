@@ -97,19 +97,8 @@ Workflow customization:
 		// This allows users to fully customize workflow instructions
 		// Check local .beads/ first (even if redirected), then redirected location
 		if !primeExportMode {
-			localPrimePath := filepath.Join(".beads", "PRIME.md")
-			redirectedPrimePath := filepath.Join(beadsDir, "PRIME.md")
-
-			// Try local first (user's clone-specific customization)
-			// #nosec G304 -- path is relative to cwd
-			if content, err := os.ReadFile(localPrimePath); err == nil {
-				fmt.Print(string(content))
-				return
-			}
-			// Fall back to redirected location (shared customization)
-			// #nosec G304 -- path is constructed from beadsDir which we control
-			if content, err := os.ReadFile(redirectedPrimePath); err == nil {
-				fmt.Print(string(content))
+			if content, ok := readPrimeOverride(beadsDir); ok {
+				fmt.Print(content)
 				return
 			}
 		}
@@ -131,6 +120,21 @@ func init() {
 	rootCmd.AddCommand(primeCmd)
 }
 
+// readPrimeOverride returns the contents of a custom PRIME.md, checking the
+// local .beads/ first (user's clone-specific customization) and then the
+// redirected beads directory (shared customization).
+func readPrimeOverride(beadsDir string) (string, bool) {
+	// #nosec G304 -- path is relative to cwd
+	if content, err := os.ReadFile(filepath.Join(".beads", "PRIME.md")); err == nil {
+		return string(content), true
+	}
+	// #nosec G304 -- path is constructed from beadsDir which we control
+	if content, err := os.ReadFile(filepath.Join(beadsDir, "PRIME.md")); err == nil {
+		return string(content), true
+	}
+	return "", false
+}
+
 // isMCPActive detects if MCP server is currently active
 func isMCPActive() bool {
 	// Get home directory with fallback
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/beads/internal/ui"
 	"github.com/steveyegge/beads/internal/util"
 	"github.com/steveyegge/beads/internal/utils"
+	"github.com/steveyegge/beads/internal/views"
 )
 
 var readyCmd = &cobra.Command{
@@ -26,8 +27,19 @@ Use --mol to filter to a specific molecule's steps:
 Use --gated to find molecules ready for gate-resume dispatch:
   bd ready --gated           # Find molecules where a gate closed
 
-This is useful for agents executing molecules to see which steps can run next.`,
+This is useful for agents executing molecules to see which steps can run next.
+
+Use --town to see ready work in every rig of an orchestrator town:
+  bd ready --town            # Cross-rig blockers are resolved against their rig`,
 	Run: func(cmd *cobra.Command, args []string) {
+		// Saved view: its flags apply unless given on the command line
+		if viewName, _ := cmd.Flags().GetString("view"); viewName != "" {
+			if err := applyView(cmd, viewName, views.CommandReady); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
 		// Handle --gated flag (gate-resume discovery)
 		gated, _ := cmd.Flags().GetBool("gated")
 		if gated {
@@ -85,6 +97,7 @@ This is useful for agents executing molecules to see which steps can run next.`,
 			Labels:          labels,
 			LabelsAny:       labelsAny,
 			IncludeDeferred: includeDeferred, // GH#820: respect --include-deferred flag
+			LeaseHolder:     actor,           // Hide issues claimed by other agents (bd claim)
 		}
 		// Use Changed() to properly handle P0 (priority=0)
 		if cmd.Flags().Changed("priority") {
@@ -105,6 +118,11 @@ This is useful for agents executing molecules to see which steps can run next.`,
 			fmt.Fprintf(os.Stderr, "Error: invalid sort policy '%s'. Valid values: hybrid, priority, oldest\n", sortPolicy)
 			os.Exit(1)
 		}
+		// Town scope: fan out to every rig routed from routes.jsonl
+		if townScope, _ := cmd.Flags().GetBool("town"); townScope {
+			runTownReady(filter)
+			return
+		}
 		// If daemon is running, use RPC
 		if daemonClient != nil {
 			readyArgs := &rpc.ReadyArgs{
@@ -265,6 +283,16 @@ var blockedCmd = &cobra.Command{
 	Short: "Show blocked issues",
 	Run: func(cmd *cobra.Command, args []string) {
 		// Use global jsonOutput set by PersistentPreRun (respects config.yaml + env vars)
+		parentID, _ := cmd.Flags().GetString("parent")
+		var blockedFilter types.WorkFilter
+		if parentID != "" {
+			blockedFilter.ParentID = &parentID
+		}
+		// Town scope: fan out to every rig routed from routes.jsonl
+		if townScope, _ := cmd.Flags().GetBool("town"); townScope {
+			runTownBlocked(blockedFilter)
+			return
+		}
 		// If daemon is running but doesn't support this command, use direct storage
 		ctx := rootCtx
 		if daemonClient != nil && store == nil {
@@ -276,11 +304,6 @@ var blockedCmd = &cobra.Command{
 			}
 			defer func() { _ = store.Close() }()
 		}
-		parentID, _ := cmd.Flags().GetString("parent")
-		var blockedFilter types.WorkFilter
-		if parentID != "" {
-			blockedFilter.ParentID = &parentID
-		}
 		blocked, err := store.GetBlockedIssues(ctx, blockedFilter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -462,7 +485,10 @@ func init() {
 	readyCmd.Flags().Bool("pretty", false, "Display issues in a tree format with status/priority symbols")
 	readyCmd.Flags().Bool("include-deferred", false, "Include issues with future defer_until timestamps")
 	readyCmd.Flags().Bool("gated", false, "Find molecules ready for gate-resume dispatch")
+	readyCmd.Flags().Bool("town", false, townFlagUsage)
+	readyCmd.Flags().String("view", "", "Run a saved ready view (see bd view); flags given here override the view's")
 	rootCmd.AddCommand(readyCmd)
 	blockedCmd.Flags().String("parent", "", "Filter to descendants of this bead/epic")
+	blockedCmd.Flags().Bool("town", false, townFlagUsage)
 	rootCmd.AddCommand(blockedCmd)
 }
@@ -107,7 +107,7 @@ func collectResetItems(gitCommonDir, beadsDir string) []resetItem {
 	}
 
 	// Check for git hooks (hooks are in common git dir, shared across worktrees)
-	hookNames := []string{"pre-commit", "post-merge", "pre-push", "post-checkout"}
+	hookNames := []string{"pre-commit", "post-merge", "pre-push", "post-checkout", "post-commit"}
 	hooksDir := filepath.Join(gitCommonDir, "hooks")
 	for _, hookName := range hookNames {
 		hookPath := filepath.Join(hooksDir, hookName)
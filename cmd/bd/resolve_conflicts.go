@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/importer"
 	"github.com/steveyegge/beads/internal/merge"
+	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/ui"
 )
 
@@ -25,7 +27,14 @@ resolves the conflicts using beads merge semantics.
 
 Modes:
   mechanical (default)  Uses deterministic merge rules (updated_at wins, etc.)
-  interactive           Prompts for each conflict (not yet implemented)
+  interactive           Shows each conflicting issue field by field (base from
+                        the merge, ours as local, theirs as incoming) and asks
+                        which version to keep
+
+Choices made interactively are recorded in .beads/conflict-resolutions.json
+and reused by later runs and by 'bd import'. --strategy-file applies choices
+from a file without prompting; conflicts it doesn't cover are resolved
+mechanically.
 
 The file defaults to .beads/beads.jsonl if not specified.
 
@@ -33,7 +42,9 @@ Examples:
   bd resolve-conflicts                    # Resolve conflicts in .beads/beads.jsonl
   bd resolve-conflicts --dry-run          # Show what would be resolved
   bd resolve-conflicts custom.jsonl       # Resolve conflicts in custom file
-  bd resolve-conflicts --json             # Output results as JSON`,
+  bd resolve-conflicts --json             # Output results as JSON
+  bd resolve-conflicts --interactive      # Pick field values for each conflict
+  bd resolve-conflicts --strategy-file ci-resolutions.yaml`,
 	Args: cobra.MaximumNArgs(1),
 	// PreRun disables PersistentPreRun for this command (no database needed)
 	PreRun: func(cmd *cobra.Command, args []string) {},
@@ -45,6 +56,9 @@ var (
 	resolveConflictsDryRun bool
 	resolveConflictsJSON   bool
 	resolveConflictsPath   string
+
+	resolveConflictsInteractive  bool
+	resolveConflictsStrategyFile string
 )
 
 func init() {
@@ -52,6 +66,8 @@ func init() {
 	resolveConflictsCmd.Flags().BoolVar(&resolveConflictsDryRun, "dry-run", false, "Show what would be resolved without making changes")
 	resolveConflictsCmd.Flags().BoolVar(&resolveConflictsJSON, "json", false, "Output results as JSON")
 	resolveConflictsCmd.Flags().StringVar(&resolveConflictsPath, "path", ".", "Path to repository with .beads directory")
+	resolveConflictsCmd.Flags().BoolVar(&resolveConflictsInteractive, "interactive", false, "Shorthand for --mode interactive")
+	resolveConflictsCmd.Flags().StringVar(&resolveConflictsStrategyFile, "strategy-file", "", "Apply field-level resolutions from a JSON/YAML file")
 	rootCmd.AddCommand(resolveConflictsCmd)
 }
 
@@ -82,8 +98,9 @@ type conflictResolutionInfo struct {
 	LineRange  string `json:"line_range"`
 	LeftLabel  string `json:"left_label"`
 	RightLabel string `json:"right_label"`
-	Resolution string `json:"resolution"` // "merged", "left", "right", "both"
-	IssueID    string `json:"issue_id,omitempty"`
+	Resolution string   `json:"resolution"` // "merged", "left", "right", "both"
+	IssueID    string   `json:"issue_id,omitempty"`
+	Fields     []string `json:"fields,omitempty"` // Fields that differ between the two sides
 }
 
 // validateResolveConflictsPath validates that file path is safe for conflict resolution.
@@ -154,11 +171,32 @@ func runResolveConflicts(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	if resolveConflictsMode == "interactive" {
-		outputResolveError(filePath, "interactive mode not yet implemented")
+	if resolveConflictsInteractive {
+		resolveConflictsMode = "interactive"
+	}
+	interactive := resolveConflictsMode == "interactive"
+	if interactive && resolveConflictsJSON {
+		outputResolveError(filePath, "interactive mode cannot be combined with --json")
 		os.Exit(1)
 	}
 
+	if _, err := merge.LoadPolicy(); err != nil {
+		outputResolveError(filePath, err.Error())
+		os.Exit(1)
+	}
+
+	// Field-level resolution (interactive, strategy file, recorded choices)
+	beadsDir := filepath.Join(resolveConflictsPath, ".beads")
+	var resolver *conflictResolver
+	if interactive || resolveConflictsStrategyFile != "" {
+		var err error
+		resolver, err = newConflictResolver(beadsDir, resolveConflictsStrategyFile, interactive)
+		if err != nil {
+			outputResolveError(filePath, err.Error())
+			os.Exit(1)
+		}
+	}
+
 	// Check file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		outputResolveError(filePath, fmt.Sprintf("file not found: %s", filePath))
@@ -208,12 +246,30 @@ func runResolveConflicts(cmd *cobra.Command, args []string) {
 	var resolvedLines []string
 	resolvedLines = append(resolvedLines, cleanLines...)
 
+	var base map[string]*types.Issue
+	if resolver != nil {
+		base = loadConflictBase(filePath)
+	}
+
 	for i, conflict := range conflicts {
-		resolution, info := resolveConflict(conflict, i+1)
+		var resolution []string
+		var info conflictResolutionInfo
+		resolvedByField := false
+		if resolver != nil {
+			var err error
+			resolution, info, resolvedByField, err = resolveConflictByField(conflict, base, resolver)
+			if err != nil {
+				outputResolveError(filePath, err.Error())
+				os.Exit(1)
+			}
+		}
+		if !resolvedByField {
+			resolution, info = resolveConflict(conflict, i+1)
+		}
 		result.Conflicts = append(result.Conflicts, info)
 
 		if !resolveConflictsJSON && !resolveConflictsDryRun {
-			fmt.Printf("  Conflict %d (lines %d-%d): %s\n", i+1, conflict.StartLine, conflict.EndLine, info.Resolution)
+			fmt.Printf("  Conflict %d (lines %d-%d): %s%s\n", i+1, conflict.StartLine, conflict.EndLine, info.Resolution, conflictFieldsSuffix(info))
 		}
 
 		resolvedLines = append(resolvedLines, resolution...)
@@ -232,12 +288,19 @@ func runResolveConflicts(cmd *cobra.Command, args []string) {
 				if info.IssueID != "" {
 					fmt.Printf(" (issue: %s)", info.IssueID)
 				}
+				fmt.Print(conflictFieldsSuffix(info))
 				fmt.Println()
 			}
 		}
 		return
 	}
 
+	if resolver != nil {
+		if err := resolver.saveRecorded(beadsDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record conflict resolutions: %v\n", err)
+		}
+	}
+
 	// Create backup
 	backupPath := filePath + ".pre-resolve"
 	if err := copyFile(filePath, backupPath); err != nil {
@@ -270,6 +333,15 @@ func runResolveConflicts(cmd *cobra.Command, args []string) {
 	}
 }
 
+// conflictFieldsSuffix lists the fields a merged conflict differed on, e.g.
+// " [description, assignee]".
+func conflictFieldsSuffix(info conflictResolutionInfo) string {
+	if len(info.Fields) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(info.Fields, ", ") + "]"
+}
+
 // parseConflicts extracts conflict regions and non-conflicted lines from content
 func parseConflicts(content string) ([]conflictRegion, []string, error) {
 	var conflicts []conflictRegion
@@ -335,39 +407,82 @@ func parseConflicts(content string) ([]conflictRegion, []string, error) {
 	return conflicts, cleanLines, scanner.Err()
 }
 
-// resolveConflict resolves a single conflict region using merge semantics
-func resolveConflict(conflict conflictRegion, _ int) ([]string, conflictResolutionInfo) {
+// resolveConflictByField resolves a conflict region holding one issue on
+// each side using field-level choices. ok is false if the region isn't a
+// single issue per side or some conflicting field got no choice, in which
+// case the caller falls back to mechanical resolution.
+func resolveConflictByField(conflict conflictRegion, base map[string]*types.Issue, resolver *conflictResolver) ([]string, conflictResolutionInfo, bool, error) {
 	info := conflictResolutionInfo{
 		LineRange:  fmt.Sprintf("%d-%d", conflict.StartLine, conflict.EndLine),
 		LeftLabel:  conflict.LeftLabel,
 		RightLabel: conflict.RightLabel,
 	}
 
-	// Try to parse left and right as JSON issues
-	var leftIssues, rightIssues []merge.Issue
+	left := parseIssueLines([]byte(strings.Join(conflict.LeftSide, "\n")))
+	right := parseIssueLines([]byte(strings.Join(conflict.RightSide, "\n")))
+	if len(left) != 1 || len(right) != 1 {
+		return nil, info, false, nil
+	}
+	var local, incoming *types.Issue
+	for _, issue := range left {
+		local = issue
+	}
+	for _, issue := range right {
+		incoming = issue
+	}
+	if local.ID != incoming.ID {
+		return nil, info, false, nil
+	}
+	info.IssueID = local.ID
 
-	for _, line := range conflict.LeftSide {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		var issue merge.Issue
-		if err := json.Unmarshal([]byte(line), &issue); err == nil {
-			issue.RawLine = line
-			leftIssues = append(leftIssues, issue)
-		}
+	c := &importer.IssueConflict{
+		ID:       local.ID,
+		Base:     base[local.ID],
+		Local:    local,
+		Incoming: incoming,
+		Fields:   importer.DiffIssueFields(base[local.ID], local, incoming),
+	}
+	if len(c.Fields) == 0 {
+		return nil, info, false, nil
 	}
+	choices, complete, err := resolver.choose(c)
+	if err != nil || !complete {
+		return nil, info, false, err
+	}
+	c.Resolve(choices)
 
-	for _, line := range conflict.RightSide {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		var issue merge.Issue
-		if err := json.Unmarshal([]byte(line), &issue); err == nil {
-			issue.RawLine = line
-			rightIssues = append(rightIssues, issue)
+	// Keep relationships added on either side, as the mechanical merge does
+	c.Incoming.Labels = unionStrings(incoming.Labels, local.Labels)
+	seen := make(map[string]bool)
+	for _, dep := range incoming.Dependencies {
+		seen[dep.DependsOnID+":"+string(dep.Type)] = true
+	}
+	for _, dep := range local.Dependencies {
+		if !seen[dep.DependsOnID+":"+string(dep.Type)] {
+			c.Incoming.Dependencies = append(c.Incoming.Dependencies, dep)
 		}
 	}
 
+	data, err := json.Marshal(c.Incoming)
+	if err != nil {
+		return nil, info, false, nil
+	}
+	info.Resolution = "field_level"
+	return []string{string(data)}, info, true, nil
+}
+
+// resolveConflict resolves a single conflict region using merge semantics
+func resolveConflict(conflict conflictRegion, _ int) ([]string, conflictResolutionInfo) {
+	info := conflictResolutionInfo{
+		LineRange:  fmt.Sprintf("%d-%d", conflict.StartLine, conflict.EndLine),
+		LeftLabel:  conflict.LeftLabel,
+		RightLabel: conflict.RightLabel,
+	}
+
+	// Try to parse left and right as JSON issues
+	leftIssues := parseConflictSide(conflict.LeftSide)
+	rightIssues := parseConflictSide(conflict.RightSide)
+
 	// If we couldn't parse as JSON, keep both sides
 	if len(leftIssues) == 0 && len(rightIssues) == 0 {
 		info.Resolution = "kept_both_unparseable"
@@ -381,7 +496,7 @@ func resolveConflict(conflict conflictRegion, _ int) ([]string, conflictResoluti
 	if len(leftIssues) > 0 && len(rightIssues) == 0 {
 		info.Resolution = "left_only_valid"
 		if len(leftIssues) == 1 {
-			info.IssueID = leftIssues[0].ID
+			info.IssueID = leftIssues[0].issue.ID
 		}
 		return conflict.LeftSide, info
 	}
@@ -389,7 +504,7 @@ func resolveConflict(conflict conflictRegion, _ int) ([]string, conflictResoluti
 	if len(rightIssues) > 0 && len(leftIssues) == 0 {
 		info.Resolution = "right_only_valid"
 		if len(rightIssues) == 1 {
-			info.IssueID = rightIssues[0].ID
+			info.IssueID = rightIssues[0].issue.ID
 		}
 		return conflict.RightSide, info
 	}
@@ -401,9 +516,9 @@ func resolveConflict(conflict conflictRegion, _ int) ([]string, conflictResoluti
 
 	for _, left := range leftIssues {
 		// Find matching right issue by ID
-		var matchingRight *merge.Issue
+		var matchingRight *conflictIssue
 		for i := range rightIssues {
-			if rightIssues[i].ID == left.ID {
+			if rightIssues[i].issue.ID == left.issue.ID {
 				matchingRight = &rightIssues[i]
 				break
 			}
@@ -411,27 +526,28 @@ func resolveConflict(conflict conflictRegion, _ int) ([]string, conflictResoluti
 
 		if matchingRight != nil {
 			// Merge the two versions
-			merged := mergeIssueConflict(left, *matchingRight)
+			merged := mergeIssueConflict(left.issue, matchingRight.issue)
 			mergedJSON, err := json.Marshal(merged)
 			if err != nil {
 				// Fall back to left on marshal error
-				result = append(result, left.RawLine)
+				result = append(result, left.raw)
 			} else {
 				result = append(result, string(mergedJSON))
 			}
-			mergedIDs[left.ID] = true
-			info.IssueID = left.ID
+			mergedIDs[left.issue.ID] = true
+			info.IssueID = left.issue.ID
+			info.Fields = merge.DiffFields(left.issue, matchingRight.issue)
 			info.Resolution = "merged"
 		} else {
 			// No matching right issue - keep left
-			result = append(result, left.RawLine)
+			result = append(result, left.raw)
 		}
 	}
 
 	// Add any right issues that weren't merged
 	for _, right := range rightIssues {
-		if !mergedIDs[right.ID] {
-			result = append(result, right.RawLine)
+		if !mergedIDs[right.issue.ID] {
+			result = append(result, right.raw)
 		}
 	}
 
@@ -442,143 +558,49 @@ func resolveConflict(conflict conflictRegion, _ int) ([]string, conflictResoluti
 	return result, info
 }
 
-// mergeIssueConflict merges two conflicting issue versions
-// Uses similar logic to internal/merge but simplified for conflict resolution
-func mergeIssueConflict(left, right merge.Issue) merge.Issue {
-	result := merge.Issue{
-		ID:        left.ID,
-		CreatedAt: left.CreatedAt,
-		CreatedBy: left.CreatedBy,
-	}
-
-	// Title: prefer later updated_at
-	result.Title = pickByUpdatedAt(left.Title, right.Title, left.UpdatedAt, right.UpdatedAt)
-
-	// Description: prefer later updated_at
-	result.Description = pickByUpdatedAt(left.Description, right.Description, left.UpdatedAt, right.UpdatedAt)
-
-	// Notes: concatenate if different
-	if left.Notes == right.Notes {
-		result.Notes = left.Notes
-	} else if left.Notes == "" {
-		result.Notes = right.Notes
-	} else if right.Notes == "" {
-		result.Notes = left.Notes
-	} else {
-		result.Notes = left.Notes + "\n\n---\n\n" + right.Notes
-	}
-
-	// Status: closed wins
-	if left.Status == "closed" || right.Status == "closed" {
-		result.Status = "closed"
-	} else if left.Status == "tombstone" || right.Status == "tombstone" {
-		result.Status = "tombstone"
-	} else {
-		result.Status = pickByUpdatedAt(left.Status, right.Status, left.UpdatedAt, right.UpdatedAt)
-	}
-
-	// Priority: lower number (higher priority) wins
-	if left.Priority != 0 && right.Priority != 0 {
-		if left.Priority < right.Priority {
-			result.Priority = left.Priority
-		} else {
-			result.Priority = right.Priority
-		}
-	} else if left.Priority != 0 {
-		result.Priority = left.Priority
-	} else {
-		result.Priority = right.Priority
-	}
-
-	// IssueType: prefer left
-	if left.IssueType != "" {
-		result.IssueType = left.IssueType
-	} else {
-		result.IssueType = right.IssueType
-	}
-
-	// UpdatedAt: max
-	result.UpdatedAt = maxTimeStr(left.UpdatedAt, right.UpdatedAt)
-
-	// ClosedAt: max (if status is closed)
-	if result.Status == "closed" {
-		result.ClosedAt = maxTimeStr(left.ClosedAt, right.ClosedAt)
-		// CloseReason and ClosedBySession from whichever has later ClosedAt
-		if isTimeAfterStr(left.ClosedAt, right.ClosedAt) {
-			result.CloseReason = left.CloseReason
-			result.ClosedBySession = left.ClosedBySession
-		} else {
-			result.CloseReason = right.CloseReason
-			result.ClosedBySession = right.ClosedBySession
-		}
-	}
+// conflictIssue is one parsed line from a conflict region, with the
+// original text kept for output when the line is not merged.
+type conflictIssue struct {
+	issue merge.Issue
+	raw   string
+}
 
-	// Dependencies: union
-	depMap := make(map[string]merge.Dependency)
-	for _, dep := range left.Dependencies {
-		key := fmt.Sprintf("%s:%s:%s", dep.IssueID, dep.DependsOnID, dep.Type)
-		depMap[key] = dep
-	}
-	for _, dep := range right.Dependencies {
-		key := fmt.Sprintf("%s:%s:%s", dep.IssueID, dep.DependsOnID, dep.Type)
-		if _, exists := depMap[key]; !exists {
-			depMap[key] = dep
+// parseConflictSide parses the JSON issues on one side of a conflict,
+// skipping blank and unparseable lines.
+func parseConflictSide(lines []string) []conflictIssue {
+	var issues []conflictIssue
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
 		}
-	}
-	for _, dep := range depMap {
-		result.Dependencies = append(result.Dependencies, dep)
-	}
-
-	// Tombstone fields
-	if result.Status == "tombstone" {
-		if isTimeAfterStr(left.DeletedAt, right.DeletedAt) {
-			result.DeletedAt = left.DeletedAt
-			result.DeletedBy = left.DeletedBy
-			result.DeleteReason = left.DeleteReason
-			result.OriginalType = left.OriginalType
-		} else {
-			result.DeletedAt = right.DeletedAt
-			result.DeletedBy = right.DeletedBy
-			result.DeleteReason = right.DeleteReason
-			result.OriginalType = right.OriginalType
+		var issue merge.Issue
+		if err := json.Unmarshal([]byte(line), &issue); err == nil {
+			issues = append(issues, conflictIssue{issue: issue, raw: line})
 		}
 	}
-
-	return result
-}
-
-func pickByUpdatedAt(left, right, leftTime, rightTime string) string {
-	if left == right {
-		return left
-	}
-	if isTimeAfterStr(leftTime, rightTime) {
-		return left
-	}
-	return right
+	return issues
 }
 
-func maxTimeStr(t1, t2 string) string {
-	if t1 == "" {
-		return t2
-	}
-	if t2 == "" {
-		return t1
-	}
-	if isTimeAfterStr(t1, t2) {
-		return t1
-	}
-	return t2
+// mergeIssueConflict merges two conflicting issue versions with the merge
+// driver's field rules and the merge: config (there is no base version in a
+// conflict region). The config was validated by runResolveConflicts.
+func mergeIssueConflict(left, right merge.Issue) merge.Issue {
+	policy, _ := merge.LoadPolicy()
+	return merge.MergeIssue(left, right, policy)
 }
 
-func isTimeAfterStr(t1, t2 string) bool {
-	if t1 == "" {
-		return false
+func unionStrings(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	for _, v := range a {
+		seen[v] = true
 	}
-	if t2 == "" {
-		return true
+	for _, v := range b {
+		if !seen[v] {
+			a = append(a, v)
+			seen[v] = true
+		}
 	}
-	// Simple string comparison works for RFC3339 timestamps
-	return t1 > t2
+	return a
 }
 
 func outputResolveError(filePath, errMsg string) {
@@ -2,10 +2,14 @@ package main
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/steveyegge/beads/internal/importer"
 	"github.com/steveyegge/beads/internal/merge"
 )
 
@@ -220,12 +224,12 @@ func TestMergeIssueConflict(t *testing.T) {
 		left := merge.Issue{
 			ID:        "bd-1",
 			Title:     "Old Title",
-			UpdatedAt: "2024-01-01T00:00:00Z",
+			UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 		}
 		right := merge.Issue{
 			ID:        "bd-1",
 			Title:     "New Title",
-			UpdatedAt: "2024-01-02T00:00:00Z",
+			UpdatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
 		}
 
 		result := mergeIssueConflict(left, right)
@@ -289,13 +293,13 @@ func TestMergeIssueConflict(t *testing.T) {
 	t.Run("dependencies union", func(t *testing.T) {
 		left := merge.Issue{
 			ID: "bd-1",
-			Dependencies: []merge.Dependency{
+			Dependencies: []*merge.Dependency{
 				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks"},
 			},
 		}
 		right := merge.Issue{
 			ID: "bd-1",
-			Dependencies: []merge.Dependency{
+			Dependencies: []*merge.Dependency{
 				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "blocks"},
 			},
 		}
@@ -308,65 +312,6 @@ func TestMergeIssueConflict(t *testing.T) {
 	})
 }
 
-func TestTimeHelpers(t *testing.T) {
-	t.Run("isTimeAfterStr", func(t *testing.T) {
-		tests := []struct {
-			t1, t2 string
-			want   bool
-		}{
-			{"2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z", true},
-			{"2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z", false},
-			{"2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z", false},
-			{"2024-01-01T00:00:00Z", "", true},
-			{"", "2024-01-01T00:00:00Z", false},
-			{"", "", false},
-		}
-
-		for _, tt := range tests {
-			got := isTimeAfterStr(tt.t1, tt.t2)
-			if got != tt.want {
-				t.Errorf("isTimeAfterStr(%q, %q) = %v, want %v", tt.t1, tt.t2, got, tt.want)
-			}
-		}
-	})
-
-	t.Run("maxTimeStr", func(t *testing.T) {
-		tests := []struct {
-			t1, t2, want string
-		}{
-			{"2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z"},
-			{"2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z", "2024-01-02T00:00:00Z"},
-			{"2024-01-01T00:00:00Z", "", "2024-01-01T00:00:00Z"},
-			{"", "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z"},
-			{"", "", ""},
-		}
-
-		for _, tt := range tests {
-			got := maxTimeStr(tt.t1, tt.t2)
-			if got != tt.want {
-				t.Errorf("maxTimeStr(%q, %q) = %q, want %q", tt.t1, tt.t2, got, tt.want)
-			}
-		}
-	})
-
-	t.Run("pickByUpdatedAt", func(t *testing.T) {
-		tests := []struct {
-			left, right, leftTime, rightTime, want string
-		}{
-			{"A", "B", "2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z", "A"},
-			{"A", "B", "2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z", "B"},
-			{"Same", "Same", "2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z", "Same"},
-		}
-
-		for _, tt := range tests {
-			got := pickByUpdatedAt(tt.left, tt.right, tt.leftTime, tt.rightTime)
-			if got != tt.want {
-				t.Errorf("pickByUpdatedAt(%q, %q, ...) = %q, want %q", tt.left, tt.right, got, tt.want)
-			}
-		}
-	})
-}
-
 func TestResolveConflictsEndToEnd(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "bd-resolve-test-*")
 	if err != nil {
@@ -421,3 +366,94 @@ func TestResolveConflictsEndToEnd(t *testing.T) {
 		t.Errorf("Expected resolved content to contain 'Local version', got %q", resolved[0])
 	}
 }
+
+func TestResolveConflictByField(t *testing.T) {
+	conflict := conflictRegion{
+		StartLine: 1,
+		EndLine:   5,
+		LeftSide: []string{
+			`{"id":"bd-1","title":"Ours","description":"Our desc","status":"open","priority":1,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-03T00:00:00Z","labels":["a"]}`,
+		},
+		RightSide: []string{
+			`{"id":"bd-1","title":"Theirs","description":"Their desc","status":"open","priority":1,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-02T00:00:00Z","labels":["b"]}`,
+		},
+	}
+
+	resolver := &conflictResolver{
+		strategy: &importer.ResolutionSet{Resolutions: []importer.Resolution{
+			{IssueID: "bd-1", Field: "title", Choice: importer.SideIncoming},
+			{IssueID: "bd-1", Field: "description", Choice: importer.SideLocal},
+		}},
+		recorded: &importer.ResolutionSet{},
+	}
+
+	lines, info, ok, err := resolveConflictByField(conflict, nil, resolver)
+	if err != nil || !ok {
+		t.Fatalf("resolveConflictByField = ok %v, err %v", ok, err)
+	}
+	if info.Resolution != "field_level" || info.IssueID != "bd-1" || len(lines) != 1 {
+		t.Fatalf("unexpected result: %+v %v", info, lines)
+	}
+	for _, want := range []string{`"title":"Theirs"`, `"description":"Our desc"`, `"labels":["b","a"]`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("resolved line missing %s: %s", want, lines[0])
+		}
+	}
+
+	// Without a choice for every field, fall back to mechanical resolution
+	resolver.strategy = &importer.ResolutionSet{Resolutions: []importer.Resolution{
+		{IssueID: "bd-1", Field: "title", Choice: importer.SideIncoming},
+	}}
+	if _, _, ok, err := resolveConflictByField(conflict, nil, resolver); ok || err != nil {
+		t.Errorf("expected fallback for incomplete strategy, got ok %v, err %v", ok, err)
+	}
+}
+
+func TestLoadConflictBase(t *testing.T) {
+	dir := t.TempDir()
+	if err := setupGitRepoInDir(t, dir); err != nil {
+		t.Fatalf("setupGitRepoInDir: %v", err)
+	}
+
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	jsonlPath := filepath.Join(beadsDir, "issues.jsonl")
+	committed := `{"id":"bd-1","title":"Committed","status":"open","priority":1,"issue_type":"task"}` + "\n"
+	if err := os.WriteFile(jsonlPath, []byte(committed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", ".beads/issues.jsonl"}, {"commit", "-m", "add issues"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	// No merge in progress and no snapshot: HEAD matches the working file,
+	// so it must not be taken as the base
+	if base := loadConflictBase(jsonlPath); base != nil {
+		t.Fatalf("loadConflictBase without merge or snapshot = %v, want nil", base)
+	}
+
+	// The snapshot of the last import is used instead
+	snapshot := `{"id":"bd-1","title":"Last import","status":"open","priority":1,"issue_type":"task"}` + "\n"
+	basePath, _ := NewSnapshotManager(jsonlPath).GetSnapshotPaths()
+	if err := os.WriteFile(basePath, []byte(snapshot), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	base := loadConflictBase(jsonlPath)
+	if base["bd-1"] == nil || base["bd-1"].Title != "Last import" {
+		t.Errorf("loadConflictBase with snapshot = %+v, want the snapshot version", base["bd-1"])
+	}
+}
+
+func TestConflictPreviewTruncatesRunes(t *testing.T) {
+	got := conflictPreview(strings.Repeat("é", 80))
+	if !utf8.ValidString(got) {
+		t.Fatalf("conflictPreview split a character: %q", got)
+	}
+	if want := strings.Repeat("é", 67) + "..."; got != want {
+		t.Errorf("conflictPreview = %q, want 67 runes and an ellipsis", got)
+	}
+}
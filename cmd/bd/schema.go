@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:     "schema",
+	GroupID: "sync",
+	Short:   "Show the issues.jsonl record schema",
+	Long: `Show the machine-readable schema for .beads/issues.jsonl.
+
+Each line of issues.jsonl is one issue (with its labels, dependencies and
+comments inlined). The schema is generated from the issue types compiled
+into this bd, so it always matches what 'bd export' writes.`,
+}
+
+var schemaExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the JSONL record format as JSON Schema",
+	Long: `Export the issues.jsonl record format as a JSON Schema (draft 2020-12).
+
+The schema's x-format-version matches the format_version recorded in the
+export manifest and in the database metadata (jsonl_format_version).
+External tools can use it to validate or generate parsers for JSONL files;
+'bd import --validate-schema' checks input against the same schema.
+
+Examples:
+  bd schema export                        # Print to stdout
+  bd schema export -o issue.schema.json   # Write to a file`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		data, err := json.MarshalIndent(types.JSONSchema(), "", "  ")
+		if err != nil {
+			FatalError("marshaling schema: %v", err)
+		}
+		data = append(data, '\n')
+
+		if output == "" || output == "-" {
+			_, _ = os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil { // #nosec G306 - schema is public
+			FatalError("writing %s: %v", output, err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote JSONL schema (format version %d) to %s\n", types.JSONLFormatVersion, output)
+	},
+}
+
+func init() {
+	schemaExportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	schemaCmd.AddCommand(schemaExportCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/util"
 	"github.com/steveyegge/beads/internal/validation"
@@ -172,6 +174,14 @@ Examples:
 			filter.PriorityMax = &priorityMax
 		}
 
+		// Town scope: fan out to every rig routed from routes.jsonl
+		if townScope, _ := cmd.Flags().GetBool("town"); townScope {
+			runTownIssues(func(ctx context.Context, store storage.Storage) ([]*types.Issue, error) {
+				return store.SearchIssues(ctx, query, filter)
+			}, sortBy, reverse, limit)
+			return
+		}
+
 		ctx := rootCtx
 
 		// Check database freshness before reading (skip when using daemon)
@@ -392,6 +402,7 @@ func init() {
 	// Priority range flags
 	searchCmd.Flags().String("priority-min", "", "Filter by minimum priority (inclusive, 0-4 or P0-P4)")
 	searchCmd.Flags().String("priority-max", "", "Filter by maximum priority (inclusive, 0-4 or P0-P4)")
+	searchCmd.Flags().Bool("town", false, townFlagUsage)
 
 	rootCmd.AddCommand(searchCmd)
 }
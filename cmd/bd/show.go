@@ -135,6 +135,7 @@ var showCmd = &cobra.Command{
 						details.Dependents, _ = sqliteStore.GetDependentsWithMetadata(ctx, issue.ID)
 					}
 					details.Comments, _ = issueStore.GetIssueComments(ctx, issue.ID)
+					details.Commits, _ = issueStore.GetCommitLinks(ctx, issue.ID)
 					// Compute parent from dependencies
 					for _, dep := range details.Dependencies {
 						if dep.DependencyType == types.DepParentChild {
@@ -308,6 +309,11 @@ var showCmd = &cobra.Command{
 						}
 					}
 
+					if len(details.Commits) > 0 {
+						fmt.Printf("\n%s\n", ui.RenderBold("COMMITS"))
+						printCommitLinks(details.Commits)
+					}
+
 					fmt.Println()
 				}
 			}
@@ -376,6 +382,7 @@ var showCmd = &cobra.Command{
 				}
 
 				details.Comments, _ = issueStore.GetIssueComments(ctx, issue.ID)
+				details.Commits, _ = issueStore.GetCommitLinks(ctx, issue.ID)
 				// Compute parent from dependencies
 				for _, dep := range details.Dependencies {
 					if dep.DependencyType == types.DepParentChild {
@@ -514,6 +521,13 @@ var showCmd = &cobra.Command{
 				}
 			}
 
+			// Show linked commits
+			commits, _ := issueStore.GetCommitLinks(ctx, issue.ID)
+			if len(commits) > 0 {
+				fmt.Printf("\n%s\n", ui.RenderBold("COMMITS"))
+				printCommitLinks(commits)
+			}
+
 			fmt.Println()
 			result.Close() // Close routed storage after each iteration
 		}
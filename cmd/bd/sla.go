@@ -0,0 +1,563 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/analytics"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/gateeval"
+	"github.com/steveyegge/beads/internal/hooks"
+	"github.com/steveyegge/beads/internal/sla"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var slaCmd = &cobra.Command{
+	Use:     "sla",
+	GroupID: "views",
+	Short:   "Response and resolution targets (SLA policies)",
+	Long: `Track open issues against response and resolution targets.
+
+Policies live in config.yaml, keyed by issue type and priority. The most
+specific matching policy applies (type and priority, then type, then
+priority, then a catch-all); policies without a type cover the core work
+types only.
+
+  sla:
+    at-risk: 0.75        # Fraction of a target used before it is at risk
+    policies:
+      - type: bug
+        priority: 0
+        respond: 1h      # Acknowledged: in progress, closed, or commented on
+        resolve: 24h     # Closed
+      - priority: 1
+        resolve: 3d
+
+Clocks start when the issue is created and pause while it is deferred or
+blocked on an unresolved external dependency (external:<project>:<cap>).
+Time spent closed before a reopen doesn't count toward resolution.
+
+When a target is breached, the issue gets an sla:respond-breached or
+sla:resolve-breached label, an event bead is recorded under it, the
+.beads/hooks/on_sla_breach hook runs, and its waiters and assignee are
+mailed through mail.delegate. Each breach is acted on once. The daemon
+checks every minute (disable with daemon.sla-check: false); without a
+daemon, run bd sla check.`,
+}
+
+var slaReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "List issues at risk of breaching or in breach of their SLA",
+	Long: `List open issues that are at risk of breaching an SLA target or have
+breached one, most urgent first. Use --all to include issues on track.
+
+Examples:
+  bd sla report
+  bd sla report --all --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+		all, _ := cmd.Flags().GetBool("all")
+
+		policies, atRisk, err := loadSLAPolicies()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		// Read-only, so read directly even when the daemon is running
+		if daemonClient != nil && store == nil {
+			store, err = sqlite.New(ctx, dbPath)
+			if err != nil {
+				FatalErrorRespectJSON("failed to open database: %v", err)
+			}
+			defer func() { _ = store.Close() }()
+		}
+		if store == nil {
+			FatalErrorRespectJSON("no database connection")
+		}
+
+		entries, err := loadSLAEntries(ctx, store, policies, atRisk, time.Now())
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		var statuses []*sla.Status
+		for _, e := range entries {
+			if all || e.Status.Worst() == sla.StateAtRisk || e.Status.Worst() == sla.StateBreached {
+				statuses = append(statuses, e.Status)
+			}
+		}
+
+		if jsonOutput {
+			if statuses == nil {
+				statuses = []*sla.Status{}
+			}
+			outputJSON(statuses)
+			return
+		}
+		if len(policies) == 0 {
+			fmt.Println("No SLA policies configured (see bd sla --help)")
+			return
+		}
+		if len(statuses) == 0 {
+			fmt.Printf("%s No issues at risk (%d tracked)\n", ui.RenderPass("✓"), len(entries))
+			return
+		}
+		printSLAReport(os.Stdout, statuses, time.Now())
+	},
+}
+
+var slaCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Act on SLA breaches now (direct mode)",
+	Long: `Evaluate SLA policies and act on new breaches: label the issue, record
+an event bead, run the on_sla_breach hook and mail waiters.
+
+The daemon does this every minute, so bd sla check is for direct mode
+(--no-daemon, or no daemon running). Use --dry-run to list the breaches
+that would be acted on without changing anything.
+
+Examples:
+  bd --no-daemon sla check
+  bd sla check --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if !dryRun {
+			CheckReadonly("sla check")
+		}
+
+		policies, atRisk, err := loadSLAPolicies()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if daemonClient != nil {
+			if !dryRun {
+				FatalErrorRespectJSON("the daemon checks SLA policies; use --no-daemon to check now, or --dry-run")
+			}
+			if store == nil {
+				store, err = sqlite.New(ctx, dbPath)
+				if err != nil {
+					FatalErrorRespectJSON("failed to open database: %v", err)
+				}
+				defer func() { _ = store.Close() }()
+			}
+		}
+		if store == nil {
+			FatalErrorRespectJSON("no database connection")
+		}
+
+		entries, err := loadSLAEntries(ctx, store, policies, atRisk, time.Now())
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		actions := newSLAActions(store, actor, hookRunner)
+		type breachResult struct {
+			IssueID string     `json:"issue_id"`
+			Target  sla.Target `json:"target"`
+			Marked  bool       `json:"marked"` // Labeled as breached
+			EventID string     `json:"event_id,omitempty"`
+			Error   string     `json:"error,omitempty"`
+		}
+		results := []breachResult{}
+		for _, e := range entries {
+			for _, c := range e.newBreaches() {
+				r := breachResult{IssueID: e.Issue.ID, Target: c.Target}
+				if !dryRun {
+					if err := actions.mark(ctx, e, c); err != nil {
+						r.Error = err.Error()
+					} else {
+						r.Marked = true
+						if r.EventID, err = actions.record(ctx, e, c); err != nil {
+							r.Error = err.Error()
+						}
+					}
+				}
+				results = append(results, r)
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(results)
+			return
+		}
+		if len(results) == 0 {
+			fmt.Printf("%s No new SLA breaches (%d issues tracked)\n", ui.RenderPass("✓"), len(entries))
+			return
+		}
+		for _, r := range results {
+			switch {
+			case dryRun:
+				fmt.Printf("%s %s %s breached (dry run)\n", ui.RenderWarn("⏰"), r.IssueID, r.Target)
+			case !r.Marked:
+				fmt.Printf("%s %s %s breached: %s\n", ui.RenderFail("✗"), r.IssueID, r.Target, r.Error)
+			default:
+				fmt.Printf("%s %s %s breached, labeled %s", ui.RenderFail("⏰"), r.IssueID, r.Target, sla.BreachLabel(r.Target))
+				if r.EventID != "" {
+					fmt.Printf(", recorded as %s", r.EventID)
+				}
+				fmt.Println()
+				if r.Error != "" {
+					fmt.Printf("  %s %s\n", ui.RenderWarn("⚠"), r.Error)
+				}
+			}
+		}
+	},
+}
+
+// loadSLAPolicies reads and validates the SLA configuration.
+func loadSLAPolicies() ([]sla.Policy, float64, error) {
+	return loadSLAPoliciesFrom(config.Current())
+}
+
+// loadSLAPoliciesFrom is loadSLAPolicies reading from settings.
+func loadSLAPoliciesFrom(settings *config.Settings) ([]sla.Policy, float64, error) {
+	cfg, err := settings.GetSLAConfig()
+	if err != nil {
+		return nil, 0, err
+	}
+	policies, err := sla.ParsePolicies(cfg.Policies)
+	if err != nil {
+		return nil, 0, err
+	}
+	if cfg.AtRisk <= 0 || cfg.AtRisk > 1 {
+		return nil, 0, fmt.Errorf("sla.at-risk must be between 0 and 1, got %v", cfg.AtRisk)
+	}
+	return policies, cfg.AtRisk, nil
+}
+
+// slaEntry is an open issue that an SLA policy applies to.
+type slaEntry struct {
+	Issue  *types.Issue
+	Status *sla.Status
+	Labels []string
+}
+
+// newBreaches returns the breached clocks that haven't been acted on yet.
+func (e *slaEntry) newBreaches() []*sla.Clock {
+	var clocks []*sla.Clock
+	for _, c := range e.Status.Clocks {
+		if c.State == sla.StateBreached && !slices.Contains(e.Labels, sla.BreachLabel(c.Target)) {
+			clocks = append(clocks, c)
+		}
+	}
+	return clocks
+}
+
+// loadSLAEntries evaluates every open issue that an SLA policy applies to.
+func loadSLAEntries(ctx context.Context, s storage.Storage, policies []sla.Policy, atRisk float64, now time.Time) ([]*slaEntry, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{
+		ExcludeStatus: []types.Status{types.StatusClosed, types.StatusTombstone},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	var tracked []*types.Issue
+	var ids []string
+	matched := make(map[string]sla.Policy)
+	for _, issue := range issues {
+		if issue.Ephemeral || issue.IsTemplate {
+			continue
+		}
+		if p, ok := sla.Select(policies, issue); ok {
+			matched[issue.ID] = p
+			tracked = append(tracked, issue)
+			ids = append(ids, issue.ID)
+		}
+	}
+	if len(tracked) == 0 {
+		return nil, nil
+	}
+
+	events, err := s.SearchEvents(ctx, types.EventFilter{IssueIDs: ids, Types: sla.EventTypes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	comments, err := s.GetCommentsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load comments: %w", err)
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load labels: %w", err)
+	}
+	blocking, resolvedAt, err := loadExternalBlocks(ctx, s, matched)
+	if err != nil {
+		return nil, err
+	}
+
+	depEvents := make(map[string][]*types.Event)
+	for _, e := range events {
+		if e.EventType == types.EventDependencyAdded || e.EventType == types.EventDependencyRemoved {
+			depEvents[e.IssueID] = append(depEvents[e.IssueID], e)
+		}
+	}
+	histories := analytics.BuildHistories(tracked, events)
+
+	entries := make([]*slaEntry, 0, len(tracked))
+	for _, issue := range tracked {
+		h := histories[issue.ID]
+		pauses := sla.Pauses(h, depEvents[issue.ID], blocking[issue.ID], resolvedAt)
+		entries = append(entries, &slaEntry{
+			Issue:  issue,
+			Status: sla.Evaluate(matched[issue.ID], h, pauses, comments[issue.ID], atRisk, now),
+			Labels: labels[issue.ID],
+		})
+	}
+	return entries, nil
+}
+
+// loadExternalBlocks returns the external blocks dependencies of the given
+// issues, and when each of the refs they depend on that is satisfied shipped.
+// A satisfied ref without a recorded ship time is treated as unresolved.
+func loadExternalBlocks(ctx context.Context, s storage.Storage, issues map[string]sla.Policy) (map[string][]*types.Dependency, map[string]time.Time, error) {
+	allDeps, err := s.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load dependencies: %w", err)
+	}
+	external := make(map[string][]*types.Dependency)
+	var refs []string
+	for id := range issues {
+		for _, dep := range allDeps[id] {
+			if dep.Type == types.DepBlocks && strings.HasPrefix(dep.DependsOnID, "external:") {
+				external[id] = append(external[id], dep)
+				refs = append(refs, dep.DependsOnID)
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return nil, nil, nil
+	}
+
+	resolvedAt := make(map[string]time.Time)
+	for ref, st := range sqlite.CheckExternalDeps(ctx, refs) {
+		if st.Satisfied && st.SatisfiedAt != nil {
+			resolvedAt[ref] = *st.SatisfiedAt
+		}
+	}
+	return external, resolvedAt, nil
+}
+
+// slaActions acts on SLA breaches, from the daemon or bd sla check.
+type slaActions struct {
+	store  storage.Storage
+	actor  string
+	hooks  *hooks.Runner
+	runner gateeval.CommandRunner
+}
+
+func newSLAActions(s storage.Storage, actorName string, hookRunner *hooks.Runner) *slaActions {
+	return &slaActions{store: s, actor: actorName, hooks: hookRunner, runner: gateeval.ExecRunner{}}
+}
+
+// mark adds the breach label, which records that the breach was handled.
+// It comes first so a failure further on can't make the next check act on
+// the same breach again.
+func (a *slaActions) mark(ctx context.Context, e *slaEntry, c *sla.Clock) error {
+	label := sla.BreachLabel(c.Target)
+	if err := a.store.AddLabel(ctx, e.Issue.ID, label, a.actor); err != nil {
+		return fmt.Errorf("adding label %s: %w", label, err)
+	}
+	e.Labels = append(e.Labels, label)
+	return nil
+}
+
+// record acts on a marked breach: an event bead under the issue, the
+// on_sla_breach hook, and mail to the issue's waiters and assignee. Each
+// step runs even if an earlier one fails. Returns the event bead's ID.
+func (a *slaActions) record(ctx context.Context, e *slaEntry, c *sla.Clock) (string, error) {
+	title := fmt.Sprintf("SLA breached: %s %s", e.Issue.ID, c.Target)
+	description := slaBreachMessage(e.Status, c)
+
+	var errs []error
+	eventID, err := a.recordEvent(ctx, e, c, title, description)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if a.hooks != nil {
+		a.hooks.Run(hooks.EventSLABreach, e.Issue)
+	}
+	if err := a.mail(ctx, e.Issue, title, description); err != nil {
+		errs = append(errs, err)
+	}
+	return eventID, errors.Join(errs...)
+}
+
+// recordEvent creates a closed event bead under the issue, as bd set-state
+// does. Event beads need "event" in types.custom.
+func (a *slaActions) recordEvent(ctx context.Context, e *slaEntry, c *sla.Clock, title, description string) (string, error) {
+	customTypes, err := a.store.GetCustomTypes(ctx)
+	if err != nil {
+		return "", fmt.Errorf("reading types.custom: %w", err)
+	}
+	if !slices.Contains(customTypes, string(types.TypeEvent)) {
+		return "", fmt.Errorf("no event bead recorded: add event to types.custom to enable them")
+	}
+	payload, err := json.Marshal(struct {
+		Policy string `json:"policy"`
+		*sla.Clock
+	}{e.Status.Policy, c})
+	if err != nil {
+		return "", err
+	}
+	eventID, err := a.store.GetNextChildID(ctx, e.Issue.ID)
+	if err != nil {
+		return "", fmt.Errorf("generating event ID: %w", err)
+	}
+	event := &types.Issue{
+		ID:          eventID,
+		Title:       title,
+		Description: description,
+		Status:      types.StatusClosed, // Events are immediately closed
+		Priority:    4,
+		IssueType:   types.TypeEvent,
+		CreatedBy:   a.actor,
+		EventKind:   "sla.breached",
+		Actor:       a.actor,
+		Target:      e.Issue.ID,
+		Payload:     string(payload),
+	}
+	if err := a.store.CreateIssue(ctx, event, a.actor); err != nil {
+		return "", fmt.Errorf("creating event: %w", err)
+	}
+	dep := &types.Dependency{IssueID: eventID, DependsOnID: e.Issue.ID, Type: types.DepParentChild}
+	if err := a.store.AddDependency(ctx, dep, a.actor); err != nil {
+		return eventID, fmt.Errorf("linking event: %w", err)
+	}
+	return eventID, nil
+}
+
+// mail sends the breach to the issue's waiters and assignee through the
+// configured mail delegate, if there is one.
+func (a *slaActions) mail(ctx context.Context, issue *types.Issue, subject, body string) error {
+	recipients := slices.Clone(issue.Waiters)
+	if issue.Assignee != "" && !slices.Contains(recipients, issue.Assignee) {
+		recipients = append(recipients, issue.Assignee)
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+	delegate := strings.Fields(mailDelegateFor(ctx, a.store))
+	if len(delegate) == 0 {
+		return nil
+	}
+	var errs []error
+	for _, to := range recipients {
+		args := append(slices.Clone(delegate[1:]), "send", to, "-s", subject, "-m", body)
+		if _, stderr, err := a.runner.Run(ctx, "", nil, delegate[0], args...); err != nil {
+			if msg := strings.TrimSpace(string(stderr)); msg != "" {
+				err = fmt.Errorf("%w: %s", err, msg)
+			}
+			errs = append(errs, fmt.Errorf("mailing %s: %w", to, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// slaBreachMessage describes a breach for the event bead and mail.
+func slaBreachMessage(st *sla.Status, c *sla.Clock) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s) missed its %s target of %s under the %s policy.\n",
+		st.IssueID, st.Title, c.Target, formatSLADuration(c.Limit), st.Policy)
+	if c.Deadline != nil {
+		fmt.Fprintf(&b, "Deadline: %s\n", c.Deadline.Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "Elapsed: %s (excluding pauses)", formatSLADuration(c.Elapsed))
+	return b.String()
+}
+
+func printSLAReport(w io.Writer, statuses []*sla.Status, now time.Time) {
+	sort.SliceStable(statuses, func(i, j int) bool {
+		wi, wj := statuses[i].Worst(), statuses[j].Worst()
+		if wi != wj {
+			return wi == sla.StateBreached || (wi == sla.StateAtRisk && wj != sla.StateBreached)
+		}
+		return slaNextDeadline(statuses[i]).Before(slaNextDeadline(statuses[j]))
+	})
+
+	for _, st := range statuses {
+		var symbol string
+		switch st.Worst() {
+		case sla.StateBreached:
+			symbol = ui.RenderFail("✖")
+		case sla.StateAtRisk:
+			symbol = ui.RenderWarn("▲")
+		default:
+			symbol = ui.RenderPass("✓")
+		}
+		var clocks []string
+		for _, c := range st.Clocks {
+			clocks = append(clocks, formatSLAClock(c, now))
+		}
+		line := strings.Join(clocks, " · ")
+		if st.Paused != nil {
+			line += ui.RenderMuted(" (paused: " + st.Paused.Reason + ")")
+		}
+		fmt.Fprintf(w, "%s %s [P%d %s] %s\n    %s\n", symbol, st.IssueID, st.Priority, st.Type, st.Title, line)
+	}
+}
+
+// slaNextDeadline is the earliest deadline among a status's running clocks,
+// for sorting; statuses without one sort last.
+func slaNextDeadline(st *sla.Status) time.Time {
+	next := time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, c := range st.Clocks {
+		if c.StoppedAt == nil && c.Deadline != nil && c.Deadline.Before(next) {
+			next = *c.Deadline
+		}
+	}
+	return next
+}
+
+// formatSLAClock summarizes one clock, e.g. "respond breached 2h ago" or
+// "resolve due in 5h (80% used)".
+func formatSLAClock(c *sla.Clock, now time.Time) string {
+	used := int(100 * c.Elapsed / c.Limit)
+	switch {
+	case c.State == sla.StateMet:
+		return fmt.Sprintf("%s met", c.Target)
+	case c.State == sla.StateBreached && c.StoppedAt != nil:
+		return fmt.Sprintf("%s breached (late by %s)", c.Target, formatSLADuration(c.StoppedAt.Sub(*c.Deadline)))
+	case c.State == sla.StateBreached:
+		return fmt.Sprintf("%s breached %s ago", c.Target, formatSLADuration(now.Sub(*c.Deadline)))
+	case c.Deadline == nil:
+		return fmt.Sprintf("%s paused (%d%% used)", c.Target, used)
+	default:
+		return fmt.Sprintf("%s due in %s (%d%% used)", c.Target, formatSLADuration(c.Deadline.Sub(now)), used)
+	}
+}
+
+// formatSLADuration rounds d to the largest sensible unit: 45m, 5h, 3d.
+func formatSLADuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func init() {
+	slaReportCmd.Flags().Bool("all", false, "Include issues on track")
+	slaCheckCmd.Flags().Bool("dry-run", false, "List new breaches without acting on them")
+	slaCmd.AddCommand(slaReportCmd, slaCheckCmd)
+	rootCmd.AddCommand(slaCmd)
+}
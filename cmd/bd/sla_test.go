@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/sla"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// slaTestRunner records commands instead of running them.
+type slaTestRunner struct{ calls [][]string }
+
+func (r *slaTestRunner) Run(_ context.Context, _ string, _ []string, name string, args ...string) ([]byte, []byte, error) {
+	r.calls = append(r.calls, append([]string{name}, args...))
+	return nil, nil, nil
+}
+
+func TestSLAEntriesAndBreach(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	twoHoursAgo, halfHourAgo := time.Now().Add(-2*time.Hour), time.Now().Add(-30*time.Minute)
+	for _, issue := range []*types.Issue{
+		{ID: "test-1", Title: "Outage", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug, Assignee: "alice", CreatedAt: twoHoursAgo},
+		{ID: "test-2", Title: "Answered", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug, CreatedAt: halfHourAgo},
+		{ID: "test-3", Title: "Untracked", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug, CreatedAt: twoHoursAgo},
+	} {
+		if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if _, err := s.AddIssueComment(ctx, "test-2", "bob", "Looking"); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+
+	policies := []sla.Policy{{Type: types.TypeBug, Priority: 0, Respond: time.Hour, Resolve: 24 * time.Hour}}
+	entries, err := loadSLAEntries(ctx, s, policies, 0.75, time.Now())
+	if err != nil {
+		t.Fatalf("loadSLAEntries failed: %v", err)
+	}
+	byID := make(map[string]*slaEntry)
+	for _, e := range entries {
+		byID[e.Issue.ID] = e
+	}
+	if len(entries) != 2 || byID["test-3"] != nil {
+		t.Fatalf("entries = %d, want test-1 and test-2 under the P0 policy", len(entries))
+	}
+	if c := byID["test-2"].Status.Clock(sla.TargetRespond); c.State != sla.StateMet {
+		t.Errorf("test-2 respond = %s, want met by the comment", c.State)
+	}
+	breaches := byID["test-1"].newBreaches()
+	if len(breaches) != 1 || breaches[0].Target != sla.TargetRespond {
+		t.Fatalf("test-1 breaches = %+v, want respond", breaches)
+	}
+
+	t.Setenv("BEADS_MAIL_DELEGATE", "gt mail")
+	runner := &slaTestRunner{}
+	actions := &slaActions{store: s, actor: "tester", runner: runner}
+	if err := actions.mark(ctx, byID["test-1"], breaches[0]); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+	eventID, err := actions.record(ctx, byID["test-1"], breaches[0])
+	if err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+
+	event, err := s.GetIssue(ctx, eventID)
+	if err != nil || event == nil {
+		t.Fatalf("event bead %s not found: %v", eventID, err)
+	}
+	if event.IssueType != types.TypeEvent || event.Status != types.StatusClosed || event.EventKind != "sla.breached" || event.Target != "test-1" {
+		t.Errorf("event bead = %+v", event)
+	}
+	if !strings.Contains(event.Payload, `"target":"respond"`) {
+		t.Errorf("event payload = %s", event.Payload)
+	}
+	if len(runner.calls) != 1 || !slices.Equal(runner.calls[0][:4], []string{"gt", "mail", "send", "alice"}) {
+		t.Errorf("mail calls = %q, want one send to alice", runner.calls)
+	}
+
+	// Without the event type the breach is still mailed
+	if err := s.SetConfig(ctx, "types.custom", "gate"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	if eventID, err := actions.record(ctx, byID["test-1"], breaches[0]); eventID != "" || err == nil || !strings.Contains(err.Error(), "types.custom") {
+		t.Errorf("record without event type = %q, %v; want a types.custom error", eventID, err)
+	}
+	if len(runner.calls) != 2 {
+		t.Errorf("mail calls = %d, want a second send", len(runner.calls))
+	}
+
+	// Handled breaches are not acted on again
+	entries, err = loadSLAEntries(ctx, s, policies, 0.75, time.Now())
+	if err != nil {
+		t.Fatalf("loadSLAEntries failed: %v", err)
+	}
+	for _, e := range entries {
+		if len(e.newBreaches()) != 0 {
+			t.Errorf("%s still has new breaches after being marked", e.Issue.ID)
+		}
+		if e.Issue.ID == eventID {
+			t.Error("event beads should not be tracked")
+		}
+	}
+}
+
+func TestFormatSLAClock(t *testing.T) {
+	now := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+	due, past := now.Add(5*time.Hour), now.Add(-2*time.Hour)
+	stopped := past.Add(30 * time.Minute)
+	for _, tt := range []struct {
+		clock *sla.Clock
+		want  string
+	}{
+		{&sla.Clock{Target: sla.TargetResolve, Limit: 20 * time.Hour, Elapsed: 15 * time.Hour, State: sla.StateAtRisk, Deadline: &due}, "resolve due in 5h (75% used)"},
+		{&sla.Clock{Target: sla.TargetRespond, Limit: time.Hour, Elapsed: 3 * time.Hour, State: sla.StateBreached, Deadline: &past}, "respond breached 2h ago"},
+		{&sla.Clock{Target: sla.TargetRespond, Limit: time.Hour, Elapsed: 90 * time.Minute, State: sla.StateBreached, Deadline: &past, StoppedAt: &stopped}, "respond breached (late by 30m)"},
+		{&sla.Clock{Target: sla.TargetResolve, Limit: 4 * time.Hour, Elapsed: time.Hour, State: sla.StateOK}, "resolve paused (25% used)"},
+		{&sla.Clock{Target: sla.TargetRespond, Limit: time.Hour, State: sla.StateMet, StoppedAt: &stopped}, "respond met"},
+	} {
+		if got := formatSLAClock(tt.clock, now); got != tt.want {
+			t.Errorf("formatSLAClock = %q, want %q", got, tt.want)
+		}
+	}
+}
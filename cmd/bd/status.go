@@ -58,7 +58,8 @@ Examples:
   bd status --no-activity      # Skip git activity (faster)
   bd status --json             # JSON format output
   bd status --assigned         # Show issues assigned to current user
-  bd stats                     # Alias for bd status`,
+  bd stats                     # Alias for bd status
+  bd status --town             # Per-rig and total counts for every rig in the town`,
 	Run: func(cmd *cobra.Command, args []string) {
 		showAll, _ := cmd.Flags().GetBool("all")
 		showAssigned, _ := cmd.Flags().GetBool("assigned")
@@ -70,6 +71,12 @@ Examples:
 			jsonOutput = true
 		}
 
+		// Town scope: per-rig statistics and totals from every routed rig
+		if townScope, _ := cmd.Flags().GetBool("town"); townScope {
+			runTownStats()
+			return
+		}
+
 		// Get statistics
 		var stats *types.Statistics
 		var err error
@@ -343,6 +350,7 @@ func init() {
 	statusCmd.Flags().Bool("all", false, "Show all issues (default behavior)")
 	statusCmd.Flags().Bool("assigned", false, "Show issues assigned to current user")
 	statusCmd.Flags().Bool("no-activity", false, "Skip git activity tracking (faster)")
+	statusCmd.Flags().Bool("town", false, townFlagUsage)
 	// Note: --json flag is defined as a persistent flag in main.go, not here
 	rootCmd.AddCommand(statusCmd)
 }
@@ -0,0 +1,459 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// DispatchOptions controls how the swarm dispatcher picks agents.
+type DispatchOptions struct {
+	RoleType      string        // Only dispatch to agents with this role_type (empty = any)
+	Rig           string        // Only dispatch to agents in this rig (empty = any)
+	MaxConcurrent int           // Max issues in progress at once (0 = limited by idle agents only)
+	TTL           time.Duration // Lease duration for each claim
+	DryRun        bool          // Compute assignments without applying them
+}
+
+// DispatchAssignment records one issue handed to an agent by the dispatcher.
+type DispatchAssignment struct {
+	IssueID string `json:"issue_id"`
+	Title   string `json:"title"`
+	Agent   string `json:"agent"`
+	Wave    int    `json:"wave"`
+}
+
+// DispatchResult summarizes a single dispatcher pass.
+type DispatchResult struct {
+	SwarmID     string               `json:"swarm_id"`
+	EpicID      string               `json:"epic_id"`
+	Wave        int                  `json:"wave"` // Current ready front (-1 when complete)
+	Active      int                  `json:"active"`
+	Limit       int                  `json:"limit,omitempty"`
+	IdleAgents  int                  `json:"idle_agents"`
+	Assignments []DispatchAssignment `json:"assignments"`
+	Complete    bool                 `json:"complete"`
+	DryRun      bool                 `json:"dry_run,omitempty"`
+}
+
+var swarmDispatchCmd = &cobra.Command{
+	Use:   "dispatch [swarm-or-epic-id]",
+	Short: "Assign ready swarm work to idle agents",
+	Long: `Assign issues from the swarm's current ready front to idle worker agents.
+
+The dispatcher:
+- Reopens issues whose agent let the lease lapse, taking them off the
+  agent's hook
+- Finds the current front (the earliest wave with unfinished issues)
+- Finds idle agent beads (gt:agent, agent_state=idle, empty hook)
+  matching --role and --rig
+- Claims each ready issue for an agent (atomic, lease-based; see 'bd claim')
+  and puts it on the agent's hook, up to --max issues in progress
+- Records every assignment as an event on the swarm molecule
+
+The next front is only dispatched once every issue in the current front
+has closed.
+
+With --watch, the dispatcher keeps running and re-dispatches whenever the
+database changes, so a coordinator agent does not have to poll. It exits
+when the swarm is complete.
+
+Examples:
+  bd swarm dispatch gt-swarm-456                   # One dispatch pass
+  bd swarm dispatch gt-swarm-456 --role polecat    # Only polecat agents
+  bd swarm dispatch gt-swarm-456 --max 3 --watch   # Keep 3 workers busy
+  bd swarm dispatch gt-swarm-456 --dry-run         # Show what would happen`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+		opts := DispatchOptions{}
+		opts.RoleType, _ = cmd.Flags().GetString("role")
+		opts.Rig, _ = cmd.Flags().GetString("rig")
+		opts.MaxConcurrent, _ = cmd.Flags().GetInt("max")
+		opts.TTL, _ = cmd.Flags().GetDuration("ttl")
+		opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		watch, _ := cmd.Flags().GetBool("watch")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		if !opts.DryRun {
+			CheckReadonly("swarm dispatch")
+		}
+		if opts.MaxConcurrent < 0 {
+			FatalErrorRespectJSON("--max must not be negative")
+		}
+
+		// Swarm commands require direct store access
+		if store == nil {
+			if daemonClient != nil {
+				var err error
+				store, err = sqlite.New(ctx, dbPath)
+				if err != nil {
+					FatalErrorRespectJSON("failed to open database: %v", err)
+				}
+				defer func() { _ = store.Close() }()
+			} else {
+				FatalErrorRespectJSON("no database connection")
+			}
+		}
+
+		issueID, err := utils.ResolvePartialID(ctx, store, args[0])
+		if err != nil {
+			FatalErrorRespectJSON("issue '%s' not found: %v", args[0], err)
+		}
+		issue, err := store.GetIssue(ctx, issueID)
+		if err != nil {
+			FatalErrorRespectJSON("failed to get issue: %v", err)
+		}
+		if issue == nil {
+			FatalErrorRespectJSON("issue '%s' not found", issueID)
+		}
+
+		swarmMol, epic, err := resolveSwarmEpic(ctx, store, issue)
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		pass := func() (*DispatchResult, error) {
+			result, err := dispatchSwarm(ctx, store, swarmMol, epic, opts)
+			if err != nil {
+				return nil, err
+			}
+			if len(result.Assignments) > 0 && !opts.DryRun {
+				markDirtyAndScheduleFlush()
+			}
+			return result, nil
+		}
+
+		if !watch {
+			result, err := pass()
+			if err != nil {
+				FatalErrorRespectJSON("dispatch failed: %v", err)
+			}
+			if jsonOutput {
+				outputJSON(result)
+				return
+			}
+			renderDispatchResult(result)
+			return
+		}
+
+		runSwarmDispatchWatch(pass, interval)
+	},
+}
+
+// resolveSwarmEpic maps a swarm molecule or epic to the (swarm, epic) pair.
+// The swarm molecule is nil if the epic has none.
+func resolveSwarmEpic(ctx context.Context, s SwarmStorage, issue *types.Issue) (*types.Issue, *types.Issue, error) {
+	if issue.IssueType == types.TypeMolecule && issue.MolType == types.MolTypeSwarm {
+		deps, err := s.GetDependencyRecords(ctx, issue.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get swarm dependencies: %w", err)
+		}
+		for _, dep := range deps {
+			if dep.Type == types.DepRelatesTo {
+				epic, err := s.GetIssue(ctx, dep.DependsOnID)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to get linked epic: %w", err)
+				}
+				if epic != nil {
+					return issue, epic, nil
+				}
+			}
+		}
+		return nil, nil, fmt.Errorf("swarm molecule '%s' has no linked epic", issue.ID)
+	}
+
+	if issue.IssueType != types.TypeEpic && issue.IssueType != types.TypeMolecule {
+		return nil, nil, fmt.Errorf("'%s' is not an epic or swarm molecule (type: %s)", issue.ID, issue.IssueType)
+	}
+	swarmMol, err := findExistingSwarm(ctx, s, issue.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return swarmMol, issue, nil
+}
+
+// dispatchSwarm runs one dispatcher pass: it pairs ready issues in the
+// current front with idle agents and claims each issue for its agent.
+// Decisions are recorded as dispatched events on the swarm molecule (or the
+// epic if there is no swarm molecule).
+func dispatchSwarm(ctx context.Context, s storage.Storage, swarmMol, epic *types.Issue, opts DispatchOptions) (*DispatchResult, error) {
+	result := &DispatchResult{
+		EpicID:      epic.ID,
+		Wave:        -1,
+		Limit:       opts.MaxConcurrent,
+		Assignments: []DispatchAssignment{},
+		DryRun:      opts.DryRun,
+	}
+	eventTarget := epic.ID
+	if swarmMol != nil {
+		result.SwarmID = swarmMol.ID
+		eventTarget = swarmMol.ID
+	}
+
+	// Reopen issues whose agent let the lease lapse (which also clears the
+	// agent's hook) before deciding who is idle and what is ready
+	if !opts.DryRun {
+		if _, err := s.ExpireLeases(ctx, actor); err != nil {
+			return nil, fmt.Errorf("failed to expire leases: %w", err)
+		}
+	}
+
+	analysis, err := analyzeEpicForSwarm(ctx, s, epic)
+	if err != nil {
+		return nil, err
+	}
+	if !analysis.Swarmable {
+		return nil, fmt.Errorf("epic %s is not swarmable: %v", epic.ID, analysis.Errors)
+	}
+
+	// The current front is the earliest wave that still has unfinished work
+	var front []string
+	for _, rf := range analysis.ReadyFronts {
+		for _, id := range rf.Issues {
+			if analysis.Issues[id].Status != string(types.StatusClosed) {
+				front = rf.Issues
+				result.Wave = rf.Wave
+				break
+			}
+		}
+		if front != nil {
+			break
+		}
+	}
+	if front == nil {
+		result.Complete = true
+		return result, nil
+	}
+
+	for _, node := range analysis.Issues {
+		if node.Status == string(types.StatusInProgress) {
+			result.Active++
+		}
+	}
+
+	// Candidates: unassigned, unblocked open issues in the current front
+	status, err := getSwarmStatus(ctx, s, epic)
+	if err != nil {
+		return nil, err
+	}
+	inFront := make(map[string]bool, len(front))
+	for _, id := range front {
+		inFront[id] = true
+	}
+	var candidates []StatusIssue
+	for _, si := range status.Ready {
+		if inFront[si.ID] && si.Assignee == "" {
+			candidates = append(candidates, si)
+		}
+	}
+
+	agents, err := findIdleAgents(ctx, s, opts.RoleType, opts.Rig)
+	if err != nil {
+		return nil, err
+	}
+	result.IdleAgents = len(agents)
+
+	slots := len(agents)
+	if opts.MaxConcurrent > 0 && opts.MaxConcurrent-result.Active < slots {
+		slots = opts.MaxConcurrent - result.Active
+	}
+
+	next := 0
+	for _, candidate := range candidates {
+		if len(result.Assignments) >= slots || next >= len(agents) {
+			break
+		}
+		agent := agents[next]
+		assignment := DispatchAssignment{
+			IssueID: candidate.ID,
+			Title:   candidate.Title,
+			Agent:   agent.ID,
+			Wave:    result.Wave,
+		}
+
+		if !opts.DryRun {
+			if _, err := storage.ClaimIssue(ctx, s, candidate.ID, agent.ID, opts.TTL); err != nil {
+				var claimErr *storage.ClaimError
+				if errors.As(err, &claimErr) {
+					continue // Someone else got it; keep the agent for the next candidate
+				}
+				return nil, fmt.Errorf("failed to claim %s for %s: %w", candidate.ID, agent.ID, err)
+			}
+			if err := s.UpdateIssue(ctx, agent.ID, map[string]interface{}{"hook_bead": candidate.ID}, actor); err != nil {
+				// Don't leave the issue claimed by an agent that never got it
+				if releaseErr := storage.ReleaseClaim(ctx, s, candidate.ID, agent.ID); releaseErr != nil {
+					return nil, fmt.Errorf("failed to hook %s on %s: %w (releasing the claim also failed: %v)", candidate.ID, agent.ID, err, releaseErr)
+				}
+				return nil, fmt.Errorf("failed to hook %s on %s: %w", candidate.ID, agent.ID, err)
+			}
+			msg := fmt.Sprintf("dispatched %s to %s (wave %d)", candidate.ID, agent.ID, result.Wave+1)
+			event := &types.Event{
+				IssueID:   eventTarget,
+				EventType: types.EventDispatched,
+				Actor:     actor,
+				NewValue:  &assignment.IssueID,
+				Comment:   &msg,
+			}
+			if err := s.AddEvent(ctx, event); err != nil {
+				return nil, fmt.Errorf("failed to record dispatch: %w", err)
+			}
+			result.Active++
+		}
+
+		result.Assignments = append(result.Assignments, assignment)
+		next++
+	}
+
+	return result, nil
+}
+
+// findIdleAgents returns agent beads that are idle with nothing on their
+// hook, optionally filtered by role type and rig, sorted by ID.
+// Agents that predate the role_type/rig fields are matched by their ID.
+func findIdleAgents(ctx context.Context, s storage.Storage, roleType, rig string) ([]*types.Issue, error) {
+	agents, err := s.SearchIssues(ctx, "", types.IssueFilter{Labels: []string{"gt:agent"}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	var idle []*types.Issue
+	for _, agent := range agents {
+		if agent.Status == types.StatusClosed || agent.AgentState != types.StateIdle || agent.HookBead != "" {
+			continue
+		}
+		agentRole, agentRig := agent.RoleType, agent.Rig
+		if agentRole == "" && agentRig == "" {
+			agentRole, agentRig = parseAgentIDFields(agent.ID)
+		}
+		if roleType != "" && agentRole != roleType {
+			continue
+		}
+		if rig != "" && agentRig != rig {
+			continue
+		}
+		idle = append(idle, agent)
+	}
+
+	sort.Slice(idle, func(i, j int) bool {
+		return idle[i].ID < idle[j].ID
+	})
+	return idle, nil
+}
+
+// runSwarmDispatchWatch re-runs pass whenever the beads directory changes
+// (falling back to polling every interval) until the swarm is complete.
+// A failed pass is reported and retried on the next change or tick, so a
+// transient store error doesn't stop the dispatcher.
+func runSwarmDispatchWatch(pass func() (*DispatchResult, error), interval time.Duration) {
+	report := func(result *DispatchResult) {
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		timestamp := time.Now().Format("15:04:05")
+		for _, a := range result.Assignments {
+			fmt.Printf("[%s] %s %s → %s (wave %d)\n", timestamp,
+				ui.RenderPass("→"), ui.RenderID(a.IssueID), a.Agent, a.Wave+1)
+		}
+		if result.Complete {
+			fmt.Printf("[%s] %s Swarm complete\n", timestamp, ui.RenderPass("✓"))
+		}
+	}
+
+	run := func() (*DispatchResult, bool) {
+		result, err := pass()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] %s dispatch failed (will retry): %v\n",
+				time.Now().Format("15:04:05"), ui.RenderWarn("⚠"), err)
+			return nil, false
+		}
+		return result, true
+	}
+
+	if result, ok := run(); ok {
+		report(result)
+		if result.Complete {
+			return
+		}
+	}
+
+	watcher := NewActivityWatcher(filepath.Dir(dbPath), interval)
+	defer func() { _ = watcher.Close() }()
+	watcher.Start(rootCtx)
+
+	// Also re-check on a timer: agents going idle and leases expiring
+	// do not always touch the watched files.
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rootCtx.Done():
+			return
+		case _, ok := <-watcher.Events():
+			if !ok {
+				return
+			}
+		case <-ticker.C:
+		}
+
+		result, ok := run()
+		if !ok {
+			continue
+		}
+		if len(result.Assignments) > 0 || result.Complete {
+			report(result)
+		}
+		if result.Complete {
+			return
+		}
+	}
+}
+
+// renderDispatchResult outputs a human-readable dispatch summary.
+func renderDispatchResult(result *DispatchResult) {
+	if result.Complete {
+		fmt.Printf("\n%s Swarm complete: all issues in %s are closed\n\n", ui.RenderPass("✓"), result.EpicID)
+		return
+	}
+
+	verb := "Dispatched"
+	if result.DryRun {
+		verb = "Would dispatch"
+	}
+	fmt.Printf("\n%s Wave %d: %d active, %d idle agent(s)\n", ui.RenderAccent("🐝"),
+		result.Wave+1, result.Active, result.IdleAgents)
+	if len(result.Assignments) == 0 {
+		fmt.Printf("   Nothing to dispatch\n\n")
+		return
+	}
+	fmt.Printf("%s %d issue(s):\n", verb, len(result.Assignments))
+	for _, a := range result.Assignments {
+		fmt.Printf("   %s → %s  %s\n", ui.RenderID(a.IssueID), a.Agent, a.Title)
+	}
+	fmt.Println()
+}
+
+func init() {
+	swarmDispatchCmd.Flags().String("role", "", "Only dispatch to agents with this role_type (e.g., polecat)")
+	swarmDispatchCmd.Flags().String("rig", "", "Only dispatch to agents in this rig")
+	swarmDispatchCmd.Flags().Int("max", 0, "Maximum issues in progress at once (0 = one per idle agent)")
+	swarmDispatchCmd.Flags().Duration("ttl", types.DefaultLeaseTTL, "Lease duration for each claim")
+	swarmDispatchCmd.Flags().Bool("dry-run", false, "Show assignments without applying them")
+	swarmDispatchCmd.Flags().Bool("watch", false, "Keep dispatching as issues close until the swarm is complete")
+	swarmDispatchCmd.Flags().Duration("interval", 5*time.Second, "Re-check interval for --watch")
+
+	swarmCmd.AddCommand(swarmDispatchCmd)
+}
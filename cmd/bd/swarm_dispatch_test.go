@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestDispatchSwarm(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	create := func(issue *types.Issue) *types.Issue {
+		t.Helper()
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue(%q) failed: %v", issue.Title, err)
+		}
+		return issue
+	}
+	addDep := func(from, to string, depType types.DependencyType) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: from, DependsOnID: to, Type: depType}
+		if err := s.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency(%s -> %s) failed: %v", from, to, err)
+		}
+	}
+
+	// Epic with two parallel roots (wave 1) and one follow-up (wave 2)
+	epic := create(&types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic})
+	a := create(&types.Issue{Title: "A", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask})
+	b := create(&types.Issue{Title: "B", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask})
+	c := create(&types.Issue{Title: "C", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask})
+	for _, child := range []*types.Issue{a, b, c} {
+		addDep(child.ID, epic.ID, types.DepParentChild)
+	}
+	addDep(c.ID, a.ID, types.DepBlocks)
+	addDep(c.ID, b.ID, types.DepBlocks)
+
+	swarmMol := create(&types.Issue{Title: "Swarm", Status: types.StatusOpen, Priority: 1,
+		IssueType: types.TypeMolecule, MolType: types.MolTypeSwarm})
+	addDep(swarmMol.ID, epic.ID, types.DepRelatesTo)
+
+	newAgent := func(id, role string, state types.AgentState) {
+		t.Helper()
+		create(&types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask})
+		updates := map[string]interface{}{"agent_state": string(state), "role_type": role, "rig": "gastown"}
+		if err := s.UpdateIssue(ctx, id, updates, "test"); err != nil {
+			t.Fatalf("UpdateIssue failed: %v", err)
+		}
+		if err := s.AddLabel(ctx, id, "gt:agent", "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+	newAgent("test-gastown-polecat-nux", "polecat", types.StateIdle)
+	newAgent("test-gastown-polecat-ace", "polecat", types.StateIdle)
+	newAgent("test-gastown-polecat-zed", "polecat", types.StateWorking)
+	newAgent("test-gastown-witness", "witness", types.StateIdle)
+
+	opts := DispatchOptions{RoleType: "polecat", MaxConcurrent: 1, TTL: time.Hour}
+
+	// Concurrency limit of 1: only one of the two wave-1 issues goes out
+	result, err := dispatchSwarm(ctx, s, swarmMol, epic, opts)
+	if err != nil {
+		t.Fatalf("dispatchSwarm failed: %v", err)
+	}
+	if result.Wave != 0 || result.IdleAgents != 2 || len(result.Assignments) != 1 {
+		t.Fatalf("pass 1: got wave=%d idle=%d assignments=%d, want 0/2/1",
+			result.Wave, result.IdleAgents, len(result.Assignments))
+	}
+	first := result.Assignments[0]
+	if first.Agent != "test-gastown-polecat-ace" {
+		t.Errorf("expected first idle agent by ID, got %s", first.Agent)
+	}
+	assertDispatched(t, s, first.IssueID, first.Agent)
+
+	// Raise the limit: the other wave-1 issue goes to the remaining idle agent
+	opts.MaxConcurrent = 0
+	result, err = dispatchSwarm(ctx, s, swarmMol, epic, opts)
+	if err != nil {
+		t.Fatalf("dispatchSwarm failed: %v", err)
+	}
+	if len(result.Assignments) != 1 || result.Assignments[0].Agent != "test-gastown-polecat-nux" {
+		t.Fatalf("pass 2: unexpected assignments %+v", result.Assignments)
+	}
+	if result.Assignments[0].IssueID == c.ID {
+		t.Fatalf("wave-2 issue dispatched before wave 1 closed")
+	}
+
+	// Close wave 1 and free the agents: the front advances to C
+	for _, id := range []string{a.ID, b.ID} {
+		if err := s.CloseIssue(ctx, id, "done", "test", ""); err != nil {
+			t.Fatalf("CloseIssue failed: %v", err)
+		}
+	}
+	for _, id := range []string{"test-gastown-polecat-ace", "test-gastown-polecat-nux"} {
+		if err := s.UpdateIssue(ctx, id, map[string]interface{}{"hook_bead": ""}, "test"); err != nil {
+			t.Fatalf("UpdateIssue failed: %v", err)
+		}
+	}
+	result, err = dispatchSwarm(ctx, s, swarmMol, epic, opts)
+	if err != nil {
+		t.Fatalf("dispatchSwarm failed: %v", err)
+	}
+	if result.Wave != 1 || len(result.Assignments) != 1 || result.Assignments[0].IssueID != c.ID {
+		t.Fatalf("pass 3: got wave=%d assignments=%+v, want C in wave 1", result.Wave, result.Assignments)
+	}
+
+	if err := s.CloseIssue(ctx, c.ID, "done", "test", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	result, err = dispatchSwarm(ctx, s, swarmMol, epic, opts)
+	if err != nil {
+		t.Fatalf("dispatchSwarm failed: %v", err)
+	}
+	if !result.Complete {
+		t.Errorf("expected swarm to be complete")
+	}
+
+	// Every assignment was recorded on the swarm molecule
+	events, err := s.GetEvents(ctx, swarmMol.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	dispatched := 0
+	for _, e := range events {
+		if e.EventType == types.EventDispatched && e.NewValue != nil && e.Comment != nil {
+			dispatched++
+		}
+	}
+	if dispatched != 3 {
+		t.Errorf("expected 3 dispatch events, got %d", dispatched)
+	}
+}
+
+func TestDispatchSwarmDryRun(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	epic := &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	task := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	agent := &types.Issue{ID: "test-polecat", Title: "agent", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{epic, task, agent} {
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: task.ID, DependsOnID: epic.ID, Type: types.DepParentChild}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := s.AddLabel(ctx, agent.ID, "gt:agent", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := s.UpdateIssue(ctx, agent.ID, map[string]interface{}{"agent_state": "idle"}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	result, err := dispatchSwarm(ctx, s, nil, epic, DispatchOptions{DryRun: true, TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("dispatchSwarm failed: %v", err)
+	}
+	if len(result.Assignments) != 1 {
+		t.Fatalf("expected 1 planned assignment, got %d", len(result.Assignments))
+	}
+
+	got, _ := s.GetIssue(ctx, task.ID)
+	if got.Status != types.StatusOpen || got.Assignee != "" {
+		t.Errorf("dry run modified the issue: %s/%q", got.Status, got.Assignee)
+	}
+}
+
+// hookFailingStore fails every hook_bead update, as if the agent bead
+// could not be written.
+type hookFailingStore struct {
+	*sqlite.SQLiteStorage
+}
+
+func (s hookFailingStore) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	if _, ok := updates["hook_bead"]; ok {
+		return errors.New("disk full")
+	}
+	return s.SQLiteStorage.UpdateIssue(ctx, id, updates, actor)
+}
+
+func TestDispatchSwarmReleasesClaimWhenHookFails(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	epic := &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	task := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	agent := &types.Issue{ID: "test-polecat", Title: "agent", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{epic, task, agent} {
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: task.ID, DependsOnID: epic.ID, Type: types.DepParentChild}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := s.AddLabel(ctx, agent.ID, "gt:agent", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := s.UpdateIssue(ctx, agent.ID, map[string]interface{}{"agent_state": "idle"}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	_, err := dispatchSwarm(ctx, hookFailingStore{s}, nil, epic, DispatchOptions{TTL: time.Hour})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("dispatchSwarm error = %v, want hook failure", err)
+	}
+
+	got, _ := s.GetIssue(ctx, task.ID)
+	if got.Status != types.StatusOpen || got.Assignee != "" {
+		t.Errorf("claim left behind after hook failure: %s/%q", got.Status, got.Assignee)
+	}
+	if lease, _ := s.GetLease(ctx, task.ID); lease != nil {
+		t.Errorf("lease left behind after hook failure: %+v", lease)
+	}
+}
+
+func TestRunSwarmDispatchWatchRetries(t *testing.T) {
+	oldDBPath, oldRootCtx := dbPath, rootCtx
+	dbPath = filepath.Join(t.TempDir(), "beads.db")
+	rootCtx = context.Background()
+	t.Cleanup(func() { dbPath, rootCtx = oldDBPath, oldRootCtx })
+
+	calls := 0
+	pass := func() (*DispatchResult, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("database is locked")
+		}
+		return &DispatchResult{Complete: true}, nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runSwarmDispatchWatch(pass, 10*time.Millisecond)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not retry after a failed pass")
+	}
+	if calls != 2 {
+		t.Errorf("pass called %d times, want 2", calls)
+	}
+}
+
+func assertDispatched(t *testing.T, s *sqlite.SQLiteStorage, issueID, agentID string) {
+	t.Helper()
+	ctx := context.Background()
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Status != types.StatusInProgress || issue.Assignee != agentID {
+		t.Errorf("%s = %s/%q, want in_progress/%s", issueID, issue.Status, issue.Assignee, agentID)
+	}
+	agent, err := s.GetIssue(ctx, agentID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if agent.HookBead != issueID {
+		t.Errorf("agent %s hook = %q, want %s", agentID, agent.HookBead, issueID)
+	}
+}
+
+func TestDispatchSwarmRedispatchesExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, filepath.Join(t.TempDir(), ".beads", "beads.db"))
+
+	epic := &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	task := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	agent := &types.Issue{ID: "test-polecat", Title: "agent", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{epic, task, agent} {
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: task.ID, DependsOnID: epic.ID, Type: types.DepParentChild}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := s.AddLabel(ctx, agent.ID, "gt:agent", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := s.UpdateIssue(ctx, agent.ID, map[string]interface{}{"agent_state": "idle"}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	opts := DispatchOptions{TTL: 10 * time.Millisecond}
+	if result, err := dispatchSwarm(ctx, s, nil, epic, opts); err != nil || len(result.Assignments) != 1 {
+		t.Fatalf("first dispatch = %+v, %v; want one assignment", result, err)
+	}
+
+	// The agent never touches the issue, so its lease lapses: the issue is
+	// reopened, comes off the agent's hook, and goes out again.
+	time.Sleep(50 * time.Millisecond)
+	result, err := dispatchSwarm(ctx, s, nil, epic, opts)
+	if err != nil {
+		t.Fatalf("dispatchSwarm failed: %v", err)
+	}
+	if len(result.Assignments) != 1 || result.Assignments[0].IssueID != task.ID || result.Assignments[0].Agent != agent.ID {
+		t.Fatalf("second dispatch assignments = %+v, want %s to %s again", result.Assignments, task.ID, agent.ID)
+	}
+	assertDispatched(t, s, task.ID, agent.ID)
+}
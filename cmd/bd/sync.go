@@ -125,7 +125,18 @@ The --manual flag shows a diff for each conflict and prompts you to choose:
   q/quit   - Quit and skip all remaining conflicts
   d/diff   - Show full JSON diff
 
-The --full flag provides the legacy full sync behavior for backwards compatibility.`,
+The --full flag provides the legacy full sync behavior for backwards compatibility.
+
+Object Storage Transport:
+  bd sync --transport s3         Sync through an S3-compatible bucket instead of git
+
+With sync.transport: s3 (or --transport s3), bd sync exports, merges any
+generation pushed by another clone since the last sync, imports the result,
+and uploads it as the next generation. No git commits are made. Configure
+the bucket with sync.s3.bucket, sync.s3.prefix, sync.s3.region,
+sync.s3.endpoint and sync.s3.path-style. With sync.transport: s3 the daemon
+syncs through the bucket too: auto-pull merges new generations, and
+auto-commit with auto-push uploads them.`,
 	Run: func(cmd *cobra.Command, _ []string) {
 		CheckReadonly("sync")
 		ctx := rootCtx
@@ -151,6 +162,7 @@ The --full flag provides the legacy full sync behavior for backwards compatibili
 		resolveTheirs, _ := cmd.Flags().GetBool("theirs")
 		resolveManual, _ := cmd.Flags().GetBool("manual")
 		forceFlag, _ := cmd.Flags().GetBool("force")
+		transportFlag, _ := cmd.Flags().GetString("transport")
 
 		// --import is shorthand for --import-only
 		if importFlag {
@@ -296,6 +308,22 @@ The --full flag provides the legacy full sync behavior for backwards compatibili
 			return
 		}
 
+		// OBJECT STORAGE TRANSPORT: sync through a bucket instead of git
+		transport, err := resolveSyncTransport(transportFlag)
+		if err != nil {
+			FatalError("%v", err)
+		}
+		if transport == config.SyncTransportS3 {
+			t, err := newS3SyncTransport()
+			if err != nil {
+				FatalError("%v", err)
+			}
+			if err := doTransportSync(ctx, t, jsonlPath, renameOnImport, dryRun, noPush, noPull); err != nil {
+				FatalError("%v", err)
+			}
+			return
+		}
+
 		// DEFAULT BEHAVIOR: Export to JSONL only (per spec)
 		// Does NOT stage or commit - that's the user's job.
 		// Use --full for legacy full sync behavior (pull → merge → export → commit → push)
@@ -1256,6 +1284,7 @@ func init() {
 	syncCmd.Flags().Bool("theirs", false, "Use 'theirs' strategy for conflict resolution (with --resolve)")
 	syncCmd.Flags().Bool("manual", false, "Use interactive manual resolution for conflicts (with --resolve)")
 	syncCmd.Flags().Bool("force", false, "Force full export/import (skip incremental optimization)")
+	syncCmd.Flags().String("transport", "", "Sync transport: git or s3 (default: sync.transport config)")
 	syncCmd.Flags().String("set-mode", "", "Set sync mode (git-portable, realtime, dolt-native, belt-and-suspenders)")
 	rootCmd.AddCommand(syncCmd)
 }
@@ -110,6 +110,11 @@ func importFromJSONLInline(ctx context.Context, jsonlPath string, renameOnImport
 		return fmt.Errorf("error reading JSONL: %w", err)
 	}
 
+	// Reuse field-level choices recorded by an earlier 'bd import --interactive'
+	if err := resolveImportConflicts(ctx, store, allIssues, jsonlPath, "", false, false); err != nil {
+		debug.Logf("Warning: failed to apply recorded conflict resolutions: %v", err)
+	}
+
 	// Import using shared logic
 	opts := ImportOptions{
 		RenameOnImport: renameOnImport,
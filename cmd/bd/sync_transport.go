@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/merge"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/synctransport"
+)
+
+// Metadata keys recording the remote snapshot this clone last synced with
+// over an object-storage transport. It is the base of the next three-way merge.
+const (
+	transportGenerationKey = "sync.transport_generation"
+	transportObjectKey     = "sync.transport_object"
+)
+
+// resolveSyncTransport returns the transport selected by --transport, or by
+// sync.transport when the flag is not set.
+func resolveSyncTransport(flagValue string) (config.SyncTransport, error) {
+	if flagValue == "" {
+		return config.GetSyncTransport(), nil
+	}
+	if !config.IsValidSyncTransport(flagValue) {
+		return "", fmt.Errorf("invalid --transport %q (valid: git, s3)", flagValue)
+	}
+	return config.SyncTransport(flagValue), nil
+}
+
+// newS3SyncTransport builds the s3 transport from sync.s3.* settings.
+func newS3SyncTransport() (*synctransport.S3Transport, error) {
+	cfg := config.GetSyncS3Config()
+	return synctransport.NewS3(synctransport.S3Config{
+		Bucket:    cfg.Bucket,
+		Prefix:    cfg.Prefix,
+		Region:    cfg.Region,
+		Endpoint:  cfg.Endpoint,
+		PathStyle: cfg.PathStyle,
+	})
+}
+
+// daemonSyncTransport returns the object-storage transport the daemon's sync
+// loops use in place of git, or nil when sync.transport is git. Tests
+// substitute an in-memory transport.
+var daemonSyncTransport = func() (synctransport.Transport, error) {
+	if config.GetSyncTransport() != config.SyncTransportS3 {
+		return nil, nil
+	}
+	t, err := newS3SyncTransport()
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// transportSyncResult is the --json output of an object-storage sync.
+type transportSyncResult struct {
+	Transport   string `json:"transport"`
+	Generation  int64  `json:"generation"`
+	Pulled      bool   `json:"pulled"`
+	Pushed      bool   `json:"pushed"`
+	RemoteAhead bool   `json:"remote_ahead,omitempty"`
+}
+
+// doTransportSync syncs through an object-storage transport instead of git:
+// export → merge remote generation (if another clone wrote) → import → push
+// the next generation.
+func doTransportSync(ctx context.Context, t synctransport.Transport, jsonlPath string, renameOnImport, dryRun, noPush, noPull bool) error {
+	beadsDir := filepath.Dir(jsonlPath)
+
+	lock := flock.New(filepath.Join(beadsDir, ".sync.lock"))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return fmt.Errorf("acquiring sync lock: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("another sync is in progress")
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	if err := validateOpenIssuesForSync(ctx); err != nil {
+		return err
+	}
+
+	base, err := loadTransportState(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Println("→ [DRY RUN] Would export pending changes to JSONL")
+		m, _, err := t.ReadManifest(ctx)
+		switch {
+		case errors.Is(err, synctransport.ErrNotFound):
+			fmt.Println("→ [DRY RUN] Remote is empty; would push generation 1")
+		case err != nil:
+			return fmt.Errorf("reading manifest: %w", err)
+		case m.Generation != base.Generation:
+			fmt.Printf("→ [DRY RUN] Would merge remote generation %d (last synced: %d) and push generation %d\n",
+				m.Generation, base.Generation, m.Generation+1)
+		default:
+			fmt.Printf("→ [DRY RUN] Remote is at generation %d; would push local changes if any\n", m.Generation)
+		}
+		return nil
+	}
+
+	if !jsonOutput {
+		fmt.Println("→ Exporting pending changes to JSONL...")
+	}
+	if err := exportToJSONL(ctx, jsonlPath); err != nil {
+		return fmt.Errorf("exporting: %w", err)
+	}
+	// #nosec G304 -- jsonlPath is the workspace JSONL
+	local, err := os.ReadFile(jsonlPath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", jsonlPath, err)
+	}
+
+	policy, err := merge.LoadPolicy()
+	if err != nil {
+		return err
+	}
+
+	if !jsonOutput {
+		fmt.Println("→ Syncing with remote...")
+	}
+	res, err := synctransport.Sync(ctx, t, synctransport.Options{
+		Local:  local,
+		Base:   base,
+		Policy: policy,
+		Writer: config.GetIdentity(""),
+		NoPull: noPull,
+		NoPush: noPush,
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.Pulled {
+		if err := os.WriteFile(jsonlPath, res.Merged, 0600); err != nil {
+			return fmt.Errorf("writing merged JSONL: %w", err)
+		}
+		if !jsonOutput {
+			fmt.Printf("→ Importing remote changes (generation %d)...\n", res.State.Generation)
+		}
+		// Git history backfill does not apply to snapshots from object storage.
+		if err := importFromJSONLInline(ctx, jsonlPath, renameOnImport, true, false); err != nil {
+			return fmt.Errorf("importing merged state: %w", err)
+		}
+	}
+
+	if err := saveTransportState(ctx, store, res.State); err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		outputJSON(transportSyncResult{
+			Transport:   string(config.SyncTransportS3),
+			Generation:  res.State.Generation,
+			Pulled:      res.Pulled,
+			Pushed:      res.Pushed,
+			RemoteAhead: res.RemoteAhead,
+		})
+		return nil
+	}
+	switch {
+	case res.RemoteAhead:
+		fmt.Println("⚠ Remote has newer generations; run 'bd sync' without --no-pull to merge them")
+	case res.Pushed:
+		fmt.Printf("✓ Pushed generation %d\n", res.State.Generation)
+	case noPush:
+		fmt.Println("→ Skipping push (--no-push)")
+	default:
+		fmt.Printf("✓ Remote already up to date (generation %d)\n", res.State.Generation)
+	}
+	fmt.Println("\n✓ Sync complete")
+	return nil
+}
+
+// transportSyncWithStore is the daemon's counterpart of doTransportSync for
+// an already exported jsonlPath: it merges in the remote generation if
+// another clone wrote one, imports the result into s, and pushes the next
+// generation unless noPush is set.
+func transportSyncWithStore(ctx context.Context, s storage.Storage, t synctransport.Transport, jsonlPath string, noPush bool) (*synctransport.Result, error) {
+	// #nosec G304 -- jsonlPath is the workspace JSONL
+	local, err := os.ReadFile(jsonlPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", jsonlPath, err)
+	}
+
+	base, err := loadTransportState(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := merge.LoadPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := synctransport.Sync(ctx, t, synctransport.Options{
+		Local:  local,
+		Base:   base,
+		Policy: policy,
+		Writer: config.GetIdentity(""),
+		NoPush: noPush,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Pulled {
+		if err := os.WriteFile(jsonlPath, res.Merged, 0600); err != nil {
+			return nil, fmt.Errorf("writing merged JSONL: %w", err)
+		}
+		if err := importToJSONLWithStore(ctx, s, jsonlPath); err != nil {
+			return nil, fmt.Errorf("importing merged state: %w", err)
+		}
+	}
+
+	if err := saveTransportState(ctx, s, res.State); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// loadTransportState reads the last synced generation from the database.
+func loadTransportState(ctx context.Context, s storage.Storage) (synctransport.State, error) {
+	var state synctransport.State
+	gen, err := s.GetMetadata(ctx, transportGenerationKey)
+	if err != nil {
+		return state, fmt.Errorf("reading %s: %w", transportGenerationKey, err)
+	}
+	if gen == "" {
+		return state, nil
+	}
+	if state.Generation, err = strconv.ParseInt(gen, 10, 64); err != nil {
+		return state, fmt.Errorf("invalid %s %q: %w", transportGenerationKey, gen, err)
+	}
+	if state.Object, err = s.GetMetadata(ctx, transportObjectKey); err != nil {
+		return state, fmt.Errorf("reading %s: %w", transportObjectKey, err)
+	}
+	return state, nil
+}
+
+// saveTransportState records the generation this clone is now synced with.
+func saveTransportState(ctx context.Context, s storage.Storage, state synctransport.State) error {
+	if err := s.SetMetadata(ctx, transportGenerationKey, strconv.FormatInt(state.Generation, 10)); err != nil {
+		return fmt.Errorf("saving %s: %w", transportGenerationKey, err)
+	}
+	if err := s.SetMetadata(ctx, transportObjectKey, state.Object); err != nil {
+		return fmt.Errorf("saving %s: %w", transportObjectKey, err)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/synctransport"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// memTransport is an in-memory synctransport.Transport shared by the clones
+// in a test.
+type memTransport struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	manifest *synctransport.Manifest
+	version  int
+}
+
+func newMemTransport() *memTransport {
+	return &memTransport{objects: map[string][]byte{}}
+}
+
+func (m *memTransport) ReadManifest(context.Context) (*synctransport.Manifest, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.manifest == nil {
+		return nil, "", synctransport.ErrNotFound
+	}
+	copied := *m.manifest
+	return &copied, fmt.Sprint(m.version), nil
+}
+
+func (m *memTransport) WriteManifest(_ context.Context, manifest *synctransport.Manifest, version string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current := ""
+	if m.manifest != nil {
+		current = fmt.Sprint(m.version)
+	}
+	if version != current {
+		return synctransport.ErrConflict
+	}
+	copied := *manifest
+	m.manifest = &copied
+	m.version++
+	return nil
+}
+
+func (m *memTransport) PutObject(_ context.Context, key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memTransport) GetObject(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, synctransport.ErrNotFound
+	}
+	return data, nil
+}
+
+func (m *memTransport) DeleteObject(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memTransport) ListObjects(_ context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// useTestStore points the store globals at s for the rest of the test.
+func useTestStore(t *testing.T, s *sqlite.SQLiteStorage, path string) {
+	t.Helper()
+	oldStore, oldActive, oldDBPath := store, storeActive, dbPath
+	storeMutex.Lock()
+	store = s
+	storeActive = true
+	storeMutex.Unlock()
+	dbPath = path
+	t.Cleanup(func() {
+		storeMutex.Lock()
+		store, storeActive = oldStore, oldActive
+		storeMutex.Unlock()
+		dbPath = oldDBPath
+	})
+}
+
+func TestDoTransportSyncBetweenClones(t *testing.T) {
+	ensureCleanGlobalState(t)
+	ctx := context.Background()
+	remote := newMemTransport()
+
+	type clone struct {
+		store     *sqlite.SQLiteStorage
+		dbPath    string
+		jsonlPath string
+	}
+	newClone := func(issueID, title string) clone {
+		dir := filepath.Join(t.TempDir(), ".beads")
+		c := clone{dbPath: filepath.Join(dir, "beads.db"), jsonlPath: filepath.Join(dir, "issues.jsonl")}
+		c.store = newTestStore(t, c.dbPath)
+		issue := &types.Issue{ID: issueID, Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := c.store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return c
+	}
+	syncClone := func(c clone) {
+		t.Helper()
+		useTestStore(t, c.store, c.dbPath)
+		if err := doTransportSync(ctx, remote, c.jsonlPath, false, false, false, false); err != nil {
+			t.Fatalf("doTransportSync: %v", err)
+		}
+	}
+
+	alice := newClone("test-a1", "from alice")
+	bob := newClone("test-b1", "from bob")
+
+	// A dry run neither writes the JSONL nor touches the remote
+	useTestStore(t, alice.store, alice.dbPath)
+	if err := doTransportSync(ctx, remote, alice.jsonlPath, false, true, false, false); err != nil {
+		t.Fatalf("doTransportSync --dry-run: %v", err)
+	}
+	if _, err := os.Stat(alice.jsonlPath); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote %s (stat err=%v)", alice.jsonlPath, err)
+	}
+	if len(remote.objects) != 0 || remote.manifest != nil {
+		t.Error("dry run wrote to the remote")
+	}
+
+	syncClone(alice)
+	syncClone(bob)
+	syncClone(alice)
+
+	for name, c := range map[string]clone{"alice": alice, "bob": bob} {
+		for _, id := range []string{"test-a1", "test-b1"} {
+			issue, err := c.store.GetIssue(ctx, id)
+			if err != nil || issue == nil {
+				t.Errorf("%s is missing %s after sync (err=%v)", name, id, err)
+			}
+		}
+	}
+
+	m, _, err := remote.ReadManifest(ctx)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if m.Generation != 2 {
+		t.Errorf("remote generation = %d, want 2 (alice's last sync had nothing new)", m.Generation)
+	}
+	state, err := loadTransportState(ctx, alice.store)
+	if err != nil {
+		t.Fatalf("loadTransportState: %v", err)
+	}
+	if state.Generation != 2 || state.Object != m.Object {
+		t.Errorf("alice state = %+v, want generation 2 at %s", state, m.Object)
+	}
+}
+
+func TestDaemonSyncUsesTransport(t *testing.T) {
+	ensureCleanGlobalState(t)
+	ctx := context.Background()
+	remote := newMemTransport()
+
+	oldTransport, oldDBPath := daemonSyncTransport, dbPath
+	daemonSyncTransport = func() (synctransport.Transport, error) { return remote, nil }
+	t.Cleanup(func() { daemonSyncTransport, dbPath = oldTransport, oldDBPath })
+
+	// Other tests leave multi-repo config behind; the transport syncs one JSONL
+	config.Set("repos.primary", "")
+	config.Set("repos.additional", nil)
+
+	newClone := func(issueID string) (*sqlite.SQLiteStorage, string) {
+		// Not a git repository: any git call in the sync cycle would fail it
+		path := filepath.Join(t.TempDir(), ".beads", "beads.db")
+		s := newTestStore(t, path)
+		issue := &types.Issue{ID: issueID, Title: issueID, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return s, path
+	}
+	alice, alicePath := newClone("test-a1")
+	bob, bobPath := newClone("test-b1")
+
+	runSync := func(s *sqlite.SQLiteStorage, path string) {
+		dbPath = path
+		createSyncFunc(ctx, s, true, true, newTestLogger())()
+	}
+	runSync(alice, alicePath)
+	runSync(bob, bobPath)
+	runSync(alice, alicePath)
+
+	for name, s := range map[string]*sqlite.SQLiteStorage{"alice": alice, "bob": bob} {
+		for _, id := range []string{"test-a1", "test-b1"} {
+			if issue, err := s.GetIssue(ctx, id); err != nil || issue == nil {
+				t.Errorf("%s is missing %s after daemon sync (err=%v)", name, id, err)
+			}
+		}
+	}
+	m, _, err := remote.ReadManifest(ctx)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if m.Generation != 2 {
+		t.Errorf("remote generation = %d, want 2", m.Generation)
+	}
+}
+
+func TestResolveSyncTransport(t *testing.T) {
+	if got, err := resolveSyncTransport("s3"); err != nil || got != config.SyncTransportS3 {
+		t.Errorf("resolveSyncTransport(s3) = %q, %v", got, err)
+	}
+	if _, err := resolveSyncTransport("rsync"); err == nil {
+		t.Error("expected an error for an unknown transport")
+	}
+}
@@ -191,12 +191,17 @@ var templateShowCmd = &cobra.Command{
 }
 
 func showBeadsTemplate(subgraph *TemplateSubgraph) {
+	if err := loadTemplateVarDefs(subgraph); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", ui.RenderWarn("⚠"), err)
+	}
+
 	if jsonOutput {
 		outputJSON(map[string]interface{}{
 			"root":         subgraph.Root,
 			"issues":       subgraph.Issues,
 			"dependencies": subgraph.Dependencies,
 			"variables":    extractAllVariables(subgraph),
+			"var_defs":     subgraph.VarDefs,
 		})
 		return
 	}
@@ -210,7 +215,11 @@ func showBeadsTemplate(subgraph *TemplateSubgraph) {
 	if len(vars) > 0 {
 		fmt.Printf("\n%s Variables:\n", ui.RenderWarn("📝"))
 		for _, v := range vars {
-			fmt.Printf("   {{%s}}\n", v)
+			if def, ok := subgraph.VarDefs[v]; ok {
+				fmt.Printf("   {{%s}} %s\n", v, describeVarDef(def))
+			} else {
+				fmt.Printf("   {{%s}}\n", v)
+			}
 		}
 	}
 
@@ -229,6 +238,23 @@ var templateInstantiateCmd = &cobra.Command{
 Variables are specified with --var key=value flags. The template's {{key}}
 placeholders will be replaced with the corresponding values.
 
+The template epic can declare its variables in a fenced vars block in its
+description (TOML or JSON, same fields as formula vars):
+
+  ` + "```vars" + `
+  [version]
+  required = true
+  pattern = '^\d+\.\d+\.\d+$'
+
+  [env]
+  enum = ["staging", "prod"]
+  default = "staging"
+  ` + "```" + `
+
+Defaults are filled in, values are checked against enum/pattern/type, and
+missing values are prompted for when running in a terminal. Every unresolved
+placeholder is reported before anything is created.
+
 Example:
   bd template instantiate bd-abc123 --var version=1.2.0 --var date=2024-01-15`,
 	Args: cobra.ExactArgs(1),
@@ -289,17 +315,19 @@ Example:
 			os.Exit(1)
 		}
 
-		// Check for missing variables
-		requiredVars := extractAllVariables(subgraph)
-		var missingVars []string
-		for _, v := range requiredVars {
-			if _, ok := vars[v]; !ok {
-				missingVars = append(missingVars, v)
-			}
+		// Resolve variables: declared defaults, prompts for missing values,
+		// and validation - all before anything is created
+		if err := loadTemplateVarDefs(subgraph); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-		if len(missingVars) > 0 {
-			fmt.Fprintf(os.Stderr, "Error: missing required variables: %s\n", strings.Join(missingVars, ", "))
-			fmt.Fprintf(os.Stderr, "Provide them with: --var %s=<value>\n", missingVars[0])
+		noPrompt, _ := cmd.Flags().GetBool("no-prompt")
+		vars, err = resolveTemplateVars(subgraph, vars, !noPrompt && canPromptForVars())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if _, ok := err.(*templateVarError); ok {
+				fmt.Fprintf(os.Stderr, "Provide values with: --var key=value\n")
+			}
 			os.Exit(1)
 		}
 
@@ -358,6 +386,7 @@ func init() {
 	templateInstantiateCmd.Flags().StringArray("var", []string{}, "Variable substitution (key=value)")
 	templateInstantiateCmd.Flags().Bool("dry-run", false, "Preview what would be created")
 	templateInstantiateCmd.Flags().String("assignee", "", "Assign the root epic to this agent/user")
+	templateInstantiateCmd.Flags().Bool("no-prompt", false, "Never prompt for missing variables (fail instead)")
 
 	templateCmd.AddCommand(templateListCmd)
 	templateCmd.AddCommand(templateShowCmd)
@@ -707,7 +736,7 @@ func cloneSubgraphViaDaemon(client *rpc.Client, subgraph *TemplateSubgraph, opts
 		// Build create args
 		createArgs := &rpc.CreateArgs{
 			Title:              substituteVariables(oldIssue.Title, opts.Vars),
-			Description:        substituteVariables(oldIssue.Description, opts.Vars),
+			Description:        substituteDescription(oldIssue.Description, opts.Vars),
 			IssueType:          string(oldIssue.IssueType),
 			Priority:           oldIssue.Priority,
 			Design:             substituteVariables(oldIssue.Design, opts.Vars),
@@ -853,6 +882,13 @@ func substituteVariables(text string, vars map[string]string) string {
 	})
 }
 
+// substituteDescription substitutes variables in an issue description,
+// dropping the template's vars block. Used by every instantiation path
+// (template instantiate, pour, wisp, bond).
+func substituteDescription(text string, vars map[string]string) string {
+	return substituteVariables(stripTemplateVarsBlock(text), vars)
+}
+
 // generateBondedID creates a custom ID for dynamically bonded molecules.
 // When bonding a proto to a parent molecule, this generates IDs like:
 //   - Root: parent.childref (e.g., "patrol-x7k.arm-ace")
@@ -952,7 +988,7 @@ func cloneSubgraph(ctx context.Context, s storage.Storage, subgraph *TemplateSub
 			newIssue := &types.Issue{
 				// ID will be set below based on bonding options
 				Title:              substituteVariables(oldIssue.Title, opts.Vars),
-				Description:        substituteVariables(oldIssue.Description, opts.Vars),
+				Description:        substituteDescription(oldIssue.Description, opts.Vars),
 				Design:             substituteVariables(oldIssue.Design, opts.Vars),
 				AcceptanceCriteria: substituteVariables(oldIssue.AcceptanceCriteria, opts.Vars),
 				Notes:              substituteVariables(oldIssue.Notes, opts.Vars),
@@ -295,6 +295,28 @@ func TestCloneSubgraph(t *testing.T) {
 		}
 	})
 
+	t.Run("vars block is not copied", func(t *testing.T) {
+		// pour and wisp clone without loadTemplateVarDefs
+		epic := h.createIssue("Ship {{version}}", "Ship {{version}}\n\n```vars\n[version]\nrequired = true\n```\n", types.TypeEpic, 1)
+		h.addLabel(epic.ID, BeadsTemplateLabel)
+
+		subgraph, err := loadTemplateSubgraph(ctx, s, epic.ID)
+		if err != nil {
+			t.Fatalf("loadTemplateSubgraph failed: %v", err)
+		}
+		result, err := cloneSubgraph(ctx, s, subgraph, CloneOptions{Vars: map[string]string{"version": "3.0"}, Actor: "test-user"})
+		if err != nil {
+			t.Fatalf("cloneSubgraph failed: %v", err)
+		}
+		newEpic, err := s.GetIssue(ctx, result.NewEpicID)
+		if err != nil {
+			t.Fatalf("Failed to get cloned issue: %v", err)
+		}
+		if newEpic.Description != "Ship 3.0" {
+			t.Errorf("Description = %q, want %q", newEpic.Description, "Ship 3.0")
+		}
+	})
+
 	t.Run("clone template with children", func(t *testing.T) {
 		epic := h.createIssue("Deploy {{service}}", "", types.TypeEpic, 1)
 		child1 := h.createIssue("Build {{service}}", "", types.TypeTask, 2)
@@ -828,3 +850,97 @@ func TestLoadTemplateSubgraphWithManyChildren(t *testing.T) {
 		}
 	})
 }
+
+// TestParseTemplateVarDefs tests the ```vars declaration block on template epics
+func TestParseTemplateVarDefs(t *testing.T) {
+	desc := "Release checklist.\n\n```vars\n[version]\nrequired = true\npattern = '^\\d+\\.\\d+\\.\\d+$'\n\n[env]\nenum = [\"staging\", \"prod\"]\ndefault = \"staging\"\n\n[replicas]\ntype = \"int\"\ndefault = \"3\"\n```\n"
+
+	defs, stripped, err := parseTemplateVarDefs(desc)
+	if err != nil {
+		t.Fatalf("parseTemplateVarDefs failed: %v", err)
+	}
+	if stripped != "Release checklist." {
+		t.Errorf("vars block not stripped: %q", stripped)
+	}
+	if !defs["version"].Required || defs["version"].Pattern == "" {
+		t.Errorf("version def = %+v", defs["version"])
+	}
+	if defs["env"].Default != "staging" || len(defs["env"].Enum) != 2 {
+		t.Errorf("env def = %+v", defs["env"])
+	}
+	if defs["replicas"].Type != "int" {
+		t.Errorf("replicas def = %+v", defs["replicas"])
+	}
+
+	// JSON form
+	defs, _, err = parseTemplateVarDefs("```vars\n{\"name\": {\"required\": true}}\n```")
+	if err != nil || !defs["name"].Required {
+		t.Errorf("JSON vars block: defs=%+v err=%v", defs, err)
+	}
+
+	// No block
+	defs, stripped, err = parseTemplateVarDefs("plain")
+	if err != nil || defs != nil || stripped != "plain" {
+		t.Errorf("no vars block: defs=%+v stripped=%q err=%v", defs, stripped, err)
+	}
+
+	// Default that violates its own constraint
+	if _, _, err := parseTemplateVarDefs("```vars\n[n]\ntype = \"int\"\ndefault = \"x\"\n```"); err == nil {
+		t.Error("expected error for invalid default")
+	}
+}
+
+// TestResolveTemplateVars tests defaults, validation and error reporting
+func TestResolveTemplateVars(t *testing.T) {
+	root := &types.Issue{
+		ID:    "tpl-1",
+		Title: "Release {{version}} to {{env}}",
+		Description: "```vars\n[version]\nrequired = true\npattern = '^\\d+\\.\\d+\\.\\d+$'\n\n" +
+			"[env]\nenum = [\"staging\", \"prod\"]\ndefault = \"staging\"\n```",
+	}
+	child := &types.Issue{ID: "tpl-1.1", Title: "Tag {{version}}", Notes: "owner: {{owner}}"}
+	newSubgraph := func() *TemplateSubgraph {
+		r, c := *root, *child
+		sg := &TemplateSubgraph{Root: &r, Issues: []*types.Issue{&r, &c}}
+		if err := loadTemplateVarDefs(sg); err != nil {
+			t.Fatalf("loadTemplateVarDefs failed: %v", err)
+		}
+		return sg
+	}
+
+	t.Run("defaults applied", func(t *testing.T) {
+		vars, err := resolveTemplateVars(newSubgraph(), map[string]string{"version": "1.2.3", "owner": "ann"}, false)
+		if err != nil {
+			t.Fatalf("resolveTemplateVars failed: %v", err)
+		}
+		if vars["env"] != "staging" {
+			t.Errorf("env = %q, want default staging", vars["env"])
+		}
+	})
+
+	t.Run("all problems reported", func(t *testing.T) {
+		_, err := resolveTemplateVars(newSubgraph(), map[string]string{"env": "qa", "verison": "1.0.0"}, false)
+		varErr, ok := err.(*templateVarError)
+		if !ok {
+			t.Fatalf("expected *templateVarError, got %v", err)
+		}
+		msg := varErr.Error()
+		for _, want := range []string{
+			"{{owner}} is not set (used in tpl-1.1.notes)",
+			"{{version}} is not set (used in tpl-1.title, tpl-1.1.title)",
+			`value "qa" not in allowed values`,
+			`unknown variable "verison"`,
+		} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("error missing %q:\n%s", want, msg)
+			}
+		}
+	})
+
+	t.Run("pattern violation", func(t *testing.T) {
+		_, err := resolveTemplateVars(newSubgraph(), map[string]string{"version": "v1", "owner": "ann"}, false)
+		if err == nil || !strings.Contains(err.Error(), "does not match pattern") {
+			t.Errorf("expected pattern error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/huh"
+	"github.com/steveyegge/beads/internal/formula"
+	"github.com/steveyegge/beads/internal/ui"
+	"golang.org/x/term"
+)
+
+// templateVarsBlockPattern matches a fenced ```vars block in a template
+// epic's description. The block declares the template's variables using the
+// same fields as formula vars (description, default, required, enum,
+// pattern, type), in TOML or JSON:
+//
+//	```vars
+//	[version]
+//	required = true
+//	pattern = '^\d+\.\d+\.\d+$'
+//
+//	[env]
+//	enum = ["staging", "prod"]
+//	default = "staging"
+//	```
+var templateVarsBlockPattern = regexp.MustCompile("(?s)\n?```vars[ \t]*\n(.*?)```[ \t]*\n?")
+
+// parseTemplateVarDefs extracts variable declarations from a template
+// description. Returns the declarations (nil if there is no vars block) and
+// the description with the block removed, so it isn't copied into
+// instantiated issues.
+func parseTemplateVarDefs(description string) (map[string]formula.VarDef, string, error) {
+	match := templateVarsBlockPattern.FindStringSubmatchIndex(description)
+	if match == nil {
+		return nil, description, nil
+	}
+	body := strings.TrimSpace(description[match[2]:match[3]])
+	stripped := strings.TrimSpace(description[:match[0]] + "\n" + description[match[1]:])
+
+	defs := make(map[string]formula.VarDef)
+	if strings.HasPrefix(body, "{") {
+		if err := json.Unmarshal([]byte(body), &defs); err != nil {
+			return nil, description, fmt.Errorf("invalid vars block (json): %w", err)
+		}
+	} else if _, err := toml.Decode(body, &defs); err != nil {
+		return nil, description, fmt.Errorf("invalid vars block (toml): %w", err)
+	}
+
+	for name, def := range defs {
+		if def.Required && def.Default != "" {
+			return nil, description, fmt.Errorf("variable %q: required variables cannot have a default", name)
+		}
+		if def.Default != "" {
+			if err := formula.ValidateVarValue(name, &def, def.Default); err != nil {
+				return nil, description, fmt.Errorf("invalid default: %w", err)
+			}
+		}
+	}
+	return defs, stripped, nil
+}
+
+// stripTemplateVarsBlock removes the vars block from a template
+// description; it declares variables and isn't content of the issues
+// instantiated from the template.
+func stripTemplateVarsBlock(description string) string {
+	match := templateVarsBlockPattern.FindStringIndex(description)
+	if match == nil {
+		return description
+	}
+	return strings.TrimSpace(description[:match[0]] + "\n" + description[match[1]:])
+}
+
+// loadTemplateVarDefs reads the vars block from the template root into
+// subgraph.VarDefs and strips it from the root's description.
+func loadTemplateVarDefs(subgraph *TemplateSubgraph) error {
+	defs, description, err := parseTemplateVarDefs(subgraph.Root.Description)
+	if err != nil {
+		return fmt.Errorf("template %s: %w", subgraph.Root.ID, err)
+	}
+	if defs == nil {
+		return nil
+	}
+	subgraph.Root.Description = description
+	if subgraph.VarDefs == nil {
+		subgraph.VarDefs = make(map[string]formula.VarDef)
+	}
+	for name, def := range defs {
+		subgraph.VarDefs[name] = def
+	}
+	return nil
+}
+
+// templateVarError reports every problem found while resolving template
+// variables, so users can fix them all in one go.
+type templateVarError struct {
+	Problems []string
+}
+
+func (e *templateVarError) Error() string {
+	return fmt.Sprintf("template variables are not valid:\n  - %s", strings.Join(e.Problems, "\n  - "))
+}
+
+// resolveTemplateVars fills in defaults, prompts for missing values if
+// prompt is set, and validates every value against its declaration.
+// Returns a *templateVarError listing all problems (unresolved placeholders
+// with their locations, constraint violations, unknown --var names) before
+// anything is created.
+func resolveTemplateVars(subgraph *TemplateSubgraph, provided map[string]string, prompt bool) (map[string]string, error) {
+	vars := applyVariableDefaults(provided, subgraph)
+	placeholders := extractAllVariables(subgraph)
+
+	needed := make(map[string]bool)
+	for _, name := range placeholders {
+		needed[name] = true
+	}
+	for name, def := range subgraph.VarDefs {
+		if def.Required {
+			needed[name] = true
+		}
+	}
+
+	var missing []string
+	for name := range needed {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) > 0 && prompt {
+		prompted, err := promptTemplateVars(missing, subgraph.VarDefs)
+		if err != nil {
+			return nil, err
+		}
+		for name, val := range prompted {
+			vars[name] = val
+		}
+		missing = nil
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		locations := placeholderLocations(subgraph)
+		for _, name := range missing {
+			where := locations[name]
+			if len(where) == 0 {
+				problems = append(problems, fmt.Sprintf("{{%s}} is required", name))
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("{{%s}} is not set (used in %s)", name, strings.Join(where, ", ")))
+		}
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def, declared := subgraph.VarDefs[name]
+		if !declared {
+			if !needed[name] && len(subgraph.VarDefs) > 0 {
+				problems = append(problems, fmt.Sprintf("unknown variable %q (declared: %s)", name, strings.Join(sortedVarNames(subgraph.VarDefs), ", ")))
+			} else if !needed[name] {
+				fmt.Fprintf(os.Stderr, "%s variable %q is not used by this template\n", ui.RenderWarn("⚠"), name)
+			}
+			continue
+		}
+		if err := formula.ValidateVarValue(name, &def, vars[name]); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &templateVarError{Problems: problems}
+	}
+	return vars, nil
+}
+
+// placeholderLocations maps each {{variable}} to the issue fields using it,
+// e.g. "bd-abc.title".
+func placeholderLocations(subgraph *TemplateSubgraph) map[string][]string {
+	locations := make(map[string][]string)
+	for _, issue := range subgraph.Issues {
+		fields := []struct {
+			name string
+			text string
+		}{
+			{"title", issue.Title},
+			{"description", issue.Description},
+			{"design", issue.Design},
+			{"acceptance_criteria", issue.AcceptanceCriteria},
+			{"notes", issue.Notes},
+		}
+		for _, f := range fields {
+			for _, name := range extractVariables(f.text) {
+				locations[name] = append(locations[name], issue.ID+"."+f.name)
+			}
+		}
+	}
+	return locations
+}
+
+// promptTemplateVars asks for each missing variable using a huh form.
+// Declared enums become a select; other constraints are checked inline.
+func promptTemplateVars(missing []string, defs map[string]formula.VarDef) (map[string]string, error) {
+	values := make(map[string]*string, len(missing))
+	var fields []huh.Field
+	for _, name := range missing {
+		def := defs[name]
+		val := new(string)
+		values[name] = val
+
+		if len(def.Enum) > 0 {
+			options := make([]huh.Option[string], 0, len(def.Enum))
+			for _, e := range def.Enum {
+				options = append(options, huh.NewOption(e, e))
+			}
+			fields = append(fields, huh.NewSelect[string]().
+				Title(name).
+				Description(def.Description).
+				Options(options...).
+				Value(val))
+			continue
+		}
+
+		fields = append(fields, huh.NewInput().
+			Title(name).
+			Description(def.Description).
+			Value(val).
+			Validate(func(s string) error {
+				if s == "" {
+					return fmt.Errorf("%s is required", name)
+				}
+				return formula.ValidateVarValue(name, &def, s)
+			}))
+	}
+
+	form := huh.NewForm(huh.NewGroup(fields...)).WithTheme(huh.ThemeDracula())
+	if err := form.Run(); err != nil {
+		return nil, fmt.Errorf("prompt canceled: %w", err)
+	}
+
+	result := make(map[string]string, len(values))
+	for name, val := range values {
+		result[name] = *val
+	}
+	return result, nil
+}
+
+// canPromptForVars reports whether we can interactively ask for variables.
+func canPromptForVars() bool {
+	return !jsonOutput && term.IsTerminal(int(os.Stdin.Fd())) && ui.IsTerminal()
+}
+
+// describeVarDef renders a variable's constraints for display,
+// e.g. "(required, int) - Number of replicas".
+func describeVarDef(def formula.VarDef) string {
+	var parts []string
+	if def.Required {
+		parts = append(parts, "required")
+	}
+	if def.Default != "" {
+		parts = append(parts, fmt.Sprintf("default %q", def.Default))
+	}
+	if def.Type != "" && def.Type != "string" {
+		parts = append(parts, def.Type)
+	}
+	if len(def.Enum) > 0 {
+		parts = append(parts, "one of "+strings.Join(def.Enum, "|"))
+	}
+	if def.Pattern != "" {
+		parts = append(parts, "matching "+def.Pattern)
+	}
+	s := ""
+	if len(parts) > 0 {
+		s = "(" + strings.Join(parts, ", ") + ")"
+	}
+	if def.Description != "" {
+		s = strings.TrimSpace(s + " - " + def.Description)
+	}
+	return s
+}
+
+func sortedVarNames(defs map[string]formula.VarDef) []string {
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
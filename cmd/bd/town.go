@@ -0,0 +1,429 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/steveyegge/beads/internal/routing"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/views"
+)
+
+// townFlagUsage is the help text shared by every command with a --town scope.
+const townFlagUsage = "Query every rig in the town (uses routes.jsonl)"
+
+// TownIssue is an issue from a --town query, annotated with its source rig.
+type TownIssue struct {
+	*types.Issue
+	Rig string `json:"rig"`
+	// CrossRigBlockedBy lists blocks dependencies in other rigs that are
+	// still open (or capabilities not yet shipped).
+	CrossRigBlockedBy []string `json:"cross_rig_blocked_by,omitempty"`
+}
+
+// TownBlockedIssue is a blocked issue from a --town query.
+type TownBlockedIssue struct {
+	*types.BlockedIssue
+	Rig string `json:"rig"`
+}
+
+// TownRigError reports a rig that could not be queried. It doesn't fail the
+// command; the other rigs' results are still returned.
+type TownRigError struct {
+	Rig   string `json:"rig"`
+	Error string `json:"error"`
+}
+
+// TownIssuesOutput is the JSON output of list, search and ready with --town.
+type TownIssuesOutput struct {
+	Issues []*TownIssue   `json:"issues"`
+	Errors []TownRigError `json:"errors"`
+}
+
+// TownBlockedOutput is the JSON output of bd blocked --town.
+type TownBlockedOutput struct {
+	Issues []*TownBlockedIssue `json:"issues"`
+	Errors []TownRigError      `json:"errors"`
+}
+
+// TownRigStatistics holds one rig's statistics.
+type TownRigStatistics struct {
+	Rig   string            `json:"rig"`
+	Stats *types.Statistics `json:"stats"`
+}
+
+// TownStatsOutput is the JSON output of bd status --town.
+type TownStatsOutput struct {
+	Total  *types.Statistics    `json:"total"`
+	Rigs   []*TownRigStatistics `json:"rigs"`
+	Errors []TownRigError       `json:"errors"`
+}
+
+// townRigIssues is what a rig returned for an issue query, with the blocks
+// dependency targets of those issues.
+type townRigIssues struct {
+	issues []*types.Issue
+	deps   map[string][]*types.Dependency
+}
+
+// loadTown finds the town for the current workspace, exiting if there is none.
+func loadTown() *routing.Town {
+	beadsDir := ""
+	if dbPath != "" {
+		beadsDir = filepath.Dir(dbPath)
+	} else if dir, err := findTownBeadsDir(); err == nil {
+		beadsDir = dir
+	}
+
+	town, err := routing.LoadTown(beadsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --town requires an orchestrator town: %v\n", err)
+		os.Exit(1)
+	}
+	return town
+}
+
+// queryTownIssues runs query against every rig and annotates the merged
+// results with cross-rig blockers that are still open.
+func queryTownIssues(ctx context.Context, town *routing.Town, query func(ctx context.Context, store storage.Storage) ([]*types.Issue, error)) ([]*TownIssue, []TownRigError) {
+	results := routing.QueryTown(ctx, town, func(ctx context.Context, _ routing.TownRig, store storage.Storage) (townRigIssues, error) {
+		issues, err := query(ctx, store)
+		if err != nil {
+			return townRigIssues{}, err
+		}
+		deps, err := store.GetAllDependencyRecords(ctx)
+		if err != nil {
+			return townRigIssues{}, fmt.Errorf("failed to load dependencies: %w", err)
+		}
+		return townRigIssues{issues: issues, deps: deps}, nil
+	})
+
+	var issues []*TownIssue
+	var errs []TownRigError
+	crossRefs := make(map[*TownIssue][]routing.CrossRigRef)
+	var allRefs []routing.CrossRigRef
+	for _, res := range results {
+		if res.Err != nil {
+			errs = append(errs, TownRigError{Rig: res.Rig.Name, Error: res.Err.Error()})
+			continue
+		}
+		for _, issue := range res.Value.issues {
+			ti := &TownIssue{Issue: issue, Rig: res.Rig.Name}
+			issues = append(issues, ti)
+			if issue.Status == types.StatusClosed || issue.Status == types.StatusTombstone {
+				continue
+			}
+			for _, dep := range res.Value.deps[issue.ID] {
+				if dep.Type != types.DepBlocks {
+					continue
+				}
+				if ref, ok := town.ParseCrossRigRef(dep.DependsOnID, res.Rig.Name); ok {
+					crossRefs[ti] = append(crossRefs[ti], ref)
+					allRefs = append(allRefs, ref)
+				}
+			}
+		}
+	}
+
+	if len(allRefs) > 0 {
+		statuses := town.ResolveCrossRigRefs(ctx, allRefs)
+		for ti, refs := range crossRefs {
+			for _, ref := range refs {
+				if statuses[ref.Ref].Blocking {
+					ti.CrossRigBlockedBy = append(ti.CrossRigBlockedBy, ref.Ref)
+				}
+			}
+		}
+	}
+	return issues, errs
+}
+
+// sortTownIssues sorts merged results by a list/search sort field, falling
+// back to priority then newest first so rigs interleave deterministically.
+func sortTownIssues(issues []*TownIssue, sortBy string, reverse bool) {
+	if sortBy == "" {
+		sortBy = "priority"
+	}
+	slices.SortStableFunc(issues, func(a, b *TownIssue) int {
+		result := views.Compare(a.Issue, b.Issue, sortBy)
+		if reverse {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+		return cmp.Or(
+			views.Compare(a.Issue, b.Issue, "priority"),
+			views.Compare(a.Issue, b.Issue, "created"),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+}
+
+// runTownIssues executes a list-style query across the town and prints it.
+// query must not limit its results: the merged issues are sorted and then
+// truncated to limit here.
+func runTownIssues(query func(ctx context.Context, store storage.Storage) ([]*types.Issue, error), sortBy string, reverse bool, limit int) {
+	ctx := rootCtx
+	town := loadTown()
+
+	issues, errs := queryTownIssues(ctx, town, query)
+	sortTownIssues(issues, sortBy, reverse)
+	if limit > 0 && len(issues) > limit {
+		issues = issues[:limit]
+	}
+	reportTownErrors(errs)
+	printTownIssues(town, issues, errs)
+	exitIfNoRigAnswered(town, errs)
+}
+
+// runTownReady shows ready work across the town. Issues that are ready in
+// their own rig but blocked by an open issue in another rig are dropped.
+func runTownReady(filter types.WorkFilter) {
+	town := loadTown()
+	issues, errs := townReadyIssues(rootCtx, town, filter)
+	reportTownErrors(errs)
+	printTownIssues(town, issues, errs)
+	exitIfNoRigAnswered(town, errs)
+}
+
+// townReadyIssues collects ready work from every rig. Rigs are queried
+// without filter.Limit, since issues blocked from another rig are only
+// dropped after the merge; the limit applies once to the sorted result.
+func townReadyIssues(ctx context.Context, town *routing.Town, filter types.WorkFilter) ([]*TownIssue, []TownRigError) {
+	rigFilter := filter
+	rigFilter.Limit = 0
+	issues, errs := queryTownIssues(ctx, town, func(ctx context.Context, store storage.Storage) ([]*types.Issue, error) {
+		return store.GetReadyWork(ctx, rigFilter)
+	})
+	issues = slices.DeleteFunc(issues, func(ti *TownIssue) bool {
+		return len(ti.CrossRigBlockedBy) > 0
+	})
+
+	if filter.SortPolicy == types.SortPolicyOldest {
+		sortTownIssues(issues, "created", true)
+	} else {
+		sortTownIssues(issues, "priority", false)
+	}
+	if filter.Limit > 0 && len(issues) > filter.Limit {
+		issues = issues[:filter.Limit]
+	}
+	return issues, errs
+}
+
+// runTownBlocked shows blocked issues across the town. External blockers
+// that resolve to closed issues (or shipped capabilities) in another rig are
+// removed, and issues left with no blockers are dropped unless their status
+// is blocked or deferred.
+func runTownBlocked(filter types.WorkFilter) {
+	ctx := rootCtx
+	town := loadTown()
+
+	results := routing.QueryTown(ctx, town, func(ctx context.Context, _ routing.TownRig, store storage.Storage) ([]*types.BlockedIssue, error) {
+		return store.GetBlockedIssues(ctx, filter)
+	})
+
+	var issues []*TownBlockedIssue
+	var errs []TownRigError
+	var allRefs []routing.CrossRigRef
+	for _, res := range results {
+		if res.Err != nil {
+			errs = append(errs, TownRigError{Rig: res.Rig.Name, Error: res.Err.Error()})
+			continue
+		}
+		for _, issue := range res.Value {
+			issues = append(issues, &TownBlockedIssue{BlockedIssue: issue, Rig: res.Rig.Name})
+			for _, blocker := range issue.BlockedBy {
+				if ref, ok := town.ParseCrossRigRef(blocker, res.Rig.Name); ok {
+					allRefs = append(allRefs, ref)
+				}
+			}
+		}
+	}
+
+	if len(allRefs) > 0 {
+		statuses := town.ResolveCrossRigRefs(ctx, allRefs)
+		issues = slices.DeleteFunc(issues, func(ti *TownBlockedIssue) bool {
+			remaining := ti.BlockedBy[:0]
+			for _, blocker := range ti.BlockedBy {
+				if status, ok := statuses[blocker]; ok && !status.Blocking {
+					continue
+				}
+				remaining = append(remaining, blocker)
+			}
+			ti.BlockedBy = remaining
+			ti.BlockedByCount = len(remaining)
+			return len(remaining) == 0 && ti.Status != types.StatusBlocked && ti.Status != types.StatusDeferred
+		})
+	}
+
+	slices.SortStableFunc(issues, func(a, b *TownBlockedIssue) int {
+		return cmp.Or(
+			cmp.Compare(a.Priority, b.Priority),
+			cmp.Compare(a.Rig, b.Rig),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	reportTownErrors(errs)
+	printTownBlocked(town, issues, errs)
+	exitIfNoRigAnswered(town, errs)
+}
+
+// printTownBlocked prints blocked issues as JSON or one entry per issue.
+func printTownBlocked(town *routing.Town, issues []*TownBlockedIssue, errs []TownRigError) {
+	if jsonOutput {
+		if issues == nil {
+			issues = []*TownBlockedIssue{}
+		}
+		if errs == nil {
+			errs = []TownRigError{}
+		}
+		outputJSON(TownBlockedOutput{Issues: issues, Errors: errs})
+		return
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("\n%s No blocked issues in %d rigs\n\n", ui.RenderPass("✨"), len(town.Rigs))
+		return
+	}
+	fmt.Printf("\n%s Blocked issues across %d rigs (%d):\n\n", ui.RenderFail("🚫"), len(town.Rigs), len(issues))
+	for _, issue := range issues {
+		fmt.Printf("[%s] [%s] %s: %s\n",
+			ui.RenderPriority(issue.Priority), issue.Rig,
+			ui.RenderID(issue.ID), issue.Title)
+		fmt.Printf("  Blocked by %d open dependencies: %v\n",
+			issue.BlockedByCount, issue.BlockedBy)
+		fmt.Println()
+	}
+}
+
+// runTownStats shows statistics for every rig and their totals.
+func runTownStats() {
+	ctx := rootCtx
+	town := loadTown()
+
+	results := routing.QueryTown(ctx, town, func(ctx context.Context, _ routing.TownRig, store storage.Storage) (*types.Statistics, error) {
+		return store.GetStatistics(ctx)
+	})
+
+	total := &types.Statistics{}
+	var rigs []*TownRigStatistics
+	var errs []TownRigError
+	var leadHours float64
+	var leadWeight int
+	for _, res := range results {
+		if res.Err != nil {
+			errs = append(errs, TownRigError{Rig: res.Rig.Name, Error: res.Err.Error()})
+			continue
+		}
+		s := res.Value
+		rigs = append(rigs, &TownRigStatistics{Rig: res.Rig.Name, Stats: s})
+		total.TotalIssues += s.TotalIssues
+		total.OpenIssues += s.OpenIssues
+		total.InProgressIssues += s.InProgressIssues
+		total.ClosedIssues += s.ClosedIssues
+		total.BlockedIssues += s.BlockedIssues
+		total.DeferredIssues += s.DeferredIssues
+		total.ReadyIssues += s.ReadyIssues
+		total.TombstoneIssues += s.TombstoneIssues
+		total.PinnedIssues += s.PinnedIssues
+		total.EpicsEligibleForClosure += s.EpicsEligibleForClosure
+		// Lead time is averaged over closed issues, so weight each rig by them
+		leadHours += s.AverageLeadTime * float64(s.ClosedIssues)
+		leadWeight += s.ClosedIssues
+	}
+	if leadWeight > 0 {
+		total.AverageLeadTime = leadHours / float64(leadWeight)
+	}
+
+	reportTownErrors(errs)
+	printTownStats(town, total, rigs, errs)
+	exitIfNoRigAnswered(town, errs)
+}
+
+// printTownStats prints per-rig statistics and totals as JSON or a table.
+func printTownStats(town *routing.Town, total *types.Statistics, rigs []*TownRigStatistics, errs []TownRigError) {
+	if jsonOutput {
+		if rigs == nil {
+			rigs = []*TownRigStatistics{}
+		}
+		if errs == nil {
+			errs = []TownRigError{}
+		}
+		outputJSON(TownStatsOutput{Total: total, Rigs: rigs, Errors: errs})
+		return
+	}
+
+	fmt.Printf("\n%s Town Status (%d rigs)\n\n", ui.RenderAccent("📊"), len(town.Rigs))
+	fmt.Printf("  %-12s %7s %7s %7s %7s %7s %7s\n", "Rig", "Total", "Open", "Active", "Blocked", "Ready", "Closed")
+	printRow := func(name string, s *types.Statistics) {
+		fmt.Printf("  %-12s %7d %7d %7d %7d %7d %7d\n", name,
+			s.TotalIssues, s.OpenIssues, s.InProgressIssues, s.BlockedIssues, s.ReadyIssues, s.ClosedIssues)
+	}
+	for _, rig := range rigs {
+		printRow(rig.Rig, rig.Stats)
+	}
+	printRow("total", total)
+	if total.AverageLeadTime > 0 {
+		fmt.Printf("\n  Avg Lead Time:          %.1f hours\n", total.AverageLeadTime)
+	}
+	fmt.Println()
+}
+
+// printTownIssues prints merged issue results as JSON or one line per issue.
+func printTownIssues(town *routing.Town, issues []*TownIssue, errs []TownRigError) {
+	if jsonOutput {
+		if issues == nil {
+			issues = []*TownIssue{}
+		}
+		if errs == nil {
+			errs = []TownRigError{}
+		}
+		outputJSON(TownIssuesOutput{Issues: issues, Errors: errs})
+		return
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("\n%s No issues found in %d rigs\n\n", ui.RenderPass("✨"), len(town.Rigs))
+		return
+	}
+	fmt.Printf("\n%s %d issues across %d rigs:\n\n", ui.RenderAccent("📋"), len(issues), len(town.Rigs))
+	for _, issue := range issues {
+		fmt.Printf("[%s] [%s] [%s] %s %s: %s\n",
+			ui.RenderPriority(issue.Priority),
+			ui.RenderType(string(issue.IssueType)),
+			issue.Rig,
+			ui.RenderStatus(string(issue.Status)),
+			ui.RenderID(issue.ID), issue.Title)
+		if len(issue.CrossRigBlockedBy) > 0 {
+			fmt.Printf("  Blocked by other rigs: %v\n", issue.CrossRigBlockedBy)
+		}
+	}
+	fmt.Println()
+}
+
+// reportTownErrors warns about rigs that could not be queried. In JSON mode
+// the errors are part of the output instead.
+func reportTownErrors(errs []TownRigError) {
+	if jsonOutput {
+		return
+	}
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: rig %s: %s\n", e.Rig, e.Error)
+	}
+}
+
+// exitIfNoRigAnswered exits with an error status when every rig failed, so
+// scripts can tell an unreachable town from an empty one.
+func exitIfNoRigAnswered(town *routing.Town, errs []TownRigError) {
+	if len(town.Rigs) > 0 && len(errs) == len(town.Rigs) {
+		os.Exit(1)
+	}
+}
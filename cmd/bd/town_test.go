@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/routing"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestSortTownIssues(t *testing.T) {
+	now := time.Now()
+	issue := func(id, rig string, priority int, age time.Duration) *TownIssue {
+		return &TownIssue{
+			Issue: &types.Issue{ID: id, Priority: priority, CreatedAt: now.Add(-age)},
+			Rig:   rig,
+		}
+	}
+	issues := []*TownIssue{
+		issue("gt-old", "gastown", 1, 3*time.Hour),
+		issue("bd-low", "beads", 3, time.Hour),
+		issue("bd-new", "beads", 1, time.Hour),
+		issue("gt-p0", "gastown", 0, 2*time.Hour),
+	}
+
+	sortTownIssues(issues, "", false)
+	want := []string{"gt-p0", "bd-new", "gt-old", "bd-low"}
+	for i, id := range want {
+		if issues[i].ID != id {
+			t.Fatalf("default order: got %s at %d, want %v", issues[i].ID, i, want)
+		}
+	}
+
+	sortTownIssues(issues, "created", true)
+	want = []string{"gt-old", "gt-p0", "bd-new", "bd-low"}
+	for i, id := range want {
+		if issues[i].ID != id {
+			t.Fatalf("oldest first: got %s at %d, want %v", issues[i].ID, i, want)
+		}
+	}
+}
+
+func TestTownIssueJSON(t *testing.T) {
+	ti := &TownIssue{
+		Issue:             &types.Issue{ID: "bd-1", Title: "Fix", Status: types.StatusOpen},
+		Rig:               "beads",
+		CrossRigBlockedBy: []string{"external:gastown:gt-2"},
+	}
+	data, err := json.Marshal(ti)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["id"] != "bd-1" || got["rig"] != "beads" {
+		t.Errorf("issue fields and rig should be flattened, got %s", data)
+	}
+	if blockers, ok := got["cross_rig_blocked_by"].([]interface{}); !ok || len(blockers) != 1 {
+		t.Errorf("cross_rig_blocked_by missing, got %s", data)
+	}
+}
+
+func TestTownReadyIssuesLimitsAfterMerge(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	paths := map[string]string{
+		"alpha": filepath.Join(dir, "alpha", ".beads", "beads.db"),
+		"beta":  filepath.Join(dir, "beta", ".beads", "beads.db"),
+	}
+	create := func(s *sqlite.SQLiteStorage, id string, priority int) {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue %s: %v", id, err)
+		}
+	}
+
+	// alpha's two most urgent issues wait on an open issue in beta
+	alpha := newTestStoreWithPrefix(t, paths["alpha"], "ta")
+	create(alpha, "ta-1", 0)
+	create(alpha, "ta-2", 1)
+	create(alpha, "ta-3", 2)
+	for _, id := range []string{"ta-1", "ta-2"} {
+		dep := &types.Dependency{IssueID: id, DependsOnID: "external:beta:tb-1", Type: types.DepBlocks}
+		if err := alpha.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency: %v", err)
+		}
+	}
+	beta := newTestStoreWithPrefix(t, paths["beta"], "tb")
+	create(beta, "tb-1", 3)
+
+	town := &routing.Town{
+		Rigs: []routing.TownRig{{Name: "alpha", Prefix: "ta-"}, {Name: "beta", Prefix: "tb-"}},
+		Open: func(ctx context.Context, rig routing.TownRig) (storage.Storage, error) {
+			return sqlite.New(ctx, paths[rig.Name])
+		},
+	}
+
+	issues, errs := townReadyIssues(ctx, town, types.WorkFilter{Status: types.StatusOpen, Limit: 2})
+	if len(errs) != 0 {
+		t.Fatalf("rig errors: %v", errs)
+	}
+	var ids []string
+	for _, ti := range issues {
+		ids = append(ids, ti.ID)
+	}
+	if want := []string{"ta-3", "tb-1"}; !slices.Equal(ids, want) {
+		t.Errorf("town ready = %v, want %v", ids, want)
+	}
+}
@@ -29,8 +29,11 @@ var vcMergeCmd = &cobra.Command{
 	Short: "Merge a branch into the current branch",
 	Long: `Merge the specified branch into the current branch.
 
-If there are merge conflicts, they will be reported. You can resolve
-conflicts with --strategy.
+Conflicting issues are merged field by field, using the same rules as the
+JSONL merge driver. Conflicts the field merge can't decide (fields
+configured as conflict in merge.fields, issues deleted on one side) and
+conflicts in other tables are reported; resolve them with --strategy, or
+inspect them with 'bd vc conflicts'.
 
 Examples:
   bd vc merge feature-xyz                    # Merge feature-xyz into current branch
@@ -53,46 +56,63 @@ Examples:
 			FatalErrorRespectJSON("failed to merge branch: %v", err)
 		}
 
-		// Handle conflicts
+		// Handle conflicts: issues are merged field by field, and --strategy
+		// decides what that leaves and conflicts in other tables
 		if len(conflicts) > 0 {
-			if vcMergeStrategy != "" {
-				// Auto-resolve conflicts with specified strategy
-				for _, conflict := range conflicts {
-					table := conflict.Field // Field contains table name from GetConflicts
-					if table == "" {
-						table = "issues" // Default to issues table
-					}
-					if err := vs.ResolveConflicts(ctx, table, vcMergeStrategy); err != nil {
-						FatalErrorRespectJSON("failed to resolve conflicts: %v", err)
-					}
+			for _, conflict := range conflicts {
+				table := conflict.Field // Field contains table name from GetConflicts
+				if table == "" {
+					table = "issues" // Default to issues table
+				}
+				strategy := vcMergeStrategy
+				if strategy == "" && table == "issues" {
+					strategy = "merge"
+				}
+				if strategy == "" {
+					continue
+				}
+				if err := vs.ResolveConflicts(ctx, table, strategy); err != nil {
+					FatalErrorRespectJSON("failed to resolve conflicts: %v", err)
+				}
+			}
+
+			remaining, err := vs.GetConflicts(ctx)
+			if err != nil {
+				FatalErrorRespectJSON("failed to check remaining conflicts: %v", err)
+			}
+			if len(remaining) == 0 {
+				resolvedWith := vcMergeStrategy
+				if resolvedWith == "" {
+					resolvedWith = "merge"
 				}
 				if jsonOutput {
 					outputJSON(map[string]interface{}{
 						"merged":        branchName,
 						"conflicts":     len(conflicts),
-						"resolved_with": vcMergeStrategy,
+						"resolved_with": resolvedWith,
 					})
 					return
 				}
-				fmt.Printf("Merged %s with %d conflicts resolved using '%s' strategy\n",
-					ui.RenderAccent(branchName), len(conflicts), vcMergeStrategy)
+				fmt.Printf("Merged %s with conflicts in %d tables resolved using '%s' strategy\n",
+					ui.RenderAccent(branchName), len(conflicts), resolvedWith)
 				return
 			}
 
-			// Report conflicts without auto-resolution
+			// Report what is left without further resolution
 			if jsonOutput {
 				outputJSON(map[string]interface{}{
 					"merged":    branchName,
-					"conflicts": conflicts,
+					"conflicts": remaining,
 				})
 				return
 			}
 
 			fmt.Printf("\n%s Merge completed with conflicts:\n\n", ui.RenderAccent("!!"))
-			for _, conflict := range conflicts {
+			for _, conflict := range remaining {
 				fmt.Printf("  - %s\n", conflict.Field)
 			}
-			fmt.Printf("\nResolve conflicts with: bd vc merge %s --strategy [ours|theirs]\n\n", branchName)
+			fmt.Printf("\nList them with:    bd vc conflicts\n")
+			fmt.Printf("Resolve them with: bd vc conflicts --resolve [ours|theirs]\n\n")
 			return
 		}
 
@@ -196,7 +216,7 @@ Examples:
 }
 
 func init() {
-	vcMergeCmd.Flags().StringVar(&vcMergeStrategy, "strategy", "", "Conflict resolution strategy: 'ours' or 'theirs'")
+	vcMergeCmd.Flags().StringVar(&vcMergeStrategy, "strategy", "", "Strategy for conflicts the field merge can't decide: 'ours' or 'theirs'")
 	vcCommitCmd.Flags().StringVarP(&vcCommitMessage, "message", "m", "", "Commit message")
 
 	vcCmd.AddCommand(vcMergeCmd)
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/storage/dolt"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+)
+
+var vcConflictsResolve string
+
+var vcConflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "List unresolved merge conflicts per issue and field",
+	Long: `List the conflicts left by a Dolt merge or federation sync.
+
+Conflicting issues are shown with the fields that still disagree and the
+base, ours and theirs value of each. Issues that merge cleanly field by
+field, or that were deleted on one side, are marked as such. Conflicts in
+other tables are listed by table.
+
+With --resolve, the remaining conflicts are resolved instead:
+  merge   Merge issues field by field, leaving undecidable ones in conflict
+  ours    Field merge, keeping our value where both sides disagree
+  theirs  Field merge, taking their value where both sides disagree
+Other tables take the chosen side wholesale. Commit the result with
+'bd vc commit'.
+
+Examples:
+  bd vc conflicts                    # Show what is left to resolve
+  bd vc conflicts --json             # Machine-readable listing
+  bd vc conflicts --resolve theirs   # Resolve the rest with their values`,
+	Run: runVCConflicts,
+}
+
+func init() {
+	vcConflictsCmd.Flags().StringVar(&vcConflictsResolve, "resolve", "", "Resolve remaining conflicts: 'merge', 'ours' or 'theirs'")
+	vcCmd.AddCommand(vcConflictsCmd)
+}
+
+// vcIssueConflict is the JSON form of a conflicting issue.
+type vcIssueConflict struct {
+	ID            string            `json:"id"`
+	OurDiffType   string            `json:"our_diff_type"`
+	TheirDiffType string            `json:"their_diff_type"`
+	Deleted       bool              `json:"deleted,omitempty"`
+	Resolvable    bool              `json:"resolvable"`
+	Fields        []vcFieldConflict `json:"fields,omitempty"`
+}
+
+type vcFieldConflict struct {
+	Field  string      `json:"field"`
+	Base   interface{} `json:"base"`
+	Ours   interface{} `json:"ours"`
+	Theirs interface{} `json:"theirs"`
+}
+
+func runVCConflicts(cmd *cobra.Command, args []string) {
+	ctx := rootCtx
+
+	ds, ok := store.(*dolt.DoltStore)
+	if !ok {
+		FatalErrorRespectJSON("conflicts requires Dolt backend (current backend does not support versioning)")
+	}
+
+	if vcConflictsResolve != "" {
+		if vcConflictsResolve != "merge" && vcConflictsResolve != "ours" && vcConflictsResolve != "theirs" {
+			FatalErrorRespectJSON("invalid --resolve %q: must be 'merge', 'ours' or 'theirs'", vcConflictsResolve)
+		}
+		tables, err := ds.GetConflicts(ctx)
+		if err != nil {
+			FatalErrorRespectJSON("failed to get conflicts: %v", err)
+		}
+		for _, t := range tables {
+			if vcConflictsResolve == "merge" && t.Field != "issues" {
+				continue
+			}
+			if err := ds.ResolveConflicts(ctx, t.Field, vcConflictsResolve); err != nil {
+				FatalErrorRespectJSON("failed to resolve conflicts in %s: %v", t.Field, err)
+			}
+		}
+	}
+
+	tables, err := ds.GetInternalConflicts(ctx)
+	if err != nil {
+		FatalErrorRespectJSON("failed to get conflicts: %v", err)
+	}
+	issueConflicts, err := ds.GetIssueConflicts(ctx)
+	if err != nil {
+		FatalErrorRespectJSON("failed to read issue conflicts: %v", err)
+	}
+
+	issues := make([]vcIssueConflict, 0, len(issueConflicts))
+	for _, c := range issueConflicts {
+		issues = append(issues, describeIssueConflict(c))
+	}
+	otherTables := make([]*dolt.TableConflict, 0, len(tables))
+	for _, t := range tables {
+		if t.TableName != "issues" {
+			otherTables = append(otherTables, t)
+		}
+	}
+
+	if jsonOutput {
+		tableCounts := make([]map[string]interface{}, 0, len(otherTables))
+		for _, t := range otherTables {
+			tableCounts = append(tableCounts, map[string]interface{}{"table": t.TableName, "conflicts": t.NumConflicts})
+		}
+		outputJSON(map[string]interface{}{
+			"issues": issues,
+			"tables": tableCounts,
+		})
+		return
+	}
+
+	if len(issues) == 0 && len(otherTables) == 0 {
+		fmt.Println("No merge conflicts")
+		return
+	}
+
+	if len(issues) > 0 {
+		fmt.Printf("\n%s %d conflicting issues:\n\n", ui.RenderWarn("⚠"), len(issues))
+		for _, c := range issues {
+			switch {
+			case c.Deleted:
+				fmt.Printf("  %s  %s\n", ui.RenderID(c.ID), ui.RenderMuted(deletedConflictSummary(c)))
+			case c.Resolvable:
+				fmt.Printf("  %s  %s\n", ui.RenderID(c.ID), ui.RenderMuted("merges cleanly field by field"))
+			default:
+				fmt.Printf("  %s\n", ui.RenderID(c.ID))
+				for _, f := range c.Fields {
+					fmt.Printf("    %s\n", ui.RenderBold(f.Field))
+					fmt.Printf("      base:   %s\n", conflictPreview(conflictValueString(f.Base)))
+					fmt.Printf("      ours:   %s\n", conflictPreview(conflictValueString(f.Ours)))
+					fmt.Printf("      theirs: %s\n", conflictPreview(conflictValueString(f.Theirs)))
+				}
+			}
+		}
+	}
+	if len(otherTables) > 0 {
+		fmt.Printf("\n%s Conflicts in other tables:\n\n", ui.RenderWarn("⚠"))
+		for _, t := range otherTables {
+			fmt.Printf("  - %s (%d rows)\n", t.TableName, t.NumConflicts)
+		}
+	}
+	fmt.Printf("\nResolve with: bd vc conflicts --resolve [merge|ours|theirs]\n\n")
+}
+
+// describeIssueConflict converts a Dolt issue conflict for display,
+// with the base/ours/theirs value of each unresolved field.
+func describeIssueConflict(c *dolt.IssueConflict) vcIssueConflict {
+	out := vcIssueConflict{
+		ID:            c.ID,
+		OurDiffType:   c.OurDiffType,
+		TheirDiffType: c.TheirDiffType,
+		Deleted:       c.Deleted(),
+		Resolvable:    c.Resolvable(),
+	}
+	base, ours, theirs := issueFieldValues(c.Base), issueFieldValues(c.Ours), issueFieldValues(c.Theirs)
+	for _, field := range c.Fields {
+		out.Fields = append(out.Fields, vcFieldConflict{
+			Field:  field,
+			Base:   base[field],
+			Ours:   ours[field],
+			Theirs: theirs[field],
+		})
+	}
+	return out
+}
+
+func deletedConflictSummary(c vcIssueConflict) string {
+	if c.OurDiffType == "removed" {
+		return fmt.Sprintf("deleted by us, %s by them", c.TheirDiffType)
+	}
+	return fmt.Sprintf("%s by us, deleted by them", c.OurDiffType)
+}
+
+// issueFieldValues returns an issue's fields keyed by JSONL name (nil for
+// a missing issue).
+func issueFieldValues(issue *types.Issue) map[string]interface{} {
+	if issue == nil {
+		return nil
+	}
+	data, err := json.Marshal(issue)
+	if err != nil {
+		return nil
+	}
+	var values map[string]interface{}
+	_ = json.Unmarshal(data, &values)
+	return values
+}
+
+// conflictValueString renders a field value for conflictPreview.
+func conflictValueString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/rpc"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/ui"
+	"github.com/steveyegge/beads/internal/util"
+	"github.com/steveyegge/beads/internal/views"
+)
+
+var viewCmd = &cobra.Command{
+	Use:     "view",
+	GroupID: "views",
+	Short:   "Saved queries (named bd list and bd ready filters)",
+	Long: `Save combinations of bd list or bd ready flags under a name and run them
+again later.
+
+Views are stored under views in .beads/config.yaml, so committing that file
+shares them with the team. Dates are saved as typed: a view saved with
+--created-after -7d always shows the last seven days.
+
+  views:
+    backend-bugs:
+      description: Recent backend bugs
+      filter:
+        type: bug
+        label: [backend]
+        created-after: -14d
+      sort: priority
+      columns: [id, priority, assignee, title]
+
+Run a view with bd view run <name> or bd list --view <name>; flags given to
+bd list override the view's. Other tools can run views through the daemon
+(view_list and view_run RPC operations).
+
+Examples:
+  bd view save backend-bugs -d "Recent backend bugs" --columns id,priority,assignee,title -- --type bug --label backend --created-after -14d --sort priority
+  bd view save my-next --from ready -- --assignee alice --sort priority
+  bd view run backend-bugs
+  bd list --view backend-bugs --assignee bob
+  bd view list`,
+}
+
+var viewSaveCmd = &cobra.Command{
+	Use:   "save <name> [flags] -- <list or ready flags>",
+	Short: "Save bd list (or bd ready) flags as a named view",
+	Long: `Save bd list flags (or bd ready flags, with --from ready) as a named view.
+The flags to save go after --. Display flags like --pretty, --long and
+--format are not saved; use --columns to choose the table columns.
+
+Examples:
+  bd view save p0s -- --priority 0 --all --sort updated
+  bd view save stale-mine -- --assignee alice --updated-before -14d
+  bd view save my-next --from ready -- --assignee alice --limit 5`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		description, _ := cmd.Flags().GetString("description")
+		columns, _ := cmd.Flags().GetStringSlice("columns")
+		force, _ := cmd.Flags().GetBool("force")
+
+		name := args[0]
+		if err := views.ValidateName(name); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if dash := cmd.ArgsLenAtDash(); dash > 1 || (dash < 0 && len(args) > 1) {
+			FatalErrorRespectJSON("put the bd %s flags after --, e.g. bd view save %s -- --label backend", from, name)
+		}
+
+		view, err := parseViewFlags(name, from, args[1:])
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		view.Description = description
+		if len(columns) > 0 {
+			view.Columns = util.NormalizeLabels(columns)
+		}
+		if err := view.Validate(); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		existing, err := config.GetViews()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if _, ok := existing[name]; ok && !force {
+			FatalErrorRespectJSON("view %q already exists (use --force to replace it)", name)
+		}
+		configPath, err := config.FindConfigYAMLPath()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if err := config.SetViewInYAML(configPath, name, &view.ViewConfig); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(view)
+			return
+		}
+		fmt.Printf("%s Saved view %s: %s\n", ui.RenderPass("✓"), name, view.CommandLine())
+	},
+}
+
+var viewRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a saved view",
+	Long: `Run a saved view and show the matching issues as a table of the view's
+columns. Relative dates in the view are resolved now.
+
+Examples:
+  bd view run backend-bugs
+  bd view run backend-bugs --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := rootCtx
+
+		var view *views.View
+		var issues []*types.Issue
+		if daemonClient != nil {
+			resp, err := daemonClient.ViewRun(&rpc.ViewRunArgs{Name: args[0]})
+			if err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			view, issues = resp.View, resp.Issues
+		} else {
+			if err := ensureDatabaseFresh(ctx); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			var err error
+			if view, err = views.Load(args[0]); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if err := view.Validate(); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+			if issues, err = views.Run(ctx, store, view, time.Now(), actor); err != nil {
+				FatalErrorRespectJSON("%v", err)
+			}
+		}
+
+		if jsonOutput {
+			if issues == nil {
+				issues = []*types.Issue{}
+			}
+			outputJSON(issues)
+			return
+		}
+		if len(issues) == 0 {
+			fmt.Printf("No issues match view %s\n", view.Name)
+			return
+		}
+
+		columns := view.Columns
+		if len(columns) == 0 {
+			columns = views.DefaultColumns
+		}
+		var buf strings.Builder
+		formatIssueColumns(&buf, issues, columns)
+		fmt.Print(buf.String())
+		if limit := view.EffectiveLimit(); limit > 0 && len(issues) == limit {
+			fmt.Fprintf(os.Stderr, "\nShowing %d issues (use bd %s --view %s --limit 0 for all)\n", limit, view.Cmd(), view.Name)
+		}
+	},
+}
+
+var viewListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved views",
+	Run: func(cmd *cobra.Command, args []string) {
+		all, err := config.GetViews()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		list := views.List(all)
+
+		if jsonOutput {
+			outputJSON(list)
+			return
+		}
+		if len(list) == 0 {
+			fmt.Println("No saved views (see bd view save --help)")
+			return
+		}
+		for _, v := range list {
+			fmt.Printf("%s  %s\n", ui.RenderBold(v.Name), v.CommandLine())
+			if v.Description != "" {
+				fmt.Printf("    %s\n", ui.RenderMuted(v.Description))
+			}
+		}
+	},
+}
+
+var viewDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved view",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		view, err := views.Load(args[0])
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		configPath, err := config.FindConfigYAMLPath()
+		if err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+		if err := config.SetViewInYAML(configPath, view.Name, nil); err != nil {
+			FatalErrorRespectJSON("%v", err)
+		}
+
+		if jsonOutput {
+			outputJSON(map[string]string{"deleted": view.Name})
+			return
+		}
+		fmt.Printf("%s Deleted view %s\n", ui.RenderPass("✓"), view.Name)
+	},
+}
+
+// parseViewFlags parses the bd list or bd ready flags in args into a view.
+// The flags are parsed into a copy of the command's flag definitions, so the
+// command itself is left untouched.
+func parseViewFlags(name, command string, args []string) (*views.View, error) {
+	var src *cobra.Command
+	switch command {
+	case views.CommandList:
+		src = listCmd
+	case views.CommandReady:
+		src = readyCmd
+	default:
+		return nil, fmt.Errorf("invalid --from %q (expected %s or %s)", command, views.CommandList, views.CommandReady)
+	}
+
+	parser := &cobra.Command{Use: command}
+	fs := parser.Flags()
+	for _, flagName := range views.FlagNames(command) {
+		f := src.Flags().Lookup(flagName)
+		if f == nil {
+			continue
+		}
+		switch f.Value.Type() {
+		case "bool":
+			fs.BoolP(f.Name, f.Shorthand, false, f.Usage)
+		case "int":
+			fs.IntP(f.Name, f.Shorthand, 0, f.Usage)
+		case "stringSlice":
+			fs.StringSliceP(f.Name, f.Shorthand, nil, f.Usage)
+		default:
+			fs.StringP(f.Name, f.Shorthand, "", f.Usage)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		if strings.HasPrefix(err.Error(), "unknown") {
+			return nil, fmt.Errorf("%w (views save bd %s filter flags only)", err, command)
+		}
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	view := &views.View{Name: name}
+	if command != views.CommandList {
+		view.Command = command
+	}
+	for _, flagName := range views.FlagNames(command) {
+		if !fs.Changed(flagName) {
+			continue
+		}
+		f := fs.Lookup(flagName)
+		value := f.Value.String()
+		if f.Value.Type() == "stringSlice" {
+			list, _ := fs.GetStringSlice(flagName)
+			value = strings.Join(list, ",")
+		}
+		if err := view.Set(flagName, value); err != nil {
+			return nil, err
+		}
+	}
+	return view, nil
+}
+
+// applyView sets the flags saved in the view called name on cmd, which must
+// be the view's command. Flags already given on the command line are left
+// alone, so they refine the view.
+func applyView(cmd *cobra.Command, name, command string) error {
+	view, err := views.Load(name)
+	if err != nil {
+		return err
+	}
+	if view.Cmd() != command {
+		return fmt.Errorf("view %q is a bd %s view (run it with bd view run %s)", view.Name, view.Cmd(), view.Name)
+	}
+	if err := view.Validate(); err != nil {
+		return err
+	}
+	for _, f := range view.Flags() {
+		if cmd.Flags().Changed(f.Name) {
+			continue
+		}
+		if err := cmd.Flags().Set(f.Name, f.Value); err != nil {
+			return fmt.Errorf("view %q: --%s: %w", view.Name, f.Name, err)
+		}
+	}
+	return nil
+}
+
+// formatIssueColumns writes issues as a table of columns. Issue labels must
+// be loaded for the labels column.
+func formatIssueColumns(buf *strings.Builder, issues []*types.Issue, columns []string) {
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = strings.ToUpper(c)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, issue := range issues {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = strings.ReplaceAll(views.Value(issue, c), "\t", " ")
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	_ = tw.Flush()
+}
+
+func init() {
+	viewSaveCmd.Flags().String("from", views.CommandList, "Command whose flags are saved: list or ready")
+	viewSaveCmd.Flags().StringP("description", "d", "", "What the view is for")
+	viewSaveCmd.Flags().StringSlice("columns", nil, "Columns for bd view run: "+strings.Join(views.Columns, ", "))
+	viewSaveCmd.Flags().Bool("force", false, "Replace an existing view of the same name")
+	viewCmd.AddCommand(viewSaveCmd, viewRunCmd, viewListCmd, viewDeleteCmd)
+	rootCmd.AddCommand(viewCmd)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/views"
+)
+
+func TestParseViewFlags(t *testing.T) {
+	view, err := parseViewFlags("mine", views.CommandList, []string{
+		"-t", "bug", "-l", "backend", "--label", "api", "--created-after", "-7d",
+		"--priority", "P1", "--no-assignee", "--sort", "updated", "-r", "-n", "0",
+	})
+	if err != nil {
+		t.Fatalf("parseViewFlags: %v", err)
+	}
+	if view.Command != "" || view.Filter.Type != "bug" || view.Filter.CreatedAfter != "-7d" || view.Filter.Priority != "P1" {
+		t.Errorf("view = %+v", view.ViewConfig)
+	}
+	if got := strings.Join(view.Filter.Labels, ","); got != "backend,api" {
+		t.Errorf("labels = %s, want backend,api", got)
+	}
+	if !view.Filter.NoAssignee || !view.Reverse || view.Sort != "updated" || view.Limit == nil || *view.Limit != 0 {
+		t.Errorf("view = %+v", view.ViewConfig)
+	}
+	// The flags went into a copy: bd list itself is untouched
+	if listCmd.Flags().Changed("type") {
+		t.Error("parseViewFlags changed bd list's own flags")
+	}
+
+	ready, err := parseViewFlags("next", views.CommandReady, []string{"-u", "--sort", "oldest", "-p", "1"})
+	if err != nil {
+		t.Fatalf("parseViewFlags(ready): %v", err)
+	}
+	if ready.Command != views.CommandReady || !ready.Filter.Unassigned || ready.Sort != "oldest" || ready.Filter.Priority != "1" {
+		t.Errorf("ready view = %+v", ready.ViewConfig)
+	}
+
+	for _, tt := range []struct {
+		command string
+		args    []string
+		wantErr string
+	}{
+		{views.CommandList, []string{"--pretty"}, "unknown flag: --pretty"},
+		{views.CommandList, []string{"--type", "bug", "extra"}, "unexpected argument"},
+		{views.CommandReady, []string{"--created-after", "-1d"}, "unknown flag"},
+		{"show", nil, "invalid --from"},
+	} {
+		if _, err := parseViewFlags("x", tt.command, tt.args); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseViewFlags(%s %v) error = %v, want %q", tt.command, tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatIssueColumns(t *testing.T) {
+	issues := []*types.Issue{
+		{ID: "bd-1", Title: "Short", Priority: 0, Labels: []string{"api"}},
+		{ID: "bd-22", Title: "Tab\there", Priority: 3},
+	}
+	var buf strings.Builder
+	formatIssueColumns(&buf, issues, []string{"id", "priority", "labels", "title"})
+
+	want := "ID     PRIORITY  LABELS  TITLE\n" +
+		"bd-1   P0        api     Short\n" +
+		"bd-22  P3                Tab here\n"
+	if buf.String() != want {
+		t.Errorf("formatIssueColumns() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,193 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var t0 = time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC) // A Monday
+
+func day(n int) time.Time { return t0.AddDate(0, 0, n) }
+
+func strPtr(s string) *string { return &s }
+
+// fixture returns three issues with their event histories:
+//   - flow-1: started day 1, closed day 3, reopened day 4, closed again day 5
+//   - flow-2: imported already closed (created day 0, closed day 2)
+//   - flow-3: still in progress since day 1, recorded as a bare status
+func fixture() ([]*types.Issue, []*types.Event) {
+	closed2, closed5 := day(2), day(5)
+	issues := []*types.Issue{
+		{ID: "flow-1", Status: types.StatusClosed, IssueType: types.TypeBug, CreatedAt: t0, ClosedAt: &closed5, UpdatedAt: closed5},
+		{ID: "flow-2", Status: types.StatusClosed, IssueType: types.TypeTask, CreatedAt: t0, ClosedAt: &closed2, UpdatedAt: day(6)},
+		{ID: "flow-3", Status: types.StatusInProgress, IssueType: types.TypeTask, CreatedAt: t0, UpdatedAt: day(1)},
+	}
+	events := []*types.Event{
+		// Out of order on purpose
+		{ID: 5, IssueID: "flow-1", EventType: types.EventClosed, CreatedAt: day(5)},
+		{ID: 1, IssueID: "flow-1", EventType: types.EventCreated, NewValue: strPtr(`{"id":"flow-1","status":"open"}`), CreatedAt: t0},
+		{ID: 2, IssueID: "flow-1", EventType: types.EventStatusChanged, NewValue: strPtr(`{"status":"in_progress"}`), CreatedAt: day(1)},
+		{ID: 3, IssueID: "flow-1", EventType: types.EventClosed, CreatedAt: day(3)},
+		{ID: 4, IssueID: "flow-1", EventType: types.EventReopened, NewValue: strPtr(`{"status":"open"}`), CreatedAt: day(4)},
+		{ID: 6, IssueID: "flow-2", EventType: types.EventCreated, NewValue: strPtr(`{"id":"flow-2","status":"closed"}`), CreatedAt: day(6)},
+		{ID: 7, IssueID: "flow-3", EventType: types.EventCreated, CreatedAt: t0},
+		{ID: 8, IssueID: "flow-3", EventType: types.EventStatusChanged, OldValue: strPtr("open"), NewValue: strPtr("in_progress"), CreatedAt: day(1)},
+	}
+	return issues, events
+}
+
+func TestBuildHistories(t *testing.T) {
+	issues, events := fixture()
+	histories := BuildHistories(issues, events)
+
+	want := map[string][]types.Status{
+		"flow-1": {types.StatusOpen, types.StatusInProgress, types.StatusClosed, types.StatusOpen, types.StatusClosed},
+		"flow-2": {types.StatusOpen, types.StatusClosed},
+		"flow-3": {types.StatusOpen, types.StatusInProgress},
+	}
+	for id, statuses := range want {
+		h := histories[id]
+		if len(h.Transitions) != len(statuses) {
+			t.Fatalf("%s transitions = %+v, want %v", id, h.Transitions, statuses)
+		}
+		for i, s := range statuses {
+			if h.Transitions[i].Status != s {
+				t.Errorf("%s transition %d = %s, want %s", id, i, h.Transitions[i].Status, s)
+			}
+		}
+	}
+
+	h := histories["flow-1"]
+	if s, ok := h.StatusAt(day(3).Add(time.Hour)); !ok || s != types.StatusClosed {
+		t.Errorf("flow-1 status on day 3 = %s, want closed", s)
+	}
+	if _, ok := h.StatusAt(t0.Add(-time.Hour)); ok {
+		t.Error("flow-1 should not exist before it was created")
+	}
+
+	completions := h.Completions()
+	if len(completions) != 2 {
+		t.Fatalf("flow-1 completions = %+v, want 2", completions)
+	}
+	if !completions[0].Reopened || completions[1].Reopened {
+		t.Errorf("reopened flags = %v/%v, want true/false", completions[0].Reopened, completions[1].Reopened)
+	}
+	if completions[0].Started == nil || !completions[0].Started.Equal(day(1)) || completions[1].Started != nil {
+		t.Errorf("started = %v/%v, want day 1 then never", completions[0].Started, completions[1].Started)
+	}
+}
+
+func TestBuildHistoriesReconcilesCurrentStatus(t *testing.T) {
+	// No events at all, e.g. a status edited directly in the database
+	updated := day(2)
+	issue := &types.Issue{ID: "flow-9", Status: types.StatusBlocked, CreatedAt: t0, UpdatedAt: updated}
+	h := BuildHistories([]*types.Issue{issue}, nil)["flow-9"]
+	if s, _ := h.StatusAt(day(1)); s != types.StatusOpen {
+		t.Errorf("status on day 1 = %s, want open", s)
+	}
+	if s, _ := h.StatusAt(day(3)); s != types.StatusBlocked {
+		t.Errorf("status on day 3 = %s, want the current status", s)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	issues, events := fixture()
+	histories := BuildHistories(issues, events)
+	opts := Options{Since: t0, Until: day(14)}
+
+	summaries := Summarize(histories, opts)
+	if len(summaries) != 1 {
+		t.Fatalf("summaries = %+v, want one unsegmented row", summaries)
+	}
+	s := summaries[0]
+	if s.Segment != NoSegment || s.Completed != 3 || s.Reopened != 1 {
+		t.Errorf("summary = %+v, want 3 completions with 1 reopened", s)
+	}
+	if s.Throughput != 1.5 {
+		t.Errorf("throughput = %v per week, want 1.5", s.Throughput)
+	}
+	// Only flow-1's first close went through in_progress: 2 days
+	if s.CycleTime.Count != 1 || s.CycleTime.Median != 48 {
+		t.Errorf("cycle time = %+v, want one of 48h", s.CycleTime)
+	}
+	// Lead times 48h (flow-2), 72h and 120h (flow-1)
+	if s.LeadTime.Count != 3 || s.LeadTime.Median != 72 || s.LeadTime.P85 != 120 || s.LeadTime.Mean != 80 {
+		t.Errorf("lead time = %+v", s.LeadTime)
+	}
+
+	opts.By = ByType
+	byType := Summarize(histories, opts)
+	if len(byType) != 2 || byType[0].Segment != "bug" || byType[0].Completed != 2 || byType[1].Segment != "task" {
+		t.Errorf("by type = %+v, want bug (2) then task (1)", byType)
+	}
+
+	// A window after all the closes
+	opts = Options{Since: day(6), Until: day(14)}
+	if got := Summarize(histories, opts); len(got) != 0 {
+		t.Errorf("summaries outside the window = %+v", got)
+	}
+}
+
+func TestSegments(t *testing.T) {
+	issue := &types.Issue{ID: "flow-1", IssueType: types.TypeBug}
+	opts := Options{
+		Labels: map[string][]string{"flow-1": {"backend", "urgent"}},
+		Epics:  map[string]string{"flow-1": "flow-epic"},
+	}
+	for by, want := range map[Segment][]string{
+		ByNone:     {NoSegment},
+		ByType:     {"bug"},
+		ByAssignee: {NoSegment},
+		ByLabel:    {"backend", "urgent"},
+		ByEpic:     {"flow-epic"},
+	} {
+		opts.By = by
+		got := opts.segments(issue)
+		if len(got) != len(want) || got[0] != want[0] {
+			t.Errorf("segments by %q = %v, want %v", by, got, want)
+		}
+	}
+	if _, err := ParseSegment("priority"); err == nil {
+		t.Error("ParseSegment should reject unknown segments")
+	}
+}
+
+func TestThroughput(t *testing.T) {
+	issues, events := fixture()
+	weeks := Throughput(BuildHistories(issues, events), Options{Since: t0, Until: day(13)})
+	if len(weeks) != 2 {
+		t.Fatalf("weeks = %+v, want 2", weeks)
+	}
+	if !weeks[0].Week.Equal(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)) || weeks[0].Completed != 3 || weeks[1].Completed != 0 {
+		t.Errorf("weeks = %+v / %+v, want 3 completions in the first week only", weeks[0], weeks[1])
+	}
+}
+
+func TestCumulativeFlow(t *testing.T) {
+	issues, events := fixture()
+	points := CumulativeFlow(BuildHistories(issues, events), Options{Since: t0, Until: day(5).Add(time.Hour)})
+	if len(points) != 6 {
+		t.Fatalf("points = %d, want one per day for days 0-5", len(points))
+	}
+	day1 := points[1].Counts
+	if day1[types.StatusInProgress] != 2 || day1[types.StatusOpen] != 1 {
+		t.Errorf("day 1 = %v, want 2 in progress and 1 open", day1)
+	}
+	day5 := points[5].Counts
+	if day5[types.StatusClosed] != 2 || day5[types.StatusInProgress] != 1 {
+		t.Errorf("day 5 = %v, want 2 closed and 1 in progress", day5)
+	}
+
+	statuses := FlowStatuses(points)
+	want := []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusClosed}
+	if len(statuses) != len(want) {
+		t.Fatalf("statuses = %v, want %v", statuses, want)
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("statuses = %v, want %v", statuses, want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ScopeEventTypes are the event types that add children to or remove them
+// from an epic or molecule.
+var ScopeEventTypes = []types.EventType{
+	types.EventDependencyAdded,
+	types.EventDependencyRemoved,
+}
+
+// Membership is a span of time during which an issue was a child of the
+// parent being burned down.
+type Membership struct {
+	IssueID string
+	From    time.Time
+	Until   *time.Time // nil while still a child
+}
+
+// Memberships reconstructs when each child joined and left parentID from
+// dependency events (recorded on the child, in any order). current lists the
+// parent-child dependencies that exist now; children linked without an event
+// (for example by import) are counted from the dependency's creation.
+func Memberships(parentID string, events []*types.Event, current []*types.Dependency) []Membership {
+	sorted := append([]*types.Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].ID < sorted[j].ID
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var spans []Membership
+	open := make(map[string]int) // Child ID -> index of its open span
+	for _, e := range sorted {
+		switch {
+		case isChildAdded(e, parentID):
+			if _, ok := open[e.IssueID]; !ok {
+				open[e.IssueID] = len(spans)
+				spans = append(spans, Membership{IssueID: e.IssueID, From: e.CreatedAt})
+			}
+		case isDependencyRemoved(e, parentID):
+			if i, ok := open[e.IssueID]; ok {
+				at := e.CreatedAt
+				spans[i].Until = &at
+				delete(open, e.IssueID)
+			}
+		}
+	}
+
+	linked := make(map[string]bool)
+	for _, dep := range current {
+		if dep.Type != types.DepParentChild || dep.DependsOnID != parentID {
+			continue
+		}
+		linked[dep.IssueID] = true
+		if _, ok := open[dep.IssueID]; !ok {
+			open[dep.IssueID] = len(spans)
+			spans = append(spans, Membership{IssueID: dep.IssueID, From: dep.CreatedAt})
+		}
+	}
+	// A child no longer linked whose removal wasn't recorded left at some
+	// unknown point; drop the span rather than count it forever
+	kept := spans[:0]
+	for _, m := range spans {
+		if m.Until == nil && !linked[m.IssueID] {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// isChildAdded matches "Added dependency: <child> parent-child <parent>".
+func isChildAdded(e *types.Event, parentID string) bool {
+	if e.EventType != types.EventDependencyAdded || e.Comment == nil {
+		return false
+	}
+	fields := strings.Fields(strings.TrimPrefix(*e.Comment, "Added dependency:"))
+	return len(fields) == 3 && fields[0] == e.IssueID &&
+		fields[1] == string(types.DepParentChild) && fields[2] == parentID
+}
+
+// isDependencyRemoved matches "Removed dependency on <parent>".
+func isDependencyRemoved(e *types.Event, parentID string) bool {
+	return e.EventType == types.EventDependencyRemoved && e.Comment != nil &&
+		strings.TrimSpace(strings.TrimPrefix(*e.Comment, "Removed dependency on")) == parentID
+}
+
+// BurndownPoint is the state of a parent's scope at the end of one day.
+type BurndownPoint struct {
+	Date             time.Time `json:"date"`
+	Total            int       `json:"total"` // Children in scope
+	Completed        int       `json:"completed"`
+	Remaining        int       `json:"remaining"`
+	TotalMinutes     int       `json:"total_minutes"`
+	CompletedMinutes int       `json:"completed_minutes"`
+	RemainingMinutes int       `json:"remaining_minutes"`
+}
+
+// Projection estimates when the remaining work will be done, from the net
+// drop in remaining work over the velocity window. Scope added during the
+// window slows the projection down.
+type Projection struct {
+	WindowDays     int        `json:"window_days"`
+	PerDay         float64    `json:"per_day"`                   // Net drop in remaining children per day
+	MinutesPerDay  float64    `json:"minutes_per_day"`           // Net drop in remaining estimate per day
+	Finish         *time.Time `json:"finish,omitempty"`          // From PerDay; nil if not burning down
+	FinishEstimate *time.Time `json:"finish_estimate,omitempty"` // From MinutesPerDay, when children have estimates
+	Done           bool       `json:"done"`                      // Nothing remaining
+}
+
+// BurndownOptions selects the days to chart.
+type BurndownOptions struct {
+	Since      time.Time // First day; defaults to the parent's creation
+	Until      time.Time // Last day; defaults to now
+	WindowDays int       // Days of history behind the projection; defaults to 14
+}
+
+// Burndown rebuilds the daily scope, completed and remaining counts (and
+// estimated minutes) for a parent from its children's histories and
+// memberships.
+func Burndown(parent *types.Issue, histories map[string]*History, members []Membership, opts BurndownOptions) ([]*BurndownPoint, Projection) {
+	if opts.Until.IsZero() {
+		opts.Until = time.Now()
+	}
+	if opts.Since.IsZero() {
+		opts.Since = parent.CreatedAt
+		for _, m := range members {
+			if m.From.Before(opts.Since) {
+				opts.Since = m.From
+			}
+		}
+	}
+	if opts.WindowDays <= 0 {
+		opts.WindowDays = 14
+	}
+
+	loc := opts.Until.Location()
+	since := opts.Since.In(loc)
+	var points []*BurndownPoint
+	for day := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, loc); !day.After(opts.Until); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if end.After(opts.Until) {
+			end = opts.Until
+		}
+		points = append(points, burndownPoint(day, end, histories, members))
+	}
+	return points, project(points, opts.WindowDays, opts.Until)
+}
+
+func burndownPoint(day, end time.Time, histories map[string]*History, members []Membership) *BurndownPoint {
+	p := &BurndownPoint{Date: day}
+	counted := make(map[string]bool)
+	for _, m := range members {
+		if counted[m.IssueID] || m.From.After(end) || (m.Until != nil && !m.Until.After(end)) {
+			continue
+		}
+		h := histories[m.IssueID]
+		if h == nil {
+			continue
+		}
+		status, ok := h.StatusAt(end)
+		if !ok || status == types.StatusTombstone {
+			continue
+		}
+		counted[m.IssueID] = true
+		minutes := h.EstimateAt(end)
+		p.Total++
+		p.TotalMinutes += minutes
+		if status == types.StatusClosed {
+			p.Completed++
+			p.CompletedMinutes += minutes
+		}
+	}
+	p.Remaining = p.Total - p.Completed
+	p.RemainingMinutes = p.TotalMinutes - p.CompletedMinutes
+	return p
+}
+
+// project extrapolates the finish date from the last windowDays of points.
+func project(points []*BurndownPoint, windowDays int, now time.Time) Projection {
+	proj := Projection{WindowDays: windowDays}
+	if len(points) == 0 {
+		return proj
+	}
+	last := points[len(points)-1]
+	if last.Remaining == 0 {
+		proj.Done = last.Total > 0
+		return proj
+	}
+	first := points[max(len(points)-1-windowDays, 0)]
+	days := last.Date.Sub(first.Date).Hours() / 24
+	if days < 1 {
+		return proj
+	}
+	proj.PerDay = float64(first.Remaining-last.Remaining) / days
+	proj.MinutesPerDay = float64(first.RemainingMinutes-last.RemainingMinutes) / days
+	proj.Finish = finishDate(now, float64(last.Remaining), proj.PerDay)
+	if last.RemainingMinutes > 0 {
+		proj.FinishEstimate = finishDate(now, float64(last.RemainingMinutes), proj.MinutesPerDay)
+	}
+	return proj
+}
+
+func finishDate(now time.Time, remaining, perDay float64) *time.Time {
+	if perDay <= 0 {
+		return nil
+	}
+	days := int(math.Ceil(remaining / perDay))
+	finish := now.AddDate(0, 0, days)
+	return &finish
+}
@@ -0,0 +1,106 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func depEvent(id int64, child string, added bool, parent string, at time.Time) *types.Event {
+	if added {
+		return &types.Event{ID: id, IssueID: child, EventType: types.EventDependencyAdded, CreatedAt: at,
+			Comment: strPtr("Added dependency: " + child + " parent-child " + parent)}
+	}
+	return &types.Event{ID: id, IssueID: child, EventType: types.EventDependencyRemoved, CreatedAt: at,
+		Comment: strPtr("Removed dependency on " + parent)}
+}
+
+func TestMemberships(t *testing.T) {
+	events := []*types.Event{
+		depEvent(3, "mol-c", false, "mol", day(3)),
+		depEvent(1, "mol-a", true, "mol", t0),
+		depEvent(2, "mol-c", true, "mol", t0),
+		depEvent(4, "mol-x", true, "other", t0), // Another parent
+		{ID: 5, IssueID: "mol-a", EventType: types.EventDependencyAdded, CreatedAt: t0,
+			Comment: strPtr("Added dependency: mol-a blocks mol")}, // Not parent-child
+		depEvent(6, "mol-gone", true, "mol", t0), // Unlinked without a removal event
+	}
+	current := []*types.Dependency{
+		{IssueID: "mol-a", DependsOnID: "mol", Type: types.DepParentChild, CreatedAt: t0},
+		{IssueID: "mol-d", DependsOnID: "mol", Type: types.DepParentChild, CreatedAt: day(2)}, // Imported
+	}
+
+	got := make(map[string]Membership)
+	for _, m := range Memberships("mol", events, current) {
+		got[m.IssueID] = m
+	}
+	if len(got) != 3 {
+		t.Fatalf("memberships = %+v, want mol-a, mol-c and mol-d", got)
+	}
+	if m := got["mol-c"]; m.Until == nil || !m.Until.Equal(day(3)) {
+		t.Errorf("mol-c = %+v, want removed on day 3", m)
+	}
+	if m := got["mol-d"]; !m.From.Equal(day(2)) || m.Until != nil {
+		t.Errorf("mol-d = %+v, want linked from day 2 on", m)
+	}
+}
+
+func TestBurndown(t *testing.T) {
+	est := func(m int) *int { return &m }
+	closedA, closedB := day(2), day(5)
+	parent := &types.Issue{ID: "mol", CreatedAt: t0}
+	issues := []*types.Issue{
+		{ID: "mol-a", Status: types.StatusClosed, CreatedAt: t0, ClosedAt: &closedA, EstimatedMinutes: est(120)},
+		{ID: "mol-b", Status: types.StatusClosed, CreatedAt: day(1), ClosedAt: &closedB, EstimatedMinutes: est(180)},
+		{ID: "mol-c", Status: types.StatusOpen, CreatedAt: t0, EstimatedMinutes: est(60)},
+	}
+	events := []*types.Event{
+		{ID: 1, IssueID: "mol-a", EventType: types.EventCreated, NewValue: strPtr(`{"status":"open","estimated_minutes":120}`), CreatedAt: t0},
+		{ID: 2, IssueID: "mol-a", EventType: types.EventClosed, CreatedAt: day(2)},
+		// Scope added on day 1, re-estimated on day 3
+		{ID: 3, IssueID: "mol-b", EventType: types.EventCreated, NewValue: strPtr(`{"status":"open","estimated_minutes":60}`), CreatedAt: day(1)},
+		{ID: 4, IssueID: "mol-b", EventType: types.EventUpdated, NewValue: strPtr(`{"estimated_minutes":180}`), CreatedAt: day(3)},
+		{ID: 5, IssueID: "mol-b", EventType: types.EventClosed, CreatedAt: day(5)},
+		{ID: 6, IssueID: "mol-c", EventType: types.EventCreated, NewValue: strPtr(`{"status":"open","estimated_minutes":60}`), CreatedAt: t0},
+	}
+	members := []Membership{
+		{IssueID: "mol-a", From: t0},
+		{IssueID: "mol-b", From: day(1)},
+		{IssueID: "mol-c", From: t0},
+	}
+
+	points, proj := Burndown(parent, BuildHistories(issues, events), members, BurndownOptions{Until: day(6), WindowDays: 3})
+	if len(points) != 7 {
+		t.Fatalf("points = %d, want days 0-6", len(points))
+	}
+	want := []struct{ total, completed, remainingMinutes int }{
+		{2, 0, 180}, // a + c
+		{3, 0, 240}, // b added
+		{3, 1, 120}, // a closed
+		{3, 1, 240}, // b re-estimated to 180
+		{3, 1, 240},
+		{3, 2, 60}, // b closed
+		{3, 2, 60},
+	}
+	for i, w := range want {
+		p := points[i]
+		if p.Total != w.total || p.Completed != w.completed || p.RemainingMinutes != w.remainingMinutes {
+			t.Errorf("day %d = %+v, want total %d, completed %d, remaining %dm", i, p, w.total, w.completed, w.remainingMinutes)
+		}
+	}
+
+	// Remaining went from 2 on day 3 to 1 on day 6: 1/3 per day, so 3 more days
+	if proj.Done || proj.Finish == nil || !proj.Finish.Equal(day(9)) {
+		t.Errorf("projection = %+v, want finish on day 9", proj)
+	}
+	if proj.FinishEstimate == nil || !proj.FinishEstimate.Equal(day(7)) {
+		t.Errorf("estimate projection = %v, want day 7 (60m left at 60m/day)", proj.FinishEstimate)
+	}
+
+	// Nothing burned in the window: no projection
+	_, proj = Burndown(parent, BuildHistories(issues, events), members, BurndownOptions{Until: day(9), WindowDays: 2})
+	if proj.Finish != nil {
+		t.Errorf("projection = %v with no progress in the window", proj.Finish)
+	}
+}
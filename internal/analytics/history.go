@@ -0,0 +1,248 @@
+// Package analytics computes flow metrics (cycle time, lead time,
+// throughput, cumulative flow and reopen rate) from the event history.
+//
+// Metrics are rebuilt from status events rather than from the current state
+// of each issue, so an issue that was reopened and closed again, or that sat
+// in progress for a week before being closed, is counted as it happened.
+package analytics
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// HistoryEventTypes are the event types that change an issue's status.
+// Pass them as EventFilter.Types when loading events for BuildHistories;
+// add EventUpdated to also track estimate changes.
+var HistoryEventTypes = []types.EventType{
+	types.EventCreated,
+	types.EventStatusChanged,
+	types.EventClosed,
+	types.EventReopened,
+}
+
+// Transition is a change of status in an issue's history.
+type Transition struct {
+	At     time.Time    `json:"at"`
+	Status types.Status `json:"status"`
+}
+
+// EstimateChange is a change of an issue's estimate in its history.
+type EstimateChange struct {
+	At      time.Time `json:"at"`
+	Minutes int       `json:"minutes"` // 0 when the estimate was cleared
+}
+
+// History is the reconstructed status timeline of one issue.
+type History struct {
+	Issue       *types.Issue
+	Transitions []Transition     // Oldest first; the first is the creation
+	Estimates   []EstimateChange // Oldest first; only from created and updated events
+}
+
+// Completion is one close of an issue. An issue that was reopened and closed
+// again completes more than once.
+type Completion struct {
+	IssueID  string
+	Created  time.Time
+	Started  *time.Time // First time in progress since the previous close; nil if never started
+	Closed   time.Time
+	Reopened bool // Reopened at some point after this close
+}
+
+// BuildHistories reconstructs the status timeline of each issue from its
+// events, which may be in any order. Issues without a creation event start
+// open at their creation time, and a timeline that does not end in the
+// issue's current status (for example after a direct database edit) is
+// closed off with the current status.
+func BuildHistories(issues []*types.Issue, events []*types.Event) map[string]*History {
+	byIssue := make(map[string][]*types.Event)
+	for _, e := range events {
+		byIssue[e.IssueID] = append(byIssue[e.IssueID], e)
+	}
+
+	histories := make(map[string]*History, len(issues))
+	for _, issue := range issues {
+		issueEvents := byIssue[issue.ID]
+		sort.SliceStable(issueEvents, func(i, j int) bool {
+			if issueEvents[i].CreatedAt.Equal(issueEvents[j].CreatedAt) {
+				return issueEvents[i].ID < issueEvents[j].ID
+			}
+			return issueEvents[i].CreatedAt.Before(issueEvents[j].CreatedAt)
+		})
+
+		h := &History{Issue: issue}
+		initial := types.StatusOpen
+		for _, e := range issueEvents {
+			if e.EventType == types.EventCreated {
+				if status, ok := eventStatus(e); ok {
+					initial = status
+				}
+				break
+			}
+		}
+		for _, e := range issueEvents {
+			if minutes, ok := eventEstimate(e); ok {
+				at := e.CreatedAt
+				if e.EventType == types.EventCreated {
+					at = issue.CreatedAt
+				}
+				h.Estimates = append(h.Estimates, EstimateChange{At: at, Minutes: minutes})
+			}
+		}
+		if initial == types.StatusClosed && issue.ClosedAt != nil && issue.ClosedAt.After(issue.CreatedAt) {
+			// Imported already closed: open from creation until it was closed
+			h.add(issue.CreatedAt, types.StatusOpen)
+			h.add(*issue.ClosedAt, types.StatusClosed)
+		} else {
+			h.add(issue.CreatedAt, initial)
+		}
+
+		for _, e := range issueEvents {
+			if e.EventType == types.EventCreated {
+				continue
+			}
+			if status, ok := eventStatus(e); ok {
+				h.add(e.CreatedAt, status)
+			}
+		}
+
+		if last := h.Transitions[len(h.Transitions)-1]; last.Status != issue.Status {
+			at := issue.UpdatedAt
+			if issue.Status == types.StatusClosed && issue.ClosedAt != nil {
+				at = *issue.ClosedAt
+			}
+			h.add(at, issue.Status)
+		}
+		histories[issue.ID] = h
+	}
+	return histories
+}
+
+// add appends a transition, skipping ones that don't change the status.
+// Event timestamps may be truncated to the second, so a transition is never
+// placed before the one it follows.
+func (h *History) add(at time.Time, status types.Status) {
+	if n := len(h.Transitions); n > 0 {
+		last := h.Transitions[n-1]
+		if last.Status == status {
+			return
+		}
+		if at.Before(last.At) {
+			at = last.At
+		}
+	}
+	h.Transitions = append(h.Transitions, Transition{At: at, Status: status})
+}
+
+// StatusAt returns the issue's status at time t, and false if the issue did
+// not exist yet.
+func (h *History) StatusAt(t time.Time) (types.Status, bool) {
+	var status types.Status
+	found := false
+	for _, tr := range h.Transitions {
+		if tr.At.After(t) {
+			break
+		}
+		status, found = tr.Status, true
+	}
+	return status, found
+}
+
+// EstimateAt returns the issue's estimate in minutes at time t. Without any
+// recorded estimate history the current estimate is used throughout.
+func (h *History) EstimateAt(t time.Time) int {
+	if len(h.Estimates) == 0 {
+		if h.Issue.EstimatedMinutes != nil {
+			return *h.Issue.EstimatedMinutes
+		}
+		return 0
+	}
+	minutes := h.Estimates[0].Minutes
+	for _, e := range h.Estimates {
+		if e.At.After(t) {
+			break
+		}
+		minutes = e.Minutes
+	}
+	return minutes
+}
+
+// Completions returns every close of the issue, oldest first.
+func (h *History) Completions() []Completion {
+	var completions []Completion
+	var started *time.Time
+	prev := types.Status("")
+	for _, tr := range h.Transitions {
+		switch {
+		case tr.Status == types.StatusClosed && prev != types.StatusClosed:
+			completions = append(completions, Completion{
+				IssueID: h.Issue.ID,
+				Created: h.Transitions[0].At,
+				Started: started,
+				Closed:  tr.At,
+			})
+			started = nil
+		case prev == types.StatusClosed:
+			completions[len(completions)-1].Reopened = true
+		}
+		if tr.Status == types.StatusInProgress && started == nil {
+			at := tr.At
+			started = &at
+		}
+		prev = tr.Status
+	}
+	return completions
+}
+
+// eventStatus returns the status an event moved the issue to. Update events
+// carry the changed fields as JSON; some backends record a bare status.
+func eventStatus(e *types.Event) (types.Status, bool) {
+	if e.EventType == types.EventClosed {
+		return types.StatusClosed, true
+	}
+	if e.NewValue != nil && *e.NewValue != "" {
+		var fields struct {
+			Status types.Status `json:"status"`
+		}
+		if err := json.Unmarshal([]byte(*e.NewValue), &fields); err == nil {
+			if fields.Status != "" {
+				return fields.Status, true
+			}
+		} else if status := types.Status(*e.NewValue); status.IsValid() {
+			return status, true
+		}
+	}
+	if e.EventType == types.EventReopened {
+		return types.StatusOpen, true
+	}
+	return "", false
+}
+
+// eventEstimate returns the estimate an event set. Creation events carry the
+// whole issue, where a missing estimate means none; update events carry only
+// the changed fields.
+func eventEstimate(e *types.Event) (int, bool) {
+	if e.NewValue == nil || (e.EventType != types.EventCreated && e.EventType != types.EventUpdated) {
+		return 0, false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(*e.NewValue), &fields); err != nil {
+		return 0, false
+	}
+	raw, ok := fields["estimated_minutes"]
+	if !ok {
+		return 0, e.EventType == types.EventCreated
+	}
+	var minutes *int
+	if err := json.Unmarshal(raw, &minutes); err != nil {
+		return 0, false
+	}
+	if minutes == nil {
+		return 0, true
+	}
+	return *minutes, true
+}
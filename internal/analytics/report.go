@@ -0,0 +1,304 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Segment is an issue attribute metrics can be broken down by.
+type Segment string
+
+// Segments supported by Options.By.
+const (
+	ByNone     Segment = ""
+	ByType     Segment = "type"
+	ByLabel    Segment = "label"
+	ByAssignee Segment = "assignee"
+	ByEpic     Segment = "epic"
+)
+
+// ParseSegment validates a --by value.
+func ParseSegment(s string) (Segment, error) {
+	switch seg := Segment(s); seg {
+	case ByNone, ByType, ByLabel, ByAssignee, ByEpic:
+		return seg, nil
+	}
+	return "", fmt.Errorf("invalid segment %q (valid: type, label, assignee, epic)", s)
+}
+
+// NoSegment is the segment name for issues without the attribute (no
+// labels, no assignee, not under an epic), and for every issue when
+// metrics are not broken down.
+const NoSegment = "-"
+
+// Options selects the reporting window and breakdown.
+type Options struct {
+	Since time.Time
+	Until time.Time
+	By    Segment
+
+	// Segment attributes; labels and epics come from the current state
+	Labels map[string][]string // Issue ID -> labels, for ByLabel
+	Epics  map[string]string   // Issue ID -> enclosing epic ID, for ByEpic
+}
+
+// segments returns the segments an issue counts toward. An issue with
+// several labels counts toward each of them.
+func (o Options) segments(issue *types.Issue) []string {
+	var keys []string
+	switch o.By {
+	case ByType:
+		keys = []string{string(issue.IssueType)}
+	case ByAssignee:
+		keys = []string{issue.Assignee}
+	case ByLabel:
+		keys = o.Labels[issue.ID]
+	case ByEpic:
+		keys = []string{o.Epics[issue.ID]}
+	}
+	if len(keys) == 0 || (len(keys) == 1 && keys[0] == "") {
+		return []string{NoSegment}
+	}
+	return keys
+}
+
+// inWindow reports whether t falls within the reporting window.
+func (o Options) inWindow(t time.Time) bool {
+	return !t.Before(o.Since) && !t.After(o.Until)
+}
+
+// Durations summarizes a set of durations, in hours.
+type Durations struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean_hours"`
+	Median float64 `json:"median_hours"`
+	P85    float64 `json:"p85_hours"`
+}
+
+func summarizeDurations(hours []float64) Durations {
+	d := Durations{Count: len(hours)}
+	if len(hours) == 0 {
+		return d
+	}
+	sort.Float64s(hours)
+	var sum float64
+	for _, h := range hours {
+		sum += h
+	}
+	d.Mean = sum / float64(len(hours))
+	d.Median = percentile(hours, 50)
+	d.P85 = percentile(hours, 85)
+	return d
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// Summary is the flow metrics for one segment over the reporting window.
+type Summary struct {
+	Segment    string    `json:"segment"`
+	Completed  int       `json:"completed"`
+	Reopened   int       `json:"reopened"`    // Completions in the window later reopened
+	ReopenRate float64   `json:"reopen_rate"` // Reopened / Completed
+	Throughput float64   `json:"throughput_per_week"`
+	CycleTime  Durations `json:"cycle_time"` // First in progress to closed
+	LeadTime   Durations `json:"lead_time"`  // Created to closed
+}
+
+// Summarize computes cycle time, lead time, throughput and reopen rate for
+// each segment, from completions inside the window.
+func Summarize(histories map[string]*History, opts Options) []*Summary {
+	bySegment := make(map[string]*Summary)
+	cycle := make(map[string][]float64)
+	lead := make(map[string][]float64)
+
+	for _, h := range histories {
+		for _, c := range h.Completions() {
+			if !opts.inWindow(c.Closed) {
+				continue
+			}
+			for _, seg := range opts.segments(h.Issue) {
+				s := bySegment[seg]
+				if s == nil {
+					s = &Summary{Segment: seg}
+					bySegment[seg] = s
+				}
+				s.Completed++
+				if c.Reopened {
+					s.Reopened++
+				}
+				lead[seg] = append(lead[seg], c.Closed.Sub(c.Created).Hours())
+				if c.Started != nil {
+					cycle[seg] = append(cycle[seg], c.Closed.Sub(*c.Started).Hours())
+				}
+			}
+		}
+	}
+
+	weeks := opts.Until.Sub(opts.Since).Hours() / (24 * 7)
+	summaries := make([]*Summary, 0, len(bySegment))
+	for seg, s := range bySegment {
+		s.ReopenRate = float64(s.Reopened) / float64(s.Completed)
+		if weeks > 0 {
+			s.Throughput = float64(s.Completed) / weeks
+		}
+		s.CycleTime = summarizeDurations(cycle[seg])
+		s.LeadTime = summarizeDurations(lead[seg])
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Completed != summaries[j].Completed {
+			return summaries[i].Completed > summaries[j].Completed
+		}
+		return summaries[i].Segment < summaries[j].Segment
+	})
+	return summaries
+}
+
+// WeekCount is the number of issues a segment completed in one week.
+type WeekCount struct {
+	Week      time.Time `json:"week"` // Monday the week starts on
+	Segment   string    `json:"segment"`
+	Completed int       `json:"completed"`
+}
+
+// Throughput counts completions per week and segment. Every week of the
+// window is listed for every segment, including weeks with none.
+func Throughput(histories map[string]*History, opts Options) []*WeekCount {
+	// Weeks follow the calendar of the window, keyed by Unix time since equal
+	// times in different locations are different map keys
+	loc := opts.Until.Location()
+	counts := make(map[string]map[int64]int)
+	for _, h := range histories {
+		for _, c := range h.Completions() {
+			if !opts.inWindow(c.Closed) {
+				continue
+			}
+			for _, seg := range opts.segments(h.Issue) {
+				if counts[seg] == nil {
+					counts[seg] = make(map[int64]int)
+				}
+				counts[seg][weekStart(c.Closed.In(loc)).Unix()]++
+			}
+		}
+	}
+	if len(counts) == 0 {
+		counts[NoSegment] = nil
+	}
+
+	var result []*WeekCount
+	for week := weekStart(opts.Since.In(loc)); !week.After(opts.Until); week = week.AddDate(0, 0, 7) {
+		for _, seg := range sortedKeys(counts) {
+			result = append(result, &WeekCount{Week: week, Segment: seg, Completed: counts[seg][week.Unix()]})
+		}
+	}
+	return result
+}
+
+// weekStart returns midnight on the Monday starting t's week, in t's location.
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7 // Days since Monday
+	return day.AddDate(0, 0, -offset)
+}
+
+// FlowPoint is the number of issues in each status for one segment at the
+// end of one day.
+type FlowPoint struct {
+	Date    time.Time            `json:"date"`
+	Segment string               `json:"segment"`
+	Counts  map[types.Status]int `json:"counts"`
+}
+
+// CumulativeFlow counts issues by status at the end of each day of the
+// window. Issues that did not exist yet are left out.
+func CumulativeFlow(histories map[string]*History, opts Options) []*FlowPoint {
+	ids := make([]string, 0, len(histories))
+	for id := range histories {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var result []*FlowPoint
+	since := opts.Since.In(opts.Until.Location())
+	start := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, since.Location())
+	for day := start; !day.After(opts.Until); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if end.After(opts.Until) {
+			end = opts.Until
+		}
+		points := make(map[string]*FlowPoint)
+		for _, id := range ids {
+			h := histories[id]
+			status, ok := h.StatusAt(end)
+			if !ok || status == types.StatusTombstone {
+				continue
+			}
+			for _, seg := range opts.segments(h.Issue) {
+				p := points[seg]
+				if p == nil {
+					p = &FlowPoint{Date: day, Segment: seg, Counts: make(map[types.Status]int)}
+					points[seg] = p
+				}
+				p.Counts[status]++
+			}
+		}
+		for _, seg := range sortedKeys(points) {
+			result = append(result, points[seg])
+		}
+	}
+	return result
+}
+
+// FlowStatuses returns the statuses present in points in workflow order,
+// with custom statuses just before closed.
+func FlowStatuses(points []*FlowPoint) []types.Status {
+	order := map[types.Status]int{
+		types.StatusOpen:       0,
+		types.StatusDeferred:   1,
+		types.StatusBlocked:    2,
+		types.StatusHooked:     3,
+		types.StatusInProgress: 4,
+		types.StatusPinned:     5,
+		types.StatusClosed:     7,
+	}
+	seen := make(map[types.Status]bool)
+	var statuses []types.Status
+	for _, p := range points {
+		for status := range p.Counts {
+			if !seen[status] {
+				seen[status] = true
+				statuses = append(statuses, status)
+			}
+		}
+	}
+	rank := func(s types.Status) int {
+		if r, ok := order[s]; ok {
+			return r
+		}
+		return 6
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if rank(statuses[i]) != rank(statuses[j]) {
+			return rank(statuses[i]) < rank(statuses[j])
+		}
+		return statuses[i] < statuses[j]
+	})
+	return statuses
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
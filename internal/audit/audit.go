@@ -93,7 +93,27 @@ func Append(e *Entry) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return appendEntry(p, e)
+}
+
+// AppendFile appends an event to the interactions log at path. It is used by
+// long-running processes such as the daemon, whose working directory may not
+// be inside the workspace.
+func AppendFile(path string, e *Entry) (string, error) {
+	if e == nil {
+		return "", fmt.Errorf("nil entry")
+	}
+	if e.Kind == "" {
+		return "", fmt.Errorf("kind is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return "", fmt.Errorf("failed to create .beads directory: %w", err)
+	}
+	return appendEntry(path, e)
+}
 
+func appendEntry(p string, e *Entry) (string, error) {
+	var err error
 	if e.ID == "" {
 		e.ID, err = newID()
 		if err != nil {
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -52,3 +53,23 @@ func TestAppend_CreatesFileAndWritesJSONL(t *testing.T) {
 		t.Fatalf("expected 2 lines, got %d", lines)
 	}
 }
+
+func TestAppendFile_WritesToGivenPath(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ".beads", FileName)
+
+	id, err := AppendFile(p, &Entry{Kind: "policy_denied", Actor: "bot", Reason: "no"})
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(data), id) || !strings.Contains(string(data), `"kind":"policy_denied"`) {
+		t.Fatalf("unexpected log contents: %s", data)
+	}
+
+	if _, err := AppendFile(p, &Entry{}); err == nil {
+		t.Fatal("expected error for missing kind")
+	}
+}
@@ -218,6 +218,7 @@ func findLocalBeadsDir() string {
 // 1. Check metadata.json first (single source of truth)
 //   - For SQLite backend: returns path to .db file
 //   - For Dolt backend: returns path to dolt/ directory
+//   - For Postgres backend: returns the nominal .beads/postgres path (no file on disk)
 //
 // 2. Fall back to canonical beads.db
 // 3. Search for *.db files, filtering out backups and vc.db
@@ -231,7 +232,10 @@ func findDatabaseInBeadsDir(beadsDir string, warnOnIssues bool) string {
 	// Check for metadata.json first (single source of truth)
 	if cfg, err := configfile.Load(beadsDir); err == nil && cfg != nil {
 		backend := cfg.GetBackend()
-		if backend == configfile.BackendDolt {
+		if backend == configfile.BackendPostgres {
+			// Data lives on the server; nothing to stat
+			return cfg.DatabasePath(beadsDir)
+		} else if backend == configfile.BackendDolt {
 			// For Dolt, check if the configured database directory exists
 			doltPath := cfg.DatabasePath(beadsDir)
 			if info, err := os.Stat(doltPath); err == nil && info.IsDir() {
@@ -381,6 +385,7 @@ const (
 	EventLabelAdded        = types.EventLabelAdded
 	EventLabelRemoved      = types.EventLabelRemoved
 	EventCompacted         = types.EventCompacted
+	EventDispatched        = types.EventDispatched
 )
 
 // Storage provides the minimal interface for extension orchestration
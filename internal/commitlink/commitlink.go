@@ -0,0 +1,83 @@
+// Package commitlink finds issue references in git commit messages.
+//
+// A commit links to every issue ID it mentions. Mentions that follow a
+// closing keyword ("Closes bd-12", "fixes bd-3, bd-4") are recorded as
+// closes, those after a reference keyword ("Refs bd-12", "part of bd-9")
+// as refs, and anything else as a plain mention.
+package commitlink
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Mention is one issue referenced by a commit message.
+type Mention struct {
+	IssueID string
+	Keyword types.CommitKeyword
+}
+
+var (
+	closesKeyword = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s*$`)
+	refsKeyword   = regexp.MustCompile(`(?i)\b(?:refs?|references|see|part\s+of)\s*:?\s*$`)
+	// listSeparator is what may sit between two IDs that share a keyword.
+	listSeparator = regexp.MustCompile(`(?i)^[\s,&/]*(?:and\s+)?[\s,&/]*$`)
+)
+
+// idPattern matches IDs with the given prefix, including hierarchical
+// child IDs like bd-abc.1.2.
+func idPattern(prefix string) *regexp.Regexp {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(prefix) + `-[a-z0-9]+(?:\.[0-9]+)*\b`)
+}
+
+// Parse returns the issues a commit message mentions, in order of first
+// appearance. An issue mentioned several times keeps its strongest keyword
+// (closes over refs over mentions).
+func Parse(message, prefix string) []Mention {
+	if prefix == "" {
+		return nil
+	}
+	ids := idPattern(prefix)
+	var mentions []Mention
+	index := make(map[string]int)
+	for _, line := range strings.Split(message, "\n") {
+		keyword := types.CommitMentions
+		prev := 0
+		for _, loc := range ids.FindAllStringIndex(line, -1) {
+			before := line[prev:loc[0]]
+			switch {
+			case closesKeyword.MatchString(before):
+				keyword = types.CommitCloses
+			case refsKeyword.MatchString(before):
+				keyword = types.CommitRefs
+			case prev == 0 || !listSeparator.MatchString(before):
+				keyword = types.CommitMentions
+			}
+			prev = loc[1]
+
+			id := line[loc[0]:loc[1]]
+			if i, ok := index[id]; ok {
+				if rank(keyword) > rank(mentions[i].Keyword) {
+					mentions[i].Keyword = keyword
+				}
+				continue
+			}
+			index[id] = len(mentions)
+			mentions = append(mentions, Mention{IssueID: id, Keyword: keyword})
+		}
+	}
+	return mentions
+}
+
+func rank(k types.CommitKeyword) int {
+	switch k {
+	case types.CommitCloses:
+		return 2
+	case types.CommitRefs:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,96 @@
+package commitlink
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []Mention
+	}{
+		{"bare mention", "Tidy up (bd-a1b)", []Mention{{"bd-a1b", types.CommitMentions}}},
+		{"closes", "Fix crash\n\nCloses bd-12", []Mention{{"bd-12", types.CommitCloses}}},
+		{"fixes with colon", "Fixes: bd-12", []Mention{{"bd-12", types.CommitCloses}}},
+		{"refs", "Refactor parser, refs bd-7", []Mention{{"bd-7", types.CommitRefs}}},
+		{"part of", "Part of bd-7.2", []Mention{{"bd-7.2", types.CommitRefs}}},
+		{"keyword applies to a list", "resolves bd-1, bd-2 and bd-3", []Mention{
+			{"bd-1", types.CommitCloses}, {"bd-2", types.CommitCloses}, {"bd-3", types.CommitCloses},
+		}},
+		{"list ends at other text", "Closes bd-1; touches bd-2", []Mention{
+			{"bd-1", types.CommitCloses}, {"bd-2", types.CommitMentions},
+		}},
+		{"keyword not adjacent", "fixed the bug in bd-4", []Mention{{"bd-4", types.CommitMentions}}},
+		{"strongest keyword wins", "Work on bd-5\n\nCloses bd-5\nSee bd-5", []Mention{{"bd-5", types.CommitCloses}}},
+		{"other prefixes ignored", "Closes xbd-1 and gt-2", nil},
+		{"keywords do not span lines", "Closes\nbd-6", []Mention{{"bd-6", types.CommitMentions}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.message, "bd"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLog(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(), "GIT_COMMITTER_DATE=2026-03-01T12:00:00Z", "GIT_AUTHOR_DATE=2026-03-01T12:00:00Z")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.name", "Alice")
+	git("config", "user.email", "alice@example.com")
+	git("commit", "-q", "--allow-empty", "-m", "Initial commit")
+	git("commit", "-q", "--allow-empty", "-m", "Add parser\n\nCloses bd-1, refs bd-2")
+
+	commits, err := Log(t.Context(), dir, "--max-count=1", "HEAD")
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("Log returned %d commits, want 1", len(commits))
+	}
+	c := commits[0]
+	if c.Author != "Alice" || c.Subject() != "Add parser" || len(c.SHA) != 40 {
+		t.Errorf("commit = %+v", c)
+	}
+	if !c.CommittedAt.Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("committed at %v", c.CommittedAt)
+	}
+
+	links := c.Links("bd")
+	if len(links) != 2 || links[0].Keyword != types.CommitCloses || links[1].Keyword != types.CommitRefs {
+		t.Errorf("links = %+v", links)
+	}
+	if !IsAncestor(t.Context(), dir, c.SHA, "HEAD") {
+		t.Error("HEAD should be an ancestor of itself")
+	}
+}
+
+func TestParseLogFiles(t *testing.T) {
+	out := "\x1eabc\x1fBob\x1f2026-03-01T12:00:00Z\x1fFix it\n\nCloses bd-1\n\x1f\n\na.go\ndir/b.go\n"
+	commits, err := parseLog(out)
+	if err != nil {
+		t.Fatalf("parseLog failed: %v", err)
+	}
+	if len(commits) != 1 || !reflect.DeepEqual(commits[0].Files, []string{"a.go", "dir/b.go"}) {
+		t.Errorf("commits = %+v", commits)
+	}
+}
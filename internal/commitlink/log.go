@@ -0,0 +1,102 @@
+package commitlink
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Commit is a git commit as read by Log.
+type Commit struct {
+	SHA         string
+	Author      string
+	CommittedAt time.Time
+	Message     string
+	Files       []string
+}
+
+// Subject returns the first line of the commit message.
+func (c *Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return strings.TrimSpace(subject)
+}
+
+// Links returns one link per issue the commit mentions.
+func (c *Commit) Links(prefix string) []*types.CommitLink {
+	var links []*types.CommitLink
+	for _, m := range Parse(c.Message, prefix) {
+		links = append(links, &types.CommitLink{
+			IssueID:     m.IssueID,
+			CommitSHA:   c.SHA,
+			Author:      c.Author,
+			Subject:     c.Subject(),
+			Files:       c.Files,
+			Keyword:     m.Keyword,
+			CommittedAt: c.CommittedAt,
+		})
+	}
+	return links
+}
+
+// Record and field separators; neither can appear in commit messages.
+const (
+	recordSep = "\x1e"
+	fieldSep  = "\x1f"
+)
+
+// Log reads commits from the repository in dir (the current directory if
+// empty). args are passed to git log, e.g. a revision range or --max-count.
+func Log(ctx context.Context, dir string, args ...string) ([]*Commit, error) {
+	gitArgs := append([]string{"log", "--format=" + recordSep + "%H" + fieldSep + "%an" + fieldSep + "%cI" + fieldSep + "%B" + fieldSep, "--name-only"}, args...)
+	cmd := exec.CommandContext(ctx, "git", gitArgs...) // #nosec G204 -- args are revisions chosen by the user
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git log: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	return parseLog(string(out))
+}
+
+func parseLog(out string) ([]*Commit, error) {
+	var commits []*Commit
+	for _, record := range strings.Split(out, recordSep) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		fields := strings.SplitN(record, fieldSep, 5)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected git log record: %q", record)
+		}
+		committedAt, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("commit %s: invalid date %q: %w", fields[0], fields[2], err)
+		}
+		commit := &Commit{
+			SHA:         fields[0],
+			Author:      fields[1],
+			CommittedAt: committedAt,
+			Message:     strings.TrimSpace(fields[3]),
+		}
+		for _, file := range strings.Split(fields[4], "\n") {
+			if file = strings.TrimSpace(file); file != "" {
+				commit.Files = append(commit.Files, file)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// IsAncestor reports whether commit is reachable from ref.
+func IsAncestor(ctx context.Context, dir, commit, ref string) bool {
+	cmd := exec.CommandContext(ctx, "git", "merge-base", "--is-ancestor", commit, ref) // #nosec G204 -- commit and ref come from git itself
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
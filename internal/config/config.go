@@ -77,6 +77,25 @@ func Initialize() error {
 		}
 	}
 
+	setDefaults(v)
+
+	// Read config file if it was found
+	if configFileSet {
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+		debug.Logf("Debug: loaded config from %s\n", v.ConfigFileUsed())
+	} else {
+		// No config.yaml found - use defaults and environment variables
+		debug.Logf("Debug: no config.yaml found; using defaults and environment variables\n")
+	}
+
+	return nil
+}
+
+// setDefaults binds environment variables and sets the default for every
+// setting. It is shared by Initialize and LoadWorkspace.
+func setDefaults(v *viper.Viper) {
 	// Automatic environment variable binding
 	// Environment variables take precedence over config file
 	// E.g., BD_JSON, BD_NO_DAEMON, BD_ACTOR, BD_DB
@@ -126,19 +145,52 @@ func Initialize() error {
 	v.SetDefault("sync.export_on", SyncTriggerPush)     // push | change
 	v.SetDefault("sync.import_on", SyncTriggerPull)     // pull | change
 
+	// Sync transport: how bd sync moves the JSONL between clones
+	v.SetDefault("sync.transport", SyncTransportGit) // git | s3
+	v.SetDefault("sync.s3.bucket", "")
+	v.SetDefault("sync.s3.prefix", "")
+	v.SetDefault("sync.s3.region", "")
+	v.SetDefault("sync.s3.endpoint", "") // S3-compatible stores, e.g. http://localhost:9000 for MinIO
+	v.SetDefault("sync.s3.path-style", false)
+
 	// Conflict resolution configuration
 	v.SetDefault("conflict.strategy", ConflictStrategyNewest) // newest | ours | theirs | manual
 
+	// Per-field merge strategies for the JSONL merge driver
+	v.SetDefault("merge.fields", map[string]string{})  // field -> lww | union | concat | max | min | left | right | conflict
+	v.SetDefault("merge.status-order", []string{})     // workflow rank for max/min on status (empty = built-in order)
+
 	// Federation configuration (optional Dolt remote)
 	v.SetDefault("federation.remote", "")       // e.g., dolthub://org/beads, gs://bucket/beads, s3://bucket/beads
 	v.SetDefault("federation.sovereignty", "")  // T1 | T2 | T3 | T4 (empty = no restriction)
 
+	// Gate escalation targets: gt | exec:<command> | webhook:<url> | none
+	v.SetDefault("gate.escalate", []string{"gt"})
+	// Gate waiter notification targets: gt | exec:<command> | webhook:<url> | none
+	v.SetDefault("gate.notify", []string{"gt"})
+	// Command lines cmd gates may run; empty disables cmd gates
+	v.SetDefault("gate.cmd.allow", []string{})
+	// URL prefixes the daemon may fetch for http gates; empty keeps http gates out of the daemon
+	v.SetDefault("gate.http.allow", []string{})
+	// Evaluate open gates in the daemon (see cmd/bd/daemon_gates.go); opt-in
+	v.SetDefault("daemon.gate-check", false)
+
+	// SLA policies (see GetSLAConfig); evaluated by the daemon or bd sla check
+	v.SetDefault("sla.policies", []interface{}{})
+	v.SetDefault("sla.at-risk", 0.75) // Fraction of a target elapsed before an issue counts as at risk
+	v.SetDefault("daemon.sla-check", true)
+
 	// Push configuration defaults
 	v.SetDefault("no-push", false)
 
 	// Create command defaults
 	v.SetDefault("create.require-description", false)
 
+	// Commit linking defaults (see cmd/bd/commits.go)
+	v.SetDefault("close.require-commit", false) // Refuse to close issues no commit mentions
+	v.SetDefault("commits.auto-close", true)    // Close issues when a "Closes <id>" commit reaches main
+	v.SetDefault("commits.main-branch", "")     // Branch that triggers auto-close (empty = remote default)
+
 	// Validation configuration defaults (bd-t7jq)
 	// Values: "warn" | "error" | "none"
 	// - "none": no validation (default, backwards compatible)
@@ -163,19 +215,6 @@ func Initialize() error {
 	// External projects for cross-project dependency resolution (bd-h807)
 	// Maps project names to paths for resolving external: blocked_by references
 	v.SetDefault("external_projects", map[string]string{})
-
-	// Read config file if it was found
-	if configFileSet {
-		if err := v.ReadInConfig(); err != nil {
-			return fmt.Errorf("error reading config file: %w", err)
-		}
-		debug.Logf("Debug: loaded config from %s\n", v.ConfigFileUsed())
-	} else {
-		// No config.yaml found - use defaults and environment variables
-		debug.Logf("Debug: no config.yaml found; using defaults and environment variables\n")
-	}
-
-	return nil
 }
 
 // ResetForTesting clears the config state, allowing Initialize() to be called again.
@@ -584,6 +623,29 @@ func GetConflictConfig() ConflictConfig {
 	}
 }
 
+// MergeConfig holds the per-field strategies for the JSONL merge driver.
+// Values are validated by merge.ParsePolicy.
+type MergeConfig struct {
+	Fields      map[string]string // issue field (JSON name) -> strategy
+	StatusOrder []string          // workflow rank for max/min on status
+}
+
+// GetMergeConfig returns the merge configuration.
+// Example config.yaml:
+//
+//	merge:
+//	  fields:
+//	    assignee: left
+//	    description: conflict
+//	    status: max
+//	  status-order: [open, in_progress, review, closed]
+func GetMergeConfig() MergeConfig {
+	return MergeConfig{
+		Fields:      GetStringMapString("merge.fields"),
+		StatusOrder: GetStringSlice("merge.status-order"),
+	}
+}
+
 // FederationConfig holds the federation (Dolt remote) configuration.
 type FederationConfig struct {
 	Remote      string      // dolthub://org/beads, gs://bucket/beads, s3://bucket/beads
@@ -598,6 +660,68 @@ func GetFederationConfig() FederationConfig {
 	}
 }
 
+// SyncS3Config holds the bucket settings for the s3 sync transport.
+type SyncS3Config struct {
+	Bucket    string
+	Prefix    string
+	Region    string
+	Endpoint  string
+	PathStyle bool
+}
+
+// GetSyncS3Config returns the s3 sync transport configuration.
+// Example config.yaml:
+//
+//	sync:
+//	  transport: s3
+//	  s3:
+//	    bucket: team-beads
+//	    prefix: myrepo
+//	    endpoint: http://localhost:9000
+//	    path-style: true
+func GetSyncS3Config() SyncS3Config {
+	return SyncS3Config{
+		Bucket:    GetString("sync.s3.bucket"),
+		Prefix:    GetString("sync.s3.prefix"),
+		Region:    GetString("sync.s3.region"),
+		Endpoint:  GetString("sync.s3.endpoint"),
+		PathStyle: GetBool("sync.s3.path-style"),
+	}
+}
+
+// SLAPolicyConfig is one entry of sla.policies. Values are validated by
+// sla.ParsePolicies.
+type SLAPolicyConfig struct {
+	Type     string `mapstructure:"type"`     // Issue type; empty matches any
+	Priority string `mapstructure:"priority"` // 0-4 or P0-P4; empty matches any
+	Respond  string `mapstructure:"respond"`  // Time to acknowledge, e.g. 1h
+	Resolve  string `mapstructure:"resolve"`  // Time to close, e.g. 24h or 3d
+}
+
+// SLAConfig holds the SLA policies.
+type SLAConfig struct {
+	Policies []SLAPolicyConfig
+	AtRisk   float64 // Fraction of a target elapsed that counts as at risk
+}
+
+// GetSLAConfig returns the SLA configuration. An unreadable sla.policies is
+// returned as an error rather than ignored, so a typo can't silently disable
+// enforcement.
+// Example config.yaml:
+//
+//	sla:
+//	  at-risk: 0.75
+//	  policies:
+//	    - type: bug
+//	      priority: 0
+//	      respond: 1h
+//	      resolve: 24h
+//	    - priority: 1
+//	      resolve: 3d
+func GetSLAConfig() (SLAConfig, error) {
+	return Current().GetSLAConfig()
+}
+
 // IsSyncModeValid checks if the given sync mode string is valid.
 func IsSyncModeValid(mode string) bool {
 	return validSyncModes[SyncMode(mode)]
@@ -72,6 +72,9 @@ func TestDefaults(t *testing.T) {
 		{"actor", "", func(k string) interface{} { return GetString(k) }},
 		{"flush-debounce", 30 * time.Second, func(k string) interface{} { return GetDuration(k) }},
 		{"auto-start-daemon", true, func(k string) interface{} { return GetBool(k) }},
+		{"daemon.gate-check", false, func(k string) interface{} { return GetBool(k) }},
+		{"gate.cmd.allow", 0, func(k string) interface{} { return len(GetStringSlice(k)) }},
+		{"gate.http.allow", 0, func(k string) interface{} { return len(GetStringSlice(k)) }},
 	}
 	
 	for _, tt := range tests {
@@ -62,6 +62,35 @@ func IsValidSyncMode(mode string) bool {
 	return validSyncModes[SyncMode(strings.ToLower(strings.TrimSpace(mode)))]
 }
 
+// SyncTransport selects how bd sync moves the exported JSONL between clones
+type SyncTransport string
+
+const (
+	// SyncTransportGit commits the JSONL to git (default)
+	SyncTransportGit SyncTransport = "git"
+	// SyncTransportS3 stores generation-numbered JSONL snapshots in an S3-compatible bucket
+	SyncTransportS3 SyncTransport = "s3"
+)
+
+// validSyncTransports is the set of allowed sync transport values
+var validSyncTransports = map[SyncTransport]bool{
+	SyncTransportGit: true,
+	SyncTransportS3:  true,
+}
+
+// ValidSyncTransports returns the list of valid sync transport values.
+func ValidSyncTransports() []string {
+	return []string{
+		string(SyncTransportGit),
+		string(SyncTransportS3),
+	}
+}
+
+// IsValidSyncTransport returns true if the given string is a valid sync transport.
+func IsValidSyncTransport(transport string) bool {
+	return validSyncTransports[SyncTransport(strings.ToLower(strings.TrimSpace(transport)))]
+}
+
 // ConflictStrategy represents the conflict resolution strategy
 type ConflictStrategy string
 
@@ -164,6 +193,28 @@ func GetSyncMode() SyncMode {
 	return mode
 }
 
+// GetSyncTransport retrieves the sync transport configuration.
+// Returns the configured transport, or SyncTransportGit (default) if not set or invalid.
+// Logs a warning if an invalid value is configured (unless ConfigWarnings is false).
+//
+// Config key: sync.transport
+// Valid values: git, s3
+func GetSyncTransport() SyncTransport {
+	value := GetString("sync.transport")
+	if value == "" {
+		return SyncTransportGit // Default
+	}
+
+	transport := SyncTransport(strings.ToLower(strings.TrimSpace(value)))
+	if !validSyncTransports[transport] {
+		logConfigWarning("Warning: invalid sync.transport %q in config (valid: %s), using default 'git'\n",
+			value, strings.Join(ValidSyncTransports(), ", "))
+		return SyncTransportGit
+	}
+
+	return transport
+}
+
 // GetConflictStrategy retrieves the conflict resolution strategy configuration.
 // Returns the configured strategy, or ConflictStrategyNewest (default) if not set or invalid.
 // Logs a warning if an invalid value is configured (unless ConfigWarnings is false).
@@ -214,6 +265,11 @@ func (m SyncMode) String() string {
 	return string(m)
 }
 
+// String returns the string representation of the SyncTransport.
+func (t SyncTransport) String() string {
+	return string(t)
+}
+
 // String returns the string representation of the ConflictStrategy.
 func (s ConflictStrategy) String() string {
 	return string(s)
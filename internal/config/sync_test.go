@@ -478,3 +478,113 @@ func TestSovereigntyString(t *testing.T) {
 		t.Errorf("SovereigntyNone.String() = %q, want %q", got, "")
 	}
 }
+
+func TestGetMergeConfig(t *testing.T) {
+	ResetForTesting()
+	if err := Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	if cfg := GetMergeConfig(); len(cfg.Fields) != 0 || len(cfg.StatusOrder) != 0 {
+		t.Errorf("expected empty merge config by default, got %+v", cfg)
+	}
+
+	Set("merge.fields", map[string]string{"assignee": "left", "description": "conflict"})
+	Set("merge.status-order", []string{"open", "review", "closed"})
+
+	cfg := GetMergeConfig()
+	if cfg.Fields["assignee"] != "left" || cfg.Fields["description"] != "conflict" {
+		t.Errorf("unexpected merge.fields: %v", cfg.Fields)
+	}
+	if strings.Join(cfg.StatusOrder, ",") != "open,review,closed" {
+		t.Errorf("unexpected merge.status-order: %v", cfg.StatusOrder)
+	}
+}
+
+func TestGetSLAConfig(t *testing.T) {
+	ResetForTesting()
+	if err := Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	cfg, err := GetSLAConfig()
+	if err != nil || len(cfg.Policies) != 0 || cfg.AtRisk != 0.75 {
+		t.Errorf("expected no policies and at-risk 0.75 by default, got %+v (%v)", cfg, err)
+	}
+
+	// As parsed from YAML: priorities may be numbers
+	Set("sla.policies", []interface{}{
+		map[string]interface{}{"type": "bug", "priority": 0, "respond": "1h", "resolve": "24h"},
+		map[string]interface{}{"priority": "P1", "resolve": "3d"},
+	})
+	Set("sla.at-risk", 0.5)
+	cfg, err = GetSLAConfig()
+	if err != nil {
+		t.Fatalf("GetSLAConfig failed: %v", err)
+	}
+	want := []SLAPolicyConfig{
+		{Type: "bug", Priority: "0", Respond: "1h", Resolve: "24h"},
+		{Priority: "P1", Resolve: "3d"},
+	}
+	if len(cfg.Policies) != len(want) || cfg.Policies[0] != want[0] || cfg.Policies[1] != want[1] || cfg.AtRisk != 0.5 {
+		t.Errorf("GetSLAConfig = %+v, want %+v with at-risk 0.5", cfg, want)
+	}
+
+	Set("sla.policies", "bug: 1h")
+	if _, err := GetSLAConfig(); err == nil {
+		t.Error("expected an error for malformed sla.policies")
+	}
+}
+
+func TestGetSyncTransport(t *testing.T) {
+	tests := []struct {
+		configValue    string
+		expected       SyncTransport
+		expectsWarning bool
+	}{
+		{"", SyncTransportGit, false},
+		{"git", SyncTransportGit, false},
+		{"s3", SyncTransportS3, false},
+		{" S3 ", SyncTransportS3, false},
+		{"ftp", SyncTransportGit, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.configValue, func(t *testing.T) {
+			ResetForTesting()
+			if err := Initialize(); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+			if tt.configValue != "" {
+				Set("sync.transport", tt.configValue)
+			}
+
+			var buf bytes.Buffer
+			oldWriter := ConfigWarningWriter
+			ConfigWarningWriter = &buf
+			defer func() { ConfigWarningWriter = oldWriter }()
+
+			if got := GetSyncTransport(); got != tt.expected {
+				t.Errorf("GetSyncTransport() = %q, want %q", got, tt.expected)
+			}
+			if hasWarning := strings.Contains(buf.String(), "Warning:"); hasWarning != tt.expectsWarning {
+				t.Errorf("warning = %v, want %v (output %q)", hasWarning, tt.expectsWarning, buf.String())
+			}
+		})
+	}
+}
+
+func TestGetSyncS3Config(t *testing.T) {
+	ResetForTesting()
+	if err := Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	Set("sync.s3.bucket", "team-beads")
+	Set("sync.s3.endpoint", "http://localhost:9000")
+	Set("sync.s3.path-style", true)
+
+	cfg := GetSyncS3Config()
+	if cfg.Bucket != "team-beads" || cfg.Endpoint != "http://localhost:9000" || !cfg.PathStyle || cfg.Prefix != "" {
+		t.Errorf("unexpected s3 config: %+v", cfg)
+	}
+}
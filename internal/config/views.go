@@ -0,0 +1,233 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// ViewFilterConfig is the filter of a saved view. Keys are named after the
+// bd list and bd ready flags they were saved from. Dates are stored as typed,
+// so relative expressions like "-7d" are resolved each time the view runs.
+type ViewFilterConfig struct {
+	Status           string   `yaml:"status,omitempty" mapstructure:"status" json:"status,omitempty"`
+	All              bool     `yaml:"all,omitempty" mapstructure:"all" json:"all,omitempty"`
+	Ready            bool     `yaml:"ready,omitempty" mapstructure:"ready" json:"ready,omitempty"`
+	Priority         string   `yaml:"priority,omitempty" mapstructure:"priority" json:"priority,omitempty"`
+	PriorityMin      string   `yaml:"priority-min,omitempty" mapstructure:"priority-min" json:"priority_min,omitempty"`
+	PriorityMax      string   `yaml:"priority-max,omitempty" mapstructure:"priority-max" json:"priority_max,omitempty"`
+	Assignee         string   `yaml:"assignee,omitempty" mapstructure:"assignee" json:"assignee,omitempty"`
+	NoAssignee       bool     `yaml:"no-assignee,omitempty" mapstructure:"no-assignee" json:"no_assignee,omitempty"`
+	Unassigned       bool     `yaml:"unassigned,omitempty" mapstructure:"unassigned" json:"unassigned,omitempty"`
+	Type             string   `yaml:"type,omitempty" mapstructure:"type" json:"type,omitempty"`
+	Labels           []string `yaml:"label,omitempty,flow" mapstructure:"label" json:"label,omitempty"`
+	LabelsAny        []string `yaml:"label-any,omitempty,flow" mapstructure:"label-any" json:"label_any,omitempty"`
+	NoLabels         bool     `yaml:"no-labels,omitempty" mapstructure:"no-labels" json:"no_labels,omitempty"`
+	Title            string   `yaml:"title,omitempty" mapstructure:"title" json:"title,omitempty"`
+	IDs              []string `yaml:"id,omitempty,flow" mapstructure:"id" json:"id,omitempty"`
+	TitleContains    string   `yaml:"title-contains,omitempty" mapstructure:"title-contains" json:"title_contains,omitempty"`
+	DescContains     string   `yaml:"desc-contains,omitempty" mapstructure:"desc-contains" json:"desc_contains,omitempty"`
+	NotesContains    string   `yaml:"notes-contains,omitempty" mapstructure:"notes-contains" json:"notes_contains,omitempty"`
+	EmptyDescription bool     `yaml:"empty-description,omitempty" mapstructure:"empty-description" json:"empty_description,omitempty"`
+	CreatedAfter     string   `yaml:"created-after,omitempty" mapstructure:"created-after" json:"created_after,omitempty"`
+	CreatedBefore    string   `yaml:"created-before,omitempty" mapstructure:"created-before" json:"created_before,omitempty"`
+	UpdatedAfter     string   `yaml:"updated-after,omitempty" mapstructure:"updated-after" json:"updated_after,omitempty"`
+	UpdatedBefore    string   `yaml:"updated-before,omitempty" mapstructure:"updated-before" json:"updated_before,omitempty"`
+	ClosedAfter      string   `yaml:"closed-after,omitempty" mapstructure:"closed-after" json:"closed_after,omitempty"`
+	ClosedBefore     string   `yaml:"closed-before,omitempty" mapstructure:"closed-before" json:"closed_before,omitempty"`
+	Pinned           bool     `yaml:"pinned,omitempty" mapstructure:"pinned" json:"pinned,omitempty"`
+	NoPinned         bool     `yaml:"no-pinned,omitempty" mapstructure:"no-pinned" json:"no_pinned,omitempty"`
+	IncludeTemplates bool     `yaml:"include-templates,omitempty" mapstructure:"include-templates" json:"include_templates,omitempty"`
+	IncludeGates     bool     `yaml:"include-gates,omitempty" mapstructure:"include-gates" json:"include_gates,omitempty"`
+	Parent           string   `yaml:"parent,omitempty" mapstructure:"parent" json:"parent,omitempty"`
+	MolType          string   `yaml:"mol-type,omitempty" mapstructure:"mol-type" json:"mol_type,omitempty"`
+	Deferred         bool     `yaml:"deferred,omitempty" mapstructure:"deferred" json:"deferred,omitempty"`
+	IncludeDeferred  bool     `yaml:"include-deferred,omitempty" mapstructure:"include-deferred" json:"include_deferred,omitempty"`
+	DeferAfter       string   `yaml:"defer-after,omitempty" mapstructure:"defer-after" json:"defer_after,omitempty"`
+	DeferBefore      string   `yaml:"defer-before,omitempty" mapstructure:"defer-before" json:"defer_before,omitempty"`
+	DueAfter         string   `yaml:"due-after,omitempty" mapstructure:"due-after" json:"due_after,omitempty"`
+	DueBefore        string   `yaml:"due-before,omitempty" mapstructure:"due-before" json:"due_before,omitempty"`
+	Overdue          bool     `yaml:"overdue,omitempty" mapstructure:"overdue" json:"overdue,omitempty"`
+}
+
+// ViewConfig is one entry of the views section of config.yaml: a saved
+// bd list or bd ready query. Values are validated by the views package.
+type ViewConfig struct {
+	Description string           `yaml:"description,omitempty" mapstructure:"description" json:"description,omitempty"`
+	Command     string           `yaml:"command,omitempty" mapstructure:"command" json:"command,omitempty"` // "list" (default) or "ready"
+	Filter      ViewFilterConfig `yaml:"filter,omitempty" mapstructure:"filter" json:"filter"`
+	Sort        string           `yaml:"sort,omitempty" mapstructure:"sort" json:"sort,omitempty"`
+	Reverse     bool             `yaml:"reverse,omitempty" mapstructure:"reverse" json:"reverse,omitempty"`
+	Limit       *int             `yaml:"limit,omitempty" mapstructure:"limit" json:"limit,omitempty"` // nil uses the command's default
+	Columns     []string         `yaml:"columns,omitempty,flow" mapstructure:"columns" json:"columns,omitempty"`
+}
+
+// GetViews returns the saved views by name.
+// Example config.yaml:
+//
+//	views:
+//	  backend-bugs:
+//	    description: Recent backend bugs
+//	    filter:
+//	      type: bug
+//	      label: [backend]
+//	      created-after: -14d
+//	    sort: priority
+//	    columns: [id, priority, assignee, title]
+func GetViews() (map[string]ViewConfig, error) {
+	if v == nil {
+		return map[string]ViewConfig{}, nil
+	}
+	return unmarshalViews(v)
+}
+
+// ReadViews reads the saved views straight from configPath, for long-running
+// processes (like the daemon) that must see views saved after they started.
+func ReadViews(configPath string) (map[string]ViewConfig, error) {
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return map[string]ViewConfig{}, nil
+	}
+	fv := viper.New()
+	fv.SetConfigFile(configPath)
+	fv.SetConfigType("yaml")
+	if err := fv.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	return unmarshalViews(fv)
+}
+
+func unmarshalViews(fv *viper.Viper) (map[string]ViewConfig, error) {
+	views := make(map[string]ViewConfig)
+	if err := fv.UnmarshalKey("views", &views); err != nil {
+		return nil, fmt.Errorf("invalid views: %w", err)
+	}
+	return views, nil
+}
+
+// SetViewInYAML saves view under views.<name> in config.yaml, replacing any
+// view of that name. A nil view removes it. Other sections and comments are
+// preserved where possible.
+func SetViewInYAML(configPath, name string, view *ViewConfig) error {
+	data, err := os.ReadFile(configPath) // #nosec G304 - config file path from caller
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config.yaml: %w", err)
+	}
+
+	var root yaml.Node
+	if len(data) > 0 {
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return fmt.Errorf("failed to parse config.yaml: %w", err)
+		}
+	}
+
+	var content string
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		// No settings yet, only comments (like the file bd init writes).
+		// yaml.v3 drops those, so append the views section as text.
+		if view == nil {
+			return nil
+		}
+		section, err := encodeYAML(map[string]map[string]*ViewConfig{"views": {name: view}})
+		if err != nil {
+			return err
+		}
+		content = strings.TrimRight(string(data), "\n")
+		if content != "" {
+			content += "\n\n"
+		}
+		content += section
+	} else {
+		mapping := root.Content[0]
+		if mapping.Kind != yaml.MappingNode {
+			return fmt.Errorf("config.yaml is not a mapping of settings")
+		}
+
+		views := mappingValue(mapping, "views")
+		if views == nil || views.Kind != yaml.MappingNode {
+			if view == nil {
+				return nil
+			}
+			views = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(mapping, "views", views)
+		}
+		if view == nil {
+			removeMappingValue(views, name)
+			if len(views.Content) == 0 {
+				removeMappingValue(mapping, "views")
+			}
+		} else {
+			var node yaml.Node
+			if err := node.Encode(view); err != nil {
+				return fmt.Errorf("failed to encode view %q: %w", name, err)
+			}
+			setMappingValue(views, name, &node)
+		}
+
+		if content, err = encodeYAML(&root); err != nil {
+			return err
+		}
+		if len(mapping.Content) == 0 {
+			// Last setting removed: leave the comments, not an empty "{}"
+			content = strings.TrimSuffix(content, "{}\n")
+		}
+	}
+
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write config.yaml: %w", err)
+	}
+
+	// Reload viper config so the view can be used right away
+	if v != nil {
+		_ = v.ReadInConfig()
+	}
+	return nil
+}
+
+// encodeYAML encodes value with the two-space indent config.yaml uses.
+func encodeYAML(value interface{}) (string, error) {
+	var buf strings.Builder
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to encode config.yaml: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to close encoder: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// mappingValue returns the value node for key in a yaml mapping, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue replaces the value for key in a yaml mapping, appending the
+// key if it isn't there.
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+// removeMappingValue removes key from a yaml mapping.
+func removeMappingValue(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
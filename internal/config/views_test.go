@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetViewInYAML(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	initial := "# Beads Configuration File\n# issue-prefix: \"\"\n"
+	if err := os.WriteFile(configPath, []byte(initial), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	limit := 0
+	bugs := &ViewConfig{
+		Description: "Recent bugs",
+		Filter:      ViewFilterConfig{Type: "bug", Labels: []string{"backend", "api"}, CreatedAfter: "-7d"},
+		Sort:        "priority",
+		Limit:       &limit,
+		Columns:     []string{"id", "title"},
+	}
+	if err := SetViewInYAML(configPath, "bugs", bugs); err != nil {
+		t.Fatalf("SetViewInYAML(bugs): %v", err)
+	}
+	next := &ViewConfig{Command: "ready", Filter: ViewFilterConfig{Unassigned: true}}
+	if err := SetViewInYAML(configPath, "next", next); err != nil {
+		t.Fatalf("SetViewInYAML(next): %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.Contains(content, "# Beads Configuration File") {
+		t.Errorf("comments were dropped:\n%s", content)
+	}
+	if !strings.Contains(content, "label: [backend, api]") || !strings.Contains(content, "created-after: -7d") {
+		t.Errorf("view not written with flag-named keys:\n%s", content)
+	}
+
+	views, err := ReadViews(configPath)
+	if err != nil {
+		t.Fatalf("ReadViews: %v", err)
+	}
+	if len(views) != 2 {
+		t.Fatalf("ReadViews returned %d views, want 2", len(views))
+	}
+	got := views["bugs"]
+	if got.Description != "Recent bugs" || got.Filter.Type != "bug" || got.Filter.CreatedAfter != "-7d" || got.Sort != "priority" {
+		t.Errorf("bugs = %+v", got)
+	}
+	if strings.Join(got.Filter.Labels, ",") != "backend,api" || strings.Join(got.Columns, ",") != "id,title" {
+		t.Errorf("bugs labels = %v columns = %v", got.Filter.Labels, got.Columns)
+	}
+	if got.Limit == nil || *got.Limit != 0 {
+		t.Errorf("bugs limit = %v, want explicit 0", got.Limit)
+	}
+	if n := views["next"]; n.Command != "ready" || !n.Filter.Unassigned || n.Limit != nil {
+		t.Errorf("next = %+v", n)
+	}
+
+	// Replace and remove
+	bugs.Sort = "updated"
+	if err := SetViewInYAML(configPath, "bugs", bugs); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetViewInYAML(configPath, "next", nil); err != nil {
+		t.Fatal(err)
+	}
+	views, err = ReadViews(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(views) != 1 || views["bugs"].Sort != "updated" {
+		t.Errorf("after replace and remove: %+v", views)
+	}
+
+	if err := SetViewInYAML(configPath, "bugs", nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(configPath)
+	if strings.Contains(string(data), "views:") || strings.Contains(string(data), "{}") {
+		t.Errorf("empty views section left behind:\n%s", data)
+	}
+	if !strings.Contains(string(data), "# Beads Configuration File") {
+		t.Errorf("comments were dropped:\n%s", data)
+	}
+}
+
+func TestSetViewInYAMLKeepsSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	initial := "# Team settings\nissue-prefix: demo\nsync-branch: beads-sync # shared\n"
+	if err := os.WriteFile(configPath, []byte(initial), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetViewInYAML(configPath, "mine", &ViewConfig{Filter: ViewFilterConfig{Assignee: "alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(configPath)
+	for _, want := range []string{"# Team settings", "issue-prefix: demo", "sync-branch: beads-sync # shared", "assignee: alice"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config.yaml missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestReadViewsMissingFile(t *testing.T) {
+	views, err := ReadViews(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil || len(views) != 0 {
+		t.Errorf("ReadViews(missing) = %v, %v; want empty", views, err)
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// Settings is a read-only view of one configuration. Current is the
+// process-wide configuration loaded by Initialize; LoadWorkspace reads a
+// single workspace's config.yaml without touching it.
+type Settings struct {
+	v *viper.Viper
+}
+
+// Current returns the process-wide configuration.
+func Current() *Settings {
+	return &Settings{v: v}
+}
+
+// LoadWorkspace reads beadsDir/config.yaml on top of the same defaults and
+// environment variables as Initialize. The multiplex daemon serves many
+// workspaces from one process, so its process-wide configuration belongs
+// to none of them; it reads per-workspace settings this way. A missing
+// config.yaml yields the defaults.
+func LoadWorkspace(beadsDir string) (*Settings, error) {
+	wv := viper.New()
+	wv.SetConfigType("yaml")
+	setDefaults(wv)
+
+	path := filepath.Join(beadsDir, "config.yaml")
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return &Settings{v: wv}, nil
+		}
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	wv.SetConfigFile(path)
+	if err := wv.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	return &Settings{v: wv}, nil
+}
+
+// GetBool retrieves a boolean configuration value
+func (s *Settings) GetBool(key string) bool {
+	if s.v == nil {
+		return false
+	}
+	return s.v.GetBool(key)
+}
+
+// GetStringSlice retrieves a string slice configuration value
+func (s *Settings) GetStringSlice(key string) []string {
+	if s.v == nil {
+		return []string{}
+	}
+	return s.v.GetStringSlice(key)
+}
+
+// GetSLAConfig returns the SLA configuration (see the package-level
+// GetSLAConfig).
+func (s *Settings) GetSLAConfig() (SLAConfig, error) {
+	cfg := SLAConfig{AtRisk: 0.75}
+	if s.v == nil {
+		return cfg, nil
+	}
+	cfg.AtRisk = s.v.GetFloat64("sla.at-risk")
+	if err := s.v.UnmarshalKey("sla.policies", &cfg.Policies); err != nil {
+		return cfg, fmt.Errorf("invalid sla.policies: %w", err)
+	}
+	return cfg, nil
+}
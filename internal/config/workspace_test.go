@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadWorkspace(t *testing.T) {
+	restore := envSnapshot(t)
+	defer restore()
+	if err := Initialize(); err != nil {
+		t.Fatalf("Initialize() returned error: %v", err)
+	}
+
+	beadsDir := t.TempDir()
+	yaml := `daemon:
+  gate-check: true
+gate:
+  cmd:
+    allow:
+      - make test
+sla:
+  policies:
+    - type: bug
+      resolve: 24h
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "config.yaml"), []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := LoadWorkspace(beadsDir)
+	if err != nil {
+		t.Fatalf("LoadWorkspace failed: %v", err)
+	}
+	if !ws.GetBool("daemon.gate-check") {
+		t.Error("daemon.gate-check not read from the workspace config")
+	}
+	if allow := ws.GetStringSlice("gate.cmd.allow"); len(allow) != 1 || allow[0] != "make test" {
+		t.Errorf("gate.cmd.allow = %q", allow)
+	}
+	sla, err := ws.GetSLAConfig()
+	if err != nil || len(sla.Policies) != 1 || sla.Policies[0].Type != "bug" || sla.AtRisk != 0.75 {
+		t.Errorf("GetSLAConfig = %+v, %v", sla, err)
+	}
+	// Defaults still apply to keys the file does not set
+	if !ws.GetBool("daemon.sla-check") {
+		t.Error("daemon.sla-check default missing")
+	}
+
+	// The process-wide configuration is untouched
+	if GetBool("daemon.gate-check") || len(GetStringSlice("gate.cmd.allow")) != 0 {
+		t.Error("LoadWorkspace leaked into the global configuration")
+	}
+
+	// No config.yaml: defaults only
+	empty, err := LoadWorkspace(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadWorkspace without config.yaml failed: %v", err)
+	}
+	if empty.GetBool("daemon.gate-check") {
+		t.Error("expected daemon.gate-check default false")
+	}
+
+	if err := os.WriteFile(filepath.Join(beadsDir, "config.yaml"), []byte("gate: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWorkspace(beadsDir); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+}
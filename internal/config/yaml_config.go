@@ -61,6 +61,11 @@ var YamlOnlyKeys = map[string]bool{
 	// Create command settings
 	"create.require-description": true,
 
+	// Commit linking settings
+	"close.require-commit": true,
+	"commits.auto-close":   true,
+	"commits.main-branch":  true,
+
 	// Validation settings (bd-t7jq)
 	// Values: "warn" | "error" | "none"
 	"validation.on-create": true,
@@ -68,6 +73,14 @@ var YamlOnlyKeys = map[string]bool{
 
 	// Hierarchy settings (GH#995)
 	"hierarchy.max-depth": true,
+
+	// Gate escalation and waiter notification targets (gt, exec:<command>, webhook:<url>, none)
+	"gate.escalate": true,
+	"gate.notify":   true,
+
+	// Commands cmd gates may run and URLs the daemon may poll for http gates
+	"gate.cmd.allow":  true,
+	"gate.http.allow": true,
 }
 
 // IsYamlOnlyKey returns true if the given key should be stored in config.yaml
@@ -3,8 +3,10 @@ package configfile
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -13,7 +15,11 @@ const ConfigFileName = "metadata.json"
 type Config struct {
 	Database    string `json:"database"`
 	JSONLExport string `json:"jsonl_export,omitempty"`
-	Backend     string `json:"backend,omitempty"` // "sqlite" (default) or "dolt"
+	Backend     string `json:"backend,omitempty"` // "sqlite" (default), "dolt" or "postgres"
+
+	// Postgres holds connection settings for the postgres backend.
+	// Passwords are never stored here; use PGPASSWORD, ~/.pgpass or BEADS_POSTGRES_URL.
+	Postgres *PostgresConfig `json:"postgres,omitempty"`
 
 	// Deletions configuration
 	DeletionsRetentionDays int `json:"deletions_retention_days,omitempty"` // 0 means use default (3 days)
@@ -103,6 +109,11 @@ func (c *Config) DatabasePath(beadsDir string) string {
 	//
 	// Backward-compat: early dolt configs wrote "beads.db" even when Backend=dolt.
 	// In that case, treat it as "dolt".
+	// Postgres keeps no data on disk; the path only identifies the store.
+	if backend == BackendPostgres {
+		return filepath.Join(beadsDir, "postgres")
+	}
+
 	if backend == BackendDolt {
 		db := strings.TrimSpace(c.Database)
 		if db == "" || db == "beads.db" {
@@ -145,8 +156,9 @@ func (c *Config) GetDeletionsRetentionDays() int {
 
 // Backend constants
 const (
-	BackendSQLite = "sqlite"
-	BackendDolt   = "dolt"
+	BackendSQLite   = "sqlite"
+	BackendDolt     = "dolt"
+	BackendPostgres = "postgres"
 )
 
 // BackendCapabilities describes behavioral constraints for a storage backend.
@@ -173,6 +185,9 @@ func CapabilitiesForBackend(backend string) BackendCapabilities {
 		return BackendCapabilities{SingleProcessOnly: false}
 	case BackendDolt:
 		return BackendCapabilities{SingleProcessOnly: true}
+	case BackendPostgres:
+		// The server handles concurrent writers from any number of processes
+		return BackendCapabilities{SingleProcessOnly: false}
 	default:
 		return BackendCapabilities{SingleProcessOnly: true}
 	}
@@ -185,3 +200,75 @@ func (c *Config) GetBackend() string {
 	}
 	return c.Backend
 }
+
+// PostgresURLEnv overrides the configured postgres connection when set.
+const PostgresURLEnv = "BEADS_POSTGRES_URL"
+
+// PostgresConfig describes how to reach the postgres server. Empty fields
+// fall back to libpq's PGHOST, PGPORT, PGDATABASE, PGUSER and PGSSLMODE.
+type PostgresConfig struct {
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Database string `json:"database,omitempty"`
+	User     string `json:"user,omitempty"`
+	SSLMode  string `json:"sslmode,omitempty"`
+}
+
+// PostgresDSN returns the connection string for the postgres backend.
+// BEADS_POSTGRES_URL takes precedence over metadata.json.
+func (c *Config) PostgresDSN() string {
+	if dsn := strings.TrimSpace(os.Getenv(PostgresURLEnv)); dsn != "" {
+		return dsn
+	}
+	if c.Postgres == nil {
+		return ""
+	}
+	return c.Postgres.DSN()
+}
+
+// DSN formats the settings as a postgres:// URL, omitting unset fields.
+func (p *PostgresConfig) DSN() string {
+	u := url.URL{Scheme: "postgres", Host: p.Host}
+	if p.Port != 0 {
+		u.Host = p.Host + ":" + strconv.Itoa(p.Port)
+	}
+	if p.User != "" {
+		u.User = url.User(p.User)
+	}
+	if p.Database != "" {
+		u.Path = "/" + p.Database
+	}
+	if p.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": {p.SSLMode}}.Encode()
+	}
+	return u.String()
+}
+
+// ParsePostgresURL extracts the settings worth persisting from a postgres://
+// URL. The password, if any, is dropped; hasPassword reports whether one was
+// present so callers can tell the user where to put it instead.
+func ParsePostgresURL(dsn string) (cfg *PostgresConfig, hasPassword bool, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing postgres URL: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return nil, false, fmt.Errorf("not a postgres URL: %q", dsn)
+	}
+
+	cfg = &PostgresConfig{
+		Host:     u.Hostname(),
+		Database: strings.TrimPrefix(u.Path, "/"),
+		SSLMode:  u.Query().Get("sslmode"),
+	}
+	if port := u.Port(); port != "" {
+		if cfg.Port, err = strconv.Atoi(port); err != nil {
+			return nil, false, fmt.Errorf("invalid port %q: %w", port, err)
+		}
+	}
+	if u.User != nil {
+		cfg.User = u.User.Username()
+		_, hasPassword = u.User.Password()
+	}
+	return cfg, hasPassword, nil
+}
@@ -69,6 +69,7 @@ type DaemonInfo struct {
 	ExclusiveLockHolder string
 	Alive               bool
 	Error               string
+	Mux                 bool // served by the multiplex daemon
 }
 
 // DiscoverDaemons discovers running bd daemons using the registry
@@ -143,6 +144,12 @@ func discoverDaemonsLegacy(searchRoots []string) ([]DaemonInfo, error) {
 
 // discoverDaemon attempts to connect to a daemon socket and retrieve its status
 func discoverDaemon(socketPath string) DaemonInfo {
+	return discoverDaemonForDB(socketPath, "")
+}
+
+// discoverDaemonForDB is discoverDaemon for a socket shared by several
+// databases (the multiplex daemon): the status is requested for dbPath.
+func discoverDaemonForDB(socketPath, dbPath string) DaemonInfo {
 	daemon := DaemonInfo{
 		SocketPath: socketPath,
 		Alive:      false,
@@ -186,6 +193,9 @@ func discoverDaemon(socketPath string) DaemonInfo {
 		return daemon
 	}
 	defer func() { _ = client.Close() }()
+	if dbPath != "" {
+		client.SetDatabasePath(dbPath)
+	}
 
 	// Get status
 	status, err := client.Status()
@@ -338,6 +348,9 @@ func StopDaemon(daemon DaemonInfo) error {
 	client, err := rpc.TryConnectWithTimeout(daemon.SocketPath, 500*time.Millisecond)
 	if err == nil && client != nil {
 		defer func() { _ = client.Close() }()
+		if isMuxWorkspace(daemon) {
+			client.SetDatabasePath(daemon.DatabasePath)
+		}
 		if err := client.Shutdown(); err == nil {
 			// Wait a bit for daemon to shut down
 			time.Sleep(200 * time.Millisecond)
@@ -345,6 +358,11 @@ func StopDaemon(daemon DaemonInfo) error {
 		}
 	}
 
+	// Never kill the multiplex daemon to release one of its workspaces
+	if isMuxWorkspace(daemon) {
+		return fmt.Errorf("multiplex daemon did not release %s", daemon.WorkspacePath)
+	}
+
 	// Fallback to SIGTERM if RPC failed
 	return killProcess(daemon.PID)
 }
@@ -369,8 +387,11 @@ func KillAllDaemons(daemons []DaemonInfo, force bool) KillAllResults {
 		Failures: []KillAllFailure{},
 	}
 
+	// A multiplex daemon is listed once per workspace it serves; stop each
+	// process only once.
+	stopped := make(map[int]bool)
 	for _, daemon := range daemons {
-		if !daemon.Alive {
+		if !daemon.Alive || stopped[daemon.PID] {
 			continue
 		}
 
@@ -397,17 +418,34 @@ func KillAllDaemons(daemons []DaemonInfo, force bool) KillAllResults {
 			}
 		}
 		results.Stopped++
+		if !isMuxWorkspace(daemon) {
+			stopped[daemon.PID] = true
+		}
 	}
 
 	return results
 }
 
+// isMuxWorkspace reports whether daemon is a workspace served by the
+// multiplex daemon rather than a daemon process of its own.
+func isMuxWorkspace(daemon DaemonInfo) bool {
+	return daemon.Mux && daemon.DatabasePath != ""
+}
+
 // stopDaemonWithTimeout tries RPC shutdown, then SIGTERM with timeout, then SIGKILL
 func stopDaemonWithTimeout(daemon DaemonInfo) error {
 	// Try RPC shutdown first (2 second timeout)
 	client, err := rpc.TryConnectWithTimeout(daemon.SocketPath, 2*time.Second)
 	if err == nil && client != nil {
 		defer func() { _ = client.Close() }()
+		if isMuxWorkspace(daemon) {
+			// Releasing a workspace leaves the multiplex daemon running
+			client.SetDatabasePath(daemon.DatabasePath)
+			if err := client.Shutdown(); err != nil {
+				return fmt.Errorf("multiplex daemon did not release %s: %w", daemon.WorkspacePath, err)
+			}
+			return nil
+		}
 		if err := client.Shutdown(); err == nil {
 			// Wait and verify process died
 			time.Sleep(500 * time.Millisecond)
@@ -417,6 +455,10 @@ func stopDaemonWithTimeout(daemon DaemonInfo) error {
 		}
 	}
 
+	if isMuxWorkspace(daemon) {
+		return fmt.Errorf("multiplex daemon not reachable at %s", daemon.SocketPath)
+	}
+
 	// Try graceful kill with 3 second timeout
 	if err := killProcess(daemon.PID); err != nil {
 		return fmt.Errorf("kill process failed: %w", err)
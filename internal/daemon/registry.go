@@ -20,6 +20,10 @@ type RegistryEntry struct {
 	PID           int       `json:"pid"`
 	Version       string    `json:"version"`
 	StartedAt     time.Time `json:"started_at"`
+	// Mux marks entries of a multiplex daemon: one entry for the daemon
+	// itself (empty WorkspacePath) and one per workspace it holds open,
+	// all sharing the daemon's PID and socket.
+	Mux bool `json:"mux,omitempty"`
 }
 
 // Registry manages the global daemon registry file
@@ -216,6 +220,48 @@ func (r *Registry) Unregister(workspacePath string, pid int) error {
 	})
 }
 
+// RegisterWorkspace adds a workspace served by a multiplex daemon. Unlike
+// Register it keeps other entries with the same PID, since one multiplex
+// daemon serves many workspaces.
+func (r *Registry) RegisterWorkspace(entry RegistryEntry) error {
+	return r.withFileLock(func() error {
+		entries, err := r.readEntriesLocked()
+		if err != nil {
+			return err
+		}
+
+		filtered := []RegistryEntry{}
+		for _, e := range entries {
+			if !utils.PathsEqual(e.WorkspacePath, entry.WorkspacePath) {
+				filtered = append(filtered, e)
+			}
+		}
+		filtered = append(filtered, entry)
+
+		return r.writeEntriesLocked(filtered)
+	})
+}
+
+// UnregisterWorkspace removes a workspace released by a multiplex daemon,
+// leaving the daemon's other entries in place.
+func (r *Registry) UnregisterWorkspace(workspacePath string, pid int) error {
+	return r.withFileLock(func() error {
+		entries, err := r.readEntriesLocked()
+		if err != nil {
+			return err
+		}
+
+		filtered := []RegistryEntry{}
+		for _, e := range entries {
+			if !utils.PathsEqual(e.WorkspacePath, workspacePath) || e.PID != pid {
+				filtered = append(filtered, e)
+			}
+		}
+
+		return r.writeEntriesLocked(filtered)
+	})
+}
+
 // List returns all daemons from the registry, automatically cleaning up stale entries
 func (r *Registry) List() ([]DaemonInfo, error) {
 	var daemons []DaemonInfo
@@ -238,8 +284,15 @@ func (r *Registry) List() ([]DaemonInfo, error) {
 			// Process is alive, add to both lists
 			aliveEntries = append(aliveEntries, entry)
 
-			// Try to connect and get current status
-			daemon := discoverDaemon(entry.SocketPath)
+			// Try to connect and get current status. Workspaces of a
+			// multiplex daemon share its socket, so ask for theirs by database.
+			var daemon DaemonInfo
+			if entry.Mux && entry.DatabasePath != "" {
+				daemon = discoverDaemonForDB(entry.SocketPath, entry.DatabasePath)
+			} else {
+				daemon = discoverDaemon(entry.SocketPath)
+			}
+			daemon.Mux = entry.Mux
 
 			// If connection failed but process is alive, still include basic info
 			if !daemon.Alive {
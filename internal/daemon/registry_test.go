@@ -138,6 +138,74 @@ func TestRegistryMultipleDaemons(t *testing.T) {
 	}
 }
 
+func TestRegistryMuxWorkspaces(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir)
+
+	registry, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+
+	const muxPID = 20000
+	socketPath := filepath.Join(tmpDir, ".beads", "mux", "bd.sock")
+	if err := registry.Register(RegistryEntry{SocketPath: socketPath, PID: muxPID, Mux: true}); err != nil {
+		t.Fatalf("Failed to register mux daemon: %v", err)
+	}
+	for _, ws := range []string{"/test/a", "/test/b"} {
+		entry := RegistryEntry{
+			WorkspacePath: ws,
+			SocketPath:    socketPath,
+			DatabasePath:  filepath.Join(ws, ".beads", "beads.db"),
+			PID:           muxPID,
+			Mux:           true,
+		}
+		if err := registry.RegisterWorkspace(entry); err != nil {
+			t.Fatalf("Failed to register workspace %s: %v", ws, err)
+		}
+	}
+
+	rawEntries, err := registry.readEntries()
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(rawEntries) != 3 {
+		t.Fatalf("Expected daemon and 2 workspace entries sharing a PID, got %d", len(rawEntries))
+	}
+
+	if err := registry.UnregisterWorkspace("/test/a", muxPID); err != nil {
+		t.Fatalf("Failed to unregister workspace: %v", err)
+	}
+	rawEntries, err = registry.readEntries()
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(rawEntries) != 2 {
+		t.Fatalf("Expected 2 entries after releasing a workspace, got %d", len(rawEntries))
+	}
+	for _, e := range rawEntries {
+		if e.WorkspacePath == "/test/a" {
+			t.Error("Released workspace still registered")
+		}
+		if !e.Mux {
+			t.Errorf("Entry %q lost its mux flag", e.WorkspacePath)
+		}
+	}
+
+	// Unregistering the daemon itself removes all of its workspaces
+	if err := registry.Unregister("", muxPID); err != nil {
+		t.Fatalf("Failed to unregister mux daemon: %v", err)
+	}
+	rawEntries, err = registry.readEntries()
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(rawEntries) != 0 {
+		t.Errorf("Expected empty registry, got %d entries", len(rawEntries))
+	}
+}
+
 func TestRegistryStaleCleanup(t *testing.T) {
 	tmpDir := t.TempDir()
 	oldHome := os.Getenv("HOME")
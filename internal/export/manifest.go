@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/beads/internal/types"
 )
 
 // WriteManifest writes an export manifest alongside the JSONL file
@@ -58,8 +60,28 @@ func WriteManifest(jsonlPath string, manifest *Manifest) error {
 // NewManifest creates a new export manifest
 func NewManifest(policy ErrorPolicy) *Manifest {
 	return &Manifest{
-		ExportedAt:  time.Now(),
-		ErrorPolicy: string(policy),
-		Complete:    true, // Will be set to false if any data is missing
+		ExportedAt:    time.Now(),
+		ErrorPolicy:   string(policy),
+		Complete:      true, // Will be set to false if any data is missing
+		FormatVersion: types.JSONLFormatVersion,
+	}
+}
+
+// ReadManifest reads the manifest written alongside a JSONL file.
+// Returns nil (and no error) if there is no manifest.
+func ReadManifest(jsonlPath string) (*Manifest, error) {
+	manifestPath := strings.TrimSuffix(jsonlPath, ".jsonl") + ".manifest.json"
+	// #nosec G304 - path derived from the JSONL path
+	data, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
 	}
+	return &manifest, nil
 }
@@ -62,6 +62,7 @@ type Manifest struct {
 	Complete       bool          `json:"complete"`
 	ExportedAt     time.Time     `json:"exported_at"`
 	ErrorPolicy    string        `json:"error_policy"`
+	FormatVersion  int           `json:"format_version"` // JSONL record format (types.JSONLFormatVersion)
 }
 
 // FailedIssue tracks a single issue that failed to export
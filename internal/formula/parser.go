@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -383,33 +384,56 @@ func ValidateVars(formula *Formula, values map[string]string) error {
 			continue
 		}
 
-		// Check enum constraint
-		if len(def.Enum) > 0 {
-			found := false
-			for _, allowed := range def.Enum {
-				if val == allowed {
-					found = true
-					break
-				}
-			}
-			if !found {
-				errs = append(errs, fmt.Sprintf("variable %q: value %q not in allowed values %v", name, val, def.Enum))
-			}
+		if err := ValidateVarValue(name, def, val); err != nil {
+			errs = append(errs, err.Error())
 		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("variable validation failed:\n  - %s", strings.Join(errs, "\n  - "))
+	}
+
+	return nil
+}
 
-		// Check pattern constraint
-		if def.Pattern != "" {
-			re, err := regexp.Compile(def.Pattern)
-			if err != nil {
-				errs = append(errs, fmt.Sprintf("variable %q: invalid pattern %q: %v", name, def.Pattern, err))
-			} else if !re.MatchString(val) {
-				errs = append(errs, fmt.Sprintf("variable %q: value %q does not match pattern %q", name, val, def.Pattern))
+// ValidateVarValue checks a single value against a variable's enum,
+// pattern and type constraints. Required-ness is not checked here.
+func ValidateVarValue(name string, def *VarDef, val string) error {
+	if len(def.Enum) > 0 {
+		found := false
+		for _, allowed := range def.Enum {
+			if val == allowed {
+				found = true
+				break
 			}
 		}
+		if !found {
+			return fmt.Errorf("variable %q: value %q not in allowed values %v", name, val, def.Enum)
+		}
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("variable validation failed:\n  - %s", strings.Join(errs, "\n  - "))
+	if def.Pattern != "" {
+		re, err := regexp.Compile(def.Pattern)
+		if err != nil {
+			return fmt.Errorf("variable %q: invalid pattern %q: %v", name, def.Pattern, err)
+		}
+		if !re.MatchString(val) {
+			return fmt.Errorf("variable %q: value %q does not match pattern %q", name, val, def.Pattern)
+		}
+	}
+
+	switch def.Type {
+	case "", "string":
+	case "int":
+		if _, err := strconv.Atoi(val); err != nil {
+			return fmt.Errorf("variable %q: value %q is not an int", name, val)
+		}
+	case "bool":
+		if _, err := strconv.ParseBool(val); err != nil {
+			return fmt.Errorf("variable %q: value %q is not a bool", name, val)
+		}
+	default:
+		return fmt.Errorf("variable %q: unknown type %q (must be string, int, or bool)", name, def.Type)
 	}
 
 	return nil
@@ -344,6 +344,8 @@ func TestValidateVars(t *testing.T) {
 			"enum_var":     {Enum: []string{"a", "b", "c"}},
 			"pattern_var":  {Pattern: `^[a-z]+$`},
 			"optional_var": {Default: "default"},
+			"int_var":      {Type: "int"},
+			"bool_var":     {Type: "bool"},
 		},
 	}
 
@@ -382,6 +384,21 @@ func TestValidateVars(t *testing.T) {
 			values:  map[string]string{"required_var": "x", "pattern_var": "123"},
 			wantErr: true,
 		},
+		{
+			name:    "valid int and bool",
+			values:  map[string]string{"required_var": "x", "int_var": "42", "bool_var": "true"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid int",
+			values:  map[string]string{"required_var": "x", "int_var": "forty-two"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid bool",
+			values:  map[string]string{"required_var": "x", "bool_var": "maybe"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
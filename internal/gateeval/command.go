@@ -0,0 +1,198 @@
+package gateeval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// commandEnvKeys are the only parent environment variables a cmd gate sees.
+var commandEnvKeys = []string{"PATH", "HOME", "TMPDIR", "TEMP", "TMP", "LANG", "SYSTEMROOT"}
+
+// CommandEvaluator resolves "cmd" gates when a command exits with status 0.
+//
+// The await_id is the command line. It is split into arguments like a
+// shell would split words (single and double quotes group) but is not run
+// by a shell, so there is no expansion, piping or redirection; wrap it in
+// "sh -c '...'" explicitly if that is wanted. The command runs in Dir with
+// a minimal environment (PATH, HOME, temp dirs, LANG plus BD_GATE_ID) and is
+// killed, along with its children, after Timeout.
+//
+// Gate issues can arrive from other clones, so only commands matching Allow
+// (see CommandAllowed) are run; any other command leaves the gate pending.
+type CommandEvaluator struct {
+	Runner  CommandRunner
+	Dir     string
+	Timeout time.Duration
+	Allow   []string
+}
+
+// Type implements Evaluator.
+func (e *CommandEvaluator) Type() string { return "cmd" }
+
+// Evaluate implements Evaluator.
+func (e *CommandEvaluator) Evaluate(ctx context.Context, gate *types.Issue) (Result, error) {
+	argv, err := SplitCommandLine(gate.AwaitID)
+	if err != nil {
+		return Result{Reason: fmt.Sprintf("invalid command: %v", err)}, nil
+	}
+	if len(argv) == 0 {
+		return Result{Reason: "no command specified"}, nil
+	}
+	if !CommandAllowed(e.Allow, argv) {
+		return Result{Reason: fmt.Sprintf("%s is not in the cmd gate allowlist (gate.cmd.allow)", argv[0])}, nil
+	}
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	runner := e.Runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	_, stderr, err := runner.Run(runCtx, e.Dir, commandEnv(gate.ID), argv[0], argv[1:]...)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return Result{Resolved: true, Reason: fmt.Sprintf("%s exited 0", argv[0])}, nil
+	case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+		return Result{Reason: fmt.Sprintf("%s timed out after %s", argv[0], timeout)}, nil
+	case errors.As(err, &exitErr):
+		reason := fmt.Sprintf("%s exited %d", argv[0], exitErr.ExitCode())
+		if msg := lastLine(stderr); msg != "" {
+			reason += ": " + msg
+		}
+		return Result{Reason: reason}, nil
+	default:
+		return Result{}, fmt.Errorf("running %s: %w", argv[0], err)
+	}
+}
+
+// CommandAllowed reports whether argv matches an entry of allow. Entries
+// are split like command lines and compared word for word; a final "*"
+// word matches any remaining arguments, including none. Entries that fail
+// to parse match nothing.
+func CommandAllowed(allow []string, argv []string) bool {
+	for _, entry := range allow {
+		words, err := SplitCommandLine(entry)
+		if err != nil || len(words) == 0 {
+			continue
+		}
+		if words[len(words)-1] == "*" {
+			prefix := words[:len(words)-1]
+			if len(argv) >= len(prefix) && equalWords(prefix, argv[:len(prefix)]) {
+				return true
+			}
+			continue
+		}
+		if equalWords(words, argv) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func commandEnv(gateID string) []string {
+	env := []string{"BD_GATE_ID=" + gateID}
+	for _, key := range commandEnvKeys {
+		if v, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+v)
+		}
+	}
+	return env
+}
+
+func lastLine(b []byte) string {
+	s := strings.TrimSpace(string(b))
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	}
+	return s
+}
+
+// SplitCommandLine splits s into words. Whitespace separates words; single
+// quotes group literally, double quotes group and honor \" and \\.
+func SplitCommandLine(s string) ([]string, error) {
+	var (
+		words   []string
+		cur     strings.Builder
+		inWord  bool
+		quote   byte
+		escaped bool
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			cur.WriteByte(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+		case quote == '"':
+			switch c {
+			case '"':
+				quote = 0
+			case '\\':
+				if i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+					escaped = true
+				} else {
+					cur.WriteByte(c)
+				}
+			default:
+				cur.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == '\\':
+			escaped = true
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,126 @@
+package gateeval
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"make test", []string{"make", "test"}},
+		{`  grep -q "all good"  status.txt `, []string{"grep", "-q", "all good", "status.txt"}},
+		{`sh -c 'test -f done && echo "$X"'`, []string{"sh", "-c", `test -f done && echo "$X"`}},
+		{`echo "say \"hi\"" a\ b`, []string{"echo", `say "hi"`, "a b"}},
+		{`echo ""`, []string{"echo", ""}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommandLine(tt.in)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCommandLine(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{`echo "open`, `echo 'open`, `echo \`} {
+		if _, err := SplitCommandLine(bad); err == nil {
+			t.Errorf("SplitCommandLine(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCommandEvaluator(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir := t.TempDir()
+	e := &CommandEvaluator{Dir: dir, Timeout: 5 * time.Second, Allow: []string{"true", "sh -c *", "definitely-not-a-command-xyz"}}
+	eval := func(cmd string) Result {
+		t.Helper()
+		res, err := e.Evaluate(context.Background(), &types.Issue{ID: "bd-g1", AwaitType: "cmd", AwaitID: cmd})
+		if err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+		return res
+	}
+
+	if res := eval("true"); !res.Resolved {
+		t.Errorf("true: %+v", res)
+	}
+	if res := eval(`sh -c 'echo nope >&2; exit 3'`); res.Resolved || !strings.Contains(res.Reason, "exited 3: nope") {
+		t.Errorf("exit 3: %+v", res)
+	}
+	// Runs in Dir with BD_GATE_ID set and without the rest of the environment.
+	t.Setenv("BD_GATE_SECRET", "leak")
+	if res := eval(`sh -c 'test "$BD_GATE_ID" = bd-g1 && test -z "$BD_GATE_SECRET" && test "$(pwd -P)" = "$(cd ` + dir + ` && pwd -P)"'`); !res.Resolved {
+		t.Errorf("environment check: %+v", res)
+	}
+
+	if _, err := e.Evaluate(context.Background(), &types.Issue{AwaitID: "definitely-not-a-command-xyz"}); err == nil {
+		t.Error("expected an error for a missing command")
+	}
+
+	// Commands outside the allowlist never run
+	marker := dir + "/ran"
+	if res := eval("touch " + marker); res.Resolved || !strings.Contains(res.Reason, "allowlist") {
+		t.Errorf("unlisted command: %+v", res)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("unlisted command was run")
+	}
+}
+
+func TestCommandAllowed(t *testing.T) {
+	allow := []string{"make test", "./scripts/ready.sh *", `grep -q "all good" status.txt`, `bad "quote`}
+	tests := []struct {
+		cmd  string
+		want bool
+	}{
+		{"make test", true},
+		{"make  test", true},
+		{"make test-all", false},
+		{"make test extra", false},
+		{"make", false},
+		{"./scripts/ready.sh", true},
+		{"./scripts/ready.sh --env prod", true},
+		{"./scripts/ready.shx", false},
+		{`grep -q 'all good' status.txt`, true},
+		{"grep -q all good status.txt", false},
+		{`bad "quote`, false},
+	}
+	for _, tt := range tests {
+		argv, _ := SplitCommandLine(tt.cmd)
+		if got := CommandAllowed(allow, argv); got != tt.want {
+			t.Errorf("CommandAllowed(%q) = %v, want %v", tt.cmd, got, tt.want)
+		}
+	}
+	if CommandAllowed(nil, []string{"true"}) {
+		t.Error("an empty allowlist should allow nothing")
+	}
+}
+
+func TestCommandEvaluatorTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	e := &CommandEvaluator{Timeout: 200 * time.Millisecond, Allow: []string{"sh *"}}
+	start := time.Now()
+	res, err := e.Evaluate(context.Background(), &types.Issue{AwaitID: "sh -c 'sleep 10 & sleep 10'"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if res.Resolved || !strings.Contains(res.Reason, "timed out") {
+		t.Errorf("result = %+v, want a timeout", res)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("timed-out command took %s to return", elapsed)
+	}
+}
@@ -0,0 +1,165 @@
+package gateeval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Escalation describes a gate that needs attention.
+type Escalation struct {
+	GateID    string    `json:"gate_id"`
+	AwaitType string    `json:"await_type"`
+	AwaitID   string    `json:"await_id,omitempty"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewEscalation describes gate for escalation.
+func NewEscalation(gate *types.Issue, reason string) Escalation {
+	return Escalation{
+		GateID:    gate.ID,
+		AwaitType: gate.AwaitType,
+		AwaitID:   gate.AwaitID,
+		Reason:    reason,
+		CreatedAt: gate.CreatedAt,
+	}
+}
+
+// Topic is a one-line summary of the escalation.
+func (e Escalation) Topic() string {
+	return fmt.Sprintf("Gate escalation: %s", e.GateID)
+}
+
+// Message is the escalation body.
+func (e Escalation) Message() string {
+	return fmt.Sprintf("Gate %s needs attention.\nType: %s\nReason: %s\nCreated: %s",
+		e.GateID, e.AwaitType, e.Reason, e.CreatedAt.Format(time.RFC3339))
+}
+
+// Escalator delivers escalations somewhere a person will see them.
+type Escalator interface {
+	Escalate(ctx context.Context, e Escalation) error
+}
+
+// DefaultEscalationTargets is used when gate.escalate is not configured.
+var DefaultEscalationTargets = []string{"gt"}
+
+// ParseEscalators builds escalators from gate.escalate entries:
+//
+//	gt               run "gt escalate <topic> -s HIGH -m <message>"
+//	exec:<command>   run a command; {gate}, {type}, {await_id}, {reason},
+//	                 {topic} and {message} in its arguments are replaced
+//	webhook:<url>    POST the escalation as JSON
+//	none             escalate nowhere
+func ParseEscalators(targets []string, runner CommandRunner, client *http.Client) ([]Escalator, error) {
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	var out []Escalator
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		kind, arg, _ := strings.Cut(target, ":")
+		switch kind {
+		case "gt":
+			out = append(out, &CommandEscalator{Runner: runner,
+				Argv: []string{"gt", "escalate", "{topic}", "-s", "HIGH", "-m", "{message}"}})
+		case "exec":
+			argv, err := SplitCommandLine(arg)
+			if err != nil || len(argv) == 0 {
+				return nil, fmt.Errorf("invalid escalation target %q: expected exec:<command>", target)
+			}
+			out = append(out, &CommandEscalator{Runner: runner, Argv: argv})
+		case "webhook":
+			if !strings.HasPrefix(arg, "http://") && !strings.HasPrefix(arg, "https://") {
+				return nil, fmt.Errorf("invalid escalation target %q: expected webhook:<http(s) URL>", target)
+			}
+			out = append(out, &WebhookEscalator{URL: arg, Client: client})
+		case "none", "":
+		default:
+			return nil, fmt.Errorf("unknown escalation target %q (valid: gt, exec:<command>, webhook:<url>, none)", target)
+		}
+	}
+	return out, nil
+}
+
+// CommandEscalator runs a command for each escalation.
+type CommandEscalator struct {
+	Runner CommandRunner
+	Argv   []string
+}
+
+// Escalate implements Escalator.
+func (c *CommandEscalator) Escalate(ctx context.Context, e Escalation) error {
+	return runTemplate(ctx, c.Runner, c.Argv, strings.NewReplacer(
+		"{gate}", e.GateID,
+		"{type}", e.AwaitType,
+		"{await_id}", e.AwaitID,
+		"{reason}", e.Reason,
+		"{topic}", e.Topic(),
+		"{message}", e.Message(),
+	))
+}
+
+// runTemplate runs argv after replacing placeholders in each argument.
+func runTemplate(ctx context.Context, runner CommandRunner, argv []string, replacer *strings.Replacer) error {
+	args := make([]string, len(argv))
+	for i, arg := range argv {
+		args[i] = replacer.Replace(arg)
+	}
+	_, stderr, err := runner.Run(ctx, "", nil, args[0], args[1:]...)
+	if err != nil {
+		if msg := lastLine(stderr); msg != "" {
+			return fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
+
+// WebhookEscalator POSTs each escalation as JSON to URL.
+type WebhookEscalator struct {
+	URL    string
+	Client *http.Client
+}
+
+// Escalate implements Escalator.
+func (w *WebhookEscalator) Escalate(ctx context.Context, e Escalation) error {
+	payload := struct {
+		Escalation
+		Topic   string `json:"topic"`
+		Message string `json:"message"`
+	}{e, e.Topic(), e.Message()}
+	return postJSON(ctx, w.Client, w.URL, payload)
+}
+
+// postJSON POSTs payload as JSON and fails on a non-2xx response.
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %d", url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package gateeval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParseEscalators(t *testing.T) {
+	for _, targets := range [][]string{
+		{"gt"},
+		{"exec:notify-send {topic}", "webhook:https://hooks.example.com/x"},
+		{"none"},
+		nil,
+	} {
+		if _, err := ParseEscalators(targets, &fakeRunner{}, nil); err != nil {
+			t.Errorf("ParseEscalators(%q): %v", targets, err)
+		}
+	}
+	for _, bad := range []string{"pager", "exec:", "webhook:ftp://x"} {
+		if _, err := ParseEscalators([]string{bad}, &fakeRunner{}, nil); err == nil {
+			t.Errorf("ParseEscalators(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCommandEscalators(t *testing.T) {
+	gate := &types.Issue{ID: "bd-g1", AwaitType: "gh:run", AwaitID: "123", CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	e := NewEscalation(gate, "workflow failed")
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"gt escalate " + e.Topic() + " -s HIGH -m " + e.Message(): {},
+		"page-oncall bd-g1 gh:run workflow failed":                {},
+	}}
+
+	escalators, err := ParseEscalators([]string{"gt", "exec:page-oncall {gate} {type} '{reason}'"}, runner, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, esc := range escalators {
+		if err := esc.Escalate(context.Background(), e); err != nil {
+			t.Errorf("Escalate: %v (calls %q)", err, runner.calls)
+		}
+	}
+	if len(runner.calls) != 2 {
+		t.Errorf("calls = %q, want gt and page-oncall", runner.calls)
+	}
+}
+
+func TestWebhookEscalator(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding webhook body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	escalators, err := ParseEscalators([]string{"webhook:" + srv.URL}, nil, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEscalation(&types.Issue{ID: "bd-g2", AwaitType: "http"}, "still down")
+	if err := escalators[0].Escalate(context.Background(), e); err != nil {
+		t.Fatalf("Escalate: %v", err)
+	}
+	if got["gate_id"] != "bd-g2" || got["reason"] != "still down" || got["topic"] != "Gate escalation: bd-g2" {
+		t.Errorf("webhook payload = %v", got)
+	}
+}
+
+func TestWakers(t *testing.T) {
+	var got Wake
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding webhook body: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	runner := &fakeRunner{responses: map[string]fakeResponse{
+		"gt gate wake bd-g1 mayor/":        {},
+		"notify mayor/ bd-g1 run finished": {},
+	}}
+	wakers, err := ParseWakers([]string{"gt", "exec:notify {waiter} {gate} '{reason}'", "webhook:" + srv.URL}, runner, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := Wake{GateID: "bd-g1", Waiter: "mayor/", Reason: "run finished"}
+	for _, waker := range wakers {
+		if err := waker.Wake(context.Background(), w); err != nil {
+			t.Errorf("Wake: %v (calls %q)", err, runner.calls)
+		}
+	}
+	if len(runner.calls) != 2 {
+		t.Errorf("calls = %q, want gt and notify", runner.calls)
+	}
+	if got != w {
+		t.Errorf("webhook got %+v, want %+v", got, w)
+	}
+	if _, err := ParseWakers([]string{"sms"}, runner, nil); err == nil {
+		t.Error("ParseWakers(sms) should fail")
+	}
+}
@@ -0,0 +1,245 @@
+package gateeval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// runCLI runs a forge CLI (gh, glab) and turns "not installed" into a clear
+// error. The stderr text is returned for callers that classify failures.
+func runCLI(ctx context.Context, r CommandRunner, dir, name string, args ...string) ([]byte, string, error) {
+	stdout, stderr, err := r.Run(ctx, dir, nil, name, args...)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) || strings.Contains(string(stderr), "command not found") ||
+			strings.Contains(err.Error(), "executable file not found") {
+			return nil, "", fmt.Errorf("%s CLI not installed", name)
+		}
+		return stdout, string(stderr), err
+	}
+	return stdout, string(stderr), nil
+}
+
+// isNumericID returns true if the string contains only digits.
+func isNumericID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// GitHubRunEvaluator resolves "gh:run" gates when a GitHub Actions run
+// succeeds, using the gh CLI. A non-numeric await_id is a workflow name;
+// its most recent run is looked up and reported in Result.AwaitID.
+type GitHubRunEvaluator struct {
+	Runner CommandRunner
+	Dir    string
+}
+
+// Type implements Evaluator.
+func (e *GitHubRunEvaluator) Type() string { return "gh:run" }
+
+// Evaluate implements Evaluator.
+func (e *GitHubRunEvaluator) Evaluate(ctx context.Context, gate *types.Issue) (Result, error) {
+	if gate.AwaitID == "" {
+		return Result{Reason: "no run ID specified - set await_id or use workflow name hint"}, nil
+	}
+
+	var res Result
+	runID := gate.AwaitID
+	if !isNumericID(runID) {
+		discovered, err := e.latestRun(ctx, gate.AwaitID)
+		if err != nil {
+			return Result{Reason: fmt.Sprintf("workflow hint '%s': %v", gate.AwaitID, err)}, nil
+		}
+		runID = discovered
+		res.AwaitID = discovered
+	}
+
+	stdout, stderr, err := runCLI(ctx, e.Runner, e.Dir, "gh", "run", "view", runID, "--json", "status,conclusion,name")
+	if err != nil {
+		if stderr == "" {
+			return res, err
+		}
+		if strings.Contains(stderr, "not found") {
+			res.Escalated, res.Reason = true, "workflow run not found"
+			return res, nil
+		}
+		return res, fmt.Errorf("gh run view failed: %s", stderr)
+	}
+
+	var status struct {
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+		Name       string `json:"name"`
+	}
+	if err := json.Unmarshal(stdout, &status); err != nil {
+		return res, fmt.Errorf("failed to parse gh output: %w", err)
+	}
+
+	switch status.Status {
+	case "completed":
+		switch status.Conclusion {
+		case "success":
+			res.Resolved, res.Reason = true, fmt.Sprintf("workflow '%s' succeeded", status.Name)
+		case "failure":
+			res.Escalated, res.Reason = true, fmt.Sprintf("workflow '%s' failed", status.Name)
+		case "cancelled", "canceled":
+			res.Escalated, res.Reason = true, fmt.Sprintf("workflow '%s' was canceled", status.Name)
+		case "skipped":
+			res.Resolved, res.Reason = true, fmt.Sprintf("workflow '%s' was skipped", status.Name)
+		default:
+			res.Escalated, res.Reason = true, fmt.Sprintf("workflow '%s' concluded with %s", status.Name, status.Conclusion)
+		}
+	case "in_progress", "queued", "pending", "waiting":
+		res.Reason = fmt.Sprintf("workflow '%s' is %s", status.Name, status.Status)
+	default:
+		res.Reason = fmt.Sprintf("workflow '%s' status: %s", status.Name, status.Status)
+	}
+	return res, nil
+}
+
+// latestRun returns the ID of the most recent run of a workflow. "Most
+// recent" is deterministic: gh lists runs newest-first.
+func (e *GitHubRunEvaluator) latestRun(ctx context.Context, workflow string) (string, error) {
+	stdout, stderr, err := runCLI(ctx, e.Runner, e.Dir, "gh", "run", "list",
+		"--workflow", workflow, "--json", "databaseId", "--limit", "5")
+	if err != nil {
+		if stderr != "" {
+			return "", fmt.Errorf("gh run list --workflow=%s failed: %s", workflow, stderr)
+		}
+		return "", err
+	}
+	var runs []struct {
+		DatabaseID int64 `json:"databaseId"`
+	}
+	if err := json.Unmarshal(stdout, &runs); err != nil {
+		return "", fmt.Errorf("parse gh output: %w", err)
+	}
+	if len(runs) == 0 {
+		return "", fmt.Errorf("no runs found for workflow '%s'", workflow)
+	}
+	return fmt.Sprintf("%d", runs[0].DatabaseID), nil
+}
+
+// GitHubPREvaluator resolves "gh:pr" gates when a pull request is merged,
+// using the gh CLI. A PR closed without merging escalates.
+type GitHubPREvaluator struct {
+	Runner CommandRunner
+	Dir    string
+}
+
+// Type implements Evaluator.
+func (e *GitHubPREvaluator) Type() string { return "gh:pr" }
+
+// Evaluate implements Evaluator.
+func (e *GitHubPREvaluator) Evaluate(ctx context.Context, gate *types.Issue) (Result, error) {
+	if gate.AwaitID == "" {
+		return Result{Reason: "no PR number specified"}, nil
+	}
+
+	stdout, stderr, err := runCLI(ctx, e.Runner, e.Dir, "gh", "pr", "view", gate.AwaitID, "--json", "state,merged,title")
+	if err != nil {
+		if stderr == "" {
+			return Result{}, err
+		}
+		if strings.Contains(stderr, "not found") || strings.Contains(stderr, "Could not resolve") {
+			return Result{Escalated: true, Reason: "pull request not found"}, nil
+		}
+		return Result{}, fmt.Errorf("gh pr view failed: %s", stderr)
+	}
+
+	var status struct {
+		State  string `json:"state"`
+		Merged bool   `json:"merged"`
+		Title  string `json:"title"`
+	}
+	if err := json.Unmarshal(stdout, &status); err != nil {
+		return Result{}, fmt.Errorf("failed to parse gh output: %w", err)
+	}
+
+	switch status.State {
+	case "MERGED":
+		return Result{Resolved: true, Reason: fmt.Sprintf("PR '%s' was merged", status.Title)}, nil
+	case "CLOSED":
+		if status.Merged {
+			return Result{Resolved: true, Reason: fmt.Sprintf("PR '%s' was merged", status.Title)}, nil
+		}
+		return Result{Escalated: true, Reason: fmt.Sprintf("PR '%s' was closed without merging", status.Title)}, nil
+	case "OPEN":
+		return Result{Reason: fmt.Sprintf("PR '%s' is still open", status.Title)}, nil
+	default:
+		return Result{Reason: fmt.Sprintf("PR '%s' state: %s", status.Title, status.State)}, nil
+	}
+}
+
+// GitLabMREvaluator resolves "gitlab:mr" gates when a merge request is
+// merged, using the glab CLI. await_id is the MR IID, optionally qualified
+// with the project ("group/project!42" or "group/project:42").
+type GitLabMREvaluator struct {
+	Runner CommandRunner
+	Dir    string
+}
+
+// Type implements Evaluator.
+func (e *GitLabMREvaluator) Type() string { return "gitlab:mr" }
+
+// Evaluate implements Evaluator.
+func (e *GitLabMREvaluator) Evaluate(ctx context.Context, gate *types.Issue) (Result, error) {
+	if gate.AwaitID == "" {
+		return Result{Reason: "no merge request specified"}, nil
+	}
+
+	args := []string{"mr", "view"}
+	project, iid := "", gate.AwaitID
+	if i := strings.LastIndexAny(gate.AwaitID, "!:"); i > 0 {
+		project, iid = gate.AwaitID[:i], gate.AwaitID[i+1:]
+	}
+	if !isNumericID(iid) {
+		return Result{Reason: fmt.Sprintf("invalid merge request %q", gate.AwaitID)}, nil
+	}
+	args = append(args, iid, "--output", "json")
+	if project != "" {
+		args = append(args, "--repo", project)
+	}
+
+	stdout, stderr, err := runCLI(ctx, e.Runner, e.Dir, "glab", args...)
+	if err != nil {
+		if stderr == "" {
+			return Result{}, err
+		}
+		if strings.Contains(stderr, "404") || strings.Contains(stderr, "not found") {
+			return Result{Escalated: true, Reason: "merge request not found"}, nil
+		}
+		return Result{}, fmt.Errorf("glab mr view failed: %s", stderr)
+	}
+
+	var mr struct {
+		State string `json:"state"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(stdout, &mr); err != nil {
+		return Result{}, fmt.Errorf("failed to parse glab output: %w", err)
+	}
+
+	switch mr.State {
+	case "merged":
+		return Result{Resolved: true, Reason: fmt.Sprintf("MR '%s' was merged", mr.Title)}, nil
+	case "closed":
+		return Result{Escalated: true, Reason: fmt.Sprintf("MR '%s' was closed without merging", mr.Title)}, nil
+	case "opened", "locked":
+		return Result{Reason: fmt.Sprintf("MR '%s' is still %s", mr.Title, mr.State)}, nil
+	default:
+		return Result{Reason: fmt.Sprintf("MR '%s' state: %s", mr.Title, mr.State)}, nil
+	}
+}
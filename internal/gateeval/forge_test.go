@@ -0,0 +1,132 @@
+package gateeval
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestGitHubRunEvaluator(t *testing.T) {
+	tests := []struct {
+		name          string
+		awaitID       string
+		responses     map[string]fakeResponse
+		wantResolved  bool
+		wantEscalated bool
+		wantAwaitID   string
+		wantErr       bool
+	}{
+		{
+			name:    "success",
+			awaitID: "123",
+			responses: map[string]fakeResponse{
+				"gh run view 123 --json status,conclusion,name": {stdout: `{"status":"completed","conclusion":"success","name":"CI"}`},
+			},
+			wantResolved: true,
+		},
+		{
+			name:    "failure escalates",
+			awaitID: "123",
+			responses: map[string]fakeResponse{
+				"gh run view 123 --json status,conclusion,name": {stdout: `{"status":"completed","conclusion":"failure","name":"CI"}`},
+			},
+			wantEscalated: true,
+		},
+		{
+			name:    "in progress",
+			awaitID: "123",
+			responses: map[string]fakeResponse{
+				"gh run view 123 --json status,conclusion,name": {stdout: `{"status":"in_progress","name":"CI"}`},
+			},
+		},
+		{
+			name:    "workflow name is resolved to its latest run",
+			awaitID: "release.yml",
+			responses: map[string]fakeResponse{
+				"gh run list --workflow release.yml --json databaseId --limit 5": {stdout: `[{"databaseId":987},{"databaseId":900}]`},
+				"gh run view 987 --json status,conclusion,name":                  {stdout: `{"status":"queued","name":"Release"}`},
+			},
+			wantAwaitID: "987",
+		},
+		{
+			name:    "missing run escalates",
+			awaitID: "123",
+			responses: map[string]fakeResponse{
+				"gh run view 123 --json status,conclusion,name": {stderr: "run 123 not found", err: errors.New("exit status 1")},
+			},
+			wantEscalated: true,
+		},
+		{
+			name:    "gh not installed",
+			awaitID: "123",
+			responses: map[string]fakeResponse{
+				"gh run view 123 --json status,conclusion,name": {err: errors.New(`exec: "gh": executable file not found in $PATH`)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &GitHubRunEvaluator{Runner: &fakeRunner{responses: tt.responses}}
+			res, err := e.Evaluate(context.Background(), &types.Issue{AwaitType: "gh:run", AwaitID: tt.awaitID})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if res.Resolved != tt.wantResolved || res.Escalated != tt.wantEscalated || res.AwaitID != tt.wantAwaitID {
+				t.Errorf("result = %+v", res)
+			}
+		})
+	}
+}
+
+func TestGitHubPREvaluator(t *testing.T) {
+	tests := []struct {
+		stdout        string
+		wantResolved  bool
+		wantEscalated bool
+	}{
+		{`{"state":"MERGED","title":"x"}`, true, false},
+		{`{"state":"CLOSED","merged":false,"title":"x"}`, false, true},
+		{`{"state":"OPEN","title":"x"}`, false, false},
+	}
+	for _, tt := range tests {
+		runner := &fakeRunner{responses: map[string]fakeResponse{
+			"gh pr view 42 --json state,merged,title": {stdout: tt.stdout},
+		}}
+		e := &GitHubPREvaluator{Runner: runner}
+		res, err := e.Evaluate(context.Background(), &types.Issue{AwaitType: "gh:pr", AwaitID: "42"})
+		if err != nil || res.Resolved != tt.wantResolved || res.Escalated != tt.wantEscalated {
+			t.Errorf("%s: result = %+v, err = %v", tt.stdout, res, err)
+		}
+	}
+}
+
+func TestGitLabMREvaluator(t *testing.T) {
+	tests := []struct {
+		awaitID       string
+		command       string
+		stdout        string
+		wantResolved  bool
+		wantEscalated bool
+	}{
+		{"42", "glab mr view 42 --output json", `{"state":"merged","title":"x"}`, true, false},
+		{"group/project!42", "glab mr view 42 --output json --repo group/project", `{"state":"opened","title":"x"}`, false, false},
+		{"group/sub/project:7", "glab mr view 7 --output json --repo group/sub/project", `{"state":"closed","title":"x"}`, false, true},
+	}
+	for _, tt := range tests {
+		runner := &fakeRunner{responses: map[string]fakeResponse{tt.command: {stdout: tt.stdout}}}
+		e := &GitLabMREvaluator{Runner: runner}
+		res, err := e.Evaluate(context.Background(), &types.Issue{AwaitType: "gitlab:mr", AwaitID: tt.awaitID})
+		if err != nil || res.Resolved != tt.wantResolved || res.Escalated != tt.wantEscalated {
+			t.Errorf("%s: result = %+v, err = %v (calls %v)", tt.awaitID, res, err, runner.calls)
+		}
+	}
+
+	e := &GitLabMREvaluator{Runner: &fakeRunner{}}
+	if res, err := e.Evaluate(context.Background(), &types.Issue{AwaitID: "abc"}); err != nil || res.Resolved {
+		t.Errorf("invalid MR: result = %+v, err = %v", res, err)
+	}
+}
@@ -0,0 +1,200 @@
+// Package gateeval evaluates async gates.
+//
+// A gate issue names its wait condition with AwaitType (e.g. "gh:run",
+// "timer") and AwaitID (the run ID, URL, path, ...). Each AwaitType is
+// handled by an Evaluator registered in a Registry; NewDefaultRegistry
+// registers the built-in types. Evaluators reach the outside world only
+// through Deps, so each one can be tested with fakes.
+package gateeval
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/beads/internal/formula"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Result is the outcome of evaluating a gate.
+type Result struct {
+	// Resolved means the condition is met and the gate can be closed.
+	Resolved bool
+
+	// Escalated means the condition can no longer be met (e.g. a failed
+	// workflow) and someone should look at it.
+	Escalated bool
+
+	// Reason explains the outcome for display.
+	Reason string
+
+	// AwaitID, if set, is a more specific await_id the evaluator resolved
+	// (e.g. a gh:run workflow name resolved to a run ID). Callers persist it
+	// so later evaluations track the same target.
+	AwaitID string
+}
+
+// Evaluator checks one kind of gate.
+type Evaluator interface {
+	// Type is the AwaitType this evaluator handles, e.g. "gh:run".
+	Type() string
+
+	// Evaluate checks the gate's condition. A returned error means the
+	// condition could not be checked (tool missing, network down); the gate
+	// stays pending.
+	Evaluate(ctx context.Context, gate *types.Issue) (Result, error)
+}
+
+// Registry maps await types to evaluators. It is safe for concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	evaluators map[string]Evaluator
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{evaluators: make(map[string]Evaluator)}
+}
+
+// Register adds e, replacing any evaluator registered for the same type.
+func (r *Registry) Register(e Evaluator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evaluators[e.Type()] = e
+}
+
+// Lookup returns the evaluator for awaitType. Besides exact matches, a
+// registered "gh:run" also handles qualified types such as "gh:run:deploy".
+func (r *Registry) Lookup(awaitType string) (Evaluator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if e, ok := r.evaluators[awaitType]; ok {
+		return e, true
+	}
+	for t := awaitType; ; {
+		i := strings.LastIndex(t, ":")
+		if i <= 0 {
+			return nil, false
+		}
+		t = t[:i]
+		if e, ok := r.evaluators[t]; ok {
+			return e, true
+		}
+	}
+}
+
+// Types returns the registered await types in sorted order.
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]string, 0, len(r.evaluators))
+	for t := range r.evaluators {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Evaluate runs the evaluator for gate.AwaitType. ok is false when no
+// evaluator handles the type (e.g. human gates, which are resolved by hand).
+func (r *Registry) Evaluate(ctx context.Context, gate *types.Issue) (res Result, ok bool, err error) {
+	e, ok := r.Lookup(gate.AwaitType)
+	if !ok {
+		return Result{}, false, nil
+	}
+	res, err = e.Evaluate(ctx, gate)
+	return res, true, err
+}
+
+// CommandRunner runs external commands (gh, glab, cmd gates, escalation).
+type CommandRunner interface {
+	// Run runs name with args in dir (the current directory if empty) with
+	// the given environment (the parent's if nil) and returns its output.
+	Run(ctx context.Context, dir string, env []string, name string, args ...string) (stdout, stderr []byte, err error)
+}
+
+// Deps are the effects evaluators depend on. Zero fields get defaults from
+// NewDefaultRegistry, except CheckBead and StepStates: without them the
+// bead and formula-condition evaluators report an error.
+type Deps struct {
+	// Now returns the current time (timer gates).
+	Now func() time.Time
+
+	// Runner runs gh, glab and cmd-gate commands.
+	Runner CommandRunner
+
+	// HTTPClient is used by http gates.
+	HTTPClient *http.Client
+
+	// RestrictHTTP limits http gates to URLs matching HTTPAllow (see
+	// HTTPAllowed); with an empty HTTPAllow the http evaluator is not
+	// registered. The daemon sets it so gates synced in from elsewhere
+	// cannot make it fetch arbitrary URLs unattended.
+	RestrictHTTP bool
+	HTTPAllow    []string
+
+	// WorkDir is the directory cmd gates run in and file gate paths are
+	// relative to (normally the repository root).
+	WorkDir string
+
+	// CommandTimeout bounds cmd gate commands (DefaultCommandTimeout if 0).
+	CommandTimeout time.Duration
+
+	// CommandAllow lists the cmd gate command lines that may run (see
+	// CommandAllowed). The cmd evaluator is only registered when it is
+	// non-empty: a gate's await_id comes from the issue, which may have been
+	// synced in from anywhere.
+	CommandAllow []string
+
+	// CheckBead reports whether a bead gate's target, given as its
+	// "<rig>:<bead-id>" await_id, is closed.
+	CheckBead func(ctx context.Context, awaitID string) (satisfied bool, reason string)
+
+	// StepStates returns the state of the steps in the molecule that
+	// contains gate, for formula-condition gates.
+	StepStates func(ctx context.Context, gate *types.Issue) (*formula.ConditionContext, error)
+}
+
+// DefaultCommandTimeout bounds cmd gate commands.
+const DefaultCommandTimeout = 30 * time.Second
+
+// NewDefaultRegistry returns a registry with the built-in evaluators:
+// timer, bead, gh:run, gh:pr, gitlab:mr, http, file and formula-condition,
+// plus cmd when deps.CommandAllow is set. http is left out when
+// deps.RestrictHTTP is set without an allowlist.
+func NewDefaultRegistry(deps Deps) *Registry {
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	if deps.Runner == nil {
+		deps.Runner = ExecRunner{}
+	}
+	if deps.HTTPClient == nil {
+		deps.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if deps.CommandTimeout <= 0 {
+		deps.CommandTimeout = DefaultCommandTimeout
+	}
+
+	r := NewRegistry()
+	r.Register(&TimerEvaluator{Now: deps.Now})
+	r.Register(&BeadEvaluator{Check: deps.CheckBead})
+	r.Register(&GitHubRunEvaluator{Runner: deps.Runner, Dir: deps.WorkDir})
+	r.Register(&GitHubPREvaluator{Runner: deps.Runner, Dir: deps.WorkDir})
+	r.Register(&GitLabMREvaluator{Runner: deps.Runner, Dir: deps.WorkDir})
+	switch {
+	case !deps.RestrictHTTP:
+		r.Register(&HTTPEvaluator{Client: deps.HTTPClient})
+	case len(deps.HTTPAllow) > 0:
+		r.Register(&HTTPEvaluator{Client: deps.HTTPClient, Allow: deps.HTTPAllow})
+	}
+	if len(deps.CommandAllow) > 0 {
+		r.Register(&CommandEvaluator{Runner: deps.Runner, Dir: deps.WorkDir, Timeout: deps.CommandTimeout, Allow: deps.CommandAllow})
+	}
+	r.Register(&FileEvaluator{Dir: deps.WorkDir})
+	r.Register(&FormulaConditionEvaluator{StepStates: deps.StepStates})
+	return r
+}
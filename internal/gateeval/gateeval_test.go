@@ -0,0 +1,97 @@
+package gateeval
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// fakeRunner answers commands from a table keyed by the joined command line
+// and records every call.
+type fakeRunner struct {
+	responses map[string]fakeResponse
+	calls     []string
+}
+
+type fakeResponse struct {
+	stdout, stderr string
+	err            error
+}
+
+func (f *fakeRunner) Run(_ context.Context, _ string, _ []string, name string, args ...string) ([]byte, []byte, error) {
+	line := strings.Join(append([]string{name}, args...), " ")
+	f.calls = append(f.calls, line)
+	resp, ok := f.responses[line]
+	if !ok {
+		return nil, []byte("unexpected command"), errors.New("exit status 1")
+	}
+	return []byte(resp.stdout), []byte(resp.stderr), resp.err
+}
+
+type staticEvaluator struct {
+	awaitType string
+	result    Result
+}
+
+func (s *staticEvaluator) Type() string { return s.awaitType }
+
+func (s *staticEvaluator) Evaluate(context.Context, *types.Issue) (Result, error) {
+	return s.result, nil
+}
+
+func TestRegistryLookup(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&staticEvaluator{awaitType: "gh:run"})
+	r.Register(&staticEvaluator{awaitType: "timer"})
+
+	for _, awaitType := range []string{"gh:run", "gh:run:deploy", "timer"} {
+		if _, ok := r.Lookup(awaitType); !ok {
+			t.Errorf("Lookup(%q) found nothing", awaitType)
+		}
+	}
+	for _, awaitType := range []string{"gh", "gh:pr", "human", "", "timer2"} {
+		if _, ok := r.Lookup(awaitType); ok {
+			t.Errorf("Lookup(%q) unexpectedly matched", awaitType)
+		}
+	}
+}
+
+func TestRegistryEvaluate(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&staticEvaluator{awaitType: "custom", result: Result{Resolved: true, Reason: "done"}})
+
+	res, ok, err := r.Evaluate(context.Background(), &types.Issue{AwaitType: "custom"})
+	if err != nil || !ok || !res.Resolved || res.Reason != "done" {
+		t.Errorf("Evaluate(custom) = %+v, %v, %v", res, ok, err)
+	}
+	if _, ok, _ := r.Evaluate(context.Background(), &types.Issue{AwaitType: "human"}); ok {
+		t.Error("human gates should have no evaluator")
+	}
+
+	// Registering the same type replaces the evaluator.
+	r.Register(&staticEvaluator{awaitType: "custom", result: Result{Reason: "replaced"}})
+	if res, _, _ := r.Evaluate(context.Background(), &types.Issue{AwaitType: "custom"}); res.Reason != "replaced" {
+		t.Errorf("Reason = %q after re-registering, want replaced", res.Reason)
+	}
+}
+
+func TestNewDefaultRegistryTypes(t *testing.T) {
+	want := []string{"bead", "file", "formula-condition", "gh:pr", "gh:run", "gitlab:mr", "http", "timer"}
+	if got := NewDefaultRegistry(Deps{}).Types(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Types() = %v, want %v", got, want)
+	}
+	// cmd gates run commands, so they need an allowlist
+	if _, ok := NewDefaultRegistry(Deps{CommandAllow: []string{"make test"}}).Lookup("cmd"); !ok {
+		t.Error("cmd evaluator not registered with an allowlist")
+	}
+	if _, ok := NewDefaultRegistry(Deps{RestrictHTTP: true}).Lookup("http"); ok {
+		t.Error("http evaluator registered despite RestrictHTTP without an allowlist")
+	}
+	if _, ok := NewDefaultRegistry(Deps{RestrictHTTP: true, HTTPAllow: []string{"https://ci.example.com/"}}).Lookup("http"); !ok {
+		t.Error("http evaluator not registered with an allowlist")
+	}
+}
@@ -0,0 +1,238 @@
+package gateeval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// maxHTTPBody bounds how much of a response an http gate reads.
+const maxHTTPBody = 1 << 20
+
+// HTTPEvaluator resolves "http" gates by polling a URL. The await_id is the
+// URL, optionally followed by a condition:
+//
+//	https://ci.example.com/health                   any 2xx status
+//	https://ci.example.com/build/42 status == 204   a specific status
+//	https://ci.example.com/build/42 $.state == 'done'
+//	https://ci.example.com/build/42 $.jobs[0].exit_code == 0
+//
+// JSON-path conditions use dotted fields and [n] indexes from the document
+// root and compare with ==, !=, <, <=, > or >= (numerically when both sides
+// are numbers).
+//
+// A non-nil Allow restricts the URLs that are fetched (see HTTPAllowed);
+// other URLs leave the gate pending.
+type HTTPEvaluator struct {
+	Client *http.Client
+	Allow  []string
+}
+
+// Type implements Evaluator.
+func (e *HTTPEvaluator) Type() string { return "http" }
+
+// Evaluate implements Evaluator.
+func (e *HTTPEvaluator) Evaluate(ctx context.Context, gate *types.Issue) (Result, error) {
+	url, condition, _ := strings.Cut(strings.TrimSpace(gate.AwaitID), " ")
+	if url == "" {
+		return Result{Reason: "no URL specified"}, nil
+	}
+	if e.Allow != nil && !HTTPAllowed(e.Allow, url) {
+		return Result{Reason: fmt.Sprintf("%s is not in the http gate allowlist (gate.http.allow)", url)}, nil
+	}
+	var cond *httpCondition
+	if condition = strings.TrimSpace(condition); condition != "" {
+		var err error
+		if cond, err = parseHTTPCondition(condition); err != nil {
+			return Result{Reason: err.Error()}, nil
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Result{Reason: fmt.Sprintf("invalid URL: %v", err)}, nil
+	}
+	req.Header.Set("Accept", "application/json")
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBody))
+	if err != nil {
+		return Result{}, fmt.Errorf("reading response: %w", err)
+	}
+
+	switch {
+	case cond == nil:
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return Result{Resolved: true, Reason: fmt.Sprintf("%s returned %d", url, resp.StatusCode)}, nil
+		}
+		return Result{Reason: fmt.Sprintf("%s returned %d", url, resp.StatusCode)}, nil
+
+	case cond.field == "status":
+		ok := compareValues(strconv.Itoa(resp.StatusCode), cond.op, cond.value)
+		return Result{Resolved: ok, Reason: fmt.Sprintf("status %d (want %s %s)", resp.StatusCode, cond.op, cond.value)}, nil
+
+	default:
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return Result{Reason: fmt.Sprintf("%s returned %d", url, resp.StatusCode)}, nil
+		}
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return Result{Reason: fmt.Sprintf("response is not JSON: %v", err)}, nil
+		}
+		actual, found := lookupJSONPath(doc, cond.field)
+		if !found {
+			return Result{Reason: fmt.Sprintf("%s not present in response", cond.field)}, nil
+		}
+		got := jsonScalar(actual)
+		ok := compareValues(got, cond.op, cond.value)
+		return Result{Resolved: ok, Reason: fmt.Sprintf("%s is %s (want %s %s)", cond.field, got, cond.op, cond.value)}, nil
+	}
+}
+
+// HTTPAllowed reports whether rawURL matches an entry of allow: same scheme
+// and host (including port), with the entry's path as a prefix of the URL's.
+func HTTPAllowed(allow []string, rawURL string) bool {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, entry := range allow {
+		a, err := neturl.Parse(entry)
+		if err != nil || a.Host == "" {
+			continue
+		}
+		if strings.EqualFold(a.Scheme, u.Scheme) && strings.EqualFold(a.Host, u.Host) && strings.HasPrefix(u.Path, a.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+type httpCondition struct {
+	field string // "status" or a "$." JSON path
+	op    string
+	value string
+}
+
+func parseHTTPCondition(s string) (*httpCondition, error) {
+	for _, op := range []string{"==", "!=", ">=", "<=", ">", "<"} {
+		lhs, rhs, ok := strings.Cut(s, op)
+		if !ok {
+			continue
+		}
+		field := strings.TrimSpace(lhs)
+		if field != "status" && field != "$" && !strings.HasPrefix(field, "$.") && !strings.HasPrefix(field, "$[") {
+			return nil, fmt.Errorf("invalid condition %q: left side must be status or a $. JSON path", s)
+		}
+		return &httpCondition{field: field, op: op, value: unquote(strings.TrimSpace(rhs))}, nil
+	}
+	return nil, fmt.Errorf("invalid condition %q: expected <status|$.path> <op> <value>", s)
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// lookupJSONPath resolves a path like $.a.b[2].c in a decoded JSON document.
+func lookupJSONPath(doc interface{}, path string) (interface{}, bool) {
+	rest := strings.TrimPrefix(path, "$")
+	cur := doc
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			obj, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if cur, ok = obj[rest[:end]]; !ok {
+				return nil, false
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, false
+			}
+			idx, err := strconv.Atoi(rest[1:end])
+			arr, ok := cur.([]interface{})
+			if err != nil || !ok || idx < 0 || idx >= len(arr) {
+				return nil, false
+			}
+			cur = arr[idx]
+			rest = rest[end+1:]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// jsonScalar formats a decoded JSON value for comparison.
+func jsonScalar(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		data, _ := json.Marshal(x)
+		return string(data)
+	}
+}
+
+// compareValues compares numerically when both sides parse as numbers and
+// as strings otherwise (only == and != are meaningful for strings).
+func compareValues(actual, op, expected string) bool {
+	a, aErr := strconv.ParseFloat(actual, 64)
+	b, bErr := strconv.ParseFloat(expected, 64)
+	if aErr == nil && bErr == nil {
+		switch op {
+		case "==":
+			return a == b
+		case "!=":
+			return a != b
+		case ">":
+			return a > b
+		case ">=":
+			return a >= b
+		case "<":
+			return a < b
+		case "<=":
+			return a <= b
+		}
+		return false
+	}
+	switch op {
+	case "==":
+		return actual == expected
+	case "!=":
+		return actual != expected
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package gateeval
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestHTTPEvaluator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/accepted":
+			w.WriteHeader(http.StatusAccepted)
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/build":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"state":"done","jobs":[{"exit_code":0},{"exit_code":3}],"progress":0.75}`))
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		awaitID      string
+		wantResolved bool
+	}{
+		{srv.URL + "/ok", true},
+		{srv.URL + "/down", false},
+		{srv.URL + "/accepted status == 202", true},
+		{srv.URL + "/ok status == 202", false},
+		{srv.URL + "/down status >= 500", true},
+		{srv.URL + "/build $.state == 'done'", true},
+		{srv.URL + `/build $.state == "running"`, false},
+		{srv.URL + "/build $.state != running", true},
+		{srv.URL + "/build $.jobs[0].exit_code == 0", true},
+		{srv.URL + "/build $.jobs[1].exit_code == 0", false},
+		{srv.URL + "/build $.jobs[5].exit_code == 0", false},
+		{srv.URL + "/build $.progress >= 0.5", true},
+		{srv.URL + "/down $.state == 'done'", false},
+		{srv.URL + "/build state == done", false}, // invalid condition
+	}
+
+	e := &HTTPEvaluator{Client: srv.Client()}
+	for _, tt := range tests {
+		res, err := e.Evaluate(context.Background(), &types.Issue{AwaitType: "http", AwaitID: tt.awaitID})
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.awaitID, err)
+			continue
+		}
+		if res.Resolved != tt.wantResolved {
+			t.Errorf("%s: resolved = %v (%s), want %v", tt.awaitID, res.Resolved, res.Reason, tt.wantResolved)
+		}
+	}
+}
+
+func TestHTTPEvaluatorUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	e := &HTTPEvaluator{Client: http.DefaultClient}
+	if _, err := e.Evaluate(context.Background(), &types.Issue{AwaitID: url}); err == nil {
+		t.Error("expected an error for an unreachable URL")
+	}
+}
+
+func TestHTTPEvaluatorAllow(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer srv.Close()
+
+	e := &HTTPEvaluator{Client: srv.Client(), Allow: []string{srv.URL + "/ci/"}}
+	if res, err := e.Evaluate(context.Background(), &types.Issue{AwaitID: srv.URL + "/ci/build/1"}); err != nil || !res.Resolved {
+		t.Errorf("allowed URL: %+v, %v", res, err)
+	}
+	if res, err := e.Evaluate(context.Background(), &types.Issue{AwaitID: srv.URL + "/admin"}); err != nil || res.Resolved {
+		t.Errorf("unlisted URL: %+v, %v", res, err)
+	}
+	if hits != 1 {
+		t.Errorf("server hit %d times, want 1 (unlisted URLs must not be fetched)", hits)
+	}
+}
+
+func TestHTTPAllowed(t *testing.T) {
+	allow := []string{"https://ci.example.com/builds/", "http://localhost:8080", "not a url"}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://ci.example.com/builds/42", true},
+		{"https://CI.example.com/builds/42", true},
+		{"https://ci.example.com/admin", false},
+		{"http://ci.example.com/builds/42", false},
+		{"https://ci.example.com.evil.test/builds/42", false},
+		{"http://localhost:8080/health", true},
+		{"http://localhost:9090/health", false},
+	}
+	for _, tt := range tests {
+		if got := HTTPAllowed(allow, tt.url); got != tt.want {
+			t.Errorf("HTTPAllowed(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
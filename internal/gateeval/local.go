@@ -0,0 +1,163 @@
+package gateeval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/formula"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// TimerEvaluator resolves "timer" gates once Timeout has passed since the
+// gate was created. Timers never escalate.
+type TimerEvaluator struct {
+	Now func() time.Time
+}
+
+// Type implements Evaluator.
+func (e *TimerEvaluator) Type() string { return "timer" }
+
+// Evaluate implements Evaluator.
+func (e *TimerEvaluator) Evaluate(_ context.Context, gate *types.Issue) (Result, error) {
+	if gate.Timeout == 0 {
+		return Result{Reason: "timer gate without timeout configured"}, fmt.Errorf("no timeout set")
+	}
+
+	now := time.Now()
+	if e.Now != nil {
+		now = e.Now()
+	}
+	expiresAt := gate.CreatedAt.Add(gate.Timeout)
+	if now.After(expiresAt) {
+		expired := now.Sub(expiresAt).Round(time.Second)
+		return Result{Resolved: true, Reason: fmt.Sprintf("timer expired %s ago", expired)}, nil
+	}
+	remaining := expiresAt.Sub(now).Round(time.Second)
+	return Result{Reason: fmt.Sprintf("expires in %s", remaining)}, nil
+}
+
+// BeadEvaluator resolves "bead" gates when the target bead, given as
+// "<rig>:<bead-id>", is closed.
+type BeadEvaluator struct {
+	Check func(ctx context.Context, awaitID string) (satisfied bool, reason string)
+}
+
+// Type implements Evaluator.
+func (e *BeadEvaluator) Type() string { return "bead" }
+
+// Evaluate implements Evaluator.
+func (e *BeadEvaluator) Evaluate(ctx context.Context, gate *types.Issue) (Result, error) {
+	if e.Check == nil {
+		return Result{}, errors.New("bead gates are not supported here")
+	}
+	satisfied, reason := e.Check(ctx, gate.AwaitID)
+	return Result{Resolved: satisfied, Reason: reason}, nil
+}
+
+// FileEvaluator resolves "file" gates when a path exists, or, with an
+// await_id of "<path> sha256:<hex>", when the file has that content hash.
+// Relative paths are resolved against Dir.
+type FileEvaluator struct {
+	Dir string
+}
+
+// Type implements Evaluator.
+func (e *FileEvaluator) Type() string { return "file" }
+
+// Evaluate implements Evaluator.
+func (e *FileEvaluator) Evaluate(_ context.Context, gate *types.Issue) (Result, error) {
+	fields := strings.Fields(gate.AwaitID)
+	if len(fields) == 0 || len(fields) > 2 {
+		return Result{Reason: `invalid await_id: expected "<path>" or "<path> sha256:<hex>"`}, nil
+	}
+	path := fields[0]
+	if !filepath.IsAbs(path) && e.Dir != "" {
+		path = filepath.Join(e.Dir, path)
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Result{Reason: fmt.Sprintf("%s does not exist yet", fields[0])}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	if len(fields) == 1 {
+		return Result{Resolved: true, Reason: fmt.Sprintf("%s exists", fields[0])}, nil
+	}
+
+	want, ok := strings.CutPrefix(fields[1], "sha256:")
+	if !ok {
+		return Result{Reason: fmt.Sprintf("unsupported hash %q (expected sha256:<hex>)", fields[1])}, nil
+	}
+	if info.IsDir() {
+		return Result{Reason: fmt.Sprintf("%s is a directory", fields[0])}, nil
+	}
+	got, err := fileSHA256(path)
+	if err != nil {
+		return Result{}, err
+	}
+	if strings.EqualFold(got, want) {
+		return Result{Resolved: true, Reason: fmt.Sprintf("%s matches sha256:%s", fields[0], shortHash(want))}, nil
+	}
+	return Result{Reason: fmt.Sprintf("%s has sha256:%s, waiting for sha256:%s", fields[0], shortHash(got), shortHash(want))}, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path) // #nosec G304 -- path comes from the gate definition
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
+
+// FormulaConditionEvaluator resolves "formula-condition" gates whose
+// await_id is a formula condition (e.g. "review.status == 'complete'" or
+// "children(build).all(status == 'complete')") evaluated against the
+// state of the steps in the gate's molecule.
+type FormulaConditionEvaluator struct {
+	StepStates func(ctx context.Context, gate *types.Issue) (*formula.ConditionContext, error)
+}
+
+// Type implements Evaluator.
+func (e *FormulaConditionEvaluator) Type() string { return "formula-condition" }
+
+// Evaluate implements Evaluator.
+func (e *FormulaConditionEvaluator) Evaluate(ctx context.Context, gate *types.Issue) (Result, error) {
+	if e.StepStates == nil {
+		return Result{}, errors.New("formula-condition gates are not supported here")
+	}
+	cond, err := formula.ParseCondition(gate.AwaitID)
+	if err != nil {
+		return Result{Reason: fmt.Sprintf("invalid condition: %v", err)}, nil
+	}
+	state, err := e.StepStates(ctx, gate)
+	if err != nil {
+		return Result{}, fmt.Errorf("loading molecule state: %w", err)
+	}
+	res, err := cond.Evaluate(state)
+	if err != nil {
+		return Result{Reason: fmt.Sprintf("condition error: %v", err)}, nil
+	}
+	return Result{Resolved: res.Satisfied, Reason: res.Reason}, nil
+}
@@ -0,0 +1,111 @@
+package gateeval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/formula"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestTimerEvaluator(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	gate := &types.Issue{AwaitType: "timer", CreatedAt: created, Timeout: time.Hour}
+
+	e := &TimerEvaluator{Now: func() time.Time { return created.Add(30 * time.Minute) }}
+	if res, err := e.Evaluate(context.Background(), gate); err != nil || res.Resolved || res.Reason != "expires in 30m0s" {
+		t.Errorf("before expiry: %+v, %v", res, err)
+	}
+	e.Now = func() time.Time { return created.Add(2 * time.Hour) }
+	if res, err := e.Evaluate(context.Background(), gate); err != nil || !res.Resolved || res.Escalated {
+		t.Errorf("after expiry: %+v, %v", res, err)
+	}
+	if _, err := e.Evaluate(context.Background(), &types.Issue{AwaitType: "timer"}); err == nil {
+		t.Error("expected an error for a timer without timeout")
+	}
+}
+
+func TestBeadEvaluator(t *testing.T) {
+	e := &BeadEvaluator{Check: func(_ context.Context, awaitID string) (bool, string) {
+		return awaitID == "rig:bd-1", "checked " + awaitID
+	}}
+	if res, err := e.Evaluate(context.Background(), &types.Issue{AwaitID: "rig:bd-1"}); err != nil || !res.Resolved || res.Reason != "checked rig:bd-1" {
+		t.Errorf("result = %+v, %v", res, err)
+	}
+	if _, err := (&BeadEvaluator{}).Evaluate(context.Background(), &types.Issue{}); err == nil {
+		t.Error("expected an error without a bead checker")
+	}
+}
+
+func TestFileEvaluator(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte("artifact\n")
+	if err := os.WriteFile(filepath.Join(dir, "out.txt"), content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		awaitID      string
+		wantResolved bool
+	}{
+		{"out.txt", true},
+		{filepath.Join(dir, "out.txt"), true},
+		{"missing.txt", false},
+		{"out.txt sha256:" + hash, true},
+		{"out.txt sha256:" + hash[:10] + "0000", false},
+		{"out.txt md5:abc", false},
+		{"", false},
+	}
+	e := &FileEvaluator{Dir: dir}
+	for _, tt := range tests {
+		res, err := e.Evaluate(context.Background(), &types.Issue{AwaitType: "file", AwaitID: tt.awaitID})
+		if err != nil || res.Resolved != tt.wantResolved {
+			t.Errorf("%q: result = %+v, err = %v, want resolved=%v", tt.awaitID, res, err, tt.wantResolved)
+		}
+	}
+}
+
+func TestFormulaConditionEvaluator(t *testing.T) {
+	state := &formula.ConditionContext{Steps: map[string]*formula.StepState{
+		"review": {ID: "review", Status: "complete"},
+		"build": {ID: "build", Status: "in_progress", Children: []*formula.StepState{
+			{ID: "lint", Status: "complete"},
+			{ID: "test", Status: "pending"},
+		}},
+	}}
+	e := &FormulaConditionEvaluator{StepStates: func(context.Context, *types.Issue) (*formula.ConditionContext, error) {
+		return state, nil
+	}}
+
+	tests := []struct {
+		condition    string
+		wantResolved bool
+	}{
+		{"review.status == 'complete'", true},
+		{"build.status == 'complete'", false},
+		{"children(build).any(status == 'complete')", true},
+		{"children(build).all(status == 'complete')", false},
+		{"not a condition", false},
+	}
+	for _, tt := range tests {
+		res, err := e.Evaluate(context.Background(), &types.Issue{AwaitType: "formula-condition", AwaitID: tt.condition})
+		if err != nil || res.Resolved != tt.wantResolved {
+			t.Errorf("%q: result = %+v, err = %v, want resolved=%v", tt.condition, res, err, tt.wantResolved)
+		}
+	}
+
+	failing := &FormulaConditionEvaluator{StepStates: func(context.Context, *types.Issue) (*formula.ConditionContext, error) {
+		return nil, errors.New("no molecule")
+	}}
+	if _, err := failing.Evaluate(context.Background(), &types.Issue{AwaitID: "review.status == 'complete'"}); err == nil {
+		t.Error("expected the state error to be returned")
+	}
+}
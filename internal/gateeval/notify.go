@@ -0,0 +1,91 @@
+package gateeval
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Wake tells one waiter that a gate has cleared.
+type Wake struct {
+	GateID string `json:"gate_id"`
+	Waiter string `json:"waiter"`
+	Reason string `json:"reason"`
+}
+
+// Waker delivers wake notifications to gate waiters.
+type Waker interface {
+	Wake(ctx context.Context, w Wake) error
+}
+
+// DefaultNotifyTargets is used when gate.notify is not configured.
+var DefaultNotifyTargets = []string{"gt"}
+
+// ParseWakers builds wakers from gate.notify entries:
+//
+//	gt               run "gt gate wake <gate> <waiter>"
+//	exec:<command>   run a command; {gate}, {waiter} and {reason} in its
+//	                 arguments are replaced
+//	webhook:<url>    POST the wake as JSON
+//	none             notify nobody
+func ParseWakers(targets []string, runner CommandRunner, client *http.Client) ([]Waker, error) {
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	var out []Waker
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		kind, arg, _ := strings.Cut(target, ":")
+		switch kind {
+		case "gt":
+			out = append(out, &CommandWaker{Runner: runner,
+				Argv: []string{"gt", "gate", "wake", "{gate}", "{waiter}"}})
+		case "exec":
+			argv, err := SplitCommandLine(arg)
+			if err != nil || len(argv) == 0 {
+				return nil, fmt.Errorf("invalid notify target %q: expected exec:<command>", target)
+			}
+			out = append(out, &CommandWaker{Runner: runner, Argv: argv})
+		case "webhook":
+			if !strings.HasPrefix(arg, "http://") && !strings.HasPrefix(arg, "https://") {
+				return nil, fmt.Errorf("invalid notify target %q: expected webhook:<http(s) URL>", target)
+			}
+			out = append(out, &WebhookWaker{URL: arg, Client: client})
+		case "none", "":
+		default:
+			return nil, fmt.Errorf("unknown notify target %q (valid: gt, exec:<command>, webhook:<url>, none)", target)
+		}
+	}
+	return out, nil
+}
+
+// CommandWaker runs a command for each waiter.
+type CommandWaker struct {
+	Runner CommandRunner
+	Argv   []string
+}
+
+// Wake implements Waker.
+func (c *CommandWaker) Wake(ctx context.Context, w Wake) error {
+	return runTemplate(ctx, c.Runner, c.Argv, strings.NewReplacer(
+		"{gate}", w.GateID,
+		"{waiter}", w.Waiter,
+		"{reason}", w.Reason,
+	))
+}
+
+// WebhookWaker POSTs each wake as JSON to URL.
+type WebhookWaker struct {
+	URL    string
+	Client *http.Client
+}
+
+// Wake implements Waker.
+func (h *WebhookWaker) Wake(ctx context.Context, w Wake) error {
+	return postJSON(ctx, h.Client, h.URL, w)
+}
@@ -0,0 +1,32 @@
+package gateeval
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"time"
+)
+
+// ExecRunner runs commands with os/exec. When ctx ends, the command's whole
+// process group is killed so that children of a timed-out cmd gate do not
+// outlive it.
+type ExecRunner struct{}
+
+// Run implements CommandRunner.
+func (ExecRunner) Run(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 -- commands come from gate definitions and escalation config
+	cmd.Dir = dir
+	cmd.Env = env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	configureProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return stdout.Bytes(), stderr.Bytes(), err
+}
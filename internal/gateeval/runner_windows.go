@@ -0,0 +1,14 @@
+//go:build windows
+
+package gateeval
+
+import "os/exec"
+
+func configureProcessGroup(*exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
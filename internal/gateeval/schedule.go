@@ -0,0 +1,228 @@
+package gateeval
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Backoff is how often a pending gate is re-evaluated: Initial after the
+// first check, doubling on every further pending check up to Max.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// After returns the delay before the next check once a gate has been found
+// pending attempts times (attempts >= 1).
+func (b Backoff) After(attempts int) time.Duration {
+	d := b.Initial
+	for i := 1; i < attempts && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+// DefaultBackoffs are the polling schedules for the built-in await types.
+// Types are matched exactly, then by family ("gh" for "gh:run"). Timer
+// gates are not polled: they are checked once, when they expire.
+var DefaultBackoffs = map[string]Backoff{
+	"gh":                {Initial: 30 * time.Second, Max: 5 * time.Minute},
+	"gitlab":            {Initial: 30 * time.Second, Max: 5 * time.Minute},
+	"http":              {Initial: 15 * time.Second, Max: 5 * time.Minute},
+	"cmd":               {Initial: time.Minute, Max: 10 * time.Minute},
+	"file":              {Initial: 10 * time.Second, Max: time.Minute},
+	"bead":              {Initial: time.Minute, Max: 10 * time.Minute},
+	"formula-condition": {Initial: time.Minute, Max: 10 * time.Minute},
+}
+
+// defaultBackoff is used for await types without an entry in DefaultBackoffs.
+var defaultBackoff = Backoff{Initial: time.Minute, Max: 10 * time.Minute}
+
+// BackoffFor returns the polling schedule for awaitType.
+func BackoffFor(awaitType string) Backoff {
+	if b, ok := DefaultBackoffs[awaitType]; ok {
+		return b
+	}
+	if family, _, ok := strings.Cut(awaitType, ":"); ok {
+		if b, ok := DefaultBackoffs[family]; ok {
+			return b
+		}
+	}
+	return defaultBackoff
+}
+
+// Transition is a change in a gate's state worth reporting.
+type Transition string
+
+// Gate transitions reported by Scheduler.Record.
+const (
+	TransitionNone      Transition = ""
+	TransitionResolved  Transition = "resolved"
+	TransitionEscalated Transition = "escalated"
+	TransitionTimedOut  Transition = "timed_out"
+)
+
+// Scheduler decides when each open gate is evaluated next. It keeps
+// per-gate backoff state in memory, so a restarted daemon checks every gate
+// once more and escalates each gate at most once per process. It is safe
+// for concurrent use.
+type Scheduler struct {
+	// Now returns the current time (time.Now if nil).
+	Now func() time.Time
+
+	// Backoff returns the polling schedule for an await type (BackoffFor
+	// if nil).
+	Backoff func(awaitType string) Backoff
+
+	mu    sync.Mutex
+	gates map[string]*gateSchedule
+}
+
+type gateSchedule struct {
+	awaitType string
+	awaitID   string
+	next      time.Time
+	attempts  int
+	escalated bool
+}
+
+// NewScheduler returns a scheduler with no gates.
+func NewScheduler() *Scheduler {
+	return &Scheduler{gates: make(map[string]*gateSchedule)}
+}
+
+func (s *Scheduler) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *Scheduler) backoff(awaitType string) Backoff {
+	if s.Backoff != nil {
+		return s.Backoff(awaitType)
+	}
+	return BackoffFor(awaitType)
+}
+
+// Due returns the gates in open that should be evaluated now. Gates seen
+// for the first time are due immediately, except timers, which are due
+// when they expire. Gates no longer in open are forgotten.
+func (s *Scheduler) Due(open []*types.Issue) []*types.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	seen := make(map[string]bool, len(open))
+	var due []*types.Issue
+	for _, gate := range open {
+		seen[gate.ID] = true
+		st, ok := s.gates[gate.ID]
+		if !ok || st.awaitType != gate.AwaitType || st.awaitID != gate.AwaitID {
+			st = &gateSchedule{awaitType: gate.AwaitType, awaitID: gate.AwaitID, next: now}
+			if gate.AwaitType == "timer" && gate.Timeout > 0 {
+				st.next = gate.CreatedAt.Add(gate.Timeout)
+			}
+			s.gates[gate.ID] = st
+		}
+		if !now.Before(st.next) {
+			due = append(due, gate)
+		}
+	}
+	for id := range s.gates {
+		if !seen[id] {
+			delete(s.gates, id)
+		}
+	}
+	return due
+}
+
+// Wake makes a gate due at the next call to Due.
+func (s *Scheduler) Wake(gateID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.gates[gateID]; ok {
+		st.next = time.Time{}
+	}
+}
+
+// WakeClosed makes every gate that may have been unblocked by closing
+// issueID due: bead gates awaiting it and all formula-condition gates. It
+// returns the number of gates woken.
+func (s *Scheduler) WakeClosed(issueID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	woken := 0
+	for _, st := range s.gates {
+		switch st.awaitType {
+		case "bead":
+			_, beadID, _ := strings.Cut(st.awaitID, ":")
+			if beadID != issueID {
+				continue
+			}
+		case "formula-condition":
+		default:
+			continue
+		}
+		st.next = time.Time{}
+		woken++
+	}
+	return woken
+}
+
+// Record stores the outcome of evaluating gate and schedules its next
+// check. It returns the transition to report, if any: a resolution, the
+// first escalation, or the first check after the gate's Timeout passed
+// without it resolving. Evaluation errors never escalate; the gate is
+// retried with backoff.
+func (s *Scheduler) Record(gate *types.Issue, res Result, err error) Transition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil && res.Resolved {
+		delete(s.gates, gate.ID)
+		return TransitionResolved
+	}
+
+	now := s.now()
+	st, ok := s.gates[gate.ID]
+	if !ok {
+		st = &gateSchedule{awaitType: gate.AwaitType, awaitID: gate.AwaitID}
+		s.gates[gate.ID] = st
+	}
+	if res.AwaitID != "" {
+		st.awaitID = res.AwaitID
+	}
+	st.attempts++
+	b := s.backoff(gate.AwaitType)
+	st.next = now.Add(b.After(st.attempts))
+
+	transition := TransitionNone
+	switch {
+	case err != nil:
+	case res.Escalated:
+		if !st.escalated {
+			st.escalated = true
+			transition = TransitionEscalated
+		}
+		st.next = now.Add(b.Max)
+	case gate.Timeout > 0 && gate.AwaitType != "timer":
+		deadline := gate.CreatedAt.Add(gate.Timeout)
+		if !now.Before(deadline) {
+			if !st.escalated {
+				st.escalated = true
+				transition = TransitionTimedOut
+			}
+		} else if deadline.Before(st.next) {
+			// Check again right at the deadline so the timeout is prompt
+			st.next = deadline
+		}
+	}
+	return transition
+}
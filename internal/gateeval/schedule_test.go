@@ -0,0 +1,157 @@
+package gateeval
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestBackoff(t *testing.T) {
+	b := Backoff{Initial: 10 * time.Second, Max: time.Minute}
+	for attempts, want := range map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 3: 40 * time.Second, 4: time.Minute, 10: time.Minute} {
+		if got := b.After(attempts); got != want {
+			t.Errorf("After(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+	if got := BackoffFor("gh:run"); got != DefaultBackoffs["gh"] {
+		t.Errorf("BackoffFor(gh:run) = %+v, want gh family", got)
+	}
+	if got := BackoffFor("custom"); got != defaultBackoff {
+		t.Errorf("BackoffFor(custom) = %+v, want default", got)
+	}
+}
+
+func newTestScheduler(now *time.Time) *Scheduler {
+	s := NewScheduler()
+	s.Now = func() time.Time { return *now }
+	s.Backoff = func(string) Backoff { return Backoff{Initial: time.Minute, Max: 4 * time.Minute} }
+	return s
+}
+
+func dueIDs(s *Scheduler, gates ...*types.Issue) []string {
+	var ids []string
+	for _, g := range s.Due(gates) {
+		ids = append(ids, g.ID)
+	}
+	return ids
+}
+
+func TestSchedulerBackoff(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(&now)
+	gate := &types.Issue{ID: "bd-g1", AwaitType: "gh:run", AwaitID: "1", CreatedAt: now}
+
+	if got := dueIDs(s, gate); len(got) != 1 {
+		t.Fatalf("new gate should be due immediately, got %v", got)
+	}
+	if tr := s.Record(gate, Result{Reason: "running"}, nil); tr != TransitionNone {
+		t.Errorf("pending transition = %q", tr)
+	}
+	if got := dueIDs(s, gate); len(got) != 0 {
+		t.Errorf("gate due again before backoff: %v", got)
+	}
+	now = now.Add(time.Minute)
+	if got := dueIDs(s, gate); len(got) != 1 {
+		t.Fatalf("gate should be due after 1m, got %v", got)
+	}
+	s.Record(gate, Result{}, errors.New("gh CLI not installed"))
+	now = now.Add(time.Minute)
+	if got := dueIDs(s, gate); len(got) != 0 {
+		t.Errorf("second retry should wait 2m, got due %v", got)
+	}
+	s.Wake(gate.ID)
+	if got := dueIDs(s, gate); len(got) != 1 {
+		t.Errorf("woken gate should be due, got %v", got)
+	}
+	if tr := s.Record(gate, Result{Resolved: true}, nil); tr != TransitionResolved {
+		t.Errorf("resolved transition = %q", tr)
+	}
+}
+
+func TestSchedulerTimer(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(&now)
+	timer := &types.Issue{ID: "bd-t1", AwaitType: "timer", Timeout: time.Hour, CreatedAt: now.Add(-30 * time.Minute)}
+
+	if got := dueIDs(s, timer); len(got) != 0 {
+		t.Errorf("timer should not be due before it expires, got %v", got)
+	}
+	now = now.Add(30 * time.Minute)
+	if got := dueIDs(s, timer); len(got) != 1 {
+		t.Errorf("timer should be due when it expires, got %v", got)
+	}
+}
+
+func TestSchedulerEscalatesOnce(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(&now)
+	gate := &types.Issue{ID: "bd-g1", AwaitType: "gh:pr", AwaitID: "7", CreatedAt: now}
+	s.Due([]*types.Issue{gate})
+
+	if tr := s.Record(gate, Result{Escalated: true}, nil); tr != TransitionEscalated {
+		t.Errorf("first escalation = %q", tr)
+	}
+	if tr := s.Record(gate, Result{Escalated: true}, nil); tr != TransitionNone {
+		t.Errorf("repeated escalation = %q, want none", tr)
+	}
+}
+
+func TestSchedulerTimeout(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(&now)
+	gate := &types.Issue{ID: "bd-g1", AwaitType: "http", AwaitID: "https://x", Timeout: 90 * time.Second, CreatedAt: now}
+	s.Due([]*types.Issue{gate})
+
+	s.Record(gate, Result{Reason: "503"}, nil)
+	now = now.Add(time.Minute)
+	s.Due([]*types.Issue{gate})
+	s.Record(gate, Result{Reason: "503"}, nil) // next check would be 2m out; capped at the deadline
+	now = now.Add(30 * time.Second)
+	if got := dueIDs(s, gate); len(got) != 1 {
+		t.Fatalf("gate should be due at its deadline, got %v", got)
+	}
+	if tr := s.Record(gate, Result{Reason: "503"}, nil); tr != TransitionTimedOut {
+		t.Errorf("transition at deadline = %q, want timed_out", tr)
+	}
+	if tr := s.Record(gate, Result{Reason: "503"}, nil); tr != TransitionNone {
+		t.Errorf("transition after timeout = %q, want none", tr)
+	}
+	if tr := s.Record(gate, Result{Resolved: true}, nil); tr != TransitionResolved {
+		t.Errorf("late resolution = %q, want resolved", tr)
+	}
+}
+
+func TestSchedulerWakeClosed(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(&now)
+	bead := &types.Issue{ID: "bd-g1", AwaitType: "bead", AwaitID: "rig:bd-42"}
+	other := &types.Issue{ID: "bd-g2", AwaitType: "bead", AwaitID: "rig:bd-43"}
+	cond := &types.Issue{ID: "bd-g3", AwaitType: "formula-condition", AwaitID: "review.status == 'complete'"}
+	gh := &types.Issue{ID: "bd-g4", AwaitType: "gh:run", AwaitID: "1"}
+	gates := []*types.Issue{bead, other, cond, gh}
+	for _, g := range s.Due(gates) {
+		s.Record(g, Result{}, nil)
+	}
+
+	if n := s.WakeClosed("bd-42"); n != 2 {
+		t.Errorf("WakeClosed woke %d gates, want 2", n)
+	}
+	got := dueIDs(s, gates...)
+	if len(got) != 2 || got[0] != "bd-g1" || got[1] != "bd-g3" {
+		t.Errorf("due after close = %v, want [bd-g1 bd-g3]", got)
+	}
+}
+
+func TestSchedulerForgetsClosedGates(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestScheduler(&now)
+	gate := &types.Issue{ID: "bd-g1", AwaitType: "file", AwaitID: "ready"}
+	s.Due([]*types.Issue{gate})
+	s.Record(gate, Result{}, nil)
+	s.Due(nil)
+	if got := dueIDs(s, gate); len(got) != 1 {
+		t.Errorf("reopened gate should start fresh, got %v", got)
+	}
+}
@@ -12,16 +12,18 @@ import (
 
 // Event types
 const (
-	EventCreate = "create"
-	EventUpdate = "update"
-	EventClose  = "close"
+	EventCreate    = "create"
+	EventUpdate    = "update"
+	EventClose     = "close"
+	EventSLABreach = "sla_breach" // An issue missed an SLA target (see internal/sla)
 )
 
 // Hook file names
 const (
-	HookOnCreate = "on_create"
-	HookOnUpdate = "on_update"
-	HookOnClose  = "on_close"
+	HookOnCreate    = "on_create"
+	HookOnUpdate    = "on_update"
+	HookOnClose     = "on_close"
+	HookOnSLABreach = "on_sla_breach"
 )
 
 // Runner handles hook execution
@@ -118,6 +120,8 @@ func eventToHook(event string) string {
 		return HookOnUpdate
 	case EventClose:
 		return HookOnClose
+	case EventSLABreach:
+		return HookOnSLABreach
 	default:
 		return ""
 	}
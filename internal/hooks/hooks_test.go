@@ -44,6 +44,7 @@ func TestEventToHook(t *testing.T) {
 		{EventCreate, HookOnCreate},
 		{EventUpdate, HookOnUpdate},
 		{EventClose, HookOnClose},
+		{EventSLABreach, HookOnSLABreach},
 		{"unknown", ""},
 		{"", ""},
 	}
@@ -380,6 +381,7 @@ func TestAllHookEvents(t *testing.T) {
 		{EventCreate, HookOnCreate},
 		{EventUpdate, HookOnUpdate},
 		{EventClose, HookOnClose},
+		{EventSLABreach, HookOnSLABreach},
 	}
 
 	for _, e := range events {
@@ -0,0 +1,65 @@
+package importer
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// FormatUpgrade rewrites a single decoded JSONL record from one format
+// version to the next. Upgrades operate on the raw map so they can handle
+// fields that no longer exist on types.Issue.
+type FormatUpgrade func(record map[string]interface{}) error
+
+// formatUpgrades maps a format version to the function that upgrades a
+// record from that version to the next one. Every version below
+// types.JSONLFormatVersion must have an entry.
+var formatUpgrades = map[int]FormatUpgrade{
+	1: upgradeV1ToV2,
+}
+
+// UpgradeRecord upgrades a decoded JSONL record written with format
+// version from to the current format, applying each step in order.
+// Records that are already current are left unchanged.
+func UpgradeRecord(record map[string]interface{}, from int) error {
+	if from > types.JSONLFormatVersion {
+		return fmt.Errorf("JSONL format version %d is newer than this bd supports (%d); upgrade bd", from, types.JSONLFormatVersion)
+	}
+	if from < 1 {
+		from = 1
+	}
+	for v := from; v < types.JSONLFormatVersion; v++ {
+		upgrade, ok := formatUpgrades[v]
+		if !ok {
+			return fmt.Errorf("no upgrade from JSONL format version %d to %d", v, v+1)
+		}
+		if err := upgrade(record); err != nil {
+			return fmt.Errorf("upgrading JSONL format %d -> %d: %w", v, v+1, err)
+		}
+	}
+	return nil
+}
+
+// upgradeV1ToV2 fills in fields that unversioned exports could leave out of
+// dependency records: the owning issue_id and the dependency type (which
+// defaulted to blocks).
+func upgradeV1ToV2(record map[string]interface{}) error {
+	deps, ok := record["dependencies"].([]interface{})
+	if !ok {
+		return nil
+	}
+	issueID, _ := record["id"].(string)
+	for _, d := range deps {
+		dep, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, _ := dep["issue_id"].(string); id == "" && issueID != "" {
+			dep["issue_id"] = issueID
+		}
+		if t, _ := dep["type"].(string); t == "" {
+			dep["type"] = string(types.DepBlocks)
+		}
+	}
+	return nil
+}
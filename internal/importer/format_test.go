@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestUpgradeRecord(t *testing.T) {
+	record := map[string]interface{}{
+		"id": "bd-1",
+		"dependencies": []interface{}{
+			map[string]interface{}{"depends_on_id": "bd-2"},
+			map[string]interface{}{"issue_id": "bd-1", "depends_on_id": "bd-3", "type": "related"},
+		},
+	}
+
+	if err := UpgradeRecord(record, 1); err != nil {
+		t.Fatalf("UpgradeRecord failed: %v", err)
+	}
+
+	deps := record["dependencies"].([]interface{})
+	first := deps[0].(map[string]interface{})
+	if first["issue_id"] != "bd-1" || first["type"] != "blocks" {
+		t.Errorf("v1 dependency not upgraded: %v", first)
+	}
+	second := deps[1].(map[string]interface{})
+	if second["type"] != "related" {
+		t.Errorf("explicit dependency type was overwritten: %v", second)
+	}
+}
+
+func TestUpgradeRecordCurrentAndFuture(t *testing.T) {
+	record := map[string]interface{}{
+		"id":           "bd-1",
+		"dependencies": []interface{}{map[string]interface{}{"depends_on_id": "bd-2"}},
+	}
+	if err := UpgradeRecord(record, types.JSONLFormatVersion); err != nil {
+		t.Fatalf("UpgradeRecord failed: %v", err)
+	}
+	if _, ok := record["dependencies"].([]interface{})[0].(map[string]interface{})["type"]; ok {
+		t.Errorf("current-format record should not be modified")
+	}
+
+	err := UpgradeRecord(record, types.JSONLFormatVersion+1)
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("expected error for future format version, got %v", err)
+	}
+}
+
+func TestFormatUpgradesComplete(t *testing.T) {
+	for v := 1; v < types.JSONLFormatVersion; v++ {
+		if _, ok := formatUpgrades[v]; !ok {
+			t.Errorf("missing upgrade from format version %d", v)
+		}
+	}
+}
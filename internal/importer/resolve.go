@@ -0,0 +1,339 @@
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// ConflictSide names one version of an issue in a three-way comparison.
+type ConflictSide string
+
+const (
+	SideBase     ConflictSide = "base"     // Common ancestor (from git)
+	SideLocal    ConflictSide = "local"    // Version in the database
+	SideIncoming ConflictSide = "incoming" // Version being imported
+)
+
+// IsValid reports whether s is a known side.
+func (s ConflictSide) IsValid() bool {
+	return s == SideBase || s == SideLocal || s == SideIncoming
+}
+
+// conflictField describes one field that can be resolved independently.
+// The set matches the fields the importer updates (and that
+// sqlite.DetectCollisions compares).
+type conflictField struct {
+	name string
+	get  func(*types.Issue) string
+	set  func(dst, src *types.Issue)
+}
+
+var conflictFields = []conflictField{
+	{"title", func(i *types.Issue) string { return i.Title }, func(d, s *types.Issue) { d.Title = s.Title }},
+	{"description", func(i *types.Issue) string { return i.Description }, func(d, s *types.Issue) { d.Description = s.Description }},
+	{"design", func(i *types.Issue) string { return i.Design }, func(d, s *types.Issue) { d.Design = s.Design }},
+	{"acceptance_criteria", func(i *types.Issue) string { return i.AcceptanceCriteria }, func(d, s *types.Issue) { d.AcceptanceCriteria = s.AcceptanceCriteria }},
+	{"notes", func(i *types.Issue) string { return i.Notes }, func(d, s *types.Issue) { d.Notes = s.Notes }},
+	{"status", func(i *types.Issue) string { return string(i.Status) }, func(d, s *types.Issue) {
+		d.Status = s.Status
+		d.ClosedAt = s.ClosedAt
+		d.CloseReason = s.CloseReason
+	}},
+	{"priority", func(i *types.Issue) string { return strconv.Itoa(i.Priority) }, func(d, s *types.Issue) { d.Priority = s.Priority }},
+	{"issue_type", func(i *types.Issue) string { return string(i.IssueType) }, func(d, s *types.Issue) { d.IssueType = s.IssueType }},
+	{"assignee", func(i *types.Issue) string { return i.Assignee }, func(d, s *types.Issue) { d.Assignee = s.Assignee }},
+	{"external_ref", func(i *types.Issue) string {
+		if i.ExternalRef == nil {
+			return ""
+		}
+		return *i.ExternalRef
+	}, func(d, s *types.Issue) { d.ExternalRef = s.ExternalRef }},
+}
+
+// ConflictFieldNames returns the names of the fields that can be resolved
+// individually, in display order.
+func ConflictFieldNames() []string {
+	names := make([]string, len(conflictFields))
+	for i, f := range conflictFields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// FieldConflict is one field changed differently on both sides.
+type FieldConflict struct {
+	Field    string
+	Base     string
+	Local    string
+	Incoming string
+	HasBase  bool // false if the issue is not in the base version
+}
+
+// IssueConflict is an issue whose local and incoming versions disagree on
+// at least one field that both sides changed.
+type IssueConflict struct {
+	ID       string
+	Base     *types.Issue // nil if unknown
+	Local    *types.Issue
+	Incoming *types.Issue
+	Fields   []FieldConflict
+}
+
+// DiffIssueFields returns the fields where local and incoming disagree and
+// neither side simply kept the base value. A change made on only one side
+// is not a conflict: the normal import rules handle it. If base is nil,
+// every differing field is a conflict.
+func DiffIssueFields(base, local, incoming *types.Issue) []FieldConflict {
+	var conflicts []FieldConflict
+	for _, f := range conflictFields {
+		l, in := f.get(local), f.get(incoming)
+		if l == in {
+			continue
+		}
+		fc := FieldConflict{Field: f.name, Local: l, Incoming: in}
+		if base != nil {
+			b := f.get(base)
+			if b == l || b == in {
+				continue
+			}
+			fc.Base = b
+			fc.HasBase = true
+		}
+		conflicts = append(conflicts, fc)
+	}
+	return conflicts
+}
+
+// FindConflicts compares incoming issues with the database and returns the
+// ones that conflict field-by-field. base maps issue IDs to their common
+// ancestor version (may be nil or incomplete).
+func FindConflicts(ctx context.Context, store storage.Storage, incoming []*types.Issue, base map[string]*types.Issue) ([]*IssueConflict, error) {
+	dbIssues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DB issues: %w", err)
+	}
+	dbByID := buildIDMap(dbIssues)
+
+	var conflicts []*IssueConflict
+	for _, in := range incoming {
+		local, found := dbByID[in.ID]
+		if !found {
+			continue
+		}
+		b := base[in.ID]
+		fields := DiffIssueFields(b, local, in)
+		if len(fields) == 0 {
+			continue
+		}
+		conflicts = append(conflicts, &IssueConflict{ID: in.ID, Base: b, Local: local, Incoming: in, Fields: fields})
+	}
+	return conflicts, nil
+}
+
+// Resolve merges the local version into the incoming issue in place, so the
+// regular import writes the resolved version. Fields that conflict take the
+// chosen side, or without a choice the newer version (the usual import
+// rule). Other fields are merged three-way: a field only local changed
+// keeps the local value. The result carries the newer of the two
+// updated_at values; if that would not be newer than the local issue, it is
+// moved just past it so the import doesn't discard the merge as stale.
+func (c *IssueConflict) Resolve(choices map[string]ConflictSide) {
+	incomingNewer := c.Incoming.UpdatedAt.After(c.Local.UpdatedAt)
+	conflicting := make(map[string]bool, len(c.Fields))
+	for _, fc := range c.Fields {
+		conflicting[fc.Field] = true
+	}
+
+	for _, f := range conflictFields {
+		var choice ConflictSide
+		switch {
+		case conflicting[f.name]:
+			choice = choices[f.name]
+			if choice == "" && !incomingNewer {
+				choice = SideLocal
+			}
+		case c.Base != nil && f.get(c.Incoming) == f.get(c.Base):
+			// Unchanged incoming; any local edit wins
+			choice = SideLocal
+		}
+		var src *types.Issue
+		switch choice {
+		case SideLocal:
+			src = c.Local
+		case SideBase:
+			src = c.Base
+		}
+		if src != nil {
+			f.set(c.Incoming, src)
+		}
+	}
+
+	if !incomingNewer {
+		c.Incoming.UpdatedAt = c.Local.UpdatedAt
+	}
+	if len(DiffIssueFields(nil, c.Local, c.Incoming)) == 0 {
+		// Nothing to write; let timestamps decide the rest
+		return
+	}
+	if !c.Incoming.UpdatedAt.After(c.Local.UpdatedAt) {
+		c.Incoming.UpdatedAt = c.Local.UpdatedAt.Add(time.Millisecond)
+	}
+}
+
+// Resolution records how to resolve a conflict. An empty Field (or "*")
+// applies to every field of the issue, and an IssueID of "*" applies to
+// every issue. Local and Incoming, if set, are hashes of the two values;
+// recorded resolutions use them so a later, different conflict on the same
+// field is asked about again. Hand-written strategy files usually omit them.
+type Resolution struct {
+	IssueID  string       `json:"issue_id" yaml:"issue_id"`
+	Field    string       `json:"field,omitempty" yaml:"field,omitempty"`
+	Choice   ConflictSide `json:"choice" yaml:"choice"`
+	Local    string       `json:"local,omitempty" yaml:"local,omitempty"`
+	Incoming string       `json:"incoming,omitempty" yaml:"incoming,omitempty"`
+}
+
+func (r Resolution) matches(issueID string, fc FieldConflict) bool {
+	if r.IssueID != "*" && r.IssueID != issueID {
+		return false
+	}
+	if r.Field != "" && r.Field != "*" && r.Field != fc.Field {
+		return false
+	}
+	if r.Local != "" && r.Local != valueHash(fc.Local) {
+		return false
+	}
+	if r.Incoming != "" && r.Incoming != valueHash(fc.Incoming) {
+		return false
+	}
+	return true
+}
+
+// specificity ranks matching resolutions: exact issue beats "*", and a
+// named field beats the whole-issue form.
+func (r Resolution) specificity() int {
+	score := 0
+	if r.IssueID != "*" {
+		score += 2
+	}
+	if r.Field != "" && r.Field != "*" {
+		score++
+	}
+	return score
+}
+
+// ResolutionSet is a list of conflict resolutions, used both for choices
+// recorded during interactive imports and for --strategy-file.
+type ResolutionSet struct {
+	Default     ConflictSide `json:"default,omitempty" yaml:"default,omitempty"`
+	Resolutions []Resolution `json:"resolutions" yaml:"resolutions"`
+}
+
+// LoadResolutionSet reads a resolution set from a JSON or YAML file.
+// A missing file yields an empty set.
+func LoadResolutionSet(path string) (*ResolutionSet, error) {
+	// #nosec G304 - path is a user-provided strategy file or .beads/ path
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &ResolutionSet{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var set ResolutionSet
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yaml" || ext == ".yml" {
+		err = yaml.Unmarshal(data, &set)
+	} else {
+		err = json.Unmarshal(data, &set)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if set.Default != "" && !set.Default.IsValid() {
+		return nil, fmt.Errorf("%s: invalid default %q (must be local, incoming, or base)", path, set.Default)
+	}
+	for i, r := range set.Resolutions {
+		if r.IssueID == "" {
+			return nil, fmt.Errorf("%s: resolution %d has no issue_id", path, i+1)
+		}
+		if !r.Choice.IsValid() {
+			return nil, fmt.Errorf("%s: resolution %d has invalid choice %q (must be local, incoming, or base)", path, i+1, r.Choice)
+		}
+	}
+	return &set, nil
+}
+
+// Save writes the set as indented JSON.
+func (s *ResolutionSet) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal resolutions: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Choice returns the resolution for one field conflict: the most specific
+// matching entry, then the set's default. Choosing base when the issue has
+// no base version is treated as no choice.
+func (s *ResolutionSet) Choice(issueID string, fc FieldConflict) (ConflictSide, bool) {
+	if s == nil {
+		return "", false
+	}
+	best := -1
+	var choice ConflictSide
+	for _, r := range s.Resolutions {
+		if r.matches(issueID, fc) && r.specificity() > best {
+			best = r.specificity()
+			choice = r.Choice
+		}
+	}
+	if best < 0 {
+		choice = s.Default
+	}
+	if choice == "" || (choice == SideBase && !fc.HasBase) {
+		return "", false
+	}
+	return choice, true
+}
+
+// Record stores a choice for exactly this conflict, replacing any earlier
+// recorded choice for the same issue and field.
+func (s *ResolutionSet) Record(issueID string, fc FieldConflict, choice ConflictSide) {
+	r := Resolution{
+		IssueID:  issueID,
+		Field:    fc.Field,
+		Choice:   choice,
+		Local:    valueHash(fc.Local),
+		Incoming: valueHash(fc.Incoming),
+	}
+	for i, existing := range s.Resolutions {
+		if existing.IssueID == issueID && existing.Field == fc.Field {
+			s.Resolutions[i] = r
+			return
+		}
+	}
+	s.Resolutions = append(s.Resolutions, r)
+}
+
+// valueHash is a short, stable fingerprint of a field value.
+func valueHash(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return fmt.Sprintf("%x", sum[:8])
+}
@@ -0,0 +1,207 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestDiffIssueFields(t *testing.T) {
+	base := &types.Issue{Title: "Base", Description: "same", Priority: 2, Notes: "base notes"}
+	local := &types.Issue{Title: "Local", Description: "same", Priority: 1, Notes: "base notes"}
+	incoming := &types.Issue{Title: "Incoming", Description: "same", Priority: 2, Notes: "new notes"}
+
+	// Only title changed on both sides; priority and notes changed on one side each
+	got := DiffIssueFields(base, local, incoming)
+	if len(got) != 1 || got[0].Field != "title" || !got[0].HasBase || got[0].Base != "Base" {
+		t.Fatalf("three-way diff = %+v, want only title", got)
+	}
+
+	// Without a base every difference is a conflict
+	got = DiffIssueFields(nil, local, incoming)
+	if len(got) != 3 {
+		t.Fatalf("two-way diff = %+v, want title, notes, priority", got)
+	}
+}
+
+func TestResolutionSetChoice(t *testing.T) {
+	title := FieldConflict{Field: "title", Local: "L", Incoming: "I"}
+	notes := FieldConflict{Field: "notes", Local: "L", Incoming: "I"}
+
+	set := &ResolutionSet{
+		Default: SideIncoming,
+		Resolutions: []Resolution{
+			{IssueID: "*", Field: "notes", Choice: SideLocal},
+			{IssueID: "bd-1", Choice: SideLocal},
+			{IssueID: "bd-1", Field: "notes", Choice: SideIncoming},
+		},
+	}
+
+	tests := []struct {
+		issue string
+		fc    FieldConflict
+		want  ConflictSide
+	}{
+		{"bd-1", title, SideLocal},    // whole-issue entry
+		{"bd-1", notes, SideIncoming}, // field entry beats whole-issue entry
+		{"bd-2", notes, SideLocal},    // wildcard issue
+		{"bd-2", title, SideIncoming}, // default
+	}
+	for _, tt := range tests {
+		got, ok := set.Choice(tt.issue, tt.fc)
+		if !ok || got != tt.want {
+			t.Errorf("Choice(%s, %s) = %q, %v; want %q", tt.issue, tt.fc.Field, got, ok, tt.want)
+		}
+	}
+
+	// Base can't be chosen when there is no base version
+	baseSet := &ResolutionSet{Default: SideBase}
+	if _, ok := baseSet.Choice("bd-1", title); ok {
+		t.Errorf("expected no choice for base without a base version")
+	}
+}
+
+func TestResolutionSetRecord(t *testing.T) {
+	set := &ResolutionSet{}
+	fc := FieldConflict{Field: "title", Local: "L", Incoming: "I"}
+	set.Record("bd-1", fc, SideLocal)
+	set.Record("bd-1", fc, SideIncoming)
+	if len(set.Resolutions) != 1 {
+		t.Fatalf("expected re-recording to replace the entry, got %d", len(set.Resolutions))
+	}
+	if got, ok := set.Choice("bd-1", fc); !ok || got != SideIncoming {
+		t.Errorf("Choice = %q, %v; want incoming", got, ok)
+	}
+
+	// A different conflict on the same field is not covered by the recording
+	changed := FieldConflict{Field: "title", Local: "L", Incoming: "I2"}
+	if _, ok := set.Choice("bd-1", changed); ok {
+		t.Errorf("recorded choice should not apply to a different conflict")
+	}
+
+	// Round trip through a file
+	path := filepath.Join(t.TempDir(), "resolutions.json")
+	if err := set.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := LoadResolutionSet(path)
+	if err != nil {
+		t.Fatalf("LoadResolutionSet failed: %v", err)
+	}
+	if got, ok := loaded.Choice("bd-1", fc); !ok || got != SideIncoming {
+		t.Errorf("loaded Choice = %q, %v; want incoming", got, ok)
+	}
+}
+
+func TestLoadResolutionSetYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategy.yaml")
+	content := "default: local\nresolutions:\n  - issue_id: bd-1\n    field: title\n    choice: incoming\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	set, err := LoadResolutionSet(path)
+	if err != nil {
+		t.Fatalf("LoadResolutionSet failed: %v", err)
+	}
+	if set.Default != SideLocal || len(set.Resolutions) != 1 {
+		t.Errorf("unexpected set: %+v", set)
+	}
+
+	if err := os.WriteFile(path, []byte("resolutions:\n  - issue_id: bd-1\n    choice: theirs\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadResolutionSet(path); err == nil {
+		t.Errorf("expected error for invalid choice")
+	}
+}
+
+func TestIssueConflictResolveMergesThreeWay(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := &types.Issue{ID: "bd-1", Title: "A", Description: "base", Notes: "base", Priority: 2, UpdatedAt: t0}
+	local := &types.Issue{ID: "bd-1", Title: "B-local-edit", Description: "local", Notes: "base", Priority: 2, UpdatedAt: t0.Add(2 * time.Hour)}
+	incoming := &types.Issue{ID: "bd-1", Title: "A", Description: "incoming", Notes: "incoming", Priority: 1, UpdatedAt: t0.Add(time.Hour)}
+
+	c := &IssueConflict{ID: "bd-1", Base: base, Local: local, Incoming: incoming, Fields: DiffIssueFields(base, local, incoming)}
+	if len(c.Fields) != 1 || c.Fields[0].Field != "description" {
+		t.Fatalf("conflicting fields = %+v, want only description", c.Fields)
+	}
+	c.Resolve(map[string]ConflictSide{"description": SideIncoming})
+
+	// Fields only one side changed keep that side's change
+	if incoming.Title != "B-local-edit" {
+		t.Errorf("title = %q, want the local edit", incoming.Title)
+	}
+	if incoming.Notes != "incoming" || incoming.Priority != 1 {
+		t.Errorf("notes/priority = %q/%d, want the incoming edits", incoming.Notes, incoming.Priority)
+	}
+	// The choice applies to the conflicting field
+	if incoming.Description != "incoming" {
+		t.Errorf("description = %q, want the chosen incoming value", incoming.Description)
+	}
+	// Local is newer: the result moves just past it, not to the current time
+	if want := local.UpdatedAt.Add(time.Millisecond); !incoming.UpdatedAt.Equal(want) {
+		t.Errorf("updated_at = %v, want %v", incoming.UpdatedAt, want)
+	}
+
+	// A newer incoming keeps its own timestamp
+	incoming = &types.Issue{ID: "bd-1", Title: "A", Description: "incoming", UpdatedAt: t0.Add(3 * time.Hour)}
+	c = &IssueConflict{ID: "bd-1", Base: base, Local: local, Incoming: incoming, Fields: DiffIssueFields(base, local, incoming)}
+	c.Resolve(nil)
+	if !incoming.UpdatedAt.Equal(t0.Add(3*time.Hour)) || incoming.Title != "B-local-edit" {
+		t.Errorf("resolved = %q at %v, want local title at the incoming time", incoming.Title, incoming.UpdatedAt)
+	}
+}
+
+func TestResolveConflictsThenImport(t *testing.T) {
+	ctx := context.Background()
+	tmpDB := t.TempDir() + "/test.db"
+	store, err := sqlite.New(ctx, tmpDB)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("Failed to set prefix: %v", err)
+	}
+
+	local := &types.Issue{
+		ID: "test-abc", Title: "Local title", Description: "Local desc",
+		Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask,
+	}
+	if err := store.CreateIssue(ctx, local, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// Incoming is older, so without resolution the import would keep local
+	incoming := &types.Issue{
+		ID: "test-abc", Title: "Incoming title", Description: "Incoming desc",
+		Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask,
+		CreatedAt: time.Now().Add(-time.Hour), UpdatedAt: time.Now().Add(-time.Hour),
+	}
+
+	conflicts, err := FindConflicts(ctx, store, []*types.Issue{incoming}, nil)
+	if err != nil {
+		t.Fatalf("FindConflicts failed: %v", err)
+	}
+	if len(conflicts) != 1 || len(conflicts[0].Fields) != 2 {
+		t.Fatalf("expected 1 conflict with 2 fields, got %+v", conflicts)
+	}
+
+	conflicts[0].Resolve(map[string]ConflictSide{"title": SideIncoming, "description": SideLocal})
+
+	if _, err := ImportIssues(ctx, tmpDB, store, []*types.Issue{incoming}, Options{}); err != nil {
+		t.Fatalf("ImportIssues failed: %v", err)
+	}
+	got, err := store.GetIssue(ctx, "test-abc")
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Title != "Incoming title" || got.Description != "Local desc" {
+		t.Errorf("resolved issue = %q/%q, want incoming title and local description", got.Title, got.Description)
+	}
+}
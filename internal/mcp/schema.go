@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// InputSchema derives a tool input schema from an argument struct such as
+// an rpc *Args type, so tool parameters always match what the operation
+// accepts. Each JSON-serialized field becomes a property; fields that are
+// neither omitempty nor pointers are required. Nested structs are inlined
+// because not every client resolves $ref. descriptions, keyed by JSON
+// name, document individual properties; fields named in omit are left out
+// (for arguments the server fills in itself).
+func InputSchema(args interface{}, descriptions map[string]string, omit ...string) map[string]interface{} {
+	t := reflect.TypeOf(args)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	s := objectSchema(t, make(map[reflect.Type]bool))
+	props := s["properties"].(map[string]interface{})
+	for _, name := range omit {
+		delete(props, name)
+		removeRequired(s, name)
+	}
+	for name, desc := range descriptions {
+		if prop, ok := props[name].(map[string]interface{}); ok {
+			prop["description"] = desc
+		}
+	}
+	return s
+}
+
+func schemaFor(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(time.Duration(0)):
+		return map[string]interface{}{"type": "integer", "description": "Duration in nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem(), seen)
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]interface{}{"type": "object"}
+		}
+		return objectSchema(t, seen)
+	}
+	return map[string]interface{}{}
+}
+
+func objectSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	seen[t] = true
+	defer delete(seen, t)
+
+	props := make(map[string]interface{})
+	var required []string
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("json") == "" {
+				addFields(f.Type)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			name, omitEmpty := jsonField(f)
+			if name == "" {
+				continue
+			}
+			props[name] = schemaFor(f.Type, seen)
+			if !omitEmpty && f.Type.Kind() != reflect.Ptr {
+				required = append(required, name)
+			}
+		}
+	}
+	addFields(t)
+
+	s := map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+// jsonField returns the JSON key for a struct field ("" if the field is not
+// serialized) and whether it is tagged omitempty.
+func jsonField(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	omitEmpty := false
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty
+}
+
+// applyDefaults records defaults in a schema and makes those properties
+// optional.
+func applyDefaults(schema map[string]interface{}, defaults map[string]interface{}) {
+	props, _ := schema["properties"].(map[string]interface{})
+	for name, value := range defaults {
+		if prop, ok := props[name].(map[string]interface{}); ok {
+			prop["default"] = value
+		}
+		removeRequired(schema, name)
+	}
+}
+
+func removeRequired(schema map[string]interface{}, name string) {
+	required, _ := schema["required"].([]string)
+	kept := required[:0]
+	for _, r := range required {
+		if r != name {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == 0 {
+		delete(schema, "required")
+	} else {
+		schema["required"] = kept
+	}
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type nested struct {
+	Name string `json:"name"`
+}
+
+type schemaArgs struct {
+	ID       string            `json:"id"`
+	Title    *string           `json:"title,omitempty"`
+	Priority int               `json:"priority"`
+	Labels   []string          `json:"labels,omitempty"`
+	Due      time.Time         `json:"due,omitempty"`
+	Meta     map[string]string `json:"meta,omitempty"`
+	Owner    nested            `json:"owner"`
+	Session  string            `json:"session,omitempty"`
+	Internal string            `json:"-"`
+	hidden   string
+}
+
+func TestInputSchema(t *testing.T) {
+	s := InputSchema(&schemaArgs{}, map[string]string{"id": "Issue ID"}, "session")
+	props := s["properties"].(map[string]interface{})
+
+	for _, name := range []string{"id", "title", "priority", "labels", "due", "meta", "owner"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("missing property %q", name)
+		}
+	}
+	for _, name := range []string{"session", "Internal", "hidden"} {
+		if _, ok := props[name]; ok {
+			t.Errorf("property %q should be omitted", name)
+		}
+	}
+	if got := s["required"]; !reflect.DeepEqual(got, []string{"id", "owner", "priority"}) {
+		t.Errorf("required = %v", got)
+	}
+	if got := props["id"].(map[string]interface{})["description"]; got != "Issue ID" {
+		t.Errorf("id description = %v", got)
+	}
+	if got := props["title"].(map[string]interface{})["type"]; got != "string" {
+		t.Errorf("pointer field type = %v, want string", got)
+	}
+	if got := props["labels"].(map[string]interface{})["items"]; !reflect.DeepEqual(got, map[string]interface{}{"type": "string"}) {
+		t.Errorf("labels items = %v", got)
+	}
+	if got := props["due"].(map[string]interface{})["format"]; got != "date-time" {
+		t.Errorf("due format = %v", got)
+	}
+	owner := props["owner"].(map[string]interface{})
+	if owner["type"] != "object" || owner["properties"].(map[string]interface{})["name"] == nil {
+		t.Errorf("nested struct should be inlined, got %v", owner)
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	s := InputSchema(schemaArgs{}, nil)
+	applyDefaults(s, map[string]interface{}{"priority": 2, "owner": map[string]string{}})
+	if got := s["required"]; !reflect.DeepEqual(got, []string{"id"}) {
+		t.Errorf("required = %v, want [id]", got)
+	}
+	if got := s["properties"].(map[string]interface{})["priority"].(map[string]interface{})["default"]; got != 2 {
+		t.Errorf("priority default = %v", got)
+	}
+}
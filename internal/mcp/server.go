@@ -0,0 +1,276 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ToolHandler runs a tool. args is the JSON object sent by the client, with
+// the tool's Defaults filled in. The returned text is sent to the client; an
+// error is reported as a failed tool call (not a protocol error) so the
+// model can see and react to it.
+type ToolHandler func(ctx context.Context, args json.RawMessage) (string, error)
+
+// Tool is a callable operation.
+type Tool struct {
+	Name        string
+	Description string
+
+	// InputSchema is the JSON Schema of the arguments (see InputSchema).
+	InputSchema map[string]interface{}
+
+	// Defaults are merged into the arguments for keys the client leaves
+	// out. They are advertised as "default" in the schema and make the
+	// corresponding properties optional.
+	Defaults map[string]interface{}
+
+	Handler ToolHandler
+}
+
+// Resource is a read-only document the client can fetch by URI.
+type Resource struct {
+	URI         string
+	Name        string
+	Description string
+	MIMEType    string
+	Read        func(ctx context.Context) (string, error)
+}
+
+// Server dispatches MCP requests to registered tools and resources.
+type Server struct {
+	info         implementation
+	instructions string
+
+	mu        sync.RWMutex
+	tools     map[string]Tool
+	resources map[string]Resource
+}
+
+// NewServer returns a server that identifies itself as name/version.
+// instructions, if set, is sent to the client on initialize as guidance for
+// the model.
+func NewServer(name, version, instructions string) *Server {
+	return &Server{
+		info:         implementation{Name: name, Version: version},
+		instructions: instructions,
+		tools:        make(map[string]Tool),
+		resources:    make(map[string]Resource),
+	}
+}
+
+// AddTool registers t, replacing any tool with the same name.
+func (s *Server) AddTool(t Tool) {
+	if t.InputSchema == nil {
+		t.InputSchema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	applyDefaults(t.InputSchema, t.Defaults)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools[t.Name] = t
+}
+
+// AddResource registers r, replacing any resource with the same URI.
+func (s *Server) AddResource(r Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[r.URI] = r
+}
+
+// Serve reads requests from in and writes responses to out, one JSON
+// message per line, until in is exhausted or ctx is done. Requests are
+// handled in order.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	r := bufio.NewReader(in)
+	enc := json.NewEncoder(out)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, err := r.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if resp := s.handleMessage(ctx, line); resp != nil {
+				if werr := enc.Encode(resp); werr != nil {
+					return fmt.Errorf("writing response: %w", werr)
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading request: %w", err)
+		}
+	}
+}
+
+// handleMessage handles one message and returns the response to send, or
+// nil for notifications.
+func (s *Server) handleMessage(ctx context.Context, line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		if len(line) > 0 && line[0] == '[' {
+			return errorResponse(nil, CodeInvalidRequest, "batch requests are not supported")
+		}
+		return errorResponse(nil, CodeParseError, "parse error: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if req.isNotification() {
+			return nil
+		}
+		return errorResponse(req.ID, CodeInvalidRequest, "invalid JSON-RPC 2.0 request")
+	}
+
+	result, rerr := s.dispatch(ctx, &req)
+	if req.isNotification() {
+		return nil
+	}
+	if rerr != nil {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rerr}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) dispatch(ctx context.Context, req *request) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	case "resources/list":
+		return s.listResources(), nil
+	case "resources/templates/list":
+		return map[string]interface{}{"resourceTemplates": []interface{}{}}, nil
+	case "resources/read":
+		return s.readResource(ctx, req.Params)
+	default:
+		if strings.HasPrefix(req.Method, "notifications/") {
+			// initialized, cancelled, ...: nothing to do
+			return nil, nil
+		}
+		return nil, &rpcError{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func (s *Server) initialize(raw json.RawMessage) (interface{}, *rpcError) {
+	var params initializeParams
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, &rpcError{Code: CodeInvalidParams, Message: err.Error()}
+		}
+	}
+	version := ProtocolVersion
+	for _, v := range supportedVersions {
+		if v == params.ProtocolVersion {
+			version = v
+		}
+	}
+	return initializeResult{
+		ProtocolVersion: version,
+		Capabilities: map[string]interface{}{
+			"tools":     map[string]interface{}{"listChanged": false},
+			"resources": map[string]interface{}{"listChanged": false, "subscribe": false},
+		},
+		ServerInfo:   s.info,
+		Instructions: s.instructions,
+	}, nil
+}
+
+func (s *Server) listTools() interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tools := make([]toolInfo, 0, len(s.tools))
+	for _, t := range s.tools {
+		tools = append(tools, toolInfo{Name: t.Name, Description: t.Description, InputSchema: t.InputSchema})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return map[string]interface{}{"tools": tools}
+}
+
+func (s *Server) callTool(ctx context.Context, raw json.RawMessage) (interface{}, *rpcError) {
+	var params callToolParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &rpcError{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	s.mu.RLock()
+	tool, ok := s.tools[params.Name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, &rpcError{Code: CodeInvalidParams, Message: "unknown tool: " + params.Name}
+	}
+
+	args, err := mergeDefaults(params.Arguments, tool.Defaults)
+	if err != nil {
+		return nil, &rpcError{Code: CodeInvalidParams, Message: fmt.Sprintf("invalid arguments for %s: %v", tool.Name, err)}
+	}
+	text, err := tool.Handler(ctx, args)
+	if err != nil {
+		return callToolResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return callToolResult{Content: []content{{Type: "text", Text: text}}}, nil
+}
+
+func (s *Server) listResources() interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	resources := make([]resourceInfo, 0, len(s.resources))
+	for _, r := range s.resources {
+		resources = append(resources, resourceInfo{URI: r.URI, Name: r.Name, Description: r.Description, MIMEType: r.MIMEType})
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].URI < resources[j].URI })
+	return map[string]interface{}{"resources": resources}
+}
+
+func (s *Server) readResource(ctx context.Context, raw json.RawMessage) (interface{}, *rpcError) {
+	var params readResourceParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &rpcError{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	s.mu.RLock()
+	res, ok := s.resources[params.URI]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, &rpcError{Code: CodeInvalidParams, Message: "unknown resource: " + params.URI}
+	}
+	text, err := res.Read(ctx)
+	if err != nil {
+		return nil, &rpcError{Code: CodeInternalError, Message: err.Error()}
+	}
+	return map[string]interface{}{
+		"contents": []resourceContents{{URI: res.URI, MIMEType: res.MIMEType, Text: text}},
+	}, nil
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: msg}}
+}
+
+// mergeDefaults fills defaults into a JSON object of arguments.
+func mergeDefaults(raw json.RawMessage, defaults map[string]interface{}) (json.RawMessage, error) {
+	args := make(map[string]interface{})
+	if len(bytes.TrimSpace(raw)) > 0 && string(bytes.TrimSpace(raw)) != "null" {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, errors.New("arguments must be a JSON object")
+		}
+	}
+	for k, v := range defaults {
+		if _, ok := args[k]; !ok {
+			args[k] = v
+		}
+	}
+	return json.Marshal(args)
+}
@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type echoArgs struct {
+	Text  string `json:"text"`
+	Count int    `json:"count"`
+}
+
+func newTestServer() *Server {
+	s := NewServer("bd", "1.0.0", "Track work with beads.")
+	s.AddTool(Tool{
+		Name:        "echo",
+		Description: "Repeat text",
+		InputSchema: InputSchema(echoArgs{}, nil),
+		Defaults:    map[string]interface{}{"count": 1},
+		Handler: func(_ context.Context, raw json.RawMessage) (string, error) {
+			var args echoArgs
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return "", err
+			}
+			if args.Text == "" {
+				return "", errors.New("text is required")
+			}
+			return strings.Repeat(args.Text, args.Count), nil
+		},
+	})
+	s.AddResource(Resource{
+		URI:      "beads://prime",
+		Name:     "Workflow context",
+		MIMEType: "text/markdown",
+		Read:     func(context.Context) (string, error) { return "# Beads", nil },
+	})
+	return s
+}
+
+// roundTrip sends each message on its own line and returns the decoded
+// responses.
+func roundTrip(t *testing.T, s *Server, messages ...string) []map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(strings.Join(messages, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	var responses []map[string]interface{}
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp map[string]interface{}
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServeInitialize(t *testing.T) {
+	resps := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"editor","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+	)
+	if len(resps) != 2 {
+		t.Fatalf("got %d responses, want 2 (notifications get none): %v", len(resps), resps)
+	}
+	result := resps[0]["result"].(map[string]interface{})
+	if result["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v, want the client's supported version", result["protocolVersion"])
+	}
+	if result["instructions"] != "Track work with beads." {
+		t.Errorf("instructions = %v", result["instructions"])
+	}
+	if info := result["serverInfo"].(map[string]interface{}); info["name"] != "bd" {
+		t.Errorf("serverInfo = %v", info)
+	}
+	if resps[1]["id"] != float64(2) || resps[1]["error"] != nil {
+		t.Errorf("ping response = %v", resps[1])
+	}
+}
+
+func TestServeUnknownVersion(t *testing.T) {
+	resps := roundTrip(t, newTestServer(), `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+	if got := resps[0]["result"].(map[string]interface{})["protocolVersion"]; got != ProtocolVersion {
+		t.Errorf("protocolVersion = %v, want %s", got, ProtocolVersion)
+	}
+}
+
+func TestServeTools(t *testing.T) {
+	resps := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":"ab"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"ab","count":3}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"missing"}}`,
+	)
+	if len(resps) != 5 {
+		t.Fatalf("got %d responses, want 5", len(resps))
+	}
+
+	tools := resps[0]["result"].(map[string]interface{})["tools"].([]interface{})
+	if len(tools) != 1 {
+		t.Fatalf("tools = %v", tools)
+	}
+	schema := tools[0].(map[string]interface{})["inputSchema"].(map[string]interface{})
+	if req := schema["required"].([]interface{}); len(req) != 1 || req[0] != "text" {
+		t.Errorf("required = %v, want [text] (count has a default)", req)
+	}
+	if d := schema["properties"].(map[string]interface{})["count"].(map[string]interface{})["default"]; d != float64(1) {
+		t.Errorf("count default = %v", d)
+	}
+
+	for id, want := range map[int]string{1: "ab", 2: "ababab"} {
+		result := resps[id]["result"].(map[string]interface{})
+		text := result["content"].([]interface{})[0].(map[string]interface{})["text"]
+		if text != want || result["isError"] != nil {
+			t.Errorf("call %d = %v, want %q", id+1, result, want)
+		}
+	}
+
+	failed := resps[3]["result"].(map[string]interface{})
+	if failed["isError"] != true {
+		t.Errorf("handler error should be a tool error result, got %v", resps[3])
+	}
+	if e, ok := resps[4]["error"].(map[string]interface{}); !ok || e["code"] != float64(CodeInvalidParams) {
+		t.Errorf("unknown tool should be an invalid params error, got %v", resps[4])
+	}
+}
+
+func TestServeResources(t *testing.T) {
+	resps := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"beads://prime"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"beads://nope"}}`,
+	)
+	resources := resps[0]["result"].(map[string]interface{})["resources"].([]interface{})
+	if len(resources) != 1 || resources[0].(map[string]interface{})["uri"] != "beads://prime" {
+		t.Errorf("resources = %v", resources)
+	}
+	contents := resps[1]["result"].(map[string]interface{})["contents"].([]interface{})
+	if c := contents[0].(map[string]interface{}); c["text"] != "# Beads" || c["mimeType"] != "text/markdown" {
+		t.Errorf("contents = %v", contents)
+	}
+	if resps[2]["error"] == nil {
+		t.Errorf("unknown resource should fail, got %v", resps[2])
+	}
+}
+
+func TestServeProtocolErrors(t *testing.T) {
+	resps := roundTrip(t, newTestServer(),
+		`{not json`,
+		`[{"jsonrpc":"2.0","id":1,"method":"ping"}]`,
+		`{"jsonrpc":"2.0","id":2,"method":"prompts/list"}`,
+		`{"id":3,"method":"ping"}`,
+	)
+	want := []int{CodeParseError, CodeInvalidRequest, CodeMethodNotFound, CodeInvalidRequest}
+	if len(resps) != len(want) {
+		t.Fatalf("got %d responses, want %d", len(resps), len(want))
+	}
+	for i, code := range want {
+		e, ok := resps[i]["error"].(map[string]interface{})
+		if !ok || e["code"] != float64(code) {
+			t.Errorf("response %d = %v, want error %d", i, resps[i], code)
+		}
+	}
+}
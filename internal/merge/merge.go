@@ -33,46 +33,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
 )
 
-// Issue represents a beads issue with all possible fields
-type Issue struct {
-	ID           string       `json:"id"`
-	Title        string       `json:"title,omitempty"`
-	Description  string       `json:"description,omitempty"`
-	Notes        string       `json:"notes,omitempty"`
-	Status       string       `json:"status,omitempty"`
-	Priority     int          `json:"priority"` // No omitempty: 0 is valid (P0/critical)
-	IssueType    string       `json:"issue_type,omitempty"`
-	CreatedAt       string       `json:"created_at,omitempty"`
-	UpdatedAt       string       `json:"updated_at,omitempty"`
-	ClosedAt        string       `json:"closed_at,omitempty"`
-	CloseReason     string       `json:"close_reason,omitempty"`     // Reason provided when closing (GH#891)
-	ClosedBySession string       `json:"closed_by_session,omitempty"` // Session that closed this issue (GH#891)
-	CreatedBy       string       `json:"created_by,omitempty"`
-	Dependencies []Dependency `json:"dependencies,omitempty"`
-	RawLine      string       `json:"-"` // Store original line for conflict output
-	// Tombstone fields: inline soft-delete support for merge
-	DeletedAt    string `json:"deleted_at,omitempty"`    // When the issue was deleted
-	DeletedBy    string `json:"deleted_by,omitempty"`    // Who deleted the issue
-	DeleteReason string `json:"delete_reason,omitempty"` // Why the issue was deleted
-	OriginalType string `json:"original_type,omitempty"` // Issue type before deletion
-	// HOP quality field
-	QualityScore *float32 `json:"quality_score,omitempty"` // Aggregate quality (0.0-1.0)
-}
-
-// Dependency represents an issue dependency
-type Dependency struct {
-	IssueID     string `json:"issue_id"`
-	DependsOnID string `json:"depends_on_id"`
-	Type        string `json:"type"`
-	CreatedAt   string `json:"created_at"`
-	CreatedBy   string `json:"created_by"`
-}
+// Issue is the record merged by the driver. It is the full types.Issue, so
+// every field that bd exports survives a merge; see fieldRules for how each
+// field is merged.
+type Issue = types.Issue
+
+// Dependency is an issue dependency as stored in the JSONL.
+type Dependency = types.Dependency
 
 // IssueKey uniquely identifies an issue for matching
 type IssueKey struct {
@@ -83,6 +58,13 @@ type IssueKey struct {
 
 // Merge3Way performs a 3-way merge of JSONL issue files
 func Merge3Way(outputPath, basePath, leftPath, rightPath string, debug bool) error {
+	return Merge3WayWithPolicy(outputPath, basePath, leftPath, rightPath, nil, debug)
+}
+
+// Merge3WayWithPolicy is Merge3Way with per-field strategies (see Policy).
+// Issues with unresolved conflict fields are written as conflict records
+// and counted in the returned error.
+func Merge3WayWithPolicy(outputPath, basePath, leftPath, rightPath string, policy *Policy, debug bool) error {
 	if debug {
 		fmt.Fprintf(os.Stderr, "=== DEBUG MODE ===\n")
 		fmt.Fprintf(os.Stderr, "Output path: %s\n", outputPath)
@@ -119,7 +101,7 @@ func Merge3Way(outputPath, basePath, leftPath, rightPath string, debug bool) err
 	}
 
 	// Perform 3-way merge
-	result, conflicts := merge3Way(baseIssues, leftIssues, rightIssues, debug)
+	result, conflicts := merge3WayWithPolicy(baseIssues, leftIssues, rightIssues, DefaultTombstoneTTL, policy, debug)
 
 	if debug {
 		fmt.Fprintf(os.Stderr, "Merge complete:\n")
@@ -215,6 +197,8 @@ func readIssues(path string) ([]Issue, error) {
 
 	var issues []Issue
 	scanner := bufio.NewScanner(file)
+	// Issues carry full descriptions and comments, so allow long lines
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
 	lineNum := 0
 	for scanner.Scan() {
 		lineNum++
@@ -227,7 +211,6 @@ func readIssues(path string) ([]Issue, error) {
 		if err := json.Unmarshal([]byte(line), &issue); err != nil {
 			return nil, fmt.Errorf("failed to parse line %d: %w", lineNum, err)
 		}
-		issue.RawLine = line
 		issues = append(issues, issue)
 	}
 
@@ -239,17 +222,21 @@ func readIssues(path string) ([]Issue, error) {
 }
 
 func makeKey(issue Issue) IssueKey {
+	createdAt := ""
+	if !issue.CreatedAt.IsZero() {
+		createdAt = issue.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
 	return IssueKey{
 		ID:        issue.ID,
-		CreatedAt: issue.CreatedAt,
+		CreatedAt: createdAt,
 		CreatedBy: issue.CreatedBy,
 	}
 }
 
 // Use constants from types package to avoid duplication
 const (
-	StatusTombstone = string(types.StatusTombstone)
-	StatusClosed    = string(types.StatusClosed)
+	StatusTombstone = types.StatusTombstone
+	StatusClosed    = types.StatusClosed
 )
 
 // Alias TTL constants from types package for local use
@@ -273,7 +260,7 @@ func IsExpiredTombstone(issue Issue, ttl time.Duration) bool {
 	}
 
 	// Tombstones without DeletedAt are not expired (safety: shouldn't happen in valid data)
-	if issue.DeletedAt == "" {
+	if issue.DeletedAt == nil || issue.DeletedAt.IsZero() {
 		return false
 	}
 
@@ -282,21 +269,11 @@ func IsExpiredTombstone(issue Issue, ttl time.Duration) bool {
 		ttl = DefaultTombstoneTTL
 	}
 
-	// Parse the deleted_at timestamp
-	deletedAt, err := time.Parse(time.RFC3339Nano, issue.DeletedAt)
-	if err != nil {
-		deletedAt, err = time.Parse(time.RFC3339, issue.DeletedAt)
-		if err != nil {
-			// Invalid timestamp means not expired (safety)
-			return false
-		}
-	}
-
 	// Add clock skew grace period to the TTL
 	effectiveTTL := ttl + ClockSkewGrace
 
 	// Check if the tombstone has exceeded its TTL
-	expirationTime := deletedAt.Add(effectiveTTL)
+	expirationTime := issue.DeletedAt.Add(effectiveTTL)
 	return time.Now().After(expirationTime)
 }
 
@@ -310,6 +287,10 @@ func merge3Way(base, left, right []Issue, debug bool) ([]Issue, []string) {
 // per-repository configuration. For default TTL behavior, use merge3Way.
 // When debug is true, logs resurrection events to stderr.
 func Merge3WayWithTTL(base, left, right []Issue, ttl time.Duration, debug bool) ([]Issue, []string) {
+	return merge3WayWithPolicy(base, left, right, ttl, nil, debug)
+}
+
+func merge3WayWithPolicy(base, left, right []Issue, ttl time.Duration, policy *Policy, debug bool) ([]Issue, []string) {
 	// Build maps for quick lookup by IssueKey
 	baseMap := make(map[IssueKey]Issue)
 	for _, issue := range base {
@@ -450,7 +431,7 @@ func Merge3WayWithTTL(base, left, right []Issue, ttl time.Duration, debug bool)
 			}
 
 			// CASE: Both are live issues - standard merge
-			merged, conflict := mergeIssue(baseIssue, leftIssue, rightIssue)
+			merged, conflict := mergeIssueWithPolicy(baseIssue, leftIssue, rightIssue, policy)
 			if conflict != "" {
 				conflicts = append(conflicts, conflict)
 			} else {
@@ -493,13 +474,12 @@ func Merge3WayWithTTL(base, left, right []Issue, ttl time.Duration, debug bool)
 			}
 
 			// CASE: Both are live - merge using deterministic rules with empty base
-			emptyBase := Issue{
-				ID:        leftIssue.ID,
-				CreatedAt: leftIssue.CreatedAt,
-				CreatedBy: leftIssue.CreatedBy,
+			merged, conflict := mergeIssueWithPolicy(emptyBase(leftIssue), leftIssue, rightIssue, policy)
+			if conflict != "" {
+				conflicts = append(conflicts, conflict)
+			} else {
+				result = append(result, merged)
 			}
-			merged, _ := mergeIssue(emptyBase, leftIssue, rightIssue)
-			result = append(result, merged)
 		} else if inBase && inLeft && !inRight {
 			// Deleted in right (implicitly), maybe modified in left
 			// Check if left is a tombstone - tombstones must be preserved
@@ -540,46 +520,249 @@ func Merge3WayWithTTL(base, left, right []Issue, ttl time.Duration, debug bool)
 // mergeTombstones merges two tombstones for the same issue.
 // The tombstone with the later deleted_at timestamp wins.
 //
-// Edge cases for empty DeletedAt:
-//   - If both empty: left wins (arbitrary but deterministic)
-//   - If left empty, right not: right wins (has timestamp)
-//   - If right empty, left not: left wins (has timestamp)
+// Edge cases for missing DeletedAt:
+//   - If both missing: left wins (arbitrary but deterministic)
+//   - If left missing, right not: right wins (has timestamp)
+//   - If right missing, left not: left wins (has timestamp)
 //
-// Empty DeletedAt shouldn't happen in valid data (validation catches it),
+// Missing DeletedAt shouldn't happen in valid data (validation catches it),
 // but we handle it defensively here.
 func mergeTombstones(left, right Issue) Issue {
-	// Handle empty DeletedAt explicitly for clarity
-	if left.DeletedAt == "" && right.DeletedAt == "" {
-		// Both invalid - left wins as tie-breaker
+	if isTimePtrAfter(left.DeletedAt, right.DeletedAt) || right.DeletedAt == nil {
 		return left
 	}
-	if left.DeletedAt == "" {
-		// Left invalid, right valid - right wins
-		return right
+	return right
+}
+
+// mergeRule says how a field of types.Issue is merged. Every rule first
+// applies the usual 3-way logic (a change on one side wins over an
+// unchanged field); the rules differ in what happens when both sides
+// changed the field differently.
+type mergeRule int
+
+const (
+	ruleKey          mergeRule = iota // Part of the issue key: never changes in a merge
+	ruleLatest                        // Conflict: side with the later updated_at wins
+	ruleLocal                         // Conflict: local (left) side wins
+	ruleMaxTime                       // Later of the two timestamps
+	ruleNotes                         // Conflict: both sides concatenated
+	ruleStatus                        // Closed wins over open (see mergeStatus)
+	rulePriority                      // Conflict: more urgent priority wins (see mergePriority)
+	ruleClosed                        // Taken from the later close; cleared unless status is closed
+	ruleTombstone                     // Taken from the later deletion; only kept on tombstones
+	ruleCompaction                    // Taken from the side with the higher compaction level
+	ruleStringSet                     // Set merge where removals win
+	ruleComments                      // Set merge keyed by comment ID where removals win
+	ruleDependencies                  // Set merge keyed by edge where removals win
+	ruleUnion                         // Union of both sides (append-only records)
+)
+
+// fieldRules maps every JSONL field of types.Issue to its merge rule.
+// TestFieldRulesCoverIssue fails when a field is added to types.Issue
+// without an entry here, so new fields can't silently be dropped or
+// mis-merged by the driver.
+var fieldRules = map[string]mergeRule{
+	// Identity
+	"id":         ruleKey,
+	"created_at": ruleKey,
+	"created_by": ruleKey,
+
+	// Content
+	"title":               ruleLatest,
+	"description":         ruleLatest,
+	"design":              ruleLatest,
+	"acceptance_criteria": ruleLatest,
+	"notes":               ruleNotes,
+
+	// Workflow
+	"status":     ruleStatus,
+	"priority":   rulePriority,
+	"issue_type": ruleLocal,
+
+	// Assignment and scheduling
+	"assignee":          ruleLatest,
+	"owner":             ruleLatest,
+	"estimated_minutes": ruleLatest,
+	"due_at":            ruleLatest,
+	"defer_until":       ruleLatest,
+
+	// Timestamps
+	"updated_at":        ruleMaxTime,
+	"closed_at":         ruleClosed,
+	"close_reason":      ruleClosed,
+	"closed_by_session": ruleClosed,
+
+	// External integration
+	"external_ref":  ruleLatest,
+	"source_system": ruleLatest,
+
+	// Compaction metadata travels together
+	"compaction_level":    ruleCompaction,
+	"compacted_at":        ruleCompaction,
+	"compacted_at_commit": ruleCompaction,
+	"original_size":       ruleCompaction,
+
+	// Relational data
+	"labels":       ruleStringSet,
+	"dependencies": ruleDependencies,
+	"comments":     ruleComments,
+
+	// Tombstone
+	"deleted_at":    ruleTombstone,
+	"deleted_by":    ruleTombstone,
+	"delete_reason": ruleTombstone,
+	"original_type": ruleTombstone,
+
+	// Messaging and context markers
+	"sender":      ruleLatest,
+	"ephemeral":   ruleLatest,
+	"pinned":      ruleLatest,
+	"is_template": ruleLatest,
+
+	// Bonding and HOP
+	"bonded_from":   ruleLatest,
+	"creator":       ruleLatest,
+	"validations":   ruleUnion,
+	"quality_score": ruleLatest,
+	"crystallizes":  ruleLatest,
+
+	// Gates and slots
+	"await_type": ruleLatest,
+	"await_id":   ruleLatest,
+	"timeout":    ruleLatest,
+	"waiters":    ruleStringSet,
+	"holder":     ruleLatest,
+
+	// Source tracing
+	"source_formula":  ruleLatest,
+	"source_location": ruleLatest,
+
+	// Agent identity
+	"hook_bead":     ruleLatest,
+	"role_bead":     ruleLatest,
+	"agent_state":   ruleLatest,
+	"last_activity": ruleMaxTime,
+	"role_type":     ruleLatest,
+	"rig":           ruleLatest,
+
+	// Molecule and work type
+	"mol_type":  ruleLatest,
+	"work_type": ruleLatest,
+
+	// Events
+	"event_kind": ruleLatest,
+	"actor":      ruleLatest,
+	"target":     ruleLatest,
+	"payload":    ruleLatest,
+}
+
+// issueField is a JSONL field of types.Issue: its JSON name, its index in
+// the struct and its type.
+type issueField struct {
+	name  string
+	index int
+	typ   reflect.Type
+}
+
+// issueFields lists the fields of types.Issue that appear in the JSONL, in
+// struct order. Fields tagged json:"-" are internal and never merged.
+var issueFields = func() []issueField {
+	t := reflect.TypeOf(Issue{})
+	var fields []issueField
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "-" || name == "" {
+			continue
+		}
+		fields = append(fields, issueField{name: name, index: i, typ: t.Field(i).Type})
 	}
-	if right.DeletedAt == "" {
-		// Right invalid, left valid - left wins
-		return left
+	return fields
+}()
+
+// MergeIssue merges two versions of the same issue that have no common
+// ancestor (both sides added it), using the same field rules as Merge3Way.
+// Fields configured as conflict are resolved by lww instead.
+func MergeIssue(left, right Issue, policy *Policy) Issue {
+	merged, _ := mergeIssueWithPolicy(emptyBase(left), left, right, policy.WithoutConflicts())
+	return merged
+}
+
+// emptyBase is the base used when both sides added the same issue.
+func emptyBase(issue Issue) Issue {
+	return Issue{
+		ID:        issue.ID,
+		CreatedAt: issue.CreatedAt,
+		CreatedBy: issue.CreatedBy,
 	}
-	// Both valid - use later deleted_at as the authoritative tombstone
-	if isTimeAfter(left.DeletedAt, right.DeletedAt) {
-		return left
+}
+
+// MergeIssue3Way merges two versions of an issue against their common
+// ancestor (nil if both sides added it), using the same field rules as
+// Merge3Way. It returns the merged issue and the fields configured as
+// conflict that both sides changed differently; those keep the left value.
+func MergeIssue3Way(base *Issue, left, right Issue, policy *Policy) (Issue, []string) {
+	b := emptyBase(left)
+	if base != nil {
+		b = *base
 	}
-	return right
+	return mergeIssueFields(b, left, right, policy)
 }
 
 func mergeIssue(base, left, right Issue) (Issue, string) {
-	result := Issue{
-		ID:        base.ID,
-		CreatedAt: base.CreatedAt,
-		CreatedBy: base.CreatedBy,
+	return mergeIssueWithPolicy(base, left, right, nil)
+}
+
+// mergeIssueWithPolicy merges one issue field by field. Fields configured
+// in policy use their strategy instead of the built-in rule. If a field
+// configured as conflict changed differently on both sides, the returned
+// string is a conflict record (git conflict markers around the left and
+// right versions) and the returned issue should not be used.
+func mergeIssueWithPolicy(base, left, right Issue, policy *Policy) (Issue, string) {
+	result, conflictFields := mergeIssueFields(base, left, right, policy)
+	if len(conflictFields) > 0 {
+		return result, conflictRecord(result, right, conflictFields)
 	}
 
-	// Merge title - on conflict, side with latest updated_at wins
-	result.Title = mergeFieldByUpdatedAt(base.Title, left.Title, right.Title, left.UpdatedAt, right.UpdatedAt)
+	// All field conflicts are now auto-resolved deterministically
+	return result, ""
+}
+
+// mergeIssueFields does the field-by-field merge for mergeIssueWithPolicy
+// and reports the fields left in conflict by the policy.
+func mergeIssueFields(base, left, right Issue, policy *Policy) (Issue, []string) {
+	var result Issue
+	out := reflect.ValueOf(&result).Elem()
+	bv, lv, rv := reflect.ValueOf(base), reflect.ValueOf(left), reflect.ValueOf(right)
+
+	// Fields with a generic rule are merged by reflection, so a new field only
+	// needs an entry in fieldRules. Fields with dedicated rules are set below.
+	leftNewer := isTimeAfter(left.UpdatedAt, right.UpdatedAt)
+	sameCompaction := left.CompactionLevel == right.CompactionLevel
+	for _, f := range issueFields {
+		b, l, r := bv.Field(f.index), lv.Field(f.index), rv.Field(f.index)
+		switch fieldRules[f.name] {
+		case ruleLocal:
+			out.Field(f.index).Set(mergeValue(b, l, r, true))
+		case ruleCompaction:
+			// The compaction fields describe one compaction, so keep them
+			// together: the more compacted side wins as a whole
+			switch {
+			case sameCompaction:
+				out.Field(f.index).Set(mergeValue(b, l, r, leftNewer))
+			case left.CompactionLevel > right.CompactionLevel:
+				out.Field(f.index).Set(l)
+			default:
+				out.Field(f.index).Set(r)
+			}
+		case ruleLatest:
+			out.Field(f.index).Set(mergeValue(b, l, r, leftNewer))
+		}
+	}
 
-	// Merge description - on conflict, side with latest updated_at wins
-	result.Description = mergeFieldByUpdatedAt(base.Description, left.Description, right.Description, left.UpdatedAt, right.UpdatedAt)
+	// Identity comes from the base (the issue key is the same on all sides)
+	result.ID = base.ID
+	result.CreatedAt = base.CreatedAt
+	result.CreatedBy = base.CreatedBy
 
 	// Merge notes - on conflict, concatenate both sides
 	result.Notes = mergeNotes(base.Notes, left.Notes, right.Notes)
@@ -590,67 +773,121 @@ func mergeIssue(base, left, right Issue) (Issue, string) {
 	// Merge priority - on conflict, higher priority wins (lower number = more urgent)
 	result.Priority = mergePriority(base.Priority, left.Priority, right.Priority)
 
-	// Merge issue_type - on conflict, local (left) wins
-	result.IssueType = mergeField(base.IssueType, left.IssueType, right.IssueType)
-
-	// Merge updated_at - take the max
+	// Merge updated_at and last_activity - take the max
 	result.UpdatedAt = maxTime(left.UpdatedAt, right.UpdatedAt)
+	result.LastActivity = maxTimePtr(left.LastActivity, right.LastActivity)
+
+	// Merge relational data - set merges where removals win
+	result.Labels = mergeStringSet(base.Labels, left.Labels, right.Labels)
+	result.Waiters = mergeStringSet(base.Waiters, left.Waiters, right.Waiters)
+	result.Dependencies = mergeDependencies(base.Dependencies, left.Dependencies, right.Dependencies)
+	result.Comments = mergeComments(base.Comments, left.Comments, right.Comments)
+	result.Validations = mergeValidations(left.Validations, right.Validations)
+
+	// Configured strategies override the built-in rules
+	var conflictFields []string
+	if policy != nil {
+		for _, f := range issueFields {
+			strategy := policy.strategy(f.name)
+			if strategy == "" {
+				continue
+			}
+			v, conflict := policy.applyStrategy(f.name, strategy, bv.Field(f.index), lv.Field(f.index), rv.Field(f.index), leftNewer)
+			out.Field(f.index).Set(v)
+			if conflict {
+				conflictFields = append(conflictFields, f.name)
+			}
+		}
+	}
 
 	// Merge closed_at - only if status is closed
 	// This prevents invalid state (status=open with closed_at set)
 	if result.Status == StatusClosed {
-		result.ClosedAt = maxTime(left.ClosedAt, right.ClosedAt)
+		result.ClosedAt = maxTimePtr(left.ClosedAt, right.ClosedAt)
 		// Merge close_reason and closed_by_session - use value from side with later closed_at (GH#891)
 		// This ensures we keep the most recent close action's metadata
-		if isTimeAfter(left.ClosedAt, right.ClosedAt) {
+		if isTimePtrAfter(left.ClosedAt, right.ClosedAt) || right.ClosedAt == nil {
 			result.CloseReason = left.CloseReason
 			result.ClosedBySession = left.ClosedBySession
-		} else if right.ClosedAt != "" {
+		} else {
 			result.CloseReason = right.CloseReason
 			result.ClosedBySession = right.ClosedBySession
-		} else {
-			// Both empty or only left has value - prefer left
-			result.CloseReason = left.CloseReason
-			result.ClosedBySession = left.ClosedBySession
 		}
-	} else {
-		result.ClosedAt = ""
-		result.CloseReason = ""
-		result.ClosedBySession = ""
 	}
 
-	// Merge dependencies - proper 3-way merge where removals win
-	result.Dependencies = mergeDependencies(base.Dependencies, left.Dependencies, right.Dependencies)
-
 	// If status became tombstone via mergeStatus safety fallback,
 	// copy tombstone fields from whichever side has them
 	if result.Status == StatusTombstone {
 		// Prefer the side with more recent deleted_at, or left if tied
-		if isTimeAfter(left.DeletedAt, right.DeletedAt) {
-			result.DeletedAt = left.DeletedAt
-			result.DeletedBy = left.DeletedBy
-			result.DeleteReason = left.DeleteReason
-			result.OriginalType = left.OriginalType
-		} else if right.DeletedAt != "" {
-			result.DeletedAt = right.DeletedAt
-			result.DeletedBy = right.DeletedBy
-			result.DeleteReason = right.DeleteReason
-			result.OriginalType = right.OriginalType
-		} else if left.DeletedAt != "" {
-			result.DeletedAt = left.DeletedAt
-			result.DeletedBy = left.DeletedBy
-			result.DeleteReason = left.DeleteReason
-			result.OriginalType = left.OriginalType
+		src := mergeTombstones(left, right)
+		if src.DeletedAt != nil {
+			result.DeletedAt = src.DeletedAt
+			result.DeletedBy = src.DeletedBy
+			result.DeleteReason = src.DeleteReason
+			result.OriginalType = src.OriginalType
 		}
 		// Note: if neither has DeletedAt, tombstone fields remain empty
 		// This represents invalid data that validation should catch
 	}
 
-	// All field conflicts are now auto-resolved deterministically
-	return result, ""
+	return result, conflictFields
+}
+
+// conflictRecord renders an unresolved issue in git conflict marker form,
+// which bd resolve-conflicts understands. Both versions carry the merged
+// value for every field except the conflicting ones, which keep the left
+// and right values respectively, so only the real disagreement remains.
+func conflictRecord(merged, right Issue, fields []string) string {
+	rightVersion := merged
+	rv, src := reflect.ValueOf(&rightVersion).Elem(), reflect.ValueOf(right)
+	for _, name := range fields {
+		f, _ := findIssueField(name)
+		rv.Field(f.index).Set(src.Field(f.index))
+	}
+	if slices.Contains(fields, "status") {
+		// Keep the close metadata consistent with each side's status
+		rightVersion.ClosedAt = right.ClosedAt
+		rightVersion.CloseReason = right.CloseReason
+		rightVersion.ClosedBySession = right.ClosedBySession
+	}
+	leftJSON, _ := json.Marshal(merged)
+	rightJSON, _ := json.Marshal(rightVersion)
+	return fmt.Sprintf("<<<<<<< left (conflicting: %s)\n%s\n=======\n%s\n>>>>>>> right",
+		strings.Join(fields, ", "), leftJSON, rightJSON)
+}
+
+// mergeValue is the generic 3-way merge of one field. When both sides
+// changed it differently, the left value wins if leftWins is set.
+func mergeValue(base, left, right reflect.Value, leftWins bool) reflect.Value {
+	if valuesEqual(base, left) && !valuesEqual(base, right) {
+		return right
+	}
+	if valuesEqual(base, right) || valuesEqual(left, right) || leftWins {
+		return left
+	}
+	return right
+}
+
+// valuesEqual compares two field values. Timestamps compare by instant
+// (JSONL round-trips can change the location), and empty slices equal nil.
+func valuesEqual(a, b reflect.Value) bool {
+	switch x := a.Interface().(type) {
+	case time.Time:
+		return x.Equal(b.Interface().(time.Time))
+	case *time.Time:
+		y := b.Interface().(*time.Time)
+		if x == nil || y == nil {
+			return x == y
+		}
+		return x.Equal(*y)
+	}
+	if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
 }
 
-func mergeStatus(base, left, right string) string {
+func mergeStatus(base, left, right types.Status) types.Status {
 	// RULE 0: tombstone is handled at the merge3Way level, not here.
 	// If a tombstone status reaches here, it means both sides have the same
 	// issue with possibly different statuses - tombstone should not be one of them
@@ -669,7 +906,7 @@ func mergeStatus(base, left, right string) string {
 	}
 
 	// Otherwise use standard 3-way merge
-	return mergeField(base, left, right)
+	return types.Status(mergeField(string(base), string(left), string(right)))
 }
 
 func mergeField(base, left, right string) string {
@@ -683,27 +920,6 @@ func mergeField(base, left, right string) string {
 	return left
 }
 
-// mergeFieldByUpdatedAt resolves conflicts by picking the value from the side
-// with the latest updated_at timestamp
-func mergeFieldByUpdatedAt(base, left, right, leftUpdatedAt, rightUpdatedAt string) string {
-	// Standard 3-way merge for non-conflict cases
-	if base == left && base != right {
-		return right
-	}
-	if base == right && base != left {
-		return left
-	}
-	if left == right {
-		return left
-	}
-	// True conflict: both sides changed to different values
-	// Pick the value from the side with the latest updated_at
-	if isTimeAfter(leftUpdatedAt, rightUpdatedAt) {
-		return left
-	}
-	return right
-}
-
 // mergeNotes handles notes merging - on conflict, concatenate both sides
 func mergeNotes(base, left, right string) string {
 	// Standard 3-way merge for non-conflict cases
@@ -758,168 +974,144 @@ func mergePriority(base, left, right int) int {
 	return right
 }
 
-// isTimeAfter returns true if t1 is after t2
-func isTimeAfter(t1, t2 string) bool {
-	if t1 == "" {
+// isTimeAfter returns true if t1 is after t2. A zero time loses to any set
+// time; on an exact tie left (t1) wins for consistency with the IssueType rule.
+func isTimeAfter(t1, t2 time.Time) bool {
+	if t1.IsZero() {
 		return false
 	}
-	if t2 == "" {
+	if t2.IsZero() {
 		return true
 	}
+	return !t2.After(t1)
+}
 
-	time1, err1 := time.Parse(time.RFC3339Nano, t1)
-	if err1 != nil {
-		time1, err1 = time.Parse(time.RFC3339, t1)
-	}
-
-	time2, err2 := time.Parse(time.RFC3339Nano, t2)
-	if err2 != nil {
-		time2, err2 = time.Parse(time.RFC3339, t2)
-	}
-
-	// Handle parse errors consistently with maxTime:
-	// - Valid timestamp beats invalid
-	// - If both invalid, prefer left (t1) for consistency
-	if err1 != nil && err2 != nil {
-		return true // both invalid, prefer left
-	}
-	if err1 != nil {
-		return false // t1 invalid, t2 valid - t2 wins
+// isTimePtrAfter is isTimeAfter for optional timestamps (nil is unset).
+func isTimePtrAfter(t1, t2 *time.Time) bool {
+	if t1 == nil {
+		return false
 	}
-	if err2 != nil {
-		return true // t1 valid, t2 invalid - t1 wins
+	if t2 == nil {
+		return true
 	}
-
-	// Both valid - compare. On exact tie, left wins for consistency with IssueType rule
-	// Using !time2.After(time1) returns true when t1 > t2 OR t1 == t2
-	return !time2.After(time1)
+	return isTimeAfter(*t1, *t2)
 }
 
-func maxTime(t1, t2 string) string {
-	if t1 == "" && t2 == "" {
-		return ""
-	}
-	if t1 == "" {
-		return t2
-	}
-	if t2 == "" {
+func maxTime(t1, t2 time.Time) time.Time {
+	if t1.After(t2) {
 		return t1
 	}
+	return t2
+}
 
-	// Try RFC3339Nano first (supports fractional seconds), fall back to RFC3339
-	time1, err1 := time.Parse(time.RFC3339Nano, t1)
-	if err1 != nil {
-		time1, err1 = time.Parse(time.RFC3339, t1)
-	}
-
-	time2, err2 := time.Parse(time.RFC3339Nano, t2)
-	if err2 != nil {
-		time2, err2 = time.Parse(time.RFC3339, t2)
-	}
-
-	// If both fail to parse, return t2 as fallback
-	if err1 != nil && err2 != nil {
-		return t2
-	}
-	// If only t1 failed to parse, return t2
-	if err1 != nil {
+func maxTimePtr(t1, t2 *time.Time) *time.Time {
+	if t1 == nil {
 		return t2
 	}
-	// If only t2 failed to parse, return t1
-	if err2 != nil {
-		return t1
-	}
-
-	if time1.After(time2) {
+	if t2 == nil || t1.After(*t2) {
 		return t1
 	}
 	return t2
 }
 
-// mergeDependencies performs a proper 3-way merge of dependencies
-// Key principle: REMOVALS ARE AUTHORITATIVE
-// - If dep was in base and removed by left OR right → exclude (removal wins)
-// - If dep wasn't in base and added by left OR right → include
-// - If dep was in base and both still have it → include
-func mergeDependencies(base, left, right []Dependency) []Dependency {
-	// Build sets for O(1) lookup
-	depKey := func(dep Dependency) string {
-		return fmt.Sprintf("%s:%s:%s", dep.IssueID, dep.DependsOnID, dep.Type)
-	}
-
+// mergeSet is the 3-way set merge shared by labels, comments and
+// dependencies. Key principle: REMOVALS ARE AUTHORITATIVE
+// - If an item was in base and removed by left OR right → exclude (removal wins)
+// - If an item wasn't in base and added by left OR right → include
+// - If an item was in base and both still have it → include
+// Left's version of an item is kept when both sides have it. The result is
+// ordered by key for deterministic output.
+func mergeSet[T any](base, left, right []T, key func(T) string) []T {
 	baseSet := make(map[string]bool)
-	for _, dep := range base {
-		baseSet[depKey(dep)] = true
+	for _, item := range base {
+		baseSet[key(item)] = true
 	}
 
-	leftSet := make(map[string]bool)
-	leftDeps := make(map[string]Dependency)
-	for _, dep := range left {
-		key := depKey(dep)
-		leftSet[key] = true
-		leftDeps[key] = dep
+	leftItems := make(map[string]T)
+	for _, item := range left {
+		leftItems[key(item)] = item
 	}
 
-	rightSet := make(map[string]bool)
-	rightDeps := make(map[string]Dependency)
-	for _, dep := range right {
-		key := depKey(dep)
-		rightSet[key] = true
-		rightDeps[key] = dep
+	rightItems := make(map[string]T)
+	for _, item := range right {
+		rightItems[key(item)] = item
 	}
 
-	// Collect all unique keys
-	allKeys := make(map[string]bool)
-	for k := range baseSet {
-		allKeys[k] = true
+	var keys []string
+	for k := range leftItems {
+		if _, inRight := rightItems[k]; baseSet[k] && !inRight {
+			// Right removed it → don't include (right wins)
+			continue
+		}
+		keys = append(keys, k)
 	}
-	for k := range leftSet {
-		allKeys[k] = true
+	for k := range rightItems {
+		if _, inLeft := leftItems[k]; inLeft || baseSet[k] {
+			// Already included, or left removed it (left wins)
+			continue
+		}
+		keys = append(keys, k)
 	}
-	for k := range rightSet {
-		allKeys[k] = true
+	slices.Sort(keys)
+
+	var result []T
+	for _, k := range keys {
+		if item, ok := leftItems[k]; ok {
+			result = append(result, item)
+		} else {
+			result = append(result, rightItems[k])
+		}
 	}
+	return result
+}
 
-	var result []Dependency
-	seen := make(map[string]bool)
+// mergeStringSet merges string sets such as labels and gate waiters.
+func mergeStringSet(base, left, right []string) []string {
+	return mergeSet(base, left, right, func(s string) string { return s })
+}
 
-	for key := range allKeys {
-		inBase := baseSet[key]
-		inLeft := leftSet[key]
-		inRight := rightSet[key]
-
-		// 3-way merge logic:
-		if inBase {
-			// Was in base - check if either side removed it
-			if !inLeft {
-				// Left removed it → don't include (left wins)
-				continue
-			}
-			if !inRight {
-				// Right removed it → don't include (right wins)
-				continue
-			}
-			// Both still have it → include
-		} else {
-			// Wasn't in base - must have been added by left or right
-			if !inLeft && !inRight {
-				// Neither has it (shouldn't happen but handle gracefully)
-				continue
-			}
-			// At least one side added it → include
+// mergeDependencies performs a proper 3-way merge of dependencies, keyed by
+// (issue, target, type). Removals win.
+func mergeDependencies(base, left, right []*Dependency) []*Dependency {
+	return mergeSet(base, left, right, func(dep *Dependency) string {
+		return fmt.Sprintf("%s:%s:%s", dep.IssueID, dep.DependsOnID, dep.Type)
+	})
+}
+
+// mergeComments merges comments keyed by author, time and text, so a
+// comment deleted on one side stays deleted and comments added on both
+// sides are kept. Comment IDs are not used: each clone numbers its own
+// comments, so comments added concurrently on two clones share an ID. The
+// result is in chronological order.
+func mergeComments(base, left, right []*types.Comment) []*types.Comment {
+	merged := mergeSet(base, left, right, func(c *types.Comment) string {
+		return fmt.Sprintf("%s|%s|%s", c.Author, c.CreatedAt.UTC().Format(time.RFC3339Nano), c.Text)
+	})
+	slices.SortStableFunc(merged, func(a, b *types.Comment) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
 		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return merged
+}
 
-		if !seen[key] {
-			seen[key] = true
-			// Prefer left's version of the dep (for any metadata differences)
-			if dep, ok := leftDeps[key]; ok {
-				result = append(result, dep)
-			} else if dep, ok := rightDeps[key]; ok {
-				result = append(result, dep)
-			}
+// mergeValidations unions validation records. Validations are append-only,
+// so there is nothing to remove; duplicates are dropped and the result is in
+// chronological order.
+func mergeValidations(left, right []types.Validation) []types.Validation {
+	var result []types.Validation
+	seen := make(map[string]bool)
+	for _, v := range append(slices.Clone(left), right...) {
+		data, _ := json.Marshal(v)
+		if seen[string(data)] {
+			continue
 		}
+		seen[string(data)] = true
+		result = append(result, v)
 	}
-
+	slices.SortStableFunc(result, func(a, b types.Validation) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
 	return result
 }
-
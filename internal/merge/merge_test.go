@@ -4,18 +4,44 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/beads/internal/types"
 )
 
+// ts parses an RFC3339 timestamp for test fixtures ("" is the zero time).
+func ts(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// tsp is ts for optional timestamps ("" is nil).
+func tsp(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t := ts(s)
+	return &t
+}
+
 // TestMergeStatus tests the status merging logic with special rules
 func TestMergeStatus(t *testing.T) {
 	tests := []struct {
 		name     string
-		base     string
-		left     string
-		right    string
-		expected string
+		base     types.Status
+		left     types.Status
+		right    types.Status
+		expected types.Status
 	}{
 		{
 			name:     "no changes",
@@ -132,116 +158,116 @@ func TestMergeField(t *testing.T) {
 func TestMergeDependencies(t *testing.T) {
 	tests := []struct {
 		name     string
-		base     []Dependency
-		left     []Dependency
-		right    []Dependency
-		expected []Dependency
+		base     []*Dependency
+		left     []*Dependency
+		right    []*Dependency
+		expected []*Dependency
 	}{
 		{
 			name:     "empty all sides",
-			base:     []Dependency{},
-			left:     []Dependency{},
-			right:    []Dependency{},
-			expected: []Dependency{},
+			base:     []*Dependency{},
+			left:     []*Dependency{},
+			right:    []*Dependency{},
+			expected: []*Dependency{},
 		},
 		{
 			name: "left adds dep (not in base)",
-			base: []Dependency{},
-			left: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			base: []*Dependency{},
+			left: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			right: []Dependency{},
-			expected: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			right: []*Dependency{},
+			expected: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
 		},
 		{
 			name: "right adds dep (not in base)",
-			base: []Dependency{},
-			left: []Dependency{},
-			right: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: "2024-01-01T00:00:00Z"},
+			base: []*Dependency{},
+			left: []*Dependency{},
+			right: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			expected: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: "2024-01-01T00:00:00Z"},
+			expected: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
 		},
 		{
 			name: "both add different deps (not in base)",
-			base: []Dependency{},
-			left: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			base: []*Dependency{},
+			left: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			right: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: "2024-01-01T00:00:00Z"},
+			right: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			expected: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
-				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: "2024-01-01T00:00:00Z"},
+			expected: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
+				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
 		},
 		{
 			name: "both add same dep (not in base) - no duplicates",
-			base: []Dependency{},
-			left: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			base: []*Dependency{},
+			left: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			right: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-02T00:00:00Z"},
+			right: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-02T00:00:00Z")},
 			},
-			expected: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"}, // Left preferred
+			expected: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")}, // Left preferred
 			},
 		},
 		{
 			name: "left removes dep from base - REMOVAL WINS",
-			base: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			base: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			left:     []Dependency{}, // Left removed it
-			right: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			left:     []*Dependency{}, // Left removed it
+			right: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			expected: []Dependency{}, // Should be empty - removal wins
+			expected: []*Dependency{}, // Should be empty - removal wins
 		},
 		{
 			name: "right removes dep from base - REMOVAL WINS",
-			base: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			base: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			left: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			left: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			right:    []Dependency{}, // Right removed it
-			expected: []Dependency{}, // Should be empty - removal wins
+			right:    []*Dependency{}, // Right removed it
+			expected: []*Dependency{}, // Should be empty - removal wins
 		},
 		{
 			name: "both keep dep from base",
-			base: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			base: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			left: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			left: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			right: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-02T00:00:00Z"},
+			right: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-02T00:00:00Z")},
 			},
-			expected: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			expected: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
 		},
 		{
 			name: "complex: left removes one, right adds one",
-			base: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
+			base: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
 			},
-			left: []Dependency{}, // Left removed bd-2
-			right: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: "2024-01-01T00:00:00Z"},
-				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: "2024-01-01T00:00:00Z"}, // Right added bd-3
+			left: []*Dependency{}, // Left removed bd-2
+			right: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-2", Type: "blocks", CreatedAt: ts("2024-01-01T00:00:00Z")},
+				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: ts("2024-01-01T00:00:00Z")}, // Right added bd-3
 			},
-			expected: []Dependency{
-				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: "2024-01-01T00:00:00Z"}, // Only the new one
+			expected: []*Dependency{
+				{IssueID: "bd-1", DependsOnID: "bd-3", Type: "related", CreatedAt: ts("2024-01-01T00:00:00Z")}, // Only the new one
 			},
 		},
 	}
@@ -281,19 +307,19 @@ func TestMaxTime(t *testing.T) {
 		expected string
 	}{
 		{
-			name:     "both empty",
+			name:     "both zero",
 			t1:       "",
 			t2:       "",
 			expected: "",
 		},
 		{
-			name:     "t1 empty",
+			name:     "t1 zero",
 			t1:       "",
 			t2:       "2024-01-02T00:00:00Z",
 			expected: "2024-01-02T00:00:00Z",
 		},
 		{
-			name:     "t2 empty",
+			name:     "t2 zero",
 			t1:       "2024-01-01T00:00:00Z",
 			t2:       "",
 			expected: "2024-01-01T00:00:00Z",
@@ -323,24 +349,28 @@ func TestMaxTime(t *testing.T) {
 			expected: "2024-01-01T00:00:00.123456Z",
 		},
 		{
-			name:     "invalid timestamps - returns t2 as fallback",
-			t1:       "invalid",
-			t2:       "also-invalid",
-			expected: "also-invalid",
+			name:     "different time zones compare by instant",
+			t1:       "2024-01-01T10:00:00+02:00",
+			t2:       "2024-01-01T09:00:00Z",
+			expected: "2024-01-01T09:00:00Z",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := maxTime(tt.t1, tt.t2)
-			if result != tt.expected {
-				t.Errorf("maxTime() = %q, want %q", result, tt.expected)
+			result := maxTime(ts(tt.t1), ts(tt.t2))
+			if !result.Equal(ts(tt.expected)) {
+				t.Errorf("maxTime() = %v, want %q", result, tt.expected)
+			}
+			resultPtr := maxTimePtr(tsp(tt.t1), tsp(tt.t2))
+			if (resultPtr == nil) != (tt.expected == "") || (resultPtr != nil && !resultPtr.Equal(ts(tt.expected))) {
+				t.Errorf("maxTimePtr() = %v, want %q", resultPtr, tt.expected)
 			}
 		})
 	}
 }
 
-// TestIsTimeAfter tests timestamp comparison including error handling
+// TestIsTimeAfter tests timestamp comparison including unset timestamps
 func TestIsTimeAfter(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -349,19 +379,19 @@ func TestIsTimeAfter(t *testing.T) {
 		expected bool
 	}{
 		{
-			name:     "both empty - prefer left",
+			name:     "both zero",
 			t1:       "",
 			t2:       "",
 			expected: false,
 		},
 		{
-			name:     "t1 empty - t2 wins",
+			name:     "t1 zero - t2 wins",
 			t1:       "",
 			t2:       "2024-01-02T00:00:00Z",
 			expected: false,
 		},
 		{
-			name:     "t2 empty - t1 wins",
+			name:     "t2 zero - t1 wins",
 			t1:       "2024-01-01T00:00:00Z",
 			t2:       "",
 			expected: true,
@@ -385,31 +415,22 @@ func TestIsTimeAfter(t *testing.T) {
 			expected: true,
 		},
 		{
-			name:     "t1 invalid, t2 valid - t2 wins",
-			t1:       "not-a-timestamp",
+			name:     "same instant in different zones - left wins",
+			t1:       "2024-01-01T02:00:00+02:00",
 			t2:       "2024-01-01T00:00:00Z",
-			expected: false,
-		},
-		{
-			name:     "t1 valid, t2 invalid - t1 wins",
-			t1:       "2024-01-01T00:00:00Z",
-			t2:       "not-a-timestamp",
-			expected: true,
-		},
-		{
-			name:     "both invalid - prefer left",
-			t1:       "invalid1",
-			t2:       "invalid2",
 			expected: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := isTimeAfter(tt.t1, tt.t2)
+			result := isTimeAfter(ts(tt.t1), ts(tt.t2))
 			if result != tt.expected {
 				t.Errorf("isTimeAfter(%q, %q) = %v, want %v", tt.t1, tt.t2, result, tt.expected)
 			}
+			if got := isTimePtrAfter(tsp(tt.t1), tsp(tt.t2)); got != tt.expected {
+				t.Errorf("isTimePtrAfter(%q, %q) = %v, want %v", tt.t1, tt.t2, got, tt.expected)
+			}
 		})
 	}
 }
@@ -422,10 +443,9 @@ func TestMerge3Way_SimpleUpdates(t *testing.T) {
 			Title:     "Original title",
 			Status:    "open",
 			Priority:  2,
-			CreatedAt: "2024-01-01T00:00:00Z",
-			UpdatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
+			UpdatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
-			RawLine:   `{"id":"bd-abc123","title":"Original title","status":"open","priority":2,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 		},
 	}
 
@@ -436,10 +456,9 @@ func TestMerge3Way_SimpleUpdates(t *testing.T) {
 				Title:     "Updated title",
 				Status:    "open",
 				Priority:  2,
-				CreatedAt: "2024-01-01T00:00:00Z",
-				UpdatedAt: "2024-01-02T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
+				UpdatedAt: ts("2024-01-02T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Updated title","status":"open","priority":2,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		right := base
@@ -464,10 +483,9 @@ func TestMerge3Way_SimpleUpdates(t *testing.T) {
 				Title:     "Original title",
 				Status:    "in_progress",
 				Priority:  2,
-				CreatedAt: "2024-01-01T00:00:00Z",
-				UpdatedAt: "2024-01-02T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
+				UpdatedAt: ts("2024-01-02T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Original title","status":"in_progress","priority":2,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -490,10 +508,9 @@ func TestMerge3Way_SimpleUpdates(t *testing.T) {
 				Title:     "Updated title",
 				Status:    "open",
 				Priority:  2,
-				CreatedAt: "2024-01-01T00:00:00Z",
-				UpdatedAt: "2024-01-02T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
+				UpdatedAt: ts("2024-01-02T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Updated title","status":"open","priority":2,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		right := []Issue{
@@ -502,10 +519,9 @@ func TestMerge3Way_SimpleUpdates(t *testing.T) {
 				Title:     "Original title",
 				Status:    "in_progress",
 				Priority:  2,
-				CreatedAt: "2024-01-01T00:00:00Z",
-				UpdatedAt: "2024-01-02T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
+				UpdatedAt: ts("2024-01-02T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Original title","status":"in_progress","priority":2,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -654,30 +670,27 @@ func TestMerge3Way_AutoResolve(t *testing.T) {
 			{
 				ID:        "bd-abc123",
 				Title:     "Original",
-				UpdatedAt: "2024-01-01T00:00:00Z",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				UpdatedAt: ts("2024-01-01T00:00:00Z"),
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Original","updated_at":"2024-01-01T00:00:00Z","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		left := []Issue{
 			{
 				ID:        "bd-abc123",
 				Title:     "Left version",
-				UpdatedAt: "2024-01-02T00:00:00Z", // Older
-				CreatedAt: "2024-01-01T00:00:00Z",
+				UpdatedAt: ts("2024-01-02T00:00:00Z"), // Older
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Left version","updated_at":"2024-01-02T00:00:00Z","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		right := []Issue{
 			{
 				ID:        "bd-abc123",
 				Title:     "Right version",
-				UpdatedAt: "2024-01-03T00:00:00Z", // Newer - this should win
-				CreatedAt: "2024-01-01T00:00:00Z",
+				UpdatedAt: ts("2024-01-03T00:00:00Z"), // Newer - this should win
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Right version","updated_at":"2024-01-03T00:00:00Z","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -699,27 +712,24 @@ func TestMerge3Way_AutoResolve(t *testing.T) {
 			{
 				ID:        "bd-abc123",
 				Priority:  2,
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","priority":2,"created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		left := []Issue{
 			{
 				ID:        "bd-abc123",
 				Priority:  3, // Lower priority (higher number)
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","priority":3,"created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		right := []Issue{
 			{
 				ID:        "bd-abc123",
 				Priority:  1, // Higher priority (lower number) - this should win
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","priority":1,"created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -741,27 +751,24 @@ func TestMerge3Way_AutoResolve(t *testing.T) {
 			{
 				ID:        "bd-abc123",
 				Notes:     "Original notes",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","notes":"Original notes","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		left := []Issue{
 			{
 				ID:        "bd-abc123",
 				Notes:     "Left notes",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","notes":"Left notes","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		right := []Issue{
 			{
 				ID:        "bd-abc123",
 				Notes:     "Right notes",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","notes":"Right notes","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -784,27 +791,24 @@ func TestMerge3Way_AutoResolve(t *testing.T) {
 			{
 				ID:        "bd-abc123",
 				IssueType: "task",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","issue_type":"task","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		left := []Issue{
 			{
 				ID:        "bd-abc123",
 				IssueType: "bug", // Local change - should win
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","issue_type":"bug","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		right := []Issue{
 			{
 				ID:        "bd-abc123",
 				IssueType: "feature",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","issue_type":"feature","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -829,9 +833,8 @@ func TestMerge3Way_Deletions(t *testing.T) {
 			{
 				ID:        "bd-abc123",
 				Title:     "Will be deleted",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Will be deleted","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		left := []Issue{} // Deleted in left
@@ -851,9 +854,8 @@ func TestMerge3Way_Deletions(t *testing.T) {
 			{
 				ID:        "bd-abc123",
 				Title:     "Will be deleted",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Will be deleted","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		left := base     // Unchanged in left
@@ -874,9 +876,8 @@ func TestMerge3Way_Deletions(t *testing.T) {
 				ID:        "bd-abc123",
 				Title:     "Original",
 				Status:    "open",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Original","status":"open","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		left := []Issue{} // Deleted in left
@@ -885,9 +886,8 @@ func TestMerge3Way_Deletions(t *testing.T) {
 				ID:        "bd-abc123",
 				Title:     "Modified",
 				Status:    "in_progress",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Modified","status":"in_progress","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -906,9 +906,8 @@ func TestMerge3Way_Deletions(t *testing.T) {
 				ID:        "bd-abc123",
 				Title:     "Original",
 				Status:    "open",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Original","status":"open","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		left := []Issue{ // Modified in left
@@ -916,9 +915,8 @@ func TestMerge3Way_Deletions(t *testing.T) {
 				ID:        "bd-abc123",
 				Title:     "Modified",
 				Status:    "in_progress",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Modified","status":"in_progress","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		right := []Issue{} // Deleted in right
@@ -941,9 +939,8 @@ func TestMerge3Way_Additions(t *testing.T) {
 			{
 				ID:        "bd-abc123",
 				Title:     "New issue",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"New issue","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		right := []Issue{}
@@ -967,9 +964,8 @@ func TestMerge3Way_Additions(t *testing.T) {
 			{
 				ID:        "bd-abc123",
 				Title:     "New issue",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"New issue","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -992,9 +988,8 @@ func TestMerge3Way_Additions(t *testing.T) {
 			Title:     "New issue",
 			Status:    "open",
 			Priority:  2,
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
-			RawLine:   `{"id":"bd-abc123","title":"New issue","status":"open","priority":2,"created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 		}
 		left := []Issue{issueData}
 		right := []Issue{issueData}
@@ -1014,20 +1009,18 @@ func TestMerge3Way_Additions(t *testing.T) {
 			{
 				ID:        "bd-abc123",
 				Title:     "Left version",
-				UpdatedAt: "2024-01-02T00:00:00Z", // Older
-				CreatedAt: "2024-01-01T00:00:00Z",
+				UpdatedAt: ts("2024-01-02T00:00:00Z"), // Older
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Left version","updated_at":"2024-01-02T00:00:00Z","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		right := []Issue{
 			{
 				ID:        "bd-abc123",
 				Title:     "Right version",
-				UpdatedAt: "2024-01-03T00:00:00Z", // Newer - should win
-				CreatedAt: "2024-01-01T00:00:00Z",
+				UpdatedAt: ts("2024-01-03T00:00:00Z"), // Newer - should win
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-abc123","title":"Right version","updated_at":"2024-01-03T00:00:00Z","created_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -1055,11 +1048,10 @@ func TestMerge3Way_ResurrectionPrevention(t *testing.T) {
 				ID:        "bd-test",
 				Title:     "Test issue",
 				Status:    "closed",
-				ClosedAt:  "2024-01-02T00:00:00Z",
-				CreatedAt: "2024-01-01T00:00:00Z",
-				UpdatedAt: "2024-01-02T00:00:00Z",
+				ClosedAt:  tsp("2024-01-02T00:00:00Z"),
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
+				UpdatedAt: ts("2024-01-02T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-test","title":"Test issue","status":"closed","closed_at":"2024-01-02T00:00:00Z","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		// Left: still closed with closed_at
@@ -1070,11 +1062,10 @@ func TestMerge3Way_ResurrectionPrevention(t *testing.T) {
 				ID:        "bd-test",
 				Title:     "Test issue",
 				Status:    "open", // reopened
-				ClosedAt:  "",     // correctly removed
-				CreatedAt: "2024-01-01T00:00:00Z",
-				UpdatedAt: "2024-01-03T00:00:00Z",
+				ClosedAt:  nil, // correctly removed
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
+				UpdatedAt: ts("2024-01-03T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-test","title":"Test issue","status":"open","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-03T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 
@@ -1092,12 +1083,12 @@ func TestMerge3Way_ResurrectionPrevention(t *testing.T) {
 		}
 
 		// CRITICAL: If status is closed, closed_at MUST be set
-		if result[0].Status == "closed" && result[0].ClosedAt == "" {
+		if result[0].Status == "closed" && result[0].ClosedAt == nil {
 			t.Error("INVALID STATE: status='closed' but closed_at is empty")
 		}
 
 		// CRITICAL: If status is open, closed_at MUST be empty
-		if result[0].Status == "open" && result[0].ClosedAt != "" {
+		if result[0].Status == "open" && result[0].ClosedAt != nil {
 			t.Errorf("INVALID STATE: status='open' but closed_at='%s'", result[0].ClosedAt)
 		}
 	})
@@ -1109,11 +1100,10 @@ func TestMerge3Way_ResurrectionPrevention(t *testing.T) {
 				ID:        "bd-hv01",
 				Title:     "Test issue",
 				Status:    "open",
-				ClosedAt:  "",
-				CreatedAt: "2024-01-01T00:00:00Z",
-				UpdatedAt: "2024-01-01T00:00:00Z",
+				ClosedAt:  nil,
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
+				UpdatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-hv01","title":"Test issue","status":"open","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		// Left: issue is closed (newer)
@@ -1122,11 +1112,10 @@ func TestMerge3Way_ResurrectionPrevention(t *testing.T) {
 				ID:        "bd-hv01",
 				Title:     "Test issue",
 				Status:    "closed",
-				ClosedAt:  "2024-01-02T00:00:00Z",
-				CreatedAt: "2024-01-01T00:00:00Z",
-				UpdatedAt: "2024-01-02T00:00:00Z",
+				ClosedAt:  tsp("2024-01-02T00:00:00Z"),
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
+				UpdatedAt: ts("2024-01-02T00:00:00Z"),
 				CreatedBy: "user1",
-				RawLine:   `{"id":"bd-hv01","title":"Test issue","status":"closed","closed_at":"2024-01-02T00:00:00Z","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z","created_by":"user1"}`,
 			},
 		}
 		// Right: issue is still open (stale)
@@ -1143,12 +1132,12 @@ func TestMerge3Way_ResurrectionPrevention(t *testing.T) {
 		if result[0].Status != "closed" {
 			t.Errorf("expected status 'closed', got %q - issue was resurrected!", result[0].Status)
 		}
-		if result[0].ClosedAt == "" {
+		if result[0].ClosedAt == nil {
 			t.Error("expected closed_at to be set, got empty string")
 		}
 		// UpdatedAt should be the max (left's newer timestamp)
-		if result[0].UpdatedAt != "2024-01-02T00:00:00Z" {
-			t.Errorf("expected updated_at '2024-01-02T00:00:00Z', got %q", result[0].UpdatedAt)
+		if !result[0].UpdatedAt.Equal(ts("2024-01-02T00:00:00Z")) {
+			t.Errorf("expected updated_at '2024-01-02T00:00:00Z', got %v", result[0].UpdatedAt)
 		}
 	})
 }
@@ -1254,7 +1243,7 @@ func TestMerge3Way_Integration(t *testing.T) {
 func TestIsTombstone(t *testing.T) {
 	tests := []struct {
 		name     string
-		status   string
+		status   types.Status
 		expected bool
 	}{
 		{
@@ -1334,13 +1323,13 @@ func TestMergeTombstones(t *testing.T) {
 			left := Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: tt.leftDeletedAt,
+				DeletedAt: tsp(tt.leftDeletedAt),
 				DeletedBy: "user-left",
 			}
 			right := Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: tt.rightDeletedAt,
+				DeletedAt: tsp(tt.rightDeletedAt),
 				DeletedBy: "user-right",
 			}
 			result := mergeTombstones(left, right)
@@ -1362,8 +1351,8 @@ func TestMerge3Way_TombstoneVsLive(t *testing.T) {
 		Title:     "Original title",
 		Status:    "open",
 		Priority:  2,
-		CreatedAt: "2024-01-01T00:00:00Z",
-		UpdatedAt: "2024-01-01T00:00:00Z",
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
+		UpdatedAt: ts("2024-01-01T00:00:00Z"),
 		CreatedBy: "user1",
 	}
 
@@ -1373,10 +1362,10 @@ func TestMerge3Way_TombstoneVsLive(t *testing.T) {
 		Title:        "Original title",
 		Status:       StatusTombstone,
 		Priority:     2,
-		CreatedAt:    "2024-01-01T00:00:00Z",
-		UpdatedAt:    "2024-01-02T00:00:00Z",
+		CreatedAt:    ts("2024-01-01T00:00:00Z"),
+		UpdatedAt:    ts("2024-01-02T00:00:00Z"),
 		CreatedBy:    "user1",
-		DeletedAt:    time.Now().Add(-24 * time.Hour).Format(time.RFC3339), // 1 day ago
+		DeletedAt:    tsp(time.Now().Add(-24 * time.Hour).Format(time.RFC3339)), // 1 day ago
 		DeletedBy:    "user2",
 		DeleteReason: "Duplicate issue",
 		OriginalType: "task",
@@ -1388,10 +1377,10 @@ func TestMerge3Way_TombstoneVsLive(t *testing.T) {
 		Title:        "Original title",
 		Status:       StatusTombstone,
 		Priority:     2,
-		CreatedAt:    "2024-01-01T00:00:00Z",
-		UpdatedAt:    "2024-01-02T00:00:00Z",
+		CreatedAt:    ts("2024-01-01T00:00:00Z"),
+		UpdatedAt:    ts("2024-01-02T00:00:00Z"),
 		CreatedBy:    "user1",
-		DeletedAt:    time.Now().Add(-60 * 24 * time.Hour).Format(time.RFC3339), // 60 days ago
+		DeletedAt:    tsp(time.Now().Add(-60 * 24 * time.Hour).Format(time.RFC3339)), // 60 days ago
 		DeletedBy:    "user2",
 		DeleteReason: "Duplicate issue",
 		OriginalType: "task",
@@ -1403,8 +1392,8 @@ func TestMerge3Way_TombstoneVsLive(t *testing.T) {
 		Title:     "Updated title",
 		Status:    "in_progress",
 		Priority:  1,
-		CreatedAt: "2024-01-01T00:00:00Z",
-		UpdatedAt: "2024-01-03T00:00:00Z",
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
+		UpdatedAt: ts("2024-01-03T00:00:00Z"),
 		CreatedBy: "user1",
 	}
 
@@ -1486,7 +1475,7 @@ func TestMerge3Way_TombstoneVsTombstone(t *testing.T) {
 		ID:        "bd-abc123",
 		Title:     "Original title",
 		Status:    "open",
-		CreatedAt: "2024-01-01T00:00:00Z",
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
 		CreatedBy: "user1",
 	}
 
@@ -1495,9 +1484,9 @@ func TestMerge3Way_TombstoneVsTombstone(t *testing.T) {
 			ID:           "bd-abc123",
 			Title:        "Original title",
 			Status:       StatusTombstone,
-			CreatedAt:    "2024-01-01T00:00:00Z",
+			CreatedAt:    ts("2024-01-01T00:00:00Z"),
 			CreatedBy:    "user1",
-			DeletedAt:    "2024-01-02T00:00:00Z",
+			DeletedAt:    tsp("2024-01-02T00:00:00Z"),
 			DeletedBy:    "user-left",
 			DeleteReason: "Left reason",
 		}
@@ -1505,9 +1494,9 @@ func TestMerge3Way_TombstoneVsTombstone(t *testing.T) {
 			ID:           "bd-abc123",
 			Title:        "Original title",
 			Status:       StatusTombstone,
-			CreatedAt:    "2024-01-01T00:00:00Z",
+			CreatedAt:    ts("2024-01-01T00:00:00Z"),
 			CreatedBy:    "user1",
-			DeletedAt:    "2024-01-03T00:00:00Z", // Later
+			DeletedAt:    tsp("2024-01-03T00:00:00Z"), // Later
 			DeletedBy:    "user-right",
 			DeleteReason: "Right reason",
 		}
@@ -1539,9 +1528,9 @@ func TestMerge3Way_TombstoneNoBase(t *testing.T) {
 			ID:        "bd-abc123",
 			Title:     "New tombstone",
 			Status:    StatusTombstone,
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
-			DeletedAt: "2024-01-02T00:00:00Z",
+			DeletedAt: tsp("2024-01-02T00:00:00Z"),
 			DeletedBy: "user1",
 		}
 
@@ -1562,9 +1551,9 @@ func TestMerge3Way_TombstoneNoBase(t *testing.T) {
 			ID:        "bd-abc123",
 			Title:     "New tombstone",
 			Status:    StatusTombstone,
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
-			DeletedAt: "2024-01-02T00:00:00Z",
+			DeletedAt: tsp("2024-01-02T00:00:00Z"),
 			DeletedBy: "user1",
 		}
 
@@ -1585,16 +1574,16 @@ func TestMerge3Way_TombstoneNoBase(t *testing.T) {
 			ID:        "bd-abc123",
 			Title:     "Issue",
 			Status:    StatusTombstone,
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
-			DeletedAt: time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+			DeletedAt: tsp(time.Now().Add(-24 * time.Hour).Format(time.RFC3339)),
 			DeletedBy: "user1",
 		}
 		live := Issue{
 			ID:        "bd-abc123",
 			Title:     "Issue",
 			Status:    "open",
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
 		}
 
@@ -1618,7 +1607,7 @@ func TestMerge3WayWithTTL(t *testing.T) {
 		ID:        "bd-abc123",
 		Title:     "Original",
 		Status:    "open",
-		CreatedAt: "2024-01-01T00:00:00Z",
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
 		CreatedBy: "user1",
 	}
 
@@ -1627,9 +1616,9 @@ func TestMerge3WayWithTTL(t *testing.T) {
 		ID:        "bd-abc123",
 		Title:     "Original",
 		Status:    StatusTombstone,
-		CreatedAt: "2024-01-01T00:00:00Z",
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
 		CreatedBy: "user1",
-		DeletedAt: time.Now().Add(-10 * 24 * time.Hour).Format(time.RFC3339),
+		DeletedAt: tsp(time.Now().Add(-10 * 24 * time.Hour).Format(time.RFC3339)),
 		DeletedBy: "user2",
 	}
 
@@ -1637,7 +1626,7 @@ func TestMerge3WayWithTTL(t *testing.T) {
 		ID:        "bd-abc123",
 		Title:     "Updated",
 		Status:    "open",
-		CreatedAt: "2024-01-01T00:00:00Z",
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
 		CreatedBy: "user1",
 	}
 
@@ -1680,10 +1669,10 @@ func TestMerge3WayWithTTL(t *testing.T) {
 func TestMergeStatus_Tombstone(t *testing.T) {
 	tests := []struct {
 		name     string
-		base     string
-		left     string
-		right    string
-		expected string
+		base     types.Status
+		left     types.Status
+		right    types.Status
+		expected types.Status
 	}{
 		{
 			name:     "tombstone in left wins over open in right",
@@ -1733,7 +1722,7 @@ func TestMerge3Way_TombstoneWithImplicitDeletion(t *testing.T) {
 		ID:        "bd-abc123",
 		Title:     "Original",
 		Status:    "open",
-		CreatedAt: "2024-01-01T00:00:00Z",
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
 		CreatedBy: "user1",
 	}
 
@@ -1741,9 +1730,9 @@ func TestMerge3Way_TombstoneWithImplicitDeletion(t *testing.T) {
 		ID:           "bd-abc123",
 		Title:        "Original",
 		Status:       StatusTombstone,
-		CreatedAt:    "2024-01-01T00:00:00Z",
+		CreatedAt:    ts("2024-01-01T00:00:00Z"),
 		CreatedBy:    "user1",
-		DeletedAt:    time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+		DeletedAt:    tsp(time.Now().Add(-24 * time.Hour).Format(time.RFC3339)),
 		DeletedBy:    "user2",
 		DeleteReason: "Duplicate",
 	}
@@ -1791,7 +1780,7 @@ func TestMerge3Way_TombstoneWithImplicitDeletion(t *testing.T) {
 			ID:        "bd-abc123",
 			Title:     "Modified",
 			Status:    "in_progress",
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
 		}
 		left := []Issue{modifiedLive}
@@ -1842,13 +1831,13 @@ func TestMergeTombstones_EmptyDeletedAt(t *testing.T) {
 			left := Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: tt.leftDeletedAt,
+				DeletedAt: tsp(tt.leftDeletedAt),
 				DeletedBy: "user-left",
 			}
 			right := Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: tt.rightDeletedAt,
+				DeletedAt: tsp(tt.rightDeletedAt),
 				DeletedBy: "user-right",
 			}
 			result := mergeTombstones(left, right)
@@ -1869,15 +1858,15 @@ func TestMergeIssue_TombstoneFields(t *testing.T) {
 		base := Issue{
 			ID:        "bd-test",
 			Status:    "open",
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
 		}
 		left := Issue{
 			ID:           "bd-test",
 			Status:       StatusTombstone,
-			CreatedAt:    "2024-01-01T00:00:00Z",
+			CreatedAt:    ts("2024-01-01T00:00:00Z"),
 			CreatedBy:    "user1",
-			DeletedAt:    "2024-01-02T00:00:00Z",
+			DeletedAt:    tsp("2024-01-02T00:00:00Z"),
 			DeletedBy:    "user2",
 			DeleteReason: "Duplicate",
 			OriginalType: "task",
@@ -1885,7 +1874,7 @@ func TestMergeIssue_TombstoneFields(t *testing.T) {
 		right := Issue{
 			ID:        "bd-test",
 			Status:    "open",
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
 		}
 
@@ -1893,8 +1882,8 @@ func TestMergeIssue_TombstoneFields(t *testing.T) {
 		if result.Status != StatusTombstone {
 			t.Errorf("expected tombstone status, got %q", result.Status)
 		}
-		if result.DeletedAt != "2024-01-02T00:00:00Z" {
-			t.Errorf("expected DeletedAt to be copied, got %q", result.DeletedAt)
+		if result.DeletedAt == nil || !result.DeletedAt.Equal(ts("2024-01-02T00:00:00Z")) {
+			t.Errorf("expected DeletedAt to be copied, got %v", result.DeletedAt)
 		}
 		if result.DeletedBy != "user2" {
 			t.Errorf("expected DeletedBy to be copied, got %q", result.DeletedBy)
@@ -1911,24 +1900,24 @@ func TestMergeIssue_TombstoneFields(t *testing.T) {
 		base := Issue{
 			ID:        "bd-test",
 			Status:    "open",
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1",
 		}
 		left := Issue{
 			ID:           "bd-test",
 			Status:       StatusTombstone,
-			CreatedAt:    "2024-01-01T00:00:00Z",
+			CreatedAt:    ts("2024-01-01T00:00:00Z"),
 			CreatedBy:    "user1",
-			DeletedAt:    "2024-01-02T00:00:00Z",
+			DeletedAt:    tsp("2024-01-02T00:00:00Z"),
 			DeletedBy:    "user-left",
 			DeleteReason: "Left reason",
 		}
 		right := Issue{
 			ID:           "bd-test",
 			Status:       StatusTombstone,
-			CreatedAt:    "2024-01-01T00:00:00Z",
+			CreatedAt:    ts("2024-01-01T00:00:00Z"),
 			CreatedBy:    "user1",
-			DeletedAt:    "2024-01-03T00:00:00Z", // Later
+			DeletedAt:    tsp("2024-01-03T00:00:00Z"), // Later
 			DeletedBy:    "user-right",
 			DeleteReason: "Right reason",
 		}
@@ -1962,7 +1951,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    "open",
-				DeletedAt: now.Add(-100 * 24 * time.Hour).Format(time.RFC3339),
+				DeletedAt: tsp(now.Add(-100 * 24 * time.Hour).Format(time.RFC3339)),
 			},
 			ttl:      24 * time.Hour,
 			expected: false,
@@ -1972,7 +1961,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    "closed",
-				DeletedAt: now.Add(-100 * 24 * time.Hour).Format(time.RFC3339),
+				DeletedAt: tsp(now.Add(-100 * 24 * time.Hour).Format(time.RFC3339)),
 			},
 			ttl:      24 * time.Hour,
 			expected: false,
@@ -1982,27 +1971,17 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: "",
-			},
-			ttl:      24 * time.Hour,
-			expected: false,
-		},
-		{
-			name: "tombstone with invalid timestamp returns false (safety)",
-			issue: Issue{
-				ID:        "bd-test",
-				Status:    StatusTombstone,
-				DeletedAt: "not-a-valid-date",
+				DeletedAt: nil,
 			},
 			ttl:      24 * time.Hour,
 			expected: false,
 		},
 		{
-			name: "tombstone with malformed RFC3339 returns false",
+			name: "tombstone with zero deleted_at returns false (safety)",
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: "2024-13-45T99:99:99Z",
+				DeletedAt: &time.Time{},
 			},
 			ttl:      24 * time.Hour,
 			expected: false,
@@ -2012,7 +1991,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: now.Add(-1 * time.Hour).Format(time.RFC3339),
+				DeletedAt: tsp(now.Add(-1 * time.Hour).Format(time.RFC3339)),
 			},
 			ttl:      24 * time.Hour,
 			expected: false,
@@ -2022,7 +2001,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: now.Add(-48 * time.Hour).Format(time.RFC3339),
+				DeletedAt: tsp(now.Add(-48 * time.Hour).Format(time.RFC3339)),
 			},
 			ttl:      24 * time.Hour,
 			expected: true,
@@ -2032,7 +2011,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: now.Add(-24 * time.Hour).Format(time.RFC3339),
+				DeletedAt: tsp(now.Add(-24 * time.Hour).Format(time.RFC3339)),
 			},
 			ttl:      24 * time.Hour,
 			expected: false,
@@ -2042,7 +2021,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: now.Add(-26 * time.Hour).Format(time.RFC3339),
+				DeletedAt: tsp(now.Add(-26 * time.Hour).Format(time.RFC3339)),
 			},
 			ttl:      24 * time.Hour,
 			expected: true,
@@ -2052,7 +2031,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: now.Add(-20 * 24 * time.Hour).Format(time.RFC3339),
+				DeletedAt: tsp(now.Add(-20 * 24 * time.Hour).Format(time.RFC3339)),
 			},
 			ttl:      0,
 			expected: false,
@@ -2062,7 +2041,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: now.Add(-60 * 24 * time.Hour).Format(time.RFC3339),
+				DeletedAt: tsp(now.Add(-60 * 24 * time.Hour).Format(time.RFC3339)),
 			},
 			ttl:      0,
 			expected: true,
@@ -2072,7 +2051,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: now.Add(-48 * time.Hour).Format(time.RFC3339Nano),
+				DeletedAt: tsp(now.Add(-48 * time.Hour).Format(time.RFC3339Nano)),
 			},
 			ttl:      24 * time.Hour,
 			expected: true,
@@ -2082,7 +2061,7 @@ func TestIsExpiredTombstone(t *testing.T) {
 			issue: Issue{
 				ID:        "bd-test",
 				Status:    StatusTombstone,
-				DeletedAt: now.Add(-2 * time.Hour).Format(time.RFC3339),
+				DeletedAt: tsp(now.Add(-2 * time.Hour).Format(time.RFC3339)),
 			},
 			ttl:      1 * time.Minute,
 			expected: true,
@@ -2111,10 +2090,10 @@ func TestMerge3Way_TombstoneBaseBothLiveResurrection(t *testing.T) {
 		Title:        "Original title",
 		Status:       StatusTombstone,
 		Priority:     2,
-		CreatedAt:    "2024-01-01T00:00:00Z",
-		UpdatedAt:    "2024-01-05T00:00:00Z",
+		CreatedAt:    ts("2024-01-01T00:00:00Z"),
+		UpdatedAt:    ts("2024-01-05T00:00:00Z"),
 		CreatedBy:    "user1",
-		DeletedAt:    time.Now().Add(-10 * 24 * time.Hour).Format(time.RFC3339), // 10 days ago
+		DeletedAt:    tsp(time.Now().Add(-10 * 24 * time.Hour).Format(time.RFC3339)), // 10 days ago
 		DeletedBy:    "user2",
 		DeleteReason: "Obsolete",
 		OriginalType: "task",
@@ -2127,8 +2106,8 @@ func TestMerge3Way_TombstoneBaseBothLiveResurrection(t *testing.T) {
 		Status:    "open",
 		Priority:  2,
 		IssueType: "task",
-		CreatedAt: "2024-01-01T00:00:00Z",
-		UpdatedAt: "2024-01-10T00:00:00Z", // Left is older
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
+		UpdatedAt: ts("2024-01-10T00:00:00Z"), // Left is older
 		CreatedBy: "user1",
 	}
 
@@ -2139,8 +2118,8 @@ func TestMerge3Way_TombstoneBaseBothLiveResurrection(t *testing.T) {
 		Status:    "in_progress",
 		Priority:  1, // Higher priority (lower number)
 		IssueType: "bug",
-		CreatedAt: "2024-01-01T00:00:00Z",
-		UpdatedAt: "2024-01-15T00:00:00Z", // Right is newer
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
+		UpdatedAt: ts("2024-01-15T00:00:00Z"), // Right is newer
 		CreatedBy: "user1",
 	}
 
@@ -2184,7 +2163,7 @@ func TestMerge3Way_TombstoneBaseBothLiveResurrection(t *testing.T) {
 		}
 
 		// Tombstone fields should NOT be present on merged result
-		if merged.DeletedAt != "" {
+		if merged.DeletedAt != nil {
 			t.Errorf("expected empty DeletedAt on resurrected issue, got %q", merged.DeletedAt)
 		}
 		if merged.DeletedBy != "" {
@@ -2223,7 +2202,7 @@ func TestMerge3Way_TombstoneBaseBothLiveResurrection(t *testing.T) {
 		// Right resurrects and then closes
 		rightClosed := rightLive
 		rightClosed.Status = "closed"
-		rightClosed.ClosedAt = "2024-01-16T00:00:00Z"
+		rightClosed.ClosedAt = tsp("2024-01-16T00:00:00Z")
 
 		base := []Issue{baseTombstone}
 		left := []Issue{leftOpen}
@@ -2259,8 +2238,8 @@ func TestMerge3Way_TombstoneVsLiveTimestampPrecisionMismatch(t *testing.T) {
 			Title:     "Original title",
 			Status:    "closed",
 			Priority:  2,
-			CreatedAt: "2024-01-01T00:00:00Z", // No fractional seconds
-			UpdatedAt: "2024-01-10T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"), // No fractional seconds
+			UpdatedAt: ts("2024-01-10T00:00:00Z"),
 			CreatedBy: "user1",
 		}
 
@@ -2270,10 +2249,10 @@ func TestMerge3Way_TombstoneVsLiveTimestampPrecisionMismatch(t *testing.T) {
 			Title:        "(deleted)",
 			Status:       StatusTombstone,
 			Priority:     2,
-			CreatedAt:    "2024-01-01T00:00:00.000000Z", // WITH fractional seconds
-			UpdatedAt:    "2024-01-15T00:00:00Z",
+			CreatedAt:    ts("2024-01-01T00:00:00.000000Z"), // WITH fractional seconds
+			UpdatedAt:    ts("2024-01-15T00:00:00Z"),
 			CreatedBy:    "user1",
-			DeletedAt:    time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+			DeletedAt:    tsp(time.Now().Add(-24 * time.Hour).Format(time.RFC3339)),
 			DeletedBy:    "user2",
 			DeleteReason: "Duplicate issue",
 		}
@@ -2284,8 +2263,8 @@ func TestMerge3Way_TombstoneVsLiveTimestampPrecisionMismatch(t *testing.T) {
 			Title:     "Original title",
 			Status:    "closed",
 			Priority:  2,
-			CreatedAt: "2024-01-01T00:00:00Z", // No fractional seconds
-			UpdatedAt: "2024-01-12T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"), // No fractional seconds
+			UpdatedAt: ts("2024-01-12T00:00:00Z"),
 			CreatedBy: "user1",
 		}
 
@@ -2320,9 +2299,9 @@ func TestMerge3Way_TombstoneVsLiveTimestampPrecisionMismatch(t *testing.T) {
 			Title:        "(deleted)",
 			Status:       StatusTombstone,
 			Priority:     2,
-			CreatedAt:    "2024-01-01T00:00:00Z",
+			CreatedAt:    ts("2024-01-01T00:00:00Z"),
 			CreatedBy:    "", // Empty CreatedBy
-			DeletedAt:    time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+			DeletedAt:    tsp(time.Now().Add(-24 * time.Hour).Format(time.RFC3339)),
 			DeletedBy:    "user2",
 			DeleteReason: "Cleanup",
 		}
@@ -2332,7 +2311,7 @@ func TestMerge3Way_TombstoneVsLiveTimestampPrecisionMismatch(t *testing.T) {
 			Title:     "Original title",
 			Status:    "closed",
 			Priority:  2,
-			CreatedAt: "2024-01-01T00:00:00Z",
+			CreatedAt: ts("2024-01-01T00:00:00Z"),
 			CreatedBy: "user1", // Non-empty CreatedBy
 		}
 
@@ -2363,7 +2342,7 @@ func TestMerge3Way_TombstoneVsLiveTimestampPrecisionMismatch(t *testing.T) {
 			ID:        "bd-ghost3",
 			Title:     "Left version",
 			Status:    "open",
-			CreatedAt: "2024-01-01T00:00:00.123456Z", // With nanoseconds
+			CreatedAt: ts("2024-01-01T00:00:00.123456Z"), // With nanoseconds
 			CreatedBy: "user1",
 		}
 
@@ -2371,7 +2350,7 @@ func TestMerge3Way_TombstoneVsLiveTimestampPrecisionMismatch(t *testing.T) {
 			ID:        "bd-ghost3",
 			Title:     "Right version",
 			Status:    "in_progress",
-			CreatedAt: "2024-01-01T00:00:00Z", // Without nanoseconds
+			CreatedAt: ts("2024-01-01T00:00:00Z"), // Without nanoseconds
 			CreatedBy: "user1",
 		}
 
@@ -2401,11 +2380,11 @@ func TestMerge3Way_TombstoneVsLiveTimestampPrecisionMismatch(t *testing.T) {
 func TestMerge3Way_DeterministicOutputOrder(t *testing.T) {
 	// Create issues with IDs that would appear in different orders
 	// if map iteration order determined output order
-	issueA := Issue{ID: "beads-aaa", Title: "A", Status: "open", CreatedAt: "2024-01-01T00:00:00Z"}
-	issueB := Issue{ID: "beads-bbb", Title: "B", Status: "open", CreatedAt: "2024-01-02T00:00:00Z"}
-	issueC := Issue{ID: "beads-ccc", Title: "C", Status: "open", CreatedAt: "2024-01-03T00:00:00Z"}
-	issueZ := Issue{ID: "beads-zzz", Title: "Z", Status: "open", CreatedAt: "2024-01-04T00:00:00Z"}
-	issueM := Issue{ID: "beads-mmm", Title: "M", Status: "open", CreatedAt: "2024-01-05T00:00:00Z"}
+	issueA := Issue{ID: "beads-aaa", Title: "A", Status: "open", CreatedAt: ts("2024-01-01T00:00:00Z")}
+	issueB := Issue{ID: "beads-bbb", Title: "B", Status: "open", CreatedAt: ts("2024-01-02T00:00:00Z")}
+	issueC := Issue{ID: "beads-ccc", Title: "C", Status: "open", CreatedAt: ts("2024-01-03T00:00:00Z")}
+	issueZ := Issue{ID: "beads-zzz", Title: "Z", Status: "open", CreatedAt: ts("2024-01-04T00:00:00Z")}
+	issueM := Issue{ID: "beads-mmm", Title: "M", Status: "open", CreatedAt: ts("2024-01-05T00:00:00Z")}
 
 	t.Run("output is sorted by ID", func(t *testing.T) {
 		// Input in arbitrary (non-sorted) order
@@ -2469,7 +2448,7 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:        "bd-close1",
 				Title:     "Test Issue",
 				Status:    "open",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
 			},
 		}
@@ -2478,10 +2457,10 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:              "bd-close1",
 				Title:           "Test Issue",
 				Status:          "closed",
-				ClosedAt:        "2024-01-02T00:00:00Z", // Earlier
+				ClosedAt:        tsp("2024-01-02T00:00:00Z"), // Earlier
 				CloseReason:     "Fixed in commit abc",
 				ClosedBySession: "session-left",
-				CreatedAt:       "2024-01-01T00:00:00Z",
+				CreatedAt:       ts("2024-01-01T00:00:00Z"),
 				CreatedBy:       "user1",
 			},
 		}
@@ -2490,10 +2469,10 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:              "bd-close1",
 				Title:           "Test Issue",
 				Status:          "closed",
-				ClosedAt:        "2024-01-03T00:00:00Z", // Later - should win
+				ClosedAt:        tsp("2024-01-03T00:00:00Z"), // Later - should win
 				CloseReason:     "Fixed in commit xyz",
 				ClosedBySession: "session-right",
-				CreatedAt:       "2024-01-01T00:00:00Z",
+				CreatedAt:       ts("2024-01-01T00:00:00Z"),
 				CreatedBy:       "user1",
 			},
 		}
@@ -2520,7 +2499,7 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:        "bd-close2",
 				Title:     "Test Issue",
 				Status:    "open",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
 			},
 		}
@@ -2529,10 +2508,10 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:              "bd-close2",
 				Title:           "Test Issue",
 				Status:          "closed",
-				ClosedAt:        "2024-01-03T00:00:00Z", // Later - should win
+				ClosedAt:        tsp("2024-01-03T00:00:00Z"), // Later - should win
 				CloseReason:     "Resolved by PR #123",
 				ClosedBySession: "session-left",
-				CreatedAt:       "2024-01-01T00:00:00Z",
+				CreatedAt:       ts("2024-01-01T00:00:00Z"),
 				CreatedBy:       "user1",
 			},
 		}
@@ -2541,10 +2520,10 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:              "bd-close2",
 				Title:           "Test Issue",
 				Status:          "closed",
-				ClosedAt:        "2024-01-02T00:00:00Z", // Earlier
+				ClosedAt:        tsp("2024-01-02T00:00:00Z"), // Earlier
 				CloseReason:     "Duplicate",
 				ClosedBySession: "session-right",
-				CreatedAt:       "2024-01-01T00:00:00Z",
+				CreatedAt:       ts("2024-01-01T00:00:00Z"),
 				CreatedBy:       "user1",
 			},
 		}
@@ -2571,10 +2550,10 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:              "bd-close3",
 				Title:           "Test Issue",
 				Status:          "closed",
-				ClosedAt:        "2024-01-02T00:00:00Z",
+				ClosedAt:        tsp("2024-01-02T00:00:00Z"),
 				CloseReason:     "Fixed",
 				ClosedBySession: "session-old",
-				CreatedAt:       "2024-01-01T00:00:00Z",
+				CreatedAt:       ts("2024-01-01T00:00:00Z"),
 				CreatedBy:       "user1",
 			},
 		}
@@ -2583,10 +2562,10 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:              "bd-close3",
 				Title:           "Test Issue",
 				Status:          "open", // Reopened
-				ClosedAt:        "",
+				ClosedAt:        nil,
 				CloseReason:     "", // Should be cleared
 				ClosedBySession: "",
-				CreatedAt:       "2024-01-01T00:00:00Z",
+				CreatedAt:       ts("2024-01-01T00:00:00Z"),
 				CreatedBy:       "user1",
 			},
 		}
@@ -2595,10 +2574,10 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:              "bd-close3",
 				Title:           "Test Issue",
 				Status:          "open", // Both reopened
-				ClosedAt:        "",
+				ClosedAt:        nil,
 				CloseReason:     "",
 				ClosedBySession: "",
-				CreatedAt:       "2024-01-01T00:00:00Z",
+				CreatedAt:       ts("2024-01-01T00:00:00Z"),
 				CreatedBy:       "user1",
 			},
 		}
@@ -2627,7 +2606,7 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:        "bd-close4",
 				Title:     "Test Issue",
 				Status:    "open",
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
 			},
 		}
@@ -2636,10 +2615,10 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:              "bd-close4",
 				Title:           "Test Issue",
 				Status:          "closed",
-				ClosedAt:        "2024-01-02T00:00:00Z",
+				ClosedAt:        tsp("2024-01-02T00:00:00Z"),
 				CloseReason:     "Won't fix - by design",
 				ClosedBySession: "session-abc",
-				CreatedAt:       "2024-01-01T00:00:00Z",
+				CreatedAt:       ts("2024-01-01T00:00:00Z"),
 				CreatedBy:       "user1",
 			},
 		}
@@ -2648,7 +2627,7 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 				ID:        "bd-close4",
 				Title:     "Test Issue",
 				Status:    "open", // Still open on right
-				CreatedAt: "2024-01-01T00:00:00Z",
+				CreatedAt: ts("2024-01-01T00:00:00Z"),
 				CreatedBy: "user1",
 			},
 		}
@@ -2722,3 +2701,231 @@ func TestMerge3Way_CloseReasonPreservation(t *testing.T) {
 		}
 	})
 }
+
+// TestFieldRulesCoverIssue fails when a field is added to types.Issue
+// without a merge rule. Add the field to fieldRules (see mergeRule for the
+// options) so the merge driver doesn't drop or mis-merge it.
+func TestFieldRulesCoverIssue(t *testing.T) {
+	issueType := reflect.TypeOf(types.Issue{})
+	exported := make(map[string]bool)
+	for i := 0; i < issueType.NumField(); i++ {
+		f := issueType.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		exported[name] = true
+		t.Run(name, func(t *testing.T) {
+			if _, ok := fieldRules[name]; !ok {
+				t.Errorf("types.Issue.%s (%q) has no merge rule in fieldRules", f.Name, name)
+			}
+		})
+	}
+	for name := range fieldRules {
+		if !exported[name] {
+			t.Errorf("fieldRules has a rule for %q, which is not a JSONL field of types.Issue", name)
+		}
+	}
+}
+
+// TestMerge3Way_FullFidelity checks that every field changed on one side
+// survives the merge, including fields the driver has no special rule for.
+func TestMerge3Way_FullFidelity(t *testing.T) {
+	ref := "gh-42"
+	minutes := 90
+	score := float32(0.8)
+	base := Issue{
+		ID:        "bd-full",
+		Title:     "Original",
+		Status:    "open",
+		Priority:  2,
+		IssueType: "task",
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
+		CreatedBy: "alice",
+		UpdatedAt: ts("2024-01-01T00:00:00Z"),
+	}
+	left := base
+	left.Title = "Changed"
+	left.Design = "design notes"
+	left.AcceptanceCriteria = "it works"
+	left.Assignee = "bob"
+	left.Owner = "bob@example.com"
+	left.EstimatedMinutes = &minutes
+	left.UpdatedAt = ts("2024-01-03T00:00:00Z")
+	left.DueAt = tsp("2024-02-01T00:00:00Z")
+	left.ExternalRef = &ref
+	left.SourceSystem = "github"
+	left.Labels = []string{"backend", "urgent"}
+	left.Comments = []*types.Comment{{ID: 7, IssueID: "bd-full", Author: "bob", Text: "on it", CreatedAt: ts("2024-01-02T00:00:00Z")}}
+	left.Pinned = true
+	left.QualityScore = &score
+	left.Creator = &types.EntityRef{Name: "polecat/Nux", Platform: "gastown"}
+	left.AwaitType = "gh:pr"
+	left.AwaitID = "123"
+	left.Timeout = time.Hour
+	left.Waiters = []string{"mayor/"}
+	left.HookBead = "bd-hook"
+	left.AgentState = types.StateWorking
+	left.LastActivity = tsp("2024-01-03T00:00:00Z")
+	left.MolType = types.MolTypeSwarm
+	left.WorkType = types.WorkTypeOpenCompetition
+	left.EventKind = "agent.started"
+	left.Payload = `{"k":"v"}`
+
+	result, conflicts := merge3Way([]Issue{base}, []Issue{left}, []Issue{base}, false)
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %v", conflicts)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 issue, got %d", len(result))
+	}
+
+	got, _ := json.Marshal(result[0])
+	want, _ := json.Marshal(left)
+	if string(got) != string(want) {
+		t.Errorf("merged issue lost fields\n got: %s\nwant: %s", got, want)
+	}
+}
+
+// TestMergeStringSet tests labels and waiters: additions from both sides
+// are kept and removals win.
+func TestMergeStringSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     []string
+		left     []string
+		right    []string
+		expected []string
+	}{
+		{
+			name:     "both add different labels",
+			base:     []string{"a"},
+			left:     []string{"a", "b"},
+			right:    []string{"a", "c"},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "removal on one side wins",
+			base:     []string{"a", "b"},
+			left:     []string{"a"},
+			right:    []string{"a", "b", "c"},
+			expected: []string{"a", "c"},
+		},
+		{
+			name:     "both remove",
+			base:     []string{"a"},
+			left:     nil,
+			right:    nil,
+			expected: nil,
+		},
+		{
+			name:     "added on both sides without base",
+			base:     nil,
+			left:     []string{"x"},
+			right:    []string{"x"},
+			expected: []string{"x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := mergeStringSet(tt.base, tt.left, tt.right)
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("mergeStringSet() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMergeComments tests that comments merge by content with removals
+// winning.
+func TestMergeComments(t *testing.T) {
+	c := func(id int64, text, at string) *types.Comment {
+		return &types.Comment{ID: id, IssueID: "bd-1", Author: "alice", Text: text, CreatedAt: ts(at)}
+	}
+	first := c(1, "first", "2024-01-01T00:00:00Z")
+	second := c(2, "second", "2024-01-02T00:00:00Z")
+	// Each clone numbered its new comment 3; both are kept
+	fromLeft := c(3, "from left", "2024-01-04T00:00:00Z")
+	fromRight := c(3, "from right", "2024-01-03T00:00:00Z")
+	// The same comment imported with different IDs is kept once
+	unkeyed := c(0, "imported", "2024-01-05T00:00:00Z")
+
+	base := []*types.Comment{first, second}
+	left := []*types.Comment{first, second, fromLeft, unkeyed}
+	right := []*types.Comment{first, fromRight, c(5, "imported", "2024-01-05T00:00:00Z")} // right deleted "second"
+
+	result := mergeComments(base, left, right)
+	var texts []string
+	for _, comment := range result {
+		texts = append(texts, comment.Text)
+	}
+	expected := []string{"first", "from right", "from left", "imported"}
+	if !slices.Equal(texts, expected) {
+		t.Errorf("mergeComments() = %v, want %v", texts, expected)
+	}
+}
+
+// TestMergeIssue_ConflictRules tests the rules applied when both sides
+// change the same field.
+func TestMergeIssue_ConflictRules(t *testing.T) {
+	base := Issue{
+		ID:        "bd-1",
+		Title:     "base",
+		Assignee:  "base",
+		IssueType: "task",
+		CreatedAt: ts("2024-01-01T00:00:00Z"),
+		UpdatedAt: ts("2024-01-01T00:00:00Z"),
+	}
+	left := base
+	left.Title = "left"
+	left.Assignee = "left"
+	left.IssueType = "bug"
+	left.UpdatedAt = ts("2024-01-02T00:00:00Z")
+	left.CompactionLevel = 1
+	left.OriginalSize = 100
+	right := base
+	right.Title = "right"
+	right.Assignee = "right"
+	right.IssueType = "feature"
+	right.UpdatedAt = ts("2024-01-03T00:00:00Z")
+	right.CompactionLevel = 2
+	right.OriginalSize = 50
+	right.CompactedAt = tsp("2024-01-03T00:00:00Z")
+
+	result, _ := mergeIssue(base, left, right)
+	if result.Title != "right" || result.Assignee != "right" {
+		t.Errorf("ruleLatest: expected right's values (newer updated_at), got title=%q assignee=%q", result.Title, result.Assignee)
+	}
+	if result.IssueType != "bug" {
+		t.Errorf("ruleLocal: expected left's issue_type, got %q", result.IssueType)
+	}
+	if result.CompactionLevel != 2 || result.OriginalSize != 50 || result.CompactedAt == nil {
+		t.Errorf("ruleCompaction: expected right's compaction group, got level=%d size=%d at=%v",
+			result.CompactionLevel, result.OriginalSize, result.CompactedAt)
+	}
+	if !result.UpdatedAt.Equal(right.UpdatedAt) {
+		t.Errorf("ruleMaxTime: expected updated_at %v, got %v", right.UpdatedAt, result.UpdatedAt)
+	}
+}
+
+// TestMerge3Way_InvalidTimestamp tests that a malformed timestamp fails the
+// merge instead of being silently dropped.
+func TestMerge3Way_InvalidTimestamp(t *testing.T) {
+	tmpDir := t.TempDir()
+	good := `{"id":"bd-1","title":"ok","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`
+	bad := `{"id":"bd-1","title":"ok","created_at":"2024-01-01T00:00:00Z","updated_at":"not-a-valid-date"}`
+
+	paths := map[string]string{"base": good, "left": bad, "right": good}
+	for name, content := range paths {
+		if err := os.WriteFile(filepath.Join(tmpDir, name+".jsonl"), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := Merge3Way(filepath.Join(tmpDir, "out.jsonl"), filepath.Join(tmpDir, "base.jsonl"),
+		filepath.Join(tmpDir, "left.jsonl"), filepath.Join(tmpDir, "right.jsonl"), false)
+	if err == nil || !strings.Contains(err.Error(), "left") {
+		t.Errorf("expected error reading left file, got %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package merge
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Strategy is a per-field merge policy that overrides the built-in rule in
+// fieldRules. Like the built-in rules, a strategy only decides true
+// conflicts: when just one side changed a field, that change wins.
+type Strategy string
+
+const (
+	StrategyLWW      Strategy = "lww"      // Side with the later updated_at wins (left on a tie)
+	StrategyUnion    Strategy = "union"    // Lists: keep every item from both sides
+	StrategyConcat   Strategy = "concat"   // Text: both values, separated by a rule
+	StrategyMax      Strategy = "max"      // Larger number, later time, or status further along StatusOrder
+	StrategyMin      Strategy = "min"      // Smaller number, earlier time, or status earlier in StatusOrder
+	StrategyLeft     Strategy = "left"     // Local (left) side wins
+	StrategyRight    Strategy = "right"    // Remote (right) side wins
+	StrategyConflict Strategy = "conflict" // Not resolved: the issue is written as a conflict
+)
+
+// ValidStrategies returns the strategy names accepted in config.
+func ValidStrategies() []string {
+	return []string{
+		string(StrategyLWW), string(StrategyUnion), string(StrategyConcat), string(StrategyMax),
+		string(StrategyMin), string(StrategyLeft), string(StrategyRight), string(StrategyConflict),
+	}
+}
+
+// DefaultStatusOrder ranks the built-in statuses along the usual workflow,
+// for the max and min strategies on status. Statuses not in the order rank
+// below all of them.
+var DefaultStatusOrder = []types.Status{
+	types.StatusOpen,
+	types.StatusPinned,
+	types.StatusDeferred,
+	types.StatusBlocked,
+	types.StatusHooked,
+	types.StatusInProgress,
+	types.StatusClosed,
+}
+
+// Policy holds the configured per-field strategies. A nil *Policy means the
+// built-in rules for every field.
+type Policy struct {
+	Fields      map[string]Strategy
+	StatusOrder []types.Status
+}
+
+// LoadPolicy builds the merge policy from the merge: section of
+// config.yaml. Returns nil (built-in rules) if nothing is configured.
+func LoadPolicy() (*Policy, error) {
+	cfg := config.GetMergeConfig()
+	if len(cfg.Fields) == 0 {
+		return nil, nil
+	}
+	policy, err := ParsePolicy(cfg.Fields, cfg.StatusOrder)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merge config: %w", err)
+	}
+	return policy, nil
+}
+
+// ParsePolicy validates per-field strategies (from the merge.fields config)
+// and a status order (merge.status-order; empty for DefaultStatusOrder).
+// It rejects unknown fields, fields whose value is derived from other
+// fields (identity, close, tombstone and compaction metadata), and
+// strategies that don't apply to the field's type.
+func ParsePolicy(fields map[string]string, statusOrder []string) (*Policy, error) {
+	p := &Policy{Fields: make(map[string]Strategy), StatusOrder: DefaultStatusOrder}
+	if len(statusOrder) > 0 {
+		p.StatusOrder = nil
+		for _, s := range statusOrder {
+			p.StatusOrder = append(p.StatusOrder, types.Status(strings.TrimSpace(s)))
+		}
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		strategy := Strategy(strings.ToLower(strings.TrimSpace(fields[name])))
+		if !slices.Contains(ValidStrategies(), string(strategy)) {
+			return nil, fmt.Errorf("merge.fields.%s: unknown strategy %q (valid: %s)",
+				name, fields[name], strings.Join(ValidStrategies(), ", "))
+		}
+		f, ok := findIssueField(name)
+		if !ok {
+			return nil, fmt.Errorf("merge.fields.%s: not an issue field", name)
+		}
+		if !isConfigurable(name) {
+			return nil, fmt.Errorf("merge.fields.%s: field is not configurable (its value follows other fields)", name)
+		}
+		if err := checkStrategy(name, f.typ, strategy); err != nil {
+			return nil, fmt.Errorf("merge.fields.%s: %w", name, err)
+		}
+		p.Fields[name] = strategy
+	}
+	return p, nil
+}
+
+// ConfigurableFields returns the issue fields that accept a strategy.
+func ConfigurableFields() []string {
+	var names []string
+	for _, f := range issueFields {
+		if isConfigurable(f.name) {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+func isConfigurable(name string) bool {
+	switch fieldRules[name] {
+	case ruleKey, ruleClosed, ruleTombstone, ruleCompaction:
+		return false
+	}
+	return true
+}
+
+func findIssueField(name string) (issueField, bool) {
+	for _, f := range issueFields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return issueField{}, false
+}
+
+// checkStrategy reports whether strategy can merge a field of type t.
+func checkStrategy(name string, t reflect.Type, strategy Strategy) error {
+	switch strategy {
+	case StrategyUnion:
+		if t.Kind() != reflect.Slice {
+			return fmt.Errorf("union applies only to list fields")
+		}
+	case StrategyConcat:
+		if t.Kind() != reflect.String || name == "status" {
+			return fmt.Errorf("concat applies only to text fields")
+		}
+	case StrategyMax, StrategyMin:
+		if name != "status" && !isOrdered(t) {
+			return fmt.Errorf("%s applies only to numbers, timestamps and status", strategy)
+		}
+	}
+	return nil
+}
+
+func isOrdered(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// strategy returns the configured strategy for a field, or "" for the
+// built-in rule.
+func (p *Policy) strategy(name string) Strategy {
+	if p == nil {
+		return ""
+	}
+	return p.Fields[name]
+}
+
+// WithoutConflicts returns a copy of the policy in which conflict
+// strategies fall back to lww, for automated merges that can't stop for a
+// person to resolve them.
+func (p *Policy) WithoutConflicts() *Policy {
+	return p.ConflictsResolvedBy(StrategyLWW)
+}
+
+// ConflictsResolvedBy returns a copy of the policy in which conflict
+// strategies are replaced by s, e.g. StrategyLeft when the user chose to
+// keep their side of whatever is left in conflict.
+func (p *Policy) ConflictsResolvedBy(s Strategy) *Policy {
+	if p == nil {
+		return nil
+	}
+	out := &Policy{Fields: make(map[string]Strategy, len(p.Fields)), StatusOrder: p.StatusOrder}
+	for name, fs := range p.Fields {
+		if fs == StrategyConflict {
+			fs = s
+		}
+		out.Fields[name] = fs
+	}
+	return out
+}
+
+// applyStrategy merges one field with a configured strategy. It reports
+// conflict=true when the strategy is "conflict" and both sides changed the
+// field differently; the returned value is then the left one.
+func (p *Policy) applyStrategy(name string, strategy Strategy, base, left, right reflect.Value, leftNewer bool) (reflect.Value, bool) {
+	if valuesEqual(base, left) || valuesEqual(base, right) || valuesEqual(left, right) {
+		return mergeValue(base, left, right, true), false
+	}
+
+	switch strategy {
+	case StrategyLeft:
+		return left, false
+	case StrategyRight:
+		return right, false
+	case StrategyConflict:
+		return left, true
+	case StrategyConcat:
+		l, r := left.String(), right.String()
+		if l == "" || r == "" {
+			return reflect.ValueOf(l + r).Convert(left.Type()), false
+		}
+		return reflect.ValueOf(l + "\n\n---\n\n" + r).Convert(left.Type()), false
+	case StrategyUnion:
+		return unionValues(left, right), false
+	case StrategyMax, StrategyMin:
+		c := p.compare(name, left, right)
+		if (strategy == StrategyMax) == (c >= 0) {
+			return left, false
+		}
+		return right, false
+	}
+	// lww
+	if leftNewer {
+		return left, false
+	}
+	return right, false
+}
+
+// compare orders two values of an ordered field: statuses by StatusOrder,
+// timestamps by time (unset first), numbers numerically (unset first).
+func (p *Policy) compare(name string, a, b reflect.Value) int {
+	if name == "status" {
+		return p.statusRank(types.Status(a.String())) - p.statusRank(types.Status(b.String()))
+	}
+	if a.Kind() == reflect.Ptr {
+		switch {
+		case a.IsNil() && b.IsNil():
+			return 0
+		case a.IsNil():
+			return -1
+		case b.IsNil():
+			return 1
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+	if t, ok := a.Interface().(time.Time); ok {
+		return t.Compare(b.Interface().(time.Time))
+	}
+	switch a.Kind() {
+	case reflect.Float32, reflect.Float64:
+		af, bf := a.Float(), b.Float()
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	default:
+		ai, bi := a.Int(), b.Int()
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		}
+		return 0
+	}
+}
+
+func (p *Policy) statusRank(s types.Status) int {
+	return slices.Index(p.StatusOrder, s)
+}
+
+// unionValues combines two lists, keeping left's items first and adding
+// right's items that left doesn't have.
+func unionValues(left, right reflect.Value) reflect.Value {
+	out := reflect.MakeSlice(left.Type(), 0, left.Len()+right.Len())
+	for i := 0; i < left.Len(); i++ {
+		out = reflect.Append(out, left.Index(i))
+	}
+	for i := 0; i < right.Len(); i++ {
+		item := right.Index(i)
+		found := false
+		for j := 0; j < left.Len() && !found; j++ {
+			found = reflect.DeepEqual(left.Index(j).Interface(), item.Interface())
+		}
+		if !found {
+			out = reflect.Append(out, item)
+		}
+	}
+	return out
+}
+
+// DiffFields returns the JSONL fields on which two versions of an issue
+// differ, in struct order.
+func DiffFields(left, right Issue) []string {
+	lv, rv := reflect.ValueOf(left), reflect.ValueOf(right)
+	var fields []string
+	for _, f := range issueFields {
+		if !valuesEqual(lv.Field(f.index), rv.Field(f.index)) {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
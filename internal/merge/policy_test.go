@@ -0,0 +1,308 @@
+package merge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  map[string]string
+		wantErr string
+	}{
+		{name: "empty", fields: nil},
+		{name: "valid strategies", fields: map[string]string{
+			"assignee": "left", "description": "conflict", "status": "max",
+			"priority": "min", "labels": "union", "notes": "concat", "due_at": "max", "title": "LWW",
+		}},
+		{name: "unknown strategy", fields: map[string]string{"title": "newest"}, wantErr: "unknown strategy"},
+		{name: "unknown field", fields: map[string]string{"nope": "left"}, wantErr: "not an issue field"},
+		{name: "identity field", fields: map[string]string{"created_at": "max"}, wantErr: "not configurable"},
+		{name: "derived close field", fields: map[string]string{"close_reason": "left"}, wantErr: "not configurable"},
+		{name: "union on text", fields: map[string]string{"title": "union"}, wantErr: "list fields"},
+		{name: "concat on number", fields: map[string]string{"priority": "concat"}, wantErr: "text fields"},
+		{name: "concat on status", fields: map[string]string{"status": "concat"}, wantErr: "text fields"},
+		{name: "max on text", fields: map[string]string{"assignee": "max"}, wantErr: "numbers, timestamps and status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy(tt.fields, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	if err := config.Initialize(); err != nil {
+		t.Fatalf("config.Initialize: %v", err)
+	}
+	t.Cleanup(func() {
+		config.Set("merge.fields", map[string]string{})
+		config.Set("merge.status-order", []string{})
+	})
+
+	config.Set("merge.fields", map[string]string{})
+	if policy, err := LoadPolicy(); err != nil || policy != nil {
+		t.Fatalf("LoadPolicy without merge.fields = %v, %v; want nil, nil", policy, err)
+	}
+
+	config.Set("merge.fields", map[string]string{"assignee": "left"})
+	policy, err := LoadPolicy()
+	if err != nil || policy == nil || policy.Fields["assignee"] != StrategyLeft {
+		t.Fatalf("LoadPolicy = %+v, %v", policy, err)
+	}
+
+	config.Set("merge.fields", map[string]string{"title": "newest"})
+	if _, err := LoadPolicy(); err == nil || !strings.Contains(err.Error(), "invalid merge config") {
+		t.Fatalf("expected invalid merge config error, got %v", err)
+	}
+}
+
+func TestMergeIssueWithPolicy(t *testing.T) {
+	base := Issue{
+		ID:          "bd-1",
+		Title:       "base",
+		Description: "base",
+		Status:      "open",
+		Priority:    2,
+		Assignee:    "base",
+		Labels:      []string{"a", "b"},
+		CreatedAt:   ts("2024-01-01T00:00:00Z"),
+		UpdatedAt:   ts("2024-01-01T00:00:00Z"),
+	}
+	left := base
+	left.Description = "left"
+	left.Status = "in_progress"
+	left.Priority = 1
+	left.Assignee = "alice"
+	left.Labels = []string{"a"}
+	left.UpdatedAt = ts("2024-01-02T00:00:00Z")
+	right := base
+	right.Description = "right"
+	right.Status = "blocked"
+	right.Priority = 3
+	right.Assignee = "bob"
+	right.Labels = []string{"b", "c"}
+	right.UpdatedAt = ts("2024-01-03T00:00:00Z")
+
+	tests := []struct {
+		name   string
+		fields map[string]string
+		order  []string
+		check  func(t *testing.T, got Issue)
+	}{
+		{
+			name:   "left and right",
+			fields: map[string]string{"assignee": "left", "description": "right"},
+			check: func(t *testing.T, got Issue) {
+				if got.Assignee != "alice" || got.Description != "right" {
+					t.Errorf("got assignee=%q description=%q", got.Assignee, got.Description)
+				}
+			},
+		},
+		{
+			name:   "lww picks newer side",
+			fields: map[string]string{"assignee": "lww"},
+			check: func(t *testing.T, got Issue) {
+				if got.Assignee != "bob" {
+					t.Errorf("got assignee=%q, want bob", got.Assignee)
+				}
+			},
+		},
+		{
+			name:   "status max by default workflow order",
+			fields: map[string]string{"status": "max"},
+			check: func(t *testing.T, got Issue) {
+				if got.Status != types.StatusInProgress {
+					t.Errorf("got status=%q, want in_progress", got.Status)
+				}
+			},
+		},
+		{
+			name:   "status max by configured order",
+			fields: map[string]string{"status": "max"},
+			order:  []string{"open", "in_progress", "blocked"},
+			check: func(t *testing.T, got Issue) {
+				if got.Status != types.StatusBlocked {
+					t.Errorf("got status=%q, want blocked", got.Status)
+				}
+			},
+		},
+		{
+			name:   "priority max",
+			fields: map[string]string{"priority": "max"},
+			check: func(t *testing.T, got Issue) {
+				if got.Priority != 3 {
+					t.Errorf("got priority=%d, want 3", got.Priority)
+				}
+			},
+		},
+		{
+			name:   "concat description",
+			fields: map[string]string{"description": "concat"},
+			check: func(t *testing.T, got Issue) {
+				if got.Description != "left\n\n---\n\nright" {
+					t.Errorf("got description=%q", got.Description)
+				}
+			},
+		},
+		{
+			name:   "union labels keeps removed items",
+			fields: map[string]string{"labels": "union"},
+			check: func(t *testing.T, got Issue) {
+				if strings.Join(got.Labels, ",") != "a,b,c" {
+					t.Errorf("got labels=%v, want [a b c]", got.Labels)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParsePolicy(tt.fields, tt.order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, conflict := mergeIssueWithPolicy(base, left, right, policy)
+			if conflict != "" {
+				t.Fatalf("unexpected conflict: %s", conflict)
+			}
+			tt.check(t, got)
+		})
+	}
+
+	t.Run("one-sided change is not a conflict", func(t *testing.T) {
+		policy, _ := ParsePolicy(map[string]string{"title": "conflict"}, nil)
+		onlyLeft := base
+		onlyLeft.Title = "changed"
+		got, conflict := mergeIssueWithPolicy(base, onlyLeft, base, policy)
+		if conflict != "" || got.Title != "changed" {
+			t.Errorf("got title=%q conflict=%q", got.Title, conflict)
+		}
+	})
+
+	t.Run("conflict strategy produces a conflict record", func(t *testing.T) {
+		policy, _ := ParsePolicy(map[string]string{"description": "conflict"}, nil)
+		_, conflict := mergeIssueWithPolicy(base, left, right, policy)
+		if !strings.HasPrefix(conflict, "<<<<<<< left (conflicting: description)\n") {
+			t.Fatalf("unexpected conflict record:\n%s", conflict)
+		}
+		if !strings.Contains(conflict, `"description":"left"`) || !strings.Contains(conflict, `"description":"right"`) {
+			t.Errorf("conflict record should carry both descriptions:\n%s", conflict)
+		}
+		if !strings.HasSuffix(conflict, "\n>>>>>>> right") {
+			t.Errorf("conflict record should end with a marker:\n%s", conflict)
+		}
+		// Other fields are already merged, so the two versions agree on them
+		if strings.Count(conflict, `"assignee":"bob"`) != 2 {
+			t.Errorf("expected merged assignee in both versions:\n%s", conflict)
+		}
+	})
+
+	t.Run("without conflicts falls back to lww", func(t *testing.T) {
+		policy, _ := ParsePolicy(map[string]string{"description": "conflict"}, nil)
+		got, conflict := mergeIssueWithPolicy(base, left, right, policy.WithoutConflicts())
+		if conflict != "" || got.Description != "right" {
+			t.Errorf("got description=%q conflict=%q", got.Description, conflict)
+		}
+	})
+}
+
+func TestMerge3WayWithPolicy_ConflictOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"base":  `{"id":"bd-1","title":"t","description":"base","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`,
+		"left":  `{"id":"bd-1","title":"t","description":"left","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z"}`,
+		"right": `{"id":"bd-1","title":"t","description":"right","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-03T00:00:00Z"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name+".jsonl"), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	policy, err := ParsePolicy(map[string]string{"description": "conflict"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(tmpDir, "out.jsonl")
+	err = Merge3WayWithPolicy(out, filepath.Join(tmpDir, "base.jsonl"), filepath.Join(tmpDir, "left.jsonl"),
+		filepath.Join(tmpDir, "right.jsonl"), policy, false)
+	if err == nil || !strings.Contains(err.Error(), "merge completed with 1 conflicts") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "<<<<<<<") || lines[2] != "=======" || !strings.HasPrefix(lines[4], ">>>>>>>") {
+		t.Errorf("expected a single conflict block, got:\n%s", data)
+	}
+}
+
+func TestDiffFields(t *testing.T) {
+	a := Issue{ID: "bd-1", Title: "x", Labels: nil, UpdatedAt: ts("2024-01-01T00:00:00Z")}
+	b := Issue{ID: "bd-1", Title: "y", Labels: []string{}, UpdatedAt: ts("2024-01-01T02:00:00+02:00")}
+	if got := DiffFields(a, b); strings.Join(got, ",") != "title" {
+		t.Errorf("DiffFields() = %v, want [title]", got)
+	}
+}
+
+func TestMergeIssue3Way(t *testing.T) {
+	base := Issue{ID: "bd-1", Title: "Base", Assignee: "", Description: "base", UpdatedAt: ts("2024-01-01T00:00:00Z")}
+	left := base
+	left.Assignee = "alpha"
+	left.Description = "ours"
+	left.UpdatedAt = ts("2024-01-02T00:00:00Z")
+	right := base
+	right.Title = "Renamed"
+	right.Description = "theirs"
+	right.UpdatedAt = ts("2024-01-03T00:00:00Z")
+
+	merged, fields := MergeIssue3Way(&base, left, right, nil)
+	if len(fields) != 0 {
+		t.Errorf("built-in rules should leave no conflicts, got %v", fields)
+	}
+	if merged.Title != "Renamed" || merged.Assignee != "alpha" || merged.Description != "theirs" {
+		t.Errorf("unexpected merge: title=%q assignee=%q description=%q", merged.Title, merged.Assignee, merged.Description)
+	}
+
+	policy, err := ParsePolicy(map[string]string{"description": "conflict"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, fields = MergeIssue3Way(&base, left, right, policy)
+	if len(fields) != 1 || fields[0] != "description" {
+		t.Fatalf("expected description conflict, got %v", fields)
+	}
+	if merged.Description != "ours" {
+		t.Errorf("conflicting field should keep the left value, got %q", merged.Description)
+	}
+
+	merged, fields = MergeIssue3Way(&base, left, right, policy.ConflictsResolvedBy(StrategyRight))
+	if len(fields) != 0 || merged.Description != "theirs" {
+		t.Errorf("ConflictsResolvedBy(right): fields=%v description=%q", fields, merged.Description)
+	}
+
+	// No base: both sides added the issue
+	merged, fields = MergeIssue3Way(nil, left, right, policy)
+	if len(fields) != 1 || merged.Title != "Renamed" {
+		t.Errorf("nil base: fields=%v title=%q", fields, merged.Title)
+	}
+}
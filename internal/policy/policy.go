@@ -0,0 +1,235 @@
+// Package policy implements actor-based authorization rules for daemon RPC
+// operations, loaded from .beads/policy.yaml.
+//
+// Example:
+//
+//	default: allow
+//	rules:
+//	  - name: polecats
+//	    actors: ["*/polecats/*"]
+//	    allow: [read, create, update, close, comment_add, label_add, label_remove]
+//	    deny: [delete]
+//	    issue_types: [task, bug, chore]
+//	    labels: ["area:*", "status:*"]
+//	  - name: everyone-else
+//	    actors: ["*"]
+//	    allow: ["*"]
+//
+// The first rule whose actor patterns match the request's actor decides the
+// request. Actors that match no rule get the default effect.
+//
+// The daemon checks policies against the actor named in a token issued from
+// .beads/policy.key (see IssueToken), not the actor the client reports.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the policy file name stored under .beads/.
+const FileName = "policy.yaml"
+
+// Effects for Policy.Default.
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Wildcard matches every operation in Allow/Deny and every actor in Actors.
+const Wildcard = "*"
+
+// Policy is the parsed contents of policy.yaml.
+type Policy struct {
+	// Default is the effect for actors that match no rule: "allow" (the
+	// default, so adding a policy file never locks out unlisted actors) or "deny".
+	Default string `yaml:"default,omitempty"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Rule grants a set of actors access to operations, optionally narrowed to
+// issue types and label scopes.
+type Rule struct {
+	Name string `yaml:"name,omitempty"`
+	// Actors are glob patterns; "*" matches any run of characters, including "/".
+	Actors []string `yaml:"actors"`
+	// Allow lists operations or operation groups (read, write, admin) the
+	// actors may perform. Empty allows everything not denied.
+	Allow []string `yaml:"allow,omitempty"`
+	// Deny lists operations or groups that are refused even if allowed.
+	Deny []string `yaml:"deny,omitempty"`
+	// IssueTypes restricts the types of issues the actors may create or modify.
+	IssueTypes []string `yaml:"issue_types,omitempty"`
+	// Labels restricts, by glob pattern, the labels the actors may add or remove.
+	Labels []string `yaml:"labels,omitempty"`
+}
+
+// Request describes an operation to authorize.
+type Request struct {
+	Actor     string
+	Operation string
+	// Groups are the operation groups Operation belongs to.
+	Groups []string
+	// IssueTypes are the types of the issues the operation creates or modifies.
+	IssueTypes []string
+	// Labels are the labels the operation adds or removes.
+	Labels []string
+}
+
+// Decision is the outcome of Check.
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Rule    string `json:"rule"` // rule name, "rules[i]" if unnamed, or "default"
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Load reads and validates a policy file. A missing file returns (nil, nil):
+// no policy means every operation is allowed.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is .beads/policy.yaml
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates policy YAML.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate checks the default effect and that every rule names its actors.
+func (p *Policy) Validate() error {
+	switch p.Default {
+	case "", EffectAllow, EffectDeny:
+	default:
+		return fmt.Errorf("invalid %s: default must be %q or %q, got %q", FileName, EffectAllow, EffectDeny, p.Default)
+	}
+	for i, r := range p.Rules {
+		if len(r.Actors) == 0 {
+			return fmt.Errorf("invalid %s: %s has no actors", FileName, ruleName(i, r))
+		}
+	}
+	return nil
+}
+
+// Check decides whether req is allowed. A nil policy allows everything.
+func (p *Policy) Check(req Request) Decision {
+	if p == nil {
+		return Decision{Allowed: true, Rule: "default"}
+	}
+
+	for i, r := range p.Rules {
+		if !matchAny(r.Actors, req.Actor) {
+			continue
+		}
+		name := ruleName(i, r)
+		if containsOp(r.Deny, req) {
+			return Decision{Rule: name, Reason: fmt.Sprintf("operation %q is denied", req.Operation)}
+		}
+		if len(r.Allow) > 0 && !containsOp(r.Allow, req) {
+			return Decision{Rule: name, Reason: fmt.Sprintf("operation %q is not allowed", req.Operation)}
+		}
+		if len(r.IssueTypes) > 0 {
+			for _, t := range req.IssueTypes {
+				if !contains(r.IssueTypes, t) {
+					return Decision{Rule: name, Reason: fmt.Sprintf("issue type %q is not allowed", t)}
+				}
+			}
+		}
+		if len(r.Labels) > 0 {
+			for _, l := range req.Labels {
+				if !matchAny(r.Labels, l) {
+					return Decision{Rule: name, Reason: fmt.Sprintf("label %q is outside the allowed scopes", l)}
+				}
+			}
+		}
+		return Decision{Allowed: true, Rule: name}
+	}
+
+	if p.Default == EffectDeny {
+		return Decision{Rule: "default", Reason: fmt.Sprintf("no rule grants actor %q access", req.Actor)}
+	}
+	return Decision{Allowed: true, Rule: "default"}
+}
+
+func ruleName(i int, r Rule) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("rules[%d]", i)
+}
+
+// containsOp reports whether ops names the request's operation, one of its
+// groups, or the wildcard.
+func containsOp(ops []string, req Request) bool {
+	for _, op := range ops {
+		if op == Wildcard || op == req.Operation || contains(req.Groups, op) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if Match(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether s matches the glob pattern. "*" matches any run of
+// characters (including "/") and "?" matches exactly one.
+func Match(pattern, s string) bool {
+	star := strings.IndexByte(pattern, '*')
+	if star < 0 {
+		return matchFixed(pattern, s)
+	}
+	if len(s) < star || !matchFixed(pattern[:star], s[:star]) {
+		return false
+	}
+	rest := pattern[star+1:]
+	for i := star; i <= len(s); i++ {
+		if Match(rest, s[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFixed matches a pattern without "*" where "?" matches any one byte.
+func matchFixed(pattern, s string) bool {
+	if len(pattern) != len(s) {
+		return false
+	}
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '?' && pattern[i] != s[i] {
+			return false
+		}
+	}
+	return true
+}
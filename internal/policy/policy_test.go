@@ -0,0 +1,179 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPolicy = `
+default: deny
+rules:
+  - name: polecats
+    actors: ["*/polecats/*"]
+    allow: [read, create, update, close]
+    deny: [delete]
+    issue_types: [task, bug]
+    labels: ["area:*"]
+  - actors: [mayor, "crew-?"]
+`
+
+func TestCheck(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	polecat := "gastown/polecats/nux"
+	tests := []struct {
+		name     string
+		req      Request
+		allowed  bool
+		wantRule string
+	}{
+		{"group allowed", Request{Actor: polecat, Operation: "show", Groups: []string{"read"}}, true, "polecats"},
+		{"op allowed with type", Request{Actor: polecat, Operation: "create", Groups: []string{"write"}, IssueTypes: []string{"task"}}, true, "polecats"},
+		{"epic refused", Request{Actor: polecat, Operation: "update", Groups: []string{"write"}, IssueTypes: []string{"epic"}}, false, "polecats"},
+		{"delete denied", Request{Actor: polecat, Operation: "delete", Groups: []string{"write"}, IssueTypes: []string{"task"}}, false, "polecats"},
+		{"not in allow list", Request{Actor: polecat, Operation: "compact", Groups: []string{"admin"}}, false, "polecats"},
+		{"label in scope", Request{Actor: polecat, Operation: "update", IssueTypes: []string{"bug"}, Labels: []string{"area:cli"}}, true, "polecats"},
+		{"label out of scope", Request{Actor: polecat, Operation: "update", IssueTypes: []string{"bug"}, Labels: []string{"release"}}, false, "polecats"},
+		{"unnamed rule", Request{Actor: "mayor", Operation: "delete"}, true, "rules[1]"},
+		{"single char glob", Request{Actor: "crew-a", Operation: "shutdown"}, true, "rules[1]"},
+		{"default deny", Request{Actor: "stranger", Operation: "list"}, false, "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := p.Check(tt.req)
+			if d.Allowed != tt.allowed || d.Rule != tt.wantRule {
+				t.Errorf("Check() = %+v, want allowed=%v rule=%s", d, tt.allowed, tt.wantRule)
+			}
+			if !d.Allowed && d.Reason == "" {
+				t.Error("denials should carry a reason")
+			}
+		})
+	}
+}
+
+func TestCheck_NilAndDefaultAllow(t *testing.T) {
+	var p *Policy
+	if !p.Check(Request{Actor: "anyone", Operation: "delete"}).Allowed {
+		t.Error("nil policy should allow everything")
+	}
+	p = &Policy{Rules: []Rule{{Actors: []string{"bot"}, Deny: []string{Wildcard}}}}
+	if p.Check(Request{Actor: "bot", Operation: "list"}).Allowed {
+		t.Error("wildcard deny should refuse every operation")
+	}
+	if !p.Check(Request{Actor: "human", Operation: "delete"}).Allowed {
+		t.Error("default should be allow when unset")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"bad default": "default: maybe\n",
+		"no actors":   "rules:\n  - allow: [read]\n",
+		"bad yaml":    "rules: [\n",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	p, err := Load(filepath.Join(dir, FileName))
+	if err != nil || p != nil {
+		t.Fatalf("missing file: got %v, %v; want nil, nil", p, err)
+	}
+
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, []byte(testPolicy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(p.Rules) != 2 || p.Default != EffectDeny {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "", true},
+		{"*", "a/b/c", true},
+		{"gastown/*", "gastown/polecats/nux", true},
+		{"*/polecats/*", "gastown/polecats/nux", true},
+		{"*/polecats/*", "gastown/crew/joe", false},
+		{"area:*", "area:cli", true},
+		{"area:*", "areacli", false},
+		{"a?c", "abc", true},
+		{"a?c", "ac", false},
+		{"exact", "exact", true},
+		{"exact", "exactly", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestActorToken(t *testing.T) {
+	dir := t.TempDir()
+	key, err := LoadOrCreateKey(dir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateKey: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dir, KeyFileName))
+	if err != nil {
+		t.Fatalf("stat key: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("key mode = %v, want 0600", info.Mode().Perm())
+	}
+	again, err := LoadOrCreateKey(dir)
+	if err != nil || string(again) != string(key) {
+		t.Fatalf("second LoadOrCreateKey returned a different key (err=%v)", err)
+	}
+
+	token := IssueToken(key, "gastown/polecats/nux")
+	actor, err := VerifyToken(key, token)
+	if err != nil || actor != "gastown/polecats/nux" {
+		t.Fatalf("VerifyToken = %q, %v; want gastown/polecats/nux", actor, err)
+	}
+
+	// Relabeling the actor part must not carry the signature over.
+	forged := IssueToken([]byte("other key material, 32 bytes!!!"), "mayor")
+	if _, err := VerifyToken(key, forged); err == nil {
+		t.Error("token signed with another key verified")
+	}
+	_, mac, _ := strings.Cut(token, ".")
+	if _, err := VerifyToken(key, IssueToken(key, "mayor")[:len(tokenPrefix)+len("bWF5b3I")]+"."+mac); err == nil {
+		t.Error("token with swapped actor verified")
+	}
+	for _, bad := range []string{"", "garbage", "bdat_!!.00"} {
+		if _, err := VerifyToken(key, bad); err == nil {
+			t.Errorf("VerifyToken(%q) succeeded", bad)
+		}
+	}
+
+	// Rotating the key revokes existing tokens.
+	if err := os.Remove(filepath.Join(dir, KeyFileName)); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := LoadOrCreateKey(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyToken(rotated, token); err == nil {
+		t.Error("token survived key rotation")
+	}
+}
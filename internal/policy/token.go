@@ -0,0 +1,109 @@
+package policy
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KeyFileName is the secret under .beads/ that actor tokens are derived from.
+// Whoever can read it can act as any actor; deleting it revokes every token.
+const KeyFileName = "policy.key"
+
+// TokenEnv is the environment variable clients read their actor token from.
+const TokenEnv = "BD_ACTOR_TOKEN"
+
+// tokenPrefix marks actor tokens so they are recognizable in env dumps.
+const tokenPrefix = "bdat_"
+
+const keySize = 32
+
+// ErrNoToken is returned by VerifyToken for an empty token.
+var ErrNoToken = errors.New("no actor token")
+
+// ReadKey reads the policy key of the workspace whose .beads directory is
+// beadsDir. A missing key is reported with an error satisfying os.IsNotExist.
+func ReadKey(beadsDir string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(beadsDir, KeyFileName)) // #nosec G304 - path is .beads/policy.key
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("invalid %s: expected %d hex-encoded bytes", KeyFileName, keySize)
+	}
+	return key, nil
+}
+
+// LoadOrCreateKey reads the workspace's policy key, generating it on first
+// use. The key is written to a temporary file and linked into place, so
+// concurrent callers never see a partial key and all end up with the same one.
+func LoadOrCreateKey(beadsDir string) ([]byte, error) {
+	key, err := ReadKey(beadsDir)
+	if err == nil || !os.IsNotExist(err) {
+		return key, err
+	}
+
+	key = make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate %s: %w", KeyFileName, err)
+	}
+	tmp, err := os.CreateTemp(beadsDir, KeyFileName+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", KeyFileName, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		_ = tmp.Close()
+		return nil, fmt.Errorf("failed to write %s: %w", KeyFileName, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", KeyFileName, err)
+	}
+	if err := os.Link(tmp.Name(), filepath.Join(beadsDir, KeyFileName)); err != nil {
+		if os.IsExist(err) {
+			return ReadKey(beadsDir) // Someone else created it first
+		}
+		return nil, fmt.Errorf("failed to create %s: %w", KeyFileName, err)
+	}
+	return key, nil
+}
+
+// IssueToken returns the token that authenticates a client as actor.
+func IssueToken(key []byte, actor string) string {
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(actor)) + "." + hex.EncodeToString(tokenMAC(key, actor))
+}
+
+// VerifyToken returns the actor a token was issued to, or an error if the
+// token was not issued with key.
+func VerifyToken(key []byte, token string) (string, error) {
+	if token == "" {
+		return "", ErrNoToken
+	}
+	encoded, mac, ok := strings.Cut(strings.TrimPrefix(token, tokenPrefix), ".")
+	if !ok || !strings.HasPrefix(token, tokenPrefix) {
+		return "", errors.New("malformed actor token")
+	}
+	actor, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.New("malformed actor token")
+	}
+	got, err := hex.DecodeString(mac)
+	if err != nil || !hmac.Equal(got, tokenMAC(key, string(actor))) {
+		return "", errors.New("invalid actor token")
+	}
+	return string(actor), nil
+}
+
+func tokenMAC(key []byte, actor string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte("bd-actor:" + actor))
+	return h.Sum(nil)
+}
@@ -173,18 +173,8 @@ func ResolveBeadsDirForRig(rigOrPrefix, currentBeadsDir string) (beadsDir string
 		return "", "", fmt.Errorf("rig or prefix %q not found in routes.jsonl", rigOrPrefix)
 	}
 
-	// Resolve the target beads directory
-	var targetPath string
-	if route.Path == "." {
-		// Special case: "." means the town beads directory
-		targetPath = filepath.Join(townRoot, ".beads")
-	} else {
-		// Normal path resolution relative to town root
-		targetPath = filepath.Join(townRoot, route.Path, ".beads")
-	}
-
-	// Follow redirect if present
-	targetPath = resolveRedirect(targetPath)
+	// Resolve the target beads directory, following a redirect if present
+	targetPath := resolveRedirect(routeBeadsDir(townRoot, route))
 
 	// Verify the target exists
 	if info, statErr := os.Stat(targetPath); statErr != nil || !info.IsDir() {
@@ -251,18 +241,8 @@ func ResolveBeadsDirForID(ctx context.Context, id, currentBeadsDir string) (stri
 		if prefix != "" {
 			for _, route := range routes {
 				if route.Prefix == prefix {
-					// Found a matching route - resolve the path
-					var targetPath string
-					if route.Path == "." {
-						// Special case: "." means the town beads directory
-						targetPath = filepath.Join(townRoot, ".beads")
-					} else {
-						// Normal path resolution relative to town root
-						targetPath = filepath.Join(townRoot, route.Path, ".beads")
-					}
-
-					// Follow redirect if present
-					targetPath = resolveRedirect(targetPath)
+					// Found a matching route - resolve the path, following a redirect if present
+					targetPath := resolveRedirect(routeBeadsDir(townRoot, route))
 
 					// Verify the target exists
 					if info, err := os.Stat(targetPath); err == nil && info.IsDir() {
@@ -0,0 +1,269 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/beads/internal/configfile"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// TownRigName is the rig name used for a route whose path is "." (the town
+// beads directory itself).
+const TownRigName = "town"
+
+// TownRig is one rig database reachable through the town's routes.jsonl.
+type TownRig struct {
+	Name     string `json:"rig"`       // Rig name (first path component, "town" for ".")
+	Prefix   string `json:"prefix"`    // Issue ID prefix (e.g., "gt-")
+	BeadsDir string `json:"beads_dir"` // Resolved .beads directory (redirects followed)
+}
+
+// Town is the set of rigs routed from a town-level routes.jsonl, used for
+// queries that span every rig (--town scope).
+type Town struct {
+	Root string
+	Rigs []TownRig
+
+	// Open opens a rig's store. Defaults to OpenRigStorage.
+	Open func(ctx context.Context, rig TownRig) (storage.Storage, error)
+}
+
+// LoadTown resolves every route in the town containing currentBeadsDir.
+// Routes that point at the same directory are collapsed into one rig.
+// Rigs whose directory is missing are kept so queries can report them.
+func LoadTown(currentBeadsDir string) (*Town, error) {
+	routes, townRoot := findTownRoutes(currentBeadsDir)
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no town routes found (%s)", RoutesFileName)
+	}
+
+	town := &Town{Root: townRoot, Open: OpenRigStorage}
+	seen := make(map[string]bool)
+	for _, route := range routes {
+		beadsDir := resolveRedirect(routeBeadsDir(townRoot, route))
+		if seen[beadsDir] {
+			continue
+		}
+		seen[beadsDir] = true
+
+		name := ExtractProjectFromPath(route.Path)
+		if route.Path == "." || name == "" {
+			name = TownRigName
+		}
+		town.Rigs = append(town.Rigs, TownRig{
+			Name:     name,
+			Prefix:   route.Prefix,
+			BeadsDir: beadsDir,
+		})
+	}
+	return town, nil
+}
+
+// routeBeadsDir returns the .beads directory a route points at, before
+// following any redirect.
+func routeBeadsDir(townRoot string, route Route) string {
+	if route.Path == "." {
+		// Special case: "." means the town beads directory
+		return filepath.Join(townRoot, ".beads")
+	}
+	return filepath.Join(townRoot, route.Path, ".beads")
+}
+
+// OpenRigStorage opens a rig's SQLite database read-only, using the database
+// name from its metadata.json when present.
+func OpenRigStorage(ctx context.Context, rig TownRig) (storage.Storage, error) {
+	if info, err := os.Stat(rig.BeadsDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("beads directory not found: %s", rig.BeadsDir)
+	}
+
+	dbPath := filepath.Join(rig.BeadsDir, "beads.db")
+	if cfg, err := configfile.Load(rig.BeadsDir); err == nil && cfg != nil {
+		if backend := cfg.GetBackend(); backend != configfile.BackendSQLite {
+			return nil, fmt.Errorf("%s backend is not supported for town queries", backend)
+		}
+		dbPath = cfg.DatabasePath(rig.BeadsDir)
+	}
+	return sqlite.NewReadOnly(ctx, dbPath)
+}
+
+// Rig returns the rig with the given name, prefix (with or without the
+// trailing hyphen), or nil if the town has no such rig.
+func (t *Town) Rig(nameOrPrefix string) *TownRig {
+	normalized := strings.TrimSuffix(nameOrPrefix, "-")
+	for i := range t.Rigs {
+		rig := &t.Rigs[i]
+		if rig.Name == nameOrPrefix || strings.TrimSuffix(rig.Prefix, "-") == normalized {
+			return rig
+		}
+	}
+	return nil
+}
+
+// RigResult is what a town query returned for a single rig. A failing rig
+// sets Err and does not affect the others.
+type RigResult[T any] struct {
+	Rig   TownRig
+	Value T
+	Err   error
+}
+
+// QueryTown opens every rig concurrently, runs fn against its store and
+// closes it again. Results are returned in rig order.
+func QueryTown[T any](ctx context.Context, town *Town, fn func(ctx context.Context, rig TownRig, store storage.Storage) (T, error)) []RigResult[T] {
+	return queryRigs(ctx, town, town.Rigs, fn)
+}
+
+func queryRigs[T any](ctx context.Context, town *Town, rigs []TownRig, fn func(ctx context.Context, rig TownRig, store storage.Storage) (T, error)) []RigResult[T] {
+	open := town.Open
+	if open == nil {
+		open = OpenRigStorage
+	}
+
+	results := make([]RigResult[T], len(rigs))
+	var wg sync.WaitGroup
+	for i, rig := range rigs {
+		wg.Add(1)
+		go func(i int, rig TownRig) {
+			defer wg.Done()
+			results[i].Rig = rig
+
+			store, err := open(ctx, rig)
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+			defer func() { _ = store.Close() }()
+
+			results[i].Value, results[i].Err = fn(ctx, rig, store)
+		}(i, rig)
+	}
+	wg.Wait()
+	return results
+}
+
+// CrossRigRef is a blocking dependency target that lives in another rig of
+// the town. It is either an issue ID or a capability, which is satisfied by
+// a closed issue labeled provides:<capability> (see sqlite.CheckExternalDep).
+type CrossRigRef struct {
+	Ref        string // Dependency target as stored (external:<rig>:<x> or a foreign ID)
+	Rig        string // Name of the rig that owns the target
+	ID         string // Target issue ID, if the ref names an issue
+	Capability string // Target capability, if the ref doesn't name an issue
+}
+
+// ParseCrossRigRef recognizes a dependency target that points at a rig other
+// than fromRig: "external:<rig>:<id-or-capability>", where <rig> is a rig
+// name or prefix, or a bare issue ID carrying another rig's prefix.
+func (t *Town) ParseCrossRigRef(ref, fromRig string) (CrossRigRef, bool) {
+	if strings.HasPrefix(ref, "external:") {
+		parts := strings.SplitN(ref, ":", 3)
+		if len(parts) != 3 || parts[2] == "" {
+			return CrossRigRef{}, false
+		}
+		rig := t.Rig(parts[1])
+		if rig == nil {
+			return CrossRigRef{}, false
+		}
+		out := CrossRigRef{Ref: ref, Rig: rig.Name}
+		if ExtractPrefix(parts[2]) == rig.Prefix {
+			out.ID = parts[2]
+		} else {
+			out.Capability = parts[2]
+		}
+		return out, true
+	}
+
+	prefix := ExtractPrefix(ref)
+	if prefix == "" {
+		return CrossRigRef{}, false
+	}
+	for _, rig := range t.Rigs {
+		if rig.Prefix == prefix && rig.Name != fromRig {
+			return CrossRigRef{Ref: ref, Rig: rig.Name, ID: ref}, true
+		}
+	}
+	return CrossRigRef{}, false
+}
+
+// CrossRigStatus is the resolved state of a CrossRigRef.
+type CrossRigStatus struct {
+	Blocking bool   `json:"blocking"`
+	Reason   string `json:"reason"`
+}
+
+// ResolveCrossRigRefs looks up every ref in its owning rig and reports
+// whether it still blocks. Refs that can't be resolved (rig unavailable,
+// issue missing) are treated as blocking, matching unresolved external
+// dependencies in a single rig.
+func (t *Town) ResolveCrossRigRefs(ctx context.Context, refs []CrossRigRef) map[string]CrossRigStatus {
+	statuses := make(map[string]CrossRigStatus, len(refs))
+	byRig := make(map[string][]CrossRigRef)
+	for _, ref := range refs {
+		if _, done := statuses[ref.Ref]; done {
+			continue
+		}
+		statuses[ref.Ref] = CrossRigStatus{Blocking: true, Reason: "rig " + ref.Rig + " not queried"}
+		byRig[ref.Rig] = append(byRig[ref.Rig], ref)
+	}
+
+	var rigs []TownRig
+	for _, rig := range t.Rigs {
+		if len(byRig[rig.Name]) > 0 {
+			rigs = append(rigs, rig)
+		}
+	}
+
+	results := queryRigs(ctx, t, rigs, func(ctx context.Context, rig TownRig, store storage.Storage) (map[string]CrossRigStatus, error) {
+		out := make(map[string]CrossRigStatus)
+		for _, ref := range byRig[rig.Name] {
+			out[ref.Ref] = resolveCrossRigRef(ctx, store, ref)
+		}
+		return out, nil
+	})
+	for _, res := range results {
+		if res.Err != nil {
+			for _, ref := range byRig[res.Rig.Name] {
+				statuses[ref.Ref] = CrossRigStatus{Blocking: true, Reason: fmt.Sprintf("rig %s unavailable: %v", res.Rig.Name, res.Err)}
+			}
+			continue
+		}
+		for ref, status := range res.Value {
+			statuses[ref] = status
+		}
+	}
+	return statuses
+}
+
+func resolveCrossRigRef(ctx context.Context, store storage.Storage, ref CrossRigRef) CrossRigStatus {
+	if ref.ID != "" {
+		issue, err := store.GetIssue(ctx, ref.ID)
+		switch {
+		case err != nil:
+			return CrossRigStatus{Blocking: true, Reason: "lookup failed: " + err.Error()}
+		case issue == nil:
+			return CrossRigStatus{Blocking: true, Reason: "issue not found in rig " + ref.Rig}
+		case issue.Status == types.StatusClosed || issue.Status == types.StatusTombstone:
+			return CrossRigStatus{Blocking: false, Reason: "issue " + string(issue.Status)}
+		default:
+			return CrossRigStatus{Blocking: true, Reason: "issue " + string(issue.Status)}
+		}
+	}
+
+	issues, err := store.GetIssuesByLabel(ctx, "provides:"+ref.Capability)
+	if err != nil {
+		return CrossRigStatus{Blocking: true, Reason: "lookup failed: " + err.Error()}
+	}
+	for _, issue := range issues {
+		if issue.Status == types.StatusClosed {
+			return CrossRigStatus{Blocking: false, Reason: "capability shipped"}
+		}
+	}
+	return CrossRigStatus{Blocking: true, Reason: "capability not shipped (no closed issue with provides:" + ref.Capability + " label)"}
+}
@@ -0,0 +1,224 @@
+package routing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// setupTestTown creates a town with rigs "beads" (bd-) and "gastown" (gt-),
+// plus a "ghost" route whose directory doesn't exist. fill is called with
+// each existing rig's store before it is closed.
+func setupTestTown(t *testing.T, fill func(rig string, store storage.Storage)) string {
+	t.Helper()
+	ctx := context.Background()
+
+	townRoot, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name": "test-town"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	townBeads := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(townBeads, 0750); err != nil {
+		t.Fatal(err)
+	}
+	routes := `{"prefix": "bd-", "path": "beads/mayor/rig"}
+{"prefix": "gt-", "path": "gastown/mayor/rig"}
+{"prefix": "gh-", "path": "ghost/mayor/rig"}
+`
+	if err := os.WriteFile(filepath.Join(townBeads, RoutesFileName), []byte(routes), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for rig, prefix := range map[string]string{"beads": "bd", "gastown": "gt"} {
+		beadsDir := filepath.Join(townRoot, rig, "mayor", "rig", ".beads")
+		if err := os.MkdirAll(beadsDir, 0750); err != nil {
+			t.Fatal(err)
+		}
+		store, err := sqlite.New(ctx, filepath.Join(beadsDir, "beads.db"))
+		if err != nil {
+			t.Fatalf("failed to create %s store: %v", rig, err)
+		}
+		if err := store.SetConfig(ctx, "issue_prefix", prefix); err != nil {
+			t.Fatal(err)
+		}
+		if fill != nil {
+			fill(rig, store)
+		}
+		if err := store.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Chdir(townRoot)
+	return townBeads
+}
+
+func createTownIssue(t *testing.T, store storage.Storage, id string, status types.Status, labels ...string) {
+	t.Helper()
+	ctx := context.Background()
+	issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+	}
+	for _, label := range labels {
+		if err := store.AddLabel(ctx, id, label, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if status == types.StatusClosed {
+		if err := store.CloseIssue(ctx, id, "done", "test", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadTown(t *testing.T) {
+	townBeads := setupTestTown(t, nil)
+
+	town, err := LoadTown(townBeads)
+	if err != nil {
+		t.Fatalf("LoadTown failed: %v", err)
+	}
+	if len(town.Rigs) != 3 {
+		t.Fatalf("got %d rigs, want 3: %+v", len(town.Rigs), town.Rigs)
+	}
+	if rig := town.Rig("gt"); rig == nil || rig.Name != "gastown" {
+		t.Errorf("Rig(\"gt\") = %+v, want gastown", rig)
+	}
+	if rig := town.Rig("beads"); rig == nil || rig.Prefix != "bd-" {
+		t.Errorf("Rig(\"beads\") = %+v, want prefix bd-", rig)
+	}
+	if rig := town.Rig("nope"); rig != nil {
+		t.Errorf("Rig(\"nope\") = %+v, want nil", rig)
+	}
+}
+
+func TestLoadTown_NoRoutes(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if _, err := LoadTown(t.TempDir()); err == nil {
+		t.Error("expected error outside a town")
+	}
+}
+
+func TestQueryTown_PerRigErrors(t *testing.T) {
+	townBeads := setupTestTown(t, func(rig string, store storage.Storage) {
+		createTownIssue(t, store, map[string]string{"beads": "bd-1", "gastown": "gt-1"}[rig], types.StatusOpen)
+	})
+	town, err := LoadTown(townBeads)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := QueryTown(context.Background(), town, func(ctx context.Context, _ TownRig, store storage.Storage) ([]*types.Issue, error) {
+		return store.SearchIssues(ctx, "", types.IssueFilter{})
+	})
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for _, res := range results {
+		switch res.Rig.Name {
+		case "ghost":
+			if res.Err == nil {
+				t.Error("expected error for missing rig")
+			}
+		default:
+			if res.Err != nil {
+				t.Errorf("rig %s: unexpected error: %v", res.Rig.Name, res.Err)
+			}
+			if len(res.Value) != 1 || !strings.HasPrefix(res.Value[0].ID, res.Rig.Prefix) {
+				t.Errorf("rig %s: got %v", res.Rig.Name, res.Value)
+			}
+		}
+	}
+}
+
+func TestParseCrossRigRef(t *testing.T) {
+	town := &Town{Rigs: []TownRig{
+		{Name: "beads", Prefix: "bd-"},
+		{Name: "gastown", Prefix: "gt-"},
+	}}
+
+	tests := []struct {
+		ref    string
+		from   string
+		want   CrossRigRef
+		wantOK bool
+	}{
+		{"external:gastown:gt-abc", "beads", CrossRigRef{Ref: "external:gastown:gt-abc", Rig: "gastown", ID: "gt-abc"}, true},
+		{"external:gt:gt-abc", "beads", CrossRigRef{Ref: "external:gt:gt-abc", Rig: "gastown", ID: "gt-abc"}, true},
+		{"external:gastown:auth", "beads", CrossRigRef{Ref: "external:gastown:auth", Rig: "gastown", Capability: "auth"}, true},
+		{"gt-abc", "beads", CrossRigRef{Ref: "gt-abc", Rig: "gastown", ID: "gt-abc"}, true},
+		{"bd-abc", "beads", CrossRigRef{}, false},
+		{"external:other:thing", "beads", CrossRigRef{}, false},
+		{"external:gastown:", "beads", CrossRigRef{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := town.ParseCrossRigRef(tt.ref, tt.from)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("ParseCrossRigRef(%q) = %+v, %v; want %+v, %v", tt.ref, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestResolveCrossRigRefs(t *testing.T) {
+	townBeads := setupTestTown(t, func(rig string, store storage.Storage) {
+		if rig != "gastown" {
+			return
+		}
+		createTownIssue(t, store, "gt-open", types.StatusOpen)
+		createTownIssue(t, store, "gt-done", types.StatusClosed)
+		createTownIssue(t, store, "gt-ship", types.StatusClosed, "provides:auth")
+	})
+	town, err := LoadTown(townBeads)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var refs []CrossRigRef
+	for _, s := range []string{
+		"external:gastown:gt-open",
+		"external:gastown:gt-done",
+		"external:gastown:gt-missing",
+		"external:gastown:auth",
+		"external:gastown:billing",
+		"external:ghost:gh-1",
+	} {
+		ref, ok := town.ParseCrossRigRef(s, "beads")
+		if !ok {
+			t.Fatalf("ParseCrossRigRef(%q) failed", s)
+		}
+		refs = append(refs, ref)
+	}
+
+	statuses := town.ResolveCrossRigRefs(context.Background(), refs)
+	want := map[string]bool{
+		"external:gastown:gt-open":    true,
+		"external:gastown:gt-done":    false,
+		"external:gastown:gt-missing": true,
+		"external:gastown:auth":       false,
+		"external:gastown:billing":    true,
+		"external:ghost:gh-1":         true,
+	}
+	for ref, blocking := range want {
+		status, ok := statuses[ref]
+		if !ok {
+			t.Errorf("%s: no status", ref)
+			continue
+		}
+		if status.Blocking != blocking {
+			t.Errorf("%s: blocking = %v (%s), want %v", ref, status.Blocking, status.Reason, blocking)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/steveyegge/beads/internal/debug"
 	"github.com/steveyegge/beads/internal/lockfile"
+	"github.com/steveyegge/beads/internal/policy"
 )
 
 // rpcDebugEnabled returns true if BD_RPC_DEBUG environment variable is set
@@ -39,6 +40,7 @@ type Client struct {
 	dbPath     string // Expected database path for validation
 	actor      string // Actor for audit trail (who is performing operations)
 	authToken  string // Cached authentication token for daemon RPC
+	actorToken string // Token proving actor to policy enforcement (see getActorToken)
 }
 
 // TryConnect attempts to connect to the daemon socket
@@ -164,6 +166,45 @@ func (c *Client) SetActor(actor string) {
 	c.actor = actor
 }
 
+// SetActorToken sets the token that proves the actor when the daemon enforces
+// a policy. It overrides BD_ACTOR_TOKEN and tokens minted from policy.key.
+func (c *Client) SetActorToken(token string) {
+	c.actorToken = token
+}
+
+// getActorToken returns the actor token to send with requests: the one set
+// explicitly or via BD_ACTOR_TOKEN, or else one minted for c.actor when this
+// process can read .beads/policy.key (i.e. it runs on the host, not in a
+// sandbox). The key is created here if a policy exists but no key does yet.
+// Returns empty string when none is available; the daemon only
+// requires one while a policy is present.
+func (c *Client) getActorToken() string {
+	if c.actorToken != "" {
+		return c.actorToken
+	}
+	if token := os.Getenv(policy.TokenEnv); token != "" {
+		return token
+	}
+	beadsDir := filepath.Dir(c.socketPath)
+	if c.dbPath != "" {
+		beadsDir = filepath.Dir(c.dbPath)
+	}
+	if c.actor == "" {
+		return ""
+	}
+	key, err := policy.ReadKey(beadsDir)
+	if os.IsNotExist(err) {
+		// A policy was just added and no token has been issued yet
+		if _, statErr := os.Stat(filepath.Join(beadsDir, policy.FileName)); statErr == nil {
+			key, err = policy.LoadOrCreateKey(beadsDir)
+		}
+	}
+	if err != nil {
+		return ""
+	}
+	return policy.IssueToken(key, c.actor)
+}
+
 // loadAuthToken loads the authentication token from the daemon token file
 // Returns empty string if token file doesn't exist (backward compatibility)
 func (c *Client) loadAuthToken() string {
@@ -221,6 +262,7 @@ func (c *Client) ExecuteWithCwd(operation string, args interface{}, cwd string)
 		Operation:     operation,
 		Args:          argsJSON,
 		Actor:         c.actor, // Who is performing this operation
+		ActorToken:    c.getActorToken(),
 		ClientVersion: ClientVersion,
 		Cwd:           cwd,
 		ExpectedDB:    c.dbPath, // Send expected database path for validation
@@ -391,6 +433,11 @@ func (c *Client) Ready(args *ReadyArgs) (*Response, error) {
 	return c.Execute(OpReady, args)
 }
 
+// Claim atomically claims an issue (or the next ready issue) via the daemon
+func (c *Client) Claim(args *ClaimArgs) (*Response, error) {
+	return c.Execute(OpClaim, args)
+}
+
 // Blocked gets blocked issues via the daemon
 func (c *Client) Blocked(args *BlockedArgs) (*Response, error) {
 	return c.Execute(OpBlocked, args)
@@ -530,6 +577,36 @@ func (c *Client) MolStale(args *MolStaleArgs) (*MolStaleResponse, error) {
 	return &result, nil
 }
 
+// ViewList lists the saved views in the daemon's config.yaml
+func (c *Client) ViewList() (*ViewListResponse, error) {
+	resp, err := c.Execute(OpViewList, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ViewListResponse
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal view list response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ViewRun runs a saved view via the daemon
+func (c *Client) ViewRun(args *ViewRunArgs) (*ViewRunResponse, error) {
+	resp, err := c.Execute(OpViewRun, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ViewRunResponse
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal view run response: %w", err)
+	}
+
+	return &result, nil
+}
+
 // cleanupStaleDaemonArtifacts removes stale daemon.pid file when socket is missing and lock is free.
 // This prevents stale artifacts from accumulating after daemon crashes.
 // Only removes pid file - lock file is managed by OS (released on process exit).
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/views"
 )
 
 // Operation constants for all bd commands
@@ -20,6 +22,7 @@ const (
 	OpCount           = "count"
 	OpShow            = "show"
 	OpReady           = "ready"
+	OpClaim           = "claim"
 	OpBlocked         = "blocked"
 	OpStale           = "stale"
 	OpStats           = "stats"
@@ -52,6 +55,10 @@ const (
 	OpGateShow   = "gate_show"
 	OpGateClose  = "gate_close"
 	OpGateWait   = "gate_wait"
+
+	// Saved view operations
+	OpViewList = "view_list"
+	OpViewRun  = "view_run"
 )
 
 // Request represents an RPC request from client to daemon
@@ -59,6 +66,7 @@ type Request struct {
 	Operation     string          `json:"operation"`
 	Args          json.RawMessage `json:"args"`
 	Actor         string          `json:"actor,omitempty"`
+	ActorToken    string          `json:"actor_token,omitempty"` // Proves Actor when a policy is enforced (see policy.IssueToken)
 	RequestID     string          `json:"request_id,omitempty"`
 	Cwd           string          `json:"cwd,omitempty"`            // Working directory for database discovery
 	ClientVersion string          `json:"client_version,omitempty"` // Client version for compatibility checks
@@ -181,6 +189,8 @@ type CloseArgs struct {
 	Session     string `json:"session,omitempty"`      // Claude Code session ID that closed this issue
 	SuggestNext bool   `json:"suggest_next,omitempty"` // Return newly unblocked issues (GH#679)
 	Force       bool   `json:"force,omitempty"`        // Force close even with open blockers (GH#962)
+	// RequireCommit refuses the close unless a commit mentions the issue
+	RequireCommit bool `json:"require_commit,omitempty"`
 }
 
 // CloseResult is returned when SuggestNext is true (GH#679)
@@ -330,6 +340,21 @@ type ReadyArgs struct {
 	IncludeDeferred bool     `json:"include_deferred,omitempty"` // Include issues with future defer_until (GH#820)
 }
 
+// ClaimArgs represents arguments for the claim operation.
+// Either ID is set, or FromReady picks the next ready issue matching the filters.
+type ClaimArgs struct {
+	ID         string   `json:"id,omitempty"`
+	FromReady  bool     `json:"from_ready,omitempty"`
+	TTL        string   `json:"ttl,omitempty"` // Lease duration (e.g. "30m"); empty uses the default
+	Priority   *int     `json:"priority,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	SortPolicy string   `json:"sort_policy,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	LabelsAny  []string `json:"labels_any,omitempty"`
+	ParentID   string   `json:"parent_id,omitempty"`
+	MolType    string   `json:"mol_type,omitempty"`
+}
+
 // BlockedArgs represents arguments for the blocked operation
 type BlockedArgs struct {
 	ParentID string `json:"parent_id,omitempty"` // Filter to descendants of this bead/epic
@@ -414,7 +439,9 @@ type StatusResponse struct {
 	AutoPull     bool   `json:"auto_pull"`              // Whether auto-pull is enabled (periodic remote sync)
 	LocalMode    bool   `json:"local_mode"`             // Whether running in local-only mode (no git)
 	SyncInterval string `json:"sync_interval"`          // Sync interval (e.g., "5s")
-	DaemonMode   string `json:"daemon_mode"`            // Sync mode: "poll" or "events"
+	DaemonMode   string `json:"daemon_mode"`            // Sync mode: "poll", "events" or "multiplex"
+	// Workspaces lists the open database paths of a multiplex daemon
+	Workspaces []string `json:"workspaces,omitempty"`
 }
 
 // HealthResponse is the response for a health check operation
@@ -643,3 +670,22 @@ type MolStaleResponse struct {
 	TotalCount     int              `json:"total_count"`
 	BlockingCount  int              `json:"blocking_count"`
 }
+
+// ViewListResponse is the response of the view_list operation
+type ViewListResponse struct {
+	Views []*views.View `json:"views"`
+}
+
+// ViewRunArgs represents arguments for the view_run operation
+type ViewRunArgs struct {
+	Name string `json:"name"` // Saved view in the daemon's config.yaml
+	// View runs this definition instead of looking Name up, for tools that
+	// build views on the fly
+	View *config.ViewConfig `json:"view,omitempty"`
+}
+
+// ViewRunResponse is the response of the view_run operation
+type ViewRunResponse struct {
+	View   *views.View    `json:"view"`
+	Issues []*types.Issue `json:"issues"`
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"sync"
 	"sync/atomic"
@@ -67,6 +68,15 @@ type Server struct {
 	auth *AuthManager
 	// Rate limiting for DoS protection
 	rateLimiter *RateLimiter
+	// Optional OpenMetrics HTTP endpoint (see StartMetricsServer)
+	metricsServer *http.Server
+	// Actor policy loaded from .beads/policy.yaml (see enforcePolicy)
+	policyCache policyCache
+	// Multiplex daemon hooks (see MuxServer): route replaces handleRequest on
+	// the shared socket, and onShutdown releases a pooled workspace instead
+	// of stopping the process.
+	route      func(*Request) Response
+	onShutdown func()
 }
 
 // Mutation event types
@@ -80,6 +90,8 @@ const (
 	MutationSquashed = "squashed" // Wisp squashed to digest
 	MutationBurned   = "burned"   // Wisp discarded without digest
 	MutationStatus   = "status"   // Status change (in_progress, completed, failed)
+	MutationGate     = "gate"     // Gate evaluated by the daemon (NewStatus: resolved, escalated, timed_out)
+	MutationSLA      = "sla"      // SLA target breached, found by the daemon (NewStatus: respond or resolve)
 )
 
 // MutationEvent represents a database mutation for event-driven sync
@@ -99,6 +111,13 @@ type MutationEvent struct {
 
 // NewServer creates a new RPC server
 func NewServer(socketPath string, store storage.Storage, workspacePath string, dbPath string) *Server {
+	return newServer(socketPath, store, workspacePath, dbPath, true)
+}
+
+// newServer creates a server. Servers pooled by a MuxServer share its socket
+// and are created without an auth manager: the mux authenticates requests
+// before routing them, and a second manager would overwrite its token file.
+func newServer(socketPath string, store storage.Storage, workspacePath string, dbPath string, withAuth bool) *Server {
 	// Parse config from env vars
 	maxConns := 100 // default
 	if env := os.Getenv("BEADS_DAEMON_MAX_CONNS"); env != "" {
@@ -145,12 +164,14 @@ func NewServer(socketPath string, store storage.Storage, workspacePath string, d
 	s.lastActivityTime.Store(time.Now())
 
 	// Initialize authentication manager
-	auth, err := NewAuthManager(socketPath, startTime)
-	if err != nil {
-		// Log warning but continue - auth will be optional for backward compatibility
-		fmt.Fprintf(os.Stderr, "Warning: failed to initialize auth manager: %v\n", err)
+	if withAuth {
+		auth, err := NewAuthManager(socketPath, startTime)
+		if err != nil {
+			// Log warning but continue - auth will be optional for backward compatibility
+			fmt.Fprintf(os.Stderr, "Warning: failed to initialize auth manager: %v\n", err)
+		}
+		s.auth = auth
 	}
-	s.auth = auth
 
 	// Initialize rate limiter (100 requests per minute per client)
 	s.rateLimiter = NewRateLimiter(100, 1*time.Minute)
@@ -199,6 +220,13 @@ func (s *Server) emitRichMutation(event MutationEvent) {
 	s.recentMutationsMu.Unlock()
 }
 
+// EmitMutation records an event raised by the daemon itself rather than by
+// an RPC request (e.g. a gate resolved by the daemon's gate evaluation), so
+// it reaches the event loop and the activity feed like any other mutation.
+func (s *Server) EmitMutation(event MutationEvent) {
+	s.emitRichMutation(event)
+}
+
 // MutationChan returns the mutation event channel for the daemon to consume
 func (s *Server) MutationChan() <-chan MutationEvent {
 	return s.mutationChan
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/storage/sqlite"
 	"github.com/steveyegge/beads/internal/types"
 	"github.com/steveyegge/beads/internal/util"
@@ -847,6 +848,21 @@ func (s *Server) handleClose(req *Request) Response {
 				Error:   fmt.Sprintf("cannot close %s: blocked by open issues %v (use --force to override)", closeArgs.ID, blockers),
 			}
 		}
+		if closeArgs.RequireCommit {
+			links, err := store.GetCommitLinks(ctx, closeArgs.ID)
+			if err != nil {
+				return Response{
+					Success: false,
+					Error:   fmt.Sprintf("failed to check linked commits: %v", err),
+				}
+			}
+			if len(links) == 0 {
+				return Response{
+					Success: false,
+					Error:   fmt.Sprintf("cannot close %s: no commit mentions it (use --force to override)", closeArgs.ID),
+				}
+			}
+		}
 	}
 
 	// Capture old status for rich mutation event
@@ -1657,8 +1673,9 @@ func (s *Server) handleShow(req *Request) Response {
 		}
 	}
 
-	// Fetch comments
+	// Fetch comments and linked commits
 	comments, _ := store.GetIssueComments(ctx, issue.ID)
+	commits, _ := store.GetCommitLinks(ctx, issue.ID)
 
 	// Create detailed response with related data
 	details := &types.IssueDetails{
@@ -1667,6 +1684,7 @@ func (s *Server) handleShow(req *Request) Response {
 		Dependencies: deps,
 		Dependents:   dependents,
 		Comments:     comments,
+		Commits:      commits,
 	}
 
 	data, _ := json.Marshal(details)
@@ -1703,6 +1721,7 @@ func (s *Server) handleReady(req *Request) Response {
 		Labels:          util.NormalizeLabels(readyArgs.Labels),
 		LabelsAny:       util.NormalizeLabels(readyArgs.LabelsAny),
 		IncludeDeferred: readyArgs.IncludeDeferred, // GH#820
+		LeaseHolder:     s.reqActor(req),
 	}
 	if readyArgs.Assignee != "" && !readyArgs.Unassigned {
 		wf.Assignee = &readyArgs.Assignee
@@ -1731,6 +1750,97 @@ func (s *Server) handleReady(req *Request) Response {
 	}
 }
 
+func (s *Server) handleClaim(req *Request) Response {
+	var claimArgs ClaimArgs
+	if err := json.Unmarshal(req.Args, &claimArgs); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid claim args: %v", err),
+		}
+	}
+
+	store := s.storage
+	if store == nil {
+		return Response{
+			Success: false,
+			Error:   "storage not available (global daemon deprecated - use local daemon instead with 'bd daemon' in your project)",
+		}
+	}
+
+	if claimArgs.ID == "" && !claimArgs.FromReady {
+		return Response{
+			Success: false,
+			Error:   "claim requires an issue ID or from_ready",
+		}
+	}
+
+	ttl := types.DefaultLeaseTTL
+	if claimArgs.TTL != "" {
+		d, err := time.ParseDuration(claimArgs.TTL)
+		if err != nil || d <= 0 {
+			return Response{
+				Success: false,
+				Error:   fmt.Sprintf("invalid ttl %q", claimArgs.TTL),
+			}
+		}
+		ttl = d
+	}
+
+	ctx := s.reqCtx(req)
+	actor := s.reqActor(req)
+
+	var issue *types.Issue
+	var err error
+	if claimArgs.FromReady {
+		wf := types.WorkFilter{
+			Type:       claimArgs.Type,
+			Priority:   claimArgs.Priority,
+			SortPolicy: types.SortPolicy(claimArgs.SortPolicy),
+			Labels:     util.NormalizeLabels(claimArgs.Labels),
+			LabelsAny:  util.NormalizeLabels(claimArgs.LabelsAny),
+		}
+		if claimArgs.ParentID != "" {
+			wf.ParentID = &claimArgs.ParentID
+		}
+		if claimArgs.MolType != "" {
+			molType := types.MolType(claimArgs.MolType)
+			wf.MolType = &molType
+		}
+		accept, policyErr := s.claimPolicyFilter(req)
+		if policyErr != nil {
+			return Response{
+				Success: false,
+				Error:   policyErr.Error(),
+			}
+		}
+		issue, err = storage.ClaimReadyMatching(ctx, store, wf, actor, ttl, accept)
+	} else {
+		issue, err = storage.ClaimIssue(ctx, store, claimArgs.ID, actor, ttl)
+	}
+	if err != nil {
+		return Response{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	s.emitRichMutation(MutationEvent{
+		Type:      MutationStatus,
+		IssueID:   issue.ID,
+		Title:     issue.Title,
+		Assignee:  issue.Assignee,
+		Actor:     actor,
+		OldStatus: string(types.StatusOpen),
+		NewStatus: string(types.StatusInProgress),
+	})
+
+	data, _ := json.Marshal(issue)
+	return Response{
+		Success: true,
+		Data:    data,
+	}
+}
+
 func (s *Server) handleBlocked(req *Request) Response {
 	var blockedArgs BlockedArgs
 	if err := json.Unmarshal(req.Args, &blockedArgs); err != nil {
@@ -233,6 +233,7 @@ func (s *Server) handleBatch(req *Request) Response {
 			Operation:     op.Operation,
 			Args:          op.Args,
 			Actor:         req.Actor,
+			ActorToken:    req.ActorToken,
 			RequestID:     req.RequestID,
 			Cwd:           req.Cwd,           // Pass through context
 			ClientVersion: req.ClientVersion, // Pass through version for compatibility checks
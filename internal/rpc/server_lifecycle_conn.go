@@ -105,6 +105,8 @@ func (s *Server) Stop() error {
 		// Signal cleanup goroutine to stop
 		close(s.shutdownChan)
 
+		s.stopMetricsServer()
+
 		// Close storage
 		if s.storage != nil {
 			if closeErr := s.storage.Close(); closeErr != nil {
@@ -222,7 +224,12 @@ func (s *Server) handleConnection(conn net.Conn) {
 			return
 		}
 
-		resp := s.handleRequest(&req)
+		var resp Response
+		if s.route != nil {
+			resp = s.route(&req)
+		} else {
+			resp = s.handleRequest(&req)
+		}
 		if err := s.writeResponse(writer, resp); err != nil {
 			// Connection broken, stop handling this connection
 			return
@@ -252,6 +259,18 @@ func (s *Server) writeResponse(writer *bufio.Writer, resp Response) error {
 }
 
 func (s *Server) handleShutdown(_ *Request) Response {
+	// A workspace pooled by a multiplex daemon is released, not the process
+	if s.onShutdown != nil {
+		go func() {
+			time.Sleep(100 * time.Millisecond) // Give time for response to be sent
+			s.onShutdown()
+		}()
+		return Response{
+			Success: true,
+			Data:    json.RawMessage(`{"message":"Workspace released by multiplex daemon"}`),
+		}
+	}
+
 	// Schedule shutdown in a goroutine so we can return a response first
 	go func() {
 		time.Sleep(100 * time.Millisecond) // Give time for response to be sent
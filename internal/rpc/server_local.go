@@ -0,0 +1,22 @@
+package rpc
+
+import "github.com/steveyegge/beads/internal/storage"
+
+// NewLocalServer returns a server that is never started: requests are
+// passed to Handle in-process instead of arriving on a socket. Commands
+// that speak the RPC protocol (bd mcp serve) use it when no daemon is
+// running, so direct mode goes through the same handlers as the daemon.
+//
+// Requests are not authenticated or rate limited. Mutations are still
+// delivered on MutationChan; the caller is responsible for flushing them
+// to JSONL.
+func NewLocalServer(store storage.Storage, workspacePath, dbPath string) *Server {
+	s := newServer("", store, workspacePath, dbPath, false)
+	s.rateLimiter = nil
+	return s
+}
+
+// Handle processes one request in-process. It is safe for concurrent use.
+func (s *Server) Handle(req *Request) Response {
+	return s.handleRequest(req)
+}
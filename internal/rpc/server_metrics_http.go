@@ -0,0 +1,252 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Metadata keys the daemon writes after a successful export and a successful
+// sync with the remote. The metrics endpoint reports their age.
+const (
+	MetadataLastExportTime = "daemon_last_export_time"
+	MetadataLastSyncTime   = "daemon_last_sync_time"
+)
+
+// openMetricsContentType is the content type defined by the OpenMetrics spec.
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// metricsStorageTimeout bounds the storage queries made for a single scrape.
+const metricsStorageTimeout = 5 * time.Second
+
+// latencyQuantiles maps OpenMetrics quantile labels to the LatencyStats field
+// that holds the value.
+var latencyQuantiles = []struct {
+	label string
+	value func(LatencyStats) float64
+}{
+	{"0", func(l LatencyStats) float64 { return l.MinMS }},
+	{"0.5", func(l LatencyStats) float64 { return l.P50MS }},
+	{"0.95", func(l LatencyStats) float64 { return l.P95MS }},
+	{"0.99", func(l LatencyStats) float64 { return l.P99MS }},
+	{"1", func(l LatencyStats) float64 { return l.MaxMS }},
+}
+
+// StartMetricsServer serves daemon metrics in OpenMetrics text format at
+// /metrics on addr (e.g. "127.0.0.1:9464"). The listener is bound before
+// returning so address errors surface immediately; it is closed by Stop.
+func (s *Server) StartMetricsServer(addr string) (net.Addr, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on metrics address %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.handleMetricsHTTP)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	s.mu.Lock()
+	s.metricsServer = srv
+	s.mu.Unlock()
+
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Warning: metrics server stopped: %v\n", err)
+		}
+	}()
+
+	return listener.Addr(), nil
+}
+
+// stopMetricsServer shuts down the metrics HTTP server if one is running.
+func (s *Server) stopMetricsServer() {
+	s.mu.Lock()
+	srv := s.metricsServer
+	s.metricsServer = nil
+	s.mu.Unlock()
+
+	if srv == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(ctx)
+}
+
+func (s *Server) handleMetricsHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), metricsStorageTimeout)
+	defer cancel()
+
+	w.Header().Set("Content-Type", openMetricsContentType)
+	if err := s.WriteOpenMetrics(ctx, w); err != nil {
+		// Headers are already sent; the missing "# EOF" marks the scrape as failed.
+		fmt.Fprintf(os.Stderr, "Warning: failed to write metrics: %v\n", err)
+	}
+}
+
+// WriteOpenMetrics writes the current daemon metrics and storage gauges to w
+// in OpenMetrics text format, terminated by "# EOF". Storage gauges whose
+// query fails are omitted rather than failing the whole scrape.
+func (s *Server) WriteOpenMetrics(ctx context.Context, w io.Writer) error {
+	mw := &metricsWriter{w: bufio.NewWriter(w)}
+	snapshot := s.metrics.Snapshot(int(atomic.LoadInt32(&s.activeConns)))
+
+	mw.family("bd_daemon_uptime_seconds", "gauge", "Time since the daemon started.")
+	mw.sample("bd_daemon_uptime_seconds", nil, time.Since(s.startTime).Seconds())
+
+	mw.family("bd_daemon_requests", "counter", "RPC requests handled, by operation.")
+	for _, op := range snapshot.Operations {
+		mw.sample("bd_daemon_requests_total", []string{"operation", op.Operation}, float64(op.TotalCount))
+	}
+	mw.family("bd_daemon_request_errors", "counter", "RPC requests that returned an error, by operation.")
+	for _, op := range snapshot.Operations {
+		mw.sample("bd_daemon_request_errors_total", []string{"operation", op.Operation}, float64(op.ErrorCount))
+	}
+	mw.family("bd_daemon_request_latency_seconds", "gauge", "RPC latency quantiles over the most recent requests, by operation.")
+	for _, op := range snapshot.Operations {
+		if op.TotalCount == 0 {
+			continue
+		}
+		for _, q := range latencyQuantiles {
+			mw.sample("bd_daemon_request_latency_seconds", []string{"operation", op.Operation, "quantile", q.label}, q.value(op.Latency)/1000)
+		}
+	}
+
+	mw.family("bd_daemon_connections", "counter", "Connections accepted.")
+	mw.sample("bd_daemon_connections_total", nil, float64(snapshot.TotalConns))
+	mw.family("bd_daemon_rejected_connections", "counter", "Connections rejected because the connection limit was reached.")
+	mw.sample("bd_daemon_rejected_connections_total", nil, float64(snapshot.RejectedConns))
+	mw.family("bd_daemon_active_connections", "gauge", "Connections currently being served.")
+	mw.sample("bd_daemon_active_connections", nil, float64(snapshot.ActiveConns))
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	mw.family("bd_daemon_memory_alloc_bytes", "gauge", "Bytes of allocated heap objects.")
+	mw.sample("bd_daemon_memory_alloc_bytes", nil, float64(memStats.Alloc))
+	mw.family("bd_daemon_memory_sys_bytes", "gauge", "Bytes of memory obtained from the OS.")
+	mw.sample("bd_daemon_memory_sys_bytes", nil, float64(memStats.Sys))
+	mw.family("bd_daemon_goroutines", "gauge", "Number of goroutines.")
+	mw.sample("bd_daemon_goroutines", nil, float64(snapshot.GoroutineCount))
+
+	if s.storage != nil {
+		s.writeStorageMetrics(ctx, mw)
+	}
+
+	mw.line("# EOF")
+	if mw.err != nil {
+		return mw.err
+	}
+	return mw.w.Flush()
+}
+
+func (s *Server) writeStorageMetrics(ctx context.Context, mw *metricsWriter) {
+	if stats, err := s.storage.GetStatistics(ctx); err == nil {
+		mw.family("bd_issues", "gauge", "Issues by status.")
+		for _, c := range []struct {
+			status types.Status
+			count  int
+		}{
+			{types.StatusOpen, stats.OpenIssues},
+			{types.StatusInProgress, stats.InProgressIssues},
+			{types.StatusDeferred, stats.DeferredIssues},
+			{types.StatusClosed, stats.ClosedIssues},
+			{types.StatusTombstone, stats.TombstoneIssues},
+		} {
+			mw.sample("bd_issues", []string{"status", string(c.status)}, float64(c.count))
+		}
+		mw.family("bd_issues_ready", "gauge", "Open issues with no open blockers.")
+		mw.sample("bd_issues_ready", nil, float64(stats.ReadyIssues))
+		mw.family("bd_issues_blocked", "gauge", "Issues waiting on open blockers.")
+		mw.sample("bd_issues_blocked", nil, float64(stats.BlockedIssues))
+	}
+
+	if dirty, err := s.storage.GetDirtyIssues(ctx); err == nil {
+		mw.family("bd_issues_dirty", "gauge", "Issues changed since the last JSONL export.")
+		mw.sample("bd_issues_dirty", nil, float64(len(dirty)))
+	}
+
+	for _, m := range []struct {
+		name, help, key string
+	}{
+		{"bd_daemon_last_export_age_seconds", "Time since the daemon last exported to JSONL.", MetadataLastExportTime},
+		{"bd_daemon_last_sync_age_seconds", "Time since the daemon last synced with the remote.", MetadataLastSyncTime},
+	} {
+		value, err := s.storage.GetMetadata(ctx, m.key)
+		if err != nil || value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			continue
+		}
+		mw.family(m.name, "gauge", m.help)
+		mw.sample(m.name, nil, time.Since(t).Seconds())
+	}
+}
+
+// metricsWriter writes OpenMetrics lines, remembering the first write error.
+type metricsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (mw *metricsWriter) line(s string) {
+	if mw.err != nil {
+		return
+	}
+	_, mw.err = mw.w.WriteString(s + "\n")
+}
+
+func (mw *metricsWriter) family(name, metricType, help string) {
+	mw.line("# TYPE " + name + " " + metricType)
+	mw.line("# HELP " + name + " " + help)
+}
+
+// sample writes one sample; labels alternate name and value.
+func (mw *metricsWriter) sample(name string, labels []string, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labels[i])
+			b.WriteString(`="`)
+			b.WriteString(escapeLabelValue(labels[i+1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	mw.line(b.String())
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
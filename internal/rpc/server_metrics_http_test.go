@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestMetricsHTTPEndpoint(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+	store := newTestStore(t, dbPath)
+	defer store.Close()
+	ctx := context.Background()
+
+	for _, id := range []string{"bd-1", "bd-2"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := store.CloseIssue(ctx, "bd-2", "done", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+	exported := time.Now().Add(-time.Minute).Format(time.RFC3339Nano)
+	if err := store.SetMetadata(ctx, MetadataLastExportTime, exported); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(newTestSocketPath(t), store, tmpDir, dbPath)
+	server.metrics.RecordRequest(OpShow, 4*time.Millisecond)
+	server.metrics.RecordRequest(OpShow, 8*time.Millisecond)
+	server.metrics.RecordError(OpShow)
+
+	addr, err := server.StartMetricsServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("StartMetricsServer failed: %v", err)
+	}
+	defer server.stopMetricsServer()
+
+	resp, err := http.Get("http://" + addr.String() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(data)
+
+	for _, want := range []string{
+		"# TYPE bd_daemon_requests counter\n",
+		`bd_daemon_requests_total{operation="show"} 2` + "\n",
+		`bd_daemon_request_errors_total{operation="show"} 1` + "\n",
+		`bd_daemon_request_latency_seconds{operation="show",quantile="1"} 0.008` + "\n",
+		`bd_issues{status="open"} 1` + "\n",
+		`bd_issues{status="closed"} 1` + "\n",
+		"bd_issues_ready 1\n",
+		"bd_issues_blocked 0\n",
+		"bd_issues_dirty 2\n",
+		"bd_daemon_last_export_age_seconds ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "bd_daemon_last_sync_age_seconds") {
+		t.Error("last sync age should be omitted when no sync was recorded")
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Error("metrics output must end with # EOF")
+	}
+
+	resp, err = http.Post("http://"+addr.String()+"/metrics", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	if got := escapeLabelValue("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("escapeLabelValue = %q", got)
+	}
+}
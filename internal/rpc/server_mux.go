@@ -0,0 +1,481 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/steveyegge/beads/internal/beads"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// DaemonModeMultiplex is the StatusResponse.DaemonMode of a multiplex daemon
+// and of the workspaces it serves.
+const DaemonModeMultiplex = "multiplex"
+
+// DefaultMuxIdleTimeout is how long a pooled workspace may go without
+// requests before the multiplex daemon closes it.
+const DefaultMuxIdleTimeout = 30 * time.Minute
+
+// MuxSocketPath returns the socket of the shared multiplex daemon,
+// ~/.beads/mux/bd.sock. Its directory also holds the daemon's lock, PID file,
+// auth token and log. BEADS_MUX_SOCKET overrides the location.
+func MuxSocketPath() string {
+	if socketPath := os.Getenv("BEADS_MUX_SOCKET"); socketPath != "" {
+		return socketPath
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".beads", "mux", "bd.sock")
+}
+
+// MuxOpener opens the storage of the workspace whose database is dbPath.
+// release, if non-nil, is called after the workspace is evicted and its
+// storage closed (e.g. to release the workspace's daemon lock).
+type MuxOpener func(dbPath string) (store storage.Storage, release func(), err error)
+
+// MuxRunner runs a workspace's background loops (export, import, sync)
+// until ctx is cancelled. server and store are the pooled Server and storage
+// of the workspace whose database is dbPath.
+type MuxRunner func(ctx context.Context, server *Server, store storage.Storage, dbPath string)
+
+// MuxServer is a multiplex daemon: one process serving many workspaces over
+// a single shared socket. Requests are routed by their ExpectedDB (or the
+// workspace containing their Cwd) to a pooled per-workspace Server, which is
+// opened on first use and evicted after it has been idle for the idle timeout.
+type MuxServer struct {
+	*Server // shared socket listener, auth and connection handling
+
+	open        MuxOpener
+	run         MuxRunner
+	idleTimeout time.Duration
+
+	poolMu     sync.Mutex
+	workspaces map[string]*muxWorkspace
+	closed     bool
+}
+
+// muxWorkspace is a workspace held open by a MuxServer. It is pooled while it
+// is being opened and while it is being closed, so that concurrent requests
+// for its database wait for it instead of opening the database a second time.
+type muxWorkspace struct {
+	server   *Server
+	release  func()
+	cancel   context.CancelFunc
+	done     chan struct{} // closed when the runner returns
+	inflight sync.WaitGroup
+
+	opened chan struct{} // closed once the open finishes; err is then set on failure
+	err    error
+	gone   chan struct{} // closed once a closing workspace is released and unpooled
+
+	// Guarded by MuxServer.poolMu
+	closing  bool
+	active   int
+	lastUsed time.Time
+}
+
+// isOpen reports whether the workspace finished opening successfully.
+func (ws *muxWorkspace) isOpen() bool {
+	select {
+	case <-ws.opened:
+		return ws.err == nil
+	default:
+		return false
+	}
+}
+
+// NewMuxServer creates a multiplex daemon listening on socketPath. A zero
+// idleTimeout uses DefaultMuxIdleTimeout; run may be nil.
+func NewMuxServer(socketPath string, open MuxOpener, run MuxRunner, idleTimeout time.Duration) *MuxServer {
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultMuxIdleTimeout
+	}
+	m := &MuxServer{
+		Server:      NewServer(socketPath, nil, "", ""),
+		open:        open,
+		run:         run,
+		idleTimeout: idleTimeout,
+		workspaces:  make(map[string]*muxWorkspace),
+	}
+	m.Server.route = m.handleRequest
+	m.Server.SetConfig(false, false, false, false, "", DaemonModeMultiplex)
+	return m
+}
+
+// Start listens on the shared socket and serves requests until the daemon is
+// stopped, then closes every pooled workspace.
+func (m *MuxServer) Start(ctx context.Context) error {
+	go m.evictIdleLoop()
+	err := m.Server.Start(ctx)
+	m.closeWorkspaces()
+	return err
+}
+
+// Stop stops the shared socket and closes every pooled workspace.
+func (m *MuxServer) Stop() error {
+	err := m.Server.Stop()
+	m.closeWorkspaces()
+	return err
+}
+
+// Workspaces returns the database paths of the open workspaces, sorted.
+func (m *MuxServer) Workspaces() []string {
+	m.poolMu.Lock()
+	defer m.poolMu.Unlock()
+	paths := make([]string, 0, len(m.workspaces))
+	for dbPath, ws := range m.workspaces {
+		if ws.isOpen() && !ws.closing {
+			paths = append(paths, dbPath)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// handleRequest authenticates a request on the shared socket and either
+// answers it (daemon-level ping, health, status, metrics and shutdown) or
+// routes it to the workspace it names.
+func (m *MuxServer) handleRequest(req *Request) Response {
+	if m.auth != nil {
+		if err := m.auth.ValidateRequestAuth(req); err != nil {
+			m.metrics.RecordError(req.Operation)
+			return Response{Success: false, Error: errorSanitizer.SanitizeError(err)}
+		}
+	}
+
+	dbPath := muxDatabasePath(req)
+	if dbPath == "" {
+		return m.handleDaemonRequest(req)
+	}
+
+	touch := req.Operation != OpPing && req.Operation != OpHealth &&
+		req.Operation != OpStatus && req.Operation != OpMetrics
+	ws, err := m.acquire(dbPath, touch)
+	if err != nil {
+		m.metrics.RecordError(req.Operation)
+		return Response{Success: false, Error: fmt.Sprintf("multiplex daemon cannot serve %s: %v", dbPath, err)}
+	}
+	defer m.releaseRequest(ws)
+
+	return ws.server.handleRequest(req)
+}
+
+// handleDaemonRequest answers requests that name no workspace.
+func (m *MuxServer) handleDaemonRequest(req *Request) Response {
+	start := time.Now()
+	defer func() { m.metrics.RecordRequest(req.Operation, time.Since(start)) }()
+
+	if req.Operation != OpPing && req.Operation != OpHealth {
+		if err := m.checkVersionCompatibility(req.ClientVersion); err != nil {
+			m.metrics.RecordError(req.Operation)
+			return Response{Success: false, Error: errorSanitizer.SanitizeError(err)}
+		}
+	}
+	m.lastActivityTime.Store(time.Now())
+
+	switch req.Operation {
+	case OpPing:
+		return m.handlePing(req)
+	case OpHealth:
+		return m.handleMuxHealth(req)
+	case OpStatus:
+		return m.handleMuxStatus(req)
+	case OpMetrics:
+		return m.handleMetrics(req)
+	case OpShutdown:
+		return m.handleShutdown(req)
+	}
+	m.metrics.RecordError(req.Operation)
+	return Response{
+		Success: false,
+		Error:   fmt.Sprintf("multiplex daemon: %s request names no workspace (no expected database and no .beads above %q)", req.Operation, req.Cwd),
+	}
+}
+
+func (m *MuxServer) handleMuxHealth(req *Request) Response {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	compatible := true
+	if req.ClientVersion != "" && m.checkVersionCompatibility(req.ClientVersion) != nil {
+		compatible = false
+	}
+
+	data, _ := json.Marshal(HealthResponse{
+		Status:        "healthy",
+		Version:       ServerVersion,
+		ClientVersion: req.ClientVersion,
+		Compatible:    compatible,
+		Uptime:        time.Since(m.startTime).Seconds(),
+		ActiveConns:   atomic.LoadInt32(&m.activeConns),
+		MaxConns:      m.maxConns,
+		MemoryAllocMB: mem.Alloc / 1024 / 1024,
+	})
+	return Response{Success: true, Data: data}
+}
+
+func (m *MuxServer) handleMuxStatus(_ *Request) Response {
+	lastActivity := m.lastActivityTime.Load().(time.Time)
+	data, _ := json.Marshal(StatusResponse{
+		Version:          ServerVersion,
+		SocketPath:       m.socketPath,
+		PID:              os.Getpid(),
+		UptimeSeconds:    time.Since(m.startTime).Seconds(),
+		LastActivityTime: lastActivity.Format(time.RFC3339),
+		DaemonMode:       DaemonModeMultiplex,
+		Workspaces:       m.Workspaces(),
+	})
+	return Response{Success: true, Data: data}
+}
+
+// acquire returns the pooled workspace for dbPath, opening it if needed, and
+// counts the caller as in flight until releaseRequest. The database is opened
+// outside poolMu, so a slow open only delays requests for that workspace;
+// requests that find it opening or closing wait for that to finish.
+func (m *MuxServer) acquire(dbPath string, touch bool) (*muxWorkspace, error) {
+	for {
+		m.poolMu.Lock()
+		if m.closed {
+			m.poolMu.Unlock()
+			return nil, errors.New("daemon is shutting down")
+		}
+
+		ws := m.workspaces[dbPath]
+		switch {
+		case ws == nil:
+			ws = &muxWorkspace{
+				opened:   make(chan struct{}),
+				gone:     make(chan struct{}),
+				lastUsed: time.Now(),
+			}
+			m.workspaces[dbPath] = ws
+			m.poolMu.Unlock()
+			if err := m.openWorkspace(dbPath, ws); err != nil {
+				return nil, err
+			}
+			continue
+		case ws.closing:
+			m.poolMu.Unlock()
+			<-ws.gone
+			continue
+		case !ws.isOpen():
+			m.poolMu.Unlock()
+			<-ws.opened
+			if ws.err != nil {
+				return nil, ws.err
+			}
+			continue
+		}
+
+		if touch {
+			ws.lastUsed = time.Now()
+		}
+		ws.active++
+		ws.inflight.Add(1)
+		m.poolMu.Unlock()
+		return ws, nil
+	}
+}
+
+// openWorkspace opens the storage of the pending workspace ws and starts its
+// runner. On failure ws is unpooled and the error is left for its waiters.
+func (m *MuxServer) openWorkspace(dbPath string, ws *muxWorkspace) error {
+	defer close(ws.opened)
+
+	store, release, err := m.open(dbPath)
+	if err != nil {
+		ws.err = err
+		m.poolMu.Lock()
+		if m.workspaces[dbPath] == ws {
+			delete(m.workspaces, dbPath)
+		}
+		m.poolMu.Unlock()
+		return err
+	}
+	beadsDir := filepath.Dir(dbPath)
+	server := newServer(m.socketPath, store, filepath.Dir(beadsDir), dbPath, false)
+	server.SetConfig(false, false, false, false, "", DaemonModeMultiplex)
+	server.onShutdown = func() { m.evict(dbPath, ws) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ws.server = server
+	ws.release = release
+	ws.cancel = cancel
+	ws.done = make(chan struct{})
+	if m.run != nil {
+		go func() {
+			defer close(ws.done)
+			m.run(ctx, server, store, dbPath)
+		}()
+	} else {
+		close(ws.done)
+	}
+	return nil
+}
+
+func (m *MuxServer) releaseRequest(ws *muxWorkspace) {
+	m.poolMu.Lock()
+	ws.active--
+	m.poolMu.Unlock()
+	ws.inflight.Done()
+}
+
+// evict closes ws (if it is still pooled under dbPath) once its in-flight
+// requests finish. It stays pooled, marked closing, until it is released.
+func (m *MuxServer) evict(dbPath string, ws *muxWorkspace) {
+	m.poolMu.Lock()
+	if m.workspaces[dbPath] != ws || ws.closing {
+		m.poolMu.Unlock()
+		return
+	}
+	ws.closing = true
+	m.poolMu.Unlock()
+
+	m.closeWorkspace(ws)
+	m.unpool(dbPath, ws)
+}
+
+// unpool removes a closed workspace from the pool and wakes requests waiting
+// for it to go away.
+func (m *MuxServer) unpool(dbPath string, ws *muxWorkspace) {
+	m.poolMu.Lock()
+	if m.workspaces[dbPath] == ws {
+		delete(m.workspaces, dbPath)
+	}
+	m.poolMu.Unlock()
+	close(ws.gone)
+}
+
+// evictIdleLoop periodically closes workspaces that have had no requests for
+// the idle timeout, until the daemon shuts down.
+func (m *MuxServer) evictIdleLoop() {
+	interval := m.idleTimeout / 4
+	if interval < time.Second {
+		interval = time.Second
+	} else if interval > time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.shutdownChan:
+			return
+		case <-ticker.C:
+			m.evictIdle()
+		}
+	}
+}
+
+// evictIdle closes workspaces idle for at least the idle timeout. Workspaces
+// with requests in flight, or still opening, are kept.
+func (m *MuxServer) evictIdle() {
+	idle := make(map[string]*muxWorkspace)
+	m.poolMu.Lock()
+	for dbPath, ws := range m.workspaces {
+		if ws.isOpen() && !ws.closing && ws.active == 0 && time.Since(ws.lastUsed) >= m.idleTimeout {
+			ws.closing = true
+			idle[dbPath] = ws
+		}
+	}
+	m.poolMu.Unlock()
+
+	for dbPath, ws := range idle {
+		m.closeWorkspace(ws)
+		m.unpool(dbPath, ws)
+	}
+}
+
+// closeWorkspaces closes every pooled workspace and refuses new ones.
+// Workspaces still opening are closed once their open finishes.
+func (m *MuxServer) closeWorkspaces() {
+	pooled := make(map[string]*muxWorkspace)
+	m.poolMu.Lock()
+	m.closed = true
+	for dbPath, ws := range m.workspaces {
+		if !ws.closing {
+			ws.closing = true
+			pooled[dbPath] = ws
+		}
+	}
+	m.poolMu.Unlock()
+
+	for dbPath, ws := range pooled {
+		<-ws.opened
+		if ws.err == nil {
+			m.closeWorkspace(ws)
+		}
+		m.unpool(dbPath, ws)
+	}
+}
+
+// closeWorkspace waits for in-flight requests, stops the workspace's loops,
+// closes its storage and releases it.
+func (m *MuxServer) closeWorkspace(ws *muxWorkspace) {
+	ws.inflight.Wait()
+	ws.cancel()
+	select {
+	case <-ws.done:
+	case <-time.After(30 * time.Second):
+		fmt.Fprintf(os.Stderr, "Warning: sync loops for %s did not stop within 30s\n", ws.server.dbPath)
+	}
+
+	ws.server.stopOnce.Do(func() {
+		ws.server.mu.Lock()
+		ws.server.shutdown = true
+		ws.server.mu.Unlock()
+		close(ws.server.shutdownChan)
+		if ws.server.storage != nil {
+			if err := ws.server.storage.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to close storage for %s: %v\n", ws.server.dbPath, err)
+			}
+		}
+	})
+
+	if ws.release != nil {
+		ws.release()
+	}
+}
+
+// muxDatabasePath returns the pool key for a request: the client's expected
+// database, or else the database of the workspace containing its Cwd.
+// Daemon-level operations without an expected database are never routed by
+// Cwd, so health checks and shutdowns address the multiplex daemon itself.
+func muxDatabasePath(req *Request) string {
+	if req.ExpectedDB != "" {
+		return utils.CanonicalizePath(req.ExpectedDB)
+	}
+	switch req.Operation {
+	case OpPing, OpHealth, OpStatus, OpMetrics, OpShutdown:
+		return ""
+	}
+	if req.Cwd == "" {
+		return ""
+	}
+
+	for dir := utils.CanonicalizePath(req.Cwd); ; {
+		beadsDir := beads.FollowRedirect(filepath.Join(dir, ".beads"))
+		candidate := filepath.Join(beadsDir, beads.CanonicalDatabaseName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return utils.CanonicalizePath(candidate)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,364 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// muxTestOpener opens workspace databases for a test MuxServer and counts
+// opens and releases per database.
+type muxTestOpener struct {
+	t        *testing.T
+	mu       sync.Mutex
+	opened   map[string]int
+	released map[string]int
+}
+
+func newMuxTestOpener(t *testing.T) *muxTestOpener {
+	return &muxTestOpener{t: t, opened: map[string]int{}, released: map[string]int{}}
+}
+
+func (o *muxTestOpener) open(dbPath string) (storage.Storage, func(), error) {
+	store := newTestStore(o.t, dbPath)
+	o.mu.Lock()
+	o.opened[dbPath]++
+	o.mu.Unlock()
+	return store, func() {
+		o.mu.Lock()
+		o.released[dbPath]++
+		o.mu.Unlock()
+	}, nil
+}
+
+func (o *muxTestOpener) counts(dbPath string) (opened, released int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.opened[dbPath], o.released[dbPath]
+}
+
+// newMuxTestWorkspace creates a workspace with an initialized .beads/beads.db
+// and returns its root and canonical database path.
+func newMuxTestWorkspace(t *testing.T) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	beadsDir := filepath.Join(root, ".beads")
+	if err := os.MkdirAll(beadsDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	dbPath := filepath.Join(beadsDir, "beads.db")
+	if err := newTestStore(t, dbPath).Close(); err != nil {
+		t.Fatal(err)
+	}
+	return root, utils.CanonicalizePath(dbPath)
+}
+
+func startMuxTestServer(t *testing.T, opener *muxTestOpener, idleTimeout time.Duration) (*MuxServer, string) {
+	t.Helper()
+	socketPath := newTestSocketPath(t)
+	mux := NewMuxServer(socketPath, opener.open, nil, idleTimeout)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- mux.Start(context.Background()) }()
+	select {
+	case <-mux.WaitReady():
+	case err := <-errCh:
+		t.Fatalf("mux server failed to start: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("mux server did not become ready")
+	}
+	t.Cleanup(func() { _ = mux.Stop() })
+	return mux, socketPath
+}
+
+func connectMux(t *testing.T, socketPath, dbPath string) *Client {
+	t.Helper()
+	client, err := TryConnect(socketPath)
+	if err != nil || client == nil {
+		t.Fatalf("failed to connect to mux server: %v", err)
+	}
+	client.SetDatabasePath(dbPath)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func muxListIDs(t *testing.T, client *Client) []string {
+	t.Helper()
+	resp, err := client.List(&ListArgs{})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var issues []*types.Issue
+	if err := json.Unmarshal(resp.Data, &issues); err != nil {
+		t.Fatalf("failed to decode list: %v", err)
+	}
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
+
+func TestMuxServerRoutesByDatabase(t *testing.T) {
+	_, dbA := newMuxTestWorkspace(t)
+	_, dbB := newMuxTestWorkspace(t)
+	opener := newMuxTestOpener(t)
+	mux, socketPath := startMuxTestServer(t, opener, time.Hour)
+
+	clientA := connectMux(t, socketPath, dbA)
+	clientB := connectMux(t, socketPath, dbB)
+
+	if _, err := clientA.Create(&CreateArgs{ID: "bd-a1", Title: "in A", IssueType: "task", Priority: 2}); err != nil {
+		t.Fatalf("Create in A failed: %v", err)
+	}
+	if _, err := clientB.Create(&CreateArgs{ID: "bd-b1", Title: "in B", IssueType: "task", Priority: 2}); err != nil {
+		t.Fatalf("Create in B failed: %v", err)
+	}
+
+	if ids := muxListIDs(t, clientA); len(ids) != 1 || ids[0] != "bd-a1" {
+		t.Errorf("workspace A issues = %v, want [bd-a1]", ids)
+	}
+	if ids := muxListIDs(t, clientB); len(ids) != 1 || ids[0] != "bd-b1" {
+		t.Errorf("workspace B issues = %v, want [bd-b1]", ids)
+	}
+
+	for _, dbPath := range []string{dbA, dbB} {
+		if opened, _ := opener.counts(dbPath); opened != 1 {
+			t.Errorf("%s opened %d times, want 1", dbPath, opened)
+		}
+	}
+
+	daemonClient := connectMux(t, socketPath, "")
+	status, err := daemonClient.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.DaemonMode != DaemonModeMultiplex {
+		t.Errorf("DaemonMode = %q, want %q", status.DaemonMode, DaemonModeMultiplex)
+	}
+	if len(status.Workspaces) != 2 {
+		t.Errorf("Workspaces = %v, want both databases", status.Workspaces)
+	}
+	if got := mux.Workspaces(); len(got) != 2 {
+		t.Errorf("Workspaces() = %v, want 2 entries", got)
+	}
+}
+
+func TestMuxServerRoutesByCwd(t *testing.T) {
+	root, dbPath := newMuxTestWorkspace(t)
+	opener := newMuxTestOpener(t)
+	_, socketPath := startMuxTestServer(t, opener, time.Hour)
+
+	subdir := filepath.Join(root, "src", "pkg")
+	if err := os.MkdirAll(subdir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(subdir)
+
+	client := connectMux(t, socketPath, "")
+	if _, err := client.Create(&CreateArgs{ID: "bd-c1", Title: "by cwd", IssueType: "task", Priority: 2}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if opened, _ := opener.counts(dbPath); opened != 1 {
+		t.Errorf("workspace opened %d times, want 1 (routed by cwd)", opened)
+	}
+
+	t.Chdir(t.TempDir())
+	if _, err := client.List(&ListArgs{}); err == nil {
+		t.Error("expected an error for a request outside any workspace")
+	}
+}
+
+func TestMuxServerEvictsIdleWorkspaces(t *testing.T) {
+	_, dbA := newMuxTestWorkspace(t)
+	_, dbB := newMuxTestWorkspace(t)
+	opener := newMuxTestOpener(t)
+	mux, socketPath := startMuxTestServer(t, opener, 200*time.Millisecond)
+
+	clientA := connectMux(t, socketPath, dbA)
+	if _, err := clientA.List(&ListArgs{}); err != nil {
+		t.Fatalf("List in A failed: %v", err)
+	}
+	time.Sleep(250 * time.Millisecond)
+
+	clientB := connectMux(t, socketPath, dbB)
+	if _, err := clientB.List(&ListArgs{}); err != nil {
+		t.Fatalf("List in B failed: %v", err)
+	}
+	mux.evictIdle()
+
+	if _, released := opener.counts(dbA); released != 1 {
+		t.Errorf("idle workspace A released %d times, want 1", released)
+	}
+	if _, released := opener.counts(dbB); released != 0 {
+		t.Errorf("active workspace B released %d times, want 0", released)
+	}
+	if got := mux.Workspaces(); len(got) != 1 || got[0] != dbB {
+		t.Errorf("Workspaces() = %v, want [%s]", got, dbB)
+	}
+
+	// A request to an evicted workspace reopens it.
+	if _, err := clientA.List(&ListArgs{}); err != nil {
+		t.Fatalf("List in A after eviction failed: %v", err)
+	}
+	if opened, _ := opener.counts(dbA); opened != 2 {
+		t.Errorf("workspace A opened %d times, want 2", opened)
+	}
+}
+
+func TestMuxServerShutdownReleasesOnlyWorkspace(t *testing.T) {
+	_, dbA := newMuxTestWorkspace(t)
+	_, dbB := newMuxTestWorkspace(t)
+	opener := newMuxTestOpener(t)
+	mux, socketPath := startMuxTestServer(t, opener, time.Hour)
+
+	clientA := connectMux(t, socketPath, dbA)
+	clientB := connectMux(t, socketPath, dbB)
+	for _, client := range []*Client{clientA, clientB} {
+		if _, err := client.List(&ListArgs{}); err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+	}
+
+	if err := clientA.Shutdown(); err != nil {
+		t.Fatalf("Shutdown of workspace A failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, released := opener.counts(dbA); released == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("workspace A was not released after shutdown")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if _, released := opener.counts(dbB); released != 0 {
+		t.Errorf("workspace B released %d times, want 0", released)
+	}
+	if _, err := clientB.List(&ListArgs{}); err != nil {
+		t.Errorf("workspace B unusable after A shut down: %v", err)
+	}
+	if got := mux.Workspaces(); len(got) != 1 || got[0] != dbB {
+		t.Errorf("Workspaces() = %v, want [%s]", got, dbB)
+	}
+}
+
+func TestMuxServerOpenDoesNotBlockOtherWorkspaces(t *testing.T) {
+	_, dbA := newMuxTestWorkspace(t)
+	_, dbB := newMuxTestWorkspace(t)
+	opener := newMuxTestOpener(t)
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	mux := NewMuxServer(newTestSocketPath(t), func(dbPath string) (storage.Storage, func(), error) {
+		if dbPath == dbA {
+			close(entered)
+			<-unblock
+		}
+		return opener.open(dbPath)
+	}, nil, time.Hour)
+	t.Cleanup(mux.closeWorkspaces)
+
+	slowDone := make(chan error, 1)
+	go func() {
+		ws, err := mux.acquire(dbA, true)
+		if err == nil {
+			mux.releaseRequest(ws)
+		}
+		slowDone <- err
+	}()
+	<-entered
+
+	fastDone := make(chan error, 1)
+	go func() {
+		ws, err := mux.acquire(dbB, true)
+		if err == nil {
+			mux.releaseRequest(ws)
+		}
+		fastDone <- err
+	}()
+	select {
+	case err := <-fastDone:
+		if err != nil {
+			t.Fatalf("acquire B failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("acquire B blocked behind the open of A")
+	}
+
+	close(unblock)
+	if err := <-slowDone; err != nil {
+		t.Fatalf("acquire A failed: %v", err)
+	}
+	if opened, _ := opener.counts(dbA); opened != 1 {
+		t.Errorf("workspace A opened %d times, want 1", opened)
+	}
+}
+
+func TestMuxServerWaitsForClosingWorkspace(t *testing.T) {
+	_, dbA := newMuxTestWorkspace(t)
+	var mu sync.Mutex
+	held := false
+	var releaseOnce sync.Once
+	releasing := make(chan struct{})
+	unblock := make(chan struct{})
+	mux := NewMuxServer(newTestSocketPath(t), func(dbPath string) (storage.Storage, func(), error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if held {
+			return nil, nil, errors.New("workspace has a daemon of its own")
+		}
+		held = true
+		return newTestStore(t, dbPath), func() {
+			releaseOnce.Do(func() { close(releasing) })
+			<-unblock
+			mu.Lock()
+			held = false
+			mu.Unlock()
+		}, nil
+	}, nil, time.Hour)
+	t.Cleanup(mux.closeWorkspaces)
+
+	ws, err := mux.acquire(dbA, true)
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	mux.releaseRequest(ws)
+
+	go mux.evict(dbA, ws)
+	<-releasing
+
+	reopened := make(chan error, 1)
+	go func() {
+		ws, err := mux.acquire(dbA, true)
+		if err == nil {
+			mux.releaseRequest(ws)
+		}
+		reopened <- err
+	}()
+	select {
+	case err := <-reopened:
+		t.Fatalf("acquire returned before the workspace was released (err=%v)", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(unblock)
+	select {
+	case err := <-reopened:
+		if err != nil {
+			t.Fatalf("acquire after release failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("acquire did not resume after the workspace was released")
+	}
+}
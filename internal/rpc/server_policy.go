@@ -0,0 +1,320 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/policy"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/utils"
+)
+
+// Operation groups usable in policy.yaml allow/deny lists.
+const (
+	PolicyGroupRead  = "read"
+	PolicyGroupWrite = "write"
+	PolicyGroupAdmin = "admin"
+)
+
+// policyOperationGroups assigns every policy-checked operation to a group.
+// Operations in neither this map nor policyExemptOperations are denied
+// whenever a policy is present, so a new operation cannot slip past the
+// policy by being left out.
+var policyOperationGroups = map[string]string{
+	OpStatus:              PolicyGroupRead,
+	OpMetrics:             PolicyGroupRead,
+	OpList:                PolicyGroupRead,
+	OpCount:               PolicyGroupRead,
+	OpShow:                PolicyGroupRead,
+	OpResolveID:           PolicyGroupRead,
+	OpReady:               PolicyGroupRead,
+	OpBlocked:             PolicyGroupRead,
+	OpStale:               PolicyGroupRead,
+	OpStats:               PolicyGroupRead,
+	OpDepTree:             PolicyGroupRead,
+	OpCommentList:         PolicyGroupRead,
+	OpCompactStats:        PolicyGroupRead,
+	OpEpicStatus:          PolicyGroupRead,
+	OpGetMutations:        PolicyGroupRead,
+	OpGetMoleculeProgress: PolicyGroupRead,
+	OpGetWorkerStatus:     PolicyGroupRead,
+	OpGetConfig:           PolicyGroupRead,
+	OpMolStale:            PolicyGroupRead,
+	OpGateList:            PolicyGroupRead,
+	OpGateShow:            PolicyGroupRead,
+	OpViewList:            PolicyGroupRead,
+	OpViewRun:             PolicyGroupRead,
+
+	OpCreate:      PolicyGroupWrite,
+	OpUpdate:      PolicyGroupWrite,
+	OpClose:       PolicyGroupWrite,
+	OpClaim:       PolicyGroupWrite,
+	OpDelete:      PolicyGroupWrite,
+	OpDepAdd:      PolicyGroupWrite,
+	OpDepRemove:   PolicyGroupWrite,
+	OpLabelAdd:    PolicyGroupWrite,
+	OpLabelRemove: PolicyGroupWrite,
+	OpCommentAdd:  PolicyGroupWrite,
+	OpGateCreate:  PolicyGroupWrite,
+	OpGateClose:   PolicyGroupWrite,
+	OpGateWait:    PolicyGroupWrite,
+
+	OpCompact:  PolicyGroupAdmin,
+	OpExport:   PolicyGroupAdmin,
+	OpImport:   PolicyGroupAdmin,
+	OpShutdown: PolicyGroupAdmin,
+}
+
+// policyExemptOperations are never policy-checked: ping and health carry no
+// data, and batch sub-operations are checked individually as they are
+// dispatched.
+var policyExemptOperations = map[string]bool{
+	OpPing:   true,
+	OpHealth: true,
+	OpBatch:  true,
+}
+
+// PolicyOperationGroups returns the groups an operation belongs to for policy
+// evaluation. ok is false for operations that are not policy-checked.
+func PolicyOperationGroups(op string) (groups []string, ok bool) {
+	group, ok := policyOperationGroups[op]
+	if !ok {
+		return nil, false
+	}
+	return []string{group}, true
+}
+
+// policyCache holds the parsed policy.yaml, reloaded when the file changes.
+type policyCache struct {
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	policy  *policy.Policy
+	err     error
+}
+
+// loadPolicy returns the workspace policy, or nil when there is none.
+// An unreadable or invalid policy file is an error so that a broken policy
+// fails closed instead of silently allowing everything.
+func (s *Server) loadPolicy() (*policy.Policy, error) {
+	if s.dbPath == "" {
+		return nil, nil
+	}
+	path := filepath.Join(filepath.Dir(s.dbPath), policy.FileName)
+
+	c := &s.policyCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.policy, c.err, c.modTime, c.size = nil, nil, time.Time{}, 0
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to stat %s: %w", policy.FileName, err)
+	}
+	if info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.policy, c.err
+	}
+
+	c.policy, c.err = policy.Load(path)
+	c.modTime, c.size = info.ModTime(), info.Size()
+	return c.policy, c.err
+}
+
+// enforcePolicy checks req against policy.yaml. It returns nil when the
+// request may proceed, or the error response to send. Denials are recorded
+// in the interactions audit log.
+//
+// While a policy is present the actor is taken from req.ActorToken, not from
+// the self-reported req.Actor: every client shares the daemon auth token, so
+// only a token issued from .beads/policy.key ties a request to an actor. On
+// success req.Actor is replaced with the verified actor so handlers, audit
+// entries and claimPolicyFilter all see the authenticated identity.
+func (s *Server) enforcePolicy(req *Request) *Response {
+	if policyExemptOperations[req.Operation] {
+		return nil
+	}
+
+	p, err := s.loadPolicy()
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("policy denied: %v", err)}
+	}
+	if p == nil {
+		return nil
+	}
+
+	issueID := ""
+	var decision policy.Decision
+	if actor, err := s.authenticateActor(req); err != nil {
+		decision = policy.Decision{Rule: "actor_token", Reason: err.Error()}
+	} else if groups, ok := PolicyOperationGroups(req.Operation); ok {
+		req.Actor = actor
+		var issueTypes, labels []string
+		issueID, issueTypes, labels = s.policyTargets(req)
+		decision = p.Check(policy.Request{
+			Actor:      req.Actor,
+			Operation:  req.Operation,
+			Groups:     groups,
+			IssueTypes: issueTypes,
+			Labels:     labels,
+		})
+	} else {
+		decision = policy.Decision{Rule: "none", Reason: "operation has no policy group"}
+	}
+	if decision.Allowed {
+		return nil
+	}
+
+	entry := &audit.Entry{
+		Kind:    "policy_denied",
+		Actor:   req.Actor,
+		IssueID: issueID,
+		Reason:  decision.Reason,
+		Extra: map[string]any{
+			"operation": req.Operation,
+			"rule":      decision.Rule,
+		},
+	}
+	if _, err := audit.AppendFile(filepath.Join(filepath.Dir(s.dbPath), audit.FileName), entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to audit policy denial: %v\n", err)
+	}
+
+	return &Response{
+		Success: false,
+		Error:   fmt.Sprintf("policy denied: actor %q may not %s (rule %s: %s)", req.Actor, req.Operation, decision.Rule, decision.Reason),
+	}
+}
+
+// authenticateActor returns the actor req.ActorToken was issued to. The key
+// is read on every call so that deleting or replacing policy.key revokes
+// outstanding tokens without restarting the daemon.
+func (s *Server) authenticateActor(req *Request) (string, error) {
+	key, err := policy.LoadOrCreateKey(filepath.Dir(s.dbPath))
+	if err != nil {
+		return "", err
+	}
+	actor, err := policy.VerifyToken(key, req.ActorToken)
+	if err != nil {
+		return "", fmt.Errorf("%w (set %s to a token from 'bd policy token')", err, policy.TokenEnv)
+	}
+	return actor, nil
+}
+
+// claimPolicyFilter returns the candidate check for a from-ready claim. The
+// claimed issue is only known once a candidate is picked, so issue_types
+// restrictions are applied per candidate rather than in enforcePolicy.
+// Returns nil when there is no policy.
+func (s *Server) claimPolicyFilter(req *Request) (func(*types.Issue) bool, error) {
+	p, err := s.loadPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("policy denied: %w", err)
+	}
+	if p == nil {
+		return nil, nil
+	}
+	groups, _ := PolicyOperationGroups(OpClaim)
+	return func(issue *types.Issue) bool {
+		return p.Check(policy.Request{
+			Actor:      req.Actor,
+			Operation:  OpClaim,
+			Groups:     groups,
+			IssueTypes: []string{string(issue.IssueType)},
+		}).Allowed
+	}, nil
+}
+
+// policyTargets extracts the issue, the issue types and the labels a
+// mutating request touches. Types come from the request for creates and
+// from the stored issue otherwise; unresolvable IDs are left to the handler
+// to report.
+func (s *Server) policyTargets(req *Request) (issueID string, issueTypes, labels []string) {
+	var ids []string
+	switch req.Operation {
+	case OpCreate:
+		var args CreateArgs
+		if json.Unmarshal(req.Args, &args) == nil {
+			issueType := args.IssueType
+			if issueType == "" {
+				issueType = string(types.TypeTask)
+			}
+			issueTypes = append(issueTypes, issueType)
+			labels = append(labels, args.Labels...)
+		}
+	case OpUpdate:
+		var args UpdateArgs
+		if json.Unmarshal(req.Args, &args) == nil {
+			ids = append(ids, args.ID)
+			if args.IssueType != nil {
+				issueTypes = append(issueTypes, *args.IssueType)
+			}
+			labels = append(labels, args.AddLabels...)
+			labels = append(labels, args.RemoveLabels...)
+			labels = append(labels, args.SetLabels...)
+		}
+	case OpClose:
+		var args CloseArgs
+		if json.Unmarshal(req.Args, &args) == nil {
+			ids = append(ids, args.ID)
+		}
+	case OpClaim:
+		var args ClaimArgs
+		if json.Unmarshal(req.Args, &args) == nil {
+			if args.ID != "" {
+				ids = append(ids, args.ID)
+			} else if args.Type != "" {
+				issueTypes = append(issueTypes, args.Type)
+			}
+		}
+	case OpDelete:
+		var args DeleteArgs
+		if json.Unmarshal(req.Args, &args) == nil {
+			ids = append(ids, args.IDs...)
+		}
+	case OpDepAdd, OpDepRemove:
+		var args DepAddArgs
+		if json.Unmarshal(req.Args, &args) == nil {
+			ids = append(ids, args.FromID)
+		}
+	case OpLabelAdd, OpLabelRemove:
+		var args LabelAddArgs
+		if json.Unmarshal(req.Args, &args) == nil {
+			ids = append(ids, args.ID)
+			labels = append(labels, args.Label)
+		}
+	case OpCommentAdd:
+		var args CommentAddArgs
+		if json.Unmarshal(req.Args, &args) == nil {
+			ids = append(ids, args.ID)
+		}
+	case OpGateCreate, OpGateClose, OpGateWait:
+		issueTypes = append(issueTypes, string(types.TypeGate))
+	}
+
+	if len(ids) > 0 {
+		issueID = ids[0]
+	}
+	if s.storage == nil {
+		return issueID, issueTypes, labels
+	}
+	ctx := s.reqCtx(req)
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		resolved, err := utils.ResolvePartialID(ctx, s.storage, id)
+		if err != nil {
+			continue
+		}
+		if issue, err := s.storage.GetIssue(ctx, resolved); err == nil && issue != nil {
+			issueTypes = append(issueTypes, string(issue.IssueType))
+		}
+	}
+	return issueID, issueTypes, labels
+}
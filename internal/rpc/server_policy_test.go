@@ -0,0 +1,300 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/audit"
+	"github.com/steveyegge/beads/internal/policy"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestEnforcePolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "beads.db")
+	store := newTestStore(t, dbPath)
+	defer store.Close()
+	ctx := context.Background()
+
+	for _, issue := range []*types.Issue{
+		{ID: "bd-task", Title: "task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "bd-epic", Title: "epic", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeEpic},
+	} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	server := NewServer("/tmp/test.sock", store, tmpDir, dbPath)
+	key, err := policy.LoadOrCreateKey(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	epic := "epic"
+	request := func(actor, op string, args interface{}) *Request {
+		data, _ := json.Marshal(args)
+		return &Request{Operation: op, Actor: actor, ActorToken: policy.IssueToken(key, actor), Args: data}
+	}
+	// forged reports one actor while holding another actor's token
+	forged := func(reported, tokenActor, op string, args interface{}) *Request {
+		req := request(reported, op, args)
+		req.ActorToken = policy.IssueToken(key, tokenActor)
+		return req
+	}
+
+	// No policy file: everything is allowed
+	if resp := server.enforcePolicy(request("polecat/nux", OpDelete, DeleteArgs{IDs: []string{"bd-task"}})); resp != nil {
+		t.Fatalf("expected no enforcement without policy.yaml, got %s", resp.Error)
+	}
+
+	policyYAML := `rules:
+  - name: polecats
+    actors: ["polecat/*"]
+    allow: [read, create, update, close]
+    issue_types: [task, bug]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, policy.FileName), []byte(policyYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		req     *Request
+		allowed bool
+	}{
+		{"read", request("polecat/nux", OpList, ListArgs{}), true},
+		{"create task", request("polecat/nux", OpCreate, CreateArgs{Title: "t", IssueType: "task"}), true},
+		{"create epic", request("polecat/nux", OpCreate, CreateArgs{Title: "t", IssueType: "epic"}), false},
+		{"close task by partial id", request("polecat/nux", OpClose, CloseArgs{ID: "task"}), true},
+		{"update epic", request("polecat/nux", OpUpdate, UpdateArgs{ID: "bd-epic"}), false},
+		{"retype task to epic", request("polecat/nux", OpUpdate, UpdateArgs{ID: "bd-task", IssueType: &epic}), false},
+		{"delete", request("polecat/nux", OpDelete, DeleteArgs{IDs: []string{"bd-task"}}), false},
+		{"shutdown", request("polecat/nux", OpShutdown, nil), false},
+		{"other actor", request("mayor", OpDelete, DeleteArgs{IDs: []string{"bd-epic"}}), true},
+		{"ping is never checked", request("polecat/nux", OpPing, nil), true},
+		{"unknown operation", request("mayor", "bogus", nil), false},
+		{"reported actor ignored", forged("mayor", "polecat/nux", OpDelete, DeleteArgs{IDs: []string{"bd-epic"}}), false},
+		{"missing token", &Request{Operation: OpList, Actor: "mayor"}, false},
+		{"token from another key", &Request{Operation: OpList, Actor: "mayor", ActorToken: policy.IssueToken(make([]byte, 32), "mayor")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := server.enforcePolicy(tt.req)
+			if tt.allowed && resp != nil {
+				t.Errorf("expected allow, got %s", resp.Error)
+			}
+			if !tt.allowed && (resp == nil || !strings.Contains(resp.Error, "policy denied")) {
+				t.Errorf("expected policy denial, got %+v", resp)
+			}
+		})
+	}
+
+	// An allowed request continues as the token's actor
+	req := forged("mayor", "polecat/nux", OpList, ListArgs{})
+	if resp := server.enforcePolicy(req); resp != nil {
+		t.Fatalf("expected allow, got %s", resp.Error)
+	}
+	if req.Actor != "polecat/nux" {
+		t.Errorf("request actor = %q, want the token's actor polecat/nux", req.Actor)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, audit.FileName))
+	if err != nil {
+		t.Fatalf("expected denials in audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("got %d audit entries, want 9:\n%s", len(lines), data)
+	}
+	var entry audit.Entry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Kind != "policy_denied" || entry.Actor != "polecat/nux" || entry.Extra["rule"] != "polecats" {
+		t.Errorf("unexpected audit entry: %+v", entry)
+	}
+	if err := json.Unmarshal([]byte(lines[6]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Actor != "polecat/nux" || entry.Extra["rule"] != "polecats" {
+		t.Errorf("forged request audited as %q (rule %v), want polecat/nux (rule polecats)", entry.Actor, entry.Extra["rule"])
+	}
+
+	// Deleting the key revokes outstanding tokens
+	if err := os.Remove(filepath.Join(tmpDir, policy.KeyFileName)); err != nil {
+		t.Fatal(err)
+	}
+	if resp := server.enforcePolicy(request("mayor", OpList, ListArgs{})); resp == nil {
+		t.Error("expected token issued from the deleted key to be denied")
+	}
+	if key, err = policy.ReadKey(tmpDir); err != nil {
+		t.Fatalf("expected the daemon to create a new key: %v", err)
+	}
+	if resp := server.enforcePolicy(request("mayor", OpList, ListArgs{})); resp != nil {
+		t.Errorf("expected token from the new key to be accepted, got %s", resp.Error)
+	}
+
+	// A broken policy fails closed
+	if err := os.WriteFile(filepath.Join(tmpDir, policy.FileName), []byte("default: sometimes\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if resp := server.enforcePolicy(request("mayor", OpList, ListArgs{})); resp == nil {
+		t.Error("expected invalid policy to deny requests")
+	}
+}
+
+func TestClientActorToken(t *testing.T) {
+	t.Setenv(policy.TokenEnv, "")
+	tmpDir := t.TempDir()
+	client := &Client{socketPath: filepath.Join(tmpDir, "bd.sock"), dbPath: filepath.Join(tmpDir, "beads.db")}
+	client.SetActor("polecat/nux")
+
+	// Without policy.key (a sandbox) the client has no token, and must not create a key
+	if token := client.getActorToken(); token != "" {
+		t.Errorf("expected no token without policy.key, got %q", token)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, policy.KeyFileName)); !os.IsNotExist(err) {
+		t.Fatalf("client created policy.key: %v", err)
+	}
+
+	// Once a policy exists, a host client creates the key and mints a token for its actor
+	if err := os.WriteFile(filepath.Join(tmpDir, policy.FileName), []byte("default: allow\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	token := client.getActorToken()
+	key, err := policy.ReadKey(tmpDir)
+	if err != nil {
+		t.Fatalf("expected client to create policy.key: %v", err)
+	}
+	if actor, err := policy.VerifyToken(key, token); err != nil || actor != "polecat/nux" {
+		t.Errorf("minted token verified as %q, %v; want polecat/nux", actor, err)
+	}
+
+	// BD_ACTOR_TOKEN takes precedence
+	t.Setenv(policy.TokenEnv, policy.IssueToken(key, "mayor"))
+	if actor, _ := policy.VerifyToken(key, client.getActorToken()); actor != "mayor" {
+		t.Errorf("expected BD_ACTOR_TOKEN to be used, got token for %q", actor)
+	}
+}
+
+func TestPolicyOperationGroups(t *testing.T) {
+	if groups, ok := PolicyOperationGroups(OpDelete); !ok || groups[0] != PolicyGroupWrite {
+		t.Errorf("delete groups = %v, %v", groups, ok)
+	}
+	for _, op := range []string{OpPing, OpHealth, OpBatch, "bogus"} {
+		if _, ok := PolicyOperationGroups(op); ok {
+			t.Errorf("%s should not be policy-checked", op)
+		}
+	}
+}
+
+// TestPolicyCoversAllOperations guards against new operations bypassing
+// policy review: every Op constant needs a group or an explicit exemption.
+func TestPolicyCoversAllOperations(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "protocol.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse protocol.go: %v", err)
+	}
+	found := 0
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if !strings.HasPrefix(name.Name, "Op") || i >= len(vs.Values) {
+					continue
+				}
+				found++
+				op, ok := vs.Values[i].(*ast.BasicLit)
+				if !ok {
+					t.Errorf("%s is not a string literal", name.Name)
+					continue
+				}
+				value := strings.Trim(op.Value, `"`)
+				if _, grouped := policyOperationGroups[value]; !grouped && !policyExemptOperations[value] {
+					t.Errorf("%s (%q) has no policy group; add it to policyOperationGroups", name.Name, value)
+				}
+			}
+		}
+	}
+	if found == 0 {
+		t.Fatal("found no Op constants in protocol.go")
+	}
+}
+
+func TestClaimFromReadyRespectsPolicyIssueTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "beads.db")
+	store := newTestStore(t, dbPath)
+	defer store.Close()
+	ctx := context.Background()
+
+	for _, issue := range []*types.Issue{
+		{ID: "bd-epic", Title: "epic", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeEpic},
+		{ID: "bd-task", Title: "task", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+	} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	policyYAML := `rules:
+  - name: polecats
+    actors: ["polecat/*"]
+    issue_types: [task, bug]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, policy.FileName), []byte(policyYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer("/tmp/test.sock", store, tmpDir, dbPath)
+	key, err := policy.LoadOrCreateKey(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, _ := json.Marshal(ClaimArgs{FromReady: true, SortPolicy: string(types.SortPolicyPriority)})
+	claim := func(actor string) Response {
+		req := &Request{Operation: OpClaim, Actor: actor, ActorToken: policy.IssueToken(key, actor), Args: args}
+		if denied := server.enforcePolicy(req); denied != nil {
+			return *denied
+		}
+		return server.handleClaim(req)
+	}
+
+	resp := claim("polecat/nux")
+	if !resp.Success {
+		t.Fatalf("claim failed: %s", resp.Error)
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(resp.Data, &issue); err != nil {
+		t.Fatal(err)
+	}
+	if issue.ID != "bd-task" {
+		t.Errorf("polecat claimed %s, want bd-task (epics are outside its issue_types)", issue.ID)
+	}
+
+	if resp := claim("polecat/nux"); resp.Success {
+		t.Errorf("expected no claimable work for polecat, got %s", resp.Data)
+	}
+
+	resp = claim("mayor")
+	if !resp.Success {
+		t.Fatalf("mayor claim failed: %s", resp.Error)
+	}
+	if err := json.Unmarshal(resp.Data, &issue); err != nil {
+		t.Fatal(err)
+	}
+	if issue.ID != "bd-epic" {
+		t.Errorf("mayor claimed %s, want bd-epic", issue.ID)
+	}
+}
@@ -185,6 +185,12 @@ func (s *Server) handleRequest(req *Request) Response {
 		}
 	}
 
+	// Enforce actor policies from .beads/policy.yaml
+	if denied := s.enforcePolicy(req); denied != nil {
+		s.metrics.RecordError(req.Operation)
+		return *denied
+	}
+
 	// Check for stale JSONL and auto-import if needed
 	// Skip for write operations that will trigger export anyway
 	// Skip for import operation itself to avoid recursion
@@ -227,6 +233,8 @@ func (s *Server) handleRequest(req *Request) Response {
 		resp = s.handleResolveID(req)
 	case OpReady:
 		resp = s.handleReady(req)
+	case OpClaim:
+		resp = s.handleClaim(req)
 	case OpBlocked:
 		resp = s.handleBlocked(req)
 	case OpStale:
@@ -281,6 +289,11 @@ func (s *Server) handleRequest(req *Request) Response {
 		resp = s.handleGateClose(req)
 	case OpGateWait:
 		resp = s.handleGateWait(req)
+	// Saved view operations
+	case OpViewList:
+		resp = s.handleViewList(req)
+	case OpViewRun:
+		resp = s.handleViewRun(req)
 	default:
 		s.metrics.RecordError(req.Operation)
 		return Response{
@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/views"
+)
+
+// loadViews reads the saved views from the workspace's config.yaml. The file
+// is read on every request so views saved while the daemon runs are seen.
+func (s *Server) loadViews() (map[string]config.ViewConfig, error) {
+	return config.ReadViews(filepath.Join(filepath.Dir(s.dbPath), "config.yaml"))
+}
+
+// handleViewList lists the saved views
+func (s *Server) handleViewList(_ *Request) Response {
+	all, err := s.loadViews()
+	if err != nil {
+		return Response{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	data, _ := json.Marshal(ViewListResponse{Views: views.List(all)})
+	return Response{
+		Success: true,
+		Data:    data,
+	}
+}
+
+// handleViewRun runs a saved (or ad-hoc) view
+func (s *Server) handleViewRun(req *Request) Response {
+	var args ViewRunArgs
+	if err := json.Unmarshal(req.Args, &args); err != nil {
+		return Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid view_run args: %v", err),
+		}
+	}
+
+	store := s.storage
+	if store == nil {
+		return Response{
+			Success: false,
+			Error:   "storage not available",
+		}
+	}
+
+	var view *views.View
+	if args.View != nil {
+		view = &views.View{Name: args.Name, ViewConfig: *args.View}
+		if view.Name == "" {
+			view.Name = "adhoc"
+		}
+	} else {
+		all, err := s.loadViews()
+		if err != nil {
+			return Response{
+				Success: false,
+				Error:   err.Error(),
+			}
+		}
+		if view, err = views.Find(all, args.Name); err != nil {
+			return Response{
+				Success: false,
+				Error:   err.Error(),
+			}
+		}
+	}
+	if err := view.Validate(); err != nil {
+		return Response{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	ctx := s.reqCtx(req)
+	issues, err := views.Run(ctx, store, view, time.Now(), s.reqActor(req))
+	if err != nil {
+		return Response{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	data, _ := json.Marshal(ViewRunResponse{View: view, Issues: issues})
+	return Response{
+		Success: true,
+		Data:    data,
+	}
+}
@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestViewRun(t *testing.T) {
+	server, client, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, issue := range []*types.Issue{
+		{ID: "bd-v1", Title: "Backend bug", Status: types.StatusOpen, IssueType: types.TypeBug, Priority: 2},
+		{ID: "bd-v2", Title: "Urgent backend bug", Status: types.StatusOpen, IssueType: types.TypeBug, Priority: 0},
+		{ID: "bd-v3", Title: "Frontend bug", Status: types.StatusOpen, IssueType: types.TypeBug, Priority: 1},
+		{ID: "bd-v4", Title: "Backend task", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+	} {
+		issue.CreatedAt = time.Now()
+		issue.UpdatedAt = time.Now()
+		if err := server.storage.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue(%s): %v", issue.ID, err)
+		}
+	}
+	for _, id := range []string{"bd-v1", "bd-v2", "bd-v4"} {
+		if err := server.storage.AddLabel(ctx, id, "backend", "test"); err != nil {
+			t.Fatalf("AddLabel(%s): %v", id, err)
+		}
+	}
+
+	// The daemon reads config.yaml on each request, so views saved after
+	// it started are found
+	configPath := filepath.Join(filepath.Dir(server.dbPath), "config.yaml")
+	view := &config.ViewConfig{
+		Filter:  config.ViewFilterConfig{Type: "bug", Labels: []string{"backend"}, CreatedAfter: "-1d"},
+		Sort:    "priority",
+		Columns: []string{"id", "title"},
+	}
+	if err := config.SetViewInYAML(configPath, "backend-bugs", view); err != nil {
+		t.Fatalf("SetViewInYAML: %v", err)
+	}
+
+	list, err := client.ViewList()
+	if err != nil {
+		t.Fatalf("ViewList: %v", err)
+	}
+	if len(list.Views) != 1 || list.Views[0].Name != "backend-bugs" {
+		t.Fatalf("ViewList = %+v, want backend-bugs", list.Views)
+	}
+
+	result, err := client.ViewRun(&ViewRunArgs{Name: "backend-bugs"})
+	if err != nil {
+		t.Fatalf("ViewRun: %v", err)
+	}
+	var ids []string
+	for _, issue := range result.Issues {
+		ids = append(ids, issue.ID)
+	}
+	if got := strings.Join(ids, ","); got != "bd-v2,bd-v1" {
+		t.Errorf("ViewRun issues = %s, want bd-v2,bd-v1", got)
+	}
+	if len(result.Issues) > 0 && len(result.Issues[0].Labels) != 1 {
+		t.Errorf("ViewRun labels = %v, want [backend]", result.Issues[0].Labels)
+	}
+	if len(result.View.Columns) != 2 {
+		t.Errorf("ViewRun view columns = %v, want [id title]", result.View.Columns)
+	}
+
+	// Relative dates are resolved when the view runs
+	view.Filter.CreatedAfter = "+1d"
+	result, err = client.ViewRun(&ViewRunArgs{View: view})
+	if err != nil {
+		t.Fatalf("ViewRun ad hoc: %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Errorf("ViewRun with future created-after returned %d issues", len(result.Issues))
+	}
+
+	if _, err := client.ViewRun(&ViewRunArgs{Name: "missing"}); err == nil || !strings.Contains(err.Error(), "no view named") {
+		t.Errorf("ViewRun(missing) error = %v, want no view named", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte("views:\n  bad:\n    sort: nonsense\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ViewRun(&ViewRunArgs{Name: "bad"}); err == nil || !strings.Contains(err.Error(), "unknown field") {
+		t.Errorf("ViewRun(bad) error = %v, want invalid sort", err)
+	}
+}
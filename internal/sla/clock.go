@@ -0,0 +1,360 @@
+package sla
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/analytics"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Target is one of an issue's SLA clocks.
+type Target string
+
+const (
+	TargetRespond Target = "respond" // Until someone acknowledges the issue
+	TargetResolve Target = "resolve" // Until the issue is closed
+)
+
+// State is where a clock stands.
+type State string
+
+const (
+	StateMet      State = "met"      // Stopped within the target
+	StateOK       State = "ok"       // Running, less than the at-risk fraction used
+	StateAtRisk   State = "at_risk"  // Running, at-risk fraction used
+	StateBreached State = "breached" // Target passed, stopped or not
+)
+
+// severity orders states for Status.Worst.
+var severity = map[State]int{StateMet: 0, StateOK: 1, StateAtRisk: 2, StateBreached: 3}
+
+// BreachLabel is the label added to an issue whose target was breached. It
+// also records that the breach was handled, so actions fire once per issue
+// and target.
+func BreachLabel(t Target) string {
+	return "sla:" + string(t) + "-breached"
+}
+
+// EventTypes are the event types to load for Evaluate: status changes for
+// analytics.BuildHistories and dependency changes for Pauses.
+var EventTypes = append(append([]types.EventType(nil), analytics.HistoryEventTypes...),
+	types.EventDependencyAdded, types.EventDependencyRemoved)
+
+// Pause is a span of time during which an issue's clocks stop.
+type Pause struct {
+	From   time.Time  `json:"from"`
+	Until  *time.Time `json:"until,omitempty"` // nil while still paused
+	Reason string     `json:"reason"`          // "deferred" or the external ref blocking the issue
+}
+
+// Pauses reconstructs when an issue was deferred or blocked on an external
+// dependency. events are the issue's dependency events (in any order);
+// blocking lists its external blocks dependencies and resolvedAt maps the
+// refs among them that have been resolved to when they were.
+//
+// A span still open on an external dependency that has since been resolved
+// ends when it was resolved.
+func Pauses(h *analytics.History, events []*types.Event, blocking []*types.Dependency, resolvedAt map[string]time.Time) []Pause {
+	var pauses []Pause
+	deferred := -1
+	for _, tr := range h.Transitions {
+		switch {
+		case tr.Status == types.StatusDeferred && deferred < 0:
+			deferred = len(pauses)
+			pauses = append(pauses, Pause{From: tr.At, Reason: string(types.StatusDeferred)})
+		case tr.Status != types.StatusDeferred && deferred >= 0:
+			at := tr.At
+			pauses[deferred].Until = &at
+			deferred = -1
+		}
+	}
+
+	sorted := append([]*types.Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].ID < sorted[j].ID
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+	var external []Pause
+	open := make(map[string]int) // External ref -> index of its open span
+	for _, e := range sorted {
+		if ref := externalBlockAdded(e); ref != "" {
+			if _, ok := open[ref]; !ok {
+				open[ref] = len(external)
+				external = append(external, Pause{From: e.CreatedAt, Reason: ref})
+			}
+		} else if ref := externalRemoved(e); ref != "" {
+			if i, ok := open[ref]; ok {
+				at := e.CreatedAt
+				external[i].Until = &at
+				delete(open, ref)
+			}
+		}
+	}
+
+	linked := make(map[string]bool)
+	for _, dep := range blocking {
+		linked[dep.DependsOnID] = true
+		if _, ok := open[dep.DependsOnID]; !ok {
+			// Linked without an event, e.g. by import
+			open[dep.DependsOnID] = len(external)
+			external = append(external, Pause{From: dep.CreatedAt, Reason: dep.DependsOnID})
+		}
+	}
+	for _, p := range external {
+		if p.Until == nil {
+			if !linked[p.Reason] {
+				continue // Unlinked without an event
+			}
+			if at, ok := resolvedAt[p.Reason]; ok {
+				if at.Before(p.From) {
+					at = p.From
+				}
+				p.Until = &at
+			}
+		}
+		pauses = append(pauses, p)
+	}
+	return pauses
+}
+
+// externalBlockAdded returns the ref from "Added dependency: <id> blocks
+// external:<project>:<capability>", or "".
+func externalBlockAdded(e *types.Event) string {
+	if e.EventType != types.EventDependencyAdded || e.Comment == nil {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(*e.Comment, "Added dependency:"))
+	if len(fields) != 3 || fields[1] != string(types.DepBlocks) || !strings.HasPrefix(fields[2], "external:") {
+		return ""
+	}
+	return fields[2]
+}
+
+// externalRemoved returns the ref from "Removed dependency on
+// external:<project>:<capability>", or "".
+func externalRemoved(e *types.Event) string {
+	if e.EventType != types.EventDependencyRemoved || e.Comment == nil {
+		return ""
+	}
+	ref := strings.TrimSpace(strings.TrimPrefix(*e.Comment, "Removed dependency on"))
+	if !strings.HasPrefix(ref, "external:") {
+		return ""
+	}
+	return ref
+}
+
+// Clock is the state of one SLA target for an issue.
+type Clock struct {
+	Target    Target        `json:"target"`
+	Limit     time.Duration `json:"limit"`
+	Elapsed   time.Duration `json:"elapsed"` // Excluding pauses
+	State     State         `json:"state"`
+	Deadline  *time.Time    `json:"deadline,omitempty"`   // When the target is (or was) breached; nil while paused with time left
+	StoppedAt *time.Time    `json:"stopped_at,omitempty"` // When the issue was acknowledged or resolved
+}
+
+// Status is the SLA standing of one issue.
+type Status struct {
+	IssueID  string          `json:"issue_id"`
+	Title    string          `json:"title"`
+	Type     types.IssueType `json:"issue_type"`
+	Priority int             `json:"priority"`
+	Assignee string          `json:"assignee,omitempty"`
+	Policy   string          `json:"policy"`
+	Paused   *Pause          `json:"paused,omitempty"` // The pause in effect now, if any
+	Clocks   []*Clock        `json:"clocks"`
+}
+
+// Worst returns the most severe state across the issue's clocks.
+func (s *Status) Worst() State {
+	worst := StateMet
+	for _, c := range s.Clocks {
+		if severity[c.State] > severity[worst] {
+			worst = c.State
+		}
+	}
+	return worst
+}
+
+// Clock returns the issue's clock for target, or nil if its policy has none.
+func (s *Status) Clock(target Target) *Clock {
+	for _, c := range s.Clocks {
+		if c.Target == target {
+			return c
+		}
+	}
+	return nil
+}
+
+// Evaluate computes the clocks of policy for the issue behind h. comments
+// are the issue's comments, which count as acknowledging it; atRisk is the
+// fraction of a target after which a running clock is at risk.
+func Evaluate(policy Policy, h *analytics.History, pauses []Pause, comments []*types.Comment, atRisk float64, now time.Time) *Status {
+	issue := h.Issue
+	s := &Status{
+		IssueID:  issue.ID,
+		Title:    issue.Title,
+		Type:     issue.IssueType,
+		Priority: issue.Priority,
+		Assignee: issue.Assignee,
+		Policy:   policy.String(),
+	}
+	for i := range pauses {
+		p := pauses[i]
+		if !p.From.After(now) && (p.Until == nil || p.Until.After(now)) {
+			s.Paused = &p
+			break
+		}
+	}
+
+	if policy.Respond > 0 {
+		spans := mergePauses(pauses)
+		s.Clocks = append(s.Clocks, evaluateClock(TargetRespond, policy.Respond, issue.CreatedAt, acknowledgedAt(h, comments), spans, atRisk, now))
+	}
+	if policy.Resolve > 0 {
+		// A reopened issue's clock doesn't run while it was closed
+		var closed []Pause
+		var resolved *time.Time
+		for i, tr := range h.Transitions {
+			if tr.Status != types.StatusClosed {
+				continue
+			}
+			at := tr.At
+			if i+1 < len(h.Transitions) {
+				until := h.Transitions[i+1].At
+				closed = append(closed, Pause{From: at, Until: &until})
+			} else {
+				resolved = &at
+			}
+		}
+		spans := mergePauses(append(append([]Pause(nil), pauses...), closed...))
+		s.Clocks = append(s.Clocks, evaluateClock(TargetResolve, policy.Resolve, issue.CreatedAt, resolved, spans, atRisk, now))
+	}
+	return s
+}
+
+// acknowledgedAt is when someone first acted on the issue: moved it out of
+// open (other than deferring or blocking it) or commented on it.
+func acknowledgedAt(h *analytics.History, comments []*types.Comment) *time.Time {
+	var ack *time.Time
+	for _, tr := range h.Transitions {
+		if tr.Status != types.StatusOpen && tr.Status != types.StatusDeferred && tr.Status != types.StatusBlocked {
+			at := tr.At
+			ack = &at
+			break
+		}
+	}
+	for _, c := range comments {
+		if ack == nil || c.CreatedAt.Before(*ack) {
+			at := c.CreatedAt
+			ack = &at
+		}
+	}
+	return ack
+}
+
+func evaluateClock(target Target, limit time.Duration, start time.Time, stopped *time.Time, spans []span, atRisk float64, now time.Time) *Clock {
+	c := &Clock{Target: target, Limit: limit, StoppedAt: stopped}
+	end := now
+	if stopped != nil {
+		end = *stopped
+	}
+	c.Elapsed = activeTime(start, end, spans)
+	c.Deadline = deadline(start, limit, spans)
+
+	switch {
+	case stopped != nil && c.Deadline != nil && stopped.After(*c.Deadline):
+		c.State = StateBreached
+	case stopped != nil:
+		c.State = StateMet
+	case c.Deadline != nil && !now.Before(*c.Deadline):
+		c.State = StateBreached
+	case float64(c.Elapsed) >= atRisk*float64(limit):
+		c.State = StateAtRisk
+	default:
+		c.State = StateOK
+	}
+	return c
+}
+
+// span is a pause with a concrete end; open pauses end at forever.
+type span struct{ from, until time.Time }
+
+var forever = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// mergePauses sorts pauses and merges overlapping ones.
+func mergePauses(pauses []Pause) []span {
+	spans := make([]span, 0, len(pauses))
+	for _, p := range pauses {
+		until := forever
+		if p.Until != nil {
+			until = *p.Until
+		}
+		if until.After(p.From) {
+			spans = append(spans, span{p.From, until})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].from.Before(spans[j].from) })
+
+	var merged []span
+	for _, s := range spans {
+		if n := len(merged); n > 0 && !s.from.After(merged[n-1].until) {
+			if s.until.After(merged[n-1].until) {
+				merged[n-1].until = s.until
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// activeTime is the time from start to end outside the paused spans.
+func activeTime(start, end time.Time, spans []span) time.Duration {
+	if !end.After(start) {
+		return 0
+	}
+	d := end.Sub(start)
+	for _, s := range spans {
+		from, until := s.from, s.until
+		if from.Before(start) {
+			from = start
+		}
+		if until.After(end) {
+			until = end
+		}
+		if until.After(from) {
+			d -= until.Sub(from)
+		}
+	}
+	return d
+}
+
+// deadline is when limit of active time since start runs out, or nil if it
+// doesn't before a pause that hasn't ended.
+func deadline(start time.Time, limit time.Duration, spans []span) *time.Time {
+	cursor, left := start, limit
+	for _, s := range spans {
+		if !s.until.After(cursor) {
+			continue
+		}
+		if s.from.After(cursor) {
+			gap := s.from.Sub(cursor)
+			if gap >= left {
+				at := cursor.Add(left)
+				return &at
+			}
+			left -= gap
+		}
+		if s.until.Equal(forever) {
+			return nil
+		}
+		cursor = s.until
+	}
+	at := cursor.Add(left)
+	return &at
+}
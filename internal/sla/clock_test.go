@@ -0,0 +1,160 @@
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/analytics"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var t0 = time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+
+func at(hours float64) time.Time { return t0.Add(time.Duration(hours * float64(time.Hour))) }
+
+func strPtr(s string) *string { return &s }
+
+// history builds an issue's timeline from alternating hours and statuses.
+func history(status types.Status, steps ...interface{}) *analytics.History {
+	h := &analytics.History{Issue: &types.Issue{ID: "sla-1", Title: "Outage", IssueType: types.TypeBug, Status: status, CreatedAt: t0}}
+	h.Transitions = append(h.Transitions, analytics.Transition{At: t0, Status: types.StatusOpen})
+	for i := 0; i+1 < len(steps); i += 2 {
+		h.Transitions = append(h.Transitions, analytics.Transition{At: at(steps[i].(float64)), Status: steps[i+1].(types.Status)})
+	}
+	return h
+}
+
+var p0 = Policy{Type: types.TypeBug, Priority: 0, Respond: time.Hour, Resolve: 24 * time.Hour}
+
+func TestEvaluateRunningClocks(t *testing.T) {
+	h := history(types.StatusOpen)
+
+	s := Evaluate(p0, h, nil, nil, 0.75, at(0.8))
+	respond, resolve := s.Clock(TargetRespond), s.Clock(TargetResolve)
+	if respond.State != StateAtRisk || !respond.Deadline.Equal(at(1)) {
+		t.Errorf("respond = %+v, want at risk until hour 1", respond)
+	}
+	if resolve.State != StateOK || !resolve.Deadline.Equal(at(24)) {
+		t.Errorf("resolve = %+v, want ok until hour 24", resolve)
+	}
+	if s.Worst() != StateAtRisk || s.Policy != "bug P0" {
+		t.Errorf("worst = %s, policy = %q", s.Worst(), s.Policy)
+	}
+
+	s = Evaluate(p0, h, nil, nil, 0.75, at(2))
+	if c := s.Clock(TargetRespond); c.State != StateBreached || c.Elapsed != 2*time.Hour {
+		t.Errorf("respond = %+v, want breached after 2h", c)
+	}
+}
+
+func TestEvaluateAcknowledged(t *testing.T) {
+	// A comment within the hour meets the response target
+	comments := []*types.Comment{{CreatedAt: at(0.5)}}
+	s := Evaluate(p0, history(types.StatusOpen), nil, comments, 0.75, at(3))
+	if c := s.Clock(TargetRespond); c.State != StateMet || !c.StoppedAt.Equal(at(0.5)) {
+		t.Errorf("respond = %+v, want met at 30m", c)
+	}
+
+	// Starting work after two hours is a late acknowledgement
+	h := history(types.StatusInProgress, 2.0, types.StatusInProgress)
+	s = Evaluate(p0, h, nil, nil, 0.75, at(3))
+	if c := s.Clock(TargetRespond); c.State != StateBreached || !c.StoppedAt.Equal(at(2)) {
+		t.Errorf("respond = %+v, want breached, stopped at hour 2", c)
+	}
+
+	// Deferring isn't an acknowledgement, but the clock doesn't run meanwhile
+	h = history(types.StatusInProgress, 0.25, types.StatusDeferred, 1.5, types.StatusOpen, 2.0, types.StatusInProgress)
+	s = Evaluate(p0, h, Pauses(h, nil, nil, nil), nil, 0.75, at(3))
+	if c := s.Clock(TargetRespond); c.State != StateMet || c.Elapsed != 45*time.Minute || !c.StoppedAt.Equal(at(2)) {
+		t.Errorf("respond = %+v, want met after 45m, stopped at hour 2", c)
+	}
+}
+
+func TestEvaluatePauses(t *testing.T) {
+	policy := Policy{Priority: AnyPriority, Resolve: 10 * time.Hour}
+
+	// Deferred for hours 2-6: the deadline moves out by 4h
+	h := history(types.StatusOpen, 2.0, types.StatusDeferred, 6.0, types.StatusOpen)
+	pauses := Pauses(h, nil, nil, nil)
+	if len(pauses) != 1 || pauses[0].Reason != "deferred" || !pauses[0].Until.Equal(at(6)) {
+		t.Fatalf("pauses = %+v, want deferred from hour 2 to 6", pauses)
+	}
+	s := Evaluate(policy, h, pauses, nil, 0.75, at(4))
+	if c := s.Clock(TargetResolve); c.Elapsed != 2*time.Hour || !c.Deadline.Equal(at(14)) || s.Paused == nil {
+		t.Errorf("resolve = %+v (paused %v), want 2h elapsed, due hour 14, paused", c, s.Paused)
+	}
+
+	// Still deferred: no deadline while paused
+	h = history(types.StatusDeferred, 2.0, types.StatusDeferred)
+	s = Evaluate(policy, h, Pauses(h, nil, nil, nil), nil, 0.75, at(48))
+	if c := s.Clock(TargetResolve); c.State != StateOK || c.Deadline != nil || c.Elapsed != 2*time.Hour {
+		t.Errorf("resolve = %+v, want ok with the clock stopped at 2h", c)
+	}
+}
+
+func TestPausesExternalDependencies(t *testing.T) {
+	h := history(types.StatusOpen)
+	events := []*types.Event{
+		{ID: 2, IssueID: "sla-1", EventType: types.EventDependencyRemoved, CreatedAt: at(3), Comment: strPtr("Removed dependency on external:infra:dns")},
+		{ID: 1, IssueID: "sla-1", EventType: types.EventDependencyAdded, CreatedAt: at(1), Comment: strPtr("Added dependency: sla-1 blocks external:infra:dns")},
+		// Resolved since: pauses until it was resolved
+		{ID: 3, IssueID: "sla-1", EventType: types.EventDependencyAdded, CreatedAt: at(4), Comment: strPtr("Added dependency: sla-1 blocks external:infra:cdn")},
+		// Unlinked without an event: no longer pauses
+		{ID: 5, IssueID: "sla-1", EventType: types.EventDependencyAdded, CreatedAt: at(2), Comment: strPtr("Added dependency: sla-1 blocks external:infra:old")},
+		// Local dependencies don't pause
+		{ID: 4, IssueID: "sla-1", EventType: types.EventDependencyAdded, CreatedAt: at(4), Comment: strPtr("Added dependency: sla-1 blocks sla-2")},
+	}
+	blocking := []*types.Dependency{
+		{IssueID: "sla-1", DependsOnID: "external:infra:cdn", Type: types.DepBlocks, CreatedAt: at(4)},
+		{IssueID: "sla-1", DependsOnID: "external:vendor:fix", Type: types.DepBlocks, CreatedAt: at(5)},
+	}
+	resolvedAt := map[string]time.Time{"external:infra:cdn": at(6)}
+
+	pauses := Pauses(h, events, blocking, resolvedAt)
+	if len(pauses) != 3 {
+		t.Fatalf("pauses = %+v, want dns (hours 1-3), cdn (hours 4-6) and vendor (from hour 5)", pauses)
+	}
+	if pauses[0].Reason != "external:infra:dns" || !pauses[0].Until.Equal(at(3)) {
+		t.Errorf("pause 0 = %+v", pauses[0])
+	}
+	if pauses[1].Reason != "external:infra:cdn" || pauses[1].Until == nil || !pauses[1].Until.Equal(at(6)) {
+		t.Errorf("pause 1 = %+v", pauses[1])
+	}
+	if pauses[2].Reason != "external:vendor:fix" || pauses[2].Until != nil {
+		t.Errorf("pause 2 = %+v", pauses[2])
+	}
+
+	s := Evaluate(p0, h, pauses, nil, 0.75, at(8))
+	if s.Paused == nil || s.Paused.Reason != "external:vendor:fix" {
+		t.Errorf("paused = %+v, want the vendor dependency", s.Paused)
+	}
+	// Running for hours 0-1 and 3-4
+	if c := s.Clock(TargetResolve); c.Elapsed != 2*time.Hour || c.Deadline != nil {
+		t.Errorf("resolve = %+v, want 2h elapsed and no deadline", c)
+	}
+
+	// Once the vendor fix ships too, the blocked time still doesn't count
+	resolvedAt["external:vendor:fix"] = at(7)
+	s = Evaluate(p0, h, Pauses(h, events, blocking, resolvedAt), nil, 0.75, at(8))
+	if c := s.Clock(TargetResolve); s.Paused != nil || c.Elapsed != 3*time.Hour {
+		t.Errorf("paused = %+v, resolve = %+v, want running with 3h elapsed", s.Paused, c)
+	}
+}
+
+func TestEvaluateReopened(t *testing.T) {
+	policy := Policy{Priority: AnyPriority, Resolve: 4 * time.Hour}
+	h := history(types.StatusOpen, 2.0, types.StatusClosed, 12.0, types.StatusOpen)
+
+	// Open for hours 0-2 and 12-13
+	s := Evaluate(policy, h, nil, nil, 0.75, at(13))
+	if c := s.Clock(TargetResolve); c.State != StateAtRisk || c.Elapsed != 3*time.Hour || !c.Deadline.Equal(at(14)) {
+		t.Errorf("resolve = %+v, want at risk with 3h elapsed, due hour 14", c)
+	}
+
+	// Closed again in time
+	h = history(types.StatusClosed, 2.0, types.StatusClosed, 12.0, types.StatusOpen, 13.5, types.StatusClosed)
+	s = Evaluate(policy, h, nil, nil, 0.75, at(20))
+	if c := s.Clock(TargetResolve); c.State != StateMet || !c.StoppedAt.Equal(at(13.5)) {
+		t.Errorf("resolve = %+v, want met at hour 13.5", c)
+	}
+}
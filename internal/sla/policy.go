@@ -0,0 +1,139 @@
+// Package sla evaluates response and resolution targets (service level
+// agreements) for issues.
+//
+// Policies come from sla.policies in config.yaml and are keyed by issue type
+// and priority. Each open issue is matched against the most specific policy
+// and gets up to two clocks: respond (until someone acknowledges it) and
+// resolve (until it is closed). Clocks are rebuilt from the issue's history
+// and pause while the issue is deferred or blocked on an external
+// dependency.
+package sla
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// AnyPriority is the Policy.Priority that matches every priority.
+const AnyPriority = -1
+
+// Policy is a response and resolution target for matching issues.
+type Policy struct {
+	Type     types.IssueType // Empty matches any type
+	Priority int             // AnyPriority matches any priority
+	Respond  time.Duration   // 0 means no response target
+	Resolve  time.Duration   // 0 means no resolution target
+}
+
+// String names the issues the policy applies to, e.g. "bug P0" or "P1".
+func (p Policy) String() string {
+	var parts []string
+	if p.Type != "" {
+		parts = append(parts, string(p.Type))
+	}
+	if p.Priority != AnyPriority {
+		parts = append(parts, fmt.Sprintf("P%d", p.Priority))
+	}
+	if len(parts) == 0 {
+		return "any"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Matches reports whether the policy applies to issue. A policy without a
+// type covers the core work types only, not gates, agents, event beads and
+// other infrastructure types.
+func (p Policy) Matches(issue *types.Issue) bool {
+	if p.Type == "" && !issue.IssueType.IsValid() {
+		return false
+	}
+	return (p.Type == "" || p.Type == issue.IssueType) &&
+		(p.Priority == AnyPriority || p.Priority == issue.Priority)
+}
+
+// specificity ranks policies so that type and priority beats either alone,
+// and type beats priority.
+func (p Policy) specificity() int {
+	n := 0
+	if p.Type != "" {
+		n += 2
+	}
+	if p.Priority != AnyPriority {
+		n++
+	}
+	return n
+}
+
+// ParsePolicies validates the sla.policies entries.
+func ParsePolicies(entries []config.SLAPolicyConfig) ([]Policy, error) {
+	policies := make([]Policy, 0, len(entries))
+	for i, e := range entries {
+		p := Policy{Type: types.IssueType(strings.ToLower(strings.TrimSpace(e.Type))).Normalize(), Priority: AnyPriority}
+		if s := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(e.Priority)), "P"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 || n > 4 {
+				return nil, fmt.Errorf("sla.policies[%d]: invalid priority %q (expected 0-4 or P0-P4)", i, e.Priority)
+			}
+			p.Priority = n
+		}
+		var err error
+		if p.Respond, err = parseTarget(e.Respond); err != nil {
+			return nil, fmt.Errorf("sla.policies[%d]: invalid respond: %w", i, err)
+		}
+		if p.Resolve, err = parseTarget(e.Resolve); err != nil {
+			return nil, fmt.Errorf("sla.policies[%d]: invalid resolve: %w", i, err)
+		}
+		if p.Respond == 0 && p.Resolve == 0 {
+			return nil, fmt.Errorf("sla.policies[%d] (%s): needs respond, resolve or both", i, p)
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+var dayDuration = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// parseTarget parses a Go duration ("90m", "1h30m") or a whole number of
+// days or weeks ("3d", "2w"). Empty means no target.
+func parseTarget(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+	var d time.Duration
+	if m := dayDuration.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		d = time.Duration(n) * 24 * time.Hour
+		if m[2] == "w" {
+			d *= 7
+		}
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("%q is not a duration (use e.g. 30m, 4h, 3d or 2w)", s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q must be positive", s)
+	}
+	return d, nil
+}
+
+// Select returns the most specific policy that applies to issue. Among
+// equally specific policies the first listed wins.
+func Select(policies []Policy, issue *types.Issue) (Policy, bool) {
+	var best Policy
+	found := false
+	for _, p := range policies {
+		if p.Matches(issue) && (!found || p.specificity() > best.specificity()) {
+			best, found = p, true
+		}
+	}
+	return best, found
+}
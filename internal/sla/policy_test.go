@@ -0,0 +1,78 @@
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies([]config.SLAPolicyConfig{
+		{Type: "Bug", Priority: "P0", Respond: "1h", Resolve: "24h"},
+		{Priority: "1", Resolve: "3d"},
+		{Type: "task", Resolve: "2w"},
+	})
+	if err != nil {
+		t.Fatalf("ParsePolicies failed: %v", err)
+	}
+	want := []Policy{
+		{Type: types.TypeBug, Priority: 0, Respond: time.Hour, Resolve: 24 * time.Hour},
+		{Priority: 1, Resolve: 72 * time.Hour},
+		{Type: types.TypeTask, Priority: AnyPriority, Resolve: 14 * 24 * time.Hour},
+	}
+	for i := range want {
+		if policies[i] != want[i] {
+			t.Errorf("policy %d = %+v, want %+v", i, policies[i], want[i])
+		}
+	}
+	if got := policies[0].String(); got != "bug P0" {
+		t.Errorf("String() = %q, want %q", got, "bug P0")
+	}
+
+	for _, bad := range []config.SLAPolicyConfig{
+		{Priority: "P5", Resolve: "1h"},
+		{Priority: "high", Resolve: "1h"},
+		{Type: "bug", Respond: "soon"},
+		{Type: "bug", Resolve: "-1h"},
+		{Type: "bug"}, // No targets
+	} {
+		if _, err := ParsePolicies([]config.SLAPolicyConfig{bad}); err == nil {
+			t.Errorf("ParsePolicies(%+v) should fail", bad)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	policies := []Policy{
+		{Priority: AnyPriority, Resolve: time.Hour},                          // any
+		{Priority: 0, Resolve: 2 * time.Hour},                                // P0
+		{Type: types.TypeBug, Priority: AnyPriority, Resolve: 3 * time.Hour}, // bug
+		{Type: types.TypeBug, Priority: 0, Resolve: 4 * time.Hour},           // bug P0
+	}
+	for _, tt := range []struct {
+		issue *types.Issue
+		want  time.Duration
+	}{
+		{&types.Issue{IssueType: types.TypeBug, Priority: 0}, 4 * time.Hour},
+		{&types.Issue{IssueType: types.TypeBug, Priority: 2}, 3 * time.Hour},
+		{&types.Issue{IssueType: types.TypeTask, Priority: 0}, 2 * time.Hour},
+		{&types.Issue{IssueType: types.TypeTask, Priority: 3}, time.Hour},
+	} {
+		p, ok := Select(policies, tt.issue)
+		if !ok || p.Resolve != tt.want {
+			t.Errorf("Select(%s P%d) = %+v, want the %s policy", tt.issue.IssueType, tt.issue.Priority, p, tt.want)
+		}
+	}
+	if _, ok := Select(policies[1:2], &types.Issue{IssueType: types.TypeTask, Priority: 1}); ok {
+		t.Error("Select should find nothing when no policy matches")
+	}
+	if _, ok := Select(policies, &types.Issue{IssueType: types.TypeGate, Priority: 0}); ok {
+		t.Error("policies without a type should not apply to gates")
+	}
+	gatePolicy := Policy{Type: types.TypeGate, Priority: AnyPriority, Resolve: time.Hour}
+	if _, ok := Select([]Policy{gatePolicy}, &types.Issue{IssueType: types.TypeGate}); !ok {
+		t.Error("a policy naming the gate type should apply to gates")
+	}
+}
@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// ErrNoClaimableWork is returned by ClaimReady when no ready issue matching
+// the filter could be claimed.
+var ErrNoClaimableWork = errors.New("no claimable work")
+
+// claimCandidateLimit bounds how many ready issues ClaimReady tries before
+// giving up. Each lost race moves on to the next candidate.
+const claimCandidateLimit = 25
+
+// ClaimError reports that an issue could not be claimed because someone
+// else got there first (or it is no longer claimable).
+type ClaimError struct {
+	IssueID string
+	Reason  string
+}
+
+func (e *ClaimError) Error() string {
+	return fmt.Sprintf("cannot claim %s: %s", e.IssueID, e.Reason)
+}
+
+// ClaimIssue atomically assigns issueID to holder and marks it in_progress,
+// guarded by a lease that expires after ttl unless renewed by activity.
+//
+// The status/assignee check, lease compare-and-set and update all run inside
+// a single RunInTransaction call, so two agents racing for the same issue
+// cannot both succeed. Re-claiming an issue already held by holder refreshes
+// the lease. Returns a *ClaimError if the issue is not claimable.
+func ClaimIssue(ctx context.Context, s Storage, issueID, holder string, ttl time.Duration) (*types.Issue, error) {
+	if holder == "" {
+		return nil, fmt.Errorf("claim requires an actor")
+	}
+	if ttl <= 0 {
+		ttl = types.DefaultLeaseTTL
+	}
+
+	// Free up issues whose previous holder went away
+	if _, err := s.ExpireLeases(ctx, holder); err != nil {
+		return nil, fmt.Errorf("failed to expire leases: %w", err)
+	}
+	return claimIssue(ctx, s, issueID, holder, ttl)
+}
+
+// claimIssue is ClaimIssue without the expired-lease sweep.
+func claimIssue(ctx context.Context, s Storage, issueID, holder string, ttl time.Duration) (*types.Issue, error) {
+	var claimed *types.Issue
+	err := s.RunInTransaction(ctx, func(tx Transaction) error {
+		issue, err := tx.GetIssue(ctx, issueID)
+		if err != nil {
+			return err
+		}
+		if issue == nil {
+			return fmt.Errorf("issue %s not found", issueID)
+		}
+
+		ownedByHolder := issue.Status == types.StatusInProgress && issue.Assignee == holder
+		if issue.Status != types.StatusOpen && !ownedByHolder {
+			return &ClaimError{IssueID: issueID, Reason: fmt.Sprintf("status is %s", issue.Status)}
+		}
+		if issue.Assignee != "" && issue.Assignee != holder {
+			return &ClaimError{IssueID: issueID, Reason: fmt.Sprintf("already claimed by %s", issue.Assignee)}
+		}
+
+		ok, err := tx.AcquireLease(ctx, issueID, holder, ttl)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &ClaimError{IssueID: issueID, Reason: "leased by another agent"}
+		}
+
+		if !ownedByHolder {
+			updates := map[string]interface{}{
+				"status":   string(types.StatusInProgress),
+				"assignee": holder,
+			}
+			if err := tx.UpdateIssue(ctx, issueID, updates, holder); err != nil {
+				return err
+			}
+		}
+
+		claimed, err = tx.GetIssue(ctx, issueID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// ReleaseClaim undoes ClaimIssue: if holder still has issueID in progress,
+// the issue goes back to open and unassigned, and holder's lease is dropped.
+// If holder is an agent bead with the issue on its hook, the hook is cleared
+// so the agent counts as idle again (see UnhookAgent). Releasing an issue
+// someone else has since taken over leaves it alone.
+func ReleaseClaim(ctx context.Context, s Storage, issueID, holder string) error {
+	err := s.RunInTransaction(ctx, func(tx Transaction) error {
+		issue, err := tx.GetIssue(ctx, issueID)
+		if err != nil {
+			return err
+		}
+		if issue == nil || issue.Status != types.StatusInProgress || issue.Assignee != holder {
+			return nil
+		}
+		updates := map[string]interface{}{
+			"status":   string(types.StatusOpen),
+			"assignee": "",
+		}
+		if err := tx.UpdateIssue(ctx, issueID, updates, holder); err != nil {
+			return err
+		}
+		return UnhookAgent(ctx, tx, holder, issueID, holder)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release %s: %w", issueID, err)
+	}
+	return s.ReleaseLease(ctx, issueID, holder)
+}
+
+// UnhookAgent clears the hook of agent bead agentID if it still holds
+// issueID. Lease holders are often agent beads (bd swarm dispatch claims
+// issues under the agent's ID and hooks them on it); once the claim is
+// released or its lease expires the hook must go too, or the agent never
+// looks idle again. A holder that isn't an issue is left alone.
+func UnhookAgent(ctx context.Context, tx Transaction, agentID, issueID, actor string) error {
+	agent, err := tx.GetIssue(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	if agent == nil || agent.HookBead != issueID {
+		return nil
+	}
+	return tx.UpdateIssue(ctx, agentID, map[string]interface{}{"hook_bead": ""}, actor)
+}
+
+// ClaimReady picks the next ready issue matching filter and claims it for
+// holder (see ClaimIssue). Candidates are tried in ready-work order; losing
+// a race on one candidate moves on to the next. Returns ErrNoClaimableWork
+// if nothing could be claimed.
+//
+// Only open issues are considered. Unless filter.Assignee is set, only
+// unassigned issues are candidates.
+func ClaimReady(ctx context.Context, s Storage, filter types.WorkFilter, holder string, ttl time.Duration) (*types.Issue, error) {
+	return ClaimReadyMatching(ctx, s, filter, holder, ttl, nil)
+}
+
+// ClaimReadyMatching is ClaimReady restricted to candidates for which accept
+// returns true, for checks the WorkFilter cannot express (such as actor
+// policies). A nil accept allows every candidate.
+func ClaimReadyMatching(ctx context.Context, s Storage, filter types.WorkFilter, holder string, ttl time.Duration, accept func(*types.Issue) bool) (*types.Issue, error) {
+	if holder == "" {
+		return nil, fmt.Errorf("claim requires an actor")
+	}
+	if ttl <= 0 {
+		ttl = types.DefaultLeaseTTL
+	}
+
+	if _, err := s.ExpireLeases(ctx, holder); err != nil {
+		return nil, fmt.Errorf("failed to expire leases: %w", err)
+	}
+
+	filter.Status = types.StatusOpen
+	filter.LeaseHolder = holder
+	if filter.Assignee == nil {
+		filter.Unassigned = true
+	}
+	if filter.Limit <= 0 || filter.Limit > claimCandidateLimit {
+		filter.Limit = claimCandidateLimit
+	}
+
+	candidates, err := s.GetReadyWork(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ready work: %w", err)
+	}
+
+	for _, candidate := range candidates {
+		if accept != nil && !accept(candidate) {
+			continue
+		}
+		issue, err := claimIssue(ctx, s, candidate.ID, holder, ttl)
+		if err == nil {
+			return issue, nil
+		}
+		var claimErr *ClaimError
+		if errors.As(err, &claimErr) {
+			continue // Lost the race; try the next one
+		}
+		return nil, err
+	}
+	return nil, ErrNoClaimableWork
+}
@@ -0,0 +1,183 @@
+package dolt
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/merge"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// diffRemoved is the diff type of a conflict side that deleted the row
+// (the others are "added" and "modified").
+const diffRemoved = "removed"
+
+// IssueConflict is one conflicting row of the issues table after a Dolt
+// merge, with the three-way field merge already computed.
+type IssueConflict struct {
+	ID            string
+	ConflictID    string       // dolt_conflict_id of the row
+	Base          *types.Issue // nil if both sides added the issue
+	Ours          *types.Issue // nil if we deleted the issue
+	Theirs        *types.Issue // nil if they deleted the issue
+	OurDiffType   string       // "added", "modified" or "removed"
+	TheirDiffType string
+	Merged        *types.Issue // Field merge result; nil if a side deleted the issue
+	Fields        []string     // Fields the merge policy leaves in conflict
+}
+
+// Deleted reports whether one side deleted the issue while the other
+// changed it. Such rows can't be merged field by field.
+func (c *IssueConflict) Deleted() bool {
+	return c.Ours == nil || c.Theirs == nil
+}
+
+// Resolvable reports whether the field merge settled every field.
+func (c *IssueConflict) Resolvable() bool {
+	return !c.Deleted() && len(c.Fields) == 0
+}
+
+// GetIssueConflicts reads the conflicting rows of the issues table and
+// merges each one field by field, using the same rules (and merge.fields
+// policy) as the JSONL merge driver.
+func (s *DoltStore) GetIssueConflicts(ctx context.Context) ([]*IssueConflict, error) {
+	policy, err := merge.LoadPolicy()
+	if err != nil {
+		return nil, err
+	}
+	return s.getIssueConflicts(ctx, policy)
+}
+
+func (s *DoltStore) getIssueConflicts(ctx context.Context, policy *merge.Policy) ([]*IssueConflict, error) {
+	cols := []string{"dolt_conflict_id", "our_diff_type", "their_diff_type"}
+	for _, prefix := range []string{"base_", "our_", "their_"} {
+		for _, col := range issueRowColumns {
+			cols = append(cols, prefix+col)
+		}
+	}
+	// nolint:gosec // G201: column list is a constant
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM dolt_conflicts_issues`, strings.Join(cols, ", ")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issue conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []*IssueConflict
+	for rows.Next() {
+		var c IssueConflict
+		var ourDiff, theirDiff sql.NullString
+		var base, ours, theirs issueRow
+		dests := []interface{}{&c.ConflictID, &ourDiff, &theirDiff}
+		dests = append(dests, base.dests()...)
+		dests = append(dests, ours.dests()...)
+		dests = append(dests, theirs.dests()...)
+		if err := rows.Scan(dests...); err != nil {
+			return nil, fmt.Errorf("failed to scan issue conflict: %w", err)
+		}
+		c.OurDiffType, c.TheirDiffType = ourDiff.String, theirDiff.String
+		c.Base, c.Ours, c.Theirs = base.issue(), ours.issue(), theirs.issue()
+		if c.OurDiffType == diffRemoved {
+			c.Ours = nil
+		}
+		if c.TheirDiffType == diffRemoved {
+			c.Theirs = nil
+		}
+
+		switch {
+		case c.Ours != nil:
+			c.ID = c.Ours.ID
+		case c.Theirs != nil:
+			c.ID = c.Theirs.ID
+		case c.Base != nil:
+			c.ID = c.Base.ID
+		}
+		if !c.Deleted() {
+			merged, fields := merge.MergeIssue3Way(c.Base, *c.Ours, *c.Theirs, policy)
+			merged.ContentHash = merged.ComputeContentHash()
+			c.Merged, c.Fields = &merged, fields
+		}
+		conflicts = append(conflicts, &c)
+	}
+	return conflicts, rows.Err()
+}
+
+// MergeIssueConflicts resolves conflicting rows of the issues table with a
+// three-way field merge, so edits to different fields of the same issue
+// are all kept. fallback ("ours" or "theirs") decides the fields the merge
+// policy leaves in conflict and rows deleted on one side; with an empty
+// fallback those rows stay in conflict. Returns the rows left unresolved.
+func (s *DoltStore) MergeIssueConflicts(ctx context.Context, fallback string) ([]*IssueConflict, error) {
+	if fallback != "" && fallback != "ours" && fallback != "theirs" {
+		return nil, fmt.Errorf("unknown conflict resolution strategy: %s", fallback)
+	}
+	policy, err := merge.LoadPolicy()
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := s.getIssueConflicts(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var remaining []*IssueConflict
+	for _, c := range conflicts {
+		resolved, err := resolveIssueConflict(ctx, tx, c, policy, fallback)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve conflict on %s: %w", c.ID, err)
+		}
+		if !resolved {
+			remaining = append(remaining, c)
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM dolt_conflicts_issues WHERE dolt_conflict_id = ?", c.ConflictID); err != nil {
+			return nil, fmt.Errorf("failed to mark conflict on %s resolved: %w", c.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit conflict resolution: %w", err)
+	}
+	return remaining, nil
+}
+
+// resolveIssueConflict writes the resolved version of one conflicting issue
+// to the working set. It returns false if the conflict needs a fallback
+// and none was given.
+func resolveIssueConflict(ctx context.Context, tx *sql.Tx, c *IssueConflict, policy *merge.Policy, fallback string) (bool, error) {
+	if c.Deleted() {
+		switch fallback {
+		case "ours":
+			// The working set already holds our side
+			return true, nil
+		case "theirs":
+			if c.Theirs == nil {
+				_, err := tx.ExecContext(ctx, "DELETE FROM issues WHERE id = ?", c.ID)
+				return true, err
+			}
+			return true, upsertIssue(ctx, tx, c.Theirs)
+		}
+		return false, nil
+	}
+
+	merged := *c.Merged
+	if len(c.Fields) > 0 {
+		if fallback == "" {
+			return false, nil
+		}
+		side := merge.StrategyLeft
+		if fallback == "theirs" {
+			side = merge.StrategyRight
+		}
+		merged, _ = merge.MergeIssue3Way(c.Base, *c.Ours, *c.Theirs, policy.ConflictsResolvedBy(side))
+		merged.ContentHash = merged.ComputeContentHash()
+	}
+	return true, upsertIssue(ctx, tx, &merged)
+}
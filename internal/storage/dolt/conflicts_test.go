@@ -0,0 +1,96 @@
+package dolt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// TestMergeIssueConflicts edits different fields of the same issue on two
+// branches. Both edits touch updated_at, so Dolt reports a row conflict;
+// the field merge must keep both edits.
+func TestMergeIssueConflicts(t *testing.T) {
+	skipIfNoDolt(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	issue := &types.Issue{
+		ID:        "mc-001",
+		Title:     "Merge conflict test",
+		IssueType: types.TypeTask,
+		Status:    types.StatusOpen,
+		Priority:  2,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("failed to create issue: %v", err)
+	}
+	if err := store.Commit(ctx, "Initial issue"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if err := store.Branch(ctx, "town-beta"); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+
+	// Their side: change the title
+	if err := store.Checkout(ctx, "town-beta"); err != nil {
+		t.Fatalf("failed to checkout: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, "mc-001", map[string]interface{}{"title": "Renamed by beta"}, "beta"); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if err := store.Commit(ctx, "Beta edit"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	// Our side: change the assignee
+	if err := store.Checkout(ctx, "main"); err != nil {
+		t.Fatalf("failed to checkout main: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := store.UpdateIssue(ctx, "mc-001", map[string]interface{}{"assignee": "alpha"}, "alpha"); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if err := store.Commit(ctx, "Alpha edit"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	conflicts, err := store.Merge(ctx, "town-beta")
+	if err != nil {
+		t.Fatalf("failed to merge: %v", err)
+	}
+	if len(conflicts) == 0 {
+		t.Skip("Dolt merged the row without a conflict")
+	}
+
+	issueConflicts, err := store.GetIssueConflicts(ctx)
+	if err != nil {
+		t.Fatalf("GetIssueConflicts: %v", err)
+	}
+	if len(issueConflicts) != 1 || !issueConflicts[0].Resolvable() {
+		t.Fatalf("expected one resolvable issue conflict, got %+v", issueConflicts)
+	}
+
+	remaining, err := store.MergeIssueConflicts(ctx, "")
+	if err != nil {
+		t.Fatalf("MergeIssueConflicts: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("expected no remaining conflicts, got %d", len(remaining))
+	}
+
+	merged, err := store.GetIssue(ctx, "mc-001")
+	if err != nil {
+		t.Fatalf("failed to get issue: %v", err)
+	}
+	if merged.Title != "Renamed by beta" || merged.Assignee != "alpha" {
+		t.Errorf("expected both edits, got title=%q assignee=%q", merged.Title, merged.Assignee)
+	}
+}
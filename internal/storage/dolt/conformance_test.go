@@ -0,0 +1,30 @@
+package dolt
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/storagetest"
+)
+
+// TestConformance runs the shared storage suite. The gaps below are known
+// differences from the SQLite backend; remove an entry once it is fixed.
+func TestConformance(t *testing.T) {
+	skipIfNoDolt(t)
+
+	storagetest.Run(t, storagetest.Backend{
+		New: func(t *testing.T) storage.Storage {
+			store, cleanup := setupTestStore(t)
+			t.Cleanup(cleanup)
+			return store
+		},
+		Gaps: map[storagetest.Capability]string{
+			storagetest.EventHistory:       "dependency and comment mutations are not recorded as events",
+			storagetest.CycleDetection:     "AddDependency does not reject cycles",
+			storagetest.PrefixValidation:   "explicit IDs are not checked against issue_prefix",
+			storagetest.ChildCounters:      "explicit child IDs do not advance child_counters",
+			storagetest.TransitiveBlocking: "only direct blocks dependencies are considered",
+			storagetest.WorkFilterFields:   "GetReadyWork ignores ParentID, MolType and IncludeDeferred",
+		},
+	})
+}
@@ -2,6 +2,7 @@ package dolt
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 	"time"
@@ -36,6 +37,9 @@ func (s *DoltStore) GetDirtyIssueHash(ctx context.Context, issueID string) (stri
 		JOIN dirty_issues d ON i.id = d.issue_id
 		WHERE d.issue_id = ?
 	`, issueID).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil // Not dirty
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to get dirty issue hash: %w", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
@@ -21,6 +22,18 @@ func (s *DoltStore) AddComment(ctx context.Context, issueID, actor, comment stri
 	return nil
 }
 
+// AddEvent records an audit event for an issue without touching the issue
+func (s *DoltStore) AddEvent(ctx context.Context, event *types.Event) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, event.IssueID, event.EventType, event.Actor, nullStringPtr(event.OldValue), nullStringPtr(event.NewValue), nullStringPtr(event.Comment))
+	if err != nil {
+		return fmt.Errorf("failed to add event: %w", err)
+	}
+	return nil
+}
+
 // GetEvents retrieves events for an issue
 func (s *DoltStore) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
 	query := `
@@ -40,7 +53,52 @@ func (s *DoltStore) GetEvents(ctx context.Context, issueID string, limit int) ([
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
 	defer rows.Close()
+	return scanEvents(rows)
+}
+
+// SearchEvents retrieves events across issues matching the filter, oldest first
+func (s *DoltStore) SearchEvents(ctx context.Context, filter types.EventFilter) ([]*types.Event, error) {
+	where := []string{"1 = 1"}
+	var args []interface{}
+	if len(filter.IssueIDs) > 0 {
+		where = append(where, fmt.Sprintf("issue_id IN (%s)", placeholders(len(filter.IssueIDs))))
+		for _, id := range filter.IssueIDs {
+			args = append(args, id)
+		}
+	}
+	if len(filter.Types) > 0 {
+		where = append(where, fmt.Sprintf("event_type IN (%s)", placeholders(len(filter.Types))))
+		for _, t := range filter.Types {
+			args = append(args, string(t))
+		}
+	}
+	if filter.Since != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, filter.Since.UTC())
+	}
+	if filter.Until != nil {
+		where = append(where, "created_at <= ?")
+		args = append(args, filter.Until.UTC())
+	}
+
+	// #nosec G201 - where contains only placeholders
+	query := fmt.Sprintf(`
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
+		FROM events
+		WHERE %s
+		ORDER BY created_at ASC, id ASC
+	`, strings.Join(where, " AND "))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	defer rows.Close()
+	return scanEvents(rows)
+}
 
+// scanEvents reads event rows selected in the standard column order
+func scanEvents(rows *sql.Rows) ([]*types.Event, error) {
 	var events []*types.Event
 	for rows.Next() {
 		var event types.Event
@@ -78,6 +136,11 @@ func (s *DoltStore) AddIssueComment(ctx context.Context, issueID, author, text s
 		return nil, fmt.Errorf("failed to get comment id: %w", err)
 	}
 
+	// Commenting counts as activity for the lease holder
+	if err := renewLease(ctx, s.db, issueID, author); err != nil {
+		return nil, fmt.Errorf("failed to renew lease: %w", err)
+	}
+
 	return &types.Comment{
 		ID:        id,
 		IssueID:   issueID,
@@ -154,27 +154,44 @@ func (s *DoltStore) Sync(ctx context.Context, peer string, strategy string) (*Sy
 		return result, result.Error
 	}
 
-	// Step 4: Handle conflicts if any
+	// Step 4: Handle conflicts if any. Issue rows are merged field by field;
+	// the strategy decides whatever that leaves, and other tables wholesale.
 	if len(conflicts) > 0 {
 		result.Conflicts = conflicts
 
-		if strategy == "" {
-			// No strategy specified, leave conflicts for manual resolution
-			result.Error = fmt.Errorf("merge conflicts require resolution (use --strategy ours|theirs)")
-			return result, result.Error
-		}
-
-		// Auto-resolve using strategy
 		for _, c := range conflicts {
-			if err := s.ResolveConflicts(ctx, c.Field, strategy); err != nil {
+			tableStrategy := strategy
+			if c.Field == "issues" && strategy == "" {
+				tableStrategy = "merge"
+			}
+			if tableStrategy == "" {
+				continue
+			}
+			if err := s.ResolveConflicts(ctx, c.Field, tableStrategy); err != nil {
 				result.Error = fmt.Errorf("conflict resolution failed for %s: %w", c.Field, err)
 				return result, result.Error
 			}
 		}
+
+		remaining, err := s.GetConflicts(ctx)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to check remaining conflicts: %w", err)
+			return result, result.Error
+		}
+		if len(remaining) > 0 {
+			// Leave the rest for manual resolution
+			result.Conflicts = remaining
+			result.Error = fmt.Errorf("merge conflicts require resolution (see 'bd vc conflicts', or use --strategy ours|theirs)")
+			return result, result.Error
+		}
 		result.ConflictsResolved = true
 
 		// Commit the resolution
-		if err := s.Commit(ctx, fmt.Sprintf("Resolve conflicts from %s using %s strategy", peer, strategy)); err != nil {
+		message := fmt.Sprintf("Resolve conflicts from %s by field merge", peer)
+		if strategy != "" {
+			message = fmt.Sprintf("Resolve conflicts from %s using %s strategy", peer, strategy)
+		}
+		if err := s.Commit(ctx, message); err != nil {
 			result.Error = fmt.Errorf("failed to commit conflict resolution: %w", err)
 			return result, result.Error
 		}
@@ -318,13 +318,26 @@ type TableConflict struct {
 	NumConflicts int
 }
 
-// ResolveConflicts resolves conflicts using the specified strategy
+// ResolveConflicts resolves conflicts using the specified strategy.
+// Conflicts in the issues table are merged field by field (see
+// MergeIssueConflicts) and the strategy only decides what that leaves;
+// "merge" leaves those rows in conflict. Other tables take one side
+// ("ours" or "theirs") wholesale.
 func (s *DoltStore) ResolveConflicts(ctx context.Context, table string, strategy string) error {
 	// Validate table name to prevent SQL injection
 	if err := validateTableName(table); err != nil {
 		return fmt.Errorf("invalid table name: %w", err)
 	}
 
+	if table == "issues" {
+		fallback := strategy
+		if strategy == "merge" {
+			fallback = ""
+		}
+		_, err := s.MergeIssueConflicts(ctx, fallback)
+		return err
+	}
+
 	var query string
 	switch strategy {
 	case "ours":
@@ -332,6 +345,8 @@ func (s *DoltStore) ResolveConflicts(ctx context.Context, table string, strategy
 		query = fmt.Sprintf("CALL DOLT_CONFLICTS_RESOLVE('--ours', '%s')", table)
 	case "theirs":
 		query = fmt.Sprintf("CALL DOLT_CONFLICTS_RESOLVE('--theirs', '%s')", table)
+	case "merge":
+		return fmt.Errorf("field-level merge is only supported for the issues table, not %s", table)
 	default:
 		return fmt.Errorf("unknown conflict resolution strategy: %s", strategy)
 	}
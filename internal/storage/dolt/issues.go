@@ -284,6 +284,11 @@ func (s *DoltStore) UpdateIssue(ctx context.Context, id string, updates map[stri
 		return fmt.Errorf("failed to mark dirty: %w", err)
 	}
 
+	// Activity by the lease holder keeps their claim alive
+	if err := renewLease(ctx, tx, id, actor); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+
 	return tx.Commit()
 }
 
@@ -321,6 +326,11 @@ func (s *DoltStore) CloseIssue(ctx context.Context, id string, reason string, ac
 		return fmt.Errorf("failed to mark dirty: %w", err)
 	}
 
+	// Closing ends any claim on the issue
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_leases WHERE issue_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+
 	return tx.Commit()
 }
 
@@ -366,33 +376,46 @@ func (s *DoltStore) DeleteIssue(ctx context.Context, id string) error {
 // =============================================================================
 
 func insertIssue(ctx context.Context, tx *sql.Tx, issue *types.Issue) error {
-	_, err := tx.ExecContext(ctx, `
-		INSERT INTO issues (
-			id, content_hash, title, description, design, acceptance_criteria, notes,
-			status, priority, issue_type, assignee, estimated_minutes,
-			created_at, created_by, owner, updated_at, closed_at, external_ref,
-			compaction_level, compacted_at, compacted_at_commit, original_size,
-			deleted_at, deleted_by, delete_reason, original_type,
-			sender, ephemeral, pinned, is_template, crystallizes,
-			mol_type, work_type, quality_score, source_system, source_repo, close_reason,
-			event_kind, actor, target, payload,
-			await_type, await_id, timeout_ns, waiters,
-			hook_bead, role_bead, agent_state, last_activity, role_type, rig,
-			due_at, defer_until
-		) VALUES (
-			?, ?, ?, ?, ?, ?, ?,
-			?, ?, ?, ?, ?,
-			?, ?, ?, ?, ?, ?,
-			?, ?, ?, ?,
-			?, ?, ?, ?,
-			?, ?, ?, ?, ?,
-			?, ?, ?, ?, ?, ?,
-			?, ?, ?, ?,
-			?, ?, ?, ?,
-			?, ?, ?, ?, ?, ?,
-			?, ?
-		)
-	`,
+	// nolint:gosec // G201: column list is a constant
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO issues (%s) VALUES (%s)`,
+		strings.Join(issueInsertColumns, ", "), placeholders(len(issueInsertColumns))),
+		issueInsertValues(issue)...)
+	return err
+}
+
+// upsertIssue writes every column of an issue, inserting the row if it
+// doesn't exist. Unlike a delete and re-insert, it leaves rows that
+// reference the issue (labels, dependencies, events) alone.
+func upsertIssue(ctx context.Context, tx *sql.Tx, issue *types.Issue) error {
+	updates := make([]string, 0, len(issueInsertColumns)-1)
+	for _, col := range issueInsertColumns[1:] {
+		updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", col, col))
+	}
+	// nolint:gosec // G201: column list is a constant
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO issues (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s`,
+		strings.Join(issueInsertColumns, ", "), placeholders(len(issueInsertColumns)), strings.Join(updates, ", ")),
+		issueInsertValues(issue)...)
+	return err
+}
+
+// issueInsertColumns are the issues table columns written by insertIssue,
+// in the order of issueInsertValues. The id must stay first.
+var issueInsertColumns = []string{
+	"id", "content_hash", "title", "description", "design", "acceptance_criteria", "notes",
+	"status", "priority", "issue_type", "assignee", "estimated_minutes",
+	"created_at", "created_by", "owner", "updated_at", "closed_at", "external_ref",
+	"compaction_level", "compacted_at", "compacted_at_commit", "original_size",
+	"deleted_at", "deleted_by", "delete_reason", "original_type",
+	"sender", "ephemeral", "pinned", "is_template", "crystallizes",
+	"mol_type", "work_type", "quality_score", "source_system", "source_repo", "close_reason",
+	"event_kind", "actor", "target", "payload",
+	"await_type", "await_id", "timeout_ns", "waiters",
+	"hook_bead", "role_bead", "agent_state", "last_activity", "role_type", "rig",
+	"due_at", "defer_until",
+}
+
+func issueInsertValues(issue *types.Issue) []interface{} {
+	return []interface{}{
 		issue.ID, issue.ContentHash, issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes,
 		issue.Status, issue.Priority, issue.IssueType, nullString(issue.Assignee), nullInt(issue.EstimatedMinutes),
 		issue.CreatedAt, issue.CreatedBy, issue.Owner, issue.UpdatedAt, issue.ClosedAt, nullStringPtr(issue.ExternalRef),
@@ -404,184 +427,163 @@ func insertIssue(ctx context.Context, tx *sql.Tx, issue *types.Issue) error {
 		issue.AwaitType, issue.AwaitID, issue.Timeout.Nanoseconds(), formatJSONStringArray(issue.Waiters),
 		issue.HookBead, issue.RoleBead, issue.AgentState, issue.LastActivity, issue.RoleType, issue.Rig,
 		issue.DueAt, issue.DeferUntil,
-	)
-	return err
+	}
 }
 
-func scanIssue(ctx context.Context, db *sql.DB, id string) (*types.Issue, error) {
-	var issue types.Issue
-	var closedAt, compactedAt, deletedAt, lastActivity, dueAt, deferUntil sql.NullTime
-	var estimatedMinutes, originalSize, timeoutNs sql.NullInt64
-	var assignee, externalRef, compactedAtCommit, owner sql.NullString
-	var contentHash, sourceRepo, closeReason, deletedBy, deleteReason, originalType sql.NullString
-	var workType, sourceSystem sql.NullString
-	var sender, molType, eventKind, actor, target, payload sql.NullString
-	var awaitType, awaitID, waiters sql.NullString
-	var hookBead, roleBead, agentState, roleType, rig sql.NullString
-	var ephemeral, pinned, isTemplate, crystallizes sql.NullInt64
-	var qualityScore sql.NullFloat64
-
-	err := db.QueryRowContext(ctx, `
-		SELECT id, content_hash, title, description, design, acceptance_criteria, notes,
-		       status, priority, issue_type, assignee, estimated_minutes,
-		       created_at, created_by, owner, updated_at, closed_at, external_ref,
-		       compaction_level, compacted_at, compacted_at_commit, original_size, source_repo, close_reason,
-		       deleted_at, deleted_by, delete_reason, original_type,
-		       sender, ephemeral, pinned, is_template, crystallizes,
-		       await_type, await_id, timeout_ns, waiters,
-		       hook_bead, role_bead, agent_state, last_activity, role_type, rig, mol_type,
-		       event_kind, actor, target, payload,
-		       due_at, defer_until,
-		       quality_score, work_type, source_system
-		FROM issues
-		WHERE id = ?
-	`, id).Scan(
-		&issue.ID, &contentHash, &issue.Title, &issue.Description, &issue.Design,
-		&issue.AcceptanceCriteria, &issue.Notes, &issue.Status,
-		&issue.Priority, &issue.IssueType, &assignee, &estimatedMinutes,
-		&issue.CreatedAt, &issue.CreatedBy, &owner, &issue.UpdatedAt, &closedAt, &externalRef,
-		&issue.CompactionLevel, &compactedAt, &compactedAtCommit, &originalSize, &sourceRepo, &closeReason,
-		&deletedAt, &deletedBy, &deleteReason, &originalType,
-		&sender, &ephemeral, &pinned, &isTemplate, &crystallizes,
-		&awaitType, &awaitID, &timeoutNs, &waiters,
-		&hookBead, &roleBead, &agentState, &lastActivity, &roleType, &rig, &molType,
-		&eventKind, &actor, &target, &payload,
-		&dueAt, &deferUntil,
-		&qualityScore, &workType, &sourceSystem,
-	)
+// placeholders returns n comma-separated "?" placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
 
+func scanIssue(ctx context.Context, db *sql.DB, id string) (*types.Issue, error) {
+	var row issueRow
+	// nolint:gosec // G201: column list is a constant
+	err := db.QueryRowContext(ctx, fmt.Sprintf(`SELECT %s FROM issues WHERE id = ?`,
+		strings.Join(issueRowColumns, ", ")), id).Scan(row.dests()...)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issue: %w", err)
 	}
+	return row.issue(), nil
+}
+
+// issueRowColumns are the issues table columns read by issueRow, in scan order.
+var issueRowColumns = []string{
+	"id", "content_hash", "title", "description", "design", "acceptance_criteria", "notes",
+	"status", "priority", "issue_type", "assignee", "estimated_minutes",
+	"created_at", "created_by", "owner", "updated_at", "closed_at", "external_ref",
+	"compaction_level", "compacted_at", "compacted_at_commit", "original_size", "source_repo", "close_reason",
+	"deleted_at", "deleted_by", "delete_reason", "original_type",
+	"sender", "ephemeral", "pinned", "is_template", "crystallizes",
+	"await_type", "await_id", "timeout_ns", "waiters",
+	"hook_bead", "role_bead", "agent_state", "last_activity", "role_type", "rig", "mol_type",
+	"event_kind", "actor", "target", "payload",
+	"due_at", "defer_until",
+	"quality_score", "work_type", "source_system",
+}
+
+// issueRow holds one scanned issues row. Every column is nullable so the
+// same scan works for the base/our/their sides of dolt_conflicts_issues,
+// where a side that doesn't have the row is all NULL.
+type issueRow struct {
+	id, contentHash, title, description, design, acceptanceCriteria, notes sql.NullString
+	status, issueType, assignee, createdBy, owner, externalRef             sql.NullString
+	compactedAtCommit, sourceRepo, closeReason                             sql.NullString
+	deletedBy, deleteReason, originalType, sender                          sql.NullString
+	awaitType, awaitID, waiters, hookBead, roleBead, agentState            sql.NullString
+	roleType, rig, molType, eventKind, actor, target, payload              sql.NullString
+	workType, sourceSystem                                                 sql.NullString
+	priority, estimatedMinutes, compactionLevel, originalSize, timeoutNs   sql.NullInt64
+	ephemeral, pinned, isTemplate, crystallizes                            sql.NullInt64
+	createdAt, updatedAt, closedAt, compactedAt, deletedAt                 sql.NullTime
+	lastActivity, dueAt, deferUntil                                        sql.NullTime
+	qualityScore                                                           sql.NullFloat64
+}
 
-	// Map nullable fields
-	if contentHash.Valid {
-		issue.ContentHash = contentHash.String
+// dests returns scan destinations matching issueRowColumns.
+func (r *issueRow) dests() []interface{} {
+	return []interface{}{
+		&r.id, &r.contentHash, &r.title, &r.description, &r.design, &r.acceptanceCriteria, &r.notes,
+		&r.status, &r.priority, &r.issueType, &r.assignee, &r.estimatedMinutes,
+		&r.createdAt, &r.createdBy, &r.owner, &r.updatedAt, &r.closedAt, &r.externalRef,
+		&r.compactionLevel, &r.compactedAt, &r.compactedAtCommit, &r.originalSize, &r.sourceRepo, &r.closeReason,
+		&r.deletedAt, &r.deletedBy, &r.deleteReason, &r.originalType,
+		&r.sender, &r.ephemeral, &r.pinned, &r.isTemplate, &r.crystallizes,
+		&r.awaitType, &r.awaitID, &r.timeoutNs, &r.waiters,
+		&r.hookBead, &r.roleBead, &r.agentState, &r.lastActivity, &r.roleType, &r.rig, &r.molType,
+		&r.eventKind, &r.actor, &r.target, &r.payload,
+		&r.dueAt, &r.deferUntil,
+		&r.qualityScore, &r.workType, &r.sourceSystem,
 	}
-	if closedAt.Valid {
-		issue.ClosedAt = &closedAt.Time
+}
+
+// issue converts the row to an Issue, or nil if the row was all NULL.
+func (r *issueRow) issue() *types.Issue {
+	if !r.id.Valid {
+		return nil
 	}
-	if estimatedMinutes.Valid {
-		mins := int(estimatedMinutes.Int64)
+	issue := &types.Issue{
+		ID:                 r.id.String,
+		ContentHash:        r.contentHash.String,
+		Title:              r.title.String,
+		Description:        r.description.String,
+		Design:             r.design.String,
+		AcceptanceCriteria: r.acceptanceCriteria.String,
+		Notes:              r.notes.String,
+		Status:             types.Status(r.status.String),
+		Priority:           int(r.priority.Int64),
+		IssueType:          types.IssueType(r.issueType.String),
+		Assignee:           r.assignee.String,
+		CreatedAt:          r.createdAt.Time,
+		CreatedBy:          r.createdBy.String,
+		Owner:              r.owner.String,
+		UpdatedAt:          r.updatedAt.Time,
+		CompactionLevel:    int(r.compactionLevel.Int64),
+		OriginalSize:       int(r.originalSize.Int64),
+		SourceRepo:         r.sourceRepo.String,
+		CloseReason:        r.closeReason.String,
+		DeletedBy:          r.deletedBy.String,
+		DeleteReason:       r.deleteReason.String,
+		OriginalType:       r.originalType.String,
+		Sender:             r.sender.String,
+		Ephemeral:          r.ephemeral.Valid && r.ephemeral.Int64 != 0,
+		Pinned:             r.pinned.Valid && r.pinned.Int64 != 0,
+		IsTemplate:         r.isTemplate.Valid && r.isTemplate.Int64 != 0,
+		Crystallizes:       r.crystallizes.Valid && r.crystallizes.Int64 != 0,
+		AwaitType:          r.awaitType.String,
+		AwaitID:            r.awaitID.String,
+		Timeout:            time.Duration(r.timeoutNs.Int64),
+		HookBead:           r.hookBead.String,
+		RoleBead:           r.roleBead.String,
+		AgentState:         types.AgentState(r.agentState.String),
+		RoleType:           r.roleType.String,
+		Rig:                r.rig.String,
+		MolType:            types.MolType(r.molType.String),
+		EventKind:          r.eventKind.String,
+		Actor:              r.actor.String,
+		Target:             r.target.String,
+		Payload:            r.payload.String,
+		WorkType:           types.WorkType(r.workType.String),
+		SourceSystem:       r.sourceSystem.String,
+	}
+	if r.closedAt.Valid {
+		issue.ClosedAt = &r.closedAt.Time
+	}
+	if r.estimatedMinutes.Valid {
+		mins := int(r.estimatedMinutes.Int64)
 		issue.EstimatedMinutes = &mins
 	}
-	if assignee.Valid {
-		issue.Assignee = assignee.String
-	}
-	if owner.Valid {
-		issue.Owner = owner.String
+	if r.externalRef.Valid {
+		issue.ExternalRef = &r.externalRef.String
 	}
-	if externalRef.Valid {
-		issue.ExternalRef = &externalRef.String
+	if r.compactedAt.Valid {
+		issue.CompactedAt = &r.compactedAt.Time
 	}
-	if compactedAt.Valid {
-		issue.CompactedAt = &compactedAt.Time
+	if r.compactedAtCommit.Valid {
+		issue.CompactedAtCommit = &r.compactedAtCommit.String
 	}
-	if compactedAtCommit.Valid {
-		issue.CompactedAtCommit = &compactedAtCommit.String
+	if r.deletedAt.Valid {
+		issue.DeletedAt = &r.deletedAt.Time
 	}
-	if originalSize.Valid {
-		issue.OriginalSize = int(originalSize.Int64)
+	if r.waiters.Valid && r.waiters.String != "" {
+		issue.Waiters = parseJSONStringArray(r.waiters.String)
 	}
-	if sourceRepo.Valid {
-		issue.SourceRepo = sourceRepo.String
+	if r.lastActivity.Valid {
+		issue.LastActivity = &r.lastActivity.Time
 	}
-	if closeReason.Valid {
-		issue.CloseReason = closeReason.String
+	if r.dueAt.Valid {
+		issue.DueAt = &r.dueAt.Time
 	}
-	if deletedAt.Valid {
-		issue.DeletedAt = &deletedAt.Time
+	if r.deferUntil.Valid {
+		issue.DeferUntil = &r.deferUntil.Time
 	}
-	if deletedBy.Valid {
-		issue.DeletedBy = deletedBy.String
-	}
-	if deleteReason.Valid {
-		issue.DeleteReason = deleteReason.String
-	}
-	if originalType.Valid {
-		issue.OriginalType = originalType.String
-	}
-	if sender.Valid {
-		issue.Sender = sender.String
-	}
-	if ephemeral.Valid && ephemeral.Int64 != 0 {
-		issue.Ephemeral = true
-	}
-	if pinned.Valid && pinned.Int64 != 0 {
-		issue.Pinned = true
-	}
-	if isTemplate.Valid && isTemplate.Int64 != 0 {
-		issue.IsTemplate = true
-	}
-	if crystallizes.Valid && crystallizes.Int64 != 0 {
-		issue.Crystallizes = true
-	}
-	if awaitType.Valid {
-		issue.AwaitType = awaitType.String
-	}
-	if awaitID.Valid {
-		issue.AwaitID = awaitID.String
-	}
-	if timeoutNs.Valid {
-		issue.Timeout = time.Duration(timeoutNs.Int64)
-	}
-	if waiters.Valid && waiters.String != "" {
-		issue.Waiters = parseJSONStringArray(waiters.String)
-	}
-	if hookBead.Valid {
-		issue.HookBead = hookBead.String
-	}
-	if roleBead.Valid {
-		issue.RoleBead = roleBead.String
-	}
-	if agentState.Valid {
-		issue.AgentState = types.AgentState(agentState.String)
-	}
-	if lastActivity.Valid {
-		issue.LastActivity = &lastActivity.Time
-	}
-	if roleType.Valid {
-		issue.RoleType = roleType.String
-	}
-	if rig.Valid {
-		issue.Rig = rig.String
-	}
-	if molType.Valid {
-		issue.MolType = types.MolType(molType.String)
-	}
-	if eventKind.Valid {
-		issue.EventKind = eventKind.String
-	}
-	if actor.Valid {
-		issue.Actor = actor.String
-	}
-	if target.Valid {
-		issue.Target = target.String
-	}
-	if payload.Valid {
-		issue.Payload = payload.String
-	}
-	if dueAt.Valid {
-		issue.DueAt = &dueAt.Time
-	}
-	if deferUntil.Valid {
-		issue.DeferUntil = &deferUntil.Time
-	}
-	if qualityScore.Valid {
-		qs := float32(qualityScore.Float64)
+	if r.qualityScore.Valid {
+		qs := float32(r.qualityScore.Float64)
 		issue.QualityScore = &qs
 	}
-	if workType.Valid {
-		issue.WorkType = types.WorkType(workType.String)
-	}
-	if sourceSystem.Valid {
-		issue.SourceSystem = sourceSystem.String
-	}
-
-	return &issue, nil
+	return issue
 }
 
 func recordEvent(ctx context.Context, tx *sql.Tx, issueID string, eventType types.EventType, actor, oldValue, newValue string) error {
@@ -10,24 +10,41 @@ import (
 
 // AddLabel adds a label to an issue
 func (s *DoltStore) AddLabel(ctx context.Context, issueID, label, actor string) error {
-	_, err := s.db.ExecContext(ctx, `
+	return s.mutateLabel(ctx, issueID, label, actor, "add", types.EventLabelAdded, `
 		INSERT IGNORE INTO labels (issue_id, label) VALUES (?, ?)
-	`, issueID, label)
-	if err != nil {
-		return fmt.Errorf("failed to add label: %w", err)
-	}
-	return nil
+	`)
 }
 
 // RemoveLabel removes a label from an issue
 func (s *DoltStore) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
-	_, err := s.db.ExecContext(ctx, `
+	return s.mutateLabel(ctx, issueID, label, actor, "remove", types.EventLabelRemoved, `
 		DELETE FROM labels WHERE issue_id = ? AND label = ?
-	`, issueID, label)
+	`)
+}
+
+// mutateLabel runs a label insert or delete and, if it changed anything,
+// records the event and marks the issue dirty in the same transaction.
+func (s *DoltStore) mutateLabel(ctx context.Context, issueID, label, actor, op string, eventType types.EventType, stmt string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, stmt, issueID, label)
 	if err != nil {
-		return fmt.Errorf("failed to remove label: %w", err)
+		return fmt.Errorf("failed to %s label: %w", op, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil
+	}
+	if err := recordEvent(ctx, tx, issueID, eventType, actor, "", label); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	if err := markDirty(ctx, tx, issueID); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 // GetLabels retrieves all labels for an issue
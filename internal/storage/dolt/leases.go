@@ -0,0 +1,156 @@
+package dolt
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// AcquireLease grants holder a lease on issueID within the transaction.
+// Returns false if another holder has an active lease. The read and write
+// happen in the same transaction, so two concurrent claimers conflict on
+// the issue_leases row at commit time and only one of them wins.
+func (t *doltTransaction) AcquireLease(ctx context.Context, issueID, holder string, ttl time.Duration) (bool, error) {
+	if holder == "" {
+		return false, fmt.Errorf("lease holder is required")
+	}
+	if ttl <= 0 {
+		return false, fmt.Errorf("lease ttl must be positive (got %s)", ttl)
+	}
+
+	now := time.Now().UTC()
+	var current string
+	var expiresAt time.Time
+	err := t.tx.QueryRowContext(ctx, `
+		SELECT holder, expires_at FROM issue_leases WHERE issue_id = ? FOR UPDATE
+	`, issueID).Scan(&current, &expiresAt)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to read lease: %w", err)
+	}
+	if err == nil && current != holder && expiresAt.After(now) {
+		return false, nil
+	}
+
+	_, err = t.tx.ExecContext(ctx, `
+		REPLACE INTO issue_leases (issue_id, holder, acquired_at, expires_at, ttl_ns)
+		VALUES (?, ?, ?, ?, ?)
+	`, issueID, holder, now, now.Add(ttl), int64(ttl))
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+	return true, nil
+}
+
+// GetLease returns the lease on an issue, or nil if there is none.
+func (s *DoltStore) GetLease(ctx context.Context, issueID string) (*types.Lease, error) {
+	var lease types.Lease
+	var ttlNs int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT issue_id, holder, acquired_at, expires_at, ttl_ns
+		FROM issue_leases WHERE issue_id = ?
+	`, issueID).Scan(&lease.IssueID, &lease.Holder, &lease.AcquiredAt, &lease.ExpiresAt, &ttlNs)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lease: %w", err)
+	}
+	lease.TTL = time.Duration(ttlNs)
+	return &lease, nil
+}
+
+// ReleaseLease drops holder's lease on an issue.
+func (s *DoltStore) ReleaseLease(ctx context.Context, issueID, holder string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM issue_leases WHERE issue_id = ? AND holder = ?
+	`, issueID, holder)
+	if err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
+
+// ExpireLeases deletes every expired lease and reopens issues that are still
+// in_progress under the former holder, taking them off the holder's hook if
+// it is an agent bead. Returns the IDs of reopened issues.
+func (s *DoltStore) ExpireLeases(ctx context.Context, actor string) ([]string, error) {
+	now := time.Now().UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT l.issue_id, l.holder
+		FROM issue_leases l
+		JOIN issues i ON i.id = l.issue_id
+		WHERE l.expires_at <= ? AND i.status = ? AND i.assignee = l.holder
+	`, now, types.StatusInProgress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired leases: %w", err)
+	}
+	var ids, holders []string
+	for rows.Next() {
+		var id, holder string
+		if err := rows.Scan(&id, &holder); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan expired lease: %w", err)
+		}
+		ids = append(ids, id)
+		holders = append(holders, holder)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE issues SET status = ?, assignee = '', updated_at = ? WHERE id = ?
+		`, types.StatusOpen, now, id); err != nil {
+			return nil, fmt.Errorf("failed to reopen %s: %w", id, err)
+		}
+		if err := recordEvent(ctx, tx, id, types.EventStatusChanged, actor,
+			string(types.StatusInProgress), string(types.StatusOpen)); err != nil {
+			return nil, fmt.Errorf("failed to record event: %w", err)
+		}
+		if err := markDirty(ctx, tx, id); err != nil {
+			return nil, fmt.Errorf("failed to mark dirty: %w", err)
+		}
+		if err := storage.UnhookAgent(ctx, &doltTransaction{tx: tx, store: s}, holders[i], id, actor); err != nil {
+			return nil, fmt.Errorf("failed to unhook %s from %s: %w", id, holders[i], err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_leases WHERE expires_at <= ?`, now); err != nil {
+		return nil, fmt.Errorf("failed to delete expired leases: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// leaseExecer is satisfied by *sql.DB and *sql.Tx.
+type leaseExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// renewLease extends an active lease held by actor to now+ttl.
+// Called on issue activity so that working agents keep their claim.
+func renewLease(ctx context.Context, db leaseExecer, issueID, actor string) error {
+	now := time.Now().UTC()
+	_, err := db.ExecContext(ctx, `
+		UPDATE issue_leases
+		SET expires_at = DATE_ADD(?, INTERVAL (ttl_ns DIV 1000) MICROSECOND)
+		WHERE issue_id = ? AND holder = ? AND expires_at > ?
+	`, now, issueID, actor, now)
+	return err
+}
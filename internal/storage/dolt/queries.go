@@ -233,9 +233,17 @@ func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	whereClauses := []string{"status = 'open'", "(ephemeral = 0 OR ephemeral IS NULL)"}
+	whereClauses := []string{"(pinned = 0 OR pinned IS NULL)", "(ephemeral = 0 OR ephemeral IS NULL)"}
 	args := []interface{}{}
 
+	// Default to open OR in_progress, matching the SQLite backend
+	if filter.Status == "" {
+		whereClauses = append(whereClauses, "status IN ('open', 'in_progress')")
+	} else {
+		whereClauses = append(whereClauses, "status = ?")
+		args = append(args, filter.Status)
+	}
+
 	if filter.Priority != nil {
 		whereClauses = append(whereClauses, "priority = ?")
 		args = append(args, *filter.Priority)
@@ -243,8 +251,14 @@ func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 	if filter.Type != "" {
 		whereClauses = append(whereClauses, "issue_type = ?")
 		args = append(args, filter.Type)
+	} else {
+		// Workflow types are not claimable work (see sqlite GetReadyWork)
+		whereClauses = append(whereClauses, "issue_type NOT IN ('merge-request', 'gate', 'molecule', 'message', 'agent', 'role', 'rig')")
 	}
-	if filter.Assignee != nil {
+	// Unassigned takes precedence over Assignee filter
+	if filter.Unassigned {
+		whereClauses = append(whereClauses, "(assignee IS NULL OR assignee = '')")
+	} else if filter.Assignee != nil {
 		whereClauses = append(whereClauses, "assignee = ?")
 		args = append(args, *filter.Assignee)
 	}
@@ -254,6 +268,18 @@ func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 			args = append(args, label)
 		}
 	}
+	if len(filter.LabelsAny) > 0 {
+		placeholders := make([]string, len(filter.LabelsAny))
+		for i, label := range filter.LabelsAny {
+			placeholders[i] = "?"
+			args = append(args, label)
+		}
+		whereClauses = append(whereClauses, fmt.Sprintf("id IN (SELECT issue_id FROM labels WHERE label IN (%s))", strings.Join(placeholders, ", ")))
+	}
+
+	// Hide issues under an active lease held by someone else (bd claim)
+	whereClauses = append(whereClauses, "id NOT IN (SELECT issue_id FROM issue_leases WHERE expires_at > ? AND holder != ?)")
+	args = append(args, time.Now().UTC(), filter.LeaseHolder)
 
 	// Exclude blocked issues using subquery
 	whereClauses = append(whereClauses, `
@@ -277,9 +303,9 @@ func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 	query := fmt.Sprintf(`
 		SELECT id FROM issues
 		%s
-		ORDER BY priority ASC, created_at DESC
 		%s
-	`, whereSQL, limitSQL)
+		%s
+	`, whereSQL, readyOrderBy(filter.SortPolicy), limitSQL)
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -290,6 +316,22 @@ func (s *DoltStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 	return s.scanIssueIDs(ctx, rows)
 }
 
+// readyOrderBy mirrors the SQLite sort policies: hybrid (the default) puts
+// issues from the last 48 hours first by priority, then older ones by age.
+func readyOrderBy(policy types.SortPolicy) string {
+	switch policy {
+	case types.SortPolicyPriority:
+		return "ORDER BY priority ASC, created_at ASC"
+	case types.SortPolicyOldest:
+		return "ORDER BY created_at ASC"
+	default:
+		return `ORDER BY
+			CASE WHEN created_at >= NOW() - INTERVAL 48 HOUR THEN 0 ELSE 1 END ASC,
+			CASE WHEN created_at >= NOW() - INTERVAL 48 HOUR THEN priority ELSE NULL END ASC,
+			created_at ASC`
+	}
+}
+
 // GetBlockedIssues returns issues that are blocked by other issues
 func (s *DoltStore) GetBlockedIssues(ctx context.Context, filter types.WorkFilter) ([]*types.BlockedIssue, error) {
 	s.mu.RLock()
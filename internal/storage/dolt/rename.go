@@ -63,6 +63,12 @@ func (s *DoltStore) UpdateIssueID(ctx context.Context, oldID, newID string, issu
 		return fmt.Errorf("failed to update comments: %w", err)
 	}
 
+	// Update references in commit links
+	_, err = tx.ExecContext(ctx, `UPDATE commit_links SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update commit_links: %w", err)
+	}
+
 	// Update dirty_issues
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO dirty_issues (issue_id, marked_at)
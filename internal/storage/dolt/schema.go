@@ -169,6 +169,31 @@ CREATE TABLE IF NOT EXISTS child_counters (
     CONSTRAINT fk_counter_parent FOREIGN KEY (parent_id) REFERENCES issues(id) ON DELETE CASCADE
 );
 
+-- Issue leases table (for bd claim, local coordination state)
+CREATE TABLE IF NOT EXISTS issue_leases (
+    issue_id VARCHAR(255) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    acquired_at DATETIME(6) NOT NULL,
+    expires_at DATETIME(6) NOT NULL,
+    ttl_ns BIGINT NOT NULL,
+    INDEX idx_issue_leases_expires_at (expires_at),
+    CONSTRAINT fk_lease_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Commit links table (for bd commits, derived from git history)
+CREATE TABLE IF NOT EXISTS commit_links (
+    issue_id VARCHAR(255) NOT NULL,
+    commit_sha VARCHAR(64) NOT NULL,
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    subject TEXT NOT NULL,
+    files TEXT NOT NULL,
+    keyword VARCHAR(32) NOT NULL DEFAULT 'mentions',
+    committed_at DATETIME NOT NULL,
+    PRIMARY KEY (issue_id, commit_sha),
+    INDEX idx_commit_links_commit_sha (commit_sha),
+    CONSTRAINT fk_commit_link_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
 -- Issue snapshots table (for compaction)
 CREATE TABLE IF NOT EXISTS issue_snapshots (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
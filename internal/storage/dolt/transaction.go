@@ -139,18 +139,32 @@ func (t *doltTransaction) UpdateIssue(ctx context.Context, id string, updates ma
 	args = append(args, id)
 	// nolint:gosec // G201: setClauses contains only column names (e.g. "status = ?"), actual values passed via args
 	query := fmt.Sprintf("UPDATE issues SET %s WHERE id = ?", strings.Join(setClauses, ", "))
-	_, err := t.tx.ExecContext(ctx, query, args...)
-	return err
+	if _, err := t.tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	// Activity by the lease holder keeps their claim alive
+	if err := renewLease(ctx, t.tx, id, actor); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+	return nil
 }
 
 // CloseIssue closes an issue within the transaction
 func (t *doltTransaction) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
 	now := time.Now().UTC()
-	_, err := t.tx.ExecContext(ctx, `
+	if _, err := t.tx.ExecContext(ctx, `
 		UPDATE issues SET status = ?, closed_at = ?, updated_at = ?, close_reason = ?, closed_by_session = ?
 		WHERE id = ?
-	`, types.StatusClosed, now, now, reason, session, id)
-	return err
+	`, types.StatusClosed, now, now, reason, session, id); err != nil {
+		return err
+	}
+
+	// Closing ends any claim on the issue
+	if _, err := t.tx.ExecContext(ctx, `DELETE FROM issue_leases WHERE issue_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
 }
 
 // DeleteIssue deletes an issue within the transaction
@@ -233,11 +247,18 @@ func (t *doltTransaction) GetMetadata(ctx context.Context, key string) (string,
 
 // AddComment adds a comment within the transaction
 func (t *doltTransaction) AddComment(ctx context.Context, issueID, actor, comment string) error {
-	_, err := t.tx.ExecContext(ctx, `
+	if _, err := t.tx.ExecContext(ctx, `
 		INSERT INTO events (issue_id, event_type, actor, comment)
 		VALUES (?, ?, ?, ?)
-	`, issueID, types.EventCommented, actor, comment)
-	return err
+	`, issueID, types.EventCommented, actor, comment); err != nil {
+		return err
+	}
+
+	// Commenting counts as activity for the lease holder
+	if err := renewLease(ctx, t.tx, issueID, actor); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+	return nil
 }
 
 // Helper functions for transaction context
@@ -36,6 +36,8 @@ type Options struct {
 // New creates a storage backend based on the backend type.
 // For SQLite, path should be the full path to the .db file.
 // For Dolt, path should be the directory containing the Dolt database.
+// For Postgres, path is DatabasePath of the .beads directory; the connection
+// settings are read from its metadata.json.
 func New(ctx context.Context, backend, path string) (storage.Storage, error) {
 	return NewWithOptions(ctx, backend, path, Options{})
 }
@@ -63,7 +65,7 @@ func NewWithOptions(ctx context.Context, backend, path string, opts Options) (st
 		if backend == configfile.BackendDolt {
 			return nil, fmt.Errorf("dolt backend requires CGO (not available on this build); use sqlite backend or install from pre-built binaries")
 		}
-		return nil, fmt.Errorf("unknown storage backend: %s (supported: sqlite, dolt)", backend)
+		return nil, fmt.Errorf("unknown storage backend: %s (supported: sqlite, dolt, postgres)", backend)
 	}
 }
 
@@ -89,6 +91,8 @@ func NewFromConfigWithOptions(ctx context.Context, beadsDir string, opts Options
 		return NewWithOptions(ctx, backend, cfg.DatabasePath(beadsDir), opts)
 	case configfile.BackendDolt:
 		return NewWithOptions(ctx, backend, cfg.DatabasePath(beadsDir), opts)
+	case configfile.BackendPostgres:
+		return NewWithOptions(ctx, backend, cfg.DatabasePath(beadsDir), opts)
 	default:
 		return nil, fmt.Errorf("unknown storage backend in config: %s", backend)
 	}
@@ -0,0 +1,32 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/steveyegge/beads/internal/configfile"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/postgres"
+)
+
+func init() {
+	RegisterBackend(configfile.BackendPostgres, func(ctx context.Context, path string, opts Options) (storage.Storage, error) {
+		// Path is the nominal postgres entry inside .beads; the connection
+		// settings live in metadata.json next to it
+		beadsDir := filepath.Dir(path)
+		cfg, err := configfile.Load(beadsDir)
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+		if cfg == nil {
+			cfg = configfile.DefaultConfig()
+		}
+
+		return postgres.New(ctx, &postgres.Config{
+			DSN:      cfg.PostgresDSN(),
+			Path:     path,
+			ReadOnly: opts.ReadOnly,
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/storagetest"
+)
+
+// TestConformance runs the shared storage suite. The gaps below are what
+// keeps --no-db mode from matching SQLite; remove an entry once the
+// corresponding behavior is implemented here.
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, storagetest.Backend{
+		New: func(t *testing.T) storage.Storage {
+			store := New("")
+			t.Cleanup(func() { _ = store.Close() })
+			return store
+		},
+		Gaps: map[storagetest.Capability]string{
+			storagetest.Transactions:       "RunInTransaction is not supported in --no-db mode",
+			storagetest.Leases:             "lease methods are no-ops",
+			storagetest.CommitLinks:        "commit link methods are no-ops",
+			storagetest.EventHistory:       "only create/update/close events are recorded, oldest first",
+			storagetest.ExportHashes:       "export and JSONL file hashes are not stored",
+			storagetest.RenameIssue:        "UpdateIssueID is not supported in --no-db mode",
+			storagetest.CycleDetection:     "AddDependency does not reject cycles and DetectCycles always returns nil",
+			storagetest.EpicClosure:        "GetEpicsEligibleForClosure always returns nil",
+			storagetest.DependencyTree:     "GetDependencyTree returns direct dependencies only",
+			storagetest.TransitiveBlocking: "only direct blocks dependencies are considered",
+			storagetest.PrefixValidation:   "explicit IDs are not checked against issue_prefix",
+			storagetest.ExplicitTimestamps: "CreateIssue always stamps CreatedAt and UpdatedAt with the current time",
+			storagetest.ChildCounters:      "explicit child IDs only advance counters when loaded from JSONL",
+			storagetest.IssueFilterFields:  "SearchIssues honors only the core IssueFilter fields",
+			storagetest.WorkFilterFields:   "GetReadyWork ignores ParentID, MolType, IncludeDeferred and ephemeral issues",
+		},
+	})
+}
@@ -1427,6 +1427,46 @@ func (m *MemoryStorage) AddComment(ctx context.Context, issueID, actor, comment
 	return nil
 }
 
+// AddEvent records an event in memory; events are not persisted in --no-db mode.
+func (m *MemoryStorage) AddEvent(ctx context.Context, event *types.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.issues[event.IssueID]; !exists {
+		return fmt.Errorf("issue %s not found", event.IssueID)
+	}
+	recorded := *event
+	if recorded.CreatedAt.IsZero() {
+		recorded.CreatedAt = time.Now()
+	}
+	m.events[event.IssueID] = append(m.events[event.IssueID], &recorded)
+	return nil
+}
+
+// GetLease always returns nil: leases are acquired through RunInTransaction,
+// which --no-db mode doesn't support, so no issue is ever leased here.
+func (m *MemoryStorage) GetLease(ctx context.Context, issueID string) (*types.Lease, error) {
+	return nil, nil
+}
+
+func (m *MemoryStorage) ReleaseLease(ctx context.Context, issueID, holder string) error {
+	return nil
+}
+
+func (m *MemoryStorage) ExpireLeases(ctx context.Context, actor string) ([]string, error) {
+	return nil, nil
+}
+
+// AddCommitLinks is a no-op: commit links are a local index of git history
+// and --no-db mode has no database to keep it in.
+func (m *MemoryStorage) AddCommitLinks(ctx context.Context, links []*types.CommitLink) error {
+	return nil
+}
+
+func (m *MemoryStorage) GetCommitLinks(ctx context.Context, issueID string) ([]*types.CommitLink, error) {
+	return nil, nil
+}
+
 func (m *MemoryStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -1439,10 +1479,52 @@ func (m *MemoryStorage) GetEvents(ctx context.Context, issueID string, limit int
 	return events, nil
 }
 
+// SearchEvents returns events across issues matching the filter, oldest first.
+func (m *MemoryStorage) SearchEvents(ctx context.Context, filter types.EventFilter) ([]*types.Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	issueIDs := make(map[string]bool, len(filter.IssueIDs))
+	for _, id := range filter.IssueIDs {
+		issueIDs[id] = true
+	}
+	eventTypes := make(map[types.EventType]bool, len(filter.Types))
+	for _, t := range filter.Types {
+		eventTypes[t] = true
+	}
+
+	var events []*types.Event
+	for issueID, issueEvents := range m.events {
+		if len(issueIDs) > 0 && !issueIDs[issueID] {
+			continue
+		}
+		for _, e := range issueEvents {
+			if len(eventTypes) > 0 && !eventTypes[e.EventType] {
+				continue
+			}
+			if filter.Since != nil && e.CreatedAt.Before(*filter.Since) {
+				continue
+			}
+			if filter.Until != nil && e.CreatedAt.After(*filter.Until) {
+				continue
+			}
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	return events, nil
+}
+
 func (m *MemoryStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if _, exists := m.issues[issueID]; !exists {
+		return nil, fmt.Errorf("issue %s not found", issueID)
+	}
+
 	comment := &types.Comment{
 		ID:        int64(len(m.comments[issueID]) + 1),
 		IssueID:   issueID,
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// rebuildBlockedCache recomputes blocked_issues_cache from scratch. It runs
+// inside the caller's transaction under the write lock, so concurrent
+// writers rebuild one after another and the last one to commit sees every
+// earlier change (each statement takes a fresh READ COMMITTED snapshot).
+//
+// The blocking rules match the SQLite backend:
+//   - 'blocks': B is blocked until A is closed (any close reason)
+//   - 'conditional-blocks': B is blocked until A is closed with a failure
+//   - 'waits-for': B is blocked until the children of spawner A are closed
+//     (gate "all-children", the default) or until one of them is ("any-children")
+//   - 'parent-child': blockage propagates to children
+//
+//nolint:misspell // SQL contains both "cancelled" and "canceled" for British/US spelling
+func rebuildBlockedCache(ctx context.Context, tx dbtx) error {
+	if err := lockWrites(ctx, tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM blocked_issues_cache`); err != nil {
+		return fmt.Errorf("failed to clear blocked_issues_cache: %w", err)
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO blocked_issues_cache (issue_id)
+		WITH RECURSIVE
+		  blocked_directly AS (
+		    SELECT d.issue_id
+		    FROM dependencies d
+		    JOIN issues blocker ON d.depends_on_id = blocker.id
+		    WHERE d.type = 'blocks'
+		      AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+
+		    UNION
+
+		    SELECT d.issue_id
+		    FROM dependencies d
+		    JOIN issues blocker ON d.depends_on_id = blocker.id
+		    WHERE d.type = 'conditional-blocks'
+		      AND (
+		        blocker.status IN ('open', 'in_progress', 'blocked', 'deferred')
+		        OR (blocker.status = 'closed'
+		            AND LOWER(COALESCE(blocker.close_reason, '')) !~
+		                '(failed|rejected|wontfix|won''t fix|cancelled|canceled|abandoned|blocked|error|timeout|aborted)')
+		      )
+
+		    UNION
+
+		    SELECT d.issue_id
+		    FROM dependencies d
+		    WHERE d.type = 'waits-for'
+		      AND (
+		        (COALESCE(d.metadata->>'gate', 'all-children') = 'all-children'
+		         AND EXISTS (
+		           SELECT 1 FROM dependencies child_dep
+		           JOIN issues child ON child_dep.issue_id = child.id
+		           WHERE child_dep.type = 'parent-child'
+		             AND child_dep.depends_on_id = COALESCE(d.metadata->>'spawner_id', d.depends_on_id)
+		             AND child.status NOT IN ('closed', 'tombstone')
+		         ))
+		        OR
+		        (COALESCE(d.metadata->>'gate', 'all-children') = 'any-children'
+		         AND NOT EXISTS (
+		           SELECT 1 FROM dependencies child_dep
+		           JOIN issues child ON child_dep.issue_id = child.id
+		           WHERE child_dep.type = 'parent-child'
+		             AND child_dep.depends_on_id = COALESCE(d.metadata->>'spawner_id', d.depends_on_id)
+		             AND child.status IN ('closed', 'tombstone')
+		         ))
+		      )
+		  ),
+		  blocked_transitively AS (
+		    SELECT issue_id, 0 AS depth
+		    FROM blocked_directly
+
+		    UNION ALL
+
+		    SELECT d.issue_id, bt.depth + 1
+		    FROM blocked_transitively bt
+		    JOIN dependencies d ON d.depends_on_id = bt.issue_id
+		    WHERE d.type = 'parent-child'
+		      AND bt.depth < 50
+		  )
+		SELECT DISTINCT issue_id FROM blocked_transitively
+		ON CONFLICT (issue_id) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild blocked_issues_cache: %w", err)
+	}
+	return nil
+}
+
+// RebuildBlockedCache recomputes the blocked issues cache. Useful after
+// bulk changes made outside the store, such as a migration import.
+func (s *PostgresStore) RebuildBlockedCache(ctx context.Context) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return rebuildBlockedCache(ctx, tx)
+	})
+}
+
+// GetBlockedIssueIDs returns all issue IDs currently in the blocked cache
+func (s *PostgresStore) GetBlockedIssueIDs(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT issue_id FROM blocked_issues_cache`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocked_issues_cache: %w", err)
+	}
+	return scanStrings(rows)
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// AddCommitLinks records commits that mention issues, replacing existing
+// links for the same issue and commit.
+func (s *PostgresStore) AddCommitLinks(ctx context.Context, links []*types.CommitLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, link := range links {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO commit_links (issue_id, commit_sha, author, subject, files, keyword, committed_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (issue_id, commit_sha) DO UPDATE SET
+				author = EXCLUDED.author,
+				subject = EXCLUDED.subject,
+				files = EXCLUDED.files,
+				keyword = EXCLUDED.keyword,
+				committed_at = EXCLUDED.committed_at
+		`, link.IssueID, link.CommitSHA, link.Author, link.Subject, formatJSONStringArray(link.Files),
+			string(link.Keyword), link.CommittedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to add commit link %s -> %s: %w", link.CommitSHA, link.IssueID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCommitLinks returns the commits that mention an issue, oldest first.
+func (s *PostgresStore) GetCommitLinks(ctx context.Context, issueID string) ([]*types.CommitLink, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT issue_id, commit_sha, author, subject, files, keyword, committed_at
+		FROM commit_links WHERE issue_id = $1
+		ORDER BY committed_at, commit_sha
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit links: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var links []*types.CommitLink
+	for rows.Next() {
+		var link types.CommitLink
+		var files, keyword string
+		if err := rows.Scan(&link.IssueID, &link.CommitSHA, &link.Author, &link.Subject, &files, &keyword, &link.CommittedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan commit link: %w", err)
+		}
+		link.Files = parseJSONStringArray(files)
+		link.Keyword = types.CommitKeyword(keyword)
+		links = append(links, &link)
+	}
+	return links, rows.Err()
+}
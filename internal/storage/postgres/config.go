@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SetConfig sets a configuration value
+func (s *PostgresStore) SetConfig(ctx context.Context, key, value string) error {
+	return setKeyValue(ctx, s.db, "config", key, value)
+}
+
+// GetConfig retrieves a configuration value, or "" if it isn't set
+func (s *PostgresStore) GetConfig(ctx context.Context, key string) (string, error) {
+	return getKeyValue(ctx, s.db, "config", key)
+}
+
+// GetAllConfig retrieves all configuration values
+func (s *PostgresStore) GetAllConfig(ctx context.Context) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT key, value FROM config ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all config: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	config := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan config: %w", err)
+		}
+		config[key] = value
+	}
+	return config, rows.Err()
+}
+
+// DeleteConfig removes a configuration value
+func (s *PostgresStore) DeleteConfig(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM config WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("failed to delete config %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetMetadata sets a metadata value
+func (s *PostgresStore) SetMetadata(ctx context.Context, key, value string) error {
+	return setKeyValue(ctx, s.db, "metadata", key, value)
+}
+
+// GetMetadata retrieves a metadata value, or "" if it isn't set
+func (s *PostgresStore) GetMetadata(ctx context.Context, key string) (string, error) {
+	return getKeyValue(ctx, s.db, "metadata", key)
+}
+
+// GetCustomStatuses returns custom status values from config
+func (s *PostgresStore) GetCustomStatuses(ctx context.Context) ([]string, error) {
+	return getCustomList(ctx, s.db, "status.custom")
+}
+
+// GetCustomTypes returns custom issue type values from config
+func (s *PostgresStore) GetCustomTypes(ctx context.Context) ([]string, error) {
+	return getCustomList(ctx, s.db, "types.custom")
+}
+
+// setKeyValue upserts into the config or metadata table.
+func setKeyValue(ctx context.Context, q dbtx, table, key, value string) error {
+	// nolint:gosec // G201: table is one of two constants
+	_, err := q.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s (key, value) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value
+	`, table), key, value)
+	if err != nil {
+		return fmt.Errorf("failed to set %s %s: %w", table, key, err)
+	}
+	return nil
+}
+
+// getKeyValue reads from the config or metadata table.
+func getKeyValue(ctx context.Context, q dbtx, table, key string) (string, error) {
+	var value string
+	// nolint:gosec // G201: table is one of two constants
+	err := q.QueryRowContext(ctx, fmt.Sprintf(`SELECT value FROM %s WHERE key = $1`, table), key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s %s: %w", table, key, err)
+	}
+	return value, nil
+}
+
+// getCustomList parses a comma-separated config value, trimming whitespace
+// and dropping empty entries.
+func getCustomList(ctx context.Context, q dbtx, key string) ([]string, error) {
+	value, err := getKeyValue(ctx, q, "config", key)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,18 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/storagetest"
+)
+
+// TestConformance runs the shared storage suite against a fresh schema per
+// test. Requires BEADS_TEST_POSTGRES_URL.
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, storagetest.Backend{
+		New: func(t *testing.T) storage.Storage {
+			return setupTestStore(t)
+		},
+	})
+}
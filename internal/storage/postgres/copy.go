@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// CopyStats reports what CopyFrom transferred.
+type CopyStats struct {
+	Issues       int `json:"issues"`
+	Labels       int `json:"labels"`
+	Dependencies int `json:"dependencies"`
+	Comments     int `json:"comments"`
+	Events       int `json:"events"`
+	Config       int `json:"config"`
+	Metadata     int `json:"metadata"`
+	// Orphans counts dependency, comment and event rows skipped because
+	// their issue doesn't exist in the source.
+	Orphans int `json:"orphans,omitempty"`
+}
+
+// copiedMetadataKeys are the metadata entries CopyFrom carries over. The
+// Storage interface can't enumerate metadata, so this lists the keys that
+// identify the repo and keep JSONL sync state, so the first sync after a
+// migration doesn't see spurious changes.
+var copiedMetadataKeys = []string{
+	"bd_version",
+	"repo_id",
+	"jsonl_content_hash",
+	"jsonl_file_hash",
+	"last_import_hash",
+	"last_import_time",
+}
+
+// CopyFrom copies every issue (tombstones included), label, dependency,
+// comment, event, config value and the sync metadata from src into this
+// store in a single transaction, then rebuilds the child counters and
+// blocked cache. Issue rows are written as-is, without validation or new
+// events, and the source's dirty set is carried over, so the copy is
+// faithful to the source. The target must be empty.
+func (s *PostgresStore) CopyFrom(ctx context.Context, src storage.Storage) (*CopyStats, error) {
+	var existing int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues`).Scan(&existing); err != nil {
+		return nil, fmt.Errorf("failed to count target issues: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("target database already contains %d issues", existing)
+	}
+
+	issues, err := src.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source issues: %w", err)
+	}
+	ids := make([]string, len(issues))
+	known := make(map[string]bool, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+		known[issue.ID] = true
+	}
+
+	labels, err := src.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source labels: %w", err)
+	}
+	deps, err := src.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source dependencies: %w", err)
+	}
+	comments, err := src.GetCommentsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source comments: %w", err)
+	}
+	dirty, err := src.GetDirtyIssues(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source dirty issues: %w", err)
+	}
+	config, err := src.GetAllConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source config: %w", err)
+	}
+
+	metadata := make(map[string]string)
+	for _, key := range copiedMetadataKeys {
+		value, err := src.GetMetadata(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read source metadata %s: %w", key, err)
+		}
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+
+	stats := &CopyStats{}
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockWrites(ctx, tx); err != nil {
+			return err
+		}
+
+		for key, value := range config {
+			if err := setKeyValue(ctx, tx, "config", key, value); err != nil {
+				return err
+			}
+			stats.Config++
+		}
+		for key, value := range metadata {
+			if err := setKeyValue(ctx, tx, "metadata", key, value); err != nil {
+				return err
+			}
+			stats.Metadata++
+		}
+
+		for _, issue := range issues {
+			if err := insertIssue(ctx, tx, issue); err != nil {
+				return fmt.Errorf("failed to copy issue %s: %w", issue.ID, err)
+			}
+			for _, label := range labels[issue.ID] {
+				if _, err := tx.ExecContext(ctx, `
+					INSERT INTO labels (issue_id, label) VALUES ($1, $2) ON CONFLICT DO NOTHING
+				`, issue.ID, label); err != nil {
+					return fmt.Errorf("failed to copy label %q on %s: %w", label, issue.ID, err)
+				}
+				stats.Labels++
+			}
+			stats.Issues++
+		}
+
+		// Children may have been created with explicit IDs, so derive the
+		// counters from the IDs themselves
+		for _, id := range ids {
+			if parentID, childNum, ok := parseHierarchicalID(id); ok && known[parentID] {
+				if err := ensureChildCounter(ctx, tx, parentID, childNum); err != nil {
+					return err
+				}
+			}
+		}
+
+		for issueID, records := range deps {
+			if !known[issueID] {
+				stats.Orphans += len(records)
+				continue
+			}
+			for _, dep := range records {
+				depMetadata := dep.Metadata
+				if depMetadata == "" {
+					depMetadata = "{}"
+				}
+				createdAt := dep.CreatedAt
+				if createdAt.IsZero() {
+					createdAt = time.Now().UTC()
+				}
+				if _, err := tx.ExecContext(ctx, `
+					INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by, metadata, thread_id)
+					VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7)
+					ON CONFLICT DO NOTHING
+				`, dep.IssueID, dep.DependsOnID, string(dep.Type), createdAt, dep.CreatedBy, depMetadata, dep.ThreadID); err != nil {
+					return fmt.Errorf("failed to copy dependency %s -> %s: %w", dep.IssueID, dep.DependsOnID, err)
+				}
+				stats.Dependencies++
+			}
+		}
+
+		for issueID, list := range comments {
+			if !known[issueID] {
+				stats.Orphans += len(list)
+				continue
+			}
+			for _, c := range list {
+				if _, err := insertComment(ctx, tx, issueID, c.Author, c.Text, c.CreatedAt); err != nil {
+					return fmt.Errorf("failed to copy comment on %s: %w", issueID, err)
+				}
+				stats.Comments++
+			}
+		}
+
+		for _, id := range ids {
+			events, err := src.GetEvents(ctx, id, 0)
+			if err != nil {
+				return fmt.Errorf("failed to read events for %s: %w", id, err)
+			}
+			// GetEvents is newest first; insert oldest first so ids keep order
+			for i := len(events) - 1; i >= 0; i-- {
+				e := events[i]
+				if _, err := tx.ExecContext(ctx, `
+					INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment, created_at)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)
+				`, id, string(e.EventType), e.Actor, nullStringPtr(e.OldValue), nullStringPtr(e.NewValue),
+					nullStringPtr(e.Comment), e.CreatedAt); err != nil {
+					return fmt.Errorf("failed to copy event for %s: %w", id, err)
+				}
+				stats.Events++
+			}
+		}
+
+		// insertComment marks issues dirty; reset to the source's dirty set
+		if _, err := tx.ExecContext(ctx, `DELETE FROM dirty_issues`); err != nil {
+			return fmt.Errorf("failed to reset dirty issues: %w", err)
+		}
+		for _, id := range dirty {
+			if known[id] {
+				if err := markDirty(ctx, tx, id); err != nil {
+					return fmt.Errorf("failed to mark %s dirty: %w", id, err)
+				}
+			}
+		}
+
+		return rebuildBlockedCache(ctx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,527 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// maxDependencyDepth is the maximum depth for recursive dependency traversal
+// to prevent infinite loops and limit query complexity
+const maxDependencyDepth = 100
+
+// AddDependency adds a dependency between issues with cycle prevention
+func (s *PostgresStore) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return addDependency(ctx, tx, dep, actor)
+	})
+}
+
+// RemoveDependency removes a dependency
+func (s *PostgresStore) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return removeDependency(ctx, tx, issueID, dependsOnID, actor)
+	})
+}
+
+func addDependency(ctx context.Context, tx dbtx, dep *types.Dependency, actor string) error {
+	if !dep.Type.IsValid() {
+		return fmt.Errorf("invalid dependency type: %q (must be non-empty string, max 50 chars)", dep.Type)
+	}
+
+	source, err := getIssue(ctx, tx, dep.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to check issue %s: %w", dep.IssueID, err)
+	}
+	if source == nil {
+		return fmt.Errorf("issue %s not found", dep.IssueID)
+	}
+
+	// External refs (external:<project>:<capability>) don't need target validation
+	isExternalRef := strings.HasPrefix(dep.DependsOnID, "external:")
+	if !isExternalRef {
+		target, err := getIssue(ctx, tx, dep.DependsOnID)
+		if err != nil {
+			return fmt.Errorf("failed to check dependency %s: %w", dep.DependsOnID, err)
+		}
+		if target == nil {
+			return fmt.Errorf("dependency target %s not found", dep.DependsOnID)
+		}
+		if dep.IssueID == dep.DependsOnID {
+			return fmt.Errorf("issue cannot depend on itself")
+		}
+		// Child depends on parent, never the other way round
+		if dep.Type == types.DepParentChild && source.IssueType == types.TypeEpic && target.IssueType != types.TypeEpic {
+			return fmt.Errorf("invalid parent-child dependency: parent (%s) cannot depend on child (%s). Use: bd dep add %s %s --type parent-child",
+				dep.IssueID, dep.DependsOnID, dep.DependsOnID, dep.IssueID)
+		}
+	}
+
+	if dep.CreatedAt.IsZero() {
+		dep.CreatedAt = time.Now().UTC()
+	}
+	if dep.CreatedBy == "" {
+		dep.CreatedBy = actor
+	}
+
+	// relates-to links are bidirectional by design, so they are exempt
+	if dep.Type != types.DepRelatesTo {
+		var cycleExists bool
+		err := tx.QueryRowContext(ctx, `
+			WITH RECURSIVE paths AS (
+				SELECT issue_id, depends_on_id, 1 AS depth
+				FROM dependencies
+				WHERE issue_id = $1
+				UNION ALL
+				SELECT d.issue_id, d.depends_on_id, p.depth + 1
+				FROM dependencies d
+				JOIN paths p ON d.issue_id = p.depends_on_id
+				WHERE p.depth < $2
+			)
+			SELECT EXISTS (SELECT 1 FROM paths WHERE depends_on_id = $3)
+		`, dep.DependsOnID, maxDependencyDepth, dep.IssueID).Scan(&cycleExists)
+		if err != nil {
+			return fmt.Errorf("failed to check for cycles: %w", err)
+		}
+		if cycleExists {
+			return fmt.Errorf("cannot add dependency: would create a cycle (%s → %s → ... → %s)",
+				dep.IssueID, dep.DependsOnID, dep.IssueID)
+		}
+	}
+
+	metadata := dep.Metadata
+	if metadata == "" {
+		metadata = "{}"
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by, metadata, thread_id)
+		VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7)
+	`, dep.IssueID, dep.DependsOnID, string(dep.Type), dep.CreatedAt, dep.CreatedBy, metadata, dep.ThreadID); err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	if err := recordEvent(ctx, tx, dep.IssueID, types.EventDependencyAdded, actor, "", "",
+		fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID)); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	// For external refs, only mark the source issue (target doesn't exist locally)
+	if err := markDirty(ctx, tx, dep.IssueID); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	if !isExternalRef {
+		if err := markDirty(ctx, tx, dep.DependsOnID); err != nil {
+			return fmt.Errorf("failed to mark issue dirty: %w", err)
+		}
+	}
+
+	if dep.Type.AffectsReadyWork() {
+		if err := rebuildBlockedCache(ctx, tx); err != nil {
+			return fmt.Errorf("failed to rebuild blocked cache: %w", err)
+		}
+	}
+	return nil
+}
+
+func removeDependency(ctx context.Context, tx dbtx, issueID, dependsOnID, actor string) error {
+	var depType types.DependencyType
+	err := tx.QueryRowContext(ctx, `
+		DELETE FROM dependencies WHERE issue_id = $1 AND depends_on_id = $2
+		RETURNING type
+	`, issueID, dependsOnID).Scan(&depType)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("dependency from %s to %s does not exist", issueID, dependsOnID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+
+	if err := recordEvent(ctx, tx, issueID, types.EventDependencyRemoved, actor, "", "",
+		fmt.Sprintf("Removed dependency on %s", dependsOnID)); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	if err := markDirty(ctx, tx, issueID); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	if !strings.HasPrefix(dependsOnID, "external:") {
+		if err := markDirty(ctx, tx, dependsOnID); err != nil {
+			return fmt.Errorf("failed to mark issue dirty: %w", err)
+		}
+	}
+
+	if depType.AffectsReadyWork() {
+		if err := rebuildBlockedCache(ctx, tx); err != nil {
+			return fmt.Errorf("failed to rebuild blocked cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetDependenciesWithMetadata returns issues that this issue depends on, including dependency type
+func (s *PostgresStore) GetDependenciesWithMetadata(ctx context.Context, issueID string) ([]*types.IssueWithDependencyMetadata, error) {
+	// nolint:gosec // G201: column list is a constant
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s, d.type
+		FROM issues i
+		JOIN dependencies d ON i.id = d.depends_on_id
+		WHERE d.issue_id = $1
+		ORDER BY i.priority ASC
+	`, issueColumnsWithAlias("i")), issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies with metadata: %w", err)
+	}
+	return s.scanIssuesWithDependencyType(ctx, rows)
+}
+
+// GetDependentsWithMetadata returns issues that depend on this issue, including dependency type
+func (s *PostgresStore) GetDependentsWithMetadata(ctx context.Context, issueID string) ([]*types.IssueWithDependencyMetadata, error) {
+	// nolint:gosec // G201: column list is a constant
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s, d.type
+		FROM issues i
+		JOIN dependencies d ON i.id = d.issue_id
+		WHERE d.depends_on_id = $1
+		ORDER BY i.priority ASC
+	`, issueColumnsWithAlias("i")), issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependents with metadata: %w", err)
+	}
+	return s.scanIssuesWithDependencyType(ctx, rows)
+}
+
+func (s *PostgresStore) scanIssuesWithDependencyType(ctx context.Context, rows *sql.Rows) ([]*types.IssueWithDependencyMetadata, error) {
+	var results []*types.IssueWithDependencyMetadata
+	for rows.Next() {
+		var row issueRow
+		var depType string
+		if err := rows.Scan(append(row.dests(), &depType)...); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan issue: %w", err)
+		}
+		results = append(results, &types.IssueWithDependencyMetadata{
+			Issue:          *row.issue(),
+			DependencyType: types.DependencyType(depType),
+		})
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	labels, err := getLabelsForIssues(ctx, s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		r.Labels = labels[r.ID]
+	}
+	return results, nil
+}
+
+// GetDependencies returns issues that this issue depends on
+func (s *PostgresStore) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	withMeta, err := s.GetDependenciesWithMetadata(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]*types.Issue, len(withMeta))
+	for i, iwm := range withMeta {
+		issues[i] = &iwm.Issue
+	}
+	return issues, nil
+}
+
+// GetDependents returns issues that depend on this issue
+func (s *PostgresStore) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	withMeta, err := s.GetDependentsWithMetadata(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]*types.Issue, len(withMeta))
+	for i, iwm := range withMeta {
+		issues[i] = &iwm.Issue
+	}
+	return issues, nil
+}
+
+// GetDependencyCounts returns dependency and dependent counts for multiple issues in a single query
+func (s *PostgresStore) GetDependencyCounts(ctx context.Context, issueIDs []string) (map[string]*types.DependencyCounts, error) {
+	result := make(map[string]*types.DependencyCounts)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id,
+		       (SELECT COUNT(*) FROM dependencies WHERE issue_id = ids.id),
+		       (SELECT COUNT(*) FROM dependencies WHERE depends_on_id = ids.id)
+		FROM unnest($1::text[]) AS ids(id)
+	`, issueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependency counts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID string
+		var counts types.DependencyCounts
+		if err := rows.Scan(&issueID, &counts.DependencyCount, &counts.DependentCount); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency counts: %w", err)
+		}
+		result[issueID] = &counts
+	}
+	return result, rows.Err()
+}
+
+// GetDependencyRecords returns raw dependency records for an issue
+func (s *PostgresStore) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+dependencyColumns+`
+		FROM dependencies
+		WHERE issue_id = $1
+		ORDER BY created_at ASC
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependency records: %w", err)
+	}
+	return scanDependencies(rows)
+}
+
+// GetAllDependencyRecords returns all dependency records grouped by issue ID
+func (s *PostgresStore) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+dependencyColumns+`
+		FROM dependencies
+		ORDER BY issue_id, created_at ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all dependency records: %w", err)
+	}
+	deps, err := scanDependencies(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	depsMap := make(map[string][]*types.Dependency)
+	for _, dep := range deps {
+		depsMap[dep.IssueID] = append(depsMap[dep.IssueID], dep)
+	}
+	return depsMap, nil
+}
+
+// dependencyColumns are the columns read by scanDependencies.
+const dependencyColumns = `issue_id, depends_on_id, type, created_at, created_by, metadata::text, COALESCE(thread_id, '')`
+
+func scanDependencies(rows *sql.Rows) ([]*types.Dependency, error) {
+	defer func() { _ = rows.Close() }()
+	var deps []*types.Dependency
+	for rows.Next() {
+		var dep types.Dependency
+		var depType string
+		if err := rows.Scan(&dep.IssueID, &dep.DependsOnID, &depType, &dep.CreatedAt,
+			&dep.CreatedBy, &dep.Metadata, &dep.ThreadID); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		dep.Type = types.DependencyType(depType)
+		deps = append(deps, &dep)
+	}
+	return deps, rows.Err()
+}
+
+// GetDependencyTree returns the full dependency tree with optional deduplication.
+// When showAllPaths is false, nodes reachable via several paths appear once,
+// at their shallowest depth. When reverse is true, the tree follows
+// dependents instead of dependencies.
+func (s *PostgresStore) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error) {
+	if maxDepth <= 0 {
+		maxDepth = 50
+	}
+
+	// Normal mode walks up to what this issue depends on; reverse mode walks
+	// down to its dependents
+	join := `JOIN dependencies d ON i.id = d.depends_on_id
+				JOIN tree t ON d.issue_id = t.id`
+	if reverse {
+		join = `JOIN dependencies d ON i.id = d.issue_id
+				JOIN tree t ON d.depends_on_id = t.id`
+	}
+
+	// nolint:gosec // G201: join is one of two constants
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		WITH RECURSIVE tree AS (
+			SELECT i.id, 0 AS depth, ARRAY[i.id] AS path, i.id AS parent_id
+			FROM issues i
+			WHERE i.id = $1
+			UNION ALL
+			SELECT i.id, t.depth + 1, t.path || i.id, t.id
+			FROM issues i
+			%s
+			WHERE t.depth < $2
+			  AND NOT i.id = ANY(t.path)
+		)
+		SELECT i.id, i.title, i.status, i.priority, i.description, i.design,
+		       i.acceptance_criteria, i.notes, i.issue_type, i.assignee,
+		       i.estimated_minutes, i.created_at, i.updated_at, i.closed_at,
+		       i.external_ref, t.depth, t.parent_id
+		FROM tree t
+		JOIN issues i ON i.id = t.id
+		ORDER BY t.depth, i.priority, i.id
+	`, join), issueID, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependency tree: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	seen := make(map[string]bool)
+	var nodes []*types.TreeNode
+	for rows.Next() {
+		var node types.TreeNode
+		var status, issueType string
+		var closedAt sql.NullTime
+		var estimatedMinutes sql.NullInt64
+		var assignee, externalRef sql.NullString
+
+		if err := rows.Scan(
+			&node.ID, &node.Title, &status, &node.Priority,
+			&node.Description, &node.Design, &node.AcceptanceCriteria,
+			&node.Notes, &issueType, &assignee, &estimatedMinutes,
+			&node.CreatedAt, &node.UpdatedAt, &closedAt, &externalRef,
+			&node.Depth, &node.ParentID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan tree node: %w", err)
+		}
+		node.Status = types.Status(status)
+		node.IssueType = types.IssueType(issueType)
+		node.Assignee = assignee.String
+		if closedAt.Valid {
+			node.ClosedAt = &closedAt.Time
+		}
+		if estimatedMinutes.Valid {
+			mins := int(estimatedMinutes.Int64)
+			node.EstimatedMinutes = &mins
+		}
+		if externalRef.Valid {
+			node.ExternalRef = &externalRef.String
+		}
+		node.Truncated = node.Depth == maxDepth
+
+		// Rows arrive shallowest first, so the first occurrence wins
+		if !showAllPaths {
+			if seen[node.ID] {
+				continue
+			}
+			seen[node.ID] = true
+		}
+		nodes = append(nodes, &node)
+	}
+	return nodes, rows.Err()
+}
+
+// DetectCycles finds circular dependencies and returns the actual cycle paths.
+// relates-to links are excluded because they are intentionally bidirectional.
+func (s *PostgresStore) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT issue_id, depends_on_id FROM dependencies WHERE type != 'relates-to'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+	deps := make(map[string][]string)
+	for rows.Next() {
+		var from, to string
+		if err := rows.Scan(&from, &to); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		deps[from] = append(deps[from], to)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// DFS with a shared visited set - O(V+E)
+	visited := make(map[string]bool)
+	onStack := make(map[string]bool)
+	var allCycles [][]string
+	var dfs func(node string, path []string)
+	dfs = func(node string, path []string) {
+		visited[node] = true
+		onStack[node] = true
+		path = append(path, node)
+		for _, next := range deps[node] {
+			if !visited[next] {
+				dfs(next, path)
+			} else if onStack[next] {
+				for i, n := range path {
+					if n == next {
+						cycle := make([]string, len(path)-i)
+						copy(cycle, path[i:])
+						allCycles = append(allCycles, cycle)
+						break
+					}
+				}
+			}
+		}
+		onStack[node] = false
+	}
+	for node := range deps {
+		if !visited[node] {
+			dfs(node, nil)
+		}
+	}
+
+	// The same cycle can be found from different entry points
+	seen := make(map[string]bool)
+	var cycles [][]*types.Issue
+	for _, cycle := range allCycles {
+		normalized := normalizeCycle(cycle)
+		key := strings.Join(normalized, "→")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var cycleIssues []*types.Issue
+		for _, id := range normalized {
+			issue, err := s.GetIssue(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get issue %s: %w", id, err)
+			}
+			if issue != nil {
+				cycleIssues = append(cycleIssues, issue)
+			}
+		}
+		if len(cycleIssues) > 0 {
+			cycles = append(cycles, cycleIssues)
+		}
+	}
+	return cycles, nil
+}
+
+// normalizeCycle rotates a cycle to start with the lexicographically smallest ID.
+func normalizeCycle(cycle []string) []string {
+	if len(cycle) == 0 {
+		return cycle
+	}
+	minIdx := 0
+	for i, id := range cycle {
+		if id < cycle[minIdx] {
+			minIdx = i
+		}
+	}
+	result := make([]string, len(cycle))
+	for i := range cycle {
+		result[i] = cycle[(minIdx+i)%len(cycle)]
+	}
+	return result
+}
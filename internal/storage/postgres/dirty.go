@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetDirtyIssues returns IDs of issues that have been modified since last export
+func (s *PostgresStore) GetDirtyIssues(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT issue_id FROM dirty_issues ORDER BY marked_at ASC, issue_id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dirty issues: %w", err)
+	}
+	ids, err := scanStrings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan issue id: %w", err)
+	}
+	return ids, nil
+}
+
+// GetDirtyIssueHash returns the content hash of a dirty issue, or "" if the
+// issue isn't dirty
+func (s *PostgresStore) GetDirtyIssueHash(ctx context.Context, issueID string) (string, error) {
+	var hash sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT i.content_hash FROM issues i
+		JOIN dirty_issues d ON i.id = d.issue_id
+		WHERE d.issue_id = $1
+	`, issueID).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get dirty issue hash for %s: %w", issueID, err)
+	}
+	return hash.String, nil
+}
+
+// ClearDirtyIssuesByID removes specific issues from the dirty list
+func (s *PostgresStore) ClearDirtyIssuesByID(ctx context.Context, issueIDs []string) error {
+	if len(issueIDs) == 0 {
+		return nil
+	}
+	_, err := s.db.ExecContext(ctx, `DELETE FROM dirty_issues WHERE issue_id = ANY($1::text[])`, issueIDs)
+	if err != nil {
+		return fmt.Errorf("failed to clear dirty issues: %w", err)
+	}
+	return nil
+}
+
+// GetExportHash returns the last export hash for an issue, or "" if it has
+// never been exported
+func (s *PostgresStore) GetExportHash(ctx context.Context, issueID string) (string, error) {
+	var hash string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT content_hash FROM export_hashes WHERE issue_id = $1
+	`, issueID).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get export hash: %w", err)
+	}
+	return hash, nil
+}
+
+// SetExportHash stores the export hash for an issue
+func (s *PostgresStore) SetExportHash(ctx context.Context, issueID, contentHash string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO export_hashes (issue_id, content_hash, exported_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (issue_id) DO UPDATE SET
+			content_hash = EXCLUDED.content_hash,
+			exported_at = EXCLUDED.exported_at
+	`, issueID, contentHash, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to set export hash: %w", err)
+	}
+	return nil
+}
+
+// ClearAllExportHashes removes all export hashes (for full re-export)
+func (s *PostgresStore) ClearAllExportHashes(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM export_hashes`); err != nil {
+		return fmt.Errorf("failed to clear export hashes: %w", err)
+	}
+	return nil
+}
+
+// GetJSONLFileHash returns the stored JSONL file hash
+func (s *PostgresStore) GetJSONLFileHash(ctx context.Context) (string, error) {
+	return s.GetMetadata(ctx, "jsonl_file_hash")
+}
+
+// SetJSONLFileHash stores the JSONL file hash
+func (s *PostgresStore) SetJSONLFileHash(ctx context.Context, fileHash string) error {
+	return s.SetMetadata(ctx, "jsonl_file_hash", fileHash)
+}
@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// AddComment adds a comment event to an issue
+func (s *PostgresStore) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return addComment(ctx, tx, issueID, actor, comment)
+	})
+}
+
+func addComment(ctx context.Context, tx dbtx, issueID, actor, comment string) error {
+	// Update issue updated_at timestamp first to verify issue exists
+	res, err := tx.ExecContext(ctx, `UPDATE issues SET updated_at = $1 WHERE id = $2`, time.Now().UTC(), issueID)
+	if err != nil {
+		return fmt.Errorf("failed to update timestamp: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+
+	if err := recordEvent(ctx, tx, issueID, types.EventCommented, actor, "", "", comment); err != nil {
+		return fmt.Errorf("failed to add comment: %w", err)
+	}
+	if err := markDirty(ctx, tx, issueID); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	// Commenting counts as activity for the lease holder
+	if err := renewLease(ctx, tx, issueID, actor); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+	return nil
+}
+
+// AddEvent records an audit event for an issue. Unlike AddComment it leaves
+// the issue itself (and its updated_at) alone.
+func (s *PostgresStore) AddEvent(ctx context.Context, event *types.Event) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, event.IssueID, string(event.EventType), event.Actor, nullStringPtr(event.OldValue), nullStringPtr(event.NewValue), nullStringPtr(event.Comment))
+	if err != nil {
+		return fmt.Errorf("failed to add event: %w", err)
+	}
+	return nil
+}
+
+// GetEvents returns the event history for an issue, newest first
+func (s *PostgresStore) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
+	args := queryArgs{issueID}
+	limitSQL := ""
+	if limit > 0 {
+		limitSQL = "LIMIT " + args.add(limit)
+	}
+
+	// nolint:gosec // G201: limitSQL contains only a placeholder
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
+		FROM events
+		WHERE issue_id = $1
+		ORDER BY created_at DESC, id DESC
+		%s
+	`, limitSQL), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	return scanEvents(rows)
+}
+
+// SearchEvents returns events across issues matching the filter, oldest first
+func (s *PostgresStore) SearchEvents(ctx context.Context, filter types.EventFilter) ([]*types.Event, error) {
+	var args queryArgs
+	where := []string{"TRUE"}
+	if len(filter.IssueIDs) > 0 {
+		where = append(where, "issue_id IN ("+args.list(stringArgs(filter.IssueIDs)...)+")")
+	}
+	if len(filter.Types) > 0 {
+		eventTypes := make([]interface{}, len(filter.Types))
+		for i, t := range filter.Types {
+			eventTypes[i] = string(t)
+		}
+		where = append(where, "event_type IN ("+args.list(eventTypes...)+")")
+	}
+	if filter.Since != nil {
+		where = append(where, "created_at >= "+args.add(filter.Since.UTC()))
+	}
+	if filter.Until != nil {
+		where = append(where, "created_at <= "+args.add(filter.Until.UTC()))
+	}
+
+	// nolint:gosec // G201: where contains only placeholders
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
+		FROM events
+		WHERE %s
+		ORDER BY created_at ASC, id ASC
+	`, strings.Join(where, " AND ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	return scanEvents(rows)
+}
+
+// scanEvents reads event rows selected in the standard column order.
+func scanEvents(rows *sql.Rows) ([]*types.Event, error) {
+	var events []*types.Event
+	for rows.Next() {
+		var event types.Event
+		var oldValue, newValue, comment sql.NullString
+		if err := rows.Scan(
+			&event.ID, &event.IssueID, &event.EventType, &event.Actor,
+			&oldValue, &newValue, &comment, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		if oldValue.Valid {
+			event.OldValue = &oldValue.String
+		}
+		if newValue.Valid {
+			event.NewValue = &newValue.String
+		}
+		if comment.Valid {
+			event.Comment = &comment.String
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+// AddIssueComment adds a comment to an issue
+func (s *PostgresStore) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	var comment *types.Comment
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		comment, err = insertComment(ctx, tx, issueID, author, text, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		// Commenting counts as activity for the lease holder
+		if err := renewLease(ctx, tx, issueID, author); err != nil {
+			return fmt.Errorf("failed to renew lease: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// insertComment inserts a comment with an explicit timestamp and marks the
+// issue dirty. Migration copies pass the original created_at through.
+func insertComment(ctx context.Context, tx dbtx, issueID, author, text string, createdAt time.Time) (*types.Comment, error) {
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM issues WHERE id = $1)`, issueID,
+	).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check issue existence: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("issue %s not found", issueID)
+	}
+
+	comment := &types.Comment{}
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO comments (issue_id, author, text, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, issue_id, author, text, created_at
+	`, issueID, author, text, createdAt).Scan(&comment.ID, &comment.IssueID, &comment.Author, &comment.Text, &comment.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert comment: %w", err)
+	}
+
+	// Mark issue as dirty for JSONL export
+	if err := markDirty(ctx, tx, issueID); err != nil {
+		return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	return comment, nil
+}
+
+// GetIssueComments retrieves all comments for an issue
+func (s *PostgresStore) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	comments, err := s.GetCommentsForIssues(ctx, []string{issueID})
+	if err != nil {
+		return nil, err
+	}
+	return comments[issueID], nil
+}
+
+// GetCommentsForIssues fetches comments for multiple issues in a single query
+func (s *PostgresStore) GetCommentsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Comment, error) {
+	result := make(map[string][]*types.Comment)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, issue_id, author, text, created_at
+		FROM comments
+		WHERE issue_id = ANY($1::text[])
+		ORDER BY issue_id, created_at ASC, id ASC
+	`, issueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		comment := &types.Comment{}
+		if err := rows.Scan(&comment.ID, &comment.IssueID, &comment.Author, &comment.Text, &comment.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		result[comment.IssueID] = append(result[comment.IssueID], comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/idgen"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// GetNextChildID generates the next hierarchical child ID for a given parent
+// (e.g., bd-a3f8e9.1). The counter is bumped atomically, so concurrent
+// callers on any connection get distinct IDs.
+func (s *PostgresStore) GetNextChildID(ctx context.Context, parentID string) (string, error) {
+	var exists bool
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM issues WHERE id = $1)`, parentID,
+	).Scan(&exists); err != nil {
+		return "", fmt.Errorf("failed to check parent existence: %w", err)
+	}
+	if !exists {
+		return "", fmt.Errorf("parent issue %s does not exist", parentID)
+	}
+
+	if err := types.CheckHierarchyDepth(parentID, config.GetInt("hierarchy.max-depth")); err != nil {
+		return "", err
+	}
+
+	var nextChild int
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO child_counters (parent_id, last_child)
+		VALUES ($1, 1)
+		ON CONFLICT (parent_id) DO UPDATE SET last_child = child_counters.last_child + 1
+		RETURNING last_child
+	`, parentID).Scan(&nextChild)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate next child number for parent %s: %w", parentID, err)
+	}
+	return fmt.Sprintf("%s.%d", parentID, nextChild), nil
+}
+
+// ensureChildCounter raises the child counter of parentID to at least
+// childNum, so explicit child IDs (--id, import) don't collide with
+// generated ones later.
+func ensureChildCounter(ctx context.Context, tx dbtx, parentID string, childNum int) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO child_counters (parent_id, last_child)
+		VALUES ($1, $2)
+		ON CONFLICT (parent_id) DO UPDATE SET
+			last_child = GREATEST(child_counters.last_child, EXCLUDED.last_child)
+	`, parentID, childNum)
+	if err != nil {
+		return fmt.Errorf("failed to update child counter for parent %s: %w", parentID, err)
+	}
+	return nil
+}
+
+// parseHierarchicalID splits a child ID like bd-a3f8e9.2 into its parent
+// and child number. IDs whose last dot isn't followed by digits only are
+// not hierarchical (prefixes may contain dots).
+func parseHierarchicalID(id string) (parentID string, childNum int, ok bool) {
+	lastDot := strings.LastIndex(id, ".")
+	if lastDot == -1 || lastDot == len(id)-1 {
+		return "", 0, false
+	}
+	suffix := id[lastDot+1:]
+	for _, c := range suffix {
+		if c < '0' || c > '9' {
+			return "", 0, false
+		}
+	}
+	num, err := strconv.Atoi(suffix)
+	if err != nil {
+		return "", 0, false
+	}
+	return id[:lastDot], num, true
+}
+
+// generateIssueID generates a hash-based ID, using the same adaptive length
+// (3-8 base36 chars) and nonce fallback as the SQLite backend. The caller
+// must hold the write lock.
+func generateIssueID(ctx context.Context, tx dbtx, prefix string, issue *types.Issue, actor string) (string, error) {
+	baseLength, err := adaptiveIDLength(ctx, tx, prefix)
+	if err != nil {
+		baseLength = 6
+	}
+
+	const maxLength = 8
+	if baseLength > maxLength {
+		baseLength = maxLength
+	}
+
+	for length := baseLength; length <= maxLength; length++ {
+		for nonce := 0; nonce < 10; nonce++ {
+			candidate := idgen.GenerateHashID(prefix, issue.Title, issue.Description, actor, issue.CreatedAt, length, nonce)
+
+			var exists bool
+			if err := tx.QueryRowContext(ctx,
+				`SELECT EXISTS (SELECT 1 FROM issues WHERE id = $1)`, candidate,
+			).Scan(&exists); err != nil {
+				return "", fmt.Errorf("failed to check for ID collision: %w", err)
+			}
+			if !exists {
+				return candidate, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("failed to generate unique ID after trying lengths %d-%d with 10 nonces each", baseLength, maxLength)
+}
+
+// adaptiveIDLength picks the shortest hash length whose birthday-paradox
+// collision probability stays under max_collision_prob for the number of
+// top-level issues with this prefix.
+func adaptiveIDLength(ctx context.Context, tx dbtx, prefix string) (int, error) {
+	var numIssues int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM issues
+		WHERE id LIKE $1::text || '-%'
+		  AND strpos(substr(id, length($1::text) + 2), '.') = 0
+	`, prefix).Scan(&numIssues); err != nil {
+		return 0, err
+	}
+
+	maxProb, minLength, maxLength := 0.25, 3, 8
+	if v, err := getConfig(ctx, tx, "max_collision_prob"); err == nil && v != "" {
+		if p, err := strconv.ParseFloat(v, 64); err == nil {
+			maxProb = p
+		}
+	}
+	if v, err := getConfig(ctx, tx, "min_hash_length"); err == nil && v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			minLength = n
+		}
+	}
+	if v, err := getConfig(ctx, tx, "max_hash_length"); err == nil && v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			maxLength = n
+		}
+	}
+
+	for length := minLength; length <= maxLength; length++ {
+		total := math.Pow(36, float64(length))
+		prob := 1.0 - math.Exp(-float64(numIssues*numIssues)/(2.0*total))
+		if prob <= maxProb {
+			return length, nil
+		}
+	}
+	return maxLength, nil
+}
+
+// getConfig reads a config value, returning "" if it isn't set.
+func getConfig(ctx context.Context, q dbtx, key string) (string, error) {
+	var value string
+	err := q.QueryRowContext(ctx, `SELECT value FROM config WHERE key = $1`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
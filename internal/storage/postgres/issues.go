@@ -0,0 +1,957 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// CreateIssue creates a new issue
+func (s *PostgresStore) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	customStatuses, customTypes, err := s.customStatusesAndTypes(ctx)
+	if err != nil {
+		return err
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return createIssue(ctx, tx, issue, actor, customStatuses, customTypes)
+	})
+}
+
+// CreateIssues creates multiple issues in a single transaction
+func (s *PostgresStore) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	customStatuses, customTypes, err := s.customStatusesAndTypes(ctx)
+	if err != nil {
+		return err
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		for _, issue := range issues {
+			if err := createIssue(ctx, tx, issue, actor, customStatuses, customTypes); err != nil {
+				if issue.ID != "" {
+					return fmt.Errorf("failed to create issue %s: %w", issue.ID, err)
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetIssue retrieves an issue by ID, or nil if it doesn't exist
+func (s *PostgresStore) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	return getIssue(ctx, s.db, id)
+}
+
+// GetIssueByExternalRef retrieves an issue by external reference
+func (s *PostgresStore) GetIssueByExternalRef(ctx context.Context, externalRef string) (*types.Issue, error) {
+	var id string
+	err := s.db.QueryRowContext(ctx, `SELECT id FROM issues WHERE external_ref = $1`, externalRef).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue by external_ref: %w", err)
+	}
+	return s.GetIssue(ctx, id)
+}
+
+// UpdateIssue updates fields on an issue
+func (s *PostgresStore) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	customStatuses, err := s.GetCustomStatuses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get custom statuses: %w", err)
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return updateIssue(ctx, tx, id, updates, actor, customStatuses)
+	})
+}
+
+// CloseIssue closes an issue with a reason.
+// The session parameter records which agent session closed the issue (can be empty).
+func (s *PostgresStore) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return closeIssue(ctx, tx, id, reason, actor, session)
+	})
+}
+
+// DeleteIssue permanently removes an issue
+func (s *PostgresStore) DeleteIssue(ctx context.Context, id string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return deleteIssue(ctx, tx, id)
+	})
+}
+
+func (s *PostgresStore) customStatusesAndTypes(ctx context.Context) ([]string, []string, error) {
+	customStatuses, err := s.GetCustomStatuses(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get custom statuses: %w", err)
+	}
+	customTypes, err := s.GetCustomTypes(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get custom types: %w", err)
+	}
+	return customStatuses, customTypes, nil
+}
+
+// =============================================================================
+// Shared implementations (store and transaction)
+// =============================================================================
+
+func createIssue(ctx context.Context, tx dbtx, issue *types.Issue, actor string, customStatuses, customTypes []string) error {
+	// Set timestamps first so defensive fixes can use them
+	now := time.Now().UTC()
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = now
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = now
+	}
+
+	// Defensive fix for closed_at invariant: closed issues must have closed_at
+	if issue.Status == types.StatusClosed && issue.ClosedAt == nil {
+		maxTime := issue.CreatedAt
+		if issue.UpdatedAt.After(maxTime) {
+			maxTime = issue.UpdatedAt
+		}
+		closedAt := maxTime.Add(time.Second)
+		issue.ClosedAt = &closedAt
+	}
+
+	// Defensive fix for deleted_at invariant: tombstones must have deleted_at
+	if issue.Status == types.StatusTombstone && issue.DeletedAt == nil {
+		maxTime := issue.CreatedAt
+		if issue.UpdatedAt.After(maxTime) {
+			maxTime = issue.UpdatedAt
+		}
+		deletedAt := maxTime.Add(time.Second)
+		issue.DeletedAt = &deletedAt
+	}
+
+	if err := issue.ValidateWithCustom(customStatuses, customTypes); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	if issue.ContentHash == "" {
+		issue.ContentHash = issue.ComputeContentHash()
+	}
+
+	configPrefix, err := getConfig(ctx, tx, "issue_prefix")
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	if configPrefix == "" {
+		return fmt.Errorf("database not initialized: issue_prefix config is missing (run 'bd init --prefix <prefix>' first)")
+	}
+
+	// PrefixOverride replaces the config prefix (cross-rig creation);
+	// IDPrefix appends to it (e.g., "bd" + "wisp" → "bd-wisp")
+	prefix := configPrefix
+	if issue.PrefixOverride != "" {
+		prefix = issue.PrefixOverride
+	} else if issue.IDPrefix != "" {
+		prefix = configPrefix + "-" + issue.IDPrefix
+	}
+
+	if issue.ID == "" {
+		// Hold the write lock until commit so a concurrent creator can't
+		// pick the same candidate between our collision check and insert
+		if err := lockWrites(ctx, tx); err != nil {
+			return err
+		}
+		generatedID, err := generateIssueID(ctx, tx, prefix, issue, actor)
+		if err != nil {
+			return fmt.Errorf("failed to generate issue ID: %w", err)
+		}
+		issue.ID = generatedID
+	} else {
+		if !strings.HasPrefix(issue.ID, prefix+"-") {
+			return fmt.Errorf("issue ID '%s' does not match configured prefix '%s'", issue.ID, prefix)
+		}
+
+		// Hierarchical IDs (bd-a3f8e9.1) need an existing parent, and the
+		// child counter must cover explicit child numbers
+		if parentID, childNum, ok := parseHierarchicalID(issue.ID); ok {
+			var exists bool
+			if err := tx.QueryRowContext(ctx,
+				`SELECT EXISTS (SELECT 1 FROM issues WHERE id = $1)`, parentID,
+			).Scan(&exists); err != nil {
+				return fmt.Errorf("failed to check parent: %w", err)
+			}
+			if !exists {
+				return fmt.Errorf("parent issue %s does not exist", parentID)
+			}
+			if err := ensureChildCounter(ctx, tx, parentID, childNum); err != nil {
+				return err
+			}
+		}
+
+		// Re-creating an issue over its tombstone replaces the tombstone
+		var existingStatus string
+		err := tx.QueryRowContext(ctx, `SELECT status FROM issues WHERE id = $1`, issue.ID).Scan(&existingStatus)
+		if err == nil && existingStatus == string(types.StatusTombstone) {
+			if err := purgeIssue(ctx, tx, issue.ID); err != nil {
+				return fmt.Errorf("failed to delete tombstone: %w", err)
+			}
+		} else if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to check for existing tombstone: %w", err)
+		}
+	}
+
+	if err := insertIssue(ctx, tx, issue); err != nil {
+		return fmt.Errorf("failed to insert issue: %w", err)
+	}
+
+	eventData, err := json.Marshal(issue)
+	if err != nil {
+		eventData = []byte(fmt.Sprintf(`{"id":"%s","title":"%s"}`, issue.ID, issue.Title))
+	}
+	if err := recordEvent(ctx, tx, issue.ID, types.EventCreated, actor, "", string(eventData), ""); err != nil {
+		return fmt.Errorf("failed to record creation event: %w", err)
+	}
+
+	if err := markDirty(ctx, tx, issue.ID); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	return nil
+}
+
+func getIssue(ctx context.Context, q dbtx, id string) (*types.Issue, error) {
+	var row issueRow
+	// nolint:gosec // G201: column list is a constant
+	err := q.QueryRowContext(ctx, fmt.Sprintf(`SELECT %s FROM issues WHERE id = $1`,
+		strings.Join(issueRowColumns, ", ")), id).Scan(row.dests()...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	issue := row.issue()
+
+	labels, err := getLabels(ctx, q, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	issue.Labels = labels
+	return issue, nil
+}
+
+// updateColumns maps UpdateIssue field names to issues columns. Keys not
+// listed here are rejected, which also keeps column names out of reach of
+// callers.
+var updateColumns = map[string]string{
+	"status":              "status",
+	"priority":            "priority",
+	"title":               "title",
+	"assignee":            "assignee",
+	"description":         "description",
+	"design":              "design",
+	"acceptance_criteria": "acceptance_criteria",
+	"notes":               "notes",
+	"issue_type":          "issue_type",
+	"estimated_minutes":   "estimated_minutes",
+	"external_ref":        "external_ref",
+	"closed_at":           "closed_at",
+	"close_reason":        "close_reason",
+	"closed_by_session":   "closed_by_session",
+	"sender":              "sender",
+	"wisp":                "ephemeral",
+	"pinned":              "pinned",
+	"hook_bead":           "hook_bead",
+	"role_bead":           "role_bead",
+	"agent_state":         "agent_state",
+	"last_activity":       "last_activity",
+	"role_type":           "role_type",
+	"rig":                 "rig",
+	"mol_type":            "mol_type",
+	"event_category":      "event_kind",
+	"event_actor":         "actor",
+	"event_target":        "target",
+	"event_payload":       "payload",
+	"due_at":              "due_at",
+	"defer_until":         "defer_until",
+	"await_id":            "await_id",
+}
+
+func updateIssue(ctx context.Context, tx dbtx, id string, updates map[string]interface{}, actor string, customStatuses []string) error {
+	oldIssue, err := getIssue(ctx, tx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get issue for update: %w", err)
+	}
+	if oldIssue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+
+	var args queryArgs
+	setClauses := []string{"updated_at = " + args.add(time.Now().UTC())}
+	for key, value := range updates {
+		column, ok := updateColumns[key]
+		if !ok {
+			return fmt.Errorf("invalid field for update: %s", key)
+		}
+		if err := validateFieldUpdate(key, value, customStatuses); err != nil {
+			return fmt.Errorf("failed to validate field update: %w", err)
+		}
+		setClauses = append(setClauses, column+" = "+args.add(updateValue(value)))
+	}
+
+	// Auto-manage closed_at when status changes (enforce invariant)
+	setClauses = manageClosedAt(oldIssue, updates, setClauses, &args)
+
+	if contentChanged(updates) {
+		updated, err := applyContentUpdates(*oldIssue, updates)
+		if err != nil {
+			return err
+		}
+		setClauses = append(setClauses, "content_hash = "+args.add(updated.ComputeContentHash()))
+	}
+
+	// nolint:gosec // G201: setClauses contains only column names, values are passed via args
+	query := fmt.Sprintf("UPDATE issues SET %s WHERE id = %s", strings.Join(setClauses, ", "), args.add(id))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update issue: %w", err)
+	}
+
+	oldData, err := json.Marshal(oldIssue)
+	if err != nil {
+		oldData = []byte(fmt.Sprintf(`{"id":"%s"}`, id))
+	}
+	newData, err := json.Marshal(updates)
+	if err != nil {
+		newData = []byte(`{}`)
+	}
+	eventType := determineEventType(oldIssue, updates)
+	if err := recordEvent(ctx, tx, id, eventType, actor, string(oldData), string(newData), ""); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	if err := markDirty(ctx, tx, id); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+
+	// Status changes affect which issues are blocked
+	if _, statusChanged := updates["status"]; statusChanged {
+		if err := rebuildBlockedCache(ctx, tx); err != nil {
+			return fmt.Errorf("failed to rebuild blocked cache: %w", err)
+		}
+	}
+
+	// Activity by the lease holder keeps their claim alive
+	if err := renewLease(ctx, tx, id, actor); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+	return nil
+}
+
+func closeIssue(ctx context.Context, tx dbtx, id, reason, actor, session string) error {
+	now := time.Now().UTC()
+
+	// close_reason is kept both on the issue (for queries and export) and
+	// on the closed event (for history)
+	result, err := tx.ExecContext(ctx, `
+		UPDATE issues SET status = $1, closed_at = $2, updated_at = $2, close_reason = $3, closed_by_session = $4
+		WHERE id = $5
+	`, types.StatusClosed, now, reason, session, id)
+	if err != nil {
+		return fmt.Errorf("failed to close issue: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("issue not found: %s", id)
+	}
+
+	if err := recordEvent(ctx, tx, id, types.EventClosed, actor, "", "", reason); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	if err := markDirty(ctx, tx, id); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+
+	// Closing ends any claim on the issue
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_leases WHERE issue_id = $1`, id); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+
+	if err := closeCompletedConvoys(ctx, tx, id, now); err != nil {
+		return err
+	}
+
+	// Closed issues don't block others
+	if err := rebuildBlockedCache(ctx, tx); err != nil {
+		return fmt.Errorf("failed to rebuild blocked cache: %w", err)
+	}
+	return nil
+}
+
+// closeCompletedConvoys auto-closes convoys (gt:convoy label) that track the
+// closed issue once none of their tracked issues remain open.
+func closeCompletedConvoys(ctx context.Context, tx dbtx, closedID string, now time.Time) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT d.issue_id
+		FROM dependencies d
+		JOIN issues i ON d.issue_id = i.id
+		JOIN labels l ON i.id = l.issue_id AND l.label = 'gt:convoy'
+		WHERE d.depends_on_id = $1
+		  AND d.type = $2
+		  AND i.status != $3
+	`, closedID, types.DepTracks, types.StatusClosed)
+	if err != nil {
+		return fmt.Errorf("failed to find tracking convoys: %w", err)
+	}
+	convoyIDs, err := scanStrings(rows)
+	if err != nil {
+		return fmt.Errorf("failed to scan convoy IDs: %w", err)
+	}
+
+	for _, convoyID := range convoyIDs {
+		var openCount int
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*)
+			FROM dependencies d
+			JOIN issues i ON d.depends_on_id = i.id
+			WHERE d.issue_id = $1
+			  AND d.type = $2
+			  AND i.status NOT IN ($3, $4)
+		`, convoyID, types.DepTracks, types.StatusClosed, types.StatusTombstone).Scan(&openCount); err != nil {
+			return fmt.Errorf("failed to count open tracked issues for convoy %s: %w", convoyID, err)
+		}
+		if openCount > 0 {
+			continue
+		}
+
+		closeReason := "All tracked issues completed"
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE issues SET status = $1, closed_at = $2, updated_at = $2, close_reason = $3
+			WHERE id = $4
+		`, types.StatusClosed, now, closeReason, convoyID); err != nil {
+			return fmt.Errorf("failed to auto-close convoy %s: %w", convoyID, err)
+		}
+		if err := recordEvent(ctx, tx, convoyID, types.EventClosed, "system:convoy-completion", "", "", closeReason); err != nil {
+			return fmt.Errorf("failed to record convoy close event: %w", err)
+		}
+		if err := markDirty(ctx, tx, convoyID); err != nil {
+			return fmt.Errorf("failed to mark convoy dirty: %w", err)
+		}
+	}
+	return nil
+}
+
+func deleteIssue(ctx context.Context, tx dbtx, id string) error {
+	// Issues that depend on this one change in the next export
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dirty_issues (issue_id, marked_at)
+		SELECT DISTINCT issue_id, now() FROM dependencies WHERE depends_on_id = $1 AND issue_id != $1
+		ON CONFLICT (issue_id) DO UPDATE SET marked_at = EXCLUDED.marked_at
+	`, id); err != nil {
+		return fmt.Errorf("failed to mark dependents dirty: %w", err)
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM issues WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check issue: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("issue not found: %s", id)
+	}
+
+	if err := purgeIssue(ctx, tx, id); err != nil {
+		return err
+	}
+	if err := rebuildBlockedCache(ctx, tx); err != nil {
+		return fmt.Errorf("failed to rebuild blocked cache: %w", err)
+	}
+	return nil
+}
+
+// purgeIssue removes an issue and every row that refers to it. Foreign keys
+// cascade for most tables, but dependencies.depends_on_id has no foreign key.
+func purgeIssue(ctx context.Context, tx dbtx, id string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM dependencies WHERE issue_id = $1 OR depends_on_id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete dependencies: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issues WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete issue: %w", err)
+	}
+	return nil
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+func insertIssue(ctx context.Context, tx dbtx, issue *types.Issue) error {
+	var args queryArgs
+	placeholders := args.list(issueInsertValues(issue)...)
+	// nolint:gosec // G201: column list is a constant
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO issues (%s) VALUES (%s)`,
+		strings.Join(issueInsertColumns, ", "), placeholders), args...)
+	return err
+}
+
+// issueInsertColumns are the issues table columns written by insertIssue,
+// in the order of issueInsertValues.
+var issueInsertColumns = []string{
+	"id", "content_hash", "title", "description", "design", "acceptance_criteria", "notes",
+	"status", "priority", "issue_type", "assignee", "estimated_minutes",
+	"created_at", "created_by", "owner", "updated_at", "closed_at", "closed_by_session", "external_ref",
+	"compaction_level", "compacted_at", "compacted_at_commit", "original_size",
+	"deleted_at", "deleted_by", "delete_reason", "original_type",
+	"sender", "ephemeral", "pinned", "is_template", "crystallizes",
+	"mol_type", "work_type", "quality_score", "source_system", "source_repo", "close_reason",
+	"event_kind", "actor", "target", "payload",
+	"await_type", "await_id", "timeout_ns", "waiters",
+	"hook_bead", "role_bead", "agent_state", "last_activity", "role_type", "rig",
+	"due_at", "defer_until",
+}
+
+func issueInsertValues(issue *types.Issue) []interface{} {
+	return []interface{}{
+		issue.ID, issue.ContentHash, issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes,
+		string(issue.Status), issue.Priority, string(issue.IssueType), nullString(issue.Assignee), nullInt(issue.EstimatedMinutes),
+		issue.CreatedAt, issue.CreatedBy, issue.Owner, issue.UpdatedAt, issue.ClosedAt, issue.ClosedBySession, nullStringPtr(issue.ExternalRef),
+		issue.CompactionLevel, issue.CompactedAt, nullStringPtr(issue.CompactedAtCommit), nullIntVal(issue.OriginalSize),
+		issue.DeletedAt, issue.DeletedBy, issue.DeleteReason, issue.OriginalType,
+		issue.Sender, issue.Ephemeral, issue.Pinned, issue.IsTemplate, issue.Crystallizes,
+		string(issue.MolType), string(issue.WorkType), nullFloat32Ptr(issue.QualityScore), issue.SourceSystem, issue.SourceRepo, issue.CloseReason,
+		issue.EventKind, issue.Actor, issue.Target, issue.Payload,
+		issue.AwaitType, issue.AwaitID, issue.Timeout.Nanoseconds(), formatJSONStringArray(issue.Waiters),
+		issue.HookBead, issue.RoleBead, string(issue.AgentState), issue.LastActivity, issue.RoleType, issue.Rig,
+		issue.DueAt, issue.DeferUntil,
+	}
+}
+
+// issueRowColumns are the issues table columns read by issueRow, in scan order.
+var issueRowColumns = []string{
+	"id", "content_hash", "title", "description", "design", "acceptance_criteria", "notes",
+	"status", "priority", "issue_type", "assignee", "estimated_minutes",
+	"created_at", "created_by", "owner", "updated_at", "closed_at", "closed_by_session", "external_ref",
+	"compaction_level", "compacted_at", "compacted_at_commit", "original_size", "source_repo", "close_reason",
+	"deleted_at", "deleted_by", "delete_reason", "original_type",
+	"sender", "ephemeral", "pinned", "is_template", "crystallizes",
+	"await_type", "await_id", "timeout_ns", "waiters",
+	"hook_bead", "role_bead", "agent_state", "last_activity", "role_type", "rig", "mol_type",
+	"event_kind", "actor", "target", "payload",
+	"due_at", "defer_until",
+	"quality_score", "work_type", "source_system",
+}
+
+// issueColumnsWithAlias returns issueRowColumns qualified with a table alias.
+func issueColumnsWithAlias(alias string) string {
+	cols := make([]string, len(issueRowColumns))
+	for i, c := range issueRowColumns {
+		cols[i] = alias + "." + c
+	}
+	return strings.Join(cols, ", ")
+}
+
+// issueRow holds one scanned issues row.
+type issueRow struct {
+	id, contentHash, title, description, design, acceptanceCriteria, notes sql.NullString
+	status, issueType, assignee, createdBy, owner, closedBySession         sql.NullString
+	externalRef, compactedAtCommit, sourceRepo, closeReason                sql.NullString
+	deletedBy, deleteReason, originalType, sender                          sql.NullString
+	awaitType, awaitID, waiters, hookBead, roleBead, agentState            sql.NullString
+	roleType, rig, molType, eventKind, actor, target, payload              sql.NullString
+	workType, sourceSystem                                                 sql.NullString
+	priority, estimatedMinutes, compactionLevel, originalSize, timeoutNs   sql.NullInt64
+	ephemeral, pinned, isTemplate, crystallizes                            sql.NullBool
+	createdAt, updatedAt, closedAt, compactedAt, deletedAt                 sql.NullTime
+	lastActivity, dueAt, deferUntil                                        sql.NullTime
+	qualityScore                                                           sql.NullFloat64
+}
+
+// dests returns scan destinations matching issueRowColumns.
+func (r *issueRow) dests() []interface{} {
+	return []interface{}{
+		&r.id, &r.contentHash, &r.title, &r.description, &r.design, &r.acceptanceCriteria, &r.notes,
+		&r.status, &r.priority, &r.issueType, &r.assignee, &r.estimatedMinutes,
+		&r.createdAt, &r.createdBy, &r.owner, &r.updatedAt, &r.closedAt, &r.closedBySession, &r.externalRef,
+		&r.compactionLevel, &r.compactedAt, &r.compactedAtCommit, &r.originalSize, &r.sourceRepo, &r.closeReason,
+		&r.deletedAt, &r.deletedBy, &r.deleteReason, &r.originalType,
+		&r.sender, &r.ephemeral, &r.pinned, &r.isTemplate, &r.crystallizes,
+		&r.awaitType, &r.awaitID, &r.timeoutNs, &r.waiters,
+		&r.hookBead, &r.roleBead, &r.agentState, &r.lastActivity, &r.roleType, &r.rig, &r.molType,
+		&r.eventKind, &r.actor, &r.target, &r.payload,
+		&r.dueAt, &r.deferUntil,
+		&r.qualityScore, &r.workType, &r.sourceSystem,
+	}
+}
+
+// issue converts the scanned row to an Issue.
+func (r *issueRow) issue() *types.Issue {
+	issue := &types.Issue{
+		ID:                 r.id.String,
+		ContentHash:        r.contentHash.String,
+		Title:              r.title.String,
+		Description:        r.description.String,
+		Design:             r.design.String,
+		AcceptanceCriteria: r.acceptanceCriteria.String,
+		Notes:              r.notes.String,
+		Status:             types.Status(r.status.String),
+		Priority:           int(r.priority.Int64),
+		IssueType:          types.IssueType(r.issueType.String),
+		Assignee:           r.assignee.String,
+		CreatedAt:          r.createdAt.Time,
+		CreatedBy:          r.createdBy.String,
+		Owner:              r.owner.String,
+		UpdatedAt:          r.updatedAt.Time,
+		ClosedBySession:    r.closedBySession.String,
+		CompactionLevel:    int(r.compactionLevel.Int64),
+		OriginalSize:       int(r.originalSize.Int64),
+		SourceRepo:         r.sourceRepo.String,
+		CloseReason:        r.closeReason.String,
+		DeletedBy:          r.deletedBy.String,
+		DeleteReason:       r.deleteReason.String,
+		OriginalType:       r.originalType.String,
+		Sender:             r.sender.String,
+		Ephemeral:          r.ephemeral.Bool,
+		Pinned:             r.pinned.Bool,
+		IsTemplate:         r.isTemplate.Bool,
+		Crystallizes:       r.crystallizes.Bool,
+		AwaitType:          r.awaitType.String,
+		AwaitID:            r.awaitID.String,
+		Timeout:            time.Duration(r.timeoutNs.Int64),
+		HookBead:           r.hookBead.String,
+		RoleBead:           r.roleBead.String,
+		AgentState:         types.AgentState(r.agentState.String),
+		RoleType:           r.roleType.String,
+		Rig:                r.rig.String,
+		MolType:            types.MolType(r.molType.String),
+		EventKind:          r.eventKind.String,
+		Actor:              r.actor.String,
+		Target:             r.target.String,
+		Payload:            r.payload.String,
+		WorkType:           types.WorkType(r.workType.String),
+		SourceSystem:       r.sourceSystem.String,
+	}
+	if r.closedAt.Valid {
+		issue.ClosedAt = &r.closedAt.Time
+	}
+	if r.estimatedMinutes.Valid {
+		mins := int(r.estimatedMinutes.Int64)
+		issue.EstimatedMinutes = &mins
+	}
+	if r.externalRef.Valid {
+		issue.ExternalRef = &r.externalRef.String
+	}
+	if r.compactedAt.Valid {
+		issue.CompactedAt = &r.compactedAt.Time
+	}
+	if r.compactedAtCommit.Valid {
+		issue.CompactedAtCommit = &r.compactedAtCommit.String
+	}
+	if r.deletedAt.Valid {
+		issue.DeletedAt = &r.deletedAt.Time
+	}
+	if r.waiters.Valid && r.waiters.String != "" {
+		issue.Waiters = parseJSONStringArray(r.waiters.String)
+	}
+	if r.lastActivity.Valid {
+		issue.LastActivity = &r.lastActivity.Time
+	}
+	if r.dueAt.Valid {
+		issue.DueAt = &r.dueAt.Time
+	}
+	if r.deferUntil.Valid {
+		issue.DeferUntil = &r.deferUntil.Time
+	}
+	if r.qualityScore.Valid {
+		qs := float32(r.qualityScore.Float64)
+		issue.QualityScore = &qs
+	}
+	return issue
+}
+
+// scanIssues reads issue rows selected with issueRowColumns and attaches
+// their labels.
+func scanIssues(ctx context.Context, q dbtx, rows *sql.Rows) ([]*types.Issue, error) {
+	var issues []*types.Issue
+	for rows.Next() {
+		var row issueRow
+		if err := rows.Scan(row.dests()...); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan issue: %w", err)
+		}
+		issues = append(issues, row.issue())
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(issues) == 0 {
+		return issues, nil
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := getLabelsForIssues(ctx, q, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		issue.Labels = labels[issue.ID]
+	}
+	return issues, nil
+}
+
+// scanStrings reads a single string column from every row and closes rows.
+func scanStrings(rows *sql.Rows) ([]string, error) {
+	defer func() { _ = rows.Close() }()
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func recordEvent(ctx context.Context, tx dbtx, issueID string, eventType types.EventType, actor, oldValue, newValue, comment string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, issueID, string(eventType), actor, nullString(oldValue), nullString(newValue), nullString(comment))
+	return err
+}
+
+func markDirty(ctx context.Context, tx dbtx, issueID string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO dirty_issues (issue_id, marked_at)
+		VALUES ($1, $2)
+		ON CONFLICT (issue_id) DO UPDATE SET marked_at = EXCLUDED.marked_at
+	`, issueID, time.Now().UTC())
+	return err
+}
+
+// validateFieldUpdate applies the same value checks as the SQLite backend.
+func validateFieldUpdate(key string, value interface{}, customStatuses []string) error {
+	switch key {
+	case "priority":
+		if priority, ok := value.(int); ok && (priority < 0 || priority > 4) {
+			return fmt.Errorf("priority must be between 0 and 4 (got %d)", priority)
+		}
+	case "status":
+		if status, ok := value.(string); ok {
+			if types.Status(status) == types.StatusTombstone {
+				return fmt.Errorf("cannot set status to tombstone directly; use 'bd delete' instead")
+			}
+			if !types.Status(status).IsValidWithCustom(customStatuses) {
+				return fmt.Errorf("invalid status: %s", status)
+			}
+		}
+	case "issue_type":
+		if issueType, ok := value.(string); ok && !types.IssueType(issueType).Normalize().IsValid() {
+			return fmt.Errorf("invalid issue type: %s", issueType)
+		}
+	case "title":
+		if title, ok := value.(string); ok && (len(title) == 0 || len(title) > 500) {
+			return fmt.Errorf("title must be 1-500 characters")
+		}
+	case "estimated_minutes":
+		if mins, ok := value.(int); ok && mins < 0 {
+			return fmt.Errorf("estimated_minutes cannot be negative")
+		}
+	}
+	return nil
+}
+
+// updateValue converts typed update values to what pgx can bind.
+func updateValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case types.Status:
+		return string(v)
+	case types.IssueType:
+		return string(v)
+	case types.AgentState:
+		return string(v)
+	case types.MolType:
+		return string(v)
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	default:
+		return value
+	}
+}
+
+// contentFields are the update keys that change an issue's content hash.
+var contentFields = []string{"title", "description", "design", "acceptance_criteria", "notes", "status", "priority", "issue_type", "assignee", "external_ref"}
+
+func contentChanged(updates map[string]interface{}) bool {
+	for _, field := range contentFields {
+		if _, ok := updates[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyContentUpdates returns a copy of issue with the content fields of
+// updates applied, for recomputing the content hash.
+func applyContentUpdates(issue types.Issue, updates map[string]interface{}) (types.Issue, error) {
+	for key, value := range updates {
+		switch key {
+		case "title":
+			issue.Title, _ = value.(string)
+		case "description":
+			issue.Description, _ = value.(string)
+		case "design":
+			issue.Design, _ = value.(string)
+		case "acceptance_criteria":
+			issue.AcceptanceCriteria, _ = value.(string)
+		case "notes":
+			issue.Notes, _ = value.(string)
+		case "status":
+			if s, ok := value.(types.Status); ok {
+				issue.Status = s
+			} else {
+				s, _ := value.(string)
+				issue.Status = types.Status(s)
+			}
+		case "priority":
+			issue.Priority, _ = value.(int)
+		case "issue_type":
+			if t, ok := value.(types.IssueType); ok {
+				issue.IssueType = t
+			} else {
+				t, _ := value.(string)
+				issue.IssueType = types.IssueType(t)
+			}
+		case "assignee":
+			issue.Assignee, _ = value.(string)
+		case "external_ref":
+			switch v := value.(type) {
+			case nil:
+				issue.ExternalRef = nil
+			case string:
+				issue.ExternalRef = &v
+			case *string:
+				issue.ExternalRef = v
+			default:
+				return issue, fmt.Errorf("external_ref must be string or *string, got %T", value)
+			}
+		}
+	}
+	return issue, nil
+}
+
+// manageClosedAt keeps closed_at consistent with status changes unless the
+// caller set closed_at explicitly (imports preserve timestamps).
+func manageClosedAt(oldIssue *types.Issue, updates map[string]interface{}, setClauses []string, args *queryArgs) []string {
+	statusVal, hasStatus := updates["status"]
+	_, hasExplicitClosedAt := updates["closed_at"]
+	if hasExplicitClosedAt || !hasStatus {
+		return setClauses
+	}
+
+	var newStatus string
+	switch v := statusVal.(type) {
+	case string:
+		newStatus = v
+	case types.Status:
+		newStatus = string(v)
+	default:
+		return setClauses
+	}
+
+	if newStatus == string(types.StatusClosed) {
+		setClauses = append(setClauses, "closed_at = "+args.add(time.Now().UTC()))
+	} else if oldIssue.Status == types.StatusClosed {
+		setClauses = append(setClauses, "closed_at = NULL", "close_reason = ''")
+	}
+	return setClauses
+}
+
+func determineEventType(oldIssue *types.Issue, updates map[string]interface{}) types.EventType {
+	statusVal, hasStatus := updates["status"]
+	if !hasStatus {
+		return types.EventUpdated
+	}
+
+	newStatus, ok := statusVal.(string)
+	if !ok {
+		return types.EventUpdated
+	}
+
+	if newStatus == string(types.StatusClosed) {
+		return types.EventClosed
+	}
+	if oldIssue.Status == types.StatusClosed {
+		return types.EventReopened
+	}
+	return types.EventStatusChanged
+}
+
+// Helper functions for nullable values
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullStringPtr(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func nullInt(i *int) interface{} {
+	if i == nil {
+		return nil
+	}
+	return *i
+}
+
+func nullIntVal(i int) interface{} {
+	if i == 0 {
+		return nil
+	}
+	return i
+}
+
+func nullFloat32Ptr(f *float32) interface{} {
+	if f == nil {
+		return nil
+	}
+	return float64(*f)
+}
+
+func parseJSONStringArray(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var result []string
+	if err := json.Unmarshal([]byte(s), &result); err != nil {
+		return nil
+	}
+	return result
+}
+
+func formatJSONStringArray(arr []string) string {
+	if len(arr) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(arr)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
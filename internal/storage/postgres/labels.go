@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// AddLabel adds a label to an issue
+func (s *PostgresStore) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return addLabel(ctx, tx, issueID, label, actor)
+	})
+}
+
+// RemoveLabel removes a label from an issue
+func (s *PostgresStore) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return removeLabel(ctx, tx, issueID, label, actor)
+	})
+}
+
+func addLabel(ctx context.Context, tx dbtx, issueID, label, actor string) error {
+	return executeLabelOperation(ctx, tx, issueID, actor,
+		`INSERT INTO labels (issue_id, label) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		label, types.EventLabelAdded, fmt.Sprintf("Added label: %s", label), "failed to add label")
+}
+
+func removeLabel(ctx context.Context, tx dbtx, issueID, label, actor string) error {
+	return executeLabelOperation(ctx, tx, issueID, actor,
+		`DELETE FROM labels WHERE issue_id = $1 AND label = $2`,
+		label, types.EventLabelRemoved, fmt.Sprintf("Removed label: %s", label), "failed to remove label")
+}
+
+// executeLabelOperation runs a label insert or delete and, if it changed
+// anything, records the event and marks the issue dirty.
+func executeLabelOperation(ctx context.Context, tx dbtx, issueID, actor, labelSQL, label string,
+	eventType types.EventType, eventComment, operationError string) error {
+	result, err := tx.ExecContext(ctx, labelSQL, issueID, label)
+	if err != nil {
+		return fmt.Errorf("%s: %w", operationError, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rows == 0 {
+		// No change made (label already existed or didn't exist), so don't record event
+		return nil
+	}
+
+	if err := recordEvent(ctx, tx, issueID, eventType, actor, "", "", eventComment); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	if err := markDirty(ctx, tx, issueID); err != nil {
+		return fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+	return nil
+}
+
+// GetLabels retrieves all labels for an issue
+func (s *PostgresStore) GetLabels(ctx context.Context, issueID string) ([]string, error) {
+	return getLabels(ctx, s.db, issueID)
+}
+
+// GetLabelsForIssues retrieves labels for multiple issues
+func (s *PostgresStore) GetLabelsForIssues(ctx context.Context, issueIDs []string) (map[string][]string, error) {
+	return getLabelsForIssues(ctx, s.db, issueIDs)
+}
+
+// GetIssuesByLabel retrieves all issues with a specific label
+func (s *PostgresStore) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
+	// nolint:gosec // G201: column list is a constant
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM issues i
+		JOIN labels l ON i.id = l.issue_id
+		WHERE l.label = $1
+		ORDER BY i.priority ASC, i.created_at DESC
+	`, issueColumnsWithAlias("i")), label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues by label: %w", err)
+	}
+	return scanIssues(ctx, s.db, rows)
+}
+
+func getLabels(ctx context.Context, q dbtx, issueID string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT label FROM labels WHERE issue_id = $1 ORDER BY label
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	labels, err := scanStrings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan label: %w", err)
+	}
+	return labels, nil
+}
+
+func getLabelsForIssues(ctx context.Context, q dbtx, issueIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	var args queryArgs
+	placeholders := args.list(stringArgs(issueIDs)...)
+	// nolint:gosec // G201: placeholders are generated, values passed via args
+	rows, err := q.QueryContext(ctx, fmt.Sprintf(`
+		SELECT issue_id, label FROM labels
+		WHERE issue_id IN (%s)
+		ORDER BY issue_id, label
+	`, placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels for issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var issueID, label string
+		if err := rows.Scan(&issueID, &label); err != nil {
+			return nil, fmt.Errorf("failed to scan label: %w", err)
+		}
+		result[issueID] = append(result[issueID], label)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// acquireLease grants holder a lease on issueID. The upsert only overwrites
+// an existing row if it belongs to the same holder or has expired, so the
+// statement itself is the compare-and-set: zero rows affected means another
+// holder has an active lease.
+func acquireLease(ctx context.Context, tx dbtx, issueID, holder string, ttl time.Duration) (bool, error) {
+	if holder == "" {
+		return false, fmt.Errorf("lease holder is required")
+	}
+	if ttl <= 0 {
+		return false, fmt.Errorf("lease ttl must be positive (got %s)", ttl)
+	}
+
+	now := time.Now().UTC()
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO issue_leases (issue_id, holder, acquired_at, expires_at, ttl_ns)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (issue_id) DO UPDATE SET
+			holder = EXCLUDED.holder,
+			acquired_at = EXCLUDED.acquired_at,
+			expires_at = EXCLUDED.expires_at,
+			ttl_ns = EXCLUDED.ttl_ns
+		WHERE issue_leases.holder = EXCLUDED.holder OR issue_leases.expires_at <= $3
+	`, issueID, holder, now, now.Add(ttl), int64(ttl))
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check lease result: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetLease returns the lease on an issue, or nil if there is none.
+// Expired leases are returned as-is; use Lease.IsActive to check them.
+func (s *PostgresStore) GetLease(ctx context.Context, issueID string) (*types.Lease, error) {
+	var lease types.Lease
+	var ttlNs int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT issue_id, holder, acquired_at, expires_at, ttl_ns
+		FROM issue_leases WHERE issue_id = $1
+	`, issueID).Scan(&lease.IssueID, &lease.Holder, &lease.AcquiredAt, &lease.ExpiresAt, &ttlNs)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lease: %w", err)
+	}
+	lease.TTL = time.Duration(ttlNs)
+	return &lease, nil
+}
+
+// ReleaseLease drops holder's lease on an issue. The issue itself is left
+// untouched; releasing is a no-op if the lease belongs to someone else.
+func (s *PostgresStore) ReleaseLease(ctx context.Context, issueID, holder string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM issue_leases WHERE issue_id = $1 AND holder = $2
+	`, issueID, holder)
+	if err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
+
+// ExpireLeases deletes every expired lease. Issues still in_progress and
+// assigned to the former lease holder go back to open and unassigned, so
+// they reappear in bd ready, and come off the holder's hook if it is an
+// agent bead. Returns the IDs of reopened issues.
+func (s *PostgresStore) ExpireLeases(ctx context.Context, actor string) ([]string, error) {
+	customStatuses, err := s.GetCustomStatuses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom statuses: %w", err)
+	}
+
+	var reopened []string
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		rows, err := tx.QueryContext(ctx, `
+			SELECT l.issue_id, l.holder
+			FROM issue_leases l
+			JOIN issues i ON i.id = l.issue_id
+			WHERE l.expires_at <= $1 AND i.status = $2 AND i.assignee = l.holder
+			ORDER BY l.issue_id
+			FOR UPDATE OF l
+		`, now, types.StatusInProgress)
+		if err != nil {
+			return fmt.Errorf("failed to query expired leases: %w", err)
+		}
+		var ids, holders []string
+		for rows.Next() {
+			var id, holder string
+			if err := rows.Scan(&id, &holder); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("failed to scan expired lease: %w", err)
+			}
+			ids = append(ids, id)
+			holders = append(holders, holder)
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for i, id := range ids {
+			updates := map[string]interface{}{
+				"status":   string(types.StatusOpen),
+				"assignee": "",
+			}
+			if err := updateIssue(ctx, tx, id, updates, actor, customStatuses); err != nil {
+				return fmt.Errorf("failed to reopen %s: %w", id, err)
+			}
+			if err := storage.UnhookAgent(ctx, &pgTransaction{tx: tx}, holders[i], id, actor); err != nil {
+				return fmt.Errorf("failed to unhook %s from %s: %w", id, holders[i], err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM issue_leases WHERE expires_at <= $1`, now); err != nil {
+			return fmt.Errorf("failed to delete expired leases: %w", err)
+		}
+		reopened = ids
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reopened, nil
+}
+
+// renewLease extends an active lease held by actor to now+ttl. Called on
+// issue activity (updates, comments) so that working agents keep their claim.
+// Expired leases are not revived; they are left for ExpireLeases.
+func renewLease(ctx context.Context, tx dbtx, issueID, actor string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE issue_leases
+		SET expires_at = now() + make_interval(secs => ttl_ns / 1e9)
+		WHERE issue_id = $1 AND holder = $2 AND expires_at > now()
+	`, issueID, actor)
+	return err
+}
@@ -0,0 +1,677 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// testURLEnv names the server used by the integration tests, e.g.
+// postgres://postgres@localhost:5432/beads_test?sslmode=disable
+const testURLEnv = "BEADS_TEST_POSTGRES_URL"
+
+// setupTestStore opens a store in a fresh schema so tests don't see each
+// other's rows. The schema is dropped on cleanup.
+func setupTestStore(t *testing.T) *PostgresStore {
+	t.Helper()
+	base := os.Getenv(testURLEnv)
+	if base == "" {
+		t.Skipf("%s not set, skipping postgres test", testURLEnv)
+	}
+	ctx := context.Background()
+
+	admin, err := sql.Open("pgx", base)
+	if err != nil {
+		t.Fatalf("failed to open admin connection: %v", err)
+	}
+	defer func() { _ = admin.Close() }()
+
+	schema := fmt.Sprintf("bd_test_%d", time.Now().UnixNano())
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		db, err := sql.Open("pgx", base)
+		if err != nil {
+			return
+		}
+		defer func() { _ = db.Close() }()
+		_, _ = db.ExecContext(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	u, err := url.Parse(base)
+	if err != nil {
+		t.Fatalf("%s must be a URL: %v", testURLEnv, err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	store, err := New(ctx, &Config{DSN: u.String(), Path: filepath.Join(t.TempDir(), "postgres")})
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if err := store.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("failed to set prefix: %v", err)
+	}
+	return store
+}
+
+func newIssue(title string) *types.Issue {
+	return &types.Issue{
+		Title:     title,
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+	}
+}
+
+func createTestIssue(t *testing.T, s *PostgresStore, title string) *types.Issue {
+	t.Helper()
+	issue := newIssue(title)
+	if err := s.CreateIssue(context.Background(), issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue(%q) failed: %v", title, err)
+	}
+	return issue
+}
+
+func issueIDs(issues []*types.Issue) []string {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	return ids
+}
+
+func containsID(issues []*types.Issue, id string) bool {
+	for _, issue := range issues {
+		if issue.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseHierarchicalID(t *testing.T) {
+	tests := []struct {
+		id     string
+		parent string
+		num    int
+		ok     bool
+	}{
+		{"bd-a3f8e9.2", "bd-a3f8e9", 2, true},
+		{"bd-a3f8e9.1.10", "bd-a3f8e9.1", 10, true},
+		{"bd-a3f8e9", "", 0, false},
+		{"my.prefix-abc", "", 0, false},
+		{"bd-abc.", "", 0, false},
+	}
+	for _, tt := range tests {
+		parent, num, ok := parseHierarchicalID(tt.id)
+		if parent != tt.parent || num != tt.num || ok != tt.ok {
+			t.Errorf("parseHierarchicalID(%q) = (%q, %d, %v), want (%q, %d, %v)",
+				tt.id, parent, num, ok, tt.parent, tt.num, tt.ok)
+		}
+	}
+}
+
+func TestNewUnreachable(t *testing.T) {
+	// Port 1 on loopback refuses connections without needing a server
+	_, err := New(context.Background(), &Config{DSN: "postgres://beads@127.0.0.1:1/beads?connect_timeout=2"})
+	if !errors.Is(err, ErrUnreachable) {
+		t.Fatalf("New() error = %v, want ErrUnreachable", err)
+	}
+}
+
+func TestQueryArgs(t *testing.T) {
+	var args queryArgs
+	if got := args.add("a"); got != "$1" {
+		t.Errorf("add() = %q, want $1", got)
+	}
+	if got := args.list("b", "c"); got != "$2, $3" {
+		t.Errorf("list() = %q, want \"$2, $3\"", got)
+	}
+	if len(args) != 3 {
+		t.Errorf("len(args) = %d, want 3", len(args))
+	}
+}
+
+func TestMigrationsIdempotent(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	if err := runMigrations(ctx, s.db); err != nil {
+		t.Fatalf("second runMigrations failed: %v", err)
+	}
+	version, err := s.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatalf("SchemaVersion failed: %v", err)
+	}
+	if want := migrations[len(migrations)-1].version; version != want {
+		t.Errorf("SchemaVersion() = %d, want %d", version, want)
+	}
+}
+
+func TestCreateAndGetIssue(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	issue := newIssue("First issue")
+	issue.Description = "details"
+	issue.Labels = nil
+	if err := s.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if !strings.HasPrefix(issue.ID, "test-") {
+		t.Errorf("ID = %q, want test- prefix", issue.ID)
+	}
+
+	got, err := s.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got == nil || got.Title != "First issue" || got.Description != "details" {
+		t.Fatalf("GetIssue() = %+v, want created issue", got)
+	}
+	if got.ContentHash == "" {
+		t.Error("ContentHash not set")
+	}
+
+	missing, err := s.GetIssue(ctx, "test-missing")
+	if err != nil || missing != nil {
+		t.Errorf("GetIssue(missing) = %v, %v; want nil, nil", missing, err)
+	}
+
+	events, err := s.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].EventType != types.EventCreated {
+		t.Errorf("events = %+v, want one created event", events)
+	}
+
+	dirty, err := s.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	if len(dirty) != 1 || dirty[0] != issue.ID {
+		t.Errorf("dirty = %v, want [%s]", dirty, issue.ID)
+	}
+}
+
+func TestCreateIssueWrongPrefix(t *testing.T) {
+	s := setupTestStore(t)
+	issue := newIssue("Wrong prefix")
+	issue.ID = "other-abc"
+	if err := s.CreateIssue(context.Background(), issue, "tester"); err == nil {
+		t.Error("expected prefix mismatch error")
+	}
+}
+
+func TestUpdateAndCloseIssue(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+	issue := createTestIssue(t, s, "Update me")
+
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{
+		"status":   string(types.StatusInProgress),
+		"assignee": "alice",
+		"priority": 1,
+	}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	got, _ := s.GetIssue(ctx, issue.ID)
+	if got.Status != types.StatusInProgress || got.Assignee != "alice" || got.Priority != 1 {
+		t.Errorf("after update: status=%s assignee=%s priority=%d", got.Status, got.Assignee, got.Priority)
+	}
+
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"bogus": 1}, "tester"); err == nil {
+		t.Error("expected error for invalid field")
+	}
+
+	if err := s.CloseIssue(ctx, issue.ID, "done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	got, _ = s.GetIssue(ctx, issue.ID)
+	if got.Status != types.StatusClosed || got.ClosedAt == nil || got.CloseReason != "done" {
+		t.Errorf("after close: status=%s closed_at=%v reason=%q", got.Status, got.ClosedAt, got.CloseReason)
+	}
+
+	// Reopening clears closed_at
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusOpen)}, "tester"); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	got, _ = s.GetIssue(ctx, issue.ID)
+	if got.ClosedAt != nil {
+		t.Errorf("closed_at = %v after reopen, want nil", got.ClosedAt)
+	}
+}
+
+func TestGetNextChildID(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+	parent := createTestIssue(t, s, "Parent")
+
+	first, err := s.GetNextChildID(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("GetNextChildID failed: %v", err)
+	}
+	if first != parent.ID+".1" {
+		t.Errorf("first child = %q, want %s.1", first, parent.ID)
+	}
+
+	// An explicit child ID bumps the counter past it
+	child := newIssue("Explicit child")
+	child.ID = parent.ID + ".5"
+	if err := s.CreateIssue(ctx, child, "tester"); err != nil {
+		t.Fatalf("CreateIssue(child) failed: %v", err)
+	}
+	next, err := s.GetNextChildID(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("GetNextChildID failed: %v", err)
+	}
+	if next != parent.ID+".6" {
+		t.Errorf("next child = %q, want %s.6", next, parent.ID)
+	}
+
+	if _, err := s.GetNextChildID(ctx, "test-nope"); err == nil {
+		t.Error("expected error for missing parent")
+	}
+}
+
+func TestBlockedCacheAndReadyWork(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+	blocker := createTestIssue(t, s, "Blocker")
+	blocked := createTestIssue(t, s, "Blocked")
+	epic := createTestIssue(t, s, "Epic")
+	child := createTestIssue(t, s, "Child of blocked epic")
+
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: epic.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: epic.ID, Type: types.DepParentChild}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	ready, err := s.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if !containsID(ready, blocker.ID) || containsID(ready, blocked.ID) || containsID(ready, child.ID) {
+		t.Errorf("ready = %v, want blocker only (blocked and child excluded)", issueIDs(ready))
+	}
+
+	isBlocked, blockers, err := s.IsBlocked(ctx, blocked.ID)
+	if err != nil {
+		t.Fatalf("IsBlocked failed: %v", err)
+	}
+	if !isBlocked || len(blockers) != 1 || blockers[0] != blocker.ID {
+		t.Errorf("IsBlocked = %v, %v; want true, [%s]", isBlocked, blockers, blocker.ID)
+	}
+
+	blockedIssues, err := s.GetBlockedIssues(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(blockedIssues) != 2 {
+		t.Errorf("GetBlockedIssues returned %d issues, want 2", len(blockedIssues))
+	}
+
+	if err := s.CloseIssue(ctx, blocker.ID, "done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	unblocked, err := s.GetNewlyUnblockedByClose(ctx, blocker.ID)
+	if err != nil {
+		t.Fatalf("GetNewlyUnblockedByClose failed: %v", err)
+	}
+	if !containsID(unblocked, blocked.ID) {
+		t.Errorf("newly unblocked = %v, want %s", issueIDs(unblocked), blocked.ID)
+	}
+	ready, _ = s.GetReadyWork(ctx, types.WorkFilter{})
+	if !containsID(ready, blocked.ID) || !containsID(ready, child.ID) {
+		t.Errorf("ready after close = %v, want blocked and child", issueIDs(ready))
+	}
+}
+
+func TestDependencyCycleRejected(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+	a := createTestIssue(t, s, "A")
+	b := createTestIssue(t, s, "B")
+	c := createTestIssue(t, s, "C")
+
+	for _, dep := range []*types.Dependency{
+		{IssueID: a.ID, DependsOnID: b.ID, Type: types.DepBlocks},
+		{IssueID: b.ID, DependsOnID: c.ID, Type: types.DepBlocks},
+	} {
+		if err := s.AddDependency(ctx, dep, "tester"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	err := s.AddDependency(ctx, &types.Dependency{IssueID: c.ID, DependsOnID: a.ID, Type: types.DepBlocks}, "tester")
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("AddDependency(c->a) error = %v, want cycle error", err)
+	}
+
+	cycles, err := s.DetectCycles(ctx)
+	if err != nil {
+		t.Fatalf("DetectCycles failed: %v", err)
+	}
+	if len(cycles) != 0 {
+		t.Errorf("DetectCycles found %d cycles, want 0", len(cycles))
+	}
+
+	tree, err := s.GetDependencyTree(ctx, a.ID, 10, false, false)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	if len(tree) != 3 {
+		t.Errorf("tree has %d nodes, want 3", len(tree))
+	}
+}
+
+func TestLabelsAndSearch(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+	bug := createTestIssue(t, s, "Login Bug")
+	feature := createTestIssue(t, s, "Dark mode")
+
+	if err := s.AddLabel(ctx, bug.ID, "frontend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	// Adding twice is a no-op
+	if err := s.AddLabel(ctx, bug.ID, "frontend", "tester"); err != nil {
+		t.Fatalf("AddLabel (duplicate) failed: %v", err)
+	}
+	if err := s.AddLabel(ctx, feature.ID, "ui", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	labels, err := s.GetLabels(ctx, bug.ID)
+	if err != nil || len(labels) != 1 || labels[0] != "frontend" {
+		t.Errorf("GetLabels = %v, %v; want [frontend]", labels, err)
+	}
+
+	results, err := s.SearchIssues(ctx, "login", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != bug.ID {
+		t.Errorf("search(login) = %v, want [%s]", issueIDs(results), bug.ID)
+	}
+
+	results, err = s.SearchIssues(ctx, "", types.IssueFilter{LabelsAny: []string{"ui", "frontend"}})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("search(labels any) returned %d issues, want 2", len(results))
+	}
+
+	if err := s.RemoveLabel(ctx, bug.ID, "frontend", "tester"); err != nil {
+		t.Fatalf("RemoveLabel failed: %v", err)
+	}
+	byLabel, err := s.GetIssuesByLabel(ctx, "frontend")
+	if err != nil || len(byLabel) != 0 {
+		t.Errorf("GetIssuesByLabel after remove = %v, %v; want empty", issueIDs(byLabel), err)
+	}
+}
+
+func TestCommentsAndDirtyTracking(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+	issue := createTestIssue(t, s, "Commented")
+
+	if err := s.ClearDirtyIssuesByID(ctx, []string{issue.ID}); err != nil {
+		t.Fatalf("ClearDirtyIssuesByID failed: %v", err)
+	}
+	comment, err := s.AddIssueComment(ctx, issue.ID, "alice", "hello")
+	if err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+	if comment.ID == 0 || comment.Text != "hello" {
+		t.Errorf("comment = %+v", comment)
+	}
+	comments, err := s.GetIssueComments(ctx, issue.ID)
+	if err != nil || len(comments) != 1 {
+		t.Errorf("GetIssueComments = %v, %v; want 1 comment", comments, err)
+	}
+
+	dirty, _ := s.GetDirtyIssues(ctx)
+	if len(dirty) != 1 || dirty[0] != issue.ID {
+		t.Errorf("dirty after comment = %v, want [%s]", dirty, issue.ID)
+	}
+
+	if _, err := s.AddIssueComment(ctx, "test-missing", "alice", "hi"); err == nil {
+		t.Error("expected error commenting on missing issue")
+	}
+
+	if err := s.SetExportHash(ctx, issue.ID, "abc"); err != nil {
+		t.Fatalf("SetExportHash failed: %v", err)
+	}
+	if hash, _ := s.GetExportHash(ctx, issue.ID); hash != "abc" {
+		t.Errorf("GetExportHash = %q, want abc", hash)
+	}
+	if err := s.ClearAllExportHashes(ctx); err != nil {
+		t.Fatalf("ClearAllExportHashes failed: %v", err)
+	}
+	if hash, _ := s.GetExportHash(ctx, issue.ID); hash != "" {
+		t.Errorf("GetExportHash after clear = %q, want empty", hash)
+	}
+}
+
+func TestTransactionRollback(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	var createdID string
+	err := s.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		issue := newIssue("Rolled back")
+		if err := tx.CreateIssue(ctx, issue, "tester"); err != nil {
+			return err
+		}
+		createdID = issue.ID
+		// Read-your-writes inside the transaction
+		got, err := tx.GetIssue(ctx, issue.ID)
+		if err != nil || got == nil {
+			return fmt.Errorf("GetIssue in tx = %v, %v", got, err)
+		}
+		return fmt.Errorf("abort")
+	})
+	if err == nil || err.Error() != "abort" {
+		t.Fatalf("RunInTransaction error = %v, want abort", err)
+	}
+	if got, _ := s.GetIssue(ctx, createdID); got != nil {
+		t.Errorf("issue %s exists after rollback", createdID)
+	}
+}
+
+func TestLeases(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+	issue := createTestIssue(t, s, "Claim me")
+
+	acquire := func(holder string, ttl time.Duration) bool {
+		t.Helper()
+		var ok bool
+		err := s.RunInTransaction(ctx, func(tx storage.Transaction) error {
+			var err error
+			ok, err = tx.AcquireLease(ctx, issue.ID, holder, ttl)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("AcquireLease(%s) failed: %v", holder, err)
+		}
+		return ok
+	}
+
+	if !acquire("alice", time.Hour) {
+		t.Fatal("alice should acquire the free lease")
+	}
+	if acquire("bob", time.Hour) {
+		t.Error("bob should not steal alice's active lease")
+	}
+	lease, err := s.GetLease(ctx, issue.ID)
+	if err != nil || lease == nil || lease.Holder != "alice" || lease.TTL != time.Hour {
+		t.Errorf("GetLease = %+v, %v", lease, err)
+	}
+
+	// An expired lease reopens the issue
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{
+		"status": string(types.StatusInProgress), "assignee": "alice",
+	}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE issue_leases SET expires_at = now() - interval '1 minute'`); err != nil {
+		t.Fatalf("failed to expire lease: %v", err)
+	}
+	reopened, err := s.ExpireLeases(ctx, "reaper")
+	if err != nil {
+		t.Fatalf("ExpireLeases failed: %v", err)
+	}
+	if len(reopened) != 1 || reopened[0] != issue.ID {
+		t.Errorf("reopened = %v, want [%s]", reopened, issue.ID)
+	}
+	got, _ := s.GetIssue(ctx, issue.ID)
+	if got.Status != types.StatusOpen || got.Assignee != "" {
+		t.Errorf("after expiry: status=%s assignee=%q", got.Status, got.Assignee)
+	}
+	if !acquire("bob", time.Hour) {
+		t.Error("bob should acquire after expiry")
+	}
+}
+
+func TestConcurrentCreates(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	const writers = 8
+	const perWriter = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				// Identical titles force the hash ID collision path
+				if err := s.CreateIssue(ctx, newIssue("same title"), fmt.Sprintf("writer-%d", w%2)); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent CreateIssue failed: %v", err)
+	}
+
+	stats, err := s.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if stats.TotalIssues != writers*perWriter {
+		t.Errorf("TotalIssues = %d, want %d", stats.TotalIssues, writers*perWriter)
+	}
+}
+
+func TestUpdateIssueIDCascades(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+	a := createTestIssue(t, s, "A")
+	b := createTestIssue(t, s, "B")
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: a.ID, DependsOnID: b.ID, Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := s.AddLabel(ctx, b.ID, "keep", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	if err := s.UpdateIssueID(ctx, b.ID, "test-renamed", b, "tester"); err != nil {
+		t.Fatalf("UpdateIssueID failed: %v", err)
+	}
+	labels, _ := s.GetLabels(ctx, "test-renamed")
+	if len(labels) != 1 {
+		t.Errorf("labels after rename = %v, want [keep]", labels)
+	}
+	deps, err := s.GetDependencyRecords(ctx, a.ID)
+	if err != nil || len(deps) != 1 || deps[0].DependsOnID != "test-renamed" {
+		t.Errorf("deps after rename = %+v, %v", deps, err)
+	}
+}
+
+func TestCopyFromSQLite(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	src, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer func() { _ = src.Close() }()
+	if err := src.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	parent := newIssue("Parent")
+	if err := src.CreateIssue(ctx, parent, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	child := newIssue("Child")
+	child.ID = parent.ID + ".3"
+	if err := src.CreateIssue(ctx, child, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := src.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: parent.ID, Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := src.AddLabel(ctx, child.ID, "copied", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if _, err := src.AddIssueComment(ctx, parent.ID, "alice", "note"); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+
+	stats, err := s.CopyFrom(ctx, src)
+	if err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if stats.Issues != 2 || stats.Dependencies != 1 || stats.Labels != 1 || stats.Comments != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	got, err := s.GetIssue(ctx, child.ID)
+	if err != nil || got == nil || got.ContentHash != child.ContentHash {
+		t.Errorf("copied child = %+v, %v", got, err)
+	}
+	if isBlocked, _, _ := s.IsBlocked(ctx, child.ID); !isBlocked {
+		t.Error("blocked cache not rebuilt after copy")
+	}
+	next, err := s.GetNextChildID(ctx, parent.ID)
+	if err != nil || next != parent.ID+".4" {
+		t.Errorf("GetNextChildID after copy = %q, %v; want %s.4", next, err, parent.ID)
+	}
+
+	if _, err := s.CopyFrom(ctx, src); err == nil {
+		t.Error("CopyFrom into a non-empty store should fail")
+	}
+}
@@ -0,0 +1,616 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// activeStatuses are the statuses in which an issue blocks its dependents.
+const activeStatuses = `('open', 'in_progress', 'blocked', 'deferred', 'hooked')`
+
+// descendantsClause matches issues below the parent in placeholder ph,
+// following parent-child dependencies recursively.
+func descendantsClause(column, ph string) string {
+	return fmt.Sprintf(`%s IN (
+		WITH RECURSIVE descendants AS (
+			SELECT issue_id FROM dependencies
+			WHERE type = 'parent-child' AND depends_on_id = %s
+			UNION
+			SELECT d.issue_id FROM dependencies d
+			JOIN descendants dt ON d.depends_on_id = dt.issue_id
+			WHERE d.type = 'parent-child'
+		)
+		SELECT issue_id FROM descendants
+	)`, column, ph)
+}
+
+// SearchIssues finds issues matching query and filters.
+// Text matching is case-insensitive, like SQLite's LIKE.
+func (s *PostgresStore) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	return searchIssues(ctx, s.db, query, filter)
+}
+
+func searchIssues(ctx context.Context, q dbtx, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	var args queryArgs
+	var where []string
+
+	if query != "" {
+		ph := args.add("%" + query + "%")
+		where = append(where, fmt.Sprintf("(title ILIKE %s OR description ILIKE %s OR id ILIKE %s)", ph, ph, ph))
+	}
+	if filter.TitleSearch != "" {
+		where = append(where, "title ILIKE "+args.add("%"+filter.TitleSearch+"%"))
+	}
+	if filter.TitleContains != "" {
+		where = append(where, "title ILIKE "+args.add("%"+filter.TitleContains+"%"))
+	}
+	if filter.DescriptionContains != "" {
+		where = append(where, "description ILIKE "+args.add("%"+filter.DescriptionContains+"%"))
+	}
+	if filter.NotesContains != "" {
+		where = append(where, "notes ILIKE "+args.add("%"+filter.NotesContains+"%"))
+	}
+
+	if filter.Status != nil {
+		where = append(where, "status = "+args.add(string(*filter.Status)))
+	} else if !filter.IncludeTombstones {
+		where = append(where, "status != "+args.add(string(types.StatusTombstone)))
+	}
+	if len(filter.ExcludeStatus) > 0 {
+		values := make([]interface{}, len(filter.ExcludeStatus))
+		for i, st := range filter.ExcludeStatus {
+			values[i] = string(st)
+		}
+		where = append(where, fmt.Sprintf("status NOT IN (%s)", args.list(values...)))
+	}
+	if len(filter.ExcludeTypes) > 0 {
+		values := make([]interface{}, len(filter.ExcludeTypes))
+		for i, t := range filter.ExcludeTypes {
+			values[i] = string(t)
+		}
+		where = append(where, fmt.Sprintf("issue_type NOT IN (%s)", args.list(values...)))
+	}
+
+	if filter.Priority != nil {
+		where = append(where, "priority = "+args.add(*filter.Priority))
+	}
+	if filter.PriorityMin != nil {
+		where = append(where, "priority >= "+args.add(*filter.PriorityMin))
+	}
+	if filter.PriorityMax != nil {
+		where = append(where, "priority <= "+args.add(*filter.PriorityMax))
+	}
+	if filter.IssueType != nil {
+		where = append(where, "issue_type = "+args.add(string(*filter.IssueType)))
+	}
+	if filter.Assignee != nil {
+		where = append(where, "assignee = "+args.add(*filter.Assignee))
+	}
+
+	timeFilters := []struct {
+		clause string
+		value  *time.Time
+	}{
+		{"created_at >", filter.CreatedAfter},
+		{"created_at <", filter.CreatedBefore},
+		{"updated_at >", filter.UpdatedAfter},
+		{"updated_at <", filter.UpdatedBefore},
+		{"closed_at >", filter.ClosedAfter},
+		{"closed_at <", filter.ClosedBefore},
+		{"defer_until >", filter.DeferAfter},
+		{"defer_until <", filter.DeferBefore},
+		{"due_at >", filter.DueAfter},
+		{"due_at <", filter.DueBefore},
+	}
+	for _, tf := range timeFilters {
+		if tf.value != nil {
+			where = append(where, tf.clause+" "+args.add(*tf.value))
+		}
+	}
+
+	if filter.EmptyDescription {
+		where = append(where, "(description IS NULL OR description = '')")
+	}
+	if filter.NoAssignee {
+		where = append(where, "(assignee IS NULL OR assignee = '')")
+	}
+	if filter.NoLabels {
+		where = append(where, "NOT EXISTS (SELECT 1 FROM labels WHERE issue_id = issues.id)")
+	}
+	for _, label := range filter.Labels {
+		where = append(where, "EXISTS (SELECT 1 FROM labels WHERE issue_id = issues.id AND label = "+args.add(label)+")")
+	}
+	if len(filter.LabelsAny) > 0 {
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM labels WHERE issue_id = issues.id AND label IN (%s))",
+			args.list(stringArgs(filter.LabelsAny)...)))
+	}
+	if len(filter.IDs) > 0 {
+		where = append(where, fmt.Sprintf("id IN (%s)", args.list(stringArgs(filter.IDs)...)))
+	}
+	if filter.IDPrefix != "" {
+		where = append(where, "id LIKE "+args.add(filter.IDPrefix+"%"))
+	}
+
+	if filter.Ephemeral != nil {
+		if *filter.Ephemeral {
+			where = append(where, "ephemeral")
+		} else {
+			where = append(where, "NOT COALESCE(ephemeral, FALSE)")
+		}
+	}
+	if filter.Pinned != nil {
+		if *filter.Pinned {
+			where = append(where, "pinned")
+		} else {
+			where = append(where, "NOT COALESCE(pinned, FALSE)")
+		}
+	}
+	if filter.IsTemplate != nil {
+		if *filter.IsTemplate {
+			where = append(where, "is_template")
+		} else {
+			where = append(where, "NOT COALESCE(is_template, FALSE)")
+		}
+	}
+
+	if filter.ParentID != nil {
+		where = append(where, "id IN (SELECT issue_id FROM dependencies WHERE type = 'parent-child' AND depends_on_id = "+args.add(*filter.ParentID)+")")
+	}
+	if filter.MolType != nil {
+		where = append(where, "mol_type = "+args.add(string(*filter.MolType)))
+	}
+	if filter.Deferred {
+		where = append(where, "defer_until IS NOT NULL")
+	}
+	if filter.Overdue {
+		where = append(where, fmt.Sprintf("due_at IS NOT NULL AND due_at < now() AND status != %s", args.add(string(types.StatusClosed))))
+	}
+
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = "WHERE " + strings.Join(where, " AND ")
+	}
+	limitSQL := ""
+	if filter.Limit > 0 {
+		limitSQL = "LIMIT " + args.add(filter.Limit)
+	}
+
+	// nolint:gosec // G201: clauses are built from constants, values are passed via args
+	rows, err := q.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM issues
+		%s
+		ORDER BY priority ASC, created_at DESC
+		%s
+	`, strings.Join(issueRowColumns, ", "), whereSQL, limitSQL), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	return scanIssues(ctx, q, rows)
+}
+
+// GetReadyWork returns open issues with no active blockers
+func (s *PostgresStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	var args queryArgs
+	where := []string{
+		"NOT COALESCE(i.pinned, FALSE)",
+		"NOT COALESCE(i.ephemeral, FALSE)",
+		"i.id NOT LIKE '%-wisp-%'",
+	}
+
+	if filter.Status == "" {
+		where = append(where, "i.status IN ('open', 'in_progress')")
+	} else {
+		where = append(where, "i.status = "+args.add(string(filter.Status)))
+	}
+
+	if filter.Type != "" {
+		where = append(where, "i.issue_type = "+args.add(filter.Type))
+	} else {
+		// Workflow types are internal bookkeeping, not claimable work
+		where = append(where, "i.issue_type NOT IN ('merge-request', 'gate', 'molecule', 'message', 'agent', 'role', 'rig')")
+	}
+
+	if filter.Priority != nil {
+		where = append(where, "i.priority = "+args.add(*filter.Priority))
+	}
+
+	// Unassigned takes precedence over Assignee filter
+	if filter.Unassigned {
+		where = append(where, "(i.assignee IS NULL OR i.assignee = '')")
+	} else if filter.Assignee != nil {
+		where = append(where, "i.assignee = "+args.add(*filter.Assignee))
+	}
+
+	for _, label := range filter.Labels {
+		where = append(where, "EXISTS (SELECT 1 FROM labels WHERE issue_id = i.id AND label = "+args.add(label)+")")
+	}
+	if len(filter.LabelsAny) > 0 {
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM labels WHERE issue_id = i.id AND label IN (%s))",
+			args.list(stringArgs(filter.LabelsAny)...)))
+	}
+
+	if filter.ParentID != nil {
+		where = append(where, descendantsClause("i.id", args.add(*filter.ParentID)))
+	}
+	if filter.MolType != nil {
+		where = append(where, "i.mol_type = "+args.add(string(*filter.MolType)))
+	}
+	if !filter.IncludeDeferred {
+		where = append(where, "(i.defer_until IS NULL OR i.defer_until <= now())")
+	}
+
+	// Hide issues under an active lease held by someone else (bd claim)
+	where = append(where, fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM issue_leases l
+		WHERE l.issue_id = i.id AND l.expires_at > now() AND l.holder != %s
+	)`, args.add(filter.LeaseHolder)))
+
+	where = append(where, "NOT EXISTS (SELECT 1 FROM blocked_issues_cache WHERE issue_id = i.id)")
+
+	limitSQL := ""
+	if filter.Limit > 0 {
+		limitSQL = "LIMIT " + args.add(filter.Limit)
+	}
+
+	sortPolicy := filter.SortPolicy
+	if sortPolicy == "" {
+		sortPolicy = types.SortPolicyHybrid
+	}
+
+	// nolint:gosec // G201: clauses are built from constants, values are passed via args
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM issues i
+		WHERE %s
+		%s
+		%s
+	`, issueColumnsWithAlias("i"), strings.Join(where, " AND "), buildOrderByClause(sortPolicy), limitSQL), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ready work: %w", err)
+	}
+	return scanIssues(ctx, s.db, rows)
+}
+
+// buildOrderByClause generates the ORDER BY clause based on sort policy
+func buildOrderByClause(policy types.SortPolicy) string {
+	switch policy {
+	case types.SortPolicyPriority:
+		return `ORDER BY i.priority ASC, i.created_at ASC`
+	case types.SortPolicyOldest:
+		return `ORDER BY i.created_at ASC`
+	default:
+		// Hybrid: issues from the last 48 hours by priority, then older
+		// issues by age
+		return `ORDER BY
+			CASE WHEN i.created_at >= now() - interval '48 hours' THEN 0 ELSE 1 END ASC,
+			CASE WHEN i.created_at >= now() - interval '48 hours' THEN i.priority END ASC,
+			CASE WHEN i.created_at < now() - interval '48 hours' THEN i.created_at END ASC,
+			i.created_at ASC`
+	}
+}
+
+// GetBlockedIssues returns issues that are blocked by dependencies or have
+// status blocked/deferred. Pinned issues are excluded; external: references
+// count as blockers until resolved.
+func (s *PostgresStore) GetBlockedIssues(ctx context.Context, filter types.WorkFilter) ([]*types.BlockedIssue, error) {
+	var args queryArgs
+	filterSQL := ""
+	if filter.ParentID != nil {
+		filterSQL = " AND " + descendantsClause("i.id", args.add(*filter.ParentID))
+	}
+
+	// nolint:gosec // G201: clauses are built from constants, values are passed via args
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s,
+		       (
+		           SELECT string_agg(d.depends_on_id, ',' ORDER BY d.depends_on_id)
+		           FROM dependencies d
+		           LEFT JOIN issues blocker ON blocker.id = d.depends_on_id
+		           WHERE d.issue_id = i.id
+		             AND d.type = 'blocks'
+		             AND (blocker.status IN %s OR d.depends_on_id LIKE 'external:%%')
+		       ) AS blocker_ids
+		FROM issues i
+		WHERE i.status IN %s
+		  AND NOT COALESCE(i.pinned, FALSE)
+		  AND (
+		      i.status IN ('blocked', 'deferred')
+		      OR EXISTS (
+		          SELECT 1 FROM dependencies d2
+		          JOIN issues blocker ON d2.depends_on_id = blocker.id
+		          WHERE d2.issue_id = i.id
+		            AND d2.type = 'blocks'
+		            AND blocker.status IN %s
+		      )
+		      OR EXISTS (
+		          SELECT 1 FROM dependencies d3
+		          WHERE d3.issue_id = i.id
+		            AND d3.type = 'blocks'
+		            AND d3.depends_on_id LIKE 'external:%%'
+		      )
+		  )
+		  %s
+		ORDER BY i.priority ASC
+	`, issueColumnsWithAlias("i"), activeStatuses, activeStatuses, activeStatuses, filterSQL), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var blocked []*types.BlockedIssue
+	for rows.Next() {
+		var row issueRow
+		var blockerList sql.NullString
+		if err := rows.Scan(append(row.dests(), &blockerList)...); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked issue: %w", err)
+		}
+		blockerIDs := []string{}
+		if blockerList.String != "" {
+			blockerIDs = strings.Split(blockerList.String, ",")
+		}
+		blocked = append(blocked, &types.BlockedIssue{
+			Issue:          *row.issue(),
+			BlockedByCount: len(blockerIDs),
+			BlockedBy:      blockerIDs,
+		})
+	}
+	return blocked, rows.Err()
+}
+
+// IsBlocked reports whether an issue is in the blocked cache and, if so,
+// which open issues block it directly
+func (s *PostgresStore) IsBlocked(ctx context.Context, issueID string) (bool, []string, error) {
+	var inCache bool
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM blocked_issues_cache WHERE issue_id = $1)`, issueID,
+	).Scan(&inCache); err != nil {
+		return false, nil, fmt.Errorf("failed to check blocked status: %w", err)
+	}
+	if !inCache {
+		return false, nil, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.depends_on_id
+		FROM dependencies d
+		JOIN issues blocker ON d.depends_on_id = blocker.id
+		WHERE d.issue_id = $1
+		  AND d.type = 'blocks'
+		  AND blocker.status IN `+activeStatuses+`
+		ORDER BY blocker.priority ASC
+	`, issueID)
+	if err != nil {
+		return true, nil, fmt.Errorf("failed to get blockers: %w", err)
+	}
+	blockers, err := scanStrings(rows)
+	if err != nil {
+		return true, nil, fmt.Errorf("failed to scan blocker ID: %w", err)
+	}
+	return true, blockers, nil
+}
+
+// GetNewlyUnblockedByClose returns open issues that were blocked by the
+// closed issue and are no longer blocked by anything
+func (s *PostgresStore) GetNewlyUnblockedByClose(ctx context.Context, closedIssueID string) ([]*types.Issue, error) {
+	// nolint:gosec // G201: column list is a constant
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM issues i
+		JOIN dependencies d ON i.id = d.issue_id
+		WHERE d.depends_on_id = $1
+		  AND d.type = 'blocks'
+		  AND i.status IN ('open', 'in_progress')
+		  AND NOT COALESCE(i.pinned, FALSE)
+		  AND NOT EXISTS (SELECT 1 FROM blocked_issues_cache WHERE issue_id = i.id)
+		ORDER BY i.priority ASC
+	`, issueColumnsWithAlias("i")), closedIssueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newly unblocked issues: %w", err)
+	}
+	return scanIssues(ctx, s.db, rows)
+}
+
+// GetEpicsEligibleForClosure returns all open epics with their completion status
+func (s *PostgresStore) GetEpicsEligibleForClosure(ctx context.Context) ([]*types.EpicStatus, error) {
+	// nolint:gosec // G201: column list is a constant
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		WITH epic_stats AS (
+			SELECT d.depends_on_id AS epic_id,
+			       COUNT(*) AS total_children,
+			       COUNT(*) FILTER (WHERE child.status = 'closed') AS closed_children
+			FROM dependencies d
+			JOIN issues child ON child.id = d.issue_id
+			WHERE d.type = 'parent-child'
+			GROUP BY d.depends_on_id
+		)
+		SELECT %s, COALESCE(es.total_children, 0), COALESCE(es.closed_children, 0)
+		FROM issues i
+		LEFT JOIN epic_stats es ON es.epic_id = i.id
+		WHERE i.issue_type = 'epic'
+		  AND i.status != 'closed'
+		ORDER BY i.priority ASC, i.created_at ASC
+	`, issueColumnsWithAlias("i")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epics: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []*types.EpicStatus
+	for rows.Next() {
+		var row issueRow
+		var total, closed int
+		if err := rows.Scan(append(row.dests(), &total, &closed)...); err != nil {
+			return nil, fmt.Errorf("failed to scan epic: %w", err)
+		}
+		results = append(results, &types.EpicStatus{
+			Epic:             row.issue(),
+			TotalChildren:    total,
+			ClosedChildren:   closed,
+			EligibleForClose: total > 0 && closed == total,
+		})
+	}
+	return results, rows.Err()
+}
+
+// GetStaleIssues returns non-closed issues not updated in filter.Days days
+func (s *PostgresStore) GetStaleIssues(ctx context.Context, filter types.StaleFilter) ([]*types.Issue, error) {
+	var args queryArgs
+	where := []string{
+		"status != 'closed'",
+		"updated_at < now() - make_interval(days => " + args.add(filter.Days) + ")",
+	}
+	if filter.Status != "" {
+		where = append(where, "status = "+args.add(filter.Status))
+	}
+	limitSQL := ""
+	if filter.Limit > 0 {
+		limitSQL = "LIMIT " + args.add(filter.Limit)
+	}
+
+	// nolint:gosec // G201: clauses are built from constants, values are passed via args
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM issues
+		WHERE %s
+		ORDER BY updated_at ASC
+		%s
+	`, strings.Join(issueRowColumns, ", "), strings.Join(where, " AND "), limitSQL), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stale issues: %w", err)
+	}
+	return scanIssues(ctx, s.db, rows)
+}
+
+// GetStatistics returns aggregate statistics
+func (s *PostgresStore) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+	var stats types.Statistics
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status != 'tombstone'),
+			COUNT(*) FILTER (WHERE status = 'open'),
+			COUNT(*) FILTER (WHERE status = 'in_progress'),
+			COUNT(*) FILTER (WHERE status = 'closed'),
+			COUNT(*) FILTER (WHERE status = 'deferred'),
+			COUNT(*) FILTER (WHERE status = 'tombstone'),
+			COUNT(*) FILTER (WHERE pinned)
+		FROM issues
+	`).Scan(&stats.TotalIssues, &stats.OpenIssues, &stats.InProgressIssues, &stats.ClosedIssues,
+		&stats.DeferredIssues, &stats.TombstoneIssues, &stats.PinnedIssues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue counts: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT i.id)
+		FROM issues i
+		JOIN dependencies d ON i.id = d.issue_id
+		JOIN issues blocker ON d.depends_on_id = blocker.id
+		WHERE i.status IN `+activeStatuses+`
+		  AND d.type = 'blocks'
+		  AND blocker.status IN `+activeStatuses+`
+	`).Scan(&stats.BlockedIssues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked count: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM issues i
+		WHERE i.status = 'open'
+		  AND NOT EXISTS (
+		    SELECT 1 FROM dependencies d
+		    JOIN issues blocker ON d.depends_on_id = blocker.id
+		    WHERE d.issue_id = i.id
+		      AND d.type = 'blocks'
+		      AND blocker.status IN `+activeStatuses+`
+		  )
+	`).Scan(&stats.ReadyIssues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ready count: %w", err)
+	}
+
+	var avgLeadTime sql.NullFloat64
+	err = s.db.QueryRowContext(ctx, `
+		SELECT AVG(EXTRACT(EPOCH FROM (closed_at - created_at)) / 3600.0)
+		FROM issues
+		WHERE closed_at IS NOT NULL
+	`).Scan(&avgLeadTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lead time: %w", err)
+	}
+	stats.AverageLeadTime = avgLeadTime.Float64
+
+	err = s.db.QueryRowContext(ctx, `
+		WITH epic_stats AS (
+			SELECT d.depends_on_id AS epic_id,
+			       COUNT(*) AS total_children,
+			       COUNT(*) FILTER (WHERE child.status = 'closed') AS closed_children
+			FROM dependencies d
+			JOIN issues child ON child.id = d.issue_id
+			WHERE d.type = 'parent-child'
+			GROUP BY d.depends_on_id
+		)
+		SELECT COUNT(*)
+		FROM issues i
+		JOIN epic_stats es ON es.epic_id = i.id
+		WHERE i.issue_type = 'epic'
+		  AND i.status != 'closed'
+		  AND es.total_children > 0
+		  AND es.closed_children = es.total_children
+	`).Scan(&stats.EpicsEligibleForClosure)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eligible epics count: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// GetMoleculeProgress returns progress stats for a molecule's direct children
+func (s *PostgresStore) GetMoleculeProgress(ctx context.Context, moleculeID string) (*types.MoleculeProgressStats, error) {
+	var title string
+	err := s.db.QueryRowContext(ctx, `SELECT title FROM issues WHERE id = $1`, moleculeID).Scan(&title)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("molecule not found: %s", moleculeID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get molecule: %w", err)
+	}
+
+	stats := &types.MoleculeProgressStats{
+		MoleculeID:    moleculeID,
+		MoleculeTitle: title,
+	}
+
+	var currentStepID sql.NullString
+	var firstClosed, lastClosed sql.NullTime
+	err = s.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE i.status = 'closed'),
+			COUNT(*) FILTER (WHERE i.status = 'in_progress'),
+			(ARRAY_AGG(i.id ORDER BY i.created_at) FILTER (WHERE i.status = 'in_progress'))[1],
+			MIN(i.closed_at) FILTER (WHERE i.status = 'closed'),
+			MAX(i.closed_at) FILTER (WHERE i.status = 'closed')
+		FROM dependencies d
+		JOIN issues i ON d.issue_id = i.id
+		WHERE d.depends_on_id = $1 AND d.type = 'parent-child'
+	`, moleculeID).Scan(&stats.Total, &stats.Completed, &stats.InProgress, &currentStepID, &firstClosed, &lastClosed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child counts: %w", err)
+	}
+	stats.CurrentStepID = currentStepID.String
+	if firstClosed.Valid {
+		stats.FirstClosed = &firstClosed.Time
+	}
+	if lastClosed.Valid {
+		stats.LastClosed = &lastClosed.Time
+	}
+	return stats, nil
+}
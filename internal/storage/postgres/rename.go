@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// UpdateIssueID renames an issue and rewrites its text fields in a single
+// transaction. Every table keyed on the issue follows through ON UPDATE
+// CASCADE; only depends_on_id, which may hold external refs and so has no
+// foreign key, is rewritten by hand.
+func (s *PostgresStore) UpdateIssueID(ctx context.Context, oldID, newID string, issue *types.Issue, actor string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE issues
+			SET id = $1, title = $2, description = $3, design = $4, acceptance_criteria = $5, notes = $6, updated_at = $7
+			WHERE id = $8
+		`, newID, issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes, time.Now().UTC(), oldID)
+		if err != nil {
+			return fmt.Errorf("failed to update issue ID: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("issue not found: %s", oldID)
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE dependencies SET depends_on_id = $1 WHERE depends_on_id = $2`, newID, oldID); err != nil {
+			return fmt.Errorf("failed to update depends_on_id in dependencies: %w", err)
+		}
+		if err := markDirty(ctx, tx, newID); err != nil {
+			return fmt.Errorf("failed to mark issue dirty: %w", err)
+		}
+		if err := recordEvent(ctx, tx, newID, "renamed", actor, oldID, newID, ""); err != nil {
+			return fmt.Errorf("failed to record rename event: %w", err)
+		}
+		return nil
+	})
+}
+
+// RenameDependencyPrefix updates the prefix in all dependency records
+func (s *PostgresStore) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		for _, column := range []string{"issue_id", "depends_on_id"} {
+			// nolint:gosec // G201: column is one of two constants
+			_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+				UPDATE dependencies
+				SET %[1]s = $1::text || substr(%[1]s, length($2::text) + 1)
+				WHERE starts_with(%[1]s, $2::text)
+			`, column), newPrefix, oldPrefix)
+			if err != nil {
+				return fmt.Errorf("failed to update %s in dependencies: %w", column, err)
+			}
+		}
+		// The blocked cache stores issue IDs that have now been renamed
+		if err := rebuildBlockedCache(ctx, tx); err != nil {
+			return fmt.Errorf("failed to rebuild blocked cache: %w", err)
+		}
+		return nil
+	})
+}
+
+// RenameCounterPrefix is a no-op: child counters are keyed on the parent
+// issue and follow UpdateIssueID through ON UPDATE CASCADE.
+func (s *PostgresStore) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	return nil
+}
@@ -0,0 +1,272 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migration is one numbered schema change. Migrations are applied in order
+// and recorded in schema_migrations; never edit one that has shipped, add a
+// new one instead.
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// migrations lists every schema change, oldest first.
+var migrations = []migration{
+	{1, "initial_schema", initialSchema},
+	{2, "default_config", defaultConfig},
+	{3, "commit_links", commitLinksSchema},
+}
+
+// migrationLockKey serializes concurrent `bd` processes migrating the same
+// database on first use.
+const migrationLockKey = 0x62656164 + 1
+
+// runMigrations applies all pending migrations, each in its own transaction.
+func runMigrations(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockKey); err != nil {
+		return err
+	}
+
+	var applied bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, m.version,
+	).Scan(&applied); err != nil {
+		return err
+	}
+	if applied {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.version, m.name,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SchemaVersion returns the highest applied migration version.
+func (s *PostgresStore) SchemaVersion(ctx context.Context) (int, error) {
+	var version sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+// initialSchema mirrors the SQLite schema using PostgreSQL types.
+// dependencies.depends_on_id has no foreign key so that external:
+// references can be stored; DeleteIssue cleans up both directions.
+const initialSchema = `
+CREATE TABLE issues (
+    id TEXT PRIMARY KEY,
+    content_hash TEXT,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    design TEXT NOT NULL DEFAULT '',
+    acceptance_criteria TEXT NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    priority INTEGER NOT NULL DEFAULT 2,
+    issue_type TEXT NOT NULL DEFAULT 'task',
+    assignee TEXT,
+    estimated_minutes INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_by TEXT DEFAULT '',
+    owner TEXT DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    closed_at TIMESTAMPTZ,
+    closed_by_session TEXT DEFAULT '',
+    external_ref TEXT,
+    compaction_level INTEGER DEFAULT 0,
+    compacted_at TIMESTAMPTZ,
+    compacted_at_commit TEXT,
+    original_size INTEGER,
+    deleted_at TIMESTAMPTZ,
+    deleted_by TEXT DEFAULT '',
+    delete_reason TEXT DEFAULT '',
+    original_type TEXT DEFAULT '',
+    sender TEXT DEFAULT '',
+    ephemeral BOOLEAN DEFAULT FALSE,
+    pinned BOOLEAN DEFAULT FALSE,
+    is_template BOOLEAN DEFAULT FALSE,
+    crystallizes BOOLEAN DEFAULT FALSE,
+    mol_type TEXT DEFAULT '',
+    work_type TEXT DEFAULT 'mutex',
+    quality_score DOUBLE PRECISION,
+    source_system TEXT DEFAULT '',
+    source_repo TEXT DEFAULT '',
+    close_reason TEXT DEFAULT '',
+    event_kind TEXT DEFAULT '',
+    actor TEXT DEFAULT '',
+    target TEXT DEFAULT '',
+    payload TEXT DEFAULT '',
+    await_type TEXT DEFAULT '',
+    await_id TEXT DEFAULT '',
+    timeout_ns BIGINT DEFAULT 0,
+    waiters TEXT DEFAULT '',
+    hook_bead TEXT DEFAULT '',
+    role_bead TEXT DEFAULT '',
+    agent_state TEXT DEFAULT '',
+    last_activity TIMESTAMPTZ,
+    role_type TEXT DEFAULT '',
+    rig TEXT DEFAULT '',
+    due_at TIMESTAMPTZ,
+    defer_until TIMESTAMPTZ
+);
+CREATE INDEX idx_issues_status ON issues (status);
+CREATE INDEX idx_issues_priority ON issues (priority);
+CREATE INDEX idx_issues_assignee ON issues (assignee);
+CREATE INDEX idx_issues_created_at ON issues (created_at);
+CREATE INDEX idx_issues_external_ref ON issues (external_ref);
+
+CREATE TABLE dependencies (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    depends_on_id TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'blocks',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_by TEXT NOT NULL DEFAULT '',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    thread_id TEXT DEFAULT '',
+    PRIMARY KEY (issue_id, depends_on_id)
+);
+CREATE INDEX idx_dependencies_depends_on ON dependencies (depends_on_id);
+CREATE INDEX idx_dependencies_depends_on_type ON dependencies (depends_on_id, type);
+CREATE INDEX idx_dependencies_thread ON dependencies (thread_id);
+
+CREATE TABLE labels (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    label TEXT NOT NULL,
+    PRIMARY KEY (issue_id, label)
+);
+CREATE INDEX idx_labels_label ON labels (label);
+
+CREATE TABLE comments (
+    id BIGSERIAL PRIMARY KEY,
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    author TEXT NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_comments_issue ON comments (issue_id);
+
+CREATE TABLE events (
+    id BIGSERIAL PRIMARY KEY,
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    event_type TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+CREATE INDEX idx_events_issue ON events (issue_id);
+CREATE INDEX idx_events_created_at ON events (created_at);
+
+CREATE TABLE config (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+CREATE TABLE metadata (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+CREATE TABLE dirty_issues (
+    issue_id TEXT PRIMARY KEY REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    marked_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_dirty_issues_marked_at ON dirty_issues (marked_at);
+
+CREATE TABLE export_hashes (
+    issue_id TEXT PRIMARY KEY REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    content_hash TEXT NOT NULL,
+    exported_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE child_counters (
+    parent_id TEXT PRIMARY KEY REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    last_child INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE issue_leases (
+    issue_id TEXT PRIMARY KEY REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    holder TEXT NOT NULL,
+    acquired_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    ttl_ns BIGINT NOT NULL
+);
+CREATE INDEX idx_issue_leases_expires_at ON issue_leases (expires_at);
+
+-- Materialized set of issues blocked by open dependencies, maintained by
+-- rebuildBlockedCache (see blocked_cache.go)
+CREATE TABLE blocked_issues_cache (
+    issue_id TEXT PRIMARY KEY REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+`
+
+// defaultConfig contains the default configuration values
+const defaultConfig = `
+INSERT INTO config (key, value) VALUES
+    ('compaction_enabled', 'false'),
+    ('compact_tier1_days', '30'),
+    ('compact_tier1_dep_levels', '2'),
+    ('compact_tier2_days', '90'),
+    ('compact_tier2_dep_levels', '5'),
+    ('compact_tier2_commits', '100'),
+    ('compact_model', 'claude-3-5-haiku-20241022'),
+    ('compact_batch_size', '50'),
+    ('compact_parallel_workers', '5'),
+    ('auto_compact_enabled', 'false')
+ON CONFLICT (key) DO NOTHING;
+`
+
+// commitLinksSchema adds the table of git commits that mention issues
+// (bd commits). Links are derived from git history and never exported.
+const commitLinksSchema = `
+CREATE TABLE commit_links (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    commit_sha TEXT NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    subject TEXT NOT NULL DEFAULT '',
+    files TEXT NOT NULL DEFAULT '',
+    keyword TEXT NOT NULL DEFAULT 'mentions',
+    committed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (issue_id, commit_sha)
+);
+CREATE INDEX idx_commit_links_commit_sha ON commit_links (commit_sha);
+`
@@ -0,0 +1,194 @@
+// Package postgres implements the storage interface using PostgreSQL.
+//
+// The PostgreSQL backend is meant for teams that already run a Postgres
+// server and want many concurrent writers (agents, daemons, CI) on one
+// issue database without running a Dolt server.
+//
+// Key differences from the SQLite backend:
+//   - Uses github.com/jackc/pgx/v5 through database/sql
+//   - Schema is managed by numbered migrations recorded in schema_migrations
+//   - Writers that generate IDs or rebuild the blocked-issues cache are
+//     serialized with a transaction-scoped advisory lock instead of
+//     SQLite's BEGIN IMMEDIATE; all other writes run fully concurrently
+//   - There is no local database file: Path() returns a marker path inside
+//     .beads that only identifies the workspace
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	// Import pgx database/sql driver ("pgx")
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+// ErrUnreachable is returned by New when the server can't be reached or
+// refuses the connection (server down, wrong host or port, bad credentials).
+var ErrUnreachable = errors.New("postgres server unreachable")
+
+// PostgresStore implements the Storage interface using PostgreSQL
+type PostgresStore struct {
+	db       *sql.DB
+	path     string      // Marker path reported by Path()
+	closed   atomic.Bool // Tracks whether Close() has been called
+	readOnly bool        // True if opened in read-only mode
+}
+
+// Config holds PostgreSQL connection configuration
+type Config struct {
+	// DSN is a libpq connection string or postgres:// URL. Settings it
+	// leaves out fall back to the standard PG* environment variables and
+	// ~/.pgpass, like psql.
+	DSN string
+
+	// Path is returned by Path(). It doesn't need to exist; commands use
+	// it to locate the .beads directory of the workspace.
+	Path string
+
+	ReadOnly bool // Open in read-only mode (skip migrations)
+
+	MaxOpenConns int // Connection pool size (default: 10)
+}
+
+// New opens a PostgreSQL storage backend and brings its schema up to date
+func New(ctx context.Context, cfg *Config) (*PostgresStore, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres connection string is required")
+	}
+
+	db, err := sql.Open("pgx", cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	maxOpen := cfg.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = 10
+	}
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxOpen)
+	db.SetConnMaxLifetime(30 * time.Minute)
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+
+	store := &PostgresStore{
+		db:       db,
+		path:     cfg.Path,
+		readOnly: cfg.ReadOnly,
+	}
+
+	if !cfg.ReadOnly {
+		if err := runMigrations(ctx, db); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to migrate schema: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// Close closes the connection pool
+func (s *PostgresStore) Close() error {
+	s.closed.Store(true)
+	return s.db.Close()
+}
+
+// IsClosed returns true if Close() has been called
+func (s *PostgresStore) IsClosed() bool {
+	return s.closed.Load()
+}
+
+// Path returns the marker path of the workspace
+func (s *PostgresStore) Path() string {
+	return s.path
+}
+
+// UnderlyingDB returns the underlying *sql.DB connection pool
+func (s *PostgresStore) UnderlyingDB() *sql.DB {
+	return s.db
+}
+
+// UnderlyingConn returns a single connection from the pool
+func (s *PostgresStore) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
+	return s.db.Conn(ctx)
+}
+
+// Ensure PostgresStore implements storage.Storage
+var _ storage.Storage = (*PostgresStore)(nil)
+
+// dbtx is satisfied by *sql.DB, *sql.Tx and *sql.Conn. Store methods and
+// the Transaction implementation share query helpers through it.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// withTx runs fn in a transaction, committing if it returns nil.
+func (s *PostgresStore) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// writeLockKey is the advisory lock taken by writes that must not run
+// concurrently: hash ID generation (check-then-insert) and blocked cache
+// rebuilds (delete-then-insert of the whole table).
+const writeLockKey = 0x62656164 // "bead"
+
+// lockWrites takes the write lock for the rest of the transaction. It is
+// reentrant within one transaction.
+func lockWrites(ctx context.Context, tx dbtx) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, writeLockKey); err != nil {
+		return fmt.Errorf("failed to acquire write lock: %w", err)
+	}
+	return nil
+}
+
+// queryArgs collects the arguments of a dynamically built query and hands
+// out their $n placeholders.
+type queryArgs []interface{}
+
+// add appends v and returns its placeholder.
+func (a *queryArgs) add(v interface{}) string {
+	*a = append(*a, v)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// list appends every value and returns their comma-separated placeholders.
+func (a *queryArgs) list(values ...interface{}) string {
+	ph := make([]string, len(values))
+	for i, v := range values {
+		ph[i] = a.add(v)
+	}
+	return strings.Join(ph, ", ")
+}
+
+// stringArgs converts a string slice for queryArgs.list.
+func stringArgs(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// pgTransaction implements storage.Transaction on a single *sql.Tx. Every
+// method reuses the same shared implementation as the store, so behaviour
+// inside and outside a transaction is identical.
+type pgTransaction struct {
+	tx *sql.Tx
+}
+
+var _ storage.Transaction = (*pgTransaction)(nil)
+
+// RunInTransaction executes a function within a database transaction.
+// Writes that generate IDs or touch the blocked cache serialize on an
+// advisory lock, so concurrent transactions from other processes queue up
+// instead of failing.
+func (s *PostgresStore) RunInTransaction(ctx context.Context, fn func(tx storage.Transaction) error) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return fn(&pgTransaction{tx: tx})
+	})
+}
+
+func (t *pgTransaction) customStatusesAndTypes(ctx context.Context) ([]string, []string, error) {
+	customStatuses, err := getCustomList(ctx, t.tx, "status.custom")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get custom statuses: %w", err)
+	}
+	customTypes, err := getCustomList(ctx, t.tx, "types.custom")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get custom types: %w", err)
+	}
+	return customStatuses, customTypes, nil
+}
+
+// CreateIssue creates an issue within the transaction
+func (t *pgTransaction) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	customStatuses, customTypes, err := t.customStatusesAndTypes(ctx)
+	if err != nil {
+		return err
+	}
+	return createIssue(ctx, t.tx, issue, actor, customStatuses, customTypes)
+}
+
+// CreateIssues creates multiple issues within the transaction
+func (t *pgTransaction) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	customStatuses, customTypes, err := t.customStatusesAndTypes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		if err := createIssue(ctx, t.tx, issue, actor, customStatuses, customTypes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateIssue updates an issue within the transaction
+func (t *pgTransaction) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	customStatuses, _, err := t.customStatusesAndTypes(ctx)
+	if err != nil {
+		return err
+	}
+	return updateIssue(ctx, t.tx, id, updates, actor, customStatuses)
+}
+
+// CloseIssue closes an issue within the transaction
+func (t *pgTransaction) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	return closeIssue(ctx, t.tx, id, reason, actor, session)
+}
+
+// DeleteIssue deletes an issue within the transaction
+func (t *pgTransaction) DeleteIssue(ctx context.Context, id string) error {
+	return deleteIssue(ctx, t.tx, id)
+}
+
+// GetIssue retrieves an issue within the transaction
+func (t *pgTransaction) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	return getIssue(ctx, t.tx, id)
+}
+
+// SearchIssues searches for issues within the transaction
+func (t *pgTransaction) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	return searchIssues(ctx, t.tx, query, filter)
+}
+
+// AddDependency adds a dependency within the transaction
+func (t *pgTransaction) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	return addDependency(ctx, t.tx, dep, actor)
+}
+
+// RemoveDependency removes a dependency within the transaction
+func (t *pgTransaction) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	return removeDependency(ctx, t.tx, issueID, dependsOnID, actor)
+}
+
+// AddLabel adds a label within the transaction
+func (t *pgTransaction) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	return addLabel(ctx, t.tx, issueID, label, actor)
+}
+
+// RemoveLabel removes a label within the transaction
+func (t *pgTransaction) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	return removeLabel(ctx, t.tx, issueID, label, actor)
+}
+
+// SetConfig sets a config value within the transaction
+func (t *pgTransaction) SetConfig(ctx context.Context, key, value string) error {
+	return setKeyValue(ctx, t.tx, "config", key, value)
+}
+
+// GetConfig gets a config value within the transaction
+func (t *pgTransaction) GetConfig(ctx context.Context, key string) (string, error) {
+	return getKeyValue(ctx, t.tx, "config", key)
+}
+
+// SetMetadata sets a metadata value within the transaction
+func (t *pgTransaction) SetMetadata(ctx context.Context, key, value string) error {
+	return setKeyValue(ctx, t.tx, "metadata", key, value)
+}
+
+// GetMetadata gets a metadata value within the transaction
+func (t *pgTransaction) GetMetadata(ctx context.Context, key string) (string, error) {
+	return getKeyValue(ctx, t.tx, "metadata", key)
+}
+
+// AddComment adds a comment event within the transaction
+func (t *pgTransaction) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	return addComment(ctx, t.tx, issueID, actor, comment)
+}
+
+// AcquireLease grants holder a lease on issueID within the transaction
+func (t *pgTransaction) AcquireLease(ctx context.Context, issueID, holder string, ttl time.Duration) (bool, error) {
+	return acquireLease(ctx, t.tx, issueID, holder, ttl)
+}
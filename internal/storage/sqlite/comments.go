@@ -49,6 +49,11 @@ func (s *SQLiteStorage) AddIssueComment(ctx context.Context, issueID, author, te
 		return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
 	}
 
+	// Commenting counts as activity for the lease holder
+	if err := renewLease(ctx, s.db, issueID, author); err != nil {
+		return nil, fmt.Errorf("failed to renew lease: %w", err)
+	}
+
 	return comment, nil
 }
 
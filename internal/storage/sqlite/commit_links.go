@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// AddCommitLinks records commits that mention issues. Re-adding a link for
+// the same issue and commit replaces it, so re-indexing history is safe.
+func (s *SQLiteStorage) AddCommitLinks(ctx context.Context, links []*types.CommitLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return wrapDBError("begin commit link transaction", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, link := range links {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO commit_links (issue_id, commit_sha, author, subject, files, keyword, committed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (issue_id, commit_sha) DO UPDATE SET
+				author = excluded.author,
+				subject = excluded.subject,
+				files = excluded.files,
+				keyword = excluded.keyword,
+				committed_at = excluded.committed_at
+		`, link.IssueID, link.CommitSHA, link.Author, link.Subject, formatJSONStringArray(link.Files),
+			string(link.Keyword), link.CommittedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return wrapDBErrorf(err, "add commit link %s -> %s", link.CommitSHA, link.IssueID)
+		}
+	}
+	return wrapDBError("commit commit links", tx.Commit())
+}
+
+// GetCommitLinks returns the commits that mention an issue, oldest first.
+func (s *SQLiteStorage) GetCommitLinks(ctx context.Context, issueID string) ([]*types.CommitLink, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT issue_id, commit_sha, author, subject, files, keyword, committed_at
+		FROM commit_links WHERE issue_id = ?
+		ORDER BY committed_at, commit_sha
+	`, issueID)
+	if err != nil {
+		return nil, wrapDBError("get commit links", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var links []*types.CommitLink
+	for rows.Next() {
+		var link types.CommitLink
+		var files, keyword, committedAt string
+		if err := rows.Scan(&link.IssueID, &link.CommitSHA, &link.Author, &link.Subject, &files, &keyword, &committedAt); err != nil {
+			return nil, wrapDBError("scan commit link", err)
+		}
+		link.Files = parseJSONStringArray(files)
+		link.Keyword = types.CommitKeyword(keyword)
+		link.CommittedAt = parseTimeString(committedAt)
+		links = append(links, &link)
+	}
+	return links, wrapDBError("iterate commit links", rows.Err())
+}
@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/storagetest"
+)
+
+// TestConformance runs the shared storage suite. SQLite is the reference
+// backend, so it declares no gaps.
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, storagetest.Backend{
+		New: func(t *testing.T) storage.Storage {
+			store, err := New(context.Background(), filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("failed to create storage: %v", err)
+			}
+			t.Cleanup(func() { _ = store.Close() })
+			return store
+		},
+	})
+}
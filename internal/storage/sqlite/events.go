@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
@@ -53,6 +54,22 @@ func (s *SQLiteStorage) AddComment(ctx context.Context, issueID, actor, comment
 	})
 }
 
+// AddEvent records an audit event for an issue. Unlike AddComment it leaves
+// the issue itself (and its updated_at) alone.
+func (s *SQLiteStorage) AddEvent(ctx context.Context, event *types.Event) error {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, event.IssueID, event.EventType, event.Actor, event.OldValue, event.NewValue, event.Comment)
+	if err != nil {
+		return fmt.Errorf("failed to add event: %w", err)
+	}
+	return nil
+}
+
 // GetEvents returns the event history for an issue
 func (s *SQLiteStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
 	// Hold read lock during database operations to prevent reconnect() from
@@ -82,6 +99,58 @@ func (s *SQLiteStorage) GetEvents(ctx context.Context, issueID string, limit int
 	}
 	defer func() { _ = rows.Close() }()
 
+	return scanEvents(rows)
+}
+
+// SearchEvents returns events across issues matching the filter, oldest first
+func (s *SQLiteStorage) SearchEvents(ctx context.Context, filter types.EventFilter) ([]*types.Event, error) {
+	s.reconnectMu.RLock()
+	defer s.reconnectMu.RUnlock()
+
+	where := []string{"1 = 1"}
+	var args []interface{}
+	if len(filter.IssueIDs) > 0 {
+		where = append(where, fmt.Sprintf("issue_id IN (%s)", buildPlaceholders(len(filter.IssueIDs))))
+		for _, id := range filter.IssueIDs {
+			args = append(args, id)
+		}
+	}
+	if len(filter.Types) > 0 {
+		where = append(where, fmt.Sprintf("event_type IN (%s)", buildPlaceholders(len(filter.Types))))
+		for _, t := range filter.Types {
+			args = append(args, string(t))
+		}
+	}
+	// Event timestamps come from CURRENT_TIMESTAMP, so compare as datetimes
+	// rather than as text in mixed formats
+	if filter.Since != nil {
+		where = append(where, "datetime(created_at) >= datetime(?)")
+		args = append(args, filter.Since.UTC().Format(time.RFC3339))
+	}
+	if filter.Until != nil {
+		where = append(where, "datetime(created_at) <= datetime(?)")
+		args = append(args, filter.Until.UTC().Format(time.RFC3339))
+	}
+
+	// #nosec G201 - safe SQL with controlled formatting
+	query := fmt.Sprintf(`
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
+		FROM events
+		WHERE %s
+		ORDER BY created_at ASC, id ASC
+	`, strings.Join(where, " AND "))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return scanEvents(rows)
+}
+
+// scanEvents reads event rows selected in the standard column order
+func scanEvents(rows *sql.Rows) ([]*types.Event, error) {
 	var events []*types.Event
 	for rows.Next() {
 		var event types.Event
@@ -108,7 +177,7 @@ func (s *SQLiteStorage) GetEvents(ctx context.Context, issueID string, limit int
 		events = append(events, &event)
 	}
 
-	return events, nil
+	return events, rows.Err()
 }
 
 // GetStatistics returns aggregate statistics
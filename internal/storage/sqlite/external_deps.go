@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/configfile"
@@ -27,6 +28,10 @@ type ExternalDepStatus struct {
 	Capability string // Parsed capability name
 	Satisfied  bool   // Whether the dependency is satisfied
 	Reason     string // Human-readable reason if not satisfied
+
+	// SatisfiedAt is when the capability shipped: the earliest close of a
+	// closed issue providing it. Only set by CheckExternalDeps.
+	SatisfiedAt *time.Time
 }
 
 // CheckExternalDep checks if a single external dependency is satisfied.
@@ -159,7 +164,7 @@ func CheckExternalDeps(ctx context.Context, refs []string) map[string]*ExternalD
 		}
 
 		// Check all capabilities for this project in one DB open
-		satisfied := checkProjectCapabilities(ctx, project, capList)
+		satisfied, shippedAt := checkProjectCapabilities(ctx, project, capList)
 
 		// Map results back to original refs
 		for cap, refList := range caps {
@@ -171,11 +176,12 @@ func CheckExternalDeps(ctx context.Context, refs []string) map[string]*ExternalD
 
 			for _, ref := range refList {
 				results[ref] = &ExternalDepStatus{
-					Ref:        ref,
-					Project:    project,
-					Capability: cap,
-					Satisfied:  isSatisfied,
-					Reason:     reason,
+					Ref:         ref,
+					Project:     project,
+					Capability:  cap,
+					Satisfied:   isSatisfied,
+					Reason:      reason,
+					SatisfiedAt: shippedAt[cap],
 				}
 			}
 		}
@@ -204,46 +210,48 @@ func parseExternalRef(ref string) *parsedRef {
 }
 
 // checkProjectCapabilities opens a project's beads DB once and checks
-// multiple capabilities in a single query. Returns map of capability -> satisfied.
-func checkProjectCapabilities(ctx context.Context, project string, capabilities []string) map[string]bool {
+// multiple capabilities in a single query. Returns map of capability -> satisfied,
+// and for satisfied capabilities the earliest close of an issue providing them.
+func checkProjectCapabilities(ctx context.Context, project string, capabilities []string) (map[string]bool, map[string]*time.Time) {
 	result := make(map[string]bool)
+	shippedAt := make(map[string]*time.Time)
 	for _, cap := range capabilities {
 		result[cap] = false // default to unsatisfied
 	}
 
 	if len(capabilities) == 0 {
-		return result
+		return result, shippedAt
 	}
 
 	// Look up project path from config
 	projectPath := config.ResolveExternalProjectPath(project)
 	if projectPath == "" {
-		return result // all unsatisfied - project not configured
+		return result, shippedAt // all unsatisfied - project not configured
 	}
 
 	// Find the beads database in the project
 	beadsDir := filepath.Join(projectPath, ".beads")
 	cfg, err := configfile.Load(beadsDir)
 	if err != nil || cfg == nil {
-		return result // all unsatisfied - no beads database
+		return result, shippedAt // all unsatisfied - no beads database
 	}
 
 	dbPath := cfg.DatabasePath(beadsDir)
 
 	// Verify database file exists
 	if _, err := os.Stat(dbPath); err != nil {
-		return result // all unsatisfied - database not found
+		return result, shippedAt // all unsatisfied - database not found
 	}
 
 	// Open the external database once for all capability checks
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
-		return result // all unsatisfied - cannot open
+		return result, shippedAt // all unsatisfied - cannot open
 	}
 	defer func() { _ = db.Close() }()
 
 	if err := db.Ping(); err != nil {
-		return result // all unsatisfied - cannot connect
+		return result, shippedAt // all unsatisfied - cannot connect
 	}
 
 	// Build query to check all capabilities at once
@@ -256,10 +264,10 @@ func checkProjectCapabilities(ctx context.Context, project string, capabilities
 		args[i] = "provides:" + cap
 	}
 
-	// Query returns which provides: labels exist on closed issues
+	// Query returns the provides: labels on closed issues and when each closed
 	// #nosec G202 -- placeholders are generated as "?" markers, not user input
 	query := `
-		SELECT DISTINCT l.label FROM labels l
+		SELECT l.label, i.closed_at FROM labels l
 		JOIN issues i ON l.issue_id = i.id
 		WHERE i.status = 'closed'
 		  AND l.label IN (` + strings.Join(placeholders, ",") + `)
@@ -267,24 +275,29 @@ func checkProjectCapabilities(ctx context.Context, project string, capabilities
 
 	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return result // all unsatisfied - query failed
+		return result, shippedAt // all unsatisfied - query failed
 	}
 	defer func() { _ = rows.Close() }()
 
 	// Mark satisfied capabilities
 	for rows.Next() {
 		var label string
-		if err := rows.Scan(&label); err != nil {
+		var closedAt sql.NullTime
+		if err := rows.Scan(&label, &closedAt); err != nil {
 			continue
 		}
 		// Extract capability from "provides:capability"
 		if strings.HasPrefix(label, "provides:") {
 			cap := strings.TrimPrefix(label, "provides:")
 			result[cap] = true
+			if closedAt.Valid && (shippedAt[cap] == nil || closedAt.Time.Before(*shippedAt[cap])) {
+				at := closedAt.Time
+				shippedAt[cap] = &at
+			}
 		}
 	}
 
-	return result
+	return result, shippedAt
 }
 
 // GetUnsatisfiedExternalDeps returns external dependencies that are not satisfied.
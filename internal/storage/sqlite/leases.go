@@ -0,0 +1,174 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// leaseTimeFormat is used for issue_leases timestamps. Values are stored in UTC
+// with fixed-width fractional seconds so that lexicographic comparison in SQL
+// matches chronological order.
+const leaseTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+func formatLeaseTime(t time.Time) string {
+	return t.UTC().Format(leaseTimeFormat)
+}
+
+// AcquireLease grants holder a lease on issueID within the transaction.
+// The upsert only overwrites an existing row if it belongs to the same holder
+// or has expired, so the statement itself is the compare-and-set: zero rows
+// affected means another holder has an active lease.
+func (t *sqliteTxStorage) AcquireLease(ctx context.Context, issueID, holder string, ttl time.Duration) (bool, error) {
+	if holder == "" {
+		return false, fmt.Errorf("lease holder is required")
+	}
+	if ttl <= 0 {
+		return false, fmt.Errorf("lease ttl must be positive (got %s)", ttl)
+	}
+
+	now := time.Now()
+	result, err := t.conn.ExecContext(ctx, `
+		INSERT INTO issue_leases (issue_id, holder, acquired_at, expires_at, ttl_ns)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (issue_id) DO UPDATE SET
+			holder = excluded.holder,
+			acquired_at = excluded.acquired_at,
+			expires_at = excluded.expires_at,
+			ttl_ns = excluded.ttl_ns
+		WHERE issue_leases.holder = excluded.holder OR issue_leases.expires_at <= ?
+	`, issueID, holder, formatLeaseTime(now), formatLeaseTime(now.Add(ttl)), int64(ttl), formatLeaseTime(now))
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check lease result: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetLease returns the lease on an issue, or nil if there is none.
+// Expired leases are returned as-is; use Lease.IsActive to check them.
+func (s *SQLiteStorage) GetLease(ctx context.Context, issueID string) (*types.Lease, error) {
+	var lease types.Lease
+	var acquiredAt, expiresAt string
+	var ttlNs int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT issue_id, holder, acquired_at, expires_at, ttl_ns
+		FROM issue_leases WHERE issue_id = ?
+	`, issueID).Scan(&lease.IssueID, &lease.Holder, &acquiredAt, &expiresAt, &ttlNs)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapDBError("get lease", err)
+	}
+	lease.AcquiredAt = parseTimeString(acquiredAt)
+	lease.ExpiresAt = parseTimeString(expiresAt)
+	lease.TTL = time.Duration(ttlNs)
+	return &lease, nil
+}
+
+// ReleaseLease drops holder's lease on an issue. The issue itself is left
+// untouched; releasing is a no-op if the lease belongs to someone else.
+func (s *SQLiteStorage) ReleaseLease(ctx context.Context, issueID, holder string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM issue_leases WHERE issue_id = ? AND holder = ?
+	`, issueID, holder)
+	if err != nil {
+		return wrapDBError("release lease", err)
+	}
+	return nil
+}
+
+// ExpireLeases deletes every expired lease. Issues still in_progress and
+// assigned to the former lease holder go back to open and unassigned, so
+// they reappear in bd ready, and come off the holder's hook if it is an
+// agent bead. Returns the IDs of reopened issues.
+func (s *SQLiteStorage) ExpireLeases(ctx context.Context, actor string) ([]string, error) {
+	var reopened []string
+	err := s.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		t := tx.(*sqliteTxStorage)
+		now := formatLeaseTime(time.Now())
+
+		rows, err := t.conn.QueryContext(ctx, `
+			SELECT l.issue_id, l.holder
+			FROM issue_leases l
+			JOIN issues i ON i.id = l.issue_id
+			WHERE l.expires_at <= ? AND i.status = ? AND i.assignee = l.holder
+		`, now, types.StatusInProgress)
+		if err != nil {
+			return fmt.Errorf("failed to query expired leases: %w", err)
+		}
+		var ids, holders []string
+		for rows.Next() {
+			var id, holder string
+			if err := rows.Scan(&id, &holder); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("failed to scan expired lease: %w", err)
+			}
+			ids = append(ids, id)
+			holders = append(holders, holder)
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for i, id := range ids {
+			updates := map[string]interface{}{
+				"status":   string(types.StatusOpen),
+				"assignee": "",
+			}
+			if err := t.UpdateIssue(ctx, id, updates, actor); err != nil {
+				return fmt.Errorf("failed to reopen %s: %w", id, err)
+			}
+			if err := storage.UnhookAgent(ctx, t, holders[i], id, actor); err != nil {
+				return fmt.Errorf("failed to unhook %s from %s: %w", id, holders[i], err)
+			}
+		}
+
+		if _, err := t.conn.ExecContext(ctx, `DELETE FROM issue_leases WHERE expires_at <= ?`, now); err != nil {
+			return fmt.Errorf("failed to delete expired leases: %w", err)
+		}
+		reopened = ids
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reopened, nil
+}
+
+// leaseRenewer is satisfied by *sql.Tx and *sql.Conn.
+type leaseRenewer interface {
+	execer
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// renewLease extends an active lease held by actor to now+ttl. Called on
+// issue activity (updates, comments) so that working agents keep their claim.
+// Expired leases are not revived; they are left for ExpireLeases.
+func renewLease(ctx context.Context, db leaseRenewer, issueID, actor string) error {
+	now := time.Now()
+	var ttlNs int64
+	err := db.QueryRowContext(ctx, `
+		SELECT ttl_ns FROM issue_leases
+		WHERE issue_id = ? AND holder = ? AND expires_at > ?
+	`, issueID, actor, formatLeaseTime(now)).Scan(&ttlNs)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `
+		UPDATE issue_leases SET expires_at = ? WHERE issue_id = ?
+	`, formatLeaseTime(now.Add(time.Duration(ttlNs))), issueID)
+	return err
+}
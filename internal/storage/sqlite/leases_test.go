@@ -0,0 +1,219 @@
+package sqlite
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestClaimIssue(t *testing.T) {
+	env := newTestEnv(t)
+	issue := env.CreateIssue("Claim me")
+
+	claimed, err := storage.ClaimIssue(env.Ctx, env.Store, issue.ID, "agent-a", time.Hour)
+	if err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if claimed.Status != types.StatusInProgress || claimed.Assignee != "agent-a" {
+		t.Errorf("claimed issue = %s/%q, want in_progress/agent-a", claimed.Status, claimed.Assignee)
+	}
+
+	lease, err := env.Store.GetLease(env.Ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLease failed: %v", err)
+	}
+	if lease == nil || lease.Holder != "agent-a" || !lease.IsActive(time.Now()) {
+		t.Fatalf("expected active lease for agent-a, got %+v", lease)
+	}
+
+	// Second agent loses
+	_, err = storage.ClaimIssue(env.Ctx, env.Store, issue.ID, "agent-b", time.Hour)
+	var claimErr *storage.ClaimError
+	if !errors.As(err, &claimErr) {
+		t.Fatalf("expected ClaimError for agent-b, got %v", err)
+	}
+
+	// Holder can re-claim to refresh the lease
+	if _, err := storage.ClaimIssue(env.Ctx, env.Store, issue.ID, "agent-a", time.Hour); err != nil {
+		t.Fatalf("re-claim by holder failed: %v", err)
+	}
+}
+
+func TestReleaseClaim(t *testing.T) {
+	env := newTestEnv(t)
+	issue := env.CreateIssue("Release me")
+
+	if _, err := storage.ClaimIssue(env.Ctx, env.Store, issue.ID, "agent-a", time.Hour); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+
+	// Someone other than the holder can't release it
+	if err := storage.ReleaseClaim(env.Ctx, env.Store, issue.ID, "agent-b"); err != nil {
+		t.Fatalf("ReleaseClaim by a non-holder failed: %v", err)
+	}
+	got, _ := env.Store.GetIssue(env.Ctx, issue.ID)
+	if got.Status != types.StatusInProgress || got.Assignee != "agent-a" {
+		t.Fatalf("non-holder release changed the issue: %s/%q", got.Status, got.Assignee)
+	}
+
+	if err := storage.ReleaseClaim(env.Ctx, env.Store, issue.ID, "agent-a"); err != nil {
+		t.Fatalf("ReleaseClaim failed: %v", err)
+	}
+	got, _ = env.Store.GetIssue(env.Ctx, issue.ID)
+	if got.Status != types.StatusOpen || got.Assignee != "" {
+		t.Errorf("released issue = %s/%q, want open and unassigned", got.Status, got.Assignee)
+	}
+	if lease, _ := env.Store.GetLease(env.Ctx, issue.ID); lease != nil {
+		t.Errorf("lease still held after release: %+v", lease)
+	}
+
+	// Someone else can claim it right away
+	if _, err := storage.ClaimIssue(env.Ctx, env.Store, issue.ID, "agent-b", time.Hour); err != nil {
+		t.Errorf("claim after release failed: %v", err)
+	}
+}
+
+func TestClaimIssueConcurrent(t *testing.T) {
+	env := newTestEnv(t)
+	issue := env.CreateIssue("Contended")
+
+	const agents = 8
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+	for i := 0; i < agents; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			holder := "agent-" + string(rune('a'+i))
+			if _, err := storage.ClaimIssue(env.Ctx, env.Store, issue.ID, holder, time.Hour); err == nil {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if winners != 1 {
+		t.Fatalf("expected exactly 1 winner, got %d", winners)
+	}
+}
+
+func TestClaimReady(t *testing.T) {
+	env := newTestEnv(t)
+	first := env.CreateIssueWith("First", types.StatusOpen, 0, types.TypeTask)
+	second := env.CreateIssueWith("Second", types.StatusOpen, 1, types.TypeTask)
+
+	got, err := storage.ClaimReady(env.Ctx, env.Store, types.WorkFilter{SortPolicy: types.SortPolicyPriority}, "agent-a", time.Hour)
+	if err != nil {
+		t.Fatalf("ClaimReady failed: %v", err)
+	}
+	if got.ID != first.ID {
+		t.Errorf("agent-a claimed %s, want %s", got.ID, first.ID)
+	}
+
+	got, err = storage.ClaimReady(env.Ctx, env.Store, types.WorkFilter{SortPolicy: types.SortPolicyPriority}, "agent-b", time.Hour)
+	if err != nil {
+		t.Fatalf("ClaimReady failed: %v", err)
+	}
+	if got.ID != second.ID {
+		t.Errorf("agent-b claimed %s, want %s", got.ID, second.ID)
+	}
+
+	_, err = storage.ClaimReady(env.Ctx, env.Store, types.WorkFilter{}, "agent-c", time.Hour)
+	if !errors.Is(err, storage.ErrNoClaimableWork) {
+		t.Errorf("expected ErrNoClaimableWork, got %v", err)
+	}
+}
+
+func TestReadyHidesLeasedIssues(t *testing.T) {
+	env := newTestEnv(t)
+	issue := env.CreateIssue("Leased")
+
+	if _, err := storage.ClaimIssue(env.Ctx, env.Store, issue.ID, "agent-a", time.Hour); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+
+	ready := env.GetReadyWork(types.WorkFilter{LeaseHolder: "agent-b"})
+	for _, r := range ready {
+		if r.ID == issue.ID {
+			t.Errorf("issue leased by agent-a should be hidden from agent-b")
+		}
+	}
+
+	ready = env.GetReadyWork(types.WorkFilter{LeaseHolder: "agent-a"})
+	found := false
+	for _, r := range ready {
+		if r.ID == issue.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("issue leased by agent-a should be visible to agent-a")
+	}
+}
+
+func TestExpireLeases(t *testing.T) {
+	env := newTestEnv(t)
+	issue := env.CreateIssue("Abandoned")
+
+	if _, err := storage.ClaimIssue(env.Ctx, env.Store, issue.ID, "agent-a", time.Millisecond); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	reopened, err := env.Store.ExpireLeases(env.Ctx, "test-user")
+	if err != nil {
+		t.Fatalf("ExpireLeases failed: %v", err)
+	}
+	if len(reopened) != 1 || reopened[0] != issue.ID {
+		t.Fatalf("reopened = %v, want [%s]", reopened, issue.ID)
+	}
+
+	got, err := env.Store.GetIssue(env.Ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Status != types.StatusOpen || got.Assignee != "" {
+		t.Errorf("issue = %s/%q, want open/unassigned", got.Status, got.Assignee)
+	}
+	if lease, _ := env.Store.GetLease(env.Ctx, issue.ID); lease != nil {
+		t.Errorf("expected lease to be deleted, got %+v", lease)
+	}
+
+	// Now claimable by someone else
+	if _, err := storage.ClaimIssue(env.Ctx, env.Store, issue.ID, "agent-b", time.Hour); err != nil {
+		t.Errorf("claim after expiry failed: %v", err)
+	}
+}
+
+func TestLeaseRenewedByActivity(t *testing.T) {
+	env := newTestEnv(t)
+	issue := env.CreateIssue("Active work")
+
+	if _, err := storage.ClaimIssue(env.Ctx, env.Store, issue.ID, "agent-a", time.Hour); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	before, _ := env.Store.GetLease(env.Ctx, issue.ID)
+
+	time.Sleep(5 * time.Millisecond)
+	if err := env.Store.UpdateIssue(env.Ctx, issue.ID, map[string]interface{}{"notes": "progress"}, "agent-a"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	after, _ := env.Store.GetLease(env.Ctx, issue.ID)
+	if !after.ExpiresAt.After(before.ExpiresAt) {
+		t.Errorf("lease not renewed: before %v, after %v", before.ExpiresAt, after.ExpiresAt)
+	}
+
+	// Closing the issue drops the lease
+	env.Close(issue, "Done")
+	if lease, _ := env.Store.GetLease(env.Ctx, issue.ID); lease != nil {
+		t.Errorf("expected lease to be deleted on close, got %+v", lease)
+	}
+}
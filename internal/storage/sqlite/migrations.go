@@ -57,6 +57,8 @@ var migrationsList = []Migration{
 	{"work_type_column", migrations.MigrateWorkTypeColumn},
 	{"source_system_column", migrations.MigrateSourceSystemColumn},
 	{"quality_score_column", migrations.MigrateQualityScoreColumn},
+	{"issue_leases_table", migrations.MigrateIssueLeasesTable},
+	{"commit_links_table", migrations.MigrateCommitLinksTable},
 }
 
 // MigrationInfo contains metadata about a migration for inspection
@@ -121,6 +123,8 @@ func getMigrationDescription(name string) string {
 		"work_type_column":             "Adds work_type column for work assignment model (mutex vs open_competition per Decision 006)",
 		"source_system_column":         "Adds source_system column for federation adapter tracking",
 		"quality_score_column":         "Adds quality_score column for aggregate quality (0.0-1.0) set by Refineries",
+		"issue_leases_table":           "Adds issue_leases table for lease-based atomic work claiming (bd claim)",
+		"commit_links_table":           "Adds commit_links table linking git commits to the issues they mention",
 	}
 
 	if desc, ok := descriptions[name]; ok {
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateIssueLeasesTable adds the issue_leases table used by bd claim.
+// A lease records which holder has claimed an issue and until when; an
+// issue can only be claimed by someone else once the lease has expired.
+// Leases are local coordination state and are never exported to JSONL.
+func MigrateIssueLeasesTable(db *sql.DB) error {
+	var tableName string
+	err := db.QueryRow(`
+		SELECT name FROM sqlite_master
+		WHERE type='table' AND name='issue_leases'
+	`).Scan(&tableName)
+
+	if err == sql.ErrNoRows {
+		_, err := db.Exec(`
+			CREATE TABLE issue_leases (
+				issue_id TEXT PRIMARY KEY,
+				holder TEXT NOT NULL,
+				acquired_at TEXT NOT NULL,
+				expires_at TEXT NOT NULL,
+				ttl_ns INTEGER NOT NULL,
+				FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create issue_leases table: %w", err)
+		}
+		_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_issue_leases_expires_at ON issue_leases(expires_at)`)
+		if err != nil {
+			return fmt.Errorf("failed to create issue_leases index: %w", err)
+		}
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to check for issue_leases table: %w", err)
+	}
+
+	return nil
+}
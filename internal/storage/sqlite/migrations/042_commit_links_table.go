@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MigrateCommitLinksTable adds the commit_links table maintained by the
+// post-commit and post-merge hooks. Each row records a git commit whose
+// message mentions an issue. Links are derived from git history, so they are
+// never exported to JSONL; bd commits index rebuilds them in a fresh clone.
+func MigrateCommitLinksTable(db *sql.DB) error {
+	var tableName string
+	err := db.QueryRow(`
+		SELECT name FROM sqlite_master
+		WHERE type='table' AND name='commit_links'
+	`).Scan(&tableName)
+
+	if err == sql.ErrNoRows {
+		_, err := db.Exec(`
+			CREATE TABLE commit_links (
+				issue_id TEXT NOT NULL,
+				commit_sha TEXT NOT NULL,
+				author TEXT NOT NULL DEFAULT '',
+				subject TEXT NOT NULL DEFAULT '',
+				files TEXT NOT NULL DEFAULT '',
+				keyword TEXT NOT NULL DEFAULT 'mentions',
+				committed_at TEXT NOT NULL,
+				PRIMARY KEY (issue_id, commit_sha),
+				FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create commit_links table: %w", err)
+		}
+		_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_commit_links_commit_sha ON commit_links(commit_sha)`)
+		if err != nil {
+			return fmt.Errorf("failed to create commit_links index: %w", err)
+		}
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to check for commit_links table: %w", err)
+	}
+
+	return nil
+}
@@ -1046,6 +1046,11 @@ func (s *SQLiteStorage) UpdateIssue(ctx context.Context, id string, updates map[
 		}
 	}
 
+	// Activity by the lease holder keeps their claim alive
+	if err := renewLease(ctx, tx, id, actor); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+
 	return tx.Commit()
 }
 
@@ -1112,6 +1117,11 @@ func (s *SQLiteStorage) UpdateIssueID(ctx context.Context, oldID, newID string,
 		return fmt.Errorf("failed to update comments: %w", err)
 	}
 
+	_, err = tx.ExecContext(ctx, `UPDATE commit_links SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to update commit_links: %w", err)
+	}
+
 	_, err = tx.ExecContext(ctx, `
 		UPDATE dirty_issues SET issue_id = ? WHERE issue_id = ?
 	`, newID, oldID)
@@ -1251,6 +1261,11 @@ func (s *SQLiteStorage) CloseIssue(ctx context.Context, id string, reason string
 		return fmt.Errorf("failed to invalidate blocked cache: %w", err)
 	}
 
+	// Closing ends any claim on the issue
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_leases WHERE issue_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+
 	// Reactive convoy completion: check if any convoys tracking this issue should auto-close
 	// Find convoys that track this issue (convoy.issue_id tracks closed_issue.depends_on_id)
 	// Uses gt:convoy label instead of issue_type for Gas Town separation
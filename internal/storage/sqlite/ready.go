@@ -80,10 +80,12 @@ func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 		for i := range filter.LabelsAny {
 			placeholders[i] = "?"
 		}
+		// IN rather than a correlated EXISTS: the planner can flatten the
+		// latter into a join and return an issue once per matching label.
 		whereClauses = append(whereClauses, fmt.Sprintf(`
-			EXISTS (
-				SELECT 1 FROM labels
-				WHERE issue_id = i.id AND label IN (%s)
+			i.id IN (
+				SELECT issue_id FROM labels
+				WHERE label IN (%s)
 			)
 		`, strings.Join(placeholders, ",")))
 		for _, label := range filter.LabelsAny {
@@ -123,6 +125,15 @@ func (s *SQLiteStorage) GetReadyWork(ctx context.Context, filter types.WorkFilte
 		whereClauses = append(whereClauses, "(i.defer_until IS NULL OR datetime(i.defer_until) <= datetime('now'))")
 	}
 
+	// Hide issues under an active lease held by someone else (bd claim)
+	whereClauses = append(whereClauses, `
+		NOT EXISTS (
+			SELECT 1 FROM issue_leases l
+			WHERE l.issue_id = i.id AND l.expires_at > ? AND l.holder != ?
+		)
+	`)
+	args = append(args, formatLeaseTime(time.Now()), filter.LeaseHolder)
+
 	// Build WHERE clause properly
 	whereSQL := strings.Join(whereClauses, " AND ")
 
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/steveyegge/beads/internal/config"
 	"github.com/steveyegge/beads/internal/configfile"
@@ -1621,9 +1622,11 @@ func TestCheckExternalDepsBatching(t *testing.T) {
 		t.Errorf("Expected %d unique statuses, got %d", expectedUnique, len(statuses))
 	}
 
-	// cap1 should be satisfied
+	// cap1 should be satisfied, since the close of its providing issue
 	if s := statuses["external:batch-test:cap1"]; s == nil || !s.Satisfied {
 		t.Error("Expected external:batch-test:cap1 to be satisfied")
+	} else if s.SatisfiedAt == nil || time.Since(*s.SatisfiedAt) > time.Minute {
+		t.Errorf("Expected external:batch-test:cap1 SatisfiedAt to be its close time, got %v", s.SatisfiedAt)
 	}
 
 	// cap2 should be satisfied
@@ -1632,7 +1635,7 @@ func TestCheckExternalDepsBatching(t *testing.T) {
 	}
 
 	// cap3 should NOT be satisfied
-	if s := statuses["external:batch-test:cap3"]; s == nil || s.Satisfied {
+	if s := statuses["external:batch-test:cap3"]; s == nil || s.Satisfied || s.SatisfiedAt != nil {
 		t.Error("Expected external:batch-test:cap3 to be unsatisfied")
 	}
 
@@ -180,6 +180,35 @@ CREATE TABLE IF NOT EXISTS child_counters (
     FOREIGN KEY (parent_id) REFERENCES issues(id) ON DELETE CASCADE
 );
 
+-- Issue leases table (for bd claim)
+-- Time-limited claims on issues; local coordination state, not exported
+CREATE TABLE IF NOT EXISTS issue_leases (
+    issue_id TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    acquired_at TEXT NOT NULL,
+    expires_at TEXT NOT NULL,
+    ttl_ns INTEGER NOT NULL,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_leases_expires_at ON issue_leases(expires_at);
+
+-- Commit links table (for bd commits)
+-- Git commits whose messages mention an issue; derived from git history, not exported
+CREATE TABLE IF NOT EXISTS commit_links (
+    issue_id TEXT NOT NULL,
+    commit_sha TEXT NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    subject TEXT NOT NULL DEFAULT '',
+    files TEXT NOT NULL DEFAULT '',
+    keyword TEXT NOT NULL DEFAULT 'mentions',
+    committed_at TEXT NOT NULL,
+    PRIMARY KEY (issue_id, commit_sha),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_commit_links_commit_sha ON commit_links(commit_sha);
+
 -- Issue snapshots table (for compaction)
 CREATE TABLE IF NOT EXISTS issue_snapshots (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -477,6 +477,11 @@ func (t *sqliteTxStorage) UpdateIssue(ctx context.Context, id string, updates ma
 		}
 	}
 
+	// Activity by the lease holder keeps their claim alive
+	if err := renewLease(ctx, t.conn, id, actor); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+
 	return nil
 }
 
@@ -582,6 +587,11 @@ func (t *sqliteTxStorage) CloseIssue(ctx context.Context, id string, reason stri
 		return fmt.Errorf("failed to invalidate blocked cache: %w", err)
 	}
 
+	// Closing ends any claim on the issue
+	if _, err := t.conn.ExecContext(ctx, `DELETE FROM issue_leases WHERE issue_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+
 	// Reactive convoy completion: check if any convoys tracking this issue should auto-close
 	// Find convoys that track this issue (convoy.issue_id tracks closed_issue.depends_on_id)
 	// Uses gt:convoy label instead of issue_type for Gas Town separation
@@ -1060,6 +1070,11 @@ func (t *sqliteTxStorage) AddComment(ctx context.Context, issueID, actor, commen
 		return fmt.Errorf("failed to mark issue dirty: %w", err)
 	}
 
+	// Commenting counts as activity for the lease holder
+	if err := renewLease(ctx, t.conn, issueID, actor); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+
 	return nil
 }
 
@@ -4,6 +4,7 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/steveyegge/beads/internal/types"
 )
@@ -73,6 +74,13 @@ type Transaction interface {
 
 	// Comment operations
 	AddComment(ctx context.Context, issueID, actor, comment string) error
+
+	// Lease operations (atomic work claiming)
+	//
+	// AcquireLease is a compare-and-set: it grants holder a lease on issueID for ttl
+	// and returns true only if no other holder has an active lease. Re-acquiring a
+	// lease already held by holder refreshes it.
+	AcquireLease(ctx context.Context, issueID, holder string, ttl time.Duration) (bool, error)
 }
 
 // Storage defines the interface for issue storage backends
@@ -117,13 +125,24 @@ type Storage interface {
 
 	// Events
 	AddComment(ctx context.Context, issueID, actor, comment string) error
+	AddEvent(ctx context.Context, event *types.Event) error // Records an event without touching the issue (e.g. swarm dispatch decisions)
 	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
+	SearchEvents(ctx context.Context, filter types.EventFilter) ([]*types.Event, error) // Oldest first, across issues
 
 	// Comments
 	AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error)
 	GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error)
 	GetCommentsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Comment, error)
 
+	// Leases (atomic work claiming; acquired via Transaction.AcquireLease)
+	GetLease(ctx context.Context, issueID string) (*types.Lease, error) // nil if no lease exists
+	ReleaseLease(ctx context.Context, issueID, holder string) error     // No-op if holder doesn't hold the lease
+	ExpireLeases(ctx context.Context, actor string) ([]string, error)   // Reopens issues whose lease expired; returns their IDs
+
+	// Commit links (git commits mentioning an issue; local index, not exported to JSONL)
+	AddCommitLinks(ctx context.Context, links []*types.CommitLink) error             // Upserts by (issue, commit)
+	GetCommitLinks(ctx context.Context, issueID string) ([]*types.CommitLink, error) // Oldest first
+
 	// Statistics
 	GetStatistics(ctx context.Context) (*types.Statistics, error)
 
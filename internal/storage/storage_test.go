@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/steveyegge/beads/internal/types"
 )
@@ -113,9 +114,30 @@ func (m *mockStorage) GetNewlyUnblockedByClose(ctx context.Context, closedIssueI
 func (m *mockStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
 	return nil
 }
+func (m *mockStorage) AddEvent(ctx context.Context, event *types.Event) error {
+	return nil
+}
 func (m *mockStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
 	return nil, nil
 }
+func (m *mockStorage) SearchEvents(ctx context.Context, filter types.EventFilter) ([]*types.Event, error) {
+	return nil, nil
+}
+func (m *mockStorage) GetLease(ctx context.Context, issueID string) (*types.Lease, error) {
+	return nil, nil
+}
+func (m *mockStorage) ReleaseLease(ctx context.Context, issueID, holder string) error {
+	return nil
+}
+func (m *mockStorage) ExpireLeases(ctx context.Context, actor string) ([]string, error) {
+	return nil, nil
+}
+func (m *mockStorage) AddCommitLinks(ctx context.Context, links []*types.CommitLink) error {
+	return nil
+}
+func (m *mockStorage) GetCommitLinks(ctx context.Context, issueID string) ([]*types.CommitLink, error) {
+	return nil, nil
+}
 func (m *mockStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
 	return nil, nil
 }
@@ -258,6 +280,9 @@ func (m *mockTransaction) GetMetadata(ctx context.Context, key string) (string,
 func (m *mockTransaction) AddComment(ctx context.Context, issueID, actor, comment string) error {
 	return nil
 }
+func (m *mockTransaction) AcquireLease(ctx context.Context, issueID, holder string, ttl time.Duration) (bool, error) {
+	return false, nil
+}
 
 // TestConfig verifies the Config struct has expected fields.
 func TestConfig(t *testing.T) {
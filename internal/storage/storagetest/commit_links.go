@@ -0,0 +1,108 @@
+package storagetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var commitLinkTests = map[string]func(*testing.T, *suite){
+	"AddAndGet":    testCommitLinksAddAndGet,
+	"Upsert":       testCommitLinksUpsert,
+	"NoLinks":      testCommitLinksNone,
+	"FollowRename": testCommitLinksFollowRename,
+}
+
+func testCommitLinksAddAndGet(t *testing.T, s *suite) {
+	s.require(t, CommitLinks)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "fixed", "mentioned")
+	fixed, mentioned := issues[0], issues[1]
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	links := []*types.CommitLink{
+		{IssueID: fixed.ID, CommitSHA: "bbbb", Author: "alice", Subject: "Fix it", Files: []string{"a.go", "b.go"}, Keyword: types.CommitCloses, CommittedAt: base.Add(time.Hour)},
+		{IssueID: fixed.ID, CommitSHA: "aaaa", Author: "bob", Subject: "Start it", Keyword: types.CommitRefs, CommittedAt: base},
+		{IssueID: mentioned.ID, CommitSHA: "bbbb", Author: "alice", Subject: "Fix it", Keyword: types.CommitMentions, CommittedAt: base.Add(time.Hour)},
+	}
+	if err := store.AddCommitLinks(ctx, links); err != nil {
+		t.Fatalf("AddCommitLinks failed: %v", err)
+	}
+
+	got, err := store.GetCommitLinks(ctx, fixed.ID)
+	if err != nil {
+		t.Fatalf("GetCommitLinks failed: %v", err)
+	}
+	if len(got) != 2 || got[0].CommitSHA != "aaaa" || got[1].CommitSHA != "bbbb" {
+		t.Fatalf("GetCommitLinks = %+v, want aaaa then bbbb", got)
+	}
+	last := got[1]
+	if last.Author != "alice" || last.Subject != "Fix it" || last.Keyword != types.CommitCloses {
+		t.Errorf("link = %+v", last)
+	}
+	if len(last.Files) != 2 || last.Files[0] != "a.go" || last.Files[1] != "b.go" {
+		t.Errorf("link files = %v, want [a.go b.go]", last.Files)
+	}
+	if !last.CommittedAt.Equal(base.Add(time.Hour)) {
+		t.Errorf("link committed at %v, want %v", last.CommittedAt, base.Add(time.Hour))
+	}
+
+	if got, _ := store.GetCommitLinks(ctx, mentioned.ID); len(got) != 1 || got[0].Keyword != types.CommitMentions {
+		t.Errorf("links for the mentioned issue = %+v", got)
+	}
+}
+
+func testCommitLinksUpsert(t *testing.T, s *suite) {
+	s.require(t, CommitLinks)
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("reindexed"))
+	link := &types.CommitLink{IssueID: issue.ID, CommitSHA: "cccc", Subject: "refs", Keyword: types.CommitRefs, CommittedAt: time.Now()}
+	if err := store.AddCommitLinks(ctx, []*types.CommitLink{link}); err != nil {
+		t.Fatalf("AddCommitLinks failed: %v", err)
+	}
+	link.Keyword = types.CommitCloses
+	if err := store.AddCommitLinks(ctx, []*types.CommitLink{link}); err != nil {
+		t.Fatalf("AddCommitLinks again failed: %v", err)
+	}
+
+	got, err := store.GetCommitLinks(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetCommitLinks failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Keyword != types.CommitCloses {
+		t.Errorf("GetCommitLinks after re-adding = %+v, want one closes link", got)
+	}
+}
+
+func testCommitLinksNone(t *testing.T, s *suite) {
+	s.require(t, CommitLinks)
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("untouched"))
+	if err := store.AddCommitLinks(ctx, nil); err != nil {
+		t.Fatalf("AddCommitLinks(nil) failed: %v", err)
+	}
+	if got, err := store.GetCommitLinks(ctx, issue.ID); err != nil || len(got) != 0 {
+		t.Errorf("GetCommitLinks = %+v, %v; want none", got, err)
+	}
+}
+
+func testCommitLinksFollowRename(t *testing.T, s *suite) {
+	s.require(t, CommitLinks, RenameIssue)
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("renamed"))
+	link := &types.CommitLink{IssueID: issue.ID, CommitSHA: "dddd", Keyword: types.CommitRefs, CommittedAt: time.Now()}
+	if err := store.AddCommitLinks(ctx, []*types.CommitLink{link}); err != nil {
+		t.Fatalf("AddCommitLinks failed: %v", err)
+	}
+	newID := s.b.Prefix + "-moved"
+	if err := store.UpdateIssueID(ctx, issue.ID, newID, issue, "tester"); err != nil {
+		t.Fatalf("UpdateIssueID failed: %v", err)
+	}
+	if got, err := store.GetCommitLinks(ctx, newID); err != nil || len(got) != 1 {
+		t.Errorf("links after rename = %+v, %v; want the original link", got, err)
+	}
+}
@@ -0,0 +1,236 @@
+package storagetest
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var dependencyTests = map[string]func(*testing.T, *suite){
+	"AddAndGet":            testDepAddAndGet,
+	"WithMetadata":         testDepWithMetadata,
+	"Records":              testDepRecords,
+	"Remove":               testDepRemove,
+	"RejectsMissingIssues": testDepRejectsMissingIssues,
+	"Counts":               testDepCounts,
+	"RejectsCycles":        testDepRejectsCycles,
+	"Tree":                 testDepTree,
+	"TreeReverse":          testDepTreeReverse,
+}
+
+func testDepAddAndGet(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "feature", "schema", "api", "unrelated")
+	feature, schema, api := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, feature.ID, schema.ID, types.DepBlocks)
+	addDep(t, ctx, store, feature.ID, api.ID, types.DepBlocks)
+	addDep(t, ctx, store, api.ID, schema.ID, types.DepBlocks)
+
+	deps, err := store.GetDependencies(ctx, feature.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	expectIDs(t, "dependencies of feature", sortedIDs(deps), idsOf(schema, api))
+
+	dependents, err := store.GetDependents(ctx, schema.ID)
+	if err != nil {
+		t.Fatalf("GetDependents failed: %v", err)
+	}
+	expectIDs(t, "dependents of schema", sortedIDs(dependents), idsOf(feature, api))
+}
+
+func testDepWithMetadata(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "child", "parent", "blocker")
+	child, parent, blocker := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, child.ID, parent.ID, types.DepParentChild)
+	addDep(t, ctx, store, child.ID, blocker.ID, types.DepBlocks)
+
+	deps, err := store.GetDependenciesWithMetadata(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetDependenciesWithMetadata failed: %v", err)
+	}
+	gotTypes := make(map[string]types.DependencyType)
+	for _, d := range deps {
+		gotTypes[d.ID] = d.DependencyType
+	}
+	if gotTypes[parent.ID] != types.DepParentChild || gotTypes[blocker.ID] != types.DepBlocks || len(gotTypes) != 2 {
+		t.Errorf("dependency types = %v, want %s:parent-child %s:blocks", gotTypes, parent.ID, blocker.ID)
+	}
+
+	dependents, err := store.GetDependentsWithMetadata(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("GetDependentsWithMetadata failed: %v", err)
+	}
+	if len(dependents) != 1 || dependents[0].ID != child.ID || dependents[0].DependencyType != types.DepParentChild {
+		t.Errorf("dependents of parent = %+v, want %s via parent-child", dependents, child.ID)
+	}
+}
+
+func testDepRecords(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b", "c")
+	a, b, c := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, a.ID, b.ID, types.DepBlocks)
+	addDep(t, ctx, store, b.ID, c.ID, types.DepRelated)
+
+	records, err := store.GetDependencyRecords(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("GetDependencyRecords(%s) returned %d records, want 1", a.ID, len(records))
+	}
+	if r := records[0]; r.IssueID != a.ID || r.DependsOnID != b.ID || r.Type != types.DepBlocks {
+		t.Errorf("record = %+v, want %s -> %s blocks", r, a.ID, b.ID)
+	}
+
+	all, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		t.Fatalf("GetAllDependencyRecords failed: %v", err)
+	}
+	if len(all[a.ID]) != 1 || len(all[b.ID]) != 1 || len(all[c.ID]) != 0 {
+		t.Errorf("GetAllDependencyRecords = %v, want one record each for %s and %s", all, a.ID, b.ID)
+	}
+}
+
+func testDepRemove(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b")
+	a, b := issues[0], issues[1]
+	addDep(t, ctx, store, a.ID, b.ID, types.DepBlocks)
+
+	if err := store.RemoveDependency(ctx, a.ID, b.ID, "tester"); err != nil {
+		t.Fatalf("RemoveDependency failed: %v", err)
+	}
+	deps, err := store.GetDependencies(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(deps) != 0 {
+		t.Errorf("dependency survived removal: %v", sortedIDs(deps))
+	}
+}
+
+func testDepRejectsMissingIssues(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("real"))
+	missing := s.b.Prefix + "-missing"
+
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: issue.ID, DependsOnID: missing, Type: types.DepBlocks}, "tester"); err == nil {
+		t.Error("AddDependency on a missing target should fail")
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: missing, DependsOnID: issue.ID, Type: types.DepBlocks}, "tester"); err == nil {
+		t.Error("AddDependency from a missing issue should fail")
+	}
+}
+
+func testDepCounts(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "hub", "x", "y", "lonely")
+	hub, x, y, lonely := issues[0], issues[1], issues[2], issues[3]
+	addDep(t, ctx, store, x.ID, hub.ID, types.DepBlocks)
+	addDep(t, ctx, store, y.ID, hub.ID, types.DepBlocks)
+	addDep(t, ctx, store, hub.ID, lonely.ID, types.DepRelated)
+
+	counts, err := store.GetDependencyCounts(ctx, []string{hub.ID, x.ID, lonely.ID})
+	if err != nil {
+		t.Fatalf("GetDependencyCounts failed: %v", err)
+	}
+	check := func(id string, deps, dependents int) {
+		t.Helper()
+		c := counts[id]
+		if c == nil {
+			t.Fatalf("no counts for %s", id)
+		}
+		if c.DependencyCount != deps || c.DependentCount != dependents {
+			t.Errorf("counts for %s = %d/%d, want %d/%d", id, c.DependencyCount, c.DependentCount, deps, dependents)
+		}
+	}
+	check(hub.ID, 1, 2)
+	check(x.ID, 1, 0)
+	check(lonely.ID, 0, 1)
+}
+
+func testDepRejectsCycles(t *testing.T, s *suite) {
+	s.require(t, CycleDetection)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b", "c")
+	a, b, c := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, a.ID, b.ID, types.DepBlocks)
+	addDep(t, ctx, store, b.ID, c.ID, types.DepBlocks)
+
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: c.ID, DependsOnID: a.ID, Type: types.DepBlocks}, "tester"); err == nil {
+		t.Fatal("AddDependency closing a cycle should fail")
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: a.ID, DependsOnID: a.ID, Type: types.DepBlocks}, "tester"); err == nil {
+		t.Fatal("AddDependency on itself should fail")
+	}
+
+	cycles, err := store.DetectCycles(ctx)
+	if err != nil {
+		t.Fatalf("DetectCycles failed: %v", err)
+	}
+	if len(cycles) != 0 {
+		t.Errorf("DetectCycles found %d cycles in an acyclic graph", len(cycles))
+	}
+}
+
+func testDepTree(t *testing.T, s *suite) {
+	s.require(t, DependencyTree)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "root", "mid", "leaf")
+	root, mid, leaf := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, root.ID, mid.ID, types.DepBlocks)
+	addDep(t, ctx, store, mid.ID, leaf.ID, types.DepBlocks)
+
+	tree, err := store.GetDependencyTree(ctx, root.ID, 10, false, false)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	depths := make(map[string]int)
+	for _, node := range tree {
+		depths[node.ID] = node.Depth
+	}
+	if len(depths) != 3 || depths[root.ID] != 0 || depths[mid.ID] != 1 || depths[leaf.ID] != 2 {
+		t.Errorf("tree depths = %v, want %s:0 %s:1 %s:2", depths, root.ID, mid.ID, leaf.ID)
+	}
+
+	shallow, err := store.GetDependencyTree(ctx, root.ID, 1, false, false)
+	if err != nil {
+		t.Fatalf("GetDependencyTree(maxDepth=1) failed: %v", err)
+	}
+	for _, node := range shallow {
+		if node.ID == leaf.ID {
+			t.Errorf("maxDepth=1 tree includes depth-2 node %s", leaf.ID)
+		}
+	}
+}
+
+func testDepTreeReverse(t *testing.T, s *suite) {
+	s.require(t, DependencyTree)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "root", "mid", "leaf")
+	root, mid, leaf := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, root.ID, mid.ID, types.DepBlocks)
+	addDep(t, ctx, store, mid.ID, leaf.ID, types.DepBlocks)
+
+	tree, err := store.GetDependencyTree(ctx, leaf.ID, 10, false, true)
+	if err != nil {
+		t.Fatalf("GetDependencyTree(reverse) failed: %v", err)
+	}
+	ids := make([]string, len(tree))
+	for i, node := range tree {
+		ids[i] = node.ID
+	}
+	expectIDs(t, "reverse tree from leaf", ids, idsOf(root, mid, leaf))
+}
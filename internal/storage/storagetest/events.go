@@ -0,0 +1,278 @@
+package storagetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var eventTests = map[string]func(*testing.T, *suite){
+	"CreateRecorded":   testEventCreateRecorded,
+	"MutationsOrdered": testEventMutationsOrdered,
+	"AddComment":       testEventAddComment,
+	"AddEvent":         testEventAddEvent,
+	"Limit":            testEventLimit,
+	"SearchAcross":     testEventSearchAcross,
+	"SearchFilters":    testEventSearchFilters,
+}
+
+var commentTests = map[string]func(*testing.T, *suite){
+	"AddAndGet":        testCommentAddAndGet,
+	"ForIssues":        testCommentsForIssues,
+	"RejectsMissingID": testCommentRejectsMissingID,
+}
+
+func testEventCreateRecorded(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("audited"))
+	events, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) == 0 {
+		t.Fatal("no events after CreateIssue")
+	}
+	created := events[len(events)-1]
+	if created.EventType != types.EventCreated || created.Actor != "tester" || created.IssueID != issue.ID {
+		t.Errorf("oldest event = %+v, want created by tester on %s", created, issue.ID)
+	}
+}
+
+func testEventMutationsOrdered(t *testing.T, s *suite) {
+	s.require(t, EventHistory)
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("audited"))
+	other := create(t, ctx, store, newIssue("other"))
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "renamed"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "audit", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	addDep(t, ctx, store, issue.ID, other.ID, types.DepBlocks)
+	closeIssue(t, ctx, store, issue.ID)
+
+	events, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	seen := make(map[types.EventType]bool)
+	for _, e := range events {
+		seen[e.EventType] = true
+	}
+	for _, want := range []types.EventType{types.EventCreated, types.EventUpdated, types.EventLabelAdded, types.EventDependencyAdded, types.EventClosed} {
+		if !seen[want] {
+			t.Errorf("no %s event recorded; got %v", want, seen)
+		}
+	}
+	// Newest first; events within the same second may tie
+	for i := 1; i < len(events); i++ {
+		if events[i].CreatedAt.After(events[i-1].CreatedAt) {
+			t.Fatalf("events not newest first at %d: %v after %v", i, events[i].CreatedAt, events[i-1].CreatedAt)
+		}
+	}
+}
+
+func testEventAddComment(t *testing.T, s *suite) {
+	s.require(t, EventHistory)
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("discussed"))
+	if err := store.AddComment(ctx, issue.ID, "alice", "looks good"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+
+	events, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	for _, e := range events {
+		if e.EventType == types.EventCommented {
+			if e.Actor != "alice" || e.Comment == nil || *e.Comment != "looks good" {
+				t.Errorf("comment event = %+v, want alice's comment", e)
+			}
+			return
+		}
+	}
+	t.Errorf("no %s event recorded", types.EventCommented)
+}
+
+func testEventAddEvent(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("swarm"))
+	target, comment := "bd-task", "dispatched bd-task to agent-a"
+	event := &types.Event{IssueID: issue.ID, EventType: types.EventDispatched, Actor: "alice", NewValue: &target, Comment: &comment}
+	if err := store.AddEvent(ctx, event); err != nil {
+		t.Fatalf("AddEvent failed: %v", err)
+	}
+
+	events, err := store.SearchEvents(ctx, types.EventFilter{IssueIDs: []string{issue.ID}, Types: []types.EventType{types.EventDispatched}})
+	if err != nil {
+		t.Fatalf("SearchEvents failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d dispatched events, want 1", len(events))
+	}
+	got := events[0]
+	if got.Actor != "alice" || got.NewValue == nil || *got.NewValue != target || got.Comment == nil || *got.Comment != comment || got.OldValue != nil {
+		t.Errorf("event = %+v, want alice's dispatch of %s", got, target)
+	}
+}
+
+func testEventLimit(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("busy"))
+	for _, title := range []string{"one", "two", "three"} {
+		if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": title}, "tester"); err != nil {
+			t.Fatalf("UpdateIssue failed: %v", err)
+		}
+	}
+
+	events, err := store.GetEvents(ctx, issue.ID, 2)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("GetEvents(limit=2) returned %d events", len(events))
+	}
+}
+
+func testEventSearchAcross(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "first", "second")
+	closeIssue(t, ctx, store, issues[1].ID)
+
+	events, err := store.SearchEvents(ctx, types.EventFilter{})
+	if err != nil {
+		t.Fatalf("SearchEvents failed: %v", err)
+	}
+	created := make(map[string]bool)
+	for _, e := range events {
+		if e.EventType == types.EventCreated {
+			created[e.IssueID] = true
+		}
+	}
+	if !created[issues[0].ID] || !created[issues[1].ID] {
+		t.Errorf("created events for %v, want both issues", created)
+	}
+	// Oldest first; events within the same second may tie
+	for i := 1; i < len(events); i++ {
+		if events[i].CreatedAt.Before(events[i-1].CreatedAt) {
+			t.Fatalf("events not oldest first at %d: %v before %v", i, events[i].CreatedAt, events[i-1].CreatedAt)
+		}
+	}
+}
+
+func testEventSearchFilters(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "kept", "closed")
+	closeIssue(t, ctx, store, issues[1].ID)
+
+	closed, err := store.SearchEvents(ctx, types.EventFilter{Types: []types.EventType{types.EventClosed}})
+	if err != nil {
+		t.Fatalf("SearchEvents failed: %v", err)
+	}
+	if len(closed) != 1 || closed[0].IssueID != issues[1].ID {
+		t.Errorf("closed events = %+v, want one for %s", closed, issues[1].ID)
+	}
+
+	mine, err := store.SearchEvents(ctx, types.EventFilter{IssueIDs: []string{issues[0].ID}})
+	if err != nil {
+		t.Fatalf("SearchEvents failed: %v", err)
+	}
+	for _, e := range mine {
+		if e.IssueID != issues[0].ID {
+			t.Errorf("event for %s returned when filtering on %s", e.IssueID, issues[0].ID)
+		}
+	}
+	if len(mine) == 0 {
+		t.Errorf("no events for %s", issues[0].ID)
+	}
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	inWindow, err := store.SearchEvents(ctx, types.EventFilter{Since: &past, Until: &future})
+	if err != nil {
+		t.Fatalf("SearchEvents failed: %v", err)
+	}
+	if len(inWindow) < 3 {
+		t.Errorf("%d events in the last hour, want at least 2 creates and a close", len(inWindow))
+	}
+	later, err := store.SearchEvents(ctx, types.EventFilter{Since: &future})
+	if err != nil {
+		t.Fatalf("SearchEvents failed: %v", err)
+	}
+	earlier, err := store.SearchEvents(ctx, types.EventFilter{Until: &past})
+	if err != nil {
+		t.Fatalf("SearchEvents failed: %v", err)
+	}
+	if len(later) != 0 || len(earlier) != 0 {
+		t.Errorf("events outside the window: %d after, %d before", len(later), len(earlier))
+	}
+}
+
+func testCommentAddAndGet(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("discussed"))
+	first, err := store.AddIssueComment(ctx, issue.ID, "alice", "first")
+	if err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+	if first.IssueID != issue.ID || first.Author != "alice" || first.Text != "first" || first.CreatedAt.IsZero() {
+		t.Errorf("returned comment = %+v", first)
+	}
+	if _, err := store.AddIssueComment(ctx, issue.ID, "bob", "second"); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+
+	comments, err := store.GetIssueComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssueComments failed: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("GetIssueComments returned %d comments, want 2", len(comments))
+	}
+	if comments[0].Text != "first" || comments[1].Text != "second" {
+		t.Errorf("comments not oldest first: %q, %q", comments[0].Text, comments[1].Text)
+	}
+	if comments[0].ID == comments[1].ID {
+		t.Errorf("comments share ID %d", comments[0].ID)
+	}
+}
+
+func testCommentsForIssues(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b", "quiet")
+	for i, issue := range issues[:2] {
+		for j := 0; j <= i; j++ {
+			if _, err := store.AddIssueComment(ctx, issue.ID, "alice", "note"); err != nil {
+				t.Fatalf("AddIssueComment failed: %v", err)
+			}
+		}
+	}
+
+	got, err := store.GetCommentsForIssues(ctx, []string{issues[0].ID, issues[1].ID, issues[2].ID})
+	if err != nil {
+		t.Fatalf("GetCommentsForIssues failed: %v", err)
+	}
+	if len(got[issues[0].ID]) != 1 || len(got[issues[1].ID]) != 2 || len(got[issues[2].ID]) != 0 {
+		t.Errorf("comment counts = %d/%d/%d, want 1/2/0",
+			len(got[issues[0].ID]), len(got[issues[1].ID]), len(got[issues[2].ID]))
+	}
+}
+
+func testCommentRejectsMissingID(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	if _, err := store.AddIssueComment(ctx, s.b.Prefix+"-missing", "alice", "hello?"); err == nil {
+		t.Fatal("AddIssueComment on a missing issue should fail")
+	}
+}
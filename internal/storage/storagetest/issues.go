@@ -0,0 +1,319 @@
+package storagetest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var issueTests = map[string]func(*testing.T, *suite){
+	"CreateAndGet":              testCreateAndGet,
+	"CreateGeneratesPrefixedID": testCreateGeneratesPrefixedID,
+	"CreateRejectsInvalid":      testCreateRejectsInvalid,
+	"CreateRejectsDuplicateID":  testCreateRejectsDuplicateID,
+	"CreateRejectsWrongPrefix":  testCreateRejectsWrongPrefix,
+	"CreateKeepsTimestamps":     testCreateKeepsTimestamps,
+	"CreateIssuesBatch":         testCreateIssuesBatch,
+	"GetMissingReturnsNil":      testGetMissingReturnsNil,
+	"GetByExternalRef":          testGetByExternalRef,
+	"UpdateFields":              testUpdateFields,
+	"UpdateMissingFails":        testUpdateMissingFails,
+	"CloseAndReopen":            testCloseAndReopen,
+	"Delete":                    testDelete,
+	"DeleteMissingFails":        testDeleteMissingFails,
+}
+
+func testCreateAndGet(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	estimate := 45
+	issue := &types.Issue{
+		Title:              "Write the suite",
+		Description:        "Shared behavioral tests",
+		Design:             "Table of tests per group",
+		AcceptanceCriteria: "Runs on every backend",
+		Notes:              "Start with SQLite",
+		Status:             types.StatusOpen,
+		Priority:           1,
+		IssueType:          types.TypeFeature,
+		Assignee:           "alice",
+		EstimatedMinutes:   &estimate,
+	}
+	create(t, ctx, store, issue)
+	if issue.CreatedAt.IsZero() || issue.UpdatedAt.IsZero() {
+		t.Errorf("CreateIssue should set timestamps, got created=%v updated=%v", issue.CreatedAt, issue.UpdatedAt)
+	}
+
+	got := mustGet(t, ctx, store, issue.ID)
+	if got.Title != issue.Title || got.Description != issue.Description || got.Design != issue.Design ||
+		got.AcceptanceCriteria != issue.AcceptanceCriteria || got.Notes != issue.Notes {
+		t.Errorf("text fields not round-tripped: got %+v", got)
+	}
+	if got.Status != types.StatusOpen || got.Priority != 1 || got.IssueType != types.TypeFeature {
+		t.Errorf("status/priority/type = %s/%d/%s, want open/1/feature", got.Status, got.Priority, got.IssueType)
+	}
+	if got.Assignee != "alice" {
+		t.Errorf("Assignee = %q, want alice", got.Assignee)
+	}
+	if got.EstimatedMinutes == nil || *got.EstimatedMinutes != 45 {
+		t.Errorf("EstimatedMinutes = %v, want 45", got.EstimatedMinutes)
+	}
+	if got.ClosedAt != nil {
+		t.Errorf("ClosedAt = %v, want nil for an open issue", got.ClosedAt)
+	}
+}
+
+func testCreateGeneratesPrefixedID(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "one", "two", "three")
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if !strings.HasPrefix(issue.ID, s.b.Prefix+"-") {
+			t.Errorf("generated ID %q lacks prefix %q", issue.ID, s.b.Prefix+"-")
+		}
+		if seen[issue.ID] {
+			t.Errorf("generated ID %q twice", issue.ID)
+		}
+		seen[issue.ID] = true
+	}
+}
+
+func testCreateRejectsInvalid(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	cases := map[string]*types.Issue{
+		"empty title":    {Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		"bad priority":   {Title: "x", Status: types.StatusOpen, Priority: 9, IssueType: types.TypeTask},
+		"unknown status": {Title: "x", Status: "sleeping", Priority: 2, IssueType: types.TypeTask},
+	}
+	for name, issue := range cases {
+		if err := store.CreateIssue(ctx, issue, "tester"); err == nil {
+			t.Errorf("%s: CreateIssue should fail", name)
+		}
+	}
+}
+
+func testCreateRejectsDuplicateID(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	first := create(t, ctx, store, newIssue("first"))
+	dup := newIssue("second")
+	dup.ID = first.ID
+	if err := store.CreateIssue(ctx, dup, "tester"); err == nil {
+		t.Fatalf("CreateIssue with existing ID %s should fail", first.ID)
+	}
+	if got := mustGet(t, ctx, store, first.ID); got.Title != "first" {
+		t.Errorf("original issue overwritten: title = %q", got.Title)
+	}
+}
+
+func testCreateRejectsWrongPrefix(t *testing.T, s *suite) {
+	s.require(t, PrefixValidation)
+	ctx, store := s.open(t)
+
+	issue := newIssue("foreign")
+	issue.ID = "zz" + s.b.Prefix + "-abc"
+	if err := store.CreateIssue(ctx, issue, "tester"); err == nil {
+		t.Fatalf("CreateIssue with ID %s should fail for prefix %s", issue.ID, s.b.Prefix)
+	}
+}
+
+func testCreateKeepsTimestamps(t *testing.T, s *suite) {
+	s.require(t, ExplicitTimestamps)
+	ctx, store := s.open(t)
+
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	issue := newIssue("imported")
+	issue.CreatedAt = created
+	issue.UpdatedAt = updated
+	create(t, ctx, store, issue)
+
+	got := mustGet(t, ctx, store, issue.ID)
+	if !got.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, created)
+	}
+	if !got.UpdatedAt.Equal(updated) {
+		t.Errorf("UpdatedAt = %v, want %v", got.UpdatedAt, updated)
+	}
+}
+
+func testCreateIssuesBatch(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	batch := []*types.Issue{newIssue("a"), newIssue("b"), newIssue("c")}
+	if err := store.CreateIssues(ctx, batch, "tester"); err != nil {
+		t.Fatalf("CreateIssues failed: %v", err)
+	}
+	for _, issue := range batch {
+		if issue.ID == "" {
+			t.Fatalf("CreateIssues did not assign an ID to %q", issue.Title)
+		}
+		mustGet(t, ctx, store, issue.ID)
+	}
+
+	// A batch with an invalid issue is rejected as a whole
+	bad := []*types.Issue{newIssue("ok"), {Title: "", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}}
+	if err := store.CreateIssues(ctx, bad, "tester"); err == nil {
+		t.Fatal("CreateIssues with an invalid issue should fail")
+	}
+	expectIDs(t, "issues after rejected batch", search(t, ctx, store, types.IssueFilter{}), idsOf(batch...))
+}
+
+func testGetMissingReturnsNil(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue, err := store.GetIssue(ctx, s.b.Prefix+"-missing")
+	if err != nil {
+		t.Fatalf("GetIssue on a missing ID should not fail: %v", err)
+	}
+	if issue != nil {
+		t.Fatalf("GetIssue on a missing ID = %+v, want nil", issue)
+	}
+}
+
+func testGetByExternalRef(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := newIssue("tracked upstream")
+	issue.ExternalRef = ptr("gh-42")
+	create(t, ctx, store, issue)
+	createTitled(t, ctx, store, "unrelated")
+
+	got, err := store.GetIssueByExternalRef(ctx, "gh-42")
+	if err != nil {
+		t.Fatalf("GetIssueByExternalRef failed: %v", err)
+	}
+	if got == nil || got.ID != issue.ID {
+		t.Fatalf("GetIssueByExternalRef(gh-42) = %v, want %s", got, issue.ID)
+	}
+
+	missing, err := store.GetIssueByExternalRef(ctx, "gh-404")
+	if err != nil {
+		t.Fatalf("GetIssueByExternalRef on unknown ref should not fail: %v", err)
+	}
+	if missing != nil {
+		t.Fatalf("GetIssueByExternalRef(gh-404) = %s, want nil", missing.ID)
+	}
+}
+
+func testUpdateFields(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("before"))
+	before := mustGet(t, ctx, store, issue.ID)
+	time.Sleep(10 * time.Millisecond)
+
+	updates := map[string]interface{}{
+		"title":       "after",
+		"description": "new description",
+		"notes":       "new notes",
+		"status":      string(types.StatusInProgress),
+		"priority":    0,
+		"assignee":    "bob",
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, updates, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	got := mustGet(t, ctx, store, issue.ID)
+	if got.Title != "after" || got.Description != "new description" || got.Notes != "new notes" {
+		t.Errorf("text fields not updated: %+v", got)
+	}
+	if got.Status != types.StatusInProgress || got.Priority != 0 || got.Assignee != "bob" {
+		t.Errorf("status/priority/assignee = %s/%d/%q, want in_progress/0/bob", got.Status, got.Priority, got.Assignee)
+	}
+	if !got.UpdatedAt.After(before.UpdatedAt) {
+		t.Errorf("UpdatedAt not advanced: before %v, after %v", before.UpdatedAt, got.UpdatedAt)
+	}
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"assignee": ""}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue(clear assignee) failed: %v", err)
+	}
+	if got := mustGet(t, ctx, store, issue.ID); got.Assignee != "" {
+		t.Errorf("Assignee = %q after clearing", got.Assignee)
+	}
+}
+
+func testUpdateMissingFails(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	err := store.UpdateIssue(ctx, s.b.Prefix+"-missing", map[string]interface{}{"title": "x"}, "tester")
+	if err == nil {
+		t.Fatal("UpdateIssue on a missing ID should fail")
+	}
+}
+
+func testCloseAndReopen(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("finish me"))
+	if err := store.CloseIssue(ctx, issue.ID, "shipped", "tester", "session-1"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	closed := mustGet(t, ctx, store, issue.ID)
+	if closed.Status != types.StatusClosed {
+		t.Errorf("Status = %s, want closed", closed.Status)
+	}
+	if closed.ClosedAt == nil {
+		t.Error("ClosedAt not set on close")
+	}
+	if closed.CloseReason != "shipped" {
+		t.Errorf("CloseReason = %q, want shipped", closed.CloseReason)
+	}
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusOpen)}, "tester"); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	reopened := mustGet(t, ctx, store, issue.ID)
+	if reopened.Status != types.StatusOpen {
+		t.Errorf("Status = %s after reopen, want open", reopened.Status)
+	}
+	if reopened.ClosedAt != nil {
+		t.Errorf("ClosedAt = %v after reopen, want nil", reopened.ClosedAt)
+	}
+}
+
+func testDelete(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "doomed", "survivor")
+	doomed, survivor := issues[0], issues[1]
+	addDep(t, ctx, store, doomed.ID, survivor.ID, types.DepBlocks)
+	if err := store.AddLabel(ctx, doomed.ID, "gone", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	if err := store.DeleteIssue(ctx, doomed.ID); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	if got, err := store.GetIssue(ctx, doomed.ID); err != nil || got != nil {
+		t.Fatalf("GetIssue after delete = %v, %v; want nil, nil", got, err)
+	}
+	labels, err := store.GetLabels(ctx, doomed.ID)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if len(labels) != 0 {
+		t.Errorf("labels survived delete: %v", labels)
+	}
+	dependents, err := store.GetDependents(ctx, survivor.ID)
+	if err != nil {
+		t.Fatalf("GetDependents failed: %v", err)
+	}
+	if len(dependents) != 0 {
+		t.Errorf("dependency on deleted issue survived: %v", sortedIDs(dependents))
+	}
+	mustGet(t, ctx, store, survivor.ID)
+}
+
+func testDeleteMissingFails(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	if err := store.DeleteIssue(ctx, s.b.Prefix+"-missing"); err == nil {
+		t.Fatal("DeleteIssue on a missing ID should fail")
+	}
+}
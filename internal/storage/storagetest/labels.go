@@ -0,0 +1,145 @@
+package storagetest
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+var labelTests = map[string]func(*testing.T, *suite){
+	"AddAndGet":        testLabelAddAndGet,
+	"AddIsIdempotent":  testLabelAddIsIdempotent,
+	"Remove":           testLabelRemove,
+	"ForIssues":        testLabelsForIssues,
+	"IssuesByLabel":    testIssuesByLabel,
+	"RejectsMissingID": testLabelRejectsMissingID,
+}
+
+func testLabelAddAndGet(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("labelled"))
+	for _, l := range []string{"ui", "bug-bash", "p1"} {
+		if err := store.AddLabel(ctx, issue.ID, l, "tester"); err != nil {
+			t.Fatalf("AddLabel(%s) failed: %v", l, err)
+		}
+	}
+	expectLabels(t, ctx, store, issue.ID, "bug-bash", "p1", "ui")
+}
+
+func testLabelAddIsIdempotent(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("labelled twice"))
+	for i := 0; i < 2; i++ {
+		if err := store.AddLabel(ctx, issue.ID, "dup", "tester"); err != nil {
+			t.Fatalf("AddLabel #%d failed: %v", i+1, err)
+		}
+	}
+	expectLabels(t, ctx, store, issue.ID, "dup")
+}
+
+func testLabelRemove(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("unlabelled"))
+	for _, l := range []string{"keep", "drop"} {
+		if err := store.AddLabel(ctx, issue.ID, l, "tester"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+	if err := store.RemoveLabel(ctx, issue.ID, "drop", "tester"); err != nil {
+		t.Fatalf("RemoveLabel failed: %v", err)
+	}
+	expectLabels(t, ctx, store, issue.ID, "keep")
+
+	if err := store.RemoveLabel(ctx, issue.ID, "never-there", "tester"); err != nil {
+		t.Errorf("RemoveLabel of an absent label should be a no-op, got %v", err)
+	}
+}
+
+func testLabelsForIssues(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b", "c")
+	a, b, c := issues[0], issues[1], issues[2]
+	for _, l := range []string{"x", "y"} {
+		if err := store.AddLabel(ctx, a.ID, l, "tester"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+	if err := store.AddLabel(ctx, b.ID, "z", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	got, err := store.GetLabelsForIssues(ctx, []string{a.ID, b.ID, c.ID})
+	if err != nil {
+		t.Fatalf("GetLabelsForIssues failed: %v", err)
+	}
+	la := append([]string(nil), got[a.ID]...)
+	sort.Strings(la)
+	if len(la) != 2 || la[0] != "x" || la[1] != "y" {
+		t.Errorf("labels for %s = %v, want [x y]", a.ID, got[a.ID])
+	}
+	if len(got[b.ID]) != 1 || got[b.ID][0] != "z" {
+		t.Errorf("labels for %s = %v, want [z]", b.ID, got[b.ID])
+	}
+	if len(got[c.ID]) != 0 {
+		t.Errorf("labels for %s = %v, want none", c.ID, got[c.ID])
+	}
+
+	empty, err := store.GetLabelsForIssues(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetLabelsForIssues(nil) failed: %v", err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("GetLabelsForIssues(nil) = %v, want empty", empty)
+	}
+}
+
+func testIssuesByLabel(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b", "c")
+	for _, issue := range issues[:2] {
+		if err := store.AddLabel(ctx, issue.ID, "release", "tester"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	got, err := store.GetIssuesByLabel(ctx, "release")
+	if err != nil {
+		t.Fatalf("GetIssuesByLabel failed: %v", err)
+	}
+	expectIDs(t, "GetIssuesByLabel(release)", sortedIDs(got), idsOf(issues[0], issues[1]))
+}
+
+func testLabelRejectsMissingID(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	if err := store.AddLabel(ctx, s.b.Prefix+"-missing", "x", "tester"); err == nil {
+		t.Fatal("AddLabel on a missing issue should fail")
+	}
+}
+
+// expectLabels compares the labels of issueID, ignoring order.
+func expectLabels(t *testing.T, ctx context.Context, store storage.Storage, issueID string, want ...string) {
+	t.Helper()
+	got, err := store.GetLabels(ctx, issueID)
+	if err != nil {
+		t.Fatalf("GetLabels(%s) failed: %v", issueID, err)
+	}
+	got = append([]string(nil), got...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Fatalf("labels of %s = %v, want %v", issueID, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("labels of %s = %v, want %v", issueID, got, want)
+		}
+	}
+}
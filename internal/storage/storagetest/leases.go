@@ -0,0 +1,215 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var leaseTests = map[string]func(*testing.T, *suite){
+	"AcquireAndGet":  testLeaseAcquireAndGet,
+	"CompareAndSet":  testLeaseCompareAndSet,
+	"Release":        testLeaseRelease,
+	"Expire":         testLeaseExpire,
+	"HidesReadyWork": testLeaseHidesReadyWork,
+	"TxActivity":     testLeaseTxActivity,
+}
+
+func testLeaseAcquireAndGet(t *testing.T, s *suite) {
+	s.require(t, Leases, Transactions)
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("claimed"))
+	if lease, err := store.GetLease(ctx, issue.ID); err != nil || lease != nil {
+		t.Fatalf("GetLease before acquire = %+v, %v; want nil", lease, err)
+	}
+	if !acquire(t, ctx, store, issue.ID, "alice", time.Minute) {
+		t.Fatal("AcquireLease on an unleased issue was denied")
+	}
+
+	lease, err := store.GetLease(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLease failed: %v", err)
+	}
+	if lease == nil || lease.Holder != "alice" || lease.TTL != time.Minute {
+		t.Fatalf("GetLease = %+v, want alice's one-minute lease", lease)
+	}
+	if !lease.IsActive(time.Now()) {
+		t.Errorf("fresh lease is not active: expires %v", lease.ExpiresAt)
+	}
+}
+
+func testLeaseCompareAndSet(t *testing.T, s *suite) {
+	s.require(t, Leases, Transactions)
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("contended"))
+	if !acquire(t, ctx, store, issue.ID, "alice", time.Minute) {
+		t.Fatal("first AcquireLease was denied")
+	}
+	if acquire(t, ctx, store, issue.ID, "bob", time.Minute) {
+		t.Fatal("AcquireLease by a second holder should be denied while the lease is active")
+	}
+	if !acquire(t, ctx, store, issue.ID, "alice", time.Hour) {
+		t.Fatal("AcquireLease by the current holder should refresh the lease")
+	}
+	lease, err := store.GetLease(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLease failed: %v", err)
+	}
+	if lease == nil || lease.Holder != "alice" || lease.TTL != time.Hour {
+		t.Errorf("GetLease after refresh = %+v, want alice's one-hour lease", lease)
+	}
+}
+
+func testLeaseRelease(t *testing.T, s *suite) {
+	s.require(t, Leases, Transactions)
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("released"))
+	acquire(t, ctx, store, issue.ID, "alice", time.Minute)
+
+	if err := store.ReleaseLease(ctx, issue.ID, "bob"); err != nil {
+		t.Fatalf("ReleaseLease by a non-holder failed: %v", err)
+	}
+	if lease, _ := store.GetLease(ctx, issue.ID); lease == nil {
+		t.Fatal("ReleaseLease by a non-holder dropped the lease")
+	}
+	if err := store.ReleaseLease(ctx, issue.ID, "alice"); err != nil {
+		t.Fatalf("ReleaseLease failed: %v", err)
+	}
+	if lease, err := store.GetLease(ctx, issue.ID); err != nil || lease != nil {
+		t.Errorf("GetLease after release = %+v, %v; want nil", lease, err)
+	}
+	if !acquire(t, ctx, store, issue.ID, "bob", time.Minute) {
+		t.Error("AcquireLease after release was denied")
+	}
+}
+
+func testLeaseExpire(t *testing.T, s *suite) {
+	s.require(t, Leases, Transactions)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "abandoned", "held", "agent")
+	abandoned, held, agent := issues[0], issues[1], issues[2]
+	for _, issue := range issues[:2] {
+		updates := map[string]interface{}{"status": string(types.StatusInProgress), "assignee": "alice"}
+		if err := store.UpdateIssue(ctx, issue.ID, updates, "alice"); err != nil {
+			t.Fatalf("UpdateIssue failed: %v", err)
+		}
+	}
+	acquire(t, ctx, store, abandoned.ID, "alice", 10*time.Millisecond)
+	acquire(t, ctx, store, held.ID, "alice", time.Hour)
+
+	// An issue leased by an agent bead and on its hook, as bd swarm dispatch does
+	hooked := create(t, ctx, store, newIssue("hooked"))
+	updates := map[string]interface{}{"status": string(types.StatusInProgress), "assignee": agent.ID}
+	if err := store.UpdateIssue(ctx, hooked.ID, updates, agent.ID); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, agent.ID, map[string]interface{}{"hook_bead": hooked.ID}, "dispatcher"); err != nil {
+		t.Fatalf("UpdateIssue hook_bead failed: %v", err)
+	}
+	acquire(t, ctx, store, hooked.ID, agent.ID, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	reopened, err := store.ExpireLeases(ctx, "reaper")
+	if err != nil {
+		t.Fatalf("ExpireLeases failed: %v", err)
+	}
+	expectIDs(t, "ExpireLeases", reopened, idsOf(abandoned, hooked))
+
+	got := mustGet(t, ctx, store, abandoned.ID)
+	if got.Status != types.StatusOpen || got.Assignee != "" {
+		t.Errorf("expired issue = %s assigned to %q, want open and unassigned", got.Status, got.Assignee)
+	}
+	if lease, _ := store.GetLease(ctx, abandoned.ID); lease != nil {
+		t.Errorf("expired lease survived: %+v", lease)
+	}
+	if got := mustGet(t, ctx, store, held.ID); got.Status != types.StatusInProgress {
+		t.Errorf("issue with an active lease was reopened: %s", got.Status)
+	}
+	if got := mustGet(t, ctx, store, agent.ID); got.HookBead != "" {
+		t.Errorf("agent still has %s on its hook after its lease expired", got.HookBead)
+	}
+}
+
+// testLeaseTxActivity checks that updates and comments by the holder inside
+// a transaction renew the lease, and closing the issue there releases it.
+func testLeaseTxActivity(t *testing.T, s *suite) {
+	s.require(t, Leases, Transactions)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "updated", "commented", "closed")
+	updated, commented, closed := issues[0], issues[1], issues[2]
+	for _, issue := range issues {
+		acquire(t, ctx, store, issue.ID, "alice", time.Minute)
+	}
+	before := make(map[string]time.Time)
+	for _, issue := range issues[:2] {
+		lease, err := store.GetLease(ctx, issue.ID)
+		if err != nil || lease == nil {
+			t.Fatalf("GetLease(%s) = %+v, %v", issue.ID, lease, err)
+		}
+		before[issue.ID] = lease.ExpiresAt
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	err := store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		if err := tx.UpdateIssue(ctx, updated.ID, map[string]interface{}{"notes": "progress"}, "alice"); err != nil {
+			return err
+		}
+		if err := tx.AddComment(ctx, commented.ID, "alice", "progress"); err != nil {
+			return err
+		}
+		return tx.CloseIssue(ctx, closed.ID, "done", "alice", "")
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction failed: %v", err)
+	}
+
+	for _, issue := range issues[:2] {
+		lease, err := store.GetLease(ctx, issue.ID)
+		if err != nil || lease == nil {
+			t.Fatalf("GetLease(%s) after activity = %+v, %v", issue.ID, lease, err)
+		}
+		if !lease.ExpiresAt.After(before[issue.ID]) {
+			t.Errorf("lease on %q not renewed by activity: expires %v, was %v", issue.Title, lease.ExpiresAt, before[issue.ID])
+		}
+	}
+	if lease, err := store.GetLease(ctx, closed.ID); err != nil || lease != nil {
+		t.Errorf("GetLease after close in a transaction = %+v, %v; want nil", lease, err)
+	}
+}
+
+func testLeaseHidesReadyWork(t *testing.T, s *suite) {
+	s.require(t, Leases, Transactions)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "mine", "theirs", "free")
+	mine, theirs, free := issues[0], issues[1], issues[2]
+	acquire(t, ctx, store, mine.ID, "alice", time.Minute)
+	acquire(t, ctx, store, theirs.ID, "bob", time.Minute)
+
+	expectIDs(t, "ready for alice", ready(t, ctx, store, types.WorkFilter{LeaseHolder: "alice"}), idsOf(mine, free))
+	expectIDs(t, "ready for anyone", ready(t, ctx, store, types.WorkFilter{}), idsOf(free))
+}
+
+// acquire runs AcquireLease in its own transaction and reports whether it
+// was granted.
+func acquire(t *testing.T, ctx context.Context, store storage.Storage, issueID, holder string, ttl time.Duration) bool {
+	t.Helper()
+	var granted bool
+	err := store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		var err error
+		granted, err = tx.AcquireLease(ctx, issueID, holder, ttl)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("AcquireLease(%s, %s) failed: %v", issueID, holder, err)
+	}
+	return granted
+}
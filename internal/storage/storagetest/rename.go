@@ -0,0 +1,99 @@
+package storagetest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var renameTests = map[string]func(*testing.T, *suite){
+	"UpdateIssueID":          testRenameUpdateIssueID,
+	"UpdateIssueIDMissing":   testRenameUpdateIssueIDMissing,
+	"RenameDependencyPrefix": testRenameDependencyPrefix,
+}
+
+func testRenameUpdateIssueID(t *testing.T, s *suite) {
+	s.require(t, RenameIssue)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "moving", "upstream", "downstream")
+	moving, upstream, downstream := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, moving.ID, upstream.ID, types.DepBlocks)
+	addDep(t, ctx, store, downstream.ID, moving.ID, types.DepBlocks)
+	if err := store.AddLabel(ctx, moving.ID, "keep-me", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	newID := s.b.Prefix + "-renamed"
+	moving.Title = "moved"
+	if err := store.UpdateIssueID(ctx, moving.ID, newID, moving, "tester"); err != nil {
+		t.Fatalf("UpdateIssueID failed: %v", err)
+	}
+
+	if old, err := store.GetIssue(ctx, moving.ID); err != nil || old != nil {
+		t.Errorf("old ID still resolves: %+v, %v", old, err)
+	}
+	if got := mustGet(t, ctx, store, newID); got.Title != "moved" {
+		t.Errorf("renamed issue title = %q, want moved", got.Title)
+	}
+	expectLabels(t, ctx, store, newID, "keep-me")
+
+	deps, err := store.GetDependencies(ctx, newID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	expectIDs(t, "dependencies after rename", sortedIDs(deps), idsOf(upstream))
+
+	dependents, err := store.GetDependents(ctx, newID)
+	if err != nil {
+		t.Fatalf("GetDependents failed: %v", err)
+	}
+	expectIDs(t, "dependents after rename", sortedIDs(dependents), idsOf(downstream))
+}
+
+func testRenameUpdateIssueIDMissing(t *testing.T, s *suite) {
+	s.require(t, RenameIssue)
+	ctx, store := s.open(t)
+
+	ghost := newIssue("ghost")
+	if err := store.UpdateIssueID(ctx, s.b.Prefix+"-missing", s.b.Prefix+"-other", ghost, "tester"); err == nil {
+		t.Fatal("UpdateIssueID on a missing issue should fail")
+	}
+}
+
+// testRenameDependencyPrefix follows bd rename-prefix: issues are renamed
+// one by one, then RenameDependencyPrefix sweeps any remaining references.
+func testRenameDependencyPrefix(t *testing.T, s *suite) {
+	s.require(t, RenameIssue)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b")
+	a, b := issues[0], issues[1]
+	addDep(t, ctx, store, a.ID, b.ID, types.DepBlocks)
+
+	oldPrefix, newPrefix := s.b.Prefix, "renamed"
+	renamed := make(map[string]string)
+	for _, issue := range issues {
+		newID := newPrefix + strings.TrimPrefix(issue.ID, oldPrefix)
+		if err := store.UpdateIssueID(ctx, issue.ID, newID, issue, "tester"); err != nil {
+			t.Fatalf("UpdateIssueID failed: %v", err)
+		}
+		renamed[issue.ID] = newID
+	}
+	if err := store.RenameDependencyPrefix(ctx, oldPrefix, newPrefix); err != nil {
+		t.Fatalf("RenameDependencyPrefix failed: %v", err)
+	}
+
+	all, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		t.Fatalf("GetAllDependencyRecords failed: %v", err)
+	}
+	records := all[renamed[a.ID]]
+	if len(records) != 1 || records[0].DependsOnID != renamed[b.ID] {
+		t.Errorf("dependency records after rename = %v, want %s -> %s", all, renamed[a.ID], renamed[b.ID])
+	}
+	if len(all[a.ID]) != 0 {
+		t.Errorf("dependency still recorded under old ID %s", a.ID)
+	}
+}
@@ -0,0 +1,412 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var searchTests = map[string]func(*testing.T, *suite){
+	"Query":               testSearchQuery,
+	"CoreFilters":         testSearchCoreFilters,
+	"Labels":              testSearchLabels,
+	"IDs":                 testSearchIDs,
+	"ParentID":            testSearchParentID,
+	"Limit":               testSearchLimit,
+	"Order":               testSearchOrder,
+	"Tombstones":          testSearchTombstones,
+	"ExcludeStatus":       testSearchExcludeStatus,
+	"ExcludeTypes":        testSearchExcludeTypes,
+	"LabelsAny":           testSearchLabelsAny,
+	"TextContains":        testSearchTextContains,
+	"EmptyChecks":         testSearchEmptyChecks,
+	"PriorityRange":       testSearchPriorityRange,
+	"Flags":               testSearchFlags,
+	"MolType":             testSearchMolType,
+	"DateRanges":          testSearchDateRanges,
+	"SchedulingFilters":   testSearchSchedulingFilters,
+	"TransactionReadBack": testSearchTransactionReadBack,
+}
+
+func testSearchQuery(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	login := create(t, ctx, store, newIssue("Fix login redirect"))
+	other := newIssue("Refactor parser")
+	other.Description = "The LOGIN page is unaffected"
+	create(t, ctx, store, other)
+	createTitled(t, ctx, store, "Update docs")
+
+	issues, err := store.SearchIssues(ctx, "login", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	expectIDs(t, "query matches title and description case-insensitively", sortedIDs(issues), idsOf(login, other))
+
+	issues, err = store.SearchIssues(ctx, login.ID, types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues by ID failed: %v", err)
+	}
+	if !contains(sortedIDs(issues), login.ID) {
+		t.Errorf("query %q should match the issue's own ID", login.ID)
+	}
+}
+
+func testSearchCoreFilters(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	bug := newIssue("bug")
+	bug.IssueType = types.TypeBug
+	bug.Priority = 0
+	bug.Assignee = "alice"
+	create(t, ctx, store, bug)
+
+	feature := newIssue("feature")
+	feature.IssueType = types.TypeFeature
+	feature.Priority = 1
+	feature.Assignee = "bob"
+	create(t, ctx, store, feature)
+
+	task := create(t, ctx, store, newIssue("task"))
+	closeIssue(t, ctx, store, task.ID)
+
+	expectIDs(t, "Status=closed", search(t, ctx, store, types.IssueFilter{Status: ptr(types.StatusClosed)}), idsOf(task))
+	expectIDs(t, "Status=open", search(t, ctx, store, types.IssueFilter{Status: ptr(types.StatusOpen)}), idsOf(bug, feature))
+	expectIDs(t, "Priority=0", search(t, ctx, store, types.IssueFilter{Priority: ptr(0)}), idsOf(bug))
+	expectIDs(t, "IssueType=feature", search(t, ctx, store, types.IssueFilter{IssueType: ptr(types.TypeFeature)}), idsOf(feature))
+	expectIDs(t, "Assignee=alice", search(t, ctx, store, types.IssueFilter{Assignee: ptr("alice")}), idsOf(bug))
+	expectIDs(t, "combined filters", search(t, ctx, store, types.IssueFilter{
+		Status:   ptr(types.StatusOpen),
+		Assignee: ptr("bob"),
+	}), idsOf(feature))
+	expectIDs(t, "no filter", search(t, ctx, store, types.IssueFilter{}), idsOf(bug, feature, task))
+}
+
+func testSearchLabels(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "both", "frontend only", "none")
+	both, frontend := issues[0], issues[1]
+	for _, l := range []string{"frontend", "urgent"} {
+		if err := store.AddLabel(ctx, both.ID, l, "tester"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+	if err := store.AddLabel(ctx, frontend.ID, "frontend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	expectIDs(t, "Labels=[frontend]", search(t, ctx, store, types.IssueFilter{Labels: []string{"frontend"}}), idsOf(both, frontend))
+	expectIDs(t, "Labels=[frontend urgent] (AND)", search(t, ctx, store, types.IssueFilter{Labels: []string{"frontend", "urgent"}}), idsOf(both))
+}
+
+func testSearchIDs(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b", "c")
+	expectIDs(t, "IDs", search(t, ctx, store, types.IssueFilter{IDs: []string{issues[0].ID, issues[2].ID}}), idsOf(issues[0], issues[2]))
+
+	parent := issues[1]
+	child := newIssue("child")
+	child.ID = parent.ID + ".1"
+	create(t, ctx, store, child)
+	expectIDs(t, "IDPrefix", search(t, ctx, store, types.IssueFilter{IDPrefix: parent.ID}), idsOf(parent, child))
+}
+
+func testSearchParentID(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "epic", "child a", "child b", "grandchild", "stranger")
+	epic, a, b, grandchild := issues[0], issues[1], issues[2], issues[3]
+	addDep(t, ctx, store, a.ID, epic.ID, types.DepParentChild)
+	addDep(t, ctx, store, b.ID, epic.ID, types.DepParentChild)
+	addDep(t, ctx, store, grandchild.ID, a.ID, types.DepParentChild)
+
+	// IssueFilter.ParentID matches direct children only
+	expectIDs(t, "ParentID", search(t, ctx, store, types.IssueFilter{ParentID: &epic.ID}), idsOf(a, b))
+}
+
+func testSearchLimit(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	createTitled(t, ctx, store, "a", "b", "c", "d")
+	if got := search(t, ctx, store, types.IssueFilter{Limit: 2}); len(got) != 2 {
+		t.Fatalf("Limit=2 returned %d issues", len(got))
+	}
+}
+
+func testSearchOrder(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	low := newIssue("low")
+	low.Priority = 3
+	create(t, ctx, store, low)
+	high := newIssue("high")
+	high.Priority = 0
+	create(t, ctx, store, high)
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(issues) != 2 || issues[0].ID != high.ID {
+		t.Fatalf("SearchIssues should order by priority first, got %v", sortedIDs(issues))
+	}
+}
+
+func testSearchTombstones(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	live := create(t, ctx, store, newIssue("live"))
+	dead := createTombstone(t, ctx, store, "dead")
+
+	expectIDs(t, "default excludes tombstones", search(t, ctx, store, types.IssueFilter{}), idsOf(live))
+	expectIDs(t, "IncludeTombstones", search(t, ctx, store, types.IssueFilter{IncludeTombstones: true}), idsOf(live, dead))
+	expectIDs(t, "Status=tombstone", search(t, ctx, store, types.IssueFilter{Status: ptr(types.StatusTombstone)}), idsOf(dead))
+}
+
+func testSearchExcludeStatus(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "open", "working", "done")
+	open, working, done := issues[0], issues[1], issues[2]
+	if err := store.UpdateIssue(ctx, working.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	closeIssue(t, ctx, store, done.ID)
+
+	expectIDs(t, "ExcludeStatus=[closed]", search(t, ctx, store, types.IssueFilter{
+		ExcludeStatus: []types.Status{types.StatusClosed},
+	}), idsOf(open, working))
+	expectIDs(t, "ExcludeStatus=[closed in_progress]", search(t, ctx, store, types.IssueFilter{
+		ExcludeStatus: []types.Status{types.StatusClosed, types.StatusInProgress},
+	}), idsOf(open))
+}
+
+func testSearchExcludeTypes(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	if err := store.SetConfig(ctx, "types.custom", "gate"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	task := create(t, ctx, store, newIssue("task"))
+	gate := newIssue("gate")
+	gate.IssueType = types.TypeGate
+	create(t, ctx, store, gate)
+
+	expectIDs(t, "ExcludeTypes=[gate]", search(t, ctx, store, types.IssueFilter{
+		ExcludeTypes: []types.IssueType{types.TypeGate},
+	}), idsOf(task))
+}
+
+func testSearchLabelsAny(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "ui", "api", "docs")
+	if err := store.AddLabel(ctx, issues[0].ID, "frontend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, issues[1].ID, "backend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	expectIDs(t, "LabelsAny (OR)", search(t, ctx, store, types.IssueFilter{
+		LabelsAny: []string{"frontend", "backend"},
+	}), idsOf(issues[0], issues[1]))
+}
+
+func testSearchTextContains(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	a := newIssue("Crash on startup")
+	a.Description = "segfault in loader"
+	a.Notes = "seen on arm64"
+	create(t, ctx, store, a)
+	b := newIssue("Slow startup")
+	b.Description = "profile the loader"
+	create(t, ctx, store, b)
+
+	expectIDs(t, "TitleSearch", search(t, ctx, store, types.IssueFilter{TitleSearch: "crash"}), idsOf(a))
+	expectIDs(t, "TitleContains", search(t, ctx, store, types.IssueFilter{TitleContains: "startup"}), idsOf(a, b))
+	expectIDs(t, "DescriptionContains", search(t, ctx, store, types.IssueFilter{DescriptionContains: "segfault"}), idsOf(a))
+	expectIDs(t, "NotesContains", search(t, ctx, store, types.IssueFilter{NotesContains: "arm64"}), idsOf(a))
+}
+
+func testSearchEmptyChecks(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	bare := create(t, ctx, store, newIssue("bare"))
+	full := newIssue("full")
+	full.Description = "described"
+	full.Assignee = "alice"
+	create(t, ctx, store, full)
+	if err := store.AddLabel(ctx, full.ID, "tagged", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	expectIDs(t, "EmptyDescription", search(t, ctx, store, types.IssueFilter{EmptyDescription: true}), idsOf(bare))
+	expectIDs(t, "NoAssignee", search(t, ctx, store, types.IssueFilter{NoAssignee: true}), idsOf(bare))
+	expectIDs(t, "NoLabels", search(t, ctx, store, types.IssueFilter{NoLabels: true}), idsOf(bare))
+}
+
+func testSearchPriorityRange(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	byPriority := make([]*types.Issue, 5)
+	for p := 0; p <= 4; p++ {
+		issue := newIssue("p")
+		issue.Priority = p
+		byPriority[p] = create(t, ctx, store, issue)
+	}
+
+	expectIDs(t, "PriorityMin=3", search(t, ctx, store, types.IssueFilter{PriorityMin: ptr(3)}), idsOf(byPriority[3], byPriority[4]))
+	expectIDs(t, "PriorityMax=1", search(t, ctx, store, types.IssueFilter{PriorityMax: ptr(1)}), idsOf(byPriority[0], byPriority[1]))
+	expectIDs(t, "PriorityMin=1 PriorityMax=2", search(t, ctx, store, types.IssueFilter{
+		PriorityMin: ptr(1), PriorityMax: ptr(2),
+	}), idsOf(byPriority[1], byPriority[2]))
+}
+
+func testSearchFlags(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	plain := create(t, ctx, store, newIssue("plain"))
+	wisp := newIssue("wisp")
+	wisp.Ephemeral = true
+	create(t, ctx, store, wisp)
+	pinned := newIssue("pinned")
+	pinned.Pinned = true
+	create(t, ctx, store, pinned)
+	template := newIssue("template")
+	template.IsTemplate = true
+	create(t, ctx, store, template)
+
+	expectIDs(t, "Ephemeral=true", search(t, ctx, store, types.IssueFilter{Ephemeral: ptr(true)}), idsOf(wisp))
+	expectIDs(t, "Ephemeral=false", search(t, ctx, store, types.IssueFilter{Ephemeral: ptr(false)}), idsOf(plain, pinned, template))
+	expectIDs(t, "Pinned=true", search(t, ctx, store, types.IssueFilter{Pinned: ptr(true)}), idsOf(pinned))
+	expectIDs(t, "Pinned=false", search(t, ctx, store, types.IssueFilter{Pinned: ptr(false)}), idsOf(plain, wisp, template))
+	expectIDs(t, "IsTemplate=true", search(t, ctx, store, types.IssueFilter{IsTemplate: ptr(true)}), idsOf(template))
+	expectIDs(t, "IsTemplate=false", search(t, ctx, store, types.IssueFilter{IsTemplate: ptr(false)}), idsOf(plain, wisp, pinned))
+}
+
+func testSearchMolType(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	swarm := newIssue("swarm")
+	swarm.MolType = types.MolTypeSwarm
+	create(t, ctx, store, swarm)
+	patrol := newIssue("patrol")
+	patrol.MolType = types.MolTypePatrol
+	create(t, ctx, store, patrol)
+
+	expectIDs(t, "MolType=swarm", search(t, ctx, store, types.IssueFilter{MolType: ptr(types.MolTypeSwarm)}), idsOf(swarm))
+}
+
+func testSearchDateRanges(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields, ExplicitTimestamps)
+	ctx, store := s.open(t)
+
+	old := newIssue("old")
+	old.CreatedAt = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	old.UpdatedAt = old.CreatedAt
+	create(t, ctx, store, old)
+	recent := newIssue("recent")
+	recent.CreatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent.UpdatedAt = recent.CreatedAt
+	create(t, ctx, store, recent)
+	closed := create(t, ctx, store, newIssue("closed now"))
+	closeIssue(t, ctx, store, closed.ID)
+
+	cutoff := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectIDs(t, "CreatedBefore", search(t, ctx, store, types.IssueFilter{CreatedBefore: &cutoff}), idsOf(old))
+	expectIDs(t, "CreatedAfter", search(t, ctx, store, types.IssueFilter{CreatedAfter: &cutoff}), idsOf(recent, closed))
+	expectIDs(t, "UpdatedBefore", search(t, ctx, store, types.IssueFilter{UpdatedBefore: &cutoff}), idsOf(old))
+	expectIDs(t, "UpdatedAfter", search(t, ctx, store, types.IssueFilter{UpdatedAfter: &cutoff}), idsOf(recent, closed))
+	expectIDs(t, "ClosedAfter", search(t, ctx, store, types.IssueFilter{ClosedAfter: &cutoff}), idsOf(closed))
+
+	future := time.Now().Add(24 * time.Hour)
+	expectIDs(t, "ClosedBefore", search(t, ctx, store, types.IssueFilter{ClosedBefore: &future}), idsOf(closed))
+}
+
+func testSearchSchedulingFilters(t *testing.T, s *suite) {
+	s.require(t, IssueFilterFields)
+	ctx, store := s.open(t)
+
+	past := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	future := time.Date(2099, 6, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	plain := create(t, ctx, store, newIssue("plain"))
+	deferred := newIssue("deferred")
+	deferred.DeferUntil = &future
+	create(t, ctx, store, deferred)
+	wasDeferred := newIssue("was deferred")
+	wasDeferred.DeferUntil = &past
+	create(t, ctx, store, wasDeferred)
+	overdue := newIssue("overdue")
+	overdue.DueAt = &past
+	create(t, ctx, store, overdue)
+	dueLater := newIssue("due later")
+	dueLater.DueAt = &future
+	create(t, ctx, store, dueLater)
+	overdueClosed := newIssue("overdue but closed")
+	overdueClosed.DueAt = &past
+	create(t, ctx, store, overdueClosed)
+	closeIssue(t, ctx, store, overdueClosed.ID)
+
+	expectIDs(t, "Deferred", search(t, ctx, store, types.IssueFilter{Deferred: true}), idsOf(deferred, wasDeferred))
+	expectIDs(t, "DeferAfter", search(t, ctx, store, types.IssueFilter{DeferAfter: &cutoff}), idsOf(deferred))
+	expectIDs(t, "DeferBefore", search(t, ctx, store, types.IssueFilter{DeferBefore: &cutoff}), idsOf(wasDeferred))
+	expectIDs(t, "DueAfter", search(t, ctx, store, types.IssueFilter{DueAfter: &cutoff}), idsOf(dueLater))
+	expectIDs(t, "DueBefore", search(t, ctx, store, types.IssueFilter{DueBefore: &cutoff}), idsOf(overdue, overdueClosed))
+	expectIDs(t, "Overdue", search(t, ctx, store, types.IssueFilter{Overdue: true}), idsOf(overdue))
+	if contains(search(t, ctx, store, types.IssueFilter{Deferred: true}), plain.ID) {
+		t.Errorf("Deferred matched %s, which has no defer_until", plain.ID)
+	}
+}
+
+func testSearchTransactionReadBack(t *testing.T, s *suite) {
+	s.require(t, Transactions)
+	ctx, store := s.open(t)
+
+	err := store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		issue := newIssue("created in tx")
+		if err := tx.CreateIssue(ctx, issue, "tester"); err != nil {
+			return err
+		}
+		found, err := tx.SearchIssues(ctx, "created in tx", types.IssueFilter{})
+		if err != nil {
+			return err
+		}
+		expectIDs(t, "search inside transaction", sortedIDs(found), idsOf(issue))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction failed: %v", err)
+	}
+}
+
+// createTombstone creates an issue directly in the tombstone state, the way
+// an import of a deleted issue does.
+func createTombstone(t *testing.T, ctx context.Context, store storage.Storage, title string) *types.Issue {
+	t.Helper()
+	deletedAt := time.Now().UTC()
+	issue := newIssue(title)
+	issue.Status = types.StatusTombstone
+	issue.DeletedAt = &deletedAt
+	issue.DeletedBy = "tester"
+	issue.DeleteReason = "test"
+	issue.OriginalType = string(types.TypeTask)
+	return create(t, ctx, store, issue)
+}
@@ -0,0 +1,378 @@
+package storagetest
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var dirtyTests = map[string]func(*testing.T, *suite){
+	"MarkedOnWrite":   testDirtyMarkedOnWrite,
+	"ClearByID":       testDirtyClearByID,
+	"DirtyHash":       testDirtyHash,
+	"ExportHashes":    testExportHashes,
+	"JSONLFileHash":   testJSONLFileHash,
+	"LabelMarksDirty": testDirtyLabelMarksDirty,
+}
+
+var configTests = map[string]func(*testing.T, *suite){
+	"SetGetDelete":   testConfigSetGetDelete,
+	"Metadata":       testConfigMetadata,
+	"CustomStatuses": testConfigCustomStatuses,
+	"CustomTypes":    testConfigCustomTypes,
+}
+
+var childIDTests = map[string]func(*testing.T, *suite){
+	"Sequential":       testChildIDSequential,
+	"PerParent":        testChildIDPerParent,
+	"MissingParent":    testChildIDMissingParent,
+	"SkipsExplicitIDs": testChildIDSkipsExplicitIDs,
+	"NestedGrandchild": testChildIDNested,
+}
+
+var statisticsTests = map[string]func(*testing.T, *suite){
+	"Counts": testStatisticsCounts,
+}
+
+func testDirtyMarkedOnWrite(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b")
+	dirty, err := store.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	expectIDs(t, "dirty after create", dirty, idsOf(issues...))
+
+	if err := store.ClearDirtyIssuesByID(ctx, dirty); err != nil {
+		t.Fatalf("ClearDirtyIssuesByID failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, issues[1].ID, map[string]interface{}{"title": "b2"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	dirty, err = store.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	expectIDs(t, "dirty after update", dirty, idsOf(issues[1]))
+}
+
+func testDirtyClearByID(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b", "c")
+	if err := store.ClearDirtyIssuesByID(ctx, []string{issues[0].ID, issues[2].ID}); err != nil {
+		t.Fatalf("ClearDirtyIssuesByID failed: %v", err)
+	}
+	dirty, err := store.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	expectIDs(t, "dirty after partial clear", dirty, idsOf(issues[1]))
+
+	if err := store.ClearDirtyIssuesByID(ctx, nil); err != nil {
+		t.Fatalf("ClearDirtyIssuesByID(nil) failed: %v", err)
+	}
+}
+
+func testDirtyLabelMarksDirty(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("labelled"))
+	if err := store.ClearDirtyIssuesByID(ctx, []string{issue.ID}); err != nil {
+		t.Fatalf("ClearDirtyIssuesByID failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "new", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	dirty, err := store.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	expectIDs(t, "dirty after AddLabel", dirty, idsOf(issue))
+}
+
+func testDirtyHash(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issue := create(t, ctx, store, newIssue("hashed"))
+	if err := store.ClearDirtyIssuesByID(ctx, []string{issue.ID}); err != nil {
+		t.Fatalf("ClearDirtyIssuesByID failed: %v", err)
+	}
+	hash, err := store.GetDirtyIssueHash(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetDirtyIssueHash on a clean issue should not fail: %v", err)
+	}
+	if hash != "" {
+		t.Errorf("GetDirtyIssueHash on a clean issue = %q, want empty", hash)
+	}
+}
+
+func testExportHashes(t *testing.T, s *suite) {
+	s.require(t, ExportHashes)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b")
+	if err := store.SetExportHash(ctx, issues[0].ID, "abc123"); err != nil {
+		t.Fatalf("SetExportHash failed: %v", err)
+	}
+	if err := store.SetExportHash(ctx, issues[0].ID, "def456"); err != nil {
+		t.Fatalf("SetExportHash (overwrite) failed: %v", err)
+	}
+	if got, err := store.GetExportHash(ctx, issues[0].ID); err != nil || got != "def456" {
+		t.Errorf("GetExportHash = %q, %v; want def456", got, err)
+	}
+	if got, err := store.GetExportHash(ctx, issues[1].ID); err != nil || got != "" {
+		t.Errorf("GetExportHash for unexported issue = %q, %v; want empty", got, err)
+	}
+
+	if err := store.ClearAllExportHashes(ctx); err != nil {
+		t.Fatalf("ClearAllExportHashes failed: %v", err)
+	}
+	if got, err := store.GetExportHash(ctx, issues[0].ID); err != nil || got != "" {
+		t.Errorf("GetExportHash after clear = %q, %v; want empty", got, err)
+	}
+}
+
+func testJSONLFileHash(t *testing.T, s *suite) {
+	s.require(t, ExportHashes)
+	ctx, store := s.open(t)
+
+	if got, err := store.GetJSONLFileHash(ctx); err != nil || got != "" {
+		t.Fatalf("GetJSONLFileHash on a new store = %q, %v; want empty", got, err)
+	}
+	if err := store.SetJSONLFileHash(ctx, "feedface"); err != nil {
+		t.Fatalf("SetJSONLFileHash failed: %v", err)
+	}
+	if got, err := store.GetJSONLFileHash(ctx); err != nil || got != "feedface" {
+		t.Errorf("GetJSONLFileHash = %q, %v; want feedface", got, err)
+	}
+}
+
+func testConfigSetGetDelete(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	if err := store.SetConfig(ctx, "sync.branch", "beads-sync"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	if err := store.SetConfig(ctx, "sync.branch", "main"); err != nil {
+		t.Fatalf("SetConfig (overwrite) failed: %v", err)
+	}
+	if got, err := store.GetConfig(ctx, "sync.branch"); err != nil || got != "main" {
+		t.Errorf("GetConfig = %q, %v; want main", got, err)
+	}
+	if got, err := store.GetConfig(ctx, "no.such.key"); err != nil || got != "" {
+		t.Errorf("GetConfig on a missing key = %q, %v; want empty, nil", got, err)
+	}
+
+	all, err := store.GetAllConfig(ctx)
+	if err != nil {
+		t.Fatalf("GetAllConfig failed: %v", err)
+	}
+	if all["sync.branch"] != "main" || all["issue_prefix"] != s.b.Prefix {
+		t.Errorf("GetAllConfig = %v, want sync.branch=main and issue_prefix=%s", all, s.b.Prefix)
+	}
+
+	if err := store.DeleteConfig(ctx, "sync.branch"); err != nil {
+		t.Fatalf("DeleteConfig failed: %v", err)
+	}
+	if got, err := store.GetConfig(ctx, "sync.branch"); err != nil || got != "" {
+		t.Errorf("GetConfig after delete = %q, %v; want empty", got, err)
+	}
+}
+
+func testConfigMetadata(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	if err := store.SetMetadata(ctx, "last_import_hash", "h1"); err != nil {
+		t.Fatalf("SetMetadata failed: %v", err)
+	}
+	if err := store.SetMetadata(ctx, "last_import_hash", "h2"); err != nil {
+		t.Fatalf("SetMetadata (overwrite) failed: %v", err)
+	}
+	if got, err := store.GetMetadata(ctx, "last_import_hash"); err != nil || got != "h2" {
+		t.Errorf("GetMetadata = %q, %v; want h2", got, err)
+	}
+	if got, err := store.GetMetadata(ctx, "missing"); err != nil || got != "" {
+		t.Errorf("GetMetadata on a missing key = %q, %v; want empty, nil", got, err)
+	}
+	// Metadata and config are separate namespaces
+	if got, err := store.GetConfig(ctx, "last_import_hash"); err != nil || got != "" {
+		t.Errorf("metadata leaked into config: %q, %v", got, err)
+	}
+}
+
+func testConfigCustomStatuses(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	if got, err := store.GetCustomStatuses(ctx); err != nil || len(got) != 0 {
+		t.Fatalf("GetCustomStatuses with none configured = %v, %v", got, err)
+	}
+	if err := store.SetConfig(ctx, "status.custom", "review, qa"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	got, err := store.GetCustomStatuses(ctx)
+	if err != nil {
+		t.Fatalf("GetCustomStatuses failed: %v", err)
+	}
+	sort.Strings(got)
+	if strings.Join(got, ",") != "qa,review" {
+		t.Errorf("GetCustomStatuses = %v, want [qa review]", got)
+	}
+
+	// Custom statuses are accepted on create and update
+	issue := newIssue("in review")
+	issue.Status = "review"
+	create(t, ctx, store, issue)
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": "qa"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue to custom status failed: %v", err)
+	}
+	if got := mustGet(t, ctx, store, issue.ID); got.Status != "qa" {
+		t.Errorf("Status = %s, want qa", got.Status)
+	}
+}
+
+func testConfigCustomTypes(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	if err := store.SetConfig(ctx, "types.custom", "spike,incident"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	got, err := store.GetCustomTypes(ctx)
+	if err != nil {
+		t.Fatalf("GetCustomTypes failed: %v", err)
+	}
+	sort.Strings(got)
+	if strings.Join(got, ",") != "incident,spike" {
+		t.Errorf("GetCustomTypes = %v, want [incident spike]", got)
+	}
+
+	issue := newIssue("investigate")
+	issue.IssueType = "spike"
+	create(t, ctx, store, issue)
+	if got := mustGet(t, ctx, store, issue.ID); got.IssueType != "spike" {
+		t.Errorf("IssueType = %s, want spike", got.IssueType)
+	}
+}
+
+func testChildIDSequential(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	parent := create(t, ctx, store, newIssue("parent"))
+	for i := 1; i <= 3; i++ {
+		id, err := store.GetNextChildID(ctx, parent.ID)
+		if err != nil {
+			t.Fatalf("GetNextChildID failed: %v", err)
+		}
+		if want := parent.ID + "." + itoa(i); id != want {
+			t.Fatalf("child %d ID = %s, want %s", i, id, want)
+		}
+	}
+}
+
+func testChildIDPerParent(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	parents := createTitled(t, ctx, store, "p1", "p2")
+	for _, p := range parents {
+		id, err := store.GetNextChildID(ctx, p.ID)
+		if err != nil {
+			t.Fatalf("GetNextChildID failed: %v", err)
+		}
+		if id != p.ID+".1" {
+			t.Errorf("first child of %s = %s, want %s.1", p.ID, id, p.ID)
+		}
+	}
+}
+
+func testChildIDMissingParent(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	if _, err := store.GetNextChildID(ctx, s.b.Prefix+"-nope"); err == nil {
+		t.Fatal("GetNextChildID for a missing parent should fail")
+	}
+}
+
+func testChildIDSkipsExplicitIDs(t *testing.T, s *suite) {
+	s.require(t, ChildCounters)
+	ctx, store := s.open(t)
+
+	parent := create(t, ctx, store, newIssue("parent"))
+	explicit := newIssue("imported child")
+	explicit.ID = parent.ID + ".3"
+	create(t, ctx, store, explicit)
+
+	id, err := store.GetNextChildID(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("GetNextChildID failed: %v", err)
+	}
+	if id != parent.ID+".4" {
+		t.Errorf("next child after explicit .3 = %s, want %s.4", id, parent.ID)
+	}
+}
+
+func testChildIDNested(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	parent := create(t, ctx, store, newIssue("parent"))
+	childID, err := store.GetNextChildID(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("GetNextChildID failed: %v", err)
+	}
+	child := newIssue("child")
+	child.ID = childID
+	create(t, ctx, store, child)
+
+	grandchildID, err := store.GetNextChildID(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetNextChildID(child) failed: %v", err)
+	}
+	if grandchildID != child.ID+".1" {
+		t.Errorf("grandchild ID = %s, want %s.1", grandchildID, child.ID)
+	}
+}
+
+func testStatisticsCounts(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "blocker", "blocked", "working", "done")
+	blocker, blocked, working, done := issues[0], issues[1], issues[2], issues[3]
+	addDep(t, ctx, store, blocked.ID, blocker.ID, types.DepBlocks)
+	if err := store.UpdateIssue(ctx, working.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	closeIssue(t, ctx, store, done.ID)
+	if s.has(IssueFilterFields) {
+		createTombstone(t, ctx, store, "gone")
+	}
+
+	stats, err := store.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if stats.TotalIssues != 4 {
+		t.Errorf("TotalIssues = %d, want 4 (tombstones excluded)", stats.TotalIssues)
+	}
+	if stats.OpenIssues != 2 || stats.InProgressIssues != 1 || stats.ClosedIssues != 1 {
+		t.Errorf("open/in_progress/closed = %d/%d/%d, want 2/1/1", stats.OpenIssues, stats.InProgressIssues, stats.ClosedIssues)
+	}
+	if stats.BlockedIssues != 1 {
+		t.Errorf("BlockedIssues = %d, want 1", stats.BlockedIssues)
+	}
+	if stats.ReadyIssues != 1 {
+		t.Errorf("ReadyIssues = %d, want 1", stats.ReadyIssues)
+	}
+	if s.has(IssueFilterFields) && stats.TombstoneIssues != 1 {
+		t.Errorf("TombstoneIssues = %d, want 1", stats.TombstoneIssues)
+	}
+}
+
+func itoa(i int) string {
+	const digits = "0123456789"
+	if i < 10 {
+		return digits[i : i+1]
+	}
+	return itoa(i/10) + digits[i%10:i%10+1]
+}
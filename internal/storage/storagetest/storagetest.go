@@ -0,0 +1,319 @@
+// Package storagetest provides a backend-agnostic conformance suite for
+// storage.Storage implementations.
+//
+// Each backend runs the suite from its own tests with a factory that returns
+// a fresh, empty store:
+//
+//	func TestConformance(t *testing.T) {
+//	    storagetest.Run(t, storagetest.Backend{
+//	        New: func(t *testing.T) storage.Storage {
+//	            store := newTestStore(t)
+//	            t.Cleanup(func() { _ = store.Close() })
+//	            return store
+//	        },
+//	    })
+//	}
+//
+// The suite encodes the behavior of the SQLite store, which is the reference
+// implementation. Backends that knowingly diverge list the affected features
+// in Backend.Gaps; tests that depend on them are skipped with the recorded
+// reason instead of failing, so every gap is explicit and greppable.
+package storagetest
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Capability names a feature the suite checks that a backend may not
+// implement (yet).
+type Capability string
+
+const (
+	// Transactions: RunInTransaction commits on success and rolls back on error.
+	Transactions Capability = "transactions"
+
+	// Leases: AcquireLease, GetLease, ReleaseLease and ExpireLeases, and
+	// WorkFilter.LeaseHolder hiding leased issues from ready work.
+	Leases Capability = "leases"
+
+	// CommitLinks: AddCommitLinks and GetCommitLinks persist the commits that
+	// mention an issue.
+	CommitLinks Capability = "commit-links"
+
+	// EventHistory: every mutation (including AddComment, labels and
+	// dependencies) is recorded in the audit trail returned by GetEvents.
+	EventHistory Capability = "event-history"
+
+	// ExportHashes: content hashes for dirty issues, per-issue export hashes
+	// and the JSONL file hash are persisted.
+	ExportHashes Capability = "export-hashes"
+
+	// RenameIssue: UpdateIssueID and RenameDependencyPrefix rewrite an issue
+	// and every reference to it.
+	RenameIssue Capability = "rename-issue"
+
+	// CycleDetection: AddDependency rejects blocking cycles and DetectCycles
+	// reports cycles that exist.
+	CycleDetection Capability = "cycle-detection"
+
+	// EpicClosure: GetEpicsEligibleForClosure reports epics whose children
+	// are all closed.
+	EpicClosure Capability = "epic-closure"
+
+	// DependencyTree: GetDependencyTree walks the full graph to maxDepth in
+	// both directions.
+	DependencyTree Capability = "dependency-tree"
+
+	// TransitiveBlocking: children of blocked parents are blocked, and
+	// conditional-blocks dependencies are honored.
+	TransitiveBlocking Capability = "transitive-blocking"
+
+	// PrefixValidation: CreateIssue rejects explicit IDs whose prefix doesn't
+	// match the configured issue_prefix.
+	PrefixValidation Capability = "prefix-validation"
+
+	// ExplicitTimestamps: CreateIssue keeps caller-supplied CreatedAt and
+	// UpdatedAt values instead of overwriting them.
+	ExplicitTimestamps Capability = "explicit-timestamps"
+
+	// ChildCounters: creating an issue with an explicit hierarchical ID
+	// (parent.N) advances the parent's child counter past N.
+	ChildCounters Capability = "child-counters"
+
+	// IssueFilterFields: SearchIssues honors every IssueFilter field,
+	// including default tombstone exclusion, ExcludeStatus/ExcludeTypes,
+	// text/date/priority ranges, flags and scheduling filters. Backends
+	// without it are only held to Status, Priority, IssueType, Assignee,
+	// Labels, IDs, IDPrefix, ParentID and Limit.
+	IssueFilterFields Capability = "issue-filter-fields"
+
+	// WorkFilterFields: GetReadyWork honors ParentID, MolType,
+	// IncludeDeferred and the full set of excluded workflow types and
+	// ephemeral issues.
+	WorkFilterFields Capability = "work-filter-fields"
+)
+
+// Backend describes the storage implementation under test.
+type Backend struct {
+	// New returns a fresh, empty store. It should register its own cleanup
+	// with t.Cleanup. The suite sets issue_prefix itself.
+	New func(t *testing.T) storage.Storage
+
+	// Prefix is the issue prefix the suite configures. Defaults to "bd".
+	Prefix string
+
+	// Gaps lists capabilities the backend is known not to support, with the
+	// reason. Tests that need them are skipped, not failed.
+	Gaps map[Capability]string
+}
+
+// Run executes the conformance suite against a backend.
+func Run(t *testing.T, b Backend) {
+	t.Helper()
+	if b.New == nil {
+		t.Fatal("storagetest: Backend.New is required")
+	}
+	if b.Prefix == "" {
+		b.Prefix = "bd"
+	}
+	s := &suite{b: b}
+
+	groups := []struct {
+		name  string
+		tests map[string]func(*testing.T, *suite)
+	}{
+		{"Issues", issueTests},
+		{"Search", searchTests},
+		{"Dependencies", dependencyTests},
+		{"Labels", labelTests},
+		{"ReadyWork", readyTests},
+		{"Blocked", blockedTests},
+		{"Events", eventTests},
+		{"Comments", commentTests},
+		{"Dirty", dirtyTests},
+		{"Config", configTests},
+		{"ChildIDs", childIDTests},
+		{"Statistics", statisticsTests},
+		{"Leases", leaseTests},
+		{"CommitLinks", commitLinkTests},
+		{"Rename", renameTests},
+		{"Transactions", transactionTests},
+	}
+	for _, g := range groups {
+		g := g
+		t.Run(g.name, func(t *testing.T) {
+			names := make([]string, 0, len(g.tests))
+			for name := range g.tests {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fn := g.tests[name]
+				t.Run(name, func(t *testing.T) { fn(t, s) })
+			}
+		})
+	}
+}
+
+// suite carries the backend through the individual tests.
+type suite struct {
+	b Backend
+}
+
+// open returns a fresh store with the suite's issue prefix configured.
+func (s *suite) open(t *testing.T) (context.Context, storage.Storage) {
+	t.Helper()
+	ctx := context.Background()
+	store := s.b.New(t)
+	if err := store.SetConfig(ctx, "issue_prefix", s.b.Prefix); err != nil {
+		t.Fatalf("SetConfig(issue_prefix) failed: %v", err)
+	}
+	return ctx, store
+}
+
+// require skips the test if the backend lists any of caps as a gap.
+func (s *suite) require(t *testing.T, caps ...Capability) {
+	t.Helper()
+	for _, c := range caps {
+		if reason, ok := s.b.Gaps[c]; ok {
+			t.Skipf("backend gap %s: %s", c, reason)
+		}
+	}
+}
+
+// has reports whether the backend supports a capability.
+func (s *suite) has(c Capability) bool {
+	_, gap := s.b.Gaps[c]
+	return !gap
+}
+
+// newIssue returns a valid open task with the given title.
+func newIssue(title string) *types.Issue {
+	return &types.Issue{
+		Title:     title,
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+	}
+}
+
+// create stores issue and fails the test on error.
+func create(t *testing.T, ctx context.Context, store storage.Storage, issue *types.Issue) *types.Issue {
+	t.Helper()
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue(%q) failed: %v", issue.Title, err)
+	}
+	if issue.ID == "" {
+		t.Fatalf("CreateIssue(%q) did not assign an ID", issue.Title)
+	}
+	return issue
+}
+
+// createTitled creates an open task per title and returns them in order.
+func createTitled(t *testing.T, ctx context.Context, store storage.Storage, titles ...string) []*types.Issue {
+	t.Helper()
+	issues := make([]*types.Issue, len(titles))
+	for i, title := range titles {
+		issues[i] = create(t, ctx, store, newIssue(title))
+	}
+	return issues
+}
+
+// addDep adds a dependency and fails the test on error.
+func addDep(t *testing.T, ctx context.Context, store storage.Storage, issueID, dependsOnID string, depType types.DependencyType) {
+	t.Helper()
+	dep := &types.Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: depType}
+	if err := store.AddDependency(ctx, dep, "tester"); err != nil {
+		t.Fatalf("AddDependency(%s -> %s, %s) failed: %v", issueID, dependsOnID, depType, err)
+	}
+}
+
+// closeIssue closes an issue and fails the test on error.
+func closeIssue(t *testing.T, ctx context.Context, store storage.Storage, id string) {
+	t.Helper()
+	if err := store.CloseIssue(ctx, id, "done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue(%s) failed: %v", id, err)
+	}
+}
+
+// mustGet fetches an issue that must exist.
+func mustGet(t *testing.T, ctx context.Context, store storage.Storage, id string) *types.Issue {
+	t.Helper()
+	issue, err := store.GetIssue(ctx, id)
+	if err != nil {
+		t.Fatalf("GetIssue(%s) failed: %v", id, err)
+	}
+	if issue == nil {
+		t.Fatalf("GetIssue(%s) returned nil", id)
+	}
+	return issue
+}
+
+// search runs SearchIssues with an empty query and returns the sorted IDs.
+func search(t *testing.T, ctx context.Context, store storage.Storage, filter types.IssueFilter) []string {
+	t.Helper()
+	issues, err := store.SearchIssues(ctx, "", filter)
+	if err != nil {
+		t.Fatalf("SearchIssues(%+v) failed: %v", filter, err)
+	}
+	return sortedIDs(issues)
+}
+
+// ready runs GetReadyWork and returns the sorted IDs.
+func ready(t *testing.T, ctx context.Context, store storage.Storage, filter types.WorkFilter) []string {
+	t.Helper()
+	issues, err := store.GetReadyWork(ctx, filter)
+	if err != nil {
+		t.Fatalf("GetReadyWork(%+v) failed: %v", filter, err)
+	}
+	return sortedIDs(issues)
+}
+
+func sortedIDs(issues []*types.Issue) []string {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// idsOf returns the sorted IDs of issues.
+func idsOf(issues ...*types.Issue) []string {
+	return sortedIDs(issues)
+}
+
+func contains(ids []string, id string) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
+// expectIDs fails the test unless got and want hold the same IDs.
+func expectIDs(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	got = append([]string(nil), got...)
+	want = append([]string(nil), want...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: got %v, want %v", what, got, want)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,144 @@
+package storagetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var transactionTests = map[string]func(*testing.T, *suite){
+	"Commit":         testTxCommit,
+	"RollbackOnErr":  testTxRollbackOnError,
+	"RollbackPanic":  testTxRollbackOnPanic,
+	"ReadYourWrites": testTxReadYourWrites,
+}
+
+func testTxCommit(t *testing.T, s *suite) {
+	s.require(t, Transactions)
+	ctx, store := s.open(t)
+
+	parent, child := newIssue("parent"), newIssue("child")
+	err := store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		if err := tx.CreateIssue(ctx, parent, "tester"); err != nil {
+			return err
+		}
+		if err := tx.CreateIssue(ctx, child, "tester"); err != nil {
+			return err
+		}
+		if err := tx.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: parent.ID, Type: types.DepParentChild}, "tester"); err != nil {
+			return err
+		}
+		return tx.AddLabel(ctx, child.ID, "batch", "tester")
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction failed: %v", err)
+	}
+
+	mustGet(t, ctx, store, parent.ID)
+	mustGet(t, ctx, store, child.ID)
+	expectLabels(t, ctx, store, child.ID, "batch")
+	deps, err := store.GetDependencies(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	expectIDs(t, "committed dependencies", sortedIDs(deps), idsOf(parent))
+}
+
+func testTxRollbackOnError(t *testing.T, s *suite) {
+	s.require(t, Transactions)
+	ctx, store := s.open(t)
+
+	existing := create(t, ctx, store, newIssue("existing"))
+	doomed := newIssue("doomed")
+	sentinel := errors.New("abort")
+	err := store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		if err := tx.CreateIssue(ctx, doomed, "tester"); err != nil {
+			return err
+		}
+		if err := tx.UpdateIssue(ctx, existing.ID, map[string]interface{}{"title": "changed"}, "tester"); err != nil {
+			return err
+		}
+		if err := tx.SetConfig(ctx, "tx.key", "value"); err != nil {
+			return err
+		}
+		return sentinel
+	})
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("RunInTransaction error = %v, want the callback's error", err)
+	}
+
+	if got, err := store.GetIssue(ctx, doomed.ID); err != nil || got != nil {
+		t.Errorf("rolled-back issue exists: %+v, %v", got, err)
+	}
+	if got := mustGet(t, ctx, store, existing.ID); got.Title != "existing" {
+		t.Errorf("rolled-back update persisted: title %q", got.Title)
+	}
+	if got, _ := store.GetConfig(ctx, "tx.key"); got != "" {
+		t.Errorf("rolled-back config persisted: %q", got)
+	}
+}
+
+func testTxRollbackOnPanic(t *testing.T, s *suite) {
+	s.require(t, Transactions)
+	ctx, store := s.open(t)
+
+	doomed := newIssue("doomed")
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("RunInTransaction swallowed the callback's panic")
+			}
+		}()
+		_ = store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+			if err := tx.CreateIssue(ctx, doomed, "tester"); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+
+	if doomed.ID == "" {
+		t.Fatal("CreateIssue inside the transaction did not assign an ID")
+	}
+	if got, err := store.GetIssue(ctx, doomed.ID); err != nil || got != nil {
+		t.Errorf("issue created before panic persisted: %+v, %v", got, err)
+	}
+}
+
+func testTxReadYourWrites(t *testing.T, s *suite) {
+	s.require(t, Transactions)
+	ctx, store := s.open(t)
+
+	issue := newIssue("fresh")
+	err := store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		if err := tx.CreateIssue(ctx, issue, "tester"); err != nil {
+			return err
+		}
+		got, err := tx.GetIssue(ctx, issue.ID)
+		if err != nil {
+			return err
+		}
+		if got == nil || got.Title != "fresh" {
+			t.Errorf("tx.GetIssue = %+v, want the uncommitted issue", got)
+		}
+
+		if err := tx.SetConfig(ctx, "tx.key", "v1"); err != nil {
+			return err
+		}
+		if v, err := tx.GetConfig(ctx, "tx.key"); err != nil || v != "v1" {
+			t.Errorf("tx.GetConfig = %q, %v; want v1", v, err)
+		}
+		if err := tx.SetMetadata(ctx, "tx.meta", "m1"); err != nil {
+			return err
+		}
+		if v, err := tx.GetMetadata(ctx, "tx.meta"); err != nil || v != "m1" {
+			t.Errorf("tx.GetMetadata = %q, %v; want m1", v, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction failed: %v", err)
+	}
+}
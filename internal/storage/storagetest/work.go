@@ -0,0 +1,549 @@
+package storagetest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+var readyTests = map[string]func(*testing.T, *suite){
+	"Basic":               testReadyBasic,
+	"ClosingUnblocks":     testReadyClosingUnblocks,
+	"NonBlockingDeps":     testReadyNonBlockingDeps,
+	"Filters":             testReadyFilters,
+	"Labels":              testReadyLabels,
+	"Limit":               testReadyLimit,
+	"SortPolicies":        testReadySortPolicies,
+	"ExcludesPinned":      testReadyExcludesPinned,
+	"ExcludesWorkflow":    testReadyExcludesWorkflow,
+	"ExcludesEphemeral":   testReadyExcludesEphemeral,
+	"ParentID":            testReadyParentID,
+	"MolType":             testReadyMolType,
+	"Deferred":            testReadyDeferred,
+	"TransitiveBlocking":  testReadyTransitiveBlocking,
+	"ConditionalBlocks":   testReadyConditionalBlocks,
+	"NewlyUnblocked":      testNewlyUnblockedByClose,
+	"StaleIssues":         testStaleIssues,
+	"EpicsEligible":       testEpicsEligibleForClosure,
+	"MoleculeProgress":    testMoleculeProgress,
+	"ExcludesOtherStatus": testReadyExcludesOtherStatuses,
+}
+
+var blockedTests = map[string]func(*testing.T, *suite){
+	"Basic":     testBlockedBasic,
+	"IsBlocked": testIsBlocked,
+	"ParentID":  testBlockedParentID,
+}
+
+func testReadyBasic(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "blocker", "blocked", "free")
+	blocker, blocked, free := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, blocked.ID, blocker.ID, types.DepBlocks)
+	done := create(t, ctx, store, newIssue("done"))
+	closeIssue(t, ctx, store, done.ID)
+
+	expectIDs(t, "ready work", ready(t, ctx, store, types.WorkFilter{}), idsOf(blocker, free))
+}
+
+func testReadyClosingUnblocks(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "blocker", "blocked")
+	blocker, blocked := issues[0], issues[1]
+	addDep(t, ctx, store, blocked.ID, blocker.ID, types.DepBlocks)
+	if contains(ready(t, ctx, store, types.WorkFilter{}), blocked.ID) {
+		t.Fatalf("%s is ready while its blocker is open", blocked.ID)
+	}
+
+	closeIssue(t, ctx, store, blocker.ID)
+	if !contains(ready(t, ctx, store, types.WorkFilter{}), blocked.ID) {
+		t.Fatalf("%s is not ready after its blocker closed", blocked.ID)
+	}
+
+	// Reopening the blocker blocks again
+	if err := store.UpdateIssue(ctx, blocker.ID, map[string]interface{}{"status": string(types.StatusOpen)}, "tester"); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	if contains(ready(t, ctx, store, types.WorkFilter{}), blocked.ID) {
+		t.Fatalf("%s is ready after its blocker was reopened", blocked.ID)
+	}
+
+	// Removing the dependency unblocks
+	if err := store.RemoveDependency(ctx, blocked.ID, blocker.ID, "tester"); err != nil {
+		t.Fatalf("RemoveDependency failed: %v", err)
+	}
+	if !contains(ready(t, ctx, store, types.WorkFilter{}), blocked.ID) {
+		t.Fatalf("%s is not ready after its dependency was removed", blocked.ID)
+	}
+}
+
+func testReadyNonBlockingDeps(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "a", "b", "c")
+	a, b, c := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, a.ID, b.ID, types.DepRelated)
+	addDep(t, ctx, store, c.ID, b.ID, types.DepDiscoveredFrom)
+
+	expectIDs(t, "related and discovered-from don't block", ready(t, ctx, store, types.WorkFilter{}), idsOf(a, b, c))
+}
+
+func testReadyFilters(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	mine := newIssue("mine")
+	mine.Assignee = "alice"
+	mine.Priority = 1
+	create(t, ctx, store, mine)
+	theirs := newIssue("theirs")
+	theirs.Assignee = "bob"
+	create(t, ctx, store, theirs)
+	nobody := newIssue("nobody")
+	nobody.IssueType = types.TypeBug
+	create(t, ctx, store, nobody)
+	working := create(t, ctx, store, newIssue("working"))
+	if err := store.UpdateIssue(ctx, working.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	expectIDs(t, "default statuses are open and in_progress", ready(t, ctx, store, types.WorkFilter{}), idsOf(mine, theirs, nobody, working))
+	expectIDs(t, "Status=in_progress", ready(t, ctx, store, types.WorkFilter{Status: types.StatusInProgress}), idsOf(working))
+	expectIDs(t, "Assignee=alice", ready(t, ctx, store, types.WorkFilter{Assignee: ptr("alice")}), idsOf(mine))
+	expectIDs(t, "Unassigned", ready(t, ctx, store, types.WorkFilter{Unassigned: true}), idsOf(nobody, working))
+	expectIDs(t, "Unassigned wins over Assignee", ready(t, ctx, store, types.WorkFilter{Unassigned: true, Assignee: ptr("alice")}), idsOf(nobody, working))
+	expectIDs(t, "Priority=1", ready(t, ctx, store, types.WorkFilter{Priority: ptr(1)}), idsOf(mine))
+	expectIDs(t, "Type=bug", ready(t, ctx, store, types.WorkFilter{Type: string(types.TypeBug)}), idsOf(nobody))
+}
+
+func testReadyLabels(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "both", "backend", "none")
+	both, backend := issues[0], issues[1]
+	for _, l := range []string{"backend", "urgent"} {
+		if err := store.AddLabel(ctx, both.ID, l, "tester"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+	if err := store.AddLabel(ctx, backend.ID, "backend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	expectIDs(t, "Labels (AND)", ready(t, ctx, store, types.WorkFilter{Labels: []string{"backend", "urgent"}}), idsOf(both))
+	expectIDs(t, "LabelsAny (OR)", ready(t, ctx, store, types.WorkFilter{LabelsAny: []string{"urgent", "backend"}}), idsOf(both, backend))
+}
+
+func testReadyLimit(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	createTitled(t, ctx, store, "a", "b", "c")
+	if got := ready(t, ctx, store, types.WorkFilter{Limit: 2}); len(got) != 2 {
+		t.Fatalf("Limit=2 returned %d issues", len(got))
+	}
+}
+
+func testReadySortPolicies(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	low := newIssue("low, first")
+	low.Priority = 3
+	if s.has(ExplicitTimestamps) {
+		// An hour back is still "recent" for the hybrid policy, and survives
+		// backends that store timestamps with second precision.
+		low.CreatedAt = time.Now().Add(-time.Hour)
+		low.UpdatedAt = low.CreatedAt
+	}
+	create(t, ctx, store, low)
+	time.Sleep(10 * time.Millisecond)
+	high := newIssue("high, second")
+	high.Priority = 0
+	create(t, ctx, store, high)
+
+	first := func(policy types.SortPolicy) string {
+		t.Helper()
+		issues, err := store.GetReadyWork(ctx, types.WorkFilter{SortPolicy: policy})
+		if err != nil {
+			t.Fatalf("GetReadyWork(%s) failed: %v", policy, err)
+		}
+		if len(issues) != 2 {
+			t.Fatalf("GetReadyWork(%s) returned %d issues, want 2", policy, len(issues))
+		}
+		return issues[0].ID
+	}
+	if got := first(types.SortPolicyPriority); got != high.ID {
+		t.Errorf("priority policy put %s first, want %s", got, high.ID)
+	}
+	if got := first(types.SortPolicyOldest); got != low.ID {
+		t.Errorf("oldest policy put %s first, want %s", got, low.ID)
+	}
+	// Both issues are recent, so hybrid orders them by priority
+	if got := first(types.SortPolicyHybrid); got != high.ID {
+		t.Errorf("hybrid policy put %s first, want %s", got, high.ID)
+	}
+}
+
+func testReadyExcludesPinned(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	work := create(t, ctx, store, newIssue("work"))
+	pinned := newIssue("context marker")
+	pinned.Pinned = true
+	create(t, ctx, store, pinned)
+
+	expectIDs(t, "pinned issues are not ready work", ready(t, ctx, store, types.WorkFilter{}), idsOf(work))
+}
+
+func testReadyExcludesWorkflow(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	work := create(t, ctx, store, newIssue("work"))
+	excluded := []types.IssueType{types.TypeMergeRequest, types.TypeGate, types.TypeMolecule, types.TypeMessage}
+	if s.has(WorkFilterFields) {
+		excluded = append(excluded, types.TypeAgent, types.TypeRole, types.TypeRig)
+	}
+	custom := make([]string, len(excluded))
+	for i, typ := range excluded {
+		custom[i] = string(typ)
+	}
+	if err := store.SetConfig(ctx, "types.custom", strings.Join(custom, ",")); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	for _, typ := range excluded {
+		issue := newIssue(string(typ))
+		issue.IssueType = typ
+		create(t, ctx, store, issue)
+	}
+
+	expectIDs(t, "workflow types are not ready work", ready(t, ctx, store, types.WorkFilter{}), idsOf(work))
+	gates := ready(t, ctx, store, types.WorkFilter{Type: string(types.TypeGate)})
+	if len(gates) != 1 {
+		t.Errorf("Type=gate returned %v, want the one gate", gates)
+	}
+}
+
+func testReadyExcludesEphemeral(t *testing.T, s *suite) {
+	s.require(t, WorkFilterFields)
+	ctx, store := s.open(t)
+
+	work := create(t, ctx, store, newIssue("work"))
+	wisp := newIssue("wisp")
+	wisp.Ephemeral = true
+	create(t, ctx, store, wisp)
+
+	expectIDs(t, "ephemeral issues are not ready work", ready(t, ctx, store, types.WorkFilter{}), idsOf(work))
+}
+
+func testReadyExcludesOtherStatuses(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	work := create(t, ctx, store, newIssue("work"))
+	for _, status := range []types.Status{types.StatusBlocked, types.StatusDeferred, types.StatusHooked} {
+		issue := newIssue(string(status))
+		issue.Status = status
+		create(t, ctx, store, issue)
+	}
+
+	expectIDs(t, "only open and in_progress issues are ready", ready(t, ctx, store, types.WorkFilter{}), idsOf(work))
+}
+
+func testReadyParentID(t *testing.T, s *suite) {
+	s.require(t, WorkFilterFields)
+	ctx, store := s.open(t)
+
+	epic := newIssue("epic")
+	epic.IssueType = types.TypeEpic
+	create(t, ctx, store, epic)
+	issues := createTitled(t, ctx, store, "child", "grandchild", "stranger")
+	child, grandchild := issues[0], issues[1]
+	addDep(t, ctx, store, child.ID, epic.ID, types.DepParentChild)
+	addDep(t, ctx, store, grandchild.ID, child.ID, types.DepParentChild)
+
+	// WorkFilter.ParentID matches all descendants, not just children
+	expectIDs(t, "ParentID", ready(t, ctx, store, types.WorkFilter{ParentID: &epic.ID}), idsOf(child, grandchild))
+}
+
+func testReadyMolType(t *testing.T, s *suite) {
+	s.require(t, WorkFilterFields)
+	ctx, store := s.open(t)
+
+	swarm := newIssue("swarm")
+	swarm.MolType = types.MolTypeSwarm
+	create(t, ctx, store, swarm)
+	createTitled(t, ctx, store, "plain")
+
+	expectIDs(t, "MolType=swarm", ready(t, ctx, store, types.WorkFilter{MolType: ptr(types.MolTypeSwarm)}), idsOf(swarm))
+}
+
+func testReadyDeferred(t *testing.T, s *suite) {
+	s.require(t, WorkFilterFields)
+	ctx, store := s.open(t)
+
+	past := time.Now().Add(-time.Hour).UTC()
+	future := time.Now().Add(72 * time.Hour).UTC()
+	later := newIssue("later")
+	later.DeferUntil = &future
+	create(t, ctx, store, later)
+	due := newIssue("deferral over")
+	due.DeferUntil = &past
+	create(t, ctx, store, due)
+	now := create(t, ctx, store, newIssue("now"))
+
+	expectIDs(t, "future defer_until hidden", ready(t, ctx, store, types.WorkFilter{}), idsOf(due, now))
+	expectIDs(t, "IncludeDeferred", ready(t, ctx, store, types.WorkFilter{IncludeDeferred: true}), idsOf(later, due, now))
+}
+
+func testReadyTransitiveBlocking(t *testing.T, s *suite) {
+	s.require(t, TransitiveBlocking)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "blocker", "epic", "child", "grandchild")
+	blocker, epic, child, grandchild := issues[0], issues[1], issues[2], issues[3]
+	addDep(t, ctx, store, epic.ID, blocker.ID, types.DepBlocks)
+	addDep(t, ctx, store, child.ID, epic.ID, types.DepParentChild)
+	addDep(t, ctx, store, grandchild.ID, child.ID, types.DepParentChild)
+
+	expectIDs(t, "descendants of a blocked parent are blocked", ready(t, ctx, store, types.WorkFilter{}), idsOf(blocker))
+
+	closeIssue(t, ctx, store, blocker.ID)
+	expectIDs(t, "closing the blocker frees the tree", ready(t, ctx, store, types.WorkFilter{}), idsOf(epic, child, grandchild))
+}
+
+func testReadyConditionalBlocks(t *testing.T, s *suite) {
+	s.require(t, TransitiveBlocking)
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "attempt", "fallback", "attempt 2", "fallback 2")
+	attempt, fallback, attempt2, fallback2 := issues[0], issues[1], issues[2], issues[3]
+	addDep(t, ctx, store, fallback.ID, attempt.ID, types.DepConditionalBlocks)
+	addDep(t, ctx, store, fallback2.ID, attempt2.ID, types.DepConditionalBlocks)
+
+	if contains(ready(t, ctx, store, types.WorkFilter{}), fallback.ID) {
+		t.Fatalf("%s is ready while the attempt is open", fallback.ID)
+	}
+
+	if err := store.CloseIssue(ctx, attempt.ID, "failed", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, attempt2.ID, "completed", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	got := ready(t, ctx, store, types.WorkFilter{})
+	if !contains(got, fallback.ID) {
+		t.Errorf("%s should run after its attempt failed", fallback.ID)
+	}
+	if contains(got, fallback2.ID) {
+		t.Errorf("%s should stay blocked after its attempt succeeded", fallback2.ID)
+	}
+}
+
+func testBlockedBasic(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "blocker a", "blocker b", "blocked", "free")
+	a, b, blocked := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, blocked.ID, a.ID, types.DepBlocks)
+	addDep(t, ctx, store, blocked.ID, b.ID, types.DepBlocks)
+
+	result, err := store.GetBlockedIssues(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(result) != 1 || result[0].ID != blocked.ID {
+		ids := make([]string, len(result))
+		for i, r := range result {
+			ids[i] = r.ID
+		}
+		t.Fatalf("GetBlockedIssues = %v, want [%s]", ids, blocked.ID)
+	}
+	if result[0].BlockedByCount != 2 {
+		t.Errorf("BlockedByCount = %d, want 2", result[0].BlockedByCount)
+	}
+	expectIDs(t, "BlockedBy", result[0].BlockedBy, idsOf(a, b))
+
+	closeIssue(t, ctx, store, a.ID)
+	result, err = store.GetBlockedIssues(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(result) != 1 || result[0].BlockedByCount != 1 {
+		t.Fatalf("after closing one blocker: %+v, want one issue blocked by 1", result)
+	}
+	expectIDs(t, "BlockedBy after close", result[0].BlockedBy, idsOf(b))
+}
+
+func testIsBlocked(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "blocker", "blocked")
+	blocker, blocked := issues[0], issues[1]
+	addDep(t, ctx, store, blocked.ID, blocker.ID, types.DepBlocks)
+
+	isBlocked, by, err := store.IsBlocked(ctx, blocked.ID)
+	if err != nil {
+		t.Fatalf("IsBlocked failed: %v", err)
+	}
+	if !isBlocked {
+		t.Fatalf("IsBlocked(%s) = false, want true", blocked.ID)
+	}
+	expectIDs(t, "blockers", by, idsOf(blocker))
+
+	isBlocked, _, err = store.IsBlocked(ctx, blocker.ID)
+	if err != nil {
+		t.Fatalf("IsBlocked failed: %v", err)
+	}
+	if isBlocked {
+		t.Errorf("IsBlocked(%s) = true for an unblocked issue", blocker.ID)
+	}
+
+	closeIssue(t, ctx, store, blocker.ID)
+	if isBlocked, _, err = store.IsBlocked(ctx, blocked.ID); err != nil || isBlocked {
+		t.Errorf("IsBlocked after closing blocker = %v, %v; want false", isBlocked, err)
+	}
+}
+
+func testBlockedParentID(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	epic := newIssue("epic")
+	epic.IssueType = types.TypeEpic
+	create(t, ctx, store, epic)
+	issues := createTitled(t, ctx, store, "blocker", "child", "outsider")
+	blocker, child, outsider := issues[0], issues[1], issues[2]
+	addDep(t, ctx, store, child.ID, epic.ID, types.DepParentChild)
+	addDep(t, ctx, store, child.ID, blocker.ID, types.DepBlocks)
+	addDep(t, ctx, store, outsider.ID, blocker.ID, types.DepBlocks)
+
+	result, err := store.GetBlockedIssues(ctx, types.WorkFilter{ParentID: &epic.ID})
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(result) != 1 || result[0].ID != child.ID {
+		t.Fatalf("GetBlockedIssues(ParentID=%s) returned %d issues, want only %s", epic.ID, len(result), child.ID)
+	}
+}
+
+func testNewlyUnblockedByClose(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	issues := createTitled(t, ctx, store, "blocker", "other blocker", "freed", "still blocked")
+	blocker, other, freed, still := issues[0], issues[1], issues[2], issues[3]
+	addDep(t, ctx, store, freed.ID, blocker.ID, types.DepBlocks)
+	addDep(t, ctx, store, still.ID, blocker.ID, types.DepBlocks)
+	addDep(t, ctx, store, still.ID, other.ID, types.DepBlocks)
+
+	closeIssue(t, ctx, store, blocker.ID)
+	unblocked, err := store.GetNewlyUnblockedByClose(ctx, blocker.ID)
+	if err != nil {
+		t.Fatalf("GetNewlyUnblockedByClose failed: %v", err)
+	}
+	expectIDs(t, "newly unblocked", sortedIDs(unblocked), idsOf(freed))
+}
+
+func testStaleIssues(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	fresh := create(t, ctx, store, newIssue("fresh"))
+	got, err := store.GetStaleIssues(ctx, types.StaleFilter{Days: 7})
+	if err != nil {
+		t.Fatalf("GetStaleIssues failed: %v", err)
+	}
+	if contains(sortedIDs(got), fresh.ID) {
+		t.Fatalf("fresh issue %s reported stale", fresh.ID)
+	}
+
+	if !s.has(ExplicitTimestamps) {
+		return
+	}
+	old := newIssue("forgotten")
+	old.CreatedAt = time.Now().Add(-60 * 24 * time.Hour).UTC()
+	old.UpdatedAt = time.Now().Add(-30 * 24 * time.Hour).UTC()
+	create(t, ctx, store, old)
+	oldClosed := newIssue("forgotten but done")
+	oldClosed.CreatedAt = old.CreatedAt
+	oldClosed.UpdatedAt = old.UpdatedAt
+	oldClosed.Status = types.StatusClosed
+	oldClosed.ClosedAt = &oldClosed.UpdatedAt
+	create(t, ctx, store, oldClosed)
+
+	got, err = store.GetStaleIssues(ctx, types.StaleFilter{Days: 7})
+	if err != nil {
+		t.Fatalf("GetStaleIssues failed: %v", err)
+	}
+	expectIDs(t, "stale issues", sortedIDs(got), idsOf(old))
+
+	got, err = store.GetStaleIssues(ctx, types.StaleFilter{Days: 7, Status: string(types.StatusInProgress)})
+	if err != nil {
+		t.Fatalf("GetStaleIssues failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Status=in_progress matched %v", sortedIDs(got))
+	}
+}
+
+func testEpicsEligibleForClosure(t *testing.T, s *suite) {
+	s.require(t, EpicClosure)
+	ctx, store := s.open(t)
+
+	done := newIssue("done epic")
+	done.IssueType = types.TypeEpic
+	create(t, ctx, store, done)
+	busy := newIssue("busy epic")
+	busy.IssueType = types.TypeEpic
+	create(t, ctx, store, busy)
+	children := createTitled(t, ctx, store, "closed child", "open child")
+	addDep(t, ctx, store, children[0].ID, done.ID, types.DepParentChild)
+	addDep(t, ctx, store, children[1].ID, busy.ID, types.DepParentChild)
+	closeIssue(t, ctx, store, children[0].ID)
+
+	epics, err := store.GetEpicsEligibleForClosure(ctx)
+	if err != nil {
+		t.Fatalf("GetEpicsEligibleForClosure failed: %v", err)
+	}
+	byID := make(map[string]*types.EpicStatus)
+	for _, e := range epics {
+		byID[e.Epic.ID] = e
+	}
+	if e := byID[done.ID]; e == nil || !e.EligibleForClose || e.TotalChildren != 1 || e.ClosedChildren != 1 {
+		t.Errorf("status of %s = %+v, want eligible with 1/1 closed", done.ID, e)
+	}
+	if e := byID[busy.ID]; e != nil && e.EligibleForClose {
+		t.Errorf("%s is eligible for closure with an open child", busy.ID)
+	}
+}
+
+func testMoleculeProgress(t *testing.T, s *suite) {
+	ctx, store := s.open(t)
+
+	mol := newIssue("molecule")
+	mol.IssueType = types.TypeEpic
+	create(t, ctx, store, mol)
+	steps := createTitled(t, ctx, store, "step 1", "step 2", "step 3")
+	for _, step := range steps {
+		addDep(t, ctx, store, step.ID, mol.ID, types.DepParentChild)
+	}
+	closeIssue(t, ctx, store, steps[0].ID)
+	if err := store.UpdateIssue(ctx, steps[1].ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	progress, err := store.GetMoleculeProgress(ctx, mol.ID)
+	if err != nil {
+		t.Fatalf("GetMoleculeProgress failed: %v", err)
+	}
+	if progress.MoleculeID != mol.ID || progress.MoleculeTitle != "molecule" {
+		t.Errorf("molecule = %s %q, want %s %q", progress.MoleculeID, progress.MoleculeTitle, mol.ID, "molecule")
+	}
+	if progress.Total != 3 || progress.Completed != 1 || progress.InProgress != 1 {
+		t.Errorf("progress = %d total, %d completed, %d in progress; want 3/1/1", progress.Total, progress.Completed, progress.InProgress)
+	}
+	if progress.CurrentStepID != steps[1].ID {
+		t.Errorf("CurrentStepID = %q, want %s", progress.CurrentStepID, steps[1].ID)
+	}
+	if progress.FirstClosed == nil || progress.LastClosed == nil {
+		t.Errorf("FirstClosed/LastClosed not set: %v/%v", progress.FirstClosed, progress.LastClosed)
+	}
+}
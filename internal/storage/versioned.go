@@ -60,7 +60,9 @@ type VersionedStorage interface {
 	GetConflicts(ctx context.Context) ([]Conflict, error)
 
 	// ResolveConflicts resolves conflicts using the specified strategy.
-	// Strategy must be "ours" or "theirs".
+	// Strategy must be "ours", "theirs" or "merge". Backends that can merge
+	// issue rows field by field do so first and use "ours"/"theirs" only for
+	// what the merge can't decide; "merge" leaves that in conflict.
 	ResolveConflicts(ctx context.Context, table string, strategy string) error
 }
 
@@ -0,0 +1,197 @@
+package synctransport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// manifestKey is the manifest's key relative to the prefix.
+const manifestKey = "manifest.json"
+
+// S3Config locates the bucket used by the S3 transport. Credentials come
+// from the standard AWS chain (environment, shared config, instance role).
+type S3Config struct {
+	Bucket   string
+	Prefix   string // key prefix inside the bucket, e.g. "beads/myrepo"
+	Region   string // defaults to us-east-1
+	Endpoint string // custom endpoint for S3-compatible stores (MinIO, R2, ...)
+
+	// PathStyle addresses the bucket as endpoint/bucket/key instead of
+	// bucket.endpoint/key, which most self-hosted stores require.
+	PathStyle bool
+}
+
+// S3Transport stores snapshots and the manifest in an S3-compatible bucket.
+// The manifest is updated with conditional PUTs (If-Match/If-None-Match),
+// so the store must support conditional writes.
+type S3Transport struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+// NewS3 creates an S3 transport for cfg.
+func NewS3(cfg S3Config) (*S3Transport, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("sync.s3.bucket is not set")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	awsCfg := aws.Config{
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(cfg.PathStyle),
+	}
+	if cfg.Endpoint != "" {
+		awsCfg.Endpoint = aws.String(cfg.Endpoint)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            awsCfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating S3 session: %w", err)
+	}
+
+	prefix := strings.Trim(cfg.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3Transport{client: s3.New(sess), bucket: cfg.Bucket, prefix: prefix}, nil
+}
+
+// ReadManifest implements Transport. The version token is the object's ETag.
+func (t *S3Transport) ReadManifest(ctx context.Context) (*Manifest, string, error) {
+	out, err := t.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(t.bucket),
+		Key:    aws.String(t.prefix + manifestKey),
+	})
+	if err != nil {
+		return nil, "", t.wrap(err, manifestKey)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, "", fmt.Errorf("parsing manifest: %w", err)
+	}
+	return &m, aws.StringValue(out.ETag), nil
+}
+
+// WriteManifest implements Transport.
+func (t *S3Transport) WriteManifest(ctx context.Context, m *Manifest, version string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	req, _ := t.client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:      aws.String(t.bucket),
+		Key:         aws.String(t.prefix + manifestKey),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	req.SetContext(ctx)
+	// This SDK version has no fields for conditional writes, so the
+	// headers are set on the request directly.
+	if version == "" {
+		req.HTTPRequest.Header.Set("If-None-Match", "*")
+	} else {
+		req.HTTPRequest.Header.Set("If-Match", version)
+	}
+	if err := req.Send(); err != nil {
+		return t.wrap(err, manifestKey)
+	}
+	return nil
+}
+
+// PutObject implements Transport.
+func (t *S3Transport) PutObject(ctx context.Context, key string, data []byte) error {
+	_, err := t.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(t.bucket),
+		Key:         aws.String(t.prefix + key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/x-ndjson"),
+	})
+	if err != nil {
+		return t.wrap(err, key)
+	}
+	return nil
+}
+
+// GetObject implements Transport.
+func (t *S3Transport) GetObject(ctx context.Context, key string) ([]byte, error) {
+	out, err := t.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(t.bucket),
+		Key:    aws.String(t.prefix + key),
+	})
+	if err != nil {
+		return nil, t.wrap(err, key)
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+// DeleteObject implements Transport.
+func (t *S3Transport) DeleteObject(ctx context.Context, key string) error {
+	_, err := t.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(t.bucket),
+		Key:    aws.String(t.prefix + key),
+	})
+	if err != nil {
+		if err := t.wrap(err, key); !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListObjects implements Transport.
+func (t *S3Transport) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := t.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(t.bucket),
+		Prefix: aws.String(t.prefix + prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			keys = append(keys, strings.TrimPrefix(aws.StringValue(obj.Key), t.prefix))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, t.wrap(err, prefix)
+	}
+	return keys, nil
+}
+
+// wrap maps S3 errors onto ErrNotFound and ErrConflict.
+func (t *S3Transport) wrap(err error, key string) error {
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		switch reqErr.StatusCode() {
+		case http.StatusNotFound:
+			if reqErr.Code() == s3.ErrCodeNoSuchBucket {
+				return fmt.Errorf("bucket %s does not exist: %w", t.bucket, err)
+			}
+			return fmt.Errorf("%s: %w", key, ErrNotFound)
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			return fmt.Errorf("%s: %w", key, ErrConflict)
+		}
+	}
+	return fmt.Errorf("s3://%s/%s%s: %w", t.bucket, t.prefix, key, err)
+}
@@ -0,0 +1,212 @@
+package synctransport
+
+import (
+	"context"
+	"crypto/md5" // #nosec G501 -- ETags only, like S3
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 is a minimal S3-compatible object store (path-style GET, PUT and
+// DELETE with conditional writes, and ListObjectsV2), standing in for MinIO
+// in tests.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+
+	// beforePut, if set, runs before a PUT of the given key is applied,
+	// without the lock held, so tests can interleave another writer.
+	beforePut func(key string)
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, string) {
+	t.Helper()
+	f := &fakeS3{objects: map[string][]byte{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv.URL
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data) // #nosec G401 -- ETags only
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func s3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodGet:
+		if r.URL.Query().Get("list-type") == "2" {
+			f.list(w, key, r.URL.Query().Get("prefix"))
+			return
+		}
+		f.mu.Lock()
+		data, ok := f.objects[key]
+		f.mu.Unlock()
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", etag(data))
+		_, _ = w.Write(data)
+
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			s3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		if f.beforePut != nil {
+			f.beforePut(key)
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		existing, exists := f.objects[key]
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			s3Error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		if match := r.Header.Get("If-Match"); match != "" && (!exists || etag(existing) != match) {
+			s3Error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		f.objects[key] = data
+		w.Header().Set("ETag", etag(data))
+
+	case http.MethodDelete:
+		f.mu.Lock()
+		delete(f.objects, key)
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// list answers a ListObjectsV2 request for bucket in a single page.
+func (f *fakeS3) list(w http.ResponseWriter, bucket, prefix string) {
+	bucket = strings.TrimSuffix(bucket, "/")
+	var body strings.Builder
+	keys := f.keys(bucket + "/" + prefix)
+	sort.Strings(keys)
+	for _, k := range keys {
+		body.WriteString("<Contents><Key>")
+		_ = xml.EscapeText(&body, []byte(strings.TrimPrefix(k, bucket+"/")))
+		body.WriteString("</Key></Contents>")
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult><Name>%s</Name><KeyCount>%d</KeyCount><IsTruncated>false</IsTruncated>%s</ListBucketResult>`,
+		bucket, len(keys), body.String())
+}
+
+func (f *fakeS3) keys(prefix string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []string
+	for k := range f.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func newTestS3Transport(t *testing.T, endpoint string) *S3Transport {
+	t.Helper()
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	tr, err := NewS3(S3Config{Bucket: "beads", Prefix: "/team/repo/", Endpoint: endpoint, PathStyle: true})
+	if err != nil {
+		t.Fatalf("NewS3: %v", err)
+	}
+	return tr
+}
+
+func TestNewS3RequiresBucket(t *testing.T) {
+	if _, err := NewS3(S3Config{}); err == nil {
+		t.Fatal("expected an error without a bucket")
+	}
+}
+
+func TestS3TransportObjects(t *testing.T) {
+	fake, endpoint := newFakeS3(t)
+	tr := newTestS3Transport(t, endpoint)
+	ctx := context.Background()
+
+	if _, err := tr.GetObject(ctx, "missing.jsonl"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetObject(missing) error = %v, want ErrNotFound", err)
+	}
+	if err := tr.PutObject(ctx, "a.jsonl", []byte("hello\n")); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+	if keys := fake.keys("beads/team/repo/a.jsonl"); len(keys) != 1 {
+		t.Fatalf("object not stored under bucket and prefix, have %v", fake.keys(""))
+	}
+	data, err := tr.GetObject(ctx, "a.jsonl")
+	if err != nil || string(data) != "hello\n" {
+		t.Fatalf("GetObject = %q, %v", data, err)
+	}
+	if err := tr.PutObject(ctx, "dir/b.jsonl", nil); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+	if keys, err := tr.ListObjects(ctx, "dir/"); err != nil || len(keys) != 1 || keys[0] != "dir/b.jsonl" {
+		t.Fatalf("ListObjects(dir/) = %v, %v", keys, err)
+	}
+	if err := tr.DeleteObject(ctx, "a.jsonl"); err != nil {
+		t.Fatalf("DeleteObject: %v", err)
+	}
+	if err := tr.DeleteObject(ctx, "a.jsonl"); err != nil {
+		t.Fatalf("DeleteObject of a missing object: %v", err)
+	}
+}
+
+func TestS3TransportManifestCompareAndSwap(t *testing.T) {
+	_, endpoint := newFakeS3(t)
+	tr := newTestS3Transport(t, endpoint)
+	ctx := context.Background()
+
+	if _, _, err := tr.ReadManifest(ctx); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("ReadManifest before first push: error = %v, want ErrNotFound", err)
+	}
+
+	if err := tr.WriteManifest(ctx, &Manifest{Generation: 1, Object: "g1"}, ""); err != nil {
+		t.Fatalf("creating manifest: %v", err)
+	}
+	if err := tr.WriteManifest(ctx, &Manifest{Generation: 1, Object: "other"}, ""); !errors.Is(err, ErrConflict) {
+		t.Fatalf("second create: error = %v, want ErrConflict", err)
+	}
+
+	m, version, err := tr.ReadManifest(ctx)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if m.Generation != 1 || m.Object != "g1" || version == "" {
+		t.Fatalf("ReadManifest = %+v, version %q", m, version)
+	}
+
+	if err := tr.WriteManifest(ctx, &Manifest{Generation: 2, Object: "g2"}, version); err != nil {
+		t.Fatalf("advancing manifest: %v", err)
+	}
+	if err := tr.WriteManifest(ctx, &Manifest{Generation: 2, Object: "stale"}, version); !errors.Is(err, ErrConflict) {
+		t.Fatalf("write with stale version: error = %v, want ErrConflict", err)
+	}
+	if m, _, _ := tr.ReadManifest(ctx); m.Object != "g2" {
+		t.Fatalf("manifest object = %q, want g2", m.Object)
+	}
+}
@@ -0,0 +1,238 @@
+package synctransport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/beads/internal/merge"
+)
+
+// DefaultMaxAttempts bounds how often Sync re-reads the manifest after
+// losing a compare-and-swap race before giving up.
+const DefaultMaxAttempts = 5
+
+// DefaultKeepGenerations is how many of the newest snapshots Sync keeps on
+// the remote; older ones are deleted after each push.
+const DefaultKeepGenerations = 10
+
+// State records which remote snapshot a clone last synced with. It is the
+// merge base for the next sync.
+type State struct {
+	Generation int64
+	Object     string
+}
+
+// Options configures a single Sync.
+type Options struct {
+	// Local is the clone's current JSONL export.
+	Local []byte
+
+	// Base is the snapshot the clone last synced with (zero on first sync).
+	Base State
+
+	// Policy holds the per-field merge strategies. Conflict strategies
+	// fall back to lww since a sync cannot stop for a person to resolve.
+	Policy *merge.Policy
+
+	// Writer identifies this clone in the manifest and snapshot keys.
+	Writer string
+
+	// NoPull skips merging remote changes; if the remote moved, nothing is
+	// pushed either, since that would discard the other clone's work.
+	NoPull bool
+
+	// NoPush merges remote changes without uploading a new generation.
+	NoPush bool
+
+	// MaxAttempts bounds compare-and-swap retries (DefaultMaxAttempts if 0).
+	MaxAttempts int
+
+	// KeepGenerations is how many of the newest snapshots to keep after a
+	// push (DefaultKeepGenerations if 0). A clone whose base was pruned
+	// falls back to a two-way merge.
+	KeepGenerations int
+}
+
+// Result describes what a Sync did.
+type Result struct {
+	// State is the new merge base to persist for the next sync.
+	State State
+
+	// Merged is the JSONL after merging; it equals Options.Local unless
+	// Pulled is set, in which case the caller should import it.
+	Merged []byte
+
+	// Pulled is true when remote changes were merged into Merged.
+	Pulled bool
+
+	// Pushed is true when a new generation was written.
+	Pushed bool
+
+	// RemoteAhead is true when NoPull was set and the remote has
+	// generations this clone has not merged.
+	RemoteAhead bool
+}
+
+// Sync exchanges the local export with the remote: it merges in any
+// generation written by another clone since opts.Base and then pushes the
+// result as the next generation, retrying when another clone wins the
+// manifest update in between.
+func Sync(ctx context.Context, t Transport, opts Options) (*Result, error) {
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	result := &Result{State: opts.Base, Merged: opts.Local}
+	base := opts.Base
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		current, version, err := t.ReadManifest(ctx)
+		if errors.Is(err, ErrNotFound) {
+			current, version = nil, ""
+		} else if err != nil {
+			return nil, fmt.Errorf("reading manifest: %w", err)
+		}
+
+		if current != nil && current.Generation != base.Generation {
+			if opts.NoPull {
+				result.RemoteAhead = true
+				return result, nil
+			}
+			remote, err := getSnapshot(ctx, t, current.Object, current.SHA256)
+			if err != nil {
+				return nil, fmt.Errorf("downloading generation %d: %w", current.Generation, err)
+			}
+			var baseData []byte
+			if base.Object != "" {
+				// A pruned base degrades to a two-way merge rather than
+				// failing the sync.
+				baseData, err = t.GetObject(ctx, base.Object)
+				if err != nil && !errors.Is(err, ErrNotFound) {
+					return nil, fmt.Errorf("downloading base generation %d: %w", base.Generation, err)
+				}
+			}
+			merged, err := mergeSnapshots(baseData, result.Merged, remote, opts.Policy.WithoutConflicts())
+			if err != nil {
+				return nil, err
+			}
+			result.Merged = merged
+			result.Pulled = true
+			base = State{Generation: current.Generation, Object: current.Object}
+			result.State = base
+		}
+
+		if opts.NoPush {
+			return result, nil
+		}
+		if current != nil && current.SHA256 == checksum(result.Merged) {
+			// The remote already holds exactly this content.
+			return result, nil
+		}
+
+		next := int64(1)
+		if current != nil {
+			next = current.Generation + 1
+		}
+		key := SnapshotKey(next, opts.Writer+"-"+nonce())
+		if err := t.PutObject(ctx, key, result.Merged); err != nil {
+			return nil, fmt.Errorf("uploading generation %d: %w", next, err)
+		}
+		m := &Manifest{
+			Generation: next,
+			Object:     key,
+			SHA256:     checksum(result.Merged),
+			UpdatedAt:  time.Now().UTC(),
+			UpdatedBy:  opts.Writer,
+		}
+		err = t.WriteManifest(ctx, m, version)
+		if errors.Is(err, ErrConflict) {
+			// Another clone pushed first; our snapshot is unreferenced.
+			_ = t.DeleteObject(ctx, key)
+			continue
+		}
+		if err != nil {
+			_ = t.DeleteObject(ctx, key)
+			return nil, fmt.Errorf("updating manifest: %w", err)
+		}
+		result.State = State{Generation: next, Object: key}
+		result.Pushed = true
+		pruneSnapshots(ctx, t, next, opts.KeepGenerations, key, base.Object)
+		return result, nil
+	}
+	return nil, fmt.Errorf("giving up after %d attempts: %w", maxAttempts, ErrConflict)
+}
+
+// pruneSnapshots deletes snapshots more than keep generations older than
+// current, never deleting the keys in protect (the new snapshot and the
+// base it was merged from). Pruning is best effort: the push already
+// succeeded, and whatever is left is retried on the next push.
+func pruneSnapshots(ctx context.Context, t Transport, current int64, keep int, protect ...string) {
+	if keep <= 0 {
+		keep = DefaultKeepGenerations
+	}
+	keys, err := t.ListObjects(ctx, snapshotPrefix)
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		gen, ok := snapshotGeneration(key)
+		if !ok || gen > current-int64(keep) || slices.Contains(protect, key) {
+			continue
+		}
+		_ = t.DeleteObject(ctx, key)
+	}
+}
+
+// getSnapshot downloads a snapshot and verifies it against the manifest.
+func getSnapshot(ctx context.Context, t Transport, key, sum string) ([]byte, error) {
+	data, err := t.GetObject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if sum != "" && checksum(data) != sum {
+		return nil, fmt.Errorf("checksum mismatch for %s", key)
+	}
+	return data, nil
+}
+
+// mergeSnapshots three-way merges local (left) and remote (right) JSONL.
+func mergeSnapshots(base, local, remote []byte, policy *merge.Policy) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "bd-transport-merge-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating merge directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := map[string][]byte{"base.jsonl": base, "local.jsonl": local, "remote.jsonl": remote}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	out := filepath.Join(dir, "merged.jsonl")
+	if err := merge.Merge3WayWithPolicy(out,
+		filepath.Join(dir, "base.jsonl"),
+		filepath.Join(dir, "local.jsonl"),
+		filepath.Join(dir, "remote.jsonl"),
+		policy, false); err != nil {
+		return nil, fmt.Errorf("merging remote changes: %w", err)
+	}
+	// #nosec G304 -- path is inside the temp directory created above
+	return os.ReadFile(out)
+}
+
+func nonce() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,250 @@
+package synctransport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func issueLine(id, title, updated string) string {
+	return fmt.Sprintf(`{"id":%q,"title":%q,"status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":%q}`, id, title, updated)
+}
+
+func jsonl(lines ...string) []byte {
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// titles maps issue IDs to titles in a JSONL snapshot.
+func titles(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	out := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var issue types.Issue
+		if err := json.Unmarshal(scanner.Bytes(), &issue); err != nil {
+			t.Fatalf("bad JSONL line %q: %v", scanner.Text(), err)
+		}
+		out[issue.ID] = issue.Title
+	}
+	return out
+}
+
+func TestSyncFirstPush(t *testing.T) {
+	_, endpoint := newFakeS3(t)
+	tr := newTestS3Transport(t, endpoint)
+	ctx := context.Background()
+	local := jsonl(issueLine("bd-1", "one", "2026-01-01T00:00:00Z"))
+
+	res, err := Sync(ctx, tr, Options{Local: local, Writer: "alice"})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if !res.Pushed || res.Pulled || res.State.Generation != 1 {
+		t.Fatalf("result = %+v, want a push of generation 1", res)
+	}
+	m, _, err := tr.ReadManifest(ctx)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if m.Generation != 1 || m.Object != res.State.Object || m.SHA256 != checksum(local) || m.UpdatedBy != "alice" {
+		t.Fatalf("manifest = %+v", m)
+	}
+
+	// Syncing the same content again is a no-op.
+	res, err = Sync(ctx, tr, Options{Local: local, Base: res.State, Writer: "alice"})
+	if err != nil {
+		t.Fatalf("second Sync: %v", err)
+	}
+	if res.Pushed || res.Pulled || res.State.Generation != 1 {
+		t.Fatalf("second result = %+v, want no-op", res)
+	}
+}
+
+func TestSyncMergesChangesFromAnotherClone(t *testing.T) {
+	_, endpoint := newFakeS3(t)
+	tr := newTestS3Transport(t, endpoint)
+	ctx := context.Background()
+
+	shared := issueLine("bd-1", "one", "2026-01-01T00:00:00Z")
+	alice, err := Sync(ctx, tr, Options{Local: jsonl(shared), Writer: "alice"})
+	if err != nil {
+		t.Fatalf("alice first sync: %v", err)
+	}
+	bob, err := Sync(ctx, tr, Options{Local: jsonl(shared), Writer: "bob"})
+	if err != nil {
+		t.Fatalf("bob first sync: %v", err)
+	}
+
+	// Bob adds an issue; Alice edits the shared one.
+	bob, err = Sync(ctx, tr, Options{
+		Local:  jsonl(shared, issueLine("bd-2", "from bob", "2026-01-02T00:00:00Z")),
+		Base:   bob.State,
+		Writer: "bob",
+	})
+	if err != nil || !bob.Pushed {
+		t.Fatalf("bob sync: %+v, %v", bob, err)
+	}
+
+	alice, err = Sync(ctx, tr, Options{
+		Local:  jsonl(issueLine("bd-1", "one, edited", "2026-01-03T00:00:00Z")),
+		Base:   alice.State,
+		Writer: "alice",
+	})
+	if err != nil {
+		t.Fatalf("alice sync: %v", err)
+	}
+	if !alice.Pulled || !alice.Pushed || alice.State.Generation != bob.State.Generation+1 {
+		t.Fatalf("alice result = %+v, want pull and push of generation %d", alice, bob.State.Generation+1)
+	}
+	got := titles(t, alice.Merged)
+	if got["bd-1"] != "one, edited" || got["bd-2"] != "from bob" || len(got) != 2 {
+		t.Fatalf("merged issues = %v", got)
+	}
+
+	remote, err := tr.GetObject(ctx, alice.State.Object)
+	if err != nil {
+		t.Fatalf("GetObject: %v", err)
+	}
+	if !bytes.Equal(remote, alice.Merged) {
+		t.Fatal("pushed snapshot differs from merged content")
+	}
+}
+
+func TestSyncRetriesWhenAnotherCloneWinsTheRace(t *testing.T) {
+	fake, endpoint := newFakeS3(t)
+	tr := newTestS3Transport(t, endpoint)
+	ctx := context.Background()
+
+	first, err := Sync(ctx, tr, Options{Local: jsonl(issueLine("bd-1", "one", "2026-01-01T00:00:00Z")), Writer: "alice"})
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+
+	// Bob pushes generation 2 just before Alice's manifest update lands.
+	raced := false
+	fake.beforePut = func(key string) {
+		if raced || !strings.HasSuffix(key, manifestKey) {
+			return
+		}
+		raced = true
+		if _, err := Sync(ctx, tr, Options{
+			Local:  jsonl(issueLine("bd-1", "one", "2026-01-01T00:00:00Z"), issueLine("bd-3", "from bob", "2026-01-02T00:00:00Z")),
+			Base:   first.State,
+			Writer: "bob",
+		}); err != nil {
+			t.Errorf("bob sync: %v", err)
+		}
+	}
+
+	res, err := Sync(ctx, tr, Options{
+		Local:  jsonl(issueLine("bd-1", "one", "2026-01-01T00:00:00Z"), issueLine("bd-2", "from alice", "2026-01-02T00:00:00Z")),
+		Base:   first.State,
+		Writer: "alice",
+	})
+	if err != nil {
+		t.Fatalf("alice sync: %v", err)
+	}
+	if !raced {
+		t.Fatal("race hook did not run")
+	}
+	if !res.Pulled || !res.Pushed || res.State.Generation != 3 {
+		t.Fatalf("result = %+v, want merge and push of generation 3", res)
+	}
+	if got := titles(t, res.Merged); len(got) != 3 {
+		t.Fatalf("merged issues = %v, want bd-1, bd-2 and bd-3", got)
+	}
+	// Alice's losing generation-2 snapshot is cleaned up.
+	if keys := fake.keys("beads/team/repo/generations/"); len(keys) != 3 {
+		t.Fatalf("snapshots = %v, want 3", keys)
+	}
+}
+
+func TestSyncNoPullLeavesRemoteAlone(t *testing.T) {
+	_, endpoint := newFakeS3(t)
+	tr := newTestS3Transport(t, endpoint)
+	ctx := context.Background()
+
+	if _, err := Sync(ctx, tr, Options{Local: jsonl(issueLine("bd-1", "one", "2026-01-01T00:00:00Z")), Writer: "alice"}); err != nil {
+		t.Fatalf("alice sync: %v", err)
+	}
+	res, err := Sync(ctx, tr, Options{Local: jsonl(issueLine("bd-2", "two", "2026-01-01T00:00:00Z")), Writer: "bob", NoPull: true})
+	if err != nil {
+		t.Fatalf("bob sync: %v", err)
+	}
+	if !res.RemoteAhead || res.Pushed || res.Pulled {
+		t.Fatalf("result = %+v, want RemoteAhead without push", res)
+	}
+	if m, _, _ := tr.ReadManifest(ctx); m.Generation != 1 {
+		t.Fatalf("manifest generation = %d, want 1", m.Generation)
+	}
+}
+
+func TestSyncPrunesOldGenerations(t *testing.T) {
+	fake, endpoint := newFakeS3(t)
+	tr := newTestS3Transport(t, endpoint)
+	ctx := context.Background()
+
+	var state State
+	var objects []string
+	for i := 1; i <= 5; i++ {
+		local := jsonl(issueLine("bd-1", fmt.Sprintf("v%d", i), "2026-01-01T00:00:00Z"))
+		res, err := Sync(ctx, tr, Options{Local: local, Base: state, Writer: "alice", KeepGenerations: 2})
+		if err != nil {
+			t.Fatalf("Sync %d: %v", i, err)
+		}
+		state = res.State
+		objects = append(objects, state.Object)
+	}
+
+	keys, err := tr.ListObjects(ctx, "generations/")
+	if err != nil {
+		t.Fatalf("ListObjects: %v", err)
+	}
+	sort.Strings(keys)
+	if want := objects[3:]; strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Fatalf("remaining snapshots = %v, want %v", keys, want)
+	}
+	if len(fake.keys("beads/team/repo/manifest.json")) != 1 {
+		t.Fatal("pruning removed the manifest")
+	}
+
+	// A clone that last synced a pruned generation still merges (two-way).
+	other := jsonl(issueLine("bd-2", "other", "2026-01-01T00:00:00Z"))
+	res, err := Sync(ctx, tr, Options{Local: other, Base: State{Generation: 1, Object: objects[0]}, Writer: "bob", KeepGenerations: 2})
+	if err != nil {
+		t.Fatalf("Sync from a pruned base: %v", err)
+	}
+	if got := titles(t, res.Merged); got["bd-1"] != "v5" || got["bd-2"] != "other" {
+		t.Fatalf("merged = %v", got)
+	}
+}
+
+func TestSnapshotGeneration(t *testing.T) {
+	if gen, ok := snapshotGeneration(SnapshotKey(42, "alice")); !ok || gen != 42 {
+		t.Errorf("snapshotGeneration(SnapshotKey(42)) = %d, %v", gen, ok)
+	}
+	for _, key := range []string{"manifest.json", "generations/x.jsonl", "other/00000000000000000001-a.jsonl"} {
+		if _, ok := snapshotGeneration(key); ok {
+			t.Errorf("snapshotGeneration(%q) accepted a non-snapshot key", key)
+		}
+	}
+}
+
+func TestSnapshotKey(t *testing.T) {
+	if got := SnapshotKey(12, "alice@host/x"); got != "generations/00000000000000000012-alice_host_x.jsonl" {
+		t.Errorf("SnapshotKey = %q", got)
+	}
+	if got := SnapshotKey(1, ""); !strings.HasSuffix(got, "-unknown.jsonl") {
+		t.Errorf("SnapshotKey with empty writer = %q", got)
+	}
+}
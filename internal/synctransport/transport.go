@@ -0,0 +1,110 @@
+// Package synctransport moves the exported JSONL between clones through
+// shared object storage instead of git.
+//
+// Each successful push writes an immutable, generation-numbered snapshot of
+// the JSONL and then advances a small manifest with compare-and-swap. A
+// clone whose last synced generation is behind the manifest downloads the
+// current snapshot and three-way merges it with its own export, using the
+// snapshot it last synced as the base.
+package synctransport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when a manifest or snapshot object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// ErrConflict is returned by WriteManifest when the manifest changed since it
+// was read, i.e. another clone pushed first.
+var ErrConflict = errors.New("manifest was updated concurrently")
+
+// Manifest names the current snapshot on the remote.
+type Manifest struct {
+	Generation int64     `json:"generation"`
+	Object     string    `json:"object"`
+	SHA256     string    `json:"sha256"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	UpdatedBy  string    `json:"updated_by,omitempty"`
+}
+
+// Transport is the storage a sync transport needs: opaque objects plus one
+// manifest that can be replaced conditionally.
+type Transport interface {
+	// ReadManifest returns the manifest and a version token to pass to
+	// WriteManifest. It returns ErrNotFound if nothing was pushed yet.
+	ReadManifest(ctx context.Context) (*Manifest, string, error)
+
+	// WriteManifest replaces the manifest only if its version is still
+	// version; an empty version means the manifest must not exist yet.
+	// It returns ErrConflict if the condition fails.
+	WriteManifest(ctx context.Context, m *Manifest, version string) error
+
+	// PutObject stores data under key, which is relative to the
+	// transport's prefix.
+	PutObject(ctx context.Context, key string, data []byte) error
+
+	// GetObject returns the object stored under key, or ErrNotFound.
+	GetObject(ctx context.Context, key string) ([]byte, error)
+
+	// DeleteObject removes the object stored under key. Deleting a
+	// missing object is not an error.
+	DeleteObject(ctx context.Context, key string) error
+
+	// ListObjects returns the keys, relative to the transport's prefix,
+	// of every object whose key starts with prefix.
+	ListObjects(ctx context.Context, prefix string) ([]string, error)
+}
+
+// SnapshotKey returns the object key for a generation's snapshot. The
+// writer is part of the key so that two clones racing for the same
+// generation never overwrite each other's snapshot; the manifest CAS decides
+// which one becomes current.
+func SnapshotKey(generation int64, writer string) string {
+	return fmt.Sprintf(snapshotPrefix+"%020d-%s.jsonl", generation, sanitizeKeyPart(writer))
+}
+
+// snapshotPrefix is the key prefix shared by all snapshots.
+const snapshotPrefix = "generations/"
+
+// snapshotGeneration returns the generation in a key made by SnapshotKey.
+func snapshotGeneration(key string) (int64, bool) {
+	rest := strings.TrimPrefix(key, snapshotPrefix)
+	if len(rest) == len(key) || len(rest) < 20 {
+		return 0, false
+	}
+	gen, err := strconv.ParseInt(rest[:20], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return gen, true
+}
+
+func sanitizeKeyPart(s string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+			out = append(out, c)
+		default:
+			out = append(out, '_')
+		}
+	}
+	if len(out) == 0 {
+		return "unknown"
+	}
+	return string(out)
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
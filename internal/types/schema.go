@@ -0,0 +1,316 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JSONLFormatVersion is the version of the issues.jsonl record format
+// written by this build. Bump it whenever a change to Issue, Dependency or
+// Comment would break an older reader, and add an upgrade step in
+// internal/importer so older files can still be imported.
+//
+// Version history:
+//
+//	1: unversioned exports (before the version marker existed)
+//	2: dependency records always carry issue_id and type
+const JSONLFormatVersion = 2
+
+// JSONSchemaID is the $id of the published issues.jsonl schema.
+const JSONSchemaID = "https://github.com/steveyegge/beads/schema/issue.schema.json"
+
+// schemaEnums lists the closed value sets for string-typed fields.
+// Status, IssueType and DependencyType are deliberately absent: they accept
+// custom values configured per repository.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(AgentState("")): {
+		string(StateIdle), string(StateSpawning), string(StateRunning), string(StateWorking),
+		string(StateStuck), string(StateDone), string(StateStopped), string(StateDead),
+	},
+	reflect.TypeOf(MolType("")):  {string(MolTypeSwarm), string(MolTypePatrol), string(MolTypeWork)},
+	reflect.TypeOf(WorkType("")): {string(WorkTypeMutex), string(WorkTypeOpenCompetition)},
+}
+
+// schemaRequired lists the fields a record can't be imported without.
+// Other fields are optional even when exports always write them: a missing
+// field decodes to its zero value (priority 0, no labels, ...), and older
+// exports omitted some of them.
+var schemaRequired = map[reflect.Type][]string{
+	reflect.TypeOf(Issue{}):      {"id", "title", "created_at", "updated_at"},
+	reflect.TypeOf(Dependency{}): {"issue_id", "depends_on_id", "type"},
+	reflect.TypeOf(Comment{}):    {"author", "text", "created_at"},
+	reflect.TypeOf(BondRef{}):    {"source_id", "bond_type"},
+	reflect.TypeOf(Validation{}): {"outcome", "timestamp"},
+}
+
+// schemaBounds holds numeric ranges for Issue fields, keyed by JSON name.
+var schemaBounds = map[string][2]float64{
+	"priority":      {0, 4},
+	"quality_score": {0, 1},
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing one line of
+// issues.jsonl. It is generated from the Issue, Dependency and Comment
+// structs, so it always matches what this build exports.
+func JSONSchema() map[string]interface{} {
+	g := &schemaGenerator{defs: make(map[string]interface{})}
+	root := g.object(reflect.TypeOf(Issue{}))
+	if props, ok := root["properties"].(map[string]interface{}); ok {
+		for name, bounds := range schemaBounds {
+			if prop, ok := props[name].(map[string]interface{}); ok {
+				prop["minimum"] = bounds[0]
+				prop["maximum"] = bounds[1]
+			}
+		}
+	}
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = JSONSchemaID
+	root["title"] = "beads issue"
+	root["description"] = "One line of .beads/issues.jsonl"
+	root["x-format-version"] = JSONLFormatVersion
+	root["$defs"] = g.defs
+	return root
+}
+
+type schemaGenerator struct {
+	defs map[string]interface{}
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) map[string]interface{} {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(time.Duration(0)):
+		return map[string]interface{}{"type": "integer", "description": "Duration in nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schemaFor(t.Elem())
+	case reflect.String:
+		s := map[string]interface{}{"type": "string"}
+		if values, ok := schemaEnums[t]; ok {
+			s["enum"] = values
+		}
+		return s
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = true // placeholder guards against recursive types
+			g.defs[name] = g.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+func (g *schemaGenerator) object(t reflect.Type) map[string]interface{} {
+	props := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _ := jsonFieldName(f)
+		if name == "" {
+			continue
+		}
+		props[name] = g.schemaFor(f.Type)
+	}
+	required := slices.Clone(schemaRequired[t])
+	s := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+// jsonFieldName returns the JSON key for a struct field ("" if the field is
+// not serialized) and whether it is tagged omitempty.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	omitEmpty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty
+}
+
+// SchemaViolation describes one place where a JSONL record does not match
+// the schema.
+type SchemaViolation struct {
+	Path    string // e.g. "dependencies[0].type"
+	Message string
+	Unknown bool // true if the field is not part of the schema at all
+}
+
+func (v SchemaViolation) Error() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// ValidateJSONLRecord checks one issues.jsonl line against JSONSchema and
+// returns every violation found (nil if the record is valid).
+func ValidateJSONLRecord(line []byte) ([]SchemaViolation, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	schema := validationSchema()
+	v := &schemaValidator{defs: schema["$defs"].(map[string]interface{})}
+	v.validate(schema, value, "")
+	return v.violations, nil
+}
+
+// validationSchema is generated once and shared by every validation call.
+var validationSchema = sync.OnceValue(JSONSchema)
+
+type schemaValidator struct {
+	defs       map[string]interface{}
+	violations []SchemaViolation
+}
+
+func (v *schemaValidator) fail(path string, unknown bool, format string, args ...interface{}) {
+	v.violations = append(v.violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...), Unknown: unknown})
+}
+
+func (v *schemaValidator) validate(schema map[string]interface{}, value interface{}, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = v.defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+	}
+	// encoding/json accepts null for any field, so the schema does too
+	if value == nil {
+		return
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(path, false, "expected object")
+			return
+		}
+		props := schema["properties"].(map[string]interface{})
+		required, _ := schema["required"].([]string)
+		for _, name := range required {
+			if _, ok := obj[name]; !ok {
+				v.fail(joinSchemaPath(path, name), false, "required field is missing")
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			prop, ok := props[k].(map[string]interface{})
+			if !ok {
+				v.fail(joinSchemaPath(path, k), true, "unknown field")
+				continue
+			}
+			v.validate(prop, obj[k], joinSchemaPath(path, k))
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			v.fail(path, false, "expected array")
+			return
+		}
+		items := schema["items"].(map[string]interface{})
+		for i, item := range arr {
+			v.validate(items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			v.fail(path, false, "expected string")
+			return
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				v.fail(path, false, "invalid date-time %q", s)
+			}
+		}
+		if enum, ok := schema["enum"].([]string); ok && s != "" && !containsString(enum, s) {
+			v.fail(path, false, "invalid value %q (expected one of: %s)", s, strings.Join(enum, ", "))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(path, false, "expected boolean")
+		}
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			v.fail(path, false, "expected %s", schema["type"])
+			return
+		}
+		if schema["type"] == "integer" {
+			if _, err := n.Int64(); err != nil {
+				v.fail(path, false, "expected integer, got %s", n)
+				return
+			}
+		}
+		f, err := n.Float64()
+		if err != nil {
+			v.fail(path, false, "invalid number %s", n)
+			return
+		}
+		if lo, ok := schema["minimum"].(float64); ok && f < lo {
+			v.fail(path, false, "%s is below the minimum of %v", n, lo)
+		}
+		if hi, ok := schema["maximum"].(float64); ok && f > hi {
+			v.fail(path, false, "%s is above the maximum of %v", n, hi)
+		}
+	}
+}
+
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJSONSchemaCoversIssueFields(t *testing.T) {
+	schema := JSONSchema()
+	props := schema["properties"].(map[string]interface{})
+	for _, name := range []string{"id", "title", "work_type", "quality_score", "dependencies", "comments"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("schema missing property %q", name)
+		}
+	}
+	if _, ok := props["content_hash"]; ok {
+		t.Errorf("schema should not include internal fields")
+	}
+	defs := schema["$defs"].(map[string]interface{})
+	for _, name := range []string{"Dependency", "Comment"} {
+		if _, ok := defs[name]; !ok {
+			t.Errorf("schema missing definition %q", name)
+		}
+	}
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("schema is not serializable: %v", err)
+	}
+}
+
+func TestValidateJSONLRecord(t *testing.T) {
+	now := time.Now()
+	score := float32(0.5)
+	issue := &Issue{
+		ID: "bd-1", Title: "Valid", Status: StatusOpen, Priority: 1, IssueType: TypeTask,
+		CreatedAt: now, UpdatedAt: now, WorkType: WorkTypeMutex, QualityScore: &score,
+		Dependencies: []*Dependency{{IssueID: "bd-1", DependsOnID: "bd-2", Type: DepBlocks, CreatedAt: now}},
+		Comments:     []*Comment{{ID: 1, IssueID: "bd-1", Author: "a", Text: "hi", CreatedAt: now}},
+	}
+	line, err := json.Marshal(issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := ValidateJSONLRecord(line)
+	if err != nil {
+		t.Fatalf("ValidateJSONLRecord failed: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("expected exported issue to be valid, got %v", violations)
+	}
+
+	tests := []struct {
+		name    string
+		line    string
+		path    string
+		unknown bool
+	}{
+		{"unknown field", `{"id":"bd-1","title":"x","priority":1,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z","colour":"red"}`, "colour", true},
+		{"priority out of range", `{"id":"bd-1","title":"x","priority":9,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`, "priority", false},
+		{"bad enum", `{"id":"bd-1","title":"x","priority":1,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z","work_type":"auction"}`, "work_type", false},
+		{"bad timestamp", `{"id":"bd-1","title":"x","priority":1,"created_at":"yesterday","updated_at":"2025-01-01T00:00:00Z"}`, "created_at", false},
+		{"missing required", `{"id":"bd-1","priority":1,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`, "title", false},
+		{"missing nested required", `{"id":"bd-1","title":"x","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z","dependencies":[{"issue_id":"bd-1","type":"blocks"}]}`, "dependencies[0].depends_on_id", false},
+		{"nested", `{"id":"bd-1","title":"x","priority":1,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z","dependencies":[{"issue_id":"bd-1","depends_on_id":"bd-2","type":"blocks","created_at":"2025-01-01T00:00:00Z","weight":3}]}`, "dependencies[0].weight", true},
+	}
+	// Fields with a zero-value default may be left out; older exports
+	// omitted priority 0
+	violations, err = ValidateJSONLRecord([]byte(`{"id":"bd-1","title":"x","status":"closed","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`))
+	if err != nil || len(violations) != 0 {
+		t.Fatalf("record without priority: violations %v, err %v", violations, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := ValidateJSONLRecord([]byte(tt.line))
+			if err != nil {
+				t.Fatalf("ValidateJSONLRecord failed: %v", err)
+			}
+			if len(violations) != 1 {
+				t.Fatalf("expected 1 violation, got %v", violations)
+			}
+			if violations[0].Path != tt.path || violations[0].Unknown != tt.unknown {
+				t.Errorf("got %+v, want path %q unknown=%v", violations[0], tt.path, tt.unknown)
+			}
+		})
+	}
+}
@@ -687,6 +687,7 @@ type IssueDetails struct {
 	Dependencies []*IssueWithDependencyMetadata `json:"dependencies,omitempty"`
 	Dependents   []*IssueWithDependencyMetadata `json:"dependents,omitempty"`
 	Comments     []*Comment                     `json:"comments,omitempty"`
+	Commits      []*CommitLink                  `json:"commits,omitempty"`
 	Parent       *string                        `json:"parent,omitempty"`
 }
 
@@ -846,6 +847,58 @@ type Event struct {
 	CreatedAt time.Time  `json:"created_at"`
 }
 
+// EventFilter selects events across issues for history queries such as
+// analytics. Empty fields match everything.
+type EventFilter struct {
+	IssueIDs []string    // Only events for these issues
+	Types    []EventType // Only events of these types
+	Since    *time.Time  // Events at or after this time
+	Until    *time.Time  // Events at or before this time
+}
+
+// Lease records a time-limited claim on an issue by a single holder.
+// Leases let several agents pull from the same ready queue without grabbing
+// the same issue: a claim only succeeds if no other holder has an active lease.
+// Leases are local coordination state and are not exported to JSONL.
+type Lease struct {
+	IssueID    string        `json:"issue_id"`
+	Holder     string        `json:"holder"`
+	AcquiredAt time.Time     `json:"acquired_at"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	TTL        time.Duration `json:"ttl"` // Renewal extends ExpiresAt to now+TTL
+}
+
+// IsActive returns true if the lease has not yet expired at the given time.
+func (l *Lease) IsActive(now time.Time) bool {
+	return l != nil && now.Before(l.ExpiresAt)
+}
+
+// DefaultLeaseTTL is the lease duration used by bd claim when --ttl is not given
+const DefaultLeaseTTL = 30 * time.Minute
+
+// CommitLink records that a git commit mentions an issue in its message.
+// Links are maintained by the post-commit and post-merge hooks (or bd commits
+// index) from git history, so they are local state and not exported to JSONL.
+type CommitLink struct {
+	IssueID     string        `json:"issue_id"`
+	CommitSHA   string        `json:"commit_sha"`
+	Author      string        `json:"author"`
+	Subject     string        `json:"subject"`
+	Files       []string      `json:"files,omitempty"`
+	Keyword     CommitKeyword `json:"keyword"`
+	CommittedAt time.Time     `json:"committed_at"`
+}
+
+// CommitKeyword is how a commit message refers to an issue
+type CommitKeyword string
+
+// CommitKeyword constants
+const (
+	CommitCloses   CommitKeyword = "closes"   // "Closes bd-1", "Fixes bd-1", "Resolves bd-1"
+	CommitRefs     CommitKeyword = "refs"     // "Refs bd-1", "See bd-1", "Part of bd-1"
+	CommitMentions CommitKeyword = "mentions" // Bare "bd-1" or "(bd-1)"
+)
+
 // EventType categorizes audit trail events
 type EventType string
 
@@ -862,6 +915,7 @@ const (
 	EventLabelAdded        EventType = "label_added"
 	EventLabelRemoved      EventType = "label_removed"
 	EventCompacted         EventType = "compacted"
+	EventDispatched        EventType = "dispatched" // Swarm dispatcher assigned the issue to an agent
 )
 
 // BlockedIssue extends Issue with blocking information
@@ -1023,6 +1077,10 @@ type WorkFilter struct {
 
 	// Time-based deferral filtering (GH#820)
 	IncludeDeferred bool // If true, include issues with future defer_until timestamps
+
+	// Lease filtering: issues under an active lease held by anyone other than
+	// LeaseHolder are hidden. Empty LeaseHolder hides every actively leased issue.
+	LeaseHolder string
 }
 
 // StaleFilter is used to filter stale issue queries
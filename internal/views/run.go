@@ -0,0 +1,48 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Run runs the view against store, resolving relative dates against now,
+// and returns the matching issues sorted and with their labels loaded.
+// actor is who runs a ready view: issues leased by anyone else are hidden.
+func Run(ctx context.Context, store storage.Storage, v *View, now time.Time, actor string) ([]*types.Issue, error) {
+	var issues []*types.Issue
+	if v.Cmd() == CommandReady {
+		filter, err := v.WorkFilter(actor)
+		if err != nil {
+			return nil, err
+		}
+		if issues, err = store.GetReadyWork(ctx, filter); err != nil {
+			return nil, fmt.Errorf("view %q: %w", v.Name, err)
+		}
+	} else {
+		filter, err := v.IssueFilter(now)
+		if err != nil {
+			return nil, err
+		}
+		if issues, err = store.SearchIssues(ctx, "", filter); err != nil {
+			return nil, fmt.Errorf("view %q: %w", v.Name, err)
+		}
+		Sort(issues, v.Sort, v.Reverse)
+	}
+
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := store.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("view %q: loading labels: %w", v.Name, err)
+	}
+	for _, issue := range issues {
+		issue.Labels = labels[issue.ID]
+	}
+	return issues, nil
+}
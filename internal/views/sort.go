@@ -0,0 +1,130 @@
+package views
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// SortFields are the fields list views (and bd list --sort) can sort by.
+var SortFields = []string{"priority", "created", "updated", "closed", "status", "id", "title", "type", "assignee"}
+
+// IsSortField reports whether field is one of SortFields.
+func IsSortField(field string) bool {
+	return slices.Contains(SortFields, field)
+}
+
+// Sort sorts issues by a sort field and direction. An empty field leaves
+// them in order.
+func Sort(issues []*types.Issue, sortBy string, reverse bool) {
+	if sortBy == "" {
+		return
+	}
+
+	slices.SortFunc(issues, func(a, b *types.Issue) int {
+		result := Compare(a, b, sortBy)
+		if reverse {
+			return -result
+		}
+		return result
+	})
+}
+
+// Compare orders two issues by a sort field, using the field's default
+// direction. Unknown fields compare equal.
+func Compare(a, b *types.Issue, sortBy string) int {
+	var result int
+
+	switch sortBy {
+	case "priority":
+		// Lower priority numbers come first (P0 > P1 > P2 > P3 > P4)
+		result = cmp.Compare(a.Priority, b.Priority)
+	case "created":
+		// Default: newest first (descending)
+		result = b.CreatedAt.Compare(a.CreatedAt)
+	case "updated":
+		// Default: newest first (descending)
+		result = b.UpdatedAt.Compare(a.UpdatedAt)
+	case "closed":
+		// Default: newest first (descending)
+		// Handle nil ClosedAt values
+		if a.ClosedAt == nil && b.ClosedAt == nil {
+			result = 0
+		} else if a.ClosedAt == nil {
+			result = 1 // nil sorts last
+		} else if b.ClosedAt == nil {
+			result = -1 // non-nil sorts before nil
+		} else {
+			result = b.ClosedAt.Compare(*a.ClosedAt)
+		}
+	case "status":
+		result = cmp.Compare(a.Status, b.Status)
+	case "id":
+		result = cmp.Compare(a.ID, b.ID)
+	case "title":
+		result = cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "type":
+		result = cmp.Compare(a.IssueType, b.IssueType)
+	case "assignee":
+		result = cmp.Compare(a.Assignee, b.Assignee)
+	default:
+		// Unknown sort field, no sorting
+		result = 0
+	}
+
+	return result
+}
+
+// Columns are the columns a view can show.
+var Columns = []string{"id", "status", "priority", "type", "assignee", "labels", "title", "created", "updated", "closed", "due", "defer"}
+
+// DefaultColumns are shown by bd view run when a view names none.
+var DefaultColumns = []string{"id", "priority", "status", "type", "assignee", "title"}
+
+// IsColumn reports whether column is one of Columns.
+func IsColumn(column string) bool {
+	return slices.Contains(Columns, column)
+}
+
+// Value returns the text of an issue's column. Issue labels must be loaded
+// for the labels column.
+func Value(issue *types.Issue, column string) string {
+	date := func(t time.Time) string { return t.Format("2006-01-02") }
+	switch column {
+	case "id":
+		return issue.ID
+	case "status":
+		return string(issue.Status)
+	case "priority":
+		return fmt.Sprintf("P%d", issue.Priority)
+	case "type":
+		return string(issue.IssueType)
+	case "assignee":
+		return issue.Assignee
+	case "labels":
+		return strings.Join(issue.Labels, ",")
+	case "title":
+		return issue.Title
+	case "created":
+		return date(issue.CreatedAt)
+	case "updated":
+		return date(issue.UpdatedAt)
+	case "closed":
+		if issue.ClosedAt != nil {
+			return date(*issue.ClosedAt)
+		}
+	case "due":
+		if issue.DueAt != nil {
+			return date(*issue.DueAt)
+		}
+	case "defer":
+		if issue.DeferUntil != nil {
+			return date(*issue.DeferUntil)
+		}
+	}
+	return ""
+}
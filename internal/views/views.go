@@ -0,0 +1,451 @@
+// Package views runs saved queries ("views") stored under views in
+// config.yaml.
+//
+// A view is a bd list or bd ready filter saved by bd view save, together
+// with its sort order, limit and the columns to show. Filters are kept in
+// terms of the flags they came from, so a view can be applied to bd list
+// --view and shared through config.yaml, and dates stay relative: a view
+// saved with --created-after -7d always means the last seven days.
+package views
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/timeparsing"
+	"github.com/steveyegge/beads/internal/types"
+	"github.com/steveyegge/beads/internal/util"
+	"github.com/steveyegge/beads/internal/validation"
+)
+
+// Commands a view can be saved from.
+const (
+	CommandList  = "list"  // Filters with types.IssueFilter
+	CommandReady = "ready" // Filters with types.WorkFilter
+)
+
+// View is a saved query.
+type View struct {
+	Name string `json:"name"`
+	config.ViewConfig
+}
+
+// Flag is one command-line flag of a view, e.g. {"label", "backend,api"}.
+type Flag struct {
+	Name  string
+	Value string
+	Bool  bool // A switch: Value is "true" and can be left out
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateName checks that name can be used as a key under views in
+// config.yaml, which is case-insensitive and splits keys on dots.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid view name %q (use lowercase letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// Load returns the view called name from config.yaml.
+func Load(name string) (*View, error) {
+	all, err := config.GetViews()
+	if err != nil {
+		return nil, err
+	}
+	return Find(all, name)
+}
+
+// Find returns the view called name from all.
+func Find(all map[string]config.ViewConfig, name string) (*View, error) {
+	vc, ok := all[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("no view named %q (see bd view list)", name)
+	}
+	return &View{Name: strings.ToLower(name), ViewConfig: vc}, nil
+}
+
+// List returns all views sorted by name.
+func List(all map[string]config.ViewConfig) []*View {
+	list := make([]*View, 0, len(all))
+	for name, vc := range all {
+		list = append(list, &View{Name: name, ViewConfig: vc})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Cmd returns the command the view runs as.
+func (v *View) Cmd() string {
+	if v.Command == "" {
+		return CommandList
+	}
+	return v.Command
+}
+
+// commandFlags lists the flags each command can save in a view.
+var commandFlags = map[string][]string{
+	CommandList: {"status", "all", "ready", "priority", "priority-min", "priority-max",
+		"assignee", "no-assignee", "type", "label", "label-any", "no-labels", "title", "id",
+		"title-contains", "desc-contains", "notes-contains", "empty-description",
+		"created-after", "created-before", "updated-after", "updated-before", "closed-after", "closed-before",
+		"pinned", "no-pinned", "include-templates", "include-gates", "parent", "filter-parent", "mol-type",
+		"deferred", "defer-after", "defer-before", "due-after", "due-before", "overdue",
+		"sort", "reverse", "limit", "columns"},
+	CommandReady: {"priority", "assignee", "unassigned", "type", "label", "label-any",
+		"parent", "mol-type", "include-deferred", "sort", "limit"},
+}
+
+// FlagNames returns the flags of command that can be saved in a view.
+func FlagNames(command string) []string {
+	return commandFlags[command]
+}
+
+// Saves reports whether command's flag can be saved in a view.
+func Saves(command, flag string) bool {
+	return slices.Contains(commandFlags[command], flag)
+}
+
+// fields maps flag names to the string, boolean and list fields of f.
+func fields(f *config.ViewFilterConfig) (map[string]*string, map[string]*bool, map[string]*[]string) {
+	strs := map[string]*string{
+		"status": &f.Status, "priority": &f.Priority, "priority-min": &f.PriorityMin, "priority-max": &f.PriorityMax,
+		"assignee": &f.Assignee, "type": &f.Type, "title": &f.Title,
+		"title-contains": &f.TitleContains, "desc-contains": &f.DescContains, "notes-contains": &f.NotesContains,
+		"created-after": &f.CreatedAfter, "created-before": &f.CreatedBefore,
+		"updated-after": &f.UpdatedAfter, "updated-before": &f.UpdatedBefore,
+		"closed-after": &f.ClosedAfter, "closed-before": &f.ClosedBefore,
+		"parent": &f.Parent, "mol-type": &f.MolType,
+		"defer-after": &f.DeferAfter, "defer-before": &f.DeferBefore,
+		"due-after": &f.DueAfter, "due-before": &f.DueBefore,
+	}
+	bools := map[string]*bool{
+		"all": &f.All, "ready": &f.Ready, "no-assignee": &f.NoAssignee, "unassigned": &f.Unassigned,
+		"no-labels": &f.NoLabels, "empty-description": &f.EmptyDescription,
+		"pinned": &f.Pinned, "no-pinned": &f.NoPinned,
+		"include-templates": &f.IncludeTemplates, "include-gates": &f.IncludeGates,
+		"deferred": &f.Deferred, "include-deferred": &f.IncludeDeferred, "overdue": &f.Overdue,
+	}
+	lists := map[string]*[]string{
+		"label": &f.Labels, "label-any": &f.LabelsAny, "id": &f.IDs,
+	}
+	return strs, bools, lists
+}
+
+// Set stores the value of one of the view command's flags in the view. List
+// values are comma-separated.
+func (v *View) Set(flag, value string) error {
+	if !Saves(v.Cmd(), flag) {
+		return fmt.Errorf("--%s can't be saved in a %s view", flag, v.Cmd())
+	}
+	if flag == "filter-parent" {
+		flag = "parent"
+	}
+	splitList := func(s string) []string { return util.NormalizeLabels(strings.Split(s, ",")) }
+
+	strs, bools, lists := fields(&v.Filter)
+	switch {
+	case flag == "sort":
+		v.Sort = value
+	case flag == "reverse":
+		v.Reverse = value == "true"
+	case flag == "limit":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid --limit %q", value)
+		}
+		v.Limit = &n
+	case flag == "columns":
+		v.Columns = splitList(value)
+	case strs[flag] != nil:
+		*strs[flag] = value
+	case bools[flag] != nil:
+		*bools[flag] = value == "true"
+	case lists[flag] != nil:
+		*lists[flag] = splitList(value)
+	}
+	return nil
+}
+
+// Flags returns the view as flags of its command, sorted by name, e.g. to
+// apply it to bd list or to show the equivalent command line. Columns are
+// included for list views only, since bd ready has no --columns.
+func (v *View) Flags() []Flag {
+	var flags []Flag
+	strs, bools, lists := fields(&v.Filter)
+	for name, p := range strs {
+		if *p == "" {
+			continue
+		}
+		value := *p
+		if strings.HasPrefix(name, "priority") {
+			// bd ready takes a plain number, bd list also P0-P4
+			if n, err := validation.ValidatePriority(value); err == nil {
+				value = strconv.Itoa(n)
+			}
+		}
+		flags = append(flags, Flag{Name: name, Value: value})
+	}
+	for name, p := range bools {
+		if *p {
+			flags = append(flags, Flag{Name: name, Value: "true", Bool: true})
+		}
+	}
+	for name, p := range lists {
+		if len(*p) > 0 {
+			flags = append(flags, Flag{Name: name, Value: strings.Join(*p, ",")})
+		}
+	}
+	if v.Sort != "" {
+		flags = append(flags, Flag{Name: "sort", Value: v.Sort})
+	}
+	if v.Reverse {
+		flags = append(flags, Flag{Name: "reverse", Value: "true", Bool: true})
+	}
+	if v.Limit != nil {
+		flags = append(flags, Flag{Name: "limit", Value: strconv.Itoa(*v.Limit)})
+	}
+	if len(v.Columns) > 0 && v.Cmd() == CommandList {
+		flags = append(flags, Flag{Name: "columns", Value: strings.Join(v.Columns, ",")})
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// CommandLine returns the bd command the view is equivalent to.
+func (v *View) CommandLine() string {
+	parts := []string{"bd", v.Cmd()}
+	for _, f := range v.Flags() {
+		if f.Bool {
+			parts = append(parts, "--"+f.Name)
+			continue
+		}
+		value := f.Value
+		if value == "" || strings.ContainsAny(value, " \t'\"") {
+			value = strconv.Quote(value)
+		}
+		parts = append(parts, "--"+f.Name+" "+value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Validate checks the view without running it.
+func (v *View) Validate() error {
+	if err := ValidateName(v.Name); err != nil {
+		return err
+	}
+	if v.Cmd() != CommandList && v.Cmd() != CommandReady {
+		return fmt.Errorf("view %q: invalid command %q (expected %s or %s)", v.Name, v.Command, CommandList, CommandReady)
+	}
+	for _, f := range v.Flags() {
+		if !Saves(v.Cmd(), f.Name) {
+			return fmt.Errorf("view %q: %s views don't support %s", v.Name, v.Cmd(), f.Name)
+		}
+	}
+	for _, c := range v.Columns {
+		if !IsColumn(c) {
+			return fmt.Errorf("view %q: unknown column %q (valid: %s)", v.Name, c, strings.Join(Columns, ", "))
+		}
+	}
+	if v.Limit != nil && *v.Limit < 0 {
+		return fmt.Errorf("view %q: limit must not be negative", v.Name)
+	}
+
+	var err error
+	if v.Cmd() == CommandReady {
+		_, err = v.WorkFilter("")
+	} else {
+		_, err = v.IssueFilter(time.Now())
+	}
+	return err
+}
+
+// IssueFilter builds the filter of a list view, resolving relative dates
+// against now. It applies the same defaults as bd list: closed issues,
+// templates and gates are left out unless the view asks for them.
+func (v *View) IssueFilter(now time.Time) (types.IssueFilter, error) {
+	f := v.Filter
+	filter := types.IssueFilter{Limit: v.EffectiveLimit()}
+	fail := func(flag string, err error) (types.IssueFilter, error) {
+		return types.IssueFilter{}, fmt.Errorf("view %q: invalid %s: %w", v.Name, flag, err)
+	}
+
+	if v.Sort != "" && !IsSortField(v.Sort) {
+		return fail("sort", fmt.Errorf("unknown field %q (valid: %s)", v.Sort, strings.Join(SortFields, ", ")))
+	}
+
+	if f.Ready {
+		s := types.StatusOpen
+		filter.Status = &s
+	} else if f.Status != "" && f.Status != "all" {
+		s := types.Status(f.Status)
+		filter.Status = &s
+	}
+	if f.Status == "" && !f.All && !f.Ready {
+		filter.ExcludeStatus = []types.Status{types.StatusClosed}
+	}
+
+	priorities := []struct {
+		flag, value string
+		dst         **int
+	}{
+		{"priority", f.Priority, &filter.Priority},
+		{"priority-min", f.PriorityMin, &filter.PriorityMin},
+		{"priority-max", f.PriorityMax, &filter.PriorityMax},
+	}
+	for _, p := range priorities {
+		if p.value == "" {
+			continue
+		}
+		n, err := validation.ValidatePriority(p.value)
+		if err != nil {
+			return fail(p.flag, err)
+		}
+		*p.dst = &n
+	}
+
+	if f.Assignee != "" {
+		assignee := f.Assignee
+		filter.Assignee = &assignee
+	}
+	issueType := util.NormalizeIssueType(f.Type)
+	if issueType != "" {
+		t := types.IssueType(issueType)
+		filter.IssueType = &t
+	}
+	filter.Labels = util.NormalizeLabels(f.Labels)
+	filter.LabelsAny = util.NormalizeLabels(f.LabelsAny)
+	filter.IDs = util.NormalizeLabels(f.IDs)
+	filter.TitleSearch = f.Title
+	filter.TitleContains = f.TitleContains
+	filter.DescriptionContains = f.DescContains
+	filter.NotesContains = f.NotesContains
+	filter.EmptyDescription = f.EmptyDescription
+	filter.NoAssignee = f.NoAssignee
+	filter.NoLabels = f.NoLabels
+
+	dates := []struct {
+		flag, expr string
+		dst        **time.Time
+	}{
+		{"created-after", f.CreatedAfter, &filter.CreatedAfter},
+		{"created-before", f.CreatedBefore, &filter.CreatedBefore},
+		{"updated-after", f.UpdatedAfter, &filter.UpdatedAfter},
+		{"updated-before", f.UpdatedBefore, &filter.UpdatedBefore},
+		{"closed-after", f.ClosedAfter, &filter.ClosedAfter},
+		{"closed-before", f.ClosedBefore, &filter.ClosedBefore},
+		{"defer-after", f.DeferAfter, &filter.DeferAfter},
+		{"defer-before", f.DeferBefore, &filter.DeferBefore},
+		{"due-after", f.DueAfter, &filter.DueAfter},
+		{"due-before", f.DueBefore, &filter.DueBefore},
+	}
+	for _, d := range dates {
+		if d.expr == "" {
+			continue
+		}
+		t, err := timeparsing.ParseRelativeTime(d.expr, now)
+		if err != nil {
+			return fail(d.flag, err)
+		}
+		*d.dst = &t
+	}
+
+	if f.Pinned && f.NoPinned {
+		return fail("pinned", fmt.Errorf("pinned and no-pinned are mutually exclusive"))
+	}
+	if f.Pinned || f.NoPinned {
+		pinned := f.Pinned
+		filter.Pinned = &pinned
+	}
+	if !f.IncludeTemplates {
+		isTemplate := false
+		filter.IsTemplate = &isTemplate
+	}
+	if !f.IncludeGates && issueType != string(types.TypeGate) {
+		filter.ExcludeTypes = append(filter.ExcludeTypes, types.TypeGate)
+	}
+	if f.Parent != "" {
+		parent := f.Parent
+		filter.ParentID = &parent
+	}
+	if f.MolType != "" {
+		mt := types.MolType(f.MolType)
+		if !mt.IsValid() {
+			return fail("mol-type", fmt.Errorf("%q must be swarm, patrol, or work", f.MolType))
+		}
+		filter.MolType = &mt
+	}
+	filter.Deferred = f.Deferred
+	filter.Overdue = f.Overdue
+	return filter, nil
+}
+
+// WorkFilter builds the filter of a ready view. leaseHolder is the actor
+// running it, whose leased issues stay visible.
+func (v *View) WorkFilter(leaseHolder string) (types.WorkFilter, error) {
+	f := v.Filter
+	filter := types.WorkFilter{
+		Type:            util.NormalizeIssueType(f.Type),
+		Limit:           v.EffectiveLimit(),
+		Unassigned:      f.Unassigned,
+		SortPolicy:      types.SortPolicy(v.Sort),
+		Labels:          util.NormalizeLabels(f.Labels),
+		LabelsAny:       util.NormalizeLabels(f.LabelsAny),
+		IncludeDeferred: f.IncludeDeferred,
+		LeaseHolder:     leaseHolder,
+	}
+	if filter.SortPolicy == "" {
+		filter.SortPolicy = types.SortPolicyHybrid
+	}
+	if !filter.SortPolicy.IsValid() {
+		return types.WorkFilter{}, fmt.Errorf("view %q: invalid sort policy %q (valid: hybrid, priority, oldest)", v.Name, v.Sort)
+	}
+	if f.Priority != "" {
+		n, err := validation.ValidatePriority(f.Priority)
+		if err != nil {
+			return types.WorkFilter{}, fmt.Errorf("view %q: invalid priority: %w", v.Name, err)
+		}
+		filter.Priority = &n
+	}
+	if f.Assignee != "" && !f.Unassigned {
+		assignee := f.Assignee
+		filter.Assignee = &assignee
+	}
+	if f.Parent != "" {
+		parent := f.Parent
+		filter.ParentID = &parent
+	}
+	if f.MolType != "" {
+		mt := types.MolType(f.MolType)
+		if !mt.IsValid() {
+			return types.WorkFilter{}, fmt.Errorf("view %q: invalid mol-type %q (must be swarm, patrol, or work)", v.Name, f.MolType)
+		}
+		filter.MolType = &mt
+	}
+	return filter, nil
+}
+
+// Default limits, matching bd list and bd ready.
+const (
+	DefaultListLimit  = 50
+	DefaultReadyLimit = 10
+)
+
+// EffectiveLimit is the most issues the view returns; 0 means no limit.
+func (v *View) EffectiveLimit() int {
+	switch {
+	case v.Limit != nil:
+		return *v.Limit
+	case v.Cmd() == CommandReady:
+		return DefaultReadyLimit
+	default:
+		return DefaultListLimit
+	}
+}
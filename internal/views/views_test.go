@@ -0,0 +1,240 @@
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/config"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestSetAndFlags(t *testing.T) {
+	v := &View{Name: "mine"}
+	for _, f := range []Flag{
+		{Name: "label", Value: "backend, api,backend"},
+		{Name: "created-after", Value: "-7d"},
+		{Name: "no-assignee", Value: "true"},
+		{Name: "filter-parent", Value: "bd-1"},
+		{Name: "priority-min", Value: "P1"},
+		{Name: "sort", Value: "updated"},
+		{Name: "limit", Value: "0"},
+		{Name: "columns", Value: "id,title"},
+	} {
+		if err := v.Set(f.Name, f.Value); err != nil {
+			t.Fatalf("Set(%s): %v", f.Name, err)
+		}
+	}
+
+	if got := strings.Join(v.Filter.Labels, ","); got != "backend,api" {
+		t.Errorf("labels = %s, want backend,api", got)
+	}
+	if v.Filter.Parent != "bd-1" {
+		t.Errorf("parent = %q, want bd-1 (from --filter-parent)", v.Filter.Parent)
+	}
+	if v.Limit == nil || *v.Limit != 0 {
+		t.Errorf("limit = %v, want explicit 0", v.Limit)
+	}
+
+	want := "bd list --columns id,title --created-after -7d --label backend,api --limit 0 --no-assignee --parent bd-1 --priority-min 1 --sort updated"
+	if got := v.CommandLine(); got != want {
+		t.Errorf("CommandLine() =\n  %s\nwant\n  %s", got, want)
+	}
+
+	if err := v.Set("pretty", "true"); err == nil {
+		t.Error("Set(pretty) should fail: display flags aren't saved")
+	}
+	ready := &View{Name: "next", ViewConfig: config.ViewConfig{Command: CommandReady}}
+	if err := ready.Set("created-after", "-1d"); err == nil {
+		t.Error("Set(created-after) on a ready view should fail")
+	}
+	if err := ready.Set("unassigned", "true"); err != nil {
+		t.Errorf("Set(unassigned) on a ready view: %v", err)
+	}
+}
+
+func TestCommandLineQuotes(t *testing.T) {
+	v := &View{Name: "t", ViewConfig: config.ViewConfig{Filter: config.ViewFilterConfig{Title: "true", TitleContains: "login page"}}}
+	want := `bd list --title true --title-contains "login page"`
+	if got := v.CommandLine(); got != want {
+		t.Errorf("CommandLine() = %s, want %s", got, want)
+	}
+}
+
+func TestIssueFilter(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("defaults", func(t *testing.T) {
+		filter, err := (&View{Name: "all"}).IssueFilter(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filter.Limit != DefaultListLimit {
+			t.Errorf("limit = %d, want %d", filter.Limit, DefaultListLimit)
+		}
+		if len(filter.ExcludeStatus) != 1 || filter.ExcludeStatus[0] != types.StatusClosed {
+			t.Errorf("exclude status = %v, want [closed]", filter.ExcludeStatus)
+		}
+		if filter.IsTemplate == nil || *filter.IsTemplate {
+			t.Error("templates should be excluded by default")
+		}
+		if len(filter.ExcludeTypes) != 1 || filter.ExcludeTypes[0] != types.TypeGate {
+			t.Errorf("exclude types = %v, want [gate]", filter.ExcludeTypes)
+		}
+	})
+
+	t.Run("relative dates resolve against now", func(t *testing.T) {
+		v := &View{Name: "recent", ViewConfig: config.ViewConfig{Filter: config.ViewFilterConfig{
+			CreatedAfter: "-7d",
+			DueBefore:    "+2d",
+			ClosedBefore: "2026-01-01",
+			All:          true,
+		}}}
+		filter, err := v.IssueFilter(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := now.AddDate(0, 0, -7); filter.CreatedAfter == nil || !filter.CreatedAfter.Equal(want) {
+			t.Errorf("created after = %v, want %v", filter.CreatedAfter, want)
+		}
+		if want := now.AddDate(0, 0, 2); filter.DueBefore == nil || !filter.DueBefore.Equal(want) {
+			t.Errorf("due before = %v, want %v", filter.DueBefore, want)
+		}
+		if filter.ClosedBefore == nil || filter.ClosedBefore.Year() != 2026 || filter.ClosedBefore.YearDay() != 1 {
+			t.Errorf("closed before = %v, want 2026-01-01", filter.ClosedBefore)
+		}
+		if len(filter.ExcludeStatus) != 0 {
+			t.Errorf("all: exclude status = %v, want none", filter.ExcludeStatus)
+		}
+
+		later, err := v.IssueFilter(now.Add(24 * time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !later.CreatedAfter.After(*filter.CreatedAfter) {
+			t.Error("created-after should move with the time the view runs")
+		}
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		v := &View{Name: "bugs", ViewConfig: config.ViewConfig{Filter: config.ViewFilterConfig{
+			Type:        "bug",
+			Priority:    "P0",
+			PriorityMax: "2",
+			Ready:       true,
+			Pinned:      true,
+			MolType:     "swarm",
+			IDs:         []string{"bd-1", "bd-2"},
+		}}}
+		filter, err := v.IssueFilter(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filter.IssueType == nil || *filter.IssueType != types.TypeBug {
+			t.Errorf("type = %v, want bug", filter.IssueType)
+		}
+		if filter.Priority == nil || *filter.Priority != 0 || filter.PriorityMax == nil || *filter.PriorityMax != 2 {
+			t.Errorf("priority = %v max = %v, want 0 and 2", filter.Priority, filter.PriorityMax)
+		}
+		if filter.Status == nil || *filter.Status != types.StatusOpen {
+			t.Errorf("ready: status = %v, want open", filter.Status)
+		}
+		if filter.Pinned == nil || !*filter.Pinned {
+			t.Error("pinned should be set")
+		}
+		if filter.MolType == nil || *filter.MolType != types.MolTypeSwarm {
+			t.Errorf("mol type = %v, want swarm", filter.MolType)
+		}
+		if len(filter.IDs) != 2 {
+			t.Errorf("ids = %v, want 2", filter.IDs)
+		}
+	})
+}
+
+func TestWorkFilter(t *testing.T) {
+	v := &View{Name: "next", ViewConfig: config.ViewConfig{
+		Command: CommandReady,
+		Filter:  config.ViewFilterConfig{Priority: "P1", Assignee: "alice", Labels: []string{"api"}},
+		Sort:    "oldest",
+	}}
+	filter, err := v.WorkFilter("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Priority == nil || *filter.Priority != 1 {
+		t.Errorf("priority = %v, want 1", filter.Priority)
+	}
+	if filter.Assignee == nil || *filter.Assignee != "alice" || filter.LeaseHolder != "alice" {
+		t.Errorf("assignee = %v lease holder = %q, want alice", filter.Assignee, filter.LeaseHolder)
+	}
+	if filter.SortPolicy != types.SortPolicyOldest || filter.Limit != DefaultReadyLimit {
+		t.Errorf("sort = %q limit = %d, want oldest and %d", filter.SortPolicy, filter.Limit, DefaultReadyLimit)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		view    View
+		wantErr string
+	}{
+		{"ok", View{Name: "ok", ViewConfig: config.ViewConfig{Sort: "priority", Columns: []string{"id"}}}, ""},
+		{"bad name", View{Name: "My.View"}, "invalid view name"},
+		{"bad command", View{Name: "x", ViewConfig: config.ViewConfig{Command: "show"}}, "invalid command"},
+		{"bad column", View{Name: "x", ViewConfig: config.ViewConfig{Columns: []string{"votes"}}}, "unknown column"},
+		{"bad sort", View{Name: "x", ViewConfig: config.ViewConfig{Sort: "hybrid"}}, "unknown field"},
+		{"bad ready sort", View{Name: "x", ViewConfig: config.ViewConfig{Command: CommandReady, Sort: "title"}}, "invalid sort policy"},
+		{"bad date", View{Name: "x", ViewConfig: config.ViewConfig{Filter: config.ViewFilterConfig{UpdatedAfter: "someday"}}}, "invalid updated-after"},
+		{"bad priority", View{Name: "x", ViewConfig: config.ViewConfig{Filter: config.ViewFilterConfig{PriorityMin: "P9"}}}, "invalid priority-min"},
+		{"list flag in ready view", View{Name: "x", ViewConfig: config.ViewConfig{Command: CommandReady, Filter: config.ViewFilterConfig{Overdue: true}}}, "don't support overdue"},
+		{"pinned both ways", View{Name: "x", ViewConfig: config.ViewConfig{Filter: config.ViewFilterConfig{Pinned: true, NoPinned: true}}}, "mutually exclusive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.view.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFindAndList(t *testing.T) {
+	all := map[string]config.ViewConfig{
+		"zeta":  {Description: "last"},
+		"alpha": {Command: CommandReady},
+	}
+	list := List(all)
+	if len(list) != 2 || list[0].Name != "alpha" || list[1].Name != "zeta" {
+		t.Fatalf("List() = %+v, want alpha, zeta", list)
+	}
+	v, err := Find(all, "Zeta")
+	if err != nil || v.Description != "last" {
+		t.Errorf("Find(Zeta) = %+v, %v", v, err)
+	}
+	if _, err := Find(all, "beta"); err == nil {
+		t.Error("Find(beta) should fail")
+	}
+}
+
+func TestValue(t *testing.T) {
+	due := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	issue := &types.Issue{ID: "bd-1", Priority: 1, Labels: []string{"a", "b"}, DueAt: &due}
+	for column, want := range map[string]string{
+		"id":       "bd-1",
+		"priority": "P1",
+		"labels":   "a,b",
+		"due":      "2026-04-01",
+		"closed":   "",
+	} {
+		if got := Value(issue, column); got != want {
+			t.Errorf("Value(%s) = %q, want %q", column, got, want)
+		}
+	}
+}